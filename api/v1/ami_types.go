@@ -0,0 +1,68 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AMISourceAMILabel is set on AMI objects created for spec.autoCopyAMI and holds the copied source AMI ID.
+const AMISourceAMILabel = "compute.cloud.com/source-ami"
+
+// AMISpec describes an AMI the operator makes available in a region by copying it from another region.
+// Changing the source makes a new copy; earlier copies are kept up to retentionCount.
+type AMISpec struct {
+	// Region is where the copy is created.
+	Region string `json:"region"`
+
+	// SourceAMIID and SourceRegion identify the image to copy.
+	SourceAMIID  string `json:"sourceAMIID"`
+	SourceRegion string `json:"sourceRegion"`
+
+	// RetentionCount is the number of copies made by this object that are kept, the current one
+	// included. Older copies are deregistered and their snapshots deleted unless an Ec2Instance
+	// still uses them. When unset, no copy is ever removed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RetentionCount int32 `json:"retentionCount,omitempty"`
+}
+
+// AMIStatus is the observed state of the copied image.
+type AMIStatus struct {
+	// ImageID is the ID of the copy in spec.region.
+	ImageID string `json:"imageID,omitempty"`
+	// SourceAMIID is the source image the copy in imageID was made from.
+	SourceAMIID string `json:"sourceAMIID,omitempty"`
+	// BackingSnapshotIDs are the EBS snapshots behind imageID.
+	BackingSnapshotIDs []string `json:"backingSnapshotIDs,omitempty"`
+	// State is the AWS image state: pending, available, failed, ...
+	State   string `json:"state,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.sourceAMIID"
+// +kubebuilder:printcolumn:name="Region",type="string",JSONPath=".spec.region"
+// +kubebuilder:printcolumn:name="ImageID",type="string",JSONPath=".status.imageID"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// AMI is the Schema for the amis API.
+
+type AMI struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AMISpec   `json:"spec,omitempty"`
+	Status AMIStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AMIList contains a list of AMI.
+type AMIList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AMI `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AMI{}, &AMIList{})
+}
@@ -0,0 +1,105 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CapacityReservationSpec describes an On-Demand Capacity Reservation to hold in AWS.
+// Only the instance count and the end date can be changed after creation; AWS does not allow
+// modifying the other fields of an existing reservation.
+type CapacityReservationSpec struct {
+	Region string `json:"region"`
+
+	InstanceType string `json:"instanceType"`
+
+	// InstanceCount is the number of instances to reserve capacity for.
+	// +kubebuilder:validation:Minimum=1
+	InstanceCount int32 `json:"instanceCount"`
+
+	AvailabilityZone string `json:"availabilityZone"`
+
+	// +kubebuilder:validation:Enum=Linux/UNIX;Windows
+	// +kubebuilder:default=Linux/UNIX
+	InstancePlatform string `json:"instancePlatform,omitempty"`
+
+	// Tenancy is either default (shared hardware) or dedicated.
+	// +kubebuilder:validation:Enum=default;dedicated
+	// +kubebuilder:default=default
+	Tenancy string `json:"tenancy,omitempty"`
+
+	// EndDate is when the reservation is released. Only used when EndDateType is limited.
+	// +optional
+	EndDate *metav1.Time `json:"endDate,omitempty"`
+
+	// +kubebuilder:validation:Enum=limited;unlimited
+	// +kubebuilder:default=unlimited
+	EndDateType string `json:"endDateType,omitempty"`
+}
+
+// CapacityReservationStatus is the observed state of the reservation in AWS.
+type CapacityReservationStatus struct {
+	ReservationID          string `json:"reservationID,omitempty"`
+	State                  string `json:"state,omitempty"`
+	TotalInstanceCount     int32  `json:"totalInstanceCount,omitempty"`
+	AvailableInstanceCount int32  `json:"availableInstanceCount,omitempty"`
+
+	// AlertHistory records the alerts raised for this reservation, oldest first. Open alerts have no
+	// ResolvedAt. Only the most recent entries are kept.
+	// +optional
+	AlertHistory []CapacityReservationAlert `json:"alertHistory,omitempty"`
+}
+
+// CapacityReservationAlertType names a condition of a reservation that needs attention.
+type CapacityReservationAlertType string
+
+const (
+	// CapacityReservationUnderUtilized means less than half of the reserved capacity is in use.
+	CapacityReservationUnderUtilized CapacityReservationAlertType = "UnderUtilized"
+	// CapacityReservationExpiringSoon means the reservation ends within a week.
+	CapacityReservationExpiringSoon CapacityReservationAlertType = "ExpiringSoon"
+	// CapacityReservationOverSubscribed means no reserved capacity is left for new instances.
+	CapacityReservationOverSubscribed CapacityReservationAlertType = "OverSubscribed"
+)
+
+// CapacityReservationAlert is one occurrence of an alert.
+type CapacityReservationAlert struct {
+	Type    CapacityReservationAlertType `json:"type"`
+	Message string                       `json:"message"`
+	// RaisedAt is when the alert started firing.
+	RaisedAt metav1.Time `json:"raisedAt"`
+	// ResolvedAt is when the alert stopped firing.
+	// +optional
+	ResolvedAt *metav1.Time `json:"resolvedAt,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:validation:XValidation:rule="self.spec.endDateType != 'limited' || has(self.spec.endDate)",message="endDate is required when endDateType is limited"
+// +kubebuilder:printcolumn:name="InstanceType",type="string",JSONPath=".spec.instanceType"
+// +kubebuilder:printcolumn:name="AZ",type="string",JSONPath=".spec.availabilityZone"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.totalInstanceCount"
+// +kubebuilder:printcolumn:name="Available",type="integer",JSONPath=".status.availableInstanceCount"
+// +kubebuilder:printcolumn:name="ReservationID",type="string",JSONPath=".status.reservationID"
+// CapacityReservation is the Schema for the capacityreservations API.
+// It keeps EC2 capacity reserved in an availability zone ahead of the instances that will use it.
+
+type CapacityReservation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CapacityReservationSpec   `json:"spec,omitempty"`
+	Status CapacityReservationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CapacityReservationList contains a list of CapacityReservation.
+type CapacityReservationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CapacityReservation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CapacityReservation{}, &CapacityReservationList{})
+}
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CloudFormationMigrationPhase is the stage a CloudFormationMigration is in.
+type CloudFormationMigrationPhase string
+
+const (
+	CloudFormationMigrationPhasePending   CloudFormationMigrationPhase = "Pending"
+	CloudFormationMigrationPhaseAdopting  CloudFormationMigrationPhase = "Adopting"
+	CloudFormationMigrationPhaseCompleted CloudFormationMigrationPhase = "Completed"
+	CloudFormationMigrationPhaseFailed    CloudFormationMigrationPhase = "Failed"
+)
+
+// MigratedFromStackAnnotation is set on Ec2Instances created by a CloudFormationMigration. Its value
+// is "<stack name>/<logical resource ID>".
+const MigratedFromStackAnnotation = "ec2instance.compute.cloud.com/migrated-from-stack"
+
+// CloudFormationMigrationSpec names an EC2 instance in a CloudFormation stack to hand over to the operator.
+type CloudFormationMigrationSpec struct {
+	// Region is the AWS region the stack lives in.
+	Region string `json:"region"`
+
+	// StackName is the name or ID of the CloudFormation stack.
+	StackName string `json:"stackName"`
+
+	// ResourceLogicalID is the logical ID of the AWS::EC2::Instance resource in the stack template.
+	ResourceLogicalID string `json:"resourceLogicalID"`
+
+	// TargetNamespace is the namespace the Ec2Instance is created in.
+	TargetNamespace string `json:"targetNamespace"`
+
+	// InstanceName is the name of the created Ec2Instance. It defaults to the lower-cased logical ID.
+	// +optional
+	InstanceName string `json:"instanceName,omitempty"`
+
+	// RemoveFromStack asks CloudFormation to stop tracking the instance once it has been adopted, by
+	// skipping the resource in ContinueUpdateRollback. CloudFormation only allows this while the stack
+	// is in UPDATE_ROLLBACK_FAILED; in any other state the migration completes and reports that the
+	// resource has to be retained and removed from the template by hand.
+	// +optional
+	RemoveFromStack bool `json:"removeFromStack,omitempty"`
+}
+
+// CloudFormationMigrationStatus tracks the progress of a CloudFormationMigration.
+type CloudFormationMigrationStatus struct {
+	Phase   CloudFormationMigrationPhase `json:"phase,omitempty"`
+	Message string                       `json:"message,omitempty"`
+
+	// InstanceID is the physical ID of the stack resource.
+	InstanceID string `json:"instanceID,omitempty"`
+	// Ec2InstanceName is the Ec2Instance created in spec.targetNamespace.
+	Ec2InstanceName string `json:"ec2InstanceName,omitempty"`
+	// RemovedFromStack is true once CloudFormation has been told to skip the resource.
+	RemovedFromStack bool `json:"removedFromStack,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Stack",type="string",JSONPath=".spec.stackName"
+// +kubebuilder:printcolumn:name="Resource",type="string",JSONPath=".spec.resourceLogicalID"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="InstanceID",type="string",JSONPath=".status.instanceID"
+// CloudFormationMigration is the Schema for the cloudformationmigrations API.
+// It looks up an EC2 instance managed by a CloudFormation stack and creates an Ec2Instance that
+// adopts it. It is cluster scoped because the Ec2Instance can be created in any namespace.
+
+type CloudFormationMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudFormationMigrationSpec   `json:"spec,omitempty"`
+	Status CloudFormationMigrationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CloudFormationMigrationList contains a list of CloudFormationMigration.
+type CloudFormationMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudFormationMigration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CloudFormationMigration{}, &CloudFormationMigrationList{})
+}
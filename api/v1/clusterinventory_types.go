@@ -0,0 +1,71 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterInventoryName is the name of the singleton ClusterInventory object maintained by the operator.
+const ClusterInventoryName = "cluster"
+
+// ClusterInventorySpec defines the desired state of ClusterInventory.
+// The inventory is owned entirely by the operator, so there is nothing to configure yet.
+type ClusterInventorySpec struct{}
+
+// InventoryBreakdown holds instance counts and cost for a subset of the fleet (a region or a namespace).
+type InventoryBreakdown struct {
+	TotalInstances      int     `json:"totalInstances"`
+	RunningInstances    int     `json:"runningInstances"`
+	StoppedInstances    int     `json:"stoppedInstances"`
+	TerminatedInstances int     `json:"terminatedInstances"`
+	FailedInstances     int     `json:"failedInstances"`
+	MonthlyCostUSD      float64 `json:"monthlyCostUSD"`
+}
+
+// ClusterInventoryStatus is the aggregated view of every Ec2Instance in the cluster.
+type ClusterInventoryStatus struct {
+	TotalInstances      int     `json:"totalInstances"`
+	RunningInstances    int     `json:"runningInstances"`
+	StoppedInstances    int     `json:"stoppedInstances"`
+	TerminatedInstances int     `json:"terminatedInstances"`
+	FailedInstances     int     `json:"failedInstances"`
+	TotalMonthlyCostUSD float64 `json:"totalMonthlyCostUSD"`
+
+	// ByRegion breaks the totals down by Ec2Instance spec.region.
+	ByRegion map[string]InventoryBreakdown `json:"byRegion,omitempty"`
+	// ByNamespace breaks the totals down by the namespace of the Ec2Instance object.
+	ByNamespace map[string]InventoryBreakdown `json:"byNamespace,omitempty"`
+
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="ClusterInventory is a singleton and must be named 'cluster'"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.totalInstances"
+// +kubebuilder:printcolumn:name="Running",type="integer",JSONPath=".status.runningInstances"
+// +kubebuilder:printcolumn:name="MonthlyCostUSD",type="number",JSONPath=".status.totalMonthlyCostUSD"
+// +kubebuilder:printcolumn:name="LastUpdated",type="date",JSONPath=".status.lastUpdated"
+// ClusterInventory is the Schema for the clusterinventories API.
+// It is a cluster-scoped singleton that the operator keeps up to date with every Ec2Instance it manages.
+
+type ClusterInventory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterInventorySpec   `json:"spec,omitempty"`
+	Status ClusterInventoryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterInventoryList contains a list of ClusterInventory.
+type ClusterInventoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterInventory `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterInventory{}, &ClusterInventoryList{})
+}
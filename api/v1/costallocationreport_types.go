@@ -0,0 +1,63 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CostAllocationReportSpec is empty: reports are created and filled in by the operator.
+type CostAllocationReportSpec struct{}
+
+// CostEntry is what one Ec2Instance cost on the report date.
+type CostEntry struct {
+	InstanceName string `json:"instanceName"`
+	InstanceID   string `json:"instanceID"`
+	InstanceType string `json:"instanceType,omitempty"`
+	// Hours is the usage Cost Explorer recorded for the instance on the report date.
+	Hours float64 `json:"hours"`
+	// CostUSD is the unblended cost of the instance on the report date.
+	CostUSD float64 `json:"costUSD"`
+	// CostPerHour is CostUSD divided by Hours, or zero when there was no usage.
+	CostPerHour float64           `json:"costPerHour"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+// CostAllocationReportStatus holds the cost data of the namespace for one day.
+type CostAllocationReportStatus struct {
+	// ReportDate is the UTC day the report covers, as YYYY-MM-DD.
+	ReportDate string      `json:"reportDate,omitempty"`
+	Entries    []CostEntry `json:"entries,omitempty"`
+	// TotalCostUSD is the sum of the cost of all entries.
+	TotalCostUSD float64 `json:"totalCostUSD"`
+	// ProjectedMonthlyUSD is what the namespace would cost over a month at the rate of the report date.
+	ProjectedMonthlyUSD float64 `json:"projectedMonthlyUSD"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Date",type="string",JSONPath=".status.reportDate"
+// +kubebuilder:printcolumn:name="TotalCostUSD",type="number",JSONPath=".status.totalCostUSD"
+// +kubebuilder:printcolumn:name="ProjectedMonthlyUSD",type="number",JSONPath=".status.projectedMonthlyUSD"
+// CostAllocationReport is the Schema for the costallocationreports API.
+// The operator writes one per namespace per day, named cost-YYYY-MM-DD, with the Cost Explorer
+// data of each Ec2Instance, and keeps 90 days of them.
+
+type CostAllocationReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CostAllocationReportSpec   `json:"spec,omitempty"`
+	Status CostAllocationReportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CostAllocationReportList contains a list of CostAllocationReport.
+type CostAllocationReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CostAllocationReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CostAllocationReport{}, &CostAllocationReportList{})
+}
@@ -0,0 +1,4 @@
+package v1
+
+// Hub marks v1 as the version every other version of Ec2Instance is converted to and from.
+func (*Ec2Instance) Hub() {}
@@ -1,40 +1,858 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// +kubebuilder:validation:XValidation:rule="!has(self.userData) || !has(self.imageBuilderComponents)",message="userData and imageBuilderComponents are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.keyPair) || !has(self.keyPairRef)",message="keyPair and keyPairRef are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.instanceTypeOptimization) || has(self.resources)",message="resources are required for instanceTypeOptimization"
+// +kubebuilder:validation:XValidation:rule="!has(self.hibernationEnabled) || !self.hibernationEnabled || !has(self.nitroEnclave) || !has(self.nitroEnclave.enabled) || !self.nitroEnclave.enabled",message="hibernation and Nitro Enclaves cannot both be enabled"
+// +kubebuilder:validation:XValidation:rule="!has(self.enaExpressUDPEnabled) || !self.enaExpressUDPEnabled || (has(self.enaExpressEnabled) && self.enaExpressEnabled)",message="enaExpressUDPEnabled requires enaExpressEnabled"
+// +kubebuilder:validation:XValidation:rule="!has(self.desiredState) || self.desiredState != 'stopped' || !has(self.spotOptions) || !has(self.spotOptions.enabled) || !self.spotOptions.enabled || (has(self.spotOptions.interruptionBehavior) && self.spotOptions.interruptionBehavior != 'terminate')",message="Spot instances that terminate on interruption cannot be stopped"
+// +kubebuilder:validation:XValidation:rule="has(self.launchTemplateRef) || (has(self.instanceType) && has(self.amiId))",message="instanceType and amiId are required unless launchTemplateRef is set"
 // Spec definations for Ec2Instance which defines the defination of Ec2Instance .
 
 type Ec2InstanceSpec struct {
-	InstanceType      string            `json:"instanceType"`
-	AMIId             string            `json:"amiId"`
-	Region            string            `json:"region"`
-	AvailabilityZone  string            `json:"availabilityZone,omitempty"`
-	KeyPair           string            `json:"keyPair,omitempty"`
-	SecurityGroups    []string          `json:"securityGroups,omitempty"`
-	Subnet            string            `json:"subnet,omitempty"`
-	UserData          string            `json:"userData,omitempty"`
+	// InstanceType and AMIId are required unless launchTemplateRef is set, in which case they are
+	// taken from the template when left out.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+	// +optional
+	AMIId string `json:"amiId,omitempty"`
+	// LaunchTemplateRef launches the instance from an EC2 launch template. The fields set in this
+	// spec override the ones of the template. It only applies at launch.
+	// +optional
+	LaunchTemplateRef *LaunchTemplateRef `json:"launchTemplateRef,omitempty"`
+	// Region is the AWS region the instance runs in. It cannot change once the instance is
+	// launched; move it with a RegionMigration instead.
+	Region           string `json:"region"`
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+	// PlacementGroup launches the instance into a placement group, e.g. one created by an
+	// Ec2PlacementGroup. It cannot change once the instance is launched.
+	// +optional
+	PlacementGroup *PlacementGroupSpec `json:"placementGroup,omitempty"`
+	KeyPair        string              `json:"keyPair,omitempty"`
+	// KeyPairRef names an Ec2KeyPair in the same namespace to launch with instead of keyPair. The
+	// instance is not launched before the key pair exists. It cannot be used with roleARN.
+	// +optional
+	KeyPairRef *corev1.LocalObjectReference `json:"keyPairRef,omitempty"`
+	// SecurityGroups are the IDs of the security groups of the primary network interface. They
+	// can be changed on a running instance.
+	SecurityGroups []string `json:"securityGroups,omitempty"`
+	// SecurityGroupRefs name Ec2SecurityGroups in the same namespace whose groups are added to
+	// securityGroups. The instance is not launched before all of them have a group ID. They have
+	// to be in the instance's region and cannot be used with roleARN.
+	// +optional
+	SecurityGroupRefs []corev1.LocalObjectReference `json:"securityGroupRefs,omitempty"`
+	// ManageSGExclusive makes securityGroups the complete list, removing groups attached outside
+	// the operator. Without it those groups are kept and only groups dropped from securityGroups
+	// are removed.
+	// +optional
+	ManageSGExclusive bool `json:"manageSGExclusive,omitempty"`
+	// IAMInstanceProfile is the name or ARN of the instance profile to attach, so the instance can
+	// call other AWS services. Changing it on a running instance swaps the profile in place.
+	// +optional
+	IAMInstanceProfile string `json:"iamInstanceProfile,omitempty"`
+	// NamePattern is a Go template for the Name tag of the EC2 instance, e.g.
+	// "{{.Namespace}}-{{.Name}}-{{.Index}}". It can use .Name, .Namespace, .InstanceType, .AZ
+	// (spec.availabilityZone) and .Index (the ordinal within an Ec2InstanceSet, otherwise 0). The
+	// rendered name may be at most 256 characters and takes precedence over a Name in tags.
+	// +optional
+	NamePattern string `json:"namePattern,omitempty"`
+	// Subnet is the ID of the subnet to launch into, which also picks the VPC. When availabilityZone
+	// is set as well, the subnet must be in that zone.
+	Subnet string `json:"subnet,omitempty"`
+
+	// NetworkInterfaces launches the instance with these network interfaces instead of a single
+	// one in subnet with securityGroups, which must then be left empty. Device index 0 is the
+	// primary interface and must be present.
+	// +listType=map
+	// +listMapKey=deviceIndex
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	NetworkInterfaces []NetworkInterfaceSpec `json:"networkInterfaces,omitempty"`
+	// UserData is passed to the instance at launch, e.g. a cloud-init script. AWS cannot change it
+	// on an existing instance, so it is fixed once the instance is created.
+	// +optional
+	UserData          *UserDataSpec     `json:"userData,omitempty"`
 	Tags              map[string]string `json:"tags,omitempty"`
 	Storage           StorageConfig     `json:"storage,omitempty"`
 	AssociatePublicIP bool              `json:"associatePublicIP,omitempty"`
+
+	// EncryptRootVolume launches the instance with an encrypted root volume, or explicitly without
+	// one when false. When unset, the root volume is encrypted if the operator enforces EBS
+	// encryption (--enforce-ebs-encryption) or storage.rootVolume.encrypted is set. It only applies
+	// at launch.
+	// +optional
+	EncryptRootVolume *bool `json:"encryptRootVolume,omitempty"`
+
+	// KMSKeyID is the KMS key (ID, ARN or alias) an encrypted root volume is encrypted with, instead
+	// of the default EBS key of the account.
+	// +optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+
+	// EBSVolumes are data volumes the operator creates and attaches once the instance runs. Unlike
+	// storage.additionalVolumes they live on their own: a replacement instance gets the same volumes
+	// attached again. Volumes removed from the list are detached but kept.
+	// +listType=map
+	// +listMapKey=deviceName
+	// +optional
+	EBSVolumes []EBSVolumeSpec `json:"ebsVolumes,omitempty"`
+
+	// ElasticIP gives the instance a public IP that stays the same when the instance is replaced.
+	// +optional
+	ElasticIP ElasticIPSpec `json:"elasticIP,omitempty"`
+
+	// DisableIMDSOnTermination turns off the instance metadata endpoint right before the instance is
+	// terminated, so credentials cannot be harvested in the window between the termination request
+	// and the actual shutdown.
+	DisableIMDSOnTermination bool `json:"disableIMDSOnTermination,omitempty"`
+
+	// MetadataOptions configures the instance metadata service (IMDS). Settings left unset keep the
+	// AWS defaults. Changes are applied to the running instance.
+	// +optional
+	MetadataOptions *MetadataOptionsSpec `json:"metadataOptions,omitempty"`
+
+	// AutoRecovery lets EC2 recover the instance automatically when the underlying host fails.
+	// It is on by default, matching the AWS default; set it to false to disable recovery.
+	// +kubebuilder:default=true
+	// +optional
+	AutoRecovery bool `json:"autoRecovery"`
+
+	// AdoptInstanceID makes the operator take over an existing EC2 instance instead of launching a new one.
+	// It cannot be changed once the instance has been adopted.
+	AdoptInstanceID string `json:"adoptInstanceID,omitempty"`
+
+	// RoleARN is an IAM role, usually in another AWS account, that the operator assumes for every
+	// AWS call about this instance. It is assumed on top of the region's credentials. It cannot be
+	// changed once the instance is launched, and cannot be combined with spec.patchManagement, an
+	// Ec2LaunchTemplate in spec.launchTemplateRef, spec.securityGroupRefs or spec.keyPairRef.
+	// +kubebuilder:validation:Pattern=`^arn:aws[a-z-]*:iam::[0-9]{12}:role/.+$`
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
+
+	// DeletionPolicy controls what happens to the EC2 instance when this object is deleted.
+	// Delete (the default) terminates the instance, Orphan leaves it running in AWS.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// DeletionTimeout is how long the finalizer may take to clean up in AWS after this object is
+	// deleted. Once it has passed the finalizer is removed anyway, leaving whatever it could not
+	// clean up behind, so that the object cannot block the deletion of its namespace. Defaults to
+	// the operator's --force-delete-timeout, 10 minutes unless set otherwise.
+	// +optional
+	DeletionTimeout *metav1.Duration `json:"deletionTimeout,omitempty"`
+
+	// CostAnomalyDetection registers the instance with AWS Cost Anomaly Detection so unusual spend
+	// triggers an alert.
+	// +optional
+	CostAnomalyDetection CostAnomalySpec `json:"costAnomalyDetection,omitempty"`
+
+	// XRayEnabled publishes an X-Ray sampling configuration for the instance to SSM Parameter Store
+	// at /xray/<instance-id>/sampling-rate, where the X-Ray daemon on the instance picks it up.
+	XRayEnabled bool `json:"xrayEnabled,omitempty"`
+
+	// XRaySamplingRate is the fraction of requests to trace, from 0.0 to 1.0.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	XRaySamplingRate float64 `json:"xraySamplingRate,omitempty"`
+
+	// DNS registers an A record for the instance in Route53 once it has an IP, and deletes it with
+	// the instance.
+	// +optional
+	DNS *DNSRecordSpec `json:"dns,omitempty"`
+
+	// CloudWatchAlarms are created for the instance once it is running and deleted with it.
+	// +listType=map
+	// +listMapKey=alarmName
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	CloudWatchAlarms []CloudWatchAlarmSpec `json:"cloudWatchAlarms,omitempty"`
+
+	// AMISourceRegion is the region amiId was published in, when that is not spec.region.
+	AMISourceRegion string `json:"amiSourceRegion,omitempty"`
+
+	// AutoCopyAMI copies amiId from amiSourceRegion into spec.region (through an AMI object) before
+	// launching, instead of rejecting an AMI that does not exist in the target region.
+	// +optional
+	AutoCopyAMI bool `json:"autoCopyAMI,omitempty"`
+
+	// EBSOptimized launches the instance with dedicated EBS bandwidth. Instance types that are
+	// EBS-optimized by default ignore it; types that do not support it are rejected.
+	EBSOptimized bool `json:"ebsOptimized,omitempty"`
+
+	// HibernationEnabled launches the instance with hibernation configured, so it can later be
+	// hibernated instead of stopped. It requires an instance type that supports hibernation and an
+	// encrypted root volume (storage.rootVolume) big enough to hold the instance memory. An instance
+	// whose OS is not ready to hibernate is stopped instead.
+	HibernationEnabled bool `json:"hibernationEnabled,omitempty"`
+
+	// ImageBuilderComponents are EC2 Image Builder components run at first boot, in order. The
+	// operator renders them into a user data script, so they cannot be combined with userData.
+	// +optional
+	ImageBuilderComponents []ImageBuilderComponentRef `json:"imageBuilderComponents,omitempty"`
+
+	// CreationCondition is a CEL expression that must evaluate to true before the instance is
+	// launched. It can use spec, metadata, namespaceObject (the Namespace the object is in) and
+	// now (the current time), e.g. `now > timestamp("2025-01-01T00:00:00Z")`.
+	// +optional
+	CreationCondition string `json:"creationCondition,omitempty"`
+
+	// InstanceTypeOptimization lets the operator pick the instance type at launch. "cost-aware"
+	// prefers a type with unused Reserved Instances, then the cheapest on-demand type that fits
+	// resources. instanceType is used when no candidate can be priced.
+	// +optional
+	InstanceTypeOptimization InstanceTypeOptimization `json:"instanceTypeOptimization,omitempty"`
+
+	// Resources are the minimum vCPUs and memory the instance needs. They are required for
+	// cost-aware instance type selection.
+	// +optional
+	Resources *InstanceResources `json:"resources,omitempty"`
+
+	// SnapshotSchedule takes periodic snapshots of the root EBS volume.
+	// +optional
+	SnapshotSchedule *SnapshotScheduleSpec `json:"snapshotSchedule,omitempty"`
+
+	// EKSClusterRef marks the instance as a worker node of an EKS cluster. Once the node has joined,
+	// the operator labels it with the instance type, ID, availability zone and region.
+	// +optional
+	EKSClusterRef *EKSClusterReference `json:"eksClusterRef,omitempty"`
+
+	// Route53HealthCheck creates a Route53 health check against the public IP of the instance.
+	// +optional
+	Route53HealthCheck *Route53HealthCheckSpec `json:"route53HealthCheck,omitempty"`
+
+	// NitroEnclave launches the instance with AWS Nitro Enclaves enabled. It cannot be changed after
+	// the instance is created.
+	// +optional
+	NitroEnclave NitroEnclaveSpec `json:"nitroEnclave,omitempty"`
+
+	// CPUOptions launches the instance with fewer cores or without hyperthreading, e.g. to cut
+	// per-core licence costs. It cannot be changed after the instance is created.
+	// +optional
+	CPUOptions CPUOptions `json:"cpuOptions,omitempty"`
+
+	// ENAExpressEnabled launches the primary network interface with ENA Express, which lowers tail
+	// latency between instances in the same availability zone. Instance types without support are
+	// launched without it and reported in status.enaExpress.
+	// +optional
+	ENAExpressEnabled bool `json:"enaExpressEnabled,omitempty"`
+
+	// ENAExpressUDPEnabled also routes UDP traffic over ENA Express.
+	// +optional
+	ENAExpressUDPEnabled bool `json:"enaExpressUDPEnabled,omitempty"`
+
+	// SourceDestCheck controls whether the instance drops traffic it is neither the source nor the
+	// destination of. Network appliances such as NAT instances and firewalls need it set to false.
+	// When unset the AWS default (true) is left alone.
+	// +optional
+	SourceDestCheck *bool `json:"sourceDestCheck,omitempty"`
+
+	// DesiredState is the state the operator keeps the instance in. Changing it stops or starts the
+	// instance; with hibernationEnabled it is hibernated instead of stopped.
+	// +kubebuilder:validation:Enum=running;stopped
+	// +kubebuilder:default=running
+	// +optional
+	DesiredState string `json:"desiredState,omitempty"`
+
+	// Schedule stops and starts the instance at set times, e.g. to stop development instances at
+	// night. Each scheduled stop or start sets desiredState, which can still be changed by hand in between.
+	// +optional
+	Schedule *InstanceScheduleSpec `json:"schedule,omitempty"`
+
+	// SpotOptions launches the instance as a Spot instance.
+	// +optional
+	SpotOptions SpotOptionsSpec `json:"spotOptions,omitempty"`
+
+	// DecommissionWorkflow runs steps on the instance, such as exporting its data, before it is
+	// terminated on deletion. It is skipped when spec.deletionPolicy is Orphan.
+	// +optional
+	DecommissionWorkflow DecommissionWorkflowSpec `json:"decommissionWorkflow,omitempty"`
+
+	// PatchManagement installs OS patches with SSM Patch Manager inside a MaintenanceWindow.
+	// The instance must run the SSM agent with an instance profile that allows it.
+	// +optional
+	PatchManagement PatchManagementSpec `json:"patchManagement,omitempty"`
+}
+
+// DecommissionWorkflowSpec lists the steps to run before the instance is terminated.
+type DecommissionWorkflowSpec struct {
+	// Steps run in order. A failed step is retried until it succeeds, unless it allows failure.
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Steps []DecommissionStep `json:"steps,omitempty"`
+}
+
+// DecommissionStepType is the kind of decommission step.
+// +kubebuilder:validation:Enum=S3Export;SSMCommand;SNSNotify
+type DecommissionStepType string
+
+const (
+	// DecommissionStepS3Export copies a directory of the instance to S3 with aws s3 sync, run through SSM.
+	DecommissionStepS3Export DecommissionStepType = "S3Export"
+	// DecommissionStepSSMCommand runs shell commands on the instance through SSM.
+	DecommissionStepSSMCommand DecommissionStepType = "SSMCommand"
+	// DecommissionStepSNSNotify publishes a message to an SNS topic.
+	DecommissionStepSNSNotify DecommissionStepType = "SNSNotify"
+)
+
+// +kubebuilder:validation:XValidation:rule="self.type != 'S3Export' || has(self.s3Export)",message="s3Export is required for S3Export steps"
+// +kubebuilder:validation:XValidation:rule="self.type != 'SSMCommand' || has(self.ssmCommand)",message="ssmCommand is required for SSMCommand steps"
+// +kubebuilder:validation:XValidation:rule="self.type != 'SNSNotify' || has(self.snsNotify)",message="snsNotify is required for SNSNotify steps"
+// DecommissionStep is one step of the decommission workflow.
+
+type DecommissionStep struct {
+	Type DecommissionStepType `json:"type"`
+
+	// TimeoutSeconds is how long the step may take before it counts as failed.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=600
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// ContinueOnFailure moves on to the next step when this one fails instead of retrying it.
+	// +optional
+	ContinueOnFailure bool `json:"continueOnFailure,omitempty"`
+
+	// +optional
+	S3Export *S3ExportStep `json:"s3Export,omitempty"`
+	// +optional
+	SSMCommand *SSMCommandStep `json:"ssmCommand,omitempty"`
+	// +optional
+	SNSNotify *SNSNotifyStep `json:"snsNotify,omitempty"`
+}
+
+// S3ExportStep copies a directory of the instance to s3://<bucket>/<prefix><instance-id>/. The
+// instance profile must allow writing to the bucket.
+type S3ExportStep struct {
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// +kubebuilder:default="/data"
+	// +optional
+	SourcePath string `json:"sourcePath,omitempty"`
+}
+
+// SSMCommandStep runs shell commands on the instance with AWS-RunShellScript.
+type SSMCommandStep struct {
+	// +kubebuilder:validation:MinItems=1
+	Commands []string `json:"commands"`
+}
+
+// SNSNotifyStep publishes a message to an SNS topic.
+type SNSNotifyStep struct {
+	// +kubebuilder:validation:MinLength=1
+	TopicARN string `json:"topicARN"`
+	// Message defaults to a note naming the instance being decommissioned.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Results of a decommission step.
+const (
+	DecommissionStepSucceeded = "Succeeded"
+	DecommissionStepFailed    = "Failed"
+	DecommissionStepTimedOut  = "TimedOut"
+)
+
+// DecommissionStepResult records how a finished decommission step went.
+type DecommissionStepResult struct {
+	Type DecommissionStepType `json:"type"`
+	// Result is Succeeded, Failed or TimedOut.
+	Result string `json:"result"`
+	// +optional
+	Message     string      `json:"message,omitempty"`
+	CompletedAt metav1.Time `json:"completedAt"`
+}
+
+// DecommissionProgress tracks the decommission workflow, so it resumes where it was after an
+// operator restart.
+type DecommissionProgress struct {
+	// CompletedSteps are the finished steps, in order.
+	// +optional
+	CompletedSteps []DecommissionStepResult `json:"completedSteps,omitempty"`
+	// RemainingSteps is the number of steps still to run, including the current one.
+	RemainingSteps int32 `json:"remainingSteps"`
+
+	// StepStartedAt is when the current step started, and CommandID the SSM command it runs.
+	// +optional
+	StepStartedAt *metav1.Time `json:"stepStartedAt,omitempty"`
+	// +optional
+	CommandID string `json:"commandID,omitempty"`
+	// Attempts counts the failed attempts of the current step.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+}
+
+// SpotInterruptionBehavior is what AWS does with a Spot instance when it reclaims the capacity.
+// +kubebuilder:validation:Enum=stop;terminate;hibernate
+type SpotInterruptionBehavior string
+
+const (
+	// SpotInterruptionStop stops the instance; AWS starts it again once capacity is back.
+	SpotInterruptionStop SpotInterruptionBehavior = "stop"
+	// SpotInterruptionTerminate terminates the instance and the operator launches a replacement.
+	SpotInterruptionTerminate SpotInterruptionBehavior = "terminate"
+	// SpotInterruptionHibernate hibernates the instance; AWS resumes it once capacity is back.
+	SpotInterruptionHibernate SpotInterruptionBehavior = "hibernate"
+)
+
+// SpotOptionsSpec configures the Spot market options of the instance.
+type SpotOptionsSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MaxPrice is the highest hourly price in USD to pay, e.g. "0.05". Defaults to the On-Demand price.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	MaxPrice string `json:"maxPrice,omitempty"`
+
+	// InterruptionBehavior is what happens to the instance when AWS reclaims it. Stop and
+	// hibernate keep the instance, so it is launched from a persistent Spot request.
+	// +kubebuilder:default=terminate
+	// +optional
+	InterruptionBehavior SpotInterruptionBehavior `json:"interruptionBehavior,omitempty"`
+
+	// FallbackOnDemand launches an On-Demand instance when no Spot capacity is available at
+	// maxPrice, instead of retrying the Spot launch.
+	// +optional
+	FallbackOnDemand bool `json:"fallbackOnDemand,omitempty"`
+
+	// RebidOnInterruption launches the replacement as soon as AWS starts shutting the interrupted
+	// instance down, instead of once it is terminated.
+	// +optional
+	RebidOnInterruption bool `json:"rebidOnInterruption,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!self.enabled || has(self.maintenanceWindowRef)",message="maintenanceWindowRef is required when patch management is enabled"
+// PatchManagementSpec configures patching of the instance through SSM Patch Manager.
+
+type PatchManagementSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// PatchBaselineID is the patch baseline (pb-...) to patch against. The instance is put in a
+	// patch group named after it; without it the default baseline of the operating system is used.
+	// +optional
+	PatchBaselineID string `json:"patchBaselineID,omitempty"`
+
+	// MaintenanceWindowRef names the MaintenanceWindow in the same namespace to patch in.
+	// +optional
+	MaintenanceWindowRef *corev1.LocalObjectReference `json:"maintenanceWindowRef,omitempty"`
+
+	// RebootAfterPatch reboots the instance when an installed patch needs it. Without it such
+	// patches stay pending until the next reboot.
+	// +optional
+	RebootAfterPatch bool `json:"rebootAfterPatch,omitempty"`
+}
+
+// PatchRegistration records what the instance is registered with in its maintenance window.
+type PatchRegistration struct {
+	WindowID       string `json:"windowID"`
+	WindowTargetID string `json:"windowTargetID,omitempty"`
+	WindowTaskID   string `json:"windowTaskID,omitempty"`
+	// RebootAfterPatch is the reboot setting the task was registered with.
+	RebootAfterPatch bool `json:"rebootAfterPatch,omitempty"`
+}
+
+// UserDataSpec is the user data of an instance, given inline or read from a Secret.
+// +kubebuilder:validation:XValidation:rule="has(self.inline) != has(self.secretRef)",message="exactly one of inline and secretRef must be set"
+type UserDataSpec struct {
+	// Inline is the user data as plain text, or already base64 encoded.
+	// +optional
+	Inline string `json:"inline,omitempty"`
+
+	// SecretRef selects a key of a Secret in the namespace of the Ec2Instance that holds the user
+	// data, for scripts that carry credentials.
+	// +optional
+	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
+}
+
+// CPUOptions is the number of CPU cores of an instance and the threads on each core. A value left
+// unset at launch is taken from the defaults of the instance type.
+type CPUOptions struct {
+	// CoreCount must be one of the valid core counts of the instance type.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CoreCount int32 `json:"coreCount,omitempty"`
+
+	// ThreadsPerCore is 1 to disable hyperthreading, or 2.
+	// +kubebuilder:validation:Enum=1;2
+	// +optional
+	ThreadsPerCore int32 `json:"threadsPerCore,omitempty"`
+}
+
+// MetadataOptionsSpec configures the instance metadata service of an instance.
+type MetadataOptionsSpec struct {
+	// HTTPTokens is required to only allow IMDSv2, which needs a session token, or optional to allow
+	// IMDSv1 too. The operator's --enforce-imdsv2 flag requires tokens whatever this says.
+	// +kubebuilder:validation:Enum=required;optional
+	// +optional
+	HTTPTokens string `json:"httpTokens,omitempty"`
+
+	// HTTPPutResponseHopLimit is how many network hops the token response may travel; containers
+	// on the instance need at least 2.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +optional
+	HTTPPutResponseHopLimit int32 `json:"httpPutResponseHopLimit,omitempty"`
+
+	// InstanceMetadataTags makes the tags of the instance readable from the metadata service.
+	// +kubebuilder:validation:Enum=enabled;disabled
+	// +optional
+	InstanceMetadataTags string `json:"instanceMetadataTags,omitempty"`
+}
+
+// LaunchTemplateRef is an EC2 launch template, by ID, by name or by the Ec2LaunchTemplate that
+// manages it, and its version.
+// +kubebuilder:validation:XValidation:rule="[has(self.id), has(self.name), has(self.ec2LaunchTemplate)].filter(x, x).size() == 1",message="exactly one of id, name and ec2LaunchTemplate is required"
+type LaunchTemplateRef struct {
+	// ID is the launch template ID (lt-...).
+	// +kubebuilder:validation:Pattern=`^lt-[0-9a-f]+$`
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name is the name of the launch template.
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=128
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Ec2LaunchTemplate names an Ec2LaunchTemplate in the same namespace. The instance is not
+	// launched before the launch template exists. The launch template is created in the
+	// operator's account, so it cannot be combined with spec.roleARN.
+	// +optional
+	Ec2LaunchTemplate string `json:"ec2LaunchTemplate,omitempty"`
+
+	// Version is a version number, $Latest or $Default. The default version of the template is
+	// used when it is not set.
+	// +kubebuilder:validation:Pattern=`^(\$Latest|\$Default|[0-9]+)$`
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// PlacementGroupSpec is the placement group an instance is launched into.
+type PlacementGroupSpec struct {
+	// Name is the name of the placement group in AWS.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// PartitionNumber is the partition of a partition placement group to launch into. AWS spreads
+	// instances over the partitions when it is not set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=7
+	// +optional
+	PartitionNumber int32 `json:"partitionNumber,omitempty"`
+}
+
+// NitroEnclaveSpec configures AWS Nitro Enclaves on the instance.
+type NitroEnclaveSpec struct {
+	// Enabled turns on Nitro Enclaves. The instance type must support them and have at least 4 vCPUs.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// EnclaveImageRef points to the enclave image file (EIF) the instance should run, e.g. an S3 URI.
+	// It is put on the instance as the ec2instance.compute.cloud.com/enclave-image tag for the
+	// bootstrap scripts to pick up; the operator does not start the enclave itself.
+	// +optional
+	EnclaveImageRef string `json:"enclaveImageRef,omitempty"`
+}
+
+// ENAExpressStatus is the observed ENA Express configuration of the instance.
+type ENAExpressStatus struct {
+	Enabled    bool `json:"enabled"`
+	UDPEnabled bool `json:"udpEnabled"`
+	// SupportedByInstanceType is false when the instance type cannot use ENA Express at all.
+	SupportedByInstanceType bool `json:"supportedByInstanceType"`
+}
+
+// +kubebuilder:validation:XValidation:rule="self.protocol != 'TCP' || !has(self.path)",message="path only applies to HTTP and HTTPS health checks"
+// Route53HealthCheckSpec configures a Route53 health check of the instance.
+
+type Route53HealthCheckSpec struct {
+	// +kubebuilder:validation:Enum=HTTP;HTTPS;TCP
+	Protocol string `json:"protocol"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Path is requested by HTTP and HTTPS health checks, e.g. /healthz.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed checks before the instance is unhealthy.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default=3
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// RequestInterval is the number of seconds between checks. Changing it replaces the health check.
+	// +kubebuilder:validation:Enum=10;30
+	// +kubebuilder:default=30
+	// +optional
+	RequestInterval int32 `json:"requestInterval,omitempty"`
+}
+
+// EKSClusterReference identifies the EKS cluster an instance joins as a worker node.
+type EKSClusterReference struct {
+	// Name of the EKS cluster.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// InstanceScheduleSpec stops and starts the instance on cron schedules.
+// +kubebuilder:validation:XValidation:rule="has(self.stopCron) || has(self.startCron)",message="at least one of stopCron and startCron is required"
+type InstanceScheduleSpec struct {
+	// StopCron is a standard five field cron expression, e.g. "0 19 * * 1-5", at which the instance
+	// is stopped.
+	// +optional
+	StopCron string `json:"stopCron,omitempty"`
+
+	// StartCron is a standard five field cron expression, e.g. "0 7 * * 1-5", at which the instance
+	// is started. When both are due at the same minute, the start wins.
+	// +optional
+	StartCron string `json:"startCron,omitempty"`
+
+	// Timezone is the IANA time zone, e.g. "Europe/Berlin", that both expressions are evaluated in.
+	// +kubebuilder:default=UTC
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// SnapshotScheduleSpec configures periodic snapshots of the root EBS volume.
+type SnapshotScheduleSpec struct {
+	// CronExpression is a standard five field cron expression, e.g. "0 3 * * *". It is evaluated in
+	// UTC unless it starts with CRON_TZ=<zone>.
+	// +kubebuilder:validation:MinLength=1
+	CronExpression string `json:"cronExpression"`
+
+	// RetentionCount is the number of snapshots to keep. The oldest ones are deleted.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=7
+	// +optional
+	RetentionCount int `json:"retentionCount,omitempty"`
+
+	// Description is set on every snapshot.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Replication copies every retained snapshot to other regions for disaster recovery.
+	// +listType=map
+	// +listMapKey=targetRegion
+	// +optional
+	Replication []SnapshotReplicationTarget `json:"replication,omitempty"`
+}
+
+// SnapshotReplicationTarget is a region that receives copies of the scheduled snapshots.
+type SnapshotReplicationTarget struct {
+	// TargetRegion is the region the snapshots are copied to. It must differ from spec.region.
+	// +kubebuilder:validation:MinLength=1
+	TargetRegion string `json:"targetRegion"`
+
+	// KMSKeyID encrypts the copies with this key of the target region. Without it the copies are
+	// encrypted like their source.
+	// +optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+
+	// RetentionCount is the number of copies to keep in the target region. Copies of snapshots that
+	// were deleted in the source region are always deleted.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=7
+	// +optional
+	RetentionCount int `json:"retentionCount,omitempty"`
 }
 
+// InstanceTypeOptimization is a strategy for choosing the instance type.
+// +kubebuilder:validation:Enum=cost-aware
+type InstanceTypeOptimization string
+
+// InstanceTypeOptimizationCostAware picks the cheapest type for the account, counting unused
+// Reserved Instances as free.
+const InstanceTypeOptimizationCostAware InstanceTypeOptimization = "cost-aware"
+
+// InstanceResources describes the minimum size of an instance.
+type InstanceResources struct {
+	// +kubebuilder:validation:Minimum=1
+	VCPUs int32 `json:"vcpus"`
+	// +kubebuilder:validation:Minimum=1
+	MemoryMiB int32 `json:"memoryMiB"`
+}
+
+// ImageBuilderComponentRef references a build version of an Image Builder component.
+
+type ImageBuilderComponentRef struct {
+	// ComponentARN is the build version ARN of the component,
+	// e.g. arn:aws:imagebuilder:eu-west-1:123456789012:component/my-component/1.0.0/1.
+	// +kubebuilder:validation:Pattern=`^arn:aws[a-z-]*:imagebuilder:[a-z0-9-]+:(\d{12}|aws):component/[a-z0-9_-]+/\d+\.\d+\.\d+/\d+$`
+	ComponentARN string `json:"componentARN"`
+
+	// Parameters override the defaults of the parameters declared by the component.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// NetworkInterfaceSpec is a network interface the instance is launched with. Apart from
+// sourceDestCheck it cannot be changed once the instance runs.
+type NetworkInterfaceSpec struct {
+	// DeviceIndex is the position of the interface on the instance; 0 is the primary interface.
+	// +kubebuilder:validation:Minimum=0
+	DeviceIndex int32 `json:"deviceIndex"`
+
+	// SubnetID is the subnet the interface is created in.
+	// +kubebuilder:validation:MinLength=1
+	SubnetID string `json:"subnetID"`
+
+	// SecurityGroups are the IDs of the security groups of the interface. Without any, AWS uses the
+	// default group of the VPC.
+	// +optional
+	SecurityGroups []string `json:"securityGroups,omitempty"`
+
+	// PrivateIPAddresses are assigned to the interface, the first one as its primary private IP.
+	// +optional
+	PrivateIPAddresses []string `json:"privateIPAddresses,omitempty"`
+
+	// SecondaryPrivateIPCount is the number of additional private IPs AWS picks for the interface.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SecondaryPrivateIPCount int32 `json:"secondaryPrivateIPCount,omitempty"`
+
+	// AssociatePublicIP gives the interface a public IP. AWS only allows it on instances with a
+	// single network interface.
+	// +optional
+	AssociatePublicIP bool `json:"associatePublicIP,omitempty"`
+
+	// SourceDestCheck controls whether the interface drops traffic it is neither the source nor the
+	// destination of. When unset the AWS default (true) is left alone.
+	// +optional
+	SourceDestCheck *bool `json:"sourceDestCheck,omitempty"`
+}
+
+// DNSRecordSpec is the Route53 A record of the instance.
+type DNSRecordSpec struct {
+	// +kubebuilder:validation:MinLength=1
+	HostedZoneID string `json:"hostedZoneID"`
+
+	// RecordName is the fully qualified name of the record, e.g. web.example.com.
+	// +kubebuilder:validation:MinLength=1
+	RecordName string `json:"recordName"`
+
+	// TTL of the record in seconds.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=60
+	// +optional
+	TTL int64 `json:"ttl,omitempty"`
+
+	// UsePrivateIP points the record at the private IP instead of the public one. Instances without
+	// a public IP always use the private one.
+	// +optional
+	UsePrivateIP bool `json:"usePrivateIP,omitempty"`
+}
+
+// CloudWatchAlarmSpec is a CloudWatch alarm on a metric of the instance. The InstanceId dimension
+// is filled in with the ID of the instance.
+type CloudWatchAlarmSpec struct {
+	// AlarmName names the alarm. The instance ID is appended to it, so the name stays unique across
+	// replacements and the instances of a set.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=200
+	AlarmName string `json:"alarmName"`
+
+	// MetricName is the metric to watch, e.g. CPUUtilization or StatusCheckFailed.
+	// +kubebuilder:validation:MinLength=1
+	MetricName string `json:"metricName"`
+
+	// Namespace of the metric.
+	// +kubebuilder:default="AWS/EC2"
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Statistic applied to the metric over each period.
+	// +kubebuilder:validation:Enum=Average;Sum;Minimum;Maximum;SampleCount
+	// +kubebuilder:default=Average
+	// +optional
+	Statistic string `json:"statistic,omitempty"`
+
+	// PeriodSeconds is the length of one evaluation period.
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:default=300
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	Threshold float64 `json:"threshold"`
+
+	// +kubebuilder:validation:Enum=GreaterThanOrEqualToThreshold;GreaterThanThreshold;LessThanThreshold;LessThanOrEqualToThreshold
+	ComparisonOperator string `json:"comparisonOperator"`
+
+	// EvaluationPeriods is the number of periods the threshold must be breached to alarm.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	EvaluationPeriods int32 `json:"evaluationPeriods,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!self.enabled || has(self.notificationARN)",message="notificationARN is required when cost anomaly detection is enabled"
+// CostAnomalySpec configures per-instance cost anomaly alerting.
+//
+// The monitor filters costs on the ec2instance.compute.cloud.com/instance-id tag, which the operator
+// puts on the instance. That tag must be activated as a cost allocation tag in the billing console
+// before AWS attributes any spend to it.
+
+type CostAnomalySpec struct {
+	Enabled bool `json:"enabled"`
+
+	// ThresholdUSD is the total anomaly impact in USD at or above which a notification is sent.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ThresholdUSD float64 `json:"thresholdUSD,omitempty"`
+
+	// NotificationARN is the SNS topic that receives anomaly alerts.
+	// +optional
+	NotificationARN string `json:"notificationARN,omitempty"`
+}
+
+// DeletionPolicy describes what happens to the AWS resource when the Kubernetes object is deleted.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete terminates the AWS resource together with the Kubernetes object.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan removes the Kubernetes object but leaves the AWS resource untouched.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="InstanceType",type="string",JSONPath=".spec.instanceType",description="The EC2 instance type"
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="The current state of the EC2 instance"
 // +kubebuilder:printcolumn:name="PublicIP",type="string",JSONPath=".status.publicIP",description="The public IP of the EC2 instance"
 // +kubebuilder:printcolumn:name="InstanceID",type="string",JSONPath=".status.instanceId",description="The AWS instance ID"
+// +kubebuilder:printcolumn:name="AutoRecovery",type="boolean",JSONPath=".status.autoRecoveryEnabled",description="Whether EC2 automatic recovery is active"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",priority=1,description="Whether the instance is running and reachable"
+// +kubebuilder:printcolumn:name="AZ",type="string",JSONPath=".status.availabilityZone",priority=1,description="The availability zone the instance runs in"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.reconcilePhase",priority=1,description="The step of an operation in progress, e.g. a resize"
 // Ec2Instance is the Schema for the ec2instances API.
 
 type Ec2Instance struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
+	// Spec is the desired instance. On creation, fields left unset are filled in from the
+	// ec2instance-defaults ConfigMap in the operator's namespace when it exists. Its keys are
+	// instanceType, amiId, region, availabilityZone, subnet, keyPair and iamInstanceProfile,
+	// securityGroups as a comma-separated list, and tags.<key> for each default tag, e.g.:
+	//
+	//	region: us-east-1
+	//	subnet: subnet-0123456789abcdef0
+	//	securityGroups: sg-1,sg-2
+	//	tags.CostCenter: platform
 	Spec   Ec2InstanceSpec   `json:"spec,omitempty"`
 	Status Ec2InstanceStatus `json:"status,omitempty"`
 }
@@ -50,6 +868,261 @@ type Ec2InstanceStatus struct {
 	PublicDNS  string       `json:"publicDNS,omitempty"`
 	PrivateDNS string       `json:"privateDNS,omitempty"`
 	LaunchTime *metav1.Time `json:"launchTime,omitempty"`
+
+	// AvailabilityZone is the zone the instance runs in, which AWS picks when
+	// spec.availabilityZone is not set.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// InstanceLifecycle is how the instance is billed, e.g. spot or on-demand. A Spot instance that fell back to On-Demand because of
+	// spec.spotOptions.fallbackOnDemand reports on-demand.
+	// +optional
+	InstanceLifecycle string `json:"instanceLifecycle,omitempty"`
+
+	// IAMInstanceProfileARN is the ARN of the instance profile associated with the instance.
+	// +optional
+	IAMInstanceProfileARN string `json:"iamInstanceProfileARN,omitempty"`
+
+	// ElasticIPAllocationID is the Elastic IP the operator allocated for spec.elasticIP. It is
+	// released when the Ec2Instance is deleted. Addresses from spec.elasticIP.existingAllocationID
+	// are not recorded here and never released.
+	// +optional
+	ElasticIPAllocationID string `json:"elasticIPAllocationID,omitempty"`
+
+	// EBSVolumeIDs are the volumes created for spec.ebsVolumes, keyed by device name.
+	// +optional
+	EBSVolumeIDs map[string]string `json:"ebsVolumeIDs,omitempty"`
+
+	// LaunchedAMIID is the spec.amiId the instance was launched from. With autoCopyAMI the instance
+	// runs a regional copy of it, see selectedAMIID.
+	// +optional
+	LaunchedAMIID string `json:"launchedAMIID,omitempty"`
+
+	// Adopted is true when the instance was taken over through spec.adoptInstanceID rather than
+	// launched by the operator.
+	// +optional
+	Adopted bool `json:"adopted,omitempty"`
+
+	// InstanceName is the Name tag rendered from spec.namePattern at launch.
+	// +optional
+	InstanceName string `json:"instanceName,omitempty"`
+
+	// VpcID is the VPC the instance was launched into.
+	// +optional
+	VpcID string `json:"vpcID,omitempty"`
+
+	// AutoRecoveryEnabled reports whether automatic recovery is actually active on the instance.
+	AutoRecoveryEnabled bool `json:"autoRecoveryEnabled,omitempty"`
+
+	// AnomalyMonitorARN and AnomalySubscriptionARN identify the Cost Anomaly Detection resources
+	// created for this instance.
+	AnomalyMonitorARN      string `json:"anomalyMonitorARN,omitempty"`
+	AnomalySubscriptionARN string `json:"anomalySubscriptionARN,omitempty"`
+
+	// XRayEnabled reports whether the X-Ray sampling configuration is in place in SSM.
+	XRayEnabled bool `json:"xrayEnabled,omitempty"`
+
+	// CloudWatchAlarmNames are the CloudWatch alarms created for spec.cloudWatchAlarms.
+	CloudWatchAlarmNames []string `json:"cloudWatchAlarmNames,omitempty"`
+
+	// DNSRecordName is the A record registered for spec.dns, DNSHostedZoneID the zone it is in and
+	// DNSRecordIP the address it points at.
+	DNSRecordName   string `json:"dnsRecordName,omitempty"`
+	DNSHostedZoneID string `json:"dnsHostedZoneID,omitempty"`
+	DNSRecordIP     string `json:"dnsRecordIP,omitempty"`
+
+	// SelectedAMIID is the AMI the instance was launched from when it is not spec.amiId, e.g. a
+	// regional copy made because of spec.autoCopyAMI or the AMI of the launch template.
+	SelectedAMIID string `json:"selectedAMIID,omitempty"`
+
+	// EBSOptimized reports whether the running instance is EBS-optimized.
+	EBSOptimized bool `json:"ebsOptimized,omitempty"`
+
+	// TagPolicyCompliance is the result of checking the instance tags against the effective
+	// AWS Organizations tag policy. It is unset when no tag policy applies.
+	TagPolicyCompliance *TagComplianceStatus `json:"tagPolicyCompliance,omitempty"`
+
+	// ConsoleOutputSecretRef points to the Secret holding the console output fetched on request
+	// with the ec2instance.compute.cloud.com/fetch-console-output annotation.
+	ConsoleOutputSecretRef *corev1.LocalObjectReference `json:"consoleOutputSecretRef,omitempty"`
+
+	// ScreenshotSecretRef points to the Secret holding the console screenshot captured on request
+	// with the ec2instance.compute.cloud.com/capture-screenshot annotation, taken at ScreenshotCapturedAt.
+	// +optional
+	ScreenshotSecretRef *corev1.LocalObjectReference `json:"screenshotSecretRef,omitempty"`
+	// +optional
+	ScreenshotCapturedAt *metav1.Time `json:"screenshotCapturedAt,omitempty"`
+
+	// SelectedInstanceType is the instance type picked by spec.instanceTypeOptimization, or taken
+	// from the launch template when spec.instanceType is not set, and InstanceTypeSelectionReason
+	// explains the choice.
+	SelectedInstanceType        string `json:"selectedInstanceType,omitempty"`
+	InstanceTypeSelectionReason string `json:"instanceTypeSelectionReason,omitempty"`
+
+	// SnapshotHistory lists the retained root volume snapshots, newest first.
+	// +optional
+	SnapshotHistory []SnapshotRef `json:"snapshotHistory,omitempty"`
+
+	// ReplicatedSnapshots lists the copies of the scheduled snapshots per target region, newest first.
+	// +optional
+	ReplicatedSnapshots map[string][]SnapshotRef `json:"replicatedSnapshots,omitempty"`
+
+	// HealthCheckID is the Route53 health check created for spec.route53HealthCheck.
+	// +optional
+	HealthCheckID string `json:"healthCheckID,omitempty"`
+
+	// HealthCheckStatus is Healthy or Unhealthy as seen by the Route53 health checkers, and
+	// HealthCheckLastChecked is when they last reported.
+	// +optional
+	HealthCheckStatus string `json:"healthCheckStatus,omitempty"`
+	// +optional
+	HealthCheckLastChecked *metav1.Time `json:"healthCheckLastChecked,omitempty"`
+
+	// NitroEnclaveEnabled reports whether the instance runs with Nitro Enclaves enabled.
+	// +optional
+	NitroEnclaveEnabled bool `json:"nitroEnclaveEnabled,omitempty"`
+
+	// CPUOptions are the cores and threads per core the instance runs with.
+	// +optional
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+
+	// ENAExpress reports the ENA Express settings active on the primary network interface. It is
+	// only set when spec.enaExpressEnabled is.
+	// +optional
+	ENAExpress *ENAExpressStatus `json:"enaExpress,omitempty"`
+
+	// AppliedSecurityGroupIDs are the security groups on the primary network interface, sorted.
+	// +optional
+	AppliedSecurityGroupIDs []string `json:"appliedSecurityGroupIDs,omitempty"`
+	// ManagedSecurityGroupIDs are the groups of spec.securityGroups the operator last applied, so it
+	// can tell them from groups attached by someone else.
+	// +optional
+	ManagedSecurityGroupIDs []string `json:"managedSecurityGroupIDs,omitempty"`
+
+	// ManagedTagKeys are the tag keys the operator last applied from the spec, so it can remove the
+	// ones dropped from spec.tags without touching tags added by someone else.
+	// +optional
+	ManagedTagKeys []string `json:"managedTagKeys,omitempty"`
+
+	// LastTagSyncTime is when the tags of the instance were last found or brought in line with the
+	// spec.
+	// +optional
+	LastTagSyncTime *metav1.Time `json:"lastTagSyncTime,omitempty"`
+
+	// DecommissionProgress tracks spec.decommissionWorkflow once the Ec2Instance is being deleted.
+	// +optional
+	DecommissionProgress *DecommissionProgress `json:"decommissionProgress,omitempty"`
+
+	// PatchRegistration is the maintenance window target and task registered for spec.patchManagement.
+	// +optional
+	PatchRegistration *PatchRegistration `json:"patchRegistration,omitempty"`
+
+	// LastPatchedAt is when the last patch operation on the instance finished, and
+	// PatchComplianceStatus is Compliant or NonCompliant as reported by Patch Manager.
+	// +optional
+	LastPatchedAt *metav1.Time `json:"lastPatchedAt,omitempty"`
+	// +optional
+	PatchComplianceStatus string `json:"patchComplianceStatus,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the instance was last fully synced against,
+	// at LastSyncTime.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// LastScheduledAction is the scheduled time spec.schedule last set spec.desiredState for. Earlier
+	// stops and starts are not applied again.
+	// +optional
+	LastScheduledAction *metav1.Time `json:"lastScheduledAction,omitempty"`
+
+	// LastHibernated is when the operator last hibernated the instance for spec.desiredState.
+	// +optional
+	LastHibernated *metav1.Time `json:"lastHibernated,omitempty"`
+
+	// ReconcilePhase is the step a multi-step operation, such as a stop-start resize, has reached.
+	// It is written before each step is taken so a restarted operator resumes instead of starting
+	// over. Empty when no operation is in progress.
+	// +optional
+	ReconcilePhase string `json:"reconcilePhase,omitempty"`
+
+	// PendingInstanceType is the instance type a stop-start resize is changing the instance to. It
+	// is recorded with the first phase, so a resize resumed after a restart finishes with the type
+	// it started toward; a spec.instanceType changed in the meantime gets a resize of its own.
+	// +optional
+	PendingInstanceType string `json:"pendingInstanceType,omitempty"`
+
+	// Conditions represent the latest available observations of the instance's state.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Values of status.reconcilePhase during a stop-start resize, in order.
+const (
+	ReconcilePhaseStopping      = "Stopping"
+	ReconcilePhaseStopped       = "Stopped"
+	ReconcilePhaseModifyingType = "ModifyingType"
+	ReconcilePhaseStarting      = "Starting"
+)
+
+// ForceReplaceAnnotation set to "true" allows changing spec fields that can only be applied by
+// replacing the EC2 instance, such as amiId and subnet. The operator then terminates the instance,
+// launches a new one and removes the annotation.
+const ForceReplaceAnnotation = "ec2instance.compute.cloud.com/force-replace"
+
+// Condition types reported on Ec2Instance.
+const (
+	// ConditionReady is True while the instance is running and has an IP address.
+	ConditionReady = "Ready"
+	// ConditionProvisioning is True while a launched instance has not come up yet.
+	ConditionProvisioning = "Provisioning"
+	// ConditionPendingReplacement is True from the moment the operator terminates the instance for
+	// ec2instance.compute.cloud.com/force-replace until its replacement is launched.
+	ConditionPendingReplacement = "PendingReplacement"
+	// ConditionDegraded is True when AWS reports the instance terminated or no longer knows it; the
+	// operator then launches a replacement.
+	ConditionDegraded = "Degraded"
+	// ConditionTagPolicyCompliant is False when instance tags violate the organization's tag policy.
+	ConditionTagPolicyCompliant = "TagPolicyCompliant"
+	// ConditionCreationConditionNotMet is True while spec.creationCondition holds back the launch.
+	ConditionCreationConditionNotMet = "CreationConditionNotMet"
+	// ConditionInvalidStateTransition is True when the operator refused an action because the
+	// instance cannot get there from its current state, e.g. starting a terminated instance.
+	ConditionInvalidStateTransition = "InvalidStateTransition"
+	// ConditionSpotInterrupted is True when AWS reclaimed the Spot instance. Instances of an
+	// Ec2InstanceSet are then left for the set to replace.
+	ConditionSpotInterrupted = "SpotInterrupted"
+	// ConditionPaused is True while the ec2instance.compute.cloud.com/paused annotation stops the
+	// operator from reconciling the instance.
+	ConditionPaused = "Paused"
+	// ConditionFailed is True when the operator gave up on the instance, e.g. because the instance
+	// named in spec.adoptInstanceID does not exist or is terminated.
+	ConditionFailed = "Failed"
+	// ConditionCrossAccountReady is set when spec.roleARN is, and is False while the role cannot be
+	// assumed.
+	ConditionCrossAccountReady = "CrossAccountReady"
+	// ConditionAutoRecoveryUnsupported is True while spec.autoRecovery is set but the instance type
+	// does not support automatic recovery.
+	ConditionAutoRecoveryUnsupported = "AutoRecoveryUnsupported"
+)
+
+// SnapshotRef identifies a snapshot taken by spec.snapshotSchedule.
+type SnapshotRef struct {
+	SnapshotID string      `json:"snapshotID"`
+	StartTime  metav1.Time `json:"startTime"`
+	// SourceSnapshotID is the snapshot a replica was copied from. It is empty for source snapshots.
+	// +optional
+	SourceSnapshotID string `json:"sourceSnapshotID,omitempty"`
+}
+
+// TagComplianceStatus describes how the instance tags compare to the effective tag policy.
+type TagComplianceStatus struct {
+	Compliant bool `json:"compliant"`
+	// ViolatedKeys are the policy tag keys whose capitalization or value on the instance is not allowed.
+	ViolatedKeys    []string     `json:"violatedKeys,omitempty"`
+	LastEvaluatedAt *metav1.Time `json:"lastEvaluatedAt,omitempty"`
 }
 
 // StorageConfig defines the storage configuration for the EC2 instance.
@@ -58,6 +1131,50 @@ type StorageConfig struct {
 	AdditionalVolumes []VolumeConfig `json:"additionalVolumes,omitempty"`
 }
 
+// ElasticIPSpec associates an Elastic IP with the instance.
+type ElasticIPSpec struct {
+	// Enabled associates an Elastic IP once the instance runs. Without existingAllocationID the
+	// operator allocates one and releases it when the Ec2Instance is deleted.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ExistingAllocationID is an Elastic IP allocated outside the operator (eipalloc-...) to use
+	// instead of allocating one. It is left allocated when the Ec2Instance is deleted.
+	// +kubebuilder:validation:Pattern=`^eipalloc-[0-9a-f]+$`
+	// +optional
+	ExistingAllocationID string `json:"existingAllocationID,omitempty"`
+}
+
+// EBSVolumeSpec is a data volume managed alongside the instance.
+// +kubebuilder:validation:XValidation:rule="self.sizeGB > 0 || has(self.snapshotID)",message="sizeGB is required unless the volume is created from a snapshot"
+type EBSVolumeSpec struct {
+	// DeviceName is where the volume is attached, e.g. /dev/sdf.
+	// +kubebuilder:validation:Pattern=`^/dev/(sd[b-z]|xvd[b-z][a-z]?)$`
+	DeviceName string `json:"deviceName"`
+
+	// SizeGB defaults to the size of the snapshot when snapshotID is set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SizeGB int32 `json:"sizeGB,omitempty"`
+
+	// +kubebuilder:validation:Enum=gp2;gp3;io1;io2;st1;sc1;standard
+	// +kubebuilder:default=gp3
+	// +optional
+	VolumeType string `json:"volumeType,omitempty"`
+
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
+
+	// SnapshotID creates the volume from an EBS snapshot.
+	// +optional
+	SnapshotID string `json:"snapshotID,omitempty"`
+
+	// RetainOnDelete keeps the volume when the Ec2Instance is deleted. Otherwise it is deleted
+	// along with the instance.
+	// +optional
+	RetainOnDelete bool `json:"retainOnDelete,omitempty"`
+}
+
 // VolumeConfig defines the configuration for a volume.
 type VolumeConfig struct {
 	Size       int32  `json:"size"`
@@ -0,0 +1,192 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Ec2InstanceSetLabel is set on every Ec2Instance of a set to the name of the set.
+	Ec2InstanceSetLabel = "ec2instance.compute.cloud.com/set"
+	// TemplateHashLabel is set on every Ec2Instance of a set to the hash of the template it was created from.
+	TemplateHashLabel = "ec2instance.compute.cloud.com/template-hash"
+	// SpotPoolLabel is set on every Ec2Instance of a set that was launched in a Spot capacity pool,
+	// to the key of the pool.
+	SpotPoolLabel = "ec2instance.compute.cloud.com/spot-pool"
+	// SetIndexLabel is set on every Ec2Instance of a set to its ordinal in the set. Ordinals are
+	// reused once their instance is gone, so the lowest free one is always taken.
+	SetIndexLabel = "ec2instance.compute.cloud.com/set-index"
+
+	// ConditionRollbackTriggered is set on an Ec2InstanceSet whose current template was rolled back
+	// because new instances failed their health checks.
+	ConditionRollbackTriggered = "RollbackTriggered"
+)
+
+// Ec2InstanceTemplate describes the Ec2Instances a set creates.
+type Ec2InstanceTemplate struct {
+	// Labels are added to every Ec2Instance, next to the labels the set manages itself.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	Spec Ec2InstanceSpec `json:"spec"`
+}
+
+// AutoRollbackSpec rolls an update back when the new instances do not become healthy. Instances
+// are judged by their Route53 health check (spec.route53HealthCheck of the template).
+type AutoRollbackSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// HealthCheckFailureThreshold is the number of failed health check observations of a single new
+	// instance that triggers the rollback.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// +optional
+	HealthCheckFailureThreshold int32 `json:"healthCheckFailureThreshold,omitempty"`
+
+	// HealthCheckGracePeriodSeconds is how long after launch failed health checks are ignored, to
+	// give the instance time to boot.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=300
+	// +optional
+	HealthCheckGracePeriodSeconds int32 `json:"healthCheckGracePeriodSeconds,omitempty"`
+}
+
+// SpotCapacityPool is a combination of instance type and availability zone to launch Spot
+// instances in.
+type SpotCapacityPool struct {
+	// +kubebuilder:validation:MinLength=1
+	InstanceType string `json:"instanceType"`
+	// +kubebuilder:validation:MinLength=1
+	AvailabilityZone string `json:"availabilityZone"`
+
+	// Weight is the share of the instances the pool gets, relative to the other pools.
+	// +kubebuilder:validation:Minimum=0
+	Weight float64 `json:"weight"`
+
+	// MaxPrice is the highest hourly price in USD to pay in this pool. Defaults to the On-Demand price.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	MaxPrice string `json:"maxPrice,omitempty"`
+}
+
+// PoolStatus is the observed state of one Spot capacity pool of a set.
+type PoolStatus struct {
+	// Instances is the number of Ec2Instances of the set in the pool.
+	Instances int32 `json:"instances"`
+
+	// Interruptions counts the Spot interruptions in the pool; it is reset once the pool has gone an
+	// hour without one. LastInterruptionAt is when the latest happened.
+	// +optional
+	Interruptions int32 `json:"interruptions,omitempty"`
+	// +optional
+	LastInterruptionAt *metav1.Time `json:"lastInterruptionAt,omitempty"`
+
+	// EffectiveWeight is spec.weight lowered by the recent interruptions; new instances are spread
+	// by it.
+	EffectiveWeight float64 `json:"effectiveWeight"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.spotCapacityPools) || !has(self.template.spec.subnet)",message="template.spec.subnet cannot be set with spotCapacityPools; instances use the default subnet of their pool's availability zone"
+// Ec2InstanceSetSpec defines the desired state of Ec2InstanceSet.
+
+type Ec2InstanceSetSpec struct {
+	// Replicas is the number of Ec2Instances to run.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// Template is the Ec2Instance to create. Changing it replaces the instances one at a time: a new
+	// instance is launched and becomes healthy before an old one is deleted.
+	Template Ec2InstanceTemplate `json:"template"`
+
+	// AutoRollback returns to the last template that rolled out successfully when new instances
+	// fail their health checks.
+	// +optional
+	AutoRollback AutoRollbackSpec `json:"autoRollback,omitempty"`
+
+	// SpotCapacityPools launches the instances as Spot instances spread over these pools by weight,
+	// overriding the instance type and availability zone of the template. Pools with recent
+	// interruptions get a lower weight, and interrupted instances are replaced in the healthiest
+	// pools. Changing the pools only affects instances launched afterwards.
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	SpotCapacityPools []SpotCapacityPool `json:"spotCapacityPools,omitempty"`
+}
+
+// InstanceHealthFailures counts the failed health checks of one new instance of a set.
+type InstanceHealthFailures struct {
+	// Name of the Ec2Instance.
+	Name     string `json:"name"`
+	Failures int32  `json:"failures"`
+	// LastChecked is status.healthCheckLastChecked of the instance when it was last counted, so
+	// every health check result is counted once.
+	LastChecked *metav1.Time `json:"lastChecked,omitempty"`
+}
+
+// Ec2InstanceSetStatus defines the observed state of Ec2InstanceSet.
+type Ec2InstanceSetStatus struct {
+	Replicas      int32 `json:"replicas"`
+	ReadyReplicas int32 `json:"readyReplicas"`
+	// UpdatedReplicas are the instances created from the template being rolled out.
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+	// AvailableReplicas are the ready instances of any template. During a rollout it includes the
+	// outdated instances that still serve, where readyReplicas only counts the updated ones.
+	AvailableReplicas int32 `json:"availableReplicas"`
+
+	// CurrentTemplateHash is the hash of the template being rolled out.
+	CurrentTemplateHash string `json:"currentTemplateHash,omitempty"`
+	// StableTemplateHash and StableTemplate are the last template all replicas ran healthy with. A
+	// rollback returns to it.
+	StableTemplateHash string               `json:"stableTemplateHash,omitempty"`
+	StableTemplate     *Ec2InstanceTemplate `json:"stableTemplate,omitempty"`
+	// FailedTemplateHash is the hash of spec.template after it was rolled back. The set keeps running
+	// the stable template until spec.template changes again.
+	FailedTemplateHash string `json:"failedTemplateHash,omitempty"`
+
+	// HealthCheckFailures tracks the failed health checks of the instances being rolled out.
+	// +optional
+	HealthCheckFailures []InstanceHealthFailures `json:"healthCheckFailures,omitempty"`
+
+	// PoolBreakdown reports each Spot capacity pool by key (<instanceType>-<availabilityZone>).
+	// +optional
+	PoolBreakdown map[string]PoolStatus `json:"poolBreakdown,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Desired",type="integer",JSONPath=".spec.replicas"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas"
+// +kubebuilder:printcolumn:name="Updated",type="integer",JSONPath=".status.updatedReplicas"
+// +kubebuilder:printcolumn:name="Available",type="integer",JSONPath=".status.availableReplicas"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// Ec2InstanceSet is the Schema for the ec2instancesets API.
+// It keeps a number of identical Ec2Instances running and rolls template changes out one instance
+// at a time.
+
+type Ec2InstanceSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   Ec2InstanceSetSpec   `json:"spec,omitempty"`
+	Status Ec2InstanceSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Ec2InstanceSetList contains a list of Ec2InstanceSet.
+type Ec2InstanceSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Ec2InstanceSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Ec2InstanceSet{}, &Ec2InstanceSetList{})
+}
@@ -0,0 +1,70 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeyPairPrivateKeySecretKey is the key of the private key in the Secret of privateKeySecretRef.
+// It is the key kubernetes.io/ssh-auth Secrets use.
+const KeyPairPrivateKeySecretKey = corev1.SSHAuthPrivateKey
+
+// Ec2KeyPairSpec describes an SSH key pair created in AWS. AWS only returns the private key when the
+// key pair is created, so it is kept in a Secret.
+// +kubebuilder:validation:XValidation:rule="self.region == oldSelf.region && self.keyName == oldSelf.keyName && self.privateKeySecretRef == oldSelf.privateKeySecretRef",message="region, keyName and privateKeySecretRef cannot be changed; create a new key pair instead"
+type Ec2KeyPairSpec struct {
+	Region string `json:"region"`
+
+	// KeyName is the name of the key pair in AWS, and what Ec2Instances launch with.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	KeyName string `json:"keyName"`
+
+	// PrivateKeySecretRef is the Secret in the same namespace the private key is written to, under
+	// ssh-privatekey. The Secret is created if it does not exist, and then deleted with this object.
+	PrivateKeySecretRef corev1.LocalObjectReference `json:"privateKeySecretRef"`
+}
+
+// Ec2KeyPairStatus is the observed state of the key pair in AWS.
+type Ec2KeyPairStatus struct {
+	// KeyPairID is the key pair (key-...) in AWS.
+	// +optional
+	KeyPairID string `json:"keyPairID,omitempty"`
+
+	// KeyFingerprint is the fingerprint AWS reports for the key.
+	// +optional
+	KeyFingerprint string `json:"keyFingerprint,omitempty"`
+
+	// Message explains why the key pair could not be created.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="KeyName",type="string",JSONPath=".spec.keyName"
+// +kubebuilder:printcolumn:name="Fingerprint",type="string",JSONPath=".status.keyFingerprint"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// Ec2KeyPair is the Schema for the ec2keypairs API.
+// It manages an SSH key pair that Ec2Instances can reference by name.
+
+type Ec2KeyPair struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   Ec2KeyPairSpec   `json:"spec,omitempty"`
+	Status Ec2KeyPairStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Ec2KeyPairList contains a list of Ec2KeyPair.
+type Ec2KeyPairList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Ec2KeyPair `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Ec2KeyPair{}, &Ec2KeyPairList{})
+}
@@ -0,0 +1,102 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Ec2LaunchTemplateSpec describes a launch template managed in AWS. Every change to
+// launchTemplateData creates a new version of the template and makes it the default one.
+// +kubebuilder:validation:XValidation:rule="self.region == oldSelf.region && self.templateName == oldSelf.templateName",message="region and templateName cannot be changed; create a new launch template instead"
+type Ec2LaunchTemplateSpec struct {
+	Region string `json:"region"`
+
+	// TemplateName is the name of the launch template in AWS.
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=128
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9().\/_-]+$`
+	TemplateName string `json:"templateName"`
+
+	LaunchTemplateData LaunchTemplateDataSpec `json:"launchTemplateData"`
+}
+
+// LaunchTemplateDataSpec is the part of the RunInstances parameters a launch template captures.
+type LaunchTemplateDataSpec struct {
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+	// +optional
+	AMIId string `json:"amiId,omitempty"`
+	// +optional
+	KeyName string `json:"keyName,omitempty"`
+
+	// SecurityGroupIDs are the IDs of the security groups of the primary network interface.
+	// +optional
+	SecurityGroupIDs []string `json:"securityGroupIDs,omitempty"`
+
+	// IAMInstanceProfile is the name or ARN of the instance profile.
+	// +optional
+	IAMInstanceProfile string `json:"iamInstanceProfile,omitempty"`
+
+	// UserData is the user data in plain text; it is base64 encoded for AWS.
+	// +optional
+	UserData string `json:"userData,omitempty"`
+
+	// +optional
+	EBSOptimized bool `json:"ebsOptimized,omitempty"`
+
+	// +optional
+	MetadataOptions *MetadataOptionsSpec `json:"metadataOptions,omitempty"`
+
+	// Tags are put on the instances launched from the template.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// Ec2LaunchTemplateStatus is the observed state of the launch template in AWS.
+type Ec2LaunchTemplateStatus struct {
+	// TemplateID is the launch template (lt-...) in AWS.
+	// +optional
+	TemplateID string `json:"templateID,omitempty"`
+
+	// DefaultVersion is the version of the template instances are launched from, the one created
+	// for launchTemplateData at ObservedGeneration.
+	// +optional
+	DefaultVersion int32 `json:"defaultVersion,omitempty"`
+
+	// ObservedGeneration is the generation of the spec DefaultVersion was created for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Message explains why the launch template could not be created.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="TemplateName",type="string",JSONPath=".spec.templateName"
+// +kubebuilder:printcolumn:name="TemplateID",type="string",JSONPath=".status.templateID"
+// +kubebuilder:printcolumn:name="Version",type="integer",JSONPath=".status.defaultVersion"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// Ec2LaunchTemplate is the Schema for the ec2launchtemplates API.
+// It manages a launch template that Ec2Instances can launch from.
+
+type Ec2LaunchTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   Ec2LaunchTemplateSpec   `json:"spec,omitempty"`
+	Status Ec2LaunchTemplateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Ec2LaunchTemplateList contains a list of Ec2LaunchTemplate.
+type Ec2LaunchTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Ec2LaunchTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Ec2LaunchTemplate{}, &Ec2LaunchTemplateList{})
+}
@@ -0,0 +1,113 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Ec2OperatorConfigName is the name of the singleton Ec2OperatorConfig object read by the operator.
+const Ec2OperatorConfigName = "cluster"
+
+// Ec2OperatorConfigSpec holds settings that apply to every Ec2Instance in the cluster.
+type Ec2OperatorConfigSpec struct {
+	// RequiredTags must be set on every Ec2Instance. The validating webhook checks spec.tags and the
+	// operator adds tags that are missing on the EC2 instance itself.
+	// +listType=map
+	// +listMapKey=key
+	// +optional
+	RequiredTags []RequiredTag `json:"requiredTags,omitempty"`
+
+	// WebhookAWSTimeoutSeconds bounds each AWS call made by the validating webhook. A check whose
+	// call takes longer is skipped with a warning and the object is admitted.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=5
+	// +optional
+	WebhookAWSTimeoutSeconds int32 `json:"webhookAWSTimeoutSeconds,omitempty"`
+
+	// InventoryExport periodically uploads every Ec2Instance to S3 for tools outside of Kubernetes.
+	// +optional
+	InventoryExport InventoryExportSpec `json:"inventoryExport,omitempty"`
+}
+
+// InventoryExportFormat is the file format of an inventory export.
+// +kubebuilder:validation:Enum=JSON;CSV
+type InventoryExportFormat string
+
+const (
+	InventoryExportFormatJSON InventoryExportFormat = "JSON"
+	InventoryExportFormatCSV  InventoryExportFormat = "CSV"
+)
+
+// +kubebuilder:validation:XValidation:rule="!has(self.enabled) || !self.enabled || has(self.s3Bucket)",message="s3Bucket is required when the inventory export is enabled"
+// InventoryExportSpec configures the S3 inventory export.
+
+type InventoryExportSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// S3Bucket receives the exports, as <s3Prefix>/inventory-<YYYY-MM-DD>.<json|csv>. Each export
+	// overwrites the file of the day, so the bucket holds the last state of every day.
+	S3Bucket string `json:"s3Bucket,omitempty"`
+	// S3Prefix is the key prefix of the exports.
+	// +optional
+	S3Prefix string `json:"s3Prefix,omitempty"`
+	// Region is the region of the bucket.
+	// +kubebuilder:default=us-east-1
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// +kubebuilder:default=JSON
+	// +optional
+	Format InventoryExportFormat `json:"format,omitempty"`
+
+	// ExportIntervalMinutes is the time between two exports.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=60
+	// +optional
+	ExportIntervalMinutes int32 `json:"exportIntervalMinutes,omitempty"`
+
+	// KMSKeyID encrypts the exports with this KMS key (SSE-KMS). Without it S3 managed keys (SSE-S3) are used.
+	// +optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.defaultValue) || !has(self.allowedValues) || self.defaultValue in self.allowedValues",message="defaultValue must be one of allowedValues"
+// RequiredTag is a tag every instance must carry.
+
+type RequiredTag struct {
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// AllowedValues restricts the tag value. When empty any non-empty value is accepted.
+	// +optional
+	AllowedValues []string `json:"allowedValues,omitempty"`
+
+	// DefaultValue is applied when an Ec2Instance does not set the tag. Without a default, such
+	// instances are rejected.
+	// +optional
+	DefaultValue string `json:"defaultValue,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="Ec2OperatorConfig is a singleton and must be named 'cluster'"
+// Ec2OperatorConfig is the Schema for the ec2operatorconfigs API.
+// It is a cluster-scoped singleton with operator wide settings.
+
+type Ec2OperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec Ec2OperatorConfigSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Ec2OperatorConfigList contains a list of Ec2OperatorConfig.
+type Ec2OperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Ec2OperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Ec2OperatorConfig{}, &Ec2OperatorConfigList{})
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PlacementStrategy is how a placement group places its instances.
+// +kubebuilder:validation:Enum=cluster;spread;partition
+type PlacementStrategy string
+
+const (
+	// PlacementStrategyCluster packs instances close together in one availability zone for low
+	// latency networking between them.
+	PlacementStrategyCluster PlacementStrategy = "cluster"
+	// PlacementStrategySpread puts every instance on distinct hardware.
+	PlacementStrategySpread PlacementStrategy = "spread"
+	// PlacementStrategyPartition puts instances into partitions that share no hardware.
+	PlacementStrategyPartition PlacementStrategy = "partition"
+)
+
+// Ec2PlacementGroupSpec describes a placement group created in AWS. A placement group cannot be
+// modified, so the spec cannot be changed either.
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="a placement group cannot be changed; create a new one instead"
+// +kubebuilder:validation:XValidation:rule="!has(self.partitionCount) || self.strategy == 'partition'",message="partitionCount requires the partition strategy"
+type Ec2PlacementGroupSpec struct {
+	Region string `json:"region"`
+
+	// GroupName is the name of the placement group in AWS, and what Ec2Instances set in
+	// spec.placementGroup.name.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	GroupName string `json:"groupName"`
+
+	Strategy PlacementStrategy `json:"strategy"`
+
+	// PartitionCount is the number of partitions of a partition placement group.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=7
+	// +optional
+	PartitionCount int32 `json:"partitionCount,omitempty"`
+}
+
+// Ec2PlacementGroupStatus is the observed state of the placement group in AWS.
+type Ec2PlacementGroupStatus struct {
+	// GroupID is the placement group (pg-...) in AWS.
+	// +optional
+	GroupID string `json:"groupID,omitempty"`
+
+	// State is the state AWS reports for the placement group, e.g. available.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Message explains why the placement group could not be created.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="GroupName",type="string",JSONPath=".spec.groupName"
+// +kubebuilder:printcolumn:name="Strategy",type="string",JSONPath=".spec.strategy"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// Ec2PlacementGroup is the Schema for the ec2placementgroups API.
+// It manages a placement group that Ec2Instances can launch into by name.
+
+type Ec2PlacementGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   Ec2PlacementGroupSpec   `json:"spec,omitempty"`
+	Status Ec2PlacementGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Ec2PlacementGroupList contains a list of Ec2PlacementGroup.
+type Ec2PlacementGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Ec2PlacementGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Ec2PlacementGroup{}, &Ec2PlacementGroupList{})
+}
@@ -0,0 +1,142 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecurityGroupProtocol is the IP protocol a rule applies to.
+// +kubebuilder:validation:Enum=tcp;udp;icmp;all
+type SecurityGroupProtocol string
+
+const (
+	SecurityGroupProtocolTCP  SecurityGroupProtocol = "tcp"
+	SecurityGroupProtocolUDP  SecurityGroupProtocol = "udp"
+	SecurityGroupProtocolICMP SecurityGroupProtocol = "icmp"
+	// SecurityGroupProtocolAll matches all traffic; the ports are ignored.
+	SecurityGroupProtocolAll SecurityGroupProtocol = "all"
+)
+
+// IngressRule allows inbound traffic from CIDR blocks or other security groups.
+// +kubebuilder:validation:XValidation:rule="(has(self.cidrBlocks) && size(self.cidrBlocks) > 0) || (has(self.sourceSecurityGroupIDs) && size(self.sourceSecurityGroupIDs) > 0)",message="a rule needs cidrBlocks or sourceSecurityGroupIDs"
+type IngressRule struct {
+	// +kubebuilder:default=tcp
+	Protocol SecurityGroupProtocol `json:"protocol,omitempty"`
+
+	// FromPort and ToPort are the port range. For icmp they are the ICMP type and code, with -1 for any.
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	FromPort int32 `json:"fromPort,omitempty"`
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	ToPort int32 `json:"toPort,omitempty"`
+
+	// CIDRBlocks are IPv4 or IPv6 ranges, e.g. 10.0.0.0/16 or ::/0.
+	// +optional
+	CIDRBlocks []string `json:"cidrBlocks,omitempty"`
+
+	// SourceSecurityGroupIDs are security groups (sg-...) whose members are allowed in.
+	// +optional
+	SourceSecurityGroupIDs []string `json:"sourceSecurityGroupIDs,omitempty"`
+
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// EgressRule allows outbound traffic to CIDR blocks or other security groups.
+// +kubebuilder:validation:XValidation:rule="(has(self.cidrBlocks) && size(self.cidrBlocks) > 0) || (has(self.destinationSecurityGroupIDs) && size(self.destinationSecurityGroupIDs) > 0)",message="a rule needs cidrBlocks or destinationSecurityGroupIDs"
+type EgressRule struct {
+	// +kubebuilder:default=tcp
+	Protocol SecurityGroupProtocol `json:"protocol,omitempty"`
+
+	// FromPort and ToPort are the port range. For icmp they are the ICMP type and code, with -1 for any.
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	FromPort int32 `json:"fromPort,omitempty"`
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	ToPort int32 `json:"toPort,omitempty"`
+
+	// CIDRBlocks are IPv4 or IPv6 ranges, e.g. 0.0.0.0/0.
+	// +optional
+	CIDRBlocks []string `json:"cidrBlocks,omitempty"`
+
+	// DestinationSecurityGroupIDs are security groups (sg-...) whose members may be reached.
+	// +optional
+	DestinationSecurityGroupIDs []string `json:"destinationSecurityGroupIDs,omitempty"`
+
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// Ec2SecurityGroupSpec describes a VPC security group and its rules. The rules are kept exactly as
+// listed; rules added in AWS by hand are removed again.
+// +kubebuilder:validation:XValidation:rule="self.region == oldSelf.region && self.vpcID == oldSelf.vpcID && self.description == oldSelf.description",message="region, vpcID and description cannot be changed; create a new security group instead"
+type Ec2SecurityGroupSpec struct {
+	Region string `json:"region"`
+
+	// VpcID is the VPC (vpc-...) the group is created in.
+	// +kubebuilder:validation:MinLength=1
+	VpcID string `json:"vpcID"`
+
+	// Description is required by AWS and cannot be changed after creation.
+	// +kubebuilder:default="Managed by ec2operator"
+	// +kubebuilder:validation:MaxLength=255
+	Description string `json:"description,omitempty"`
+
+	// +optional
+	Ingress []IngressRule `json:"ingress,omitempty"`
+
+	// Egress rules replace the allow-all rule AWS creates with every group. Without any, the
+	// allow-all rule is kept.
+	// +optional
+	Egress []EgressRule `json:"egress,omitempty"`
+}
+
+// Ec2SecurityGroupStatus is the observed state of the group in AWS.
+type Ec2SecurityGroupStatus struct {
+	// GroupID is the security group (sg-...) in AWS. Ec2Instances that reference this object
+	// through spec.securityGroupRefs wait until it is set.
+	// +optional
+	GroupID string `json:"groupID,omitempty"`
+
+	// ObservedGeneration is the generation whose rules are applied.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Message explains why the rules could not be applied.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="VPC",type="string",JSONPath=".spec.vpcID"
+// +kubebuilder:printcolumn:name="GroupID",type="string",JSONPath=".status.groupID"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// Ec2SecurityGroup is the Schema for the ec2securitygroups API.
+// It manages a VPC security group that Ec2Instances can reference by name.
+
+type Ec2SecurityGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   Ec2SecurityGroupSpec   `json:"spec,omitempty"`
+	Status Ec2SecurityGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Ec2SecurityGroupList contains a list of Ec2SecurityGroup.
+type Ec2SecurityGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Ec2SecurityGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Ec2SecurityGroup{}, &Ec2SecurityGroupList{})
+}
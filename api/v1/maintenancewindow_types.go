@@ -0,0 +1,69 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaintenanceWindowSpec describes an SSM maintenance window. Ec2Instances with
+// spec.patchManagement refer to it to have their patches installed inside the window.
+// +kubebuilder:validation:XValidation:rule="self.region == oldSelf.region",message="region cannot be changed; create a new maintenance window instead"
+// +kubebuilder:validation:XValidation:rule="self.cutoffHours < self.durationHours",message="cutoffHours must be less than durationHours"
+type MaintenanceWindowSpec struct {
+	Region string `json:"region"`
+
+	// Schedule is a cron or rate expression in SSM syntax, e.g. cron(0 2 ? * SUN *).
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// ScheduleTimezone is the IANA time zone the schedule is evaluated in. Defaults to UTC.
+	// +optional
+	ScheduleTimezone string `json:"scheduleTimezone,omitempty"`
+
+	// DurationHours is how long the window stays open.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=24
+	DurationHours int32 `json:"durationHours"`
+
+	// CutoffHours is how long before the end of the window no new tasks are started.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=23
+	// +optional
+	CutoffHours int32 `json:"cutoffHours,omitempty"`
+}
+
+// MaintenanceWindowStatus is the observed state of the maintenance window in AWS.
+type MaintenanceWindowStatus struct {
+	WindowID string `json:"windowID,omitempty"`
+
+	// ObservedGeneration is the generation whose schedule and duration are applied.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Duration",type="integer",JSONPath=".spec.durationHours"
+// +kubebuilder:printcolumn:name="WindowID",type="string",JSONPath=".status.windowID"
+// MaintenanceWindow is the Schema for the maintenancewindows API.
+// It manages an SSM maintenance window that patch tasks of Ec2Instances are scheduled in.
+
+type MaintenanceWindow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MaintenanceWindowSpec   `json:"spec,omitempty"`
+	Status MaintenanceWindowStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MaintenanceWindowList contains a list of MaintenanceWindow.
+type MaintenanceWindowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MaintenanceWindow `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MaintenanceWindow{}, &MaintenanceWindowList{})
+}
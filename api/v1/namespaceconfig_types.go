@@ -0,0 +1,54 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceConfigName is the name of the NamespaceConfig object the operator reads in each namespace.
+const NamespaceConfigName = "config"
+
+// NamespaceConfigSpec holds settings that apply to every Ec2Instance in the namespace.
+type NamespaceConfigSpec struct {
+	// MaxMonthlySpendUSD caps the estimated monthly cost of the instances in the namespace. The
+	// validating webhook rejects new Ec2Instances that would take the namespace over the limit.
+	// Zero means no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxMonthlySpendUSD float64 `json:"maxMonthlySpendUSD,omitempty"`
+}
+
+// NamespaceConfigStatus is the observed state of the namespace.
+type NamespaceConfigStatus struct {
+	// CurrentMonthlySpendUSD is the estimated monthly cost of the instances in the namespace that
+	// are running or about to be.
+	CurrentMonthlySpendUSD float64 `json:"currentMonthlySpendUSD"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'config'",message="NamespaceConfig must be named 'config'"
+// +kubebuilder:printcolumn:name="MaxMonthlySpendUSD",type="number",JSONPath=".spec.maxMonthlySpendUSD"
+// +kubebuilder:printcolumn:name="CurrentMonthlySpendUSD",type="number",JSONPath=".status.currentMonthlySpendUSD"
+// NamespaceConfig is the Schema for the namespaceconfigs API.
+// There is at most one per namespace, named 'config'.
+
+type NamespaceConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NamespaceConfigSpec   `json:"spec,omitempty"`
+	Status NamespaceConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceConfigList contains a list of NamespaceConfig.
+type NamespaceConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespaceConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespaceConfig{}, &NamespaceConfigList{})
+}
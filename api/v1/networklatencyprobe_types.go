@@ -0,0 +1,115 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LatencyProbeProtocol is how latency between the two instances is measured.
+// +kubebuilder:validation:Enum=ICMP;TCP
+type LatencyProbeProtocol string
+
+const (
+	// LatencyProbeProtocolICMP measures the round trip of ICMP echo requests (ping).
+	LatencyProbeProtocolICMP LatencyProbeProtocol = "ICMP"
+	// LatencyProbeProtocolTCP measures how long a TCP connection to spec.port takes to open.
+	LatencyProbeProtocolTCP LatencyProbeProtocol = "TCP"
+)
+
+// NetworkLatencyProbeSpec describes a recurring latency measurement between two Ec2Instances in
+// the same namespace and region.
+// +kubebuilder:validation:XValidation:rule="self.protocol != 'TCP' || has(self.port)",message="port is required for TCP probes"
+type NetworkLatencyProbeSpec struct {
+	// SourceEc2InstanceRef is the instance the probes are sent from. It must run the SSM agent.
+	SourceEc2InstanceRef corev1.LocalObjectReference `json:"sourceEc2InstanceRef"`
+
+	// TargetEc2InstanceRef is the instance the probes are sent to, on its private IP.
+	TargetEc2InstanceRef corev1.LocalObjectReference `json:"targetEc2InstanceRef"`
+
+	// ProbeIntervalSeconds is the time between the start of two measurements.
+	// +kubebuilder:validation:Minimum=60
+	// +kubebuilder:default=300
+	ProbeIntervalSeconds int32 `json:"probeIntervalSeconds,omitempty"`
+
+	// +kubebuilder:default=ICMP
+	Protocol LatencyProbeProtocol `json:"protocol,omitempty"`
+
+	// Port is the TCP port on the target to connect to.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// AlertThresholdMs raises a LatencyThresholdExceeded warning event when the p99 latency of a
+	// measurement is above it. Zero disables the alert.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	AlertThresholdMs float64 `json:"alertThresholdMs,omitempty"`
+}
+
+// NetworkLatencyProbeStatus is the result of the last measurement.
+type NetworkLatencyProbeStatus struct {
+	// SourceInstanceID and TargetInstanceID are the EC2 instances the network insights path runs between.
+	// +optional
+	SourceInstanceID string `json:"sourceInstanceID,omitempty"`
+	// +optional
+	TargetInstanceID string `json:"targetInstanceID,omitempty"`
+
+	// PathID is the Reachability Analyzer path (nip-...) between the instances.
+	// +optional
+	PathID string `json:"pathID,omitempty"`
+
+	// PathAnalysisID is the latest Reachability Analyzer analysis (nia-...) of the path, and
+	// Reachable its outcome.
+	// +optional
+	PathAnalysisID string `json:"pathAnalysisID,omitempty"`
+	// +optional
+	Reachable *bool `json:"reachable,omitempty"`
+
+	// CommandID is the SSM command that is measuring the latency right now.
+	// +optional
+	CommandID string `json:"commandID,omitempty"`
+
+	// +optional
+	LatencyP50Ms float64 `json:"latencyP50Ms,omitempty"`
+	// +optional
+	LatencyP99Ms float64 `json:"latencyP99Ms,omitempty"`
+	// +optional
+	LastMeasuredAt *metav1.Time `json:"lastMeasuredAt,omitempty"`
+
+	// Message explains why the last measurement failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.sourceEc2InstanceRef.name"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetEc2InstanceRef.name"
+// +kubebuilder:printcolumn:name="Reachable",type="boolean",JSONPath=".status.reachable"
+// +kubebuilder:printcolumn:name="P50",type="number",JSONPath=".status.latencyP50Ms"
+// +kubebuilder:printcolumn:name="P99",type="number",JSONPath=".status.latencyP99Ms"
+// +kubebuilder:printcolumn:name="Measured",type="date",JSONPath=".status.lastMeasuredAt"
+// NetworkLatencyProbe is the Schema for the networklatencyprobes API.
+// It checks the network path between two Ec2Instances and measures its latency.
+
+type NetworkLatencyProbe struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkLatencyProbeSpec   `json:"spec,omitempty"`
+	Status NetworkLatencyProbeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NetworkLatencyProbeList contains a list of NetworkLatencyProbe.
+type NetworkLatencyProbeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NetworkLatencyProbe `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NetworkLatencyProbe{}, &NetworkLatencyProbeList{})
+}
@@ -0,0 +1,73 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RecoverOrphanPhase is the stage a RecoverOrphan is in.
+type RecoverOrphanPhase string
+
+const (
+	RecoverOrphanPhaseCompleted RecoverOrphanPhase = "Completed"
+	RecoverOrphanPhaseFailed    RecoverOrphanPhase = "Failed"
+)
+
+// RecoveredFromOrphanAnnotation is set on Ec2Instances created by a RecoverOrphan to its name.
+const RecoveredFromOrphanAnnotation = "ec2instance.compute.cloud.com/recovered-by"
+
+// RecoverOrphanSpec names an EC2 instance that lost its Ec2Instance and where to recreate it.
+type RecoverOrphanSpec struct {
+	// InstanceID is the orphaned EC2 instance.
+	InstanceID string `json:"instanceID"`
+
+	// Region is the AWS region of the instance.
+	Region string `json:"region"`
+
+	// TargetNamespace and TargetName name the Ec2Instance that is created for the instance.
+	TargetNamespace string `json:"targetNamespace"`
+	TargetName      string `json:"targetName"`
+
+	// PopulateSpecFromAWS copies the key pair, subnet, availability zone, security groups and tags of
+	// the instance into the new spec. Without it only the instance type, AMI and region are set, and
+	// the operator leaves the rest of the instance as it is.
+	// +optional
+	PopulateSpecFromAWS bool `json:"populateSpecFromAWS,omitempty"`
+}
+
+// RecoverOrphanStatus reports the outcome of the recovery.
+type RecoverOrphanStatus struct {
+	Phase   RecoverOrphanPhase `json:"phase,omitempty"`
+	Message string             `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="InstanceID",type="string",JSONPath=".spec.instanceID"
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".spec.targetNamespace"
+// +kubebuilder:printcolumn:name="Name",type="string",JSONPath=".spec.targetName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// RecoverOrphan is the Schema for the recoverorphans API.
+// It brings an EC2 instance whose Ec2Instance was force deleted back under management. It is
+// cluster scoped because the Ec2Instance can be created in any namespace.
+
+type RecoverOrphan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RecoverOrphanSpec   `json:"spec,omitempty"`
+	Status RecoverOrphanStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RecoverOrphanList contains a list of RecoverOrphan.
+type RecoverOrphanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RecoverOrphan `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RecoverOrphan{}, &RecoverOrphanList{})
+}
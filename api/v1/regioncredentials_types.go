@@ -0,0 +1,73 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretKeyReference names a Secret in a specific namespace.
+type SecretKeyReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// RegionCredentialsSpec says which credentials the operator uses for a region.
+type RegionCredentialsSpec struct {
+	// Region is the AWS region these credentials apply to. Only one RegionCredentials may exist per region.
+	Region string `json:"region"`
+
+	// SecretRef points to a Secret with AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY. When unset,
+	// the operator's own credentials are used.
+	// +optional
+	SecretRef *SecretKeyReference `json:"secretRef,omitempty"`
+
+	// RoleARN is an IAM role assumed on top of the base credentials, typically a role in the account
+	// that owns the region in an organization-wide setup.
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
+
+	// ExternalID is passed when assuming roleARN, if the role's trust policy requires one.
+	// +optional
+	ExternalID string `json:"externalID,omitempty"`
+}
+
+// RegionCredentialsStatus reports whether the credentials work.
+type RegionCredentialsStatus struct {
+	// Ready is true once the credentials have been verified with STS GetCallerIdentity.
+	Ready bool `json:"ready"`
+	// AccountID is the AWS account the credentials resolve to.
+	AccountID string `json:"accountID,omitempty"`
+	// ARN is the identity the operator acts as in the region.
+	ARN     string `json:"arn,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Region",type="string",JSONPath=".spec.region"
+// +kubebuilder:printcolumn:name="Account",type="string",JSONPath=".status.accountID"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready"
+// RegionCredentials is the Schema for the regioncredentials API.
+// It maps an AWS region to the credentials every controller uses for AWS calls in that region.
+// Regions without one use the operator's default credentials.
+
+type RegionCredentials struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RegionCredentialsSpec   `json:"spec,omitempty"`
+	Status RegionCredentialsStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RegionCredentialsList contains a list of RegionCredentials.
+type RegionCredentialsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RegionCredentials `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RegionCredentials{}, &RegionCredentialsList{})
+}
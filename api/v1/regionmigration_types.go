@@ -0,0 +1,101 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AMICopyStrategy selects how the image for the target region is obtained.
+type AMICopyStrategy string
+
+const (
+	// AMICopyStrategyCopyThenLaunch copies the source AMI into the target region with CopyImage.
+	AMICopyStrategyCopyThenLaunch AMICopyStrategy = "CopyThenLaunch"
+	// AMICopyStrategyLaunchFromLatestAMI uses the newest AMI in the target region that has the same name and owner as the source AMI.
+	AMICopyStrategyLaunchFromLatestAMI AMICopyStrategy = "LaunchFromLatestAMI"
+)
+
+// RegionMigrationPhase is the stage a migration is in. Phases always move forward in the order listed below.
+type RegionMigrationPhase string
+
+const (
+	RegionMigrationPhasePending           RegionMigrationPhase = "Pending"
+	RegionMigrationPhaseCopyingAMI        RegionMigrationPhase = "CopyingAMI"
+	RegionMigrationPhaseLaunchingInstance RegionMigrationPhase = "LaunchingInstance"
+	RegionMigrationPhaseCuttingOver       RegionMigrationPhase = "CuttingOver"
+	RegionMigrationPhaseVerifyingHealth   RegionMigrationPhase = "VerifyingHealth"
+	RegionMigrationPhaseTerminatingSource RegionMigrationPhase = "TerminatingSource"
+	RegionMigrationPhaseCompleted         RegionMigrationPhase = "Completed"
+	RegionMigrationPhaseFailed            RegionMigrationPhase = "Failed"
+)
+
+// RegionMigrationSpec defines how an Ec2Instance is moved to another AWS region.
+type RegionMigrationSpec struct {
+	// SourceEc2InstanceRef is the Ec2Instance (in the same namespace) to migrate.
+	SourceEc2InstanceRef corev1.LocalObjectReference `json:"sourceEc2InstanceRef"`
+
+	// TargetRegion is the AWS region the instance is moved to.
+	TargetRegion string `json:"targetRegion"`
+
+	// AMICopyStrategy selects how the image is made available in the target region.
+	// +kubebuilder:validation:Enum=CopyThenLaunch;LaunchFromLatestAMI
+	// +kubebuilder:default=CopyThenLaunch
+	AMICopyStrategy AMICopyStrategy `json:"amiCopyStrategy,omitempty"`
+
+	// TargetSubnet is the subnet to launch into in the target region. Subnets are regional, so the
+	// source subnet cannot be reused; when empty the default subnet of the target region is used.
+	TargetSubnet string `json:"targetSubnet,omitempty"`
+
+	// TrafficCutoverRef points to a ConfigMap describing the Route53 record to move over to the new
+	// instance. The ConfigMap must contain the keys "hostedZoneID" and "recordName", and may contain "ttl".
+	// When unset, no DNS change is made.
+	// +optional
+	TrafficCutoverRef *corev1.LocalObjectReference `json:"trafficCutoverRef,omitempty"`
+}
+
+// RegionMigrationStatus tracks the progress of a RegionMigration.
+type RegionMigrationStatus struct {
+	Phase   RegionMigrationPhase `json:"phase,omitempty"`
+	Message string               `json:"message,omitempty"`
+
+	// TargetAMIID is the AMI used to launch the instance in the target region.
+	TargetAMIID string `json:"targetAMIID,omitempty"`
+	// TargetEc2InstanceName is the Ec2Instance created in the target region.
+	TargetEc2InstanceName string `json:"targetEc2InstanceName,omitempty"`
+	// TargetInstanceID is the AWS instance ID of the new instance.
+	TargetInstanceID string `json:"targetInstanceID,omitempty"`
+
+	CutoverTime    *metav1.Time `json:"cutoverTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.sourceEc2InstanceRef.name"
+// +kubebuilder:printcolumn:name="TargetRegion",type="string",JSONPath=".spec.targetRegion"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="TargetInstanceID",type="string",JSONPath=".status.targetInstanceID"
+// RegionMigration is the Schema for the regionmigrations API.
+// It moves the workload of an Ec2Instance to another region: AMI copy, launch, DNS cutover,
+// health verification and finally termination of the source instance.
+
+type RegionMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RegionMigrationSpec   `json:"spec,omitempty"`
+	Status RegionMigrationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RegionMigrationList contains a list of RegionMigration.
+type RegionMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RegionMigration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RegionMigration{}, &RegionMigrationList{})
+}
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MirrorFilterRule selects which packets are mirrored.
+type MirrorFilterRule struct {
+	// Protocol is tcp, udp, icmp or all.
+	// +kubebuilder:validation:Enum=tcp;udp;icmp;all
+	// +kubebuilder:default=all
+	Protocol string `json:"protocol,omitempty"`
+
+	// Direction is ingress (traffic arriving at the source instance) or egress (traffic leaving it).
+	// +kubebuilder:validation:Enum=ingress;egress
+	Direction string `json:"direction"`
+
+	// CIDR is the remote side of the traffic: the source CIDR for ingress rules and the destination
+	// CIDR for egress rules.
+	// +kubebuilder:default="0.0.0.0/0"
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// TrafficMirrorSessionSpec mirrors the traffic of an Ec2Instance to an inspection appliance.
+// +kubebuilder:validation:XValidation:rule="self.sourceEc2InstanceRef == oldSelf.sourceEc2InstanceRef && self.targetENIRef == oldSelf.targetENIRef",message="source and target cannot be changed; create a new session instead"
+type TrafficMirrorSessionSpec struct {
+	// SourceEc2InstanceRef is the Ec2Instance (in the same namespace) whose primary network interface is mirrored.
+	SourceEc2InstanceRef corev1.LocalObjectReference `json:"sourceEc2InstanceRef"`
+
+	// TargetENIRef is the ID of the network interface (eni-...) of the appliance that receives the
+	// mirrored traffic. It must be in the same VPC as the source instance.
+	TargetENIRef string `json:"targetENIRef"`
+
+	// FilterRules select the mirrored traffic. Without rules nothing is mirrored.
+	FilterRules []MirrorFilterRule `json:"filterRules,omitempty"`
+
+	// SessionNumber orders sessions that mirror the same network interface; lower numbers win.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32766
+	// +kubebuilder:default=1
+	SessionNumber int32 `json:"sessionNumber,omitempty"`
+
+	// PacketLength is the number of bytes of each packet to mirror. Zero mirrors the whole packet.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PacketLength int32 `json:"packetLength,omitempty"`
+}
+
+// TrafficMirrorSessionStatus records the AWS resources that make up the session.
+type TrafficMirrorSessionStatus struct {
+	TargetID      string   `json:"targetID,omitempty"`
+	FilterID      string   `json:"filterID,omitempty"`
+	FilterRuleIDs []string `json:"filterRuleIDs,omitempty"`
+	SessionID     string   `json:"sessionID,omitempty"`
+
+	// Message explains why the session could not be set up.
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation whose filter rules and session settings are applied.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.sourceEc2InstanceRef.name"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetENIRef"
+// +kubebuilder:printcolumn:name="SessionID",type="string",JSONPath=".status.sessionID"
+// TrafficMirrorSession is the Schema for the trafficmirrorsessions API.
+
+type TrafficMirrorSession struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TrafficMirrorSessionSpec   `json:"spec,omitempty"`
+	Status TrafficMirrorSessionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TrafficMirrorSessionList contains a list of TrafficMirrorSession.
+type TrafficMirrorSessionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TrafficMirrorSession `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TrafficMirrorSession{}, &TrafficMirrorSessionList{})
+}
@@ -0,0 +1,87 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TGWRoute is a static route of a transit gateway route table.
+// +kubebuilder:validation:XValidation:rule="has(self.attachmentRef) != (has(self.blackhole) && self.blackhole)",message="a route needs either attachmentRef or blackhole"
+type TGWRoute struct {
+	// DestinationCIDR is the IPv4 or IPv6 CIDR the route matches.
+	// +kubebuilder:validation:MinLength=1
+	DestinationCIDR string `json:"destinationCIDR"`
+
+	// AttachmentRef is the ID of the transit gateway attachment (tgw-attach-...) traffic is sent to.
+	// +optional
+	AttachmentRef string `json:"attachmentRef,omitempty"`
+
+	// Blackhole drops traffic to the destination instead of forwarding it.
+	// +optional
+	Blackhole bool `json:"blackhole,omitempty"`
+}
+
+// PropagationRef is an attachment whose routes are propagated into the route table.
+type PropagationRef struct {
+	// AttachmentRef is the ID of the transit gateway attachment (tgw-attach-...).
+	// +kubebuilder:validation:MinLength=1
+	AttachmentRef string `json:"attachmentRef"`
+}
+
+// TransitGatewayRouteTableSpec describes a route table of an existing transit gateway.
+// Routes and propagations are kept in sync with AWS; static routes that are not listed are removed.
+// +kubebuilder:validation:XValidation:rule="self.region == oldSelf.region && self.transitGatewayRef == oldSelf.transitGatewayRef",message="region and transitGatewayRef cannot be changed; create a new route table instead"
+type TransitGatewayRouteTableSpec struct {
+	Region string `json:"region"`
+
+	// TransitGatewayRef is the ID of the transit gateway (tgw-...) the route table belongs to.
+	// +kubebuilder:validation:MinLength=1
+	TransitGatewayRef string `json:"transitGatewayRef"`
+
+	// +listType=map
+	// +listMapKey=destinationCIDR
+	// +optional
+	Routes []TGWRoute `json:"routes,omitempty"`
+
+	// +listType=map
+	// +listMapKey=attachmentRef
+	// +optional
+	Propagations []PropagationRef `json:"propagations,omitempty"`
+}
+
+// TransitGatewayRouteTableStatus is the observed state of the route table in AWS.
+type TransitGatewayRouteTableStatus struct {
+	RouteTableID string `json:"routeTableID,omitempty"`
+	State        string `json:"state,omitempty"`
+
+	// Message explains why the routes or propagations could not be applied.
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="TransitGateway",type="string",JSONPath=".spec.transitGatewayRef"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="RouteTableID",type="string",JSONPath=".status.routeTableID"
+// TransitGatewayRouteTable is the Schema for the transitgatewayroutetables API.
+// It controls how traffic is routed between the attachments of a transit gateway.
+
+type TransitGatewayRouteTable struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TransitGatewayRouteTableSpec   `json:"spec,omitempty"`
+	Status TransitGatewayRouteTableStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TransitGatewayRouteTableList contains a list of TransitGatewayRouteTable.
+type TransitGatewayRouteTableList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TransitGatewayRouteTable `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TransitGatewayRouteTable{}, &TransitGatewayRouteTableList{})
+}
@@ -0,0 +1,99 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VpcEndpointType is the kind of VPC endpoint.
+// +kubebuilder:validation:Enum=Interface;Gateway
+type VpcEndpointType string
+
+const (
+	// VpcEndpointTypeInterface places network interfaces in the subnets (AWS PrivateLink).
+	VpcEndpointTypeInterface VpcEndpointType = "Interface"
+	// VpcEndpointTypeGateway routes traffic through route table entries; only S3 and DynamoDB support it.
+	VpcEndpointTypeGateway VpcEndpointType = "Gateway"
+)
+
+// VpcEndpointSpec describes a VPC endpoint that gives a private VPC access to an AWS service.
+// The subnets, security groups and private DNS setting can be changed later; the rest is fixed.
+// +kubebuilder:validation:XValidation:rule="self.region == oldSelf.region && self.serviceName == oldSelf.serviceName && self.vpcRef == oldSelf.vpcRef && self.type == oldSelf.type",message="region, serviceName, vpcRef and type cannot be changed; create a new endpoint instead"
+// +kubebuilder:validation:XValidation:rule="self.type == 'Interface' || (!has(self.subnetRefs) && !has(self.securityGroupRefs) && !(has(self.privateDNSEnabled) && self.privateDNSEnabled))",message="subnetRefs, securityGroupRefs and privateDNSEnabled only apply to Interface endpoints"
+type VpcEndpointSpec struct {
+	Region string `json:"region"`
+
+	// ServiceName is the AWS service to connect to, e.g. com.amazonaws.us-east-1.s3.
+	// +kubebuilder:validation:MinLength=1
+	ServiceName string `json:"serviceName"`
+
+	// VpcRef is the ID of the VPC (vpc-...) the endpoint is created in.
+	// +kubebuilder:validation:MinLength=1
+	VpcRef string `json:"vpcRef"`
+
+	// +kubebuilder:default=Interface
+	Type VpcEndpointType `json:"type,omitempty"`
+
+	// SubnetRefs are the subnets (by ID, e.g. subnet-...) an Interface endpoint gets a network
+	// interface in, at most one per availability zone.
+	// +optional
+	SubnetRefs []corev1.LocalObjectReference `json:"subnetRefs,omitempty"`
+
+	// SecurityGroupRefs are the security groups (by ID, e.g. sg-...) of the endpoint network
+	// interfaces. Without them the default security group of the VPC is used.
+	// +optional
+	SecurityGroupRefs []corev1.LocalObjectReference `json:"securityGroupRefs,omitempty"`
+
+	// PrivateDNSEnabled makes the default DNS name of the service resolve to the endpoint inside the VPC.
+	// +optional
+	PrivateDNSEnabled bool `json:"privateDNSEnabled,omitempty"`
+}
+
+// VpcEndpointDNSEntry is a DNS name that resolves to the endpoint.
+type VpcEndpointDNSEntry struct {
+	DNSName      string `json:"dnsName"`
+	HostedZoneID string `json:"hostedZoneID,omitempty"`
+}
+
+// VpcEndpointStatus is the observed state of the endpoint in AWS.
+type VpcEndpointStatus struct {
+	EndpointID string `json:"endpointID,omitempty"`
+	State      string `json:"state,omitempty"`
+
+	// DNSEntries are the DNS names of an Interface endpoint.
+	// +optional
+	DNSEntries []VpcEndpointDNSEntry `json:"dnsEntries,omitempty"`
+
+	// ObservedGeneration is the generation whose subnets, security groups and DNS setting are applied.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Service",type="string",JSONPath=".spec.serviceName"
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="EndpointID",type="string",JSONPath=".status.endpointID"
+// VpcEndpoint is the Schema for the vpcendpoints API.
+// It connects a VPC to an AWS service without going through the internet.
+
+type VpcEndpoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VpcEndpointSpec   `json:"spec,omitempty"`
+	Status VpcEndpointStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VpcEndpointList contains a list of VpcEndpoint.
+type VpcEndpointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VpcEndpoint `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VpcEndpoint{}, &VpcEndpointList{})
+}
@@ -21,42 +21,2902 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMI) DeepCopyInto(out *AMI) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMI.
+func (in *AMI) DeepCopy() *AMI {
+	if in == nil {
+		return nil
+	}
+	out := new(AMI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AMI) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMIList) DeepCopyInto(out *AMIList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AMI, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMIList.
+func (in *AMIList) DeepCopy() *AMIList {
+	if in == nil {
+		return nil
+	}
+	out := new(AMIList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AMIList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMISpec) DeepCopyInto(out *AMISpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMISpec.
+func (in *AMISpec) DeepCopy() *AMISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AMISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMIStatus) DeepCopyInto(out *AMIStatus) {
+	*out = *in
+	if in.BackingSnapshotIDs != nil {
+		in, out := &in.BackingSnapshotIDs, &out.BackingSnapshotIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMIStatus.
+func (in *AMIStatus) DeepCopy() *AMIStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AMIStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoRollbackSpec) DeepCopyInto(out *AutoRollbackSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRollbackSpec.
+func (in *AutoRollbackSpec) DeepCopy() *AutoRollbackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoRollbackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUOptions) DeepCopyInto(out *CPUOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUOptions.
+func (in *CPUOptions) DeepCopy() *CPUOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservation) DeepCopyInto(out *CapacityReservation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservation.
+func (in *CapacityReservation) DeepCopy() *CapacityReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CapacityReservation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservationAlert) DeepCopyInto(out *CapacityReservationAlert) {
+	*out = *in
+	in.RaisedAt.DeepCopyInto(&out.RaisedAt)
+	if in.ResolvedAt != nil {
+		in, out := &in.ResolvedAt, &out.ResolvedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservationAlert.
+func (in *CapacityReservationAlert) DeepCopy() *CapacityReservationAlert {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservationAlert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservationList) DeepCopyInto(out *CapacityReservationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CapacityReservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservationList.
+func (in *CapacityReservationList) DeepCopy() *CapacityReservationList {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CapacityReservationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservationSpec) DeepCopyInto(out *CapacityReservationSpec) {
+	*out = *in
+	if in.EndDate != nil {
+		in, out := &in.EndDate, &out.EndDate
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservationSpec.
+func (in *CapacityReservationSpec) DeepCopy() *CapacityReservationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservationStatus) DeepCopyInto(out *CapacityReservationStatus) {
+	*out = *in
+	if in.AlertHistory != nil {
+		in, out := &in.AlertHistory, &out.AlertHistory
+		*out = make([]CapacityReservationAlert, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservationStatus.
+func (in *CapacityReservationStatus) DeepCopy() *CapacityReservationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFormationMigration) DeepCopyInto(out *CloudFormationMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFormationMigration.
+func (in *CloudFormationMigration) DeepCopy() *CloudFormationMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFormationMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudFormationMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFormationMigrationList) DeepCopyInto(out *CloudFormationMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudFormationMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFormationMigrationList.
+func (in *CloudFormationMigrationList) DeepCopy() *CloudFormationMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFormationMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudFormationMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFormationMigrationSpec) DeepCopyInto(out *CloudFormationMigrationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFormationMigrationSpec.
+func (in *CloudFormationMigrationSpec) DeepCopy() *CloudFormationMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFormationMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFormationMigrationStatus) DeepCopyInto(out *CloudFormationMigrationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFormationMigrationStatus.
+func (in *CloudFormationMigrationStatus) DeepCopy() *CloudFormationMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFormationMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudWatchAlarmSpec) DeepCopyInto(out *CloudWatchAlarmSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudWatchAlarmSpec.
+func (in *CloudWatchAlarmSpec) DeepCopy() *CloudWatchAlarmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudWatchAlarmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterInventory) DeepCopyInto(out *ClusterInventory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInventory.
+func (in *ClusterInventory) DeepCopy() *ClusterInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterInventory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterInventoryList) DeepCopyInto(out *ClusterInventoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInventoryList.
+func (in *ClusterInventoryList) DeepCopy() *ClusterInventoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterInventoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterInventoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterInventorySpec) DeepCopyInto(out *ClusterInventorySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInventorySpec.
+func (in *ClusterInventorySpec) DeepCopy() *ClusterInventorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterInventorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterInventoryStatus) DeepCopyInto(out *ClusterInventoryStatus) {
+	*out = *in
+	if in.ByRegion != nil {
+		in, out := &in.ByRegion, &out.ByRegion
+		*out = make(map[string]InventoryBreakdown, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ByNamespace != nil {
+		in, out := &in.ByNamespace, &out.ByNamespace
+		*out = make(map[string]InventoryBreakdown, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInventoryStatus.
+func (in *ClusterInventoryStatus) DeepCopy() *ClusterInventoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterInventoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostAllocationReport) DeepCopyInto(out *CostAllocationReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostAllocationReport.
+func (in *CostAllocationReport) DeepCopy() *CostAllocationReport {
+	if in == nil {
+		return nil
+	}
+	out := new(CostAllocationReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CostAllocationReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostAllocationReportList) DeepCopyInto(out *CostAllocationReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CostAllocationReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostAllocationReportList.
+func (in *CostAllocationReportList) DeepCopy() *CostAllocationReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(CostAllocationReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CostAllocationReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostAllocationReportSpec) DeepCopyInto(out *CostAllocationReportSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostAllocationReportSpec.
+func (in *CostAllocationReportSpec) DeepCopy() *CostAllocationReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CostAllocationReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostAllocationReportStatus) DeepCopyInto(out *CostAllocationReportStatus) {
+	*out = *in
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]CostEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostAllocationReportStatus.
+func (in *CostAllocationReportStatus) DeepCopy() *CostAllocationReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CostAllocationReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostAnomalySpec) DeepCopyInto(out *CostAnomalySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostAnomalySpec.
+func (in *CostAnomalySpec) DeepCopy() *CostAnomalySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CostAnomalySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEntry) DeepCopyInto(out *CostEntry) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEntry.
+func (in *CostEntry) DeepCopy() *CostEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CreatedInstanceInfo) DeepCopyInto(out *CreatedInstanceInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CreatedInstanceInfo.
+func (in *CreatedInstanceInfo) DeepCopy() *CreatedInstanceInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(CreatedInstanceInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSRecordSpec) DeepCopyInto(out *DNSRecordSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecordSpec.
+func (in *DNSRecordSpec) DeepCopy() *DNSRecordSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSRecordSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecommissionProgress) DeepCopyInto(out *DecommissionProgress) {
+	*out = *in
+	if in.CompletedSteps != nil {
+		in, out := &in.CompletedSteps, &out.CompletedSteps
+		*out = make([]DecommissionStepResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StepStartedAt != nil {
+		in, out := &in.StepStartedAt, &out.StepStartedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecommissionProgress.
+func (in *DecommissionProgress) DeepCopy() *DecommissionProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(DecommissionProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecommissionStep) DeepCopyInto(out *DecommissionStep) {
+	*out = *in
+	if in.S3Export != nil {
+		in, out := &in.S3Export, &out.S3Export
+		*out = new(S3ExportStep)
+		**out = **in
+	}
+	if in.SSMCommand != nil {
+		in, out := &in.SSMCommand, &out.SSMCommand
+		*out = new(SSMCommandStep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SNSNotify != nil {
+		in, out := &in.SNSNotify, &out.SNSNotify
+		*out = new(SNSNotifyStep)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecommissionStep.
+func (in *DecommissionStep) DeepCopy() *DecommissionStep {
+	if in == nil {
+		return nil
+	}
+	out := new(DecommissionStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecommissionStepResult) DeepCopyInto(out *DecommissionStepResult) {
+	*out = *in
+	in.CompletedAt.DeepCopyInto(&out.CompletedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecommissionStepResult.
+func (in *DecommissionStepResult) DeepCopy() *DecommissionStepResult {
+	if in == nil {
+		return nil
+	}
+	out := new(DecommissionStepResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecommissionWorkflowSpec) DeepCopyInto(out *DecommissionWorkflowSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]DecommissionStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecommissionWorkflowSpec.
+func (in *DecommissionWorkflowSpec) DeepCopy() *DecommissionWorkflowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DecommissionWorkflowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EBSVolumeSpec) DeepCopyInto(out *EBSVolumeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EBSVolumeSpec.
+func (in *EBSVolumeSpec) DeepCopy() *EBSVolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EBSVolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EKSClusterReference) DeepCopyInto(out *EKSClusterReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSClusterReference.
+func (in *EKSClusterReference) DeepCopy() *EKSClusterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(EKSClusterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ENAExpressStatus) DeepCopyInto(out *ENAExpressStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ENAExpressStatus.
+func (in *ENAExpressStatus) DeepCopy() *ENAExpressStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ENAExpressStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2Instance) DeepCopyInto(out *Ec2Instance) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2Instance.
+func (in *Ec2Instance) DeepCopy() *Ec2Instance {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2Instance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2Instance) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2InstanceList) DeepCopyInto(out *Ec2InstanceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Ec2Instance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2InstanceList.
+func (in *Ec2InstanceList) DeepCopy() *Ec2InstanceList {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2InstanceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2InstanceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2InstanceSet) DeepCopyInto(out *Ec2InstanceSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2InstanceSet.
+func (in *Ec2InstanceSet) DeepCopy() *Ec2InstanceSet {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2InstanceSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2InstanceSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2InstanceSetList) DeepCopyInto(out *Ec2InstanceSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Ec2InstanceSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2InstanceSetList.
+func (in *Ec2InstanceSetList) DeepCopy() *Ec2InstanceSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2InstanceSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2InstanceSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2InstanceSetSpec) DeepCopyInto(out *Ec2InstanceSetSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	out.AutoRollback = in.AutoRollback
+	if in.SpotCapacityPools != nil {
+		in, out := &in.SpotCapacityPools, &out.SpotCapacityPools
+		*out = make([]SpotCapacityPool, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2InstanceSetSpec.
+func (in *Ec2InstanceSetSpec) DeepCopy() *Ec2InstanceSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2InstanceSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2InstanceSetStatus) DeepCopyInto(out *Ec2InstanceSetStatus) {
+	*out = *in
+	if in.StableTemplate != nil {
+		in, out := &in.StableTemplate, &out.StableTemplate
+		*out = new(Ec2InstanceTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheckFailures != nil {
+		in, out := &in.HealthCheckFailures, &out.HealthCheckFailures
+		*out = make([]InstanceHealthFailures, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PoolBreakdown != nil {
+		in, out := &in.PoolBreakdown, &out.PoolBreakdown
+		*out = make(map[string]PoolStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2InstanceSetStatus.
+func (in *Ec2InstanceSetStatus) DeepCopy() *Ec2InstanceSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2InstanceSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2InstanceSpec) DeepCopyInto(out *Ec2InstanceSpec) {
+	*out = *in
+	if in.LaunchTemplateRef != nil {
+		in, out := &in.LaunchTemplateRef, &out.LaunchTemplateRef
+		*out = new(LaunchTemplateRef)
+		**out = **in
+	}
+	if in.PlacementGroup != nil {
+		in, out := &in.PlacementGroup, &out.PlacementGroup
+		*out = new(PlacementGroupSpec)
+		**out = **in
+	}
+	if in.KeyPairRef != nil {
+		in, out := &in.KeyPairRef, &out.KeyPairRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroupRefs != nil {
+		in, out := &in.SecurityGroupRefs, &out.SecurityGroupRefs
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = make([]NetworkInterfaceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(UserDataSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.EncryptRootVolume != nil {
+		in, out := &in.EncryptRootVolume, &out.EncryptRootVolume
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EBSVolumes != nil {
+		in, out := &in.EBSVolumes, &out.EBSVolumes
+		*out = make([]EBSVolumeSpec, len(*in))
+		copy(*out, *in)
+	}
+	out.ElasticIP = in.ElasticIP
+	if in.MetadataOptions != nil {
+		in, out := &in.MetadataOptions, &out.MetadataOptions
+		*out = new(MetadataOptionsSpec)
+		**out = **in
+	}
+	if in.DeletionTimeout != nil {
+		in, out := &in.DeletionTimeout, &out.DeletionTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	out.CostAnomalyDetection = in.CostAnomalyDetection
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(DNSRecordSpec)
+		**out = **in
+	}
+	if in.CloudWatchAlarms != nil {
+		in, out := &in.CloudWatchAlarms, &out.CloudWatchAlarms
+		*out = make([]CloudWatchAlarmSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageBuilderComponents != nil {
+		in, out := &in.ImageBuilderComponents, &out.ImageBuilderComponents
+		*out = make([]ImageBuilderComponentRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(InstanceResources)
+		**out = **in
+	}
+	if in.SnapshotSchedule != nil {
+		in, out := &in.SnapshotSchedule, &out.SnapshotSchedule
+		*out = new(SnapshotScheduleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EKSClusterRef != nil {
+		in, out := &in.EKSClusterRef, &out.EKSClusterRef
+		*out = new(EKSClusterReference)
+		**out = **in
+	}
+	if in.Route53HealthCheck != nil {
+		in, out := &in.Route53HealthCheck, &out.Route53HealthCheck
+		*out = new(Route53HealthCheckSpec)
+		**out = **in
+	}
+	out.NitroEnclave = in.NitroEnclave
+	out.CPUOptions = in.CPUOptions
+	if in.SourceDestCheck != nil {
+		in, out := &in.SourceDestCheck, &out.SourceDestCheck
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(InstanceScheduleSpec)
+		**out = **in
+	}
+	out.SpotOptions = in.SpotOptions
+	in.DecommissionWorkflow.DeepCopyInto(&out.DecommissionWorkflow)
+	in.PatchManagement.DeepCopyInto(&out.PatchManagement)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2InstanceSpec.
+func (in *Ec2InstanceSpec) DeepCopy() *Ec2InstanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2InstanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2InstanceStatus) DeepCopyInto(out *Ec2InstanceStatus) {
+	*out = *in
+	if in.LaunchTime != nil {
+		in, out := &in.LaunchTime, &out.LaunchTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EBSVolumeIDs != nil {
+		in, out := &in.EBSVolumeIDs, &out.EBSVolumeIDs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CloudWatchAlarmNames != nil {
+		in, out := &in.CloudWatchAlarmNames, &out.CloudWatchAlarmNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TagPolicyCompliance != nil {
+		in, out := &in.TagPolicyCompliance, &out.TagPolicyCompliance
+		*out = new(TagComplianceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConsoleOutputSecretRef != nil {
+		in, out := &in.ConsoleOutputSecretRef, &out.ConsoleOutputSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ScreenshotSecretRef != nil {
+		in, out := &in.ScreenshotSecretRef, &out.ScreenshotSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ScreenshotCapturedAt != nil {
+		in, out := &in.ScreenshotCapturedAt, &out.ScreenshotCapturedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.SnapshotHistory != nil {
+		in, out := &in.SnapshotHistory, &out.SnapshotHistory
+		*out = make([]SnapshotRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReplicatedSnapshots != nil {
+		in, out := &in.ReplicatedSnapshots, &out.ReplicatedSnapshots
+		*out = make(map[string][]SnapshotRef, len(*in))
+		for key, val := range *in {
+			var outVal []SnapshotRef
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]SnapshotRef, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.HealthCheckLastChecked != nil {
+		in, out := &in.HealthCheckLastChecked, &out.HealthCheckLastChecked
+		*out = (*in).DeepCopy()
+	}
+	if in.CPUOptions != nil {
+		in, out := &in.CPUOptions, &out.CPUOptions
+		*out = new(CPUOptions)
+		**out = **in
+	}
+	if in.ENAExpress != nil {
+		in, out := &in.ENAExpress, &out.ENAExpress
+		*out = new(ENAExpressStatus)
+		**out = **in
+	}
+	if in.AppliedSecurityGroupIDs != nil {
+		in, out := &in.AppliedSecurityGroupIDs, &out.AppliedSecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedSecurityGroupIDs != nil {
+		in, out := &in.ManagedSecurityGroupIDs, &out.ManagedSecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedTagKeys != nil {
+		in, out := &in.ManagedTagKeys, &out.ManagedTagKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastTagSyncTime != nil {
+		in, out := &in.LastTagSyncTime, &out.LastTagSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DecommissionProgress != nil {
+		in, out := &in.DecommissionProgress, &out.DecommissionProgress
+		*out = new(DecommissionProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PatchRegistration != nil {
+		in, out := &in.PatchRegistration, &out.PatchRegistration
+		*out = new(PatchRegistration)
+		**out = **in
+	}
+	if in.LastPatchedAt != nil {
+		in, out := &in.LastPatchedAt, &out.LastPatchedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduledAction != nil {
+		in, out := &in.LastScheduledAction, &out.LastScheduledAction
+		*out = (*in).DeepCopy()
+	}
+	if in.LastHibernated != nil {
+		in, out := &in.LastHibernated, &out.LastHibernated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2InstanceStatus.
+func (in *Ec2InstanceStatus) DeepCopy() *Ec2InstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2InstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2InstanceTemplate) DeepCopyInto(out *Ec2InstanceTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2InstanceTemplate.
+func (in *Ec2InstanceTemplate) DeepCopy() *Ec2InstanceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2InstanceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2KeyPair) DeepCopyInto(out *Ec2KeyPair) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2KeyPair.
+func (in *Ec2KeyPair) DeepCopy() *Ec2KeyPair {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2KeyPair)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2KeyPair) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2KeyPairList) DeepCopyInto(out *Ec2KeyPairList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Ec2KeyPair, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2KeyPairList.
+func (in *Ec2KeyPairList) DeepCopy() *Ec2KeyPairList {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2KeyPairList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2KeyPairList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2KeyPairSpec) DeepCopyInto(out *Ec2KeyPairSpec) {
+	*out = *in
+	out.PrivateKeySecretRef = in.PrivateKeySecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2KeyPairSpec.
+func (in *Ec2KeyPairSpec) DeepCopy() *Ec2KeyPairSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2KeyPairSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2KeyPairStatus) DeepCopyInto(out *Ec2KeyPairStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2KeyPairStatus.
+func (in *Ec2KeyPairStatus) DeepCopy() *Ec2KeyPairStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2KeyPairStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2LaunchTemplate) DeepCopyInto(out *Ec2LaunchTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2LaunchTemplate.
+func (in *Ec2LaunchTemplate) DeepCopy() *Ec2LaunchTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2LaunchTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2LaunchTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2LaunchTemplateList) DeepCopyInto(out *Ec2LaunchTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Ec2LaunchTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2LaunchTemplateList.
+func (in *Ec2LaunchTemplateList) DeepCopy() *Ec2LaunchTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2LaunchTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2LaunchTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2LaunchTemplateSpec) DeepCopyInto(out *Ec2LaunchTemplateSpec) {
+	*out = *in
+	in.LaunchTemplateData.DeepCopyInto(&out.LaunchTemplateData)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2LaunchTemplateSpec.
+func (in *Ec2LaunchTemplateSpec) DeepCopy() *Ec2LaunchTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2LaunchTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2LaunchTemplateStatus) DeepCopyInto(out *Ec2LaunchTemplateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2LaunchTemplateStatus.
+func (in *Ec2LaunchTemplateStatus) DeepCopy() *Ec2LaunchTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2LaunchTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2OperatorConfig) DeepCopyInto(out *Ec2OperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2OperatorConfig.
+func (in *Ec2OperatorConfig) DeepCopy() *Ec2OperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2OperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2OperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2OperatorConfigList) DeepCopyInto(out *Ec2OperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Ec2OperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2OperatorConfigList.
+func (in *Ec2OperatorConfigList) DeepCopy() *Ec2OperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2OperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2OperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2OperatorConfigSpec) DeepCopyInto(out *Ec2OperatorConfigSpec) {
+	*out = *in
+	if in.RequiredTags != nil {
+		in, out := &in.RequiredTags, &out.RequiredTags
+		*out = make([]RequiredTag, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.InventoryExport = in.InventoryExport
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2OperatorConfigSpec.
+func (in *Ec2OperatorConfigSpec) DeepCopy() *Ec2OperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2OperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2PlacementGroup) DeepCopyInto(out *Ec2PlacementGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2PlacementGroup.
+func (in *Ec2PlacementGroup) DeepCopy() *Ec2PlacementGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2PlacementGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2PlacementGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2PlacementGroupList) DeepCopyInto(out *Ec2PlacementGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Ec2PlacementGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2PlacementGroupList.
+func (in *Ec2PlacementGroupList) DeepCopy() *Ec2PlacementGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2PlacementGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2PlacementGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2PlacementGroupSpec) DeepCopyInto(out *Ec2PlacementGroupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2PlacementGroupSpec.
+func (in *Ec2PlacementGroupSpec) DeepCopy() *Ec2PlacementGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2PlacementGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2PlacementGroupStatus) DeepCopyInto(out *Ec2PlacementGroupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2PlacementGroupStatus.
+func (in *Ec2PlacementGroupStatus) DeepCopy() *Ec2PlacementGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2PlacementGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2SecurityGroup) DeepCopyInto(out *Ec2SecurityGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2SecurityGroup.
+func (in *Ec2SecurityGroup) DeepCopy() *Ec2SecurityGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2SecurityGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2SecurityGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2SecurityGroupList) DeepCopyInto(out *Ec2SecurityGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Ec2SecurityGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2SecurityGroupList.
+func (in *Ec2SecurityGroupList) DeepCopy() *Ec2SecurityGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2SecurityGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ec2SecurityGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2SecurityGroupSpec) DeepCopyInto(out *Ec2SecurityGroupSpec) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = make([]IngressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]EgressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2SecurityGroupSpec.
+func (in *Ec2SecurityGroupSpec) DeepCopy() *Ec2SecurityGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2SecurityGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ec2SecurityGroupStatus) DeepCopyInto(out *Ec2SecurityGroupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2SecurityGroupStatus.
+func (in *Ec2SecurityGroupStatus) DeepCopy() *Ec2SecurityGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(Ec2SecurityGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressRule) DeepCopyInto(out *EgressRule) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DestinationSecurityGroupIDs != nil {
+		in, out := &in.DestinationSecurityGroupIDs, &out.DestinationSecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressRule.
+func (in *EgressRule) DeepCopy() *EgressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticIPSpec) DeepCopyInto(out *ElasticIPSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticIPSpec.
+func (in *ElasticIPSpec) DeepCopy() *ElasticIPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticIPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageBuilderComponentRef) DeepCopyInto(out *ImageBuilderComponentRef) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBuilderComponentRef.
+func (in *ImageBuilderComponentRef) DeepCopy() *ImageBuilderComponentRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBuilderComponentRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressRule) DeepCopyInto(out *IngressRule) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SourceSecurityGroupIDs != nil {
+		in, out := &in.SourceSecurityGroupIDs, &out.SourceSecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRule.
+func (in *IngressRule) DeepCopy() *IngressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceHealthFailures) DeepCopyInto(out *InstanceHealthFailures) {
+	*out = *in
+	if in.LastChecked != nil {
+		in, out := &in.LastChecked, &out.LastChecked
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceHealthFailures.
+func (in *InstanceHealthFailures) DeepCopy() *InstanceHealthFailures {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceHealthFailures)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceResources) DeepCopyInto(out *InstanceResources) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceResources.
+func (in *InstanceResources) DeepCopy() *InstanceResources {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceScheduleSpec) DeepCopyInto(out *InstanceScheduleSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceScheduleSpec.
+func (in *InstanceScheduleSpec) DeepCopy() *InstanceScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryBreakdown) DeepCopyInto(out *InventoryBreakdown) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryBreakdown.
+func (in *InventoryBreakdown) DeepCopy() *InventoryBreakdown {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryBreakdown)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryExportSpec) DeepCopyInto(out *InventoryExportSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryExportSpec.
+func (in *InventoryExportSpec) DeepCopy() *InventoryExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchTemplateDataSpec) DeepCopyInto(out *LaunchTemplateDataSpec) {
+	*out = *in
+	if in.SecurityGroupIDs != nil {
+		in, out := &in.SecurityGroupIDs, &out.SecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MetadataOptions != nil {
+		in, out := &in.MetadataOptions, &out.MetadataOptions
+		*out = new(MetadataOptionsSpec)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchTemplateDataSpec.
+func (in *LaunchTemplateDataSpec) DeepCopy() *LaunchTemplateDataSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchTemplateDataSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchTemplateRef) DeepCopyInto(out *LaunchTemplateRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchTemplateRef.
+func (in *LaunchTemplateRef) DeepCopy() *LaunchTemplateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchTemplateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaintenanceWindow) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowList) DeepCopyInto(out *MaintenanceWindowList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowList.
+func (in *MaintenanceWindowList) DeepCopy() *MaintenanceWindowList {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaintenanceWindowList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowSpec) DeepCopyInto(out *MaintenanceWindowSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowSpec.
+func (in *MaintenanceWindowSpec) DeepCopy() *MaintenanceWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowStatus) DeepCopyInto(out *MaintenanceWindowStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowStatus.
+func (in *MaintenanceWindowStatus) DeepCopy() *MaintenanceWindowStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataOptionsSpec) DeepCopyInto(out *MetadataOptionsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataOptionsSpec.
+func (in *MetadataOptionsSpec) DeepCopy() *MetadataOptionsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataOptionsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirrorFilterRule) DeepCopyInto(out *MirrorFilterRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorFilterRule.
+func (in *MirrorFilterRule) DeepCopy() *MirrorFilterRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MirrorFilterRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceConfig) DeepCopyInto(out *NamespaceConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceConfig.
+func (in *NamespaceConfig) DeepCopy() *NamespaceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceConfigList) DeepCopyInto(out *NamespaceConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespaceConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceConfigList.
+func (in *NamespaceConfigList) DeepCopy() *NamespaceConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceConfigSpec) DeepCopyInto(out *NamespaceConfigSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceConfigSpec.
+func (in *NamespaceConfigSpec) DeepCopy() *NamespaceConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceConfigStatus) DeepCopyInto(out *NamespaceConfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceConfigStatus.
+func (in *NamespaceConfigStatus) DeepCopy() *NamespaceConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterfaceSpec) DeepCopyInto(out *NetworkInterfaceSpec) {
+	*out = *in
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrivateIPAddresses != nil {
+		in, out := &in.PrivateIPAddresses, &out.PrivateIPAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SourceDestCheck != nil {
+		in, out := &in.SourceDestCheck, &out.SourceDestCheck
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterfaceSpec.
+func (in *NetworkInterfaceSpec) DeepCopy() *NetworkInterfaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterfaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLatencyProbe) DeepCopyInto(out *NetworkLatencyProbe) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLatencyProbe.
+func (in *NetworkLatencyProbe) DeepCopy() *NetworkLatencyProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLatencyProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkLatencyProbe) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLatencyProbeList) DeepCopyInto(out *NetworkLatencyProbeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NetworkLatencyProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLatencyProbeList.
+func (in *NetworkLatencyProbeList) DeepCopy() *NetworkLatencyProbeList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLatencyProbeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkLatencyProbeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLatencyProbeSpec) DeepCopyInto(out *NetworkLatencyProbeSpec) {
+	*out = *in
+	out.SourceEc2InstanceRef = in.SourceEc2InstanceRef
+	out.TargetEc2InstanceRef = in.TargetEc2InstanceRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLatencyProbeSpec.
+func (in *NetworkLatencyProbeSpec) DeepCopy() *NetworkLatencyProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLatencyProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLatencyProbeStatus) DeepCopyInto(out *NetworkLatencyProbeStatus) {
+	*out = *in
+	if in.Reachable != nil {
+		in, out := &in.Reachable, &out.Reachable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LastMeasuredAt != nil {
+		in, out := &in.LastMeasuredAt, &out.LastMeasuredAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLatencyProbeStatus.
+func (in *NetworkLatencyProbeStatus) DeepCopy() *NetworkLatencyProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLatencyProbeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NitroEnclaveSpec) DeepCopyInto(out *NitroEnclaveSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NitroEnclaveSpec.
+func (in *NitroEnclaveSpec) DeepCopy() *NitroEnclaveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NitroEnclaveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchManagementSpec) DeepCopyInto(out *PatchManagementSpec) {
+	*out = *in
+	if in.MaintenanceWindowRef != nil {
+		in, out := &in.MaintenanceWindowRef, &out.MaintenanceWindowRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchManagementSpec.
+func (in *PatchManagementSpec) DeepCopy() *PatchManagementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchManagementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchRegistration) DeepCopyInto(out *PatchRegistration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchRegistration.
+func (in *PatchRegistration) DeepCopy() *PatchRegistration {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchRegistration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementGroupSpec) DeepCopyInto(out *PlacementGroupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementGroupSpec.
+func (in *PlacementGroupSpec) DeepCopy() *PlacementGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolStatus) DeepCopyInto(out *PoolStatus) {
+	*out = *in
+	if in.LastInterruptionAt != nil {
+		in, out := &in.LastInterruptionAt, &out.LastInterruptionAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolStatus.
+func (in *PoolStatus) DeepCopy() *PoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropagationRef) DeepCopyInto(out *PropagationRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagationRef.
+func (in *PropagationRef) DeepCopy() *PropagationRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PropagationRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoverOrphan) DeepCopyInto(out *RecoverOrphan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoverOrphan.
+func (in *RecoverOrphan) DeepCopy() *RecoverOrphan {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoverOrphan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RecoverOrphan) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoverOrphanList) DeepCopyInto(out *RecoverOrphanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RecoverOrphan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoverOrphanList.
+func (in *RecoverOrphanList) DeepCopy() *RecoverOrphanList {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoverOrphanList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RecoverOrphanList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoverOrphanSpec) DeepCopyInto(out *RecoverOrphanSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoverOrphanSpec.
+func (in *RecoverOrphanSpec) DeepCopy() *RecoverOrphanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoverOrphanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoverOrphanStatus) DeepCopyInto(out *RecoverOrphanStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoverOrphanStatus.
+func (in *RecoverOrphanStatus) DeepCopy() *RecoverOrphanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoverOrphanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegionCredentials) DeepCopyInto(out *RegionCredentials) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionCredentials.
+func (in *RegionCredentials) DeepCopy() *RegionCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(RegionCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RegionCredentials) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegionCredentialsList) DeepCopyInto(out *RegionCredentialsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RegionCredentials, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionCredentialsList.
+func (in *RegionCredentialsList) DeepCopy() *RegionCredentialsList {
+	if in == nil {
+		return nil
+	}
+	out := new(RegionCredentialsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RegionCredentialsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegionCredentialsSpec) DeepCopyInto(out *RegionCredentialsSpec) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretKeyReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionCredentialsSpec.
+func (in *RegionCredentialsSpec) DeepCopy() *RegionCredentialsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RegionCredentialsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegionCredentialsStatus) DeepCopyInto(out *RegionCredentialsStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionCredentialsStatus.
+func (in *RegionCredentialsStatus) DeepCopy() *RegionCredentialsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RegionCredentialsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegionMigration) DeepCopyInto(out *RegionMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionMigration.
+func (in *RegionMigration) DeepCopy() *RegionMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(RegionMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RegionMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegionMigrationList) DeepCopyInto(out *RegionMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RegionMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionMigrationList.
+func (in *RegionMigrationList) DeepCopy() *RegionMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(RegionMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RegionMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegionMigrationSpec) DeepCopyInto(out *RegionMigrationSpec) {
+	*out = *in
+	out.SourceEc2InstanceRef = in.SourceEc2InstanceRef
+	if in.TrafficCutoverRef != nil {
+		in, out := &in.TrafficCutoverRef, &out.TrafficCutoverRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionMigrationSpec.
+func (in *RegionMigrationSpec) DeepCopy() *RegionMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RegionMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegionMigrationStatus) DeepCopyInto(out *RegionMigrationStatus) {
+	*out = *in
+	if in.CutoverTime != nil {
+		in, out := &in.CutoverTime, &out.CutoverTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionMigrationStatus.
+func (in *RegionMigrationStatus) DeepCopy() *RegionMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RegionMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequiredTag) DeepCopyInto(out *RequiredTag) {
+	*out = *in
+	if in.AllowedValues != nil {
+		in, out := &in.AllowedValues, &out.AllowedValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredTag.
+func (in *RequiredTag) DeepCopy() *RequiredTag {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiredTag)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Route53HealthCheckSpec) DeepCopyInto(out *Route53HealthCheckSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route53HealthCheckSpec.
+func (in *Route53HealthCheckSpec) DeepCopy() *Route53HealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Route53HealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3ExportStep) DeepCopyInto(out *S3ExportStep) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3ExportStep.
+func (in *S3ExportStep) DeepCopy() *S3ExportStep {
+	if in == nil {
+		return nil
+	}
+	out := new(S3ExportStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SNSNotifyStep) DeepCopyInto(out *SNSNotifyStep) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SNSNotifyStep.
+func (in *SNSNotifyStep) DeepCopy() *SNSNotifyStep {
+	if in == nil {
+		return nil
+	}
+	out := new(SNSNotifyStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSMCommandStep) DeepCopyInto(out *SSMCommandStep) {
+	*out = *in
+	if in.Commands != nil {
+		in, out := &in.Commands, &out.Commands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSMCommandStep.
+func (in *SSMCommandStep) DeepCopy() *SSMCommandStep {
+	if in == nil {
+		return nil
+	}
+	out := new(SSMCommandStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyReference) DeepCopyInto(out *SecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyReference.
+func (in *SecretKeyReference) DeepCopy() *SecretKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRef) DeepCopyInto(out *SnapshotRef) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRef.
+func (in *SnapshotRef) DeepCopy() *SnapshotRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotReplicationTarget) DeepCopyInto(out *SnapshotReplicationTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotReplicationTarget.
+func (in *SnapshotReplicationTarget) DeepCopy() *SnapshotReplicationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotReplicationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotScheduleSpec) DeepCopyInto(out *SnapshotScheduleSpec) {
+	*out = *in
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = make([]SnapshotReplicationTarget, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotScheduleSpec.
+func (in *SnapshotScheduleSpec) DeepCopy() *SnapshotScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpotCapacityPool) DeepCopyInto(out *SpotCapacityPool) {
+	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotCapacityPool.
+func (in *SpotCapacityPool) DeepCopy() *SpotCapacityPool {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(SpotCapacityPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CreatedInstanceInfo) DeepCopyInto(out *CreatedInstanceInfo) {
+func (in *SpotOptionsSpec) DeepCopyInto(out *SpotOptionsSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CreatedInstanceInfo.
-func (in *CreatedInstanceInfo) DeepCopy() *CreatedInstanceInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotOptionsSpec.
+func (in *SpotOptionsSpec) DeepCopy() *SpotOptionsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CreatedInstanceInfo)
+	out := new(SpotOptionsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Ec2Instance) DeepCopyInto(out *Ec2Instance) {
+func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
+	*out = *in
+	out.RootVolume = in.RootVolume
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = make([]VolumeConfig, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfig.
+func (in *StorageConfig) DeepCopy() *StorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TGWRoute) DeepCopyInto(out *TGWRoute) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TGWRoute.
+func (in *TGWRoute) DeepCopy() *TGWRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(TGWRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TagComplianceStatus) DeepCopyInto(out *TagComplianceStatus) {
+	*out = *in
+	if in.ViolatedKeys != nil {
+		in, out := &in.ViolatedKeys, &out.ViolatedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastEvaluatedAt != nil {
+		in, out := &in.LastEvaluatedAt, &out.LastEvaluatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TagComplianceStatus.
+func (in *TagComplianceStatus) DeepCopy() *TagComplianceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TagComplianceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficMirrorSession) DeepCopyInto(out *TrafficMirrorSession) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -64,18 +2924,18 @@ func (in *Ec2Instance) DeepCopyInto(out *Ec2Instance) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2Instance.
-func (in *Ec2Instance) DeepCopy() *Ec2Instance {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficMirrorSession.
+func (in *TrafficMirrorSession) DeepCopy() *TrafficMirrorSession {
 	if in == nil {
 		return nil
 	}
-	out := new(Ec2Instance)
+	out := new(TrafficMirrorSession)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Ec2Instance) DeepCopyObject() runtime.Object {
+func (in *TrafficMirrorSession) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -83,31 +2943,31 @@ func (in *Ec2Instance) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Ec2InstanceList) DeepCopyInto(out *Ec2InstanceList) {
+func (in *TrafficMirrorSessionList) DeepCopyInto(out *TrafficMirrorSessionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Ec2Instance, len(*in))
+		*out = make([]TrafficMirrorSession, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2InstanceList.
-func (in *Ec2InstanceList) DeepCopy() *Ec2InstanceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficMirrorSessionList.
+func (in *TrafficMirrorSessionList) DeepCopy() *TrafficMirrorSessionList {
 	if in == nil {
 		return nil
 	}
-	out := new(Ec2InstanceList)
+	out := new(TrafficMirrorSessionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Ec2InstanceList) DeepCopyObject() runtime.Object {
+func (in *TrafficMirrorSessionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -115,69 +2975,161 @@ func (in *Ec2InstanceList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Ec2InstanceSpec) DeepCopyInto(out *Ec2InstanceSpec) {
+func (in *TrafficMirrorSessionSpec) DeepCopyInto(out *TrafficMirrorSessionSpec) {
 	*out = *in
-	if in.SecurityGroups != nil {
-		in, out := &in.SecurityGroups, &out.SecurityGroups
+	out.SourceEc2InstanceRef = in.SourceEc2InstanceRef
+	if in.FilterRules != nil {
+		in, out := &in.FilterRules, &out.FilterRules
+		*out = make([]MirrorFilterRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficMirrorSessionSpec.
+func (in *TrafficMirrorSessionSpec) DeepCopy() *TrafficMirrorSessionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficMirrorSessionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficMirrorSessionStatus) DeepCopyInto(out *TrafficMirrorSessionStatus) {
+	*out = *in
+	if in.FilterRuleIDs != nil {
+		in, out := &in.FilterRuleIDs, &out.FilterRuleIDs
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficMirrorSessionStatus.
+func (in *TrafficMirrorSessionStatus) DeepCopy() *TrafficMirrorSessionStatus {
+	if in == nil {
+		return nil
 	}
-	in.Storage.DeepCopyInto(&out.Storage)
+	out := new(TrafficMirrorSessionStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2InstanceSpec.
-func (in *Ec2InstanceSpec) DeepCopy() *Ec2InstanceSpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransitGatewayRouteTable) DeepCopyInto(out *TransitGatewayRouteTable) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransitGatewayRouteTable.
+func (in *TransitGatewayRouteTable) DeepCopy() *TransitGatewayRouteTable {
 	if in == nil {
 		return nil
 	}
-	out := new(Ec2InstanceSpec)
+	out := new(TransitGatewayRouteTable)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TransitGatewayRouteTable) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Ec2InstanceStatus) DeepCopyInto(out *Ec2InstanceStatus) {
+func (in *TransitGatewayRouteTableList) DeepCopyInto(out *TransitGatewayRouteTableList) {
 	*out = *in
-	if in.LaunchTime != nil {
-		in, out := &in.LaunchTime, &out.LaunchTime
-		*out = (*in).DeepCopy()
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TransitGatewayRouteTable, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ec2InstanceStatus.
-func (in *Ec2InstanceStatus) DeepCopy() *Ec2InstanceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransitGatewayRouteTableList.
+func (in *TransitGatewayRouteTableList) DeepCopy() *TransitGatewayRouteTableList {
 	if in == nil {
 		return nil
 	}
-	out := new(Ec2InstanceStatus)
+	out := new(TransitGatewayRouteTableList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TransitGatewayRouteTableList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
+func (in *TransitGatewayRouteTableSpec) DeepCopyInto(out *TransitGatewayRouteTableSpec) {
 	*out = *in
-	out.RootVolume = in.RootVolume
-	if in.AdditionalVolumes != nil {
-		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
-		*out = make([]VolumeConfig, len(*in))
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]TGWRoute, len(*in))
+		copy(*out, *in)
+	}
+	if in.Propagations != nil {
+		in, out := &in.Propagations, &out.Propagations
+		*out = make([]PropagationRef, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfig.
-func (in *StorageConfig) DeepCopy() *StorageConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransitGatewayRouteTableSpec.
+func (in *TransitGatewayRouteTableSpec) DeepCopy() *TransitGatewayRouteTableSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageConfig)
+	out := new(TransitGatewayRouteTableSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransitGatewayRouteTableStatus) DeepCopyInto(out *TransitGatewayRouteTableStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransitGatewayRouteTableStatus.
+func (in *TransitGatewayRouteTableStatus) DeepCopy() *TransitGatewayRouteTableStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TransitGatewayRouteTableStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserDataSpec) DeepCopyInto(out *UserDataSpec) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserDataSpec.
+func (in *UserDataSpec) DeepCopy() *UserDataSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserDataSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -196,3 +3148,122 @@ func (in *VolumeConfig) DeepCopy() *VolumeConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VpcEndpoint) DeepCopyInto(out *VpcEndpoint) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VpcEndpoint.
+func (in *VpcEndpoint) DeepCopy() *VpcEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(VpcEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VpcEndpoint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VpcEndpointDNSEntry) DeepCopyInto(out *VpcEndpointDNSEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VpcEndpointDNSEntry.
+func (in *VpcEndpointDNSEntry) DeepCopy() *VpcEndpointDNSEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(VpcEndpointDNSEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VpcEndpointList) DeepCopyInto(out *VpcEndpointList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VpcEndpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VpcEndpointList.
+func (in *VpcEndpointList) DeepCopy() *VpcEndpointList {
+	if in == nil {
+		return nil
+	}
+	out := new(VpcEndpointList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VpcEndpointList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VpcEndpointSpec) DeepCopyInto(out *VpcEndpointSpec) {
+	*out = *in
+	if in.SubnetRefs != nil {
+		in, out := &in.SubnetRefs, &out.SubnetRefs
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroupRefs != nil {
+		in, out := &in.SecurityGroupRefs, &out.SecurityGroupRefs
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VpcEndpointSpec.
+func (in *VpcEndpointSpec) DeepCopy() *VpcEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VpcEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VpcEndpointStatus) DeepCopyInto(out *VpcEndpointStatus) {
+	*out = *in
+	if in.DNSEntries != nil {
+		in, out := &in.DNSEntries, &out.DNSEntries
+		*out = make([]VpcEndpointDNSEntry, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VpcEndpointStatus.
+func (in *VpcEndpointStatus) DeepCopy() *VpcEndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VpcEndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,156 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// ConversionDataAnnotation holds the v1 fields v1alpha1 has no place for, as JSON, so that reading
+// an object as v1alpha1 and writing it back does not drop them.
+const ConversionDataAnnotation = "compute.cloud.com/conversion-data"
+
+// hubFieldNames maps the JSON names of v1alpha1 fields to their v1 names where the two differ.
+var hubFieldNames = map[string]string{
+	"amiID": "amiId",
+}
+
+// conversionData is the content of ConversionDataAnnotation.
+type conversionData struct {
+	Spec   map[string]json.RawMessage `json:"spec,omitempty"`
+	Status map[string]json.RawMessage `json:"status,omitempty"`
+}
+
+// ConvertTo converts this Ec2Instance to the v1 Hub, restoring the fields kept in
+// ConversionDataAnnotation.
+func (src *Ec2Instance) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*computev1.Ec2Instance)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	var data conversionData
+	if raw, ok := dst.Annotations[ConversionDataAnnotation]; ok {
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			return fmt.Errorf("failed to read annotation %s: %w", ConversionDataAnnotation, err)
+		}
+		delete(dst.Annotations, ConversionDataAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+	if err := mergeHubFields(data.Spec, src.Spec, &dst.Spec); err != nil {
+		return fmt.Errorf("failed to convert spec: %w", err)
+	}
+	if err := mergeHubFields(data.Status, src.Status, &dst.Status); err != nil {
+		return fmt.Errorf("failed to convert status: %w", err)
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1 Hub to this Ec2Instance, keeping the fields v1alpha1 does not have in
+// ConversionDataAnnotation.
+func (dst *Ec2Instance) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*computev1.Ec2Instance)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	var data conversionData
+	var err error
+	if data.Spec, err = splitHubFields(src.Spec, &dst.Spec); err != nil {
+		return fmt.Errorf("failed to convert spec: %w", err)
+	}
+	if data.Status, err = splitHubFields(src.Status, &dst.Status); err != nil {
+		return fmt.Errorf("failed to convert status: %w", err)
+	}
+	if len(data.Spec) == 0 && len(data.Status) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[ConversionDataAnnotation] = string(raw)
+	return nil
+}
+
+// jsonFieldNames returns the JSON names of the fields of a struct type, from their struct tags.
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// hubFieldName returns the v1 name of a v1alpha1 field.
+func hubFieldName(name string) string {
+	if hubName, ok := hubFieldNames[name]; ok {
+		return hubName
+	}
+	return name
+}
+
+// jsonFields returns the JSON fields of v.
+func jsonFields(v any) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	return fields, json.Unmarshal(raw, &fields)
+}
+
+// mergeHubFields fills hub from the fields of spoke, renamed to their v1 names, on top of the saved
+// v1 fields. A spoke field that is empty also clears the saved value, so clearing it through
+// v1alpha1 sticks.
+func mergeHubFields(saved map[string]json.RawMessage, spoke, hub any) error {
+	fields := map[string]json.RawMessage{}
+	for name, value := range saved {
+		fields[name] = value
+	}
+	for _, name := range jsonFieldNames(reflect.TypeOf(spoke)) {
+		delete(fields, hubFieldName(name))
+	}
+	spokeFields, err := jsonFields(spoke)
+	if err != nil {
+		return err
+	}
+	for name, value := range spokeFields {
+		fields[hubFieldName(name)] = value
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, hub)
+}
+
+// splitHubFields fills spoke with the fields of hub it has, by their v1 names, and returns the
+// remaining ones.
+func splitHubFields(hub, spoke any) (map[string]json.RawMessage, error) {
+	fields, err := jsonFields(hub)
+	if err != nil {
+		return nil, err
+	}
+	spokeFields := map[string]json.RawMessage{}
+	for _, name := range jsonFieldNames(reflect.TypeOf(spoke).Elem()) {
+		if value, ok := fields[hubFieldName(name)]; ok {
+			spokeFields[name] = value
+			delete(fields, hubFieldName(name))
+		}
+	}
+	raw, err := json.Marshal(spokeFields)
+	if err != nil {
+		return nil, err
+	}
+	return fields, json.Unmarshal(raw, spoke)
+}
@@ -0,0 +1,46 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Ec2InstanceSpec is the first version of the Ec2Instance spec. Everything added in v1 is kept in
+// the ConversionDataAnnotation while an object is read and written as v1alpha1.
+type Ec2InstanceSpec struct {
+	InstanceType string `json:"instanceType"`
+	AMIID        string `json:"amiID"`
+}
+
+// Ec2InstanceStatus is the first version of the Ec2Instance status.
+type Ec2InstanceStatus struct {
+	InstanceID string `json:"instanceId,omitempty"`
+	State      string `json:"state,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="InstanceType",type="string",JSONPath=".spec.instanceType",description="The EC2 instance type"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="The current state of the EC2 instance"
+// +kubebuilder:printcolumn:name="InstanceID",type="string",JSONPath=".status.instanceId",description="The AWS instance ID"
+// Ec2Instance is the Schema for the ec2instances API in its first version. New clients should use v1.
+
+type Ec2Instance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   Ec2InstanceSpec   `json:"spec,omitempty"`
+	Status Ec2InstanceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Ec2InstanceList contains a list of Ec2Instance.
+type Ec2InstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Ec2Instance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Ec2Instance{}, &Ec2InstanceList{})
+}
@@ -17,24 +17,40 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"flag"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	computev1alpha1 "github.com/bshaw7/operator-repo/api/v1alpha1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
 	"github.com/bshaw7/operator-repo/internal/controller"
+	webhookcomputev1 "github.com/bshaw7/operator-repo/internal/webhook/v1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -51,6 +67,8 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme)) // Registers built-in Kubernetes types
 
 	utilruntime.Must(computev1.AddToScheme(scheme)) // Registers custom resource types for this operator
+	// Registers the older API version, which the conversion webhook converts to and from v1
+	utilruntime.Must(computev1alpha1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -58,8 +76,33 @@ func init() {
 func main() {
 	var probeAddr string
 	var metricsAddr string
+	var inventoryAddr string
+	var webhookCertPath, webhookCertName, webhookCertKey string
+	var allowedAMIOwners string
+	var labelSelector string
+	var forceDeleteTimeout time.Duration
+	var awsAPIQPS float64
+	var enforceEBSEncryption, enforceIMDSv2 bool
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metrics endpoint binds to.")
+	flag.StringVar(&inventoryAddr, "inventory-bind-address", "0",
+		"The address the /inventory endpoint binds to, e.g. :8082. It is served over plain HTTP without "+
+			"authentication, so it is disabled (0) by default.")
+	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
+	flag.StringVar(&webhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
+	flag.StringVar(&webhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
+	flag.StringVar(&allowedAMIOwners, "allowed-ami-owners", "",
+		"Comma separated AWS account IDs or owner aliases (e.g. amazon) that AMIs may come from. Empty allows any owner.")
+	flag.StringVar(&labelSelector, "label-selector", "",
+		"Only reconcile Ec2Instances whose labels match this selector (e.g. team=platform). Empty reconciles all of them.")
+	flag.DurationVar(&forceDeleteTimeout, "force-delete-timeout", controller.DefaultDeletionTimeout,
+		"How long deleting an Ec2Instance may take before its finalizer is removed anyway, unless spec.deletionTimeout is set.")
+	flag.Float64Var(&awsAPIQPS, "aws-api-qps", controller.DefaultAWSAPIQPS,
+		"The number of EC2 API calls per second the operator makes at most, across all instances.")
+	flag.BoolVar(&enforceEBSEncryption, "enforce-ebs-encryption", true,
+		"Launch instances with an encrypted root volume and reject Ec2Instances that set spec.encryptRootVolume to false.")
+	flag.BoolVar(&enforceIMDSv2, "enforce-imdsv2", false,
+		"Require IMDSv2 session tokens on every instance and reject Ec2Instances that set spec.metadataOptions.httpTokens to optional.")
 
 	opts := zap.Options{
 		Development: true,
@@ -69,6 +112,19 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	controller.SetAWSAPIRateLimit(awsAPIQPS)
+
+	// With IAM Roles for Service Accounts the operator uses the role of its own service account
+	// instead of static keys.
+	if awsclient.IRSAConfigured() {
+		irsaConfig, err := awsclient.NewAWSConfigFromIRSA(context.Background(), os.Getenv(awsclient.RoleARNEnv), "")
+		if err != nil {
+			setupLog.Error(err, "unable to set up IRSA credentials")
+			os.Exit(1)
+		}
+		controller.SetOperatorCredentials(irsaConfig.Credentials)
+		setupLog.Info("Using IRSA credentials", "roleARN", os.Getenv(awsclient.RoleARNEnv))
+	}
 
 	// Create watcher for webhook certificates
 	// webhookCertWatcher is a pointer to a CertWatcher, which can be used to watch for changes
@@ -76,20 +132,62 @@ func main() {
 	// certificate rotation without restarting the manager. If not used, it remains nil.
 	var webhookCertWatcher *certwatcher.CertWatcher
 
+	// Initial webhook TLS options
+	webhookTLSOpts := []func(*tls.Config){}
+
+	if len(webhookCertPath) > 0 {
+		setupLog.Info("Initializing webhook certificate watcher using provided certificates",
+			"webhook-cert-path", webhookCertPath, "webhook-cert-name", webhookCertName, "webhook-cert-key", webhookCertKey)
+
+		var err error
+		webhookCertWatcher, err = certwatcher.New(
+			filepath.Join(webhookCertPath, webhookCertName),
+			filepath.Join(webhookCertPath, webhookCertKey),
+		)
+		if err != nil {
+			setupLog.Error(err, "Failed to initialize webhook certificate watcher")
+			os.Exit(1)
+		}
+
+		// The watcher reloads the certificate when cert-manager renews the mounted Secret. SIGHUP
+		// forces a reload right away.
+		webhookCertWatcher.RegisterCallback(webhookcomputev1.RecordCertificateExpiry)
+		go func() {
+			hup := make(chan os.Signal, 1)
+			signal.Notify(hup, syscall.SIGHUP)
+			for range hup {
+				if err := webhookCertWatcher.ReadCertificate(); err != nil {
+					setupLog.Error(err, "Failed to reload webhook certificate")
+					continue
+				}
+				setupLog.Info("Reloaded webhook certificate")
+			}
+		}()
+
+		webhookTLSOpts = append(webhookTLSOpts, func(config *tls.Config) {
+			config.GetCertificate = webhookCertWatcher.GetCertificate
+		})
+	}
+
 	// Create a new webhook server. The webhook server is responsible for serving admission webhooks
-	// (such as mutating or validating webhooks) for custom resources. The TLSOpts field can be used
-	// to customize TLS configuration, but is set to nil here for default behavior.
+	// (such as mutating or validating webhooks) for custom resources. When a certificate path is given,
+	// the certificate is served through the watcher so it can be rotated without a restart.
 	webhookServer := webhook.NewServer(webhook.Options{
-		TLSOpts: nil,
+		TLSOpts: webhookTLSOpts,
 	})
 
 	// Create a new controller-runtime Manager. The Manager is the main entry point for running controllers,
 	// webhooks, and other background tasks. It is configured with the scheme (which defines the types it knows about),
 	// the webhook server, and the address for health probes. ctrl.GetConfigOrDie() loads the Kubernetes REST config.
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	restConfig := ctrl.GetConfigOrDie()
+	// Send the reconcile ID as the audit ID, so the audit log ties API requests to their reconcile.
+	restConfig.Wrap(controller.WithReconcileIDHeader)
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
+		// Serves the controller-runtime registry, which the operator's own metrics are registered with.
+		Metrics: metricsserver.Options{BindAddress: metricsAddr},
 	})
 
 	if err != nil {
@@ -97,17 +195,289 @@ func main() {
 		os.Exit(1)
 	}
 
+	// EC2_CONTROLLER_WORKERS raises the number of Ec2Instances reconciled in parallel from the default of 1.
+	ec2InstanceWorkers := 1
+	if value := os.Getenv("EC2_CONTROLLER_WORKERS"); value != "" {
+		ec2InstanceWorkers, err = strconv.Atoi(value)
+		if err != nil || ec2InstanceWorkers < 1 {
+			setupLog.Error(err, "EC2_CONTROLLER_WORKERS must be a positive integer", "value", value)
+			os.Exit(1)
+		}
+	}
+
+	// Operators sharing a cluster each take the Ec2Instances their selector matches.
+	ec2InstanceSelector, err := labels.Parse(labelSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid --label-selector", "selector", labelSelector)
+		os.Exit(1)
+	}
+	if ec2InstanceSelector.Empty() {
+		setupLog.Info("WARNING: no --label-selector given, reconciling every Ec2Instance in the cluster; " +
+			"another operator running in the cluster would manage the same instances")
+	}
+
 	// Set up the Ec2InstanceReconciler controller with the manager.
 	// This controller will watch and reconcile Ec2Instance custom resources.
 	if err = (&controller.Ec2InstanceReconciler{
-		Client: mgr.GetClient(), // Kubernetes client for interacting with API server
-		Scheme: mgr.GetScheme(), // Scheme defines the types the client can work with
+		Client:                  mgr.GetClient(),                                   // Kubernetes client for interacting with API server
+		Scheme:                  mgr.GetScheme(),                                   // Scheme defines the types the client can work with
+		Recorder:                mgr.GetEventRecorderFor("ec2instance-controller"), // Emits Events on Ec2Instance objects
+		MaxConcurrentReconciles: ec2InstanceWorkers,                                // Ec2Instances reconciled in parallel
+		LabelSelector:           ec2InstanceSelector,                               // Ec2Instances this operator is responsible for
+		Scheduler:               controller.NewCronScheduler(),                     // Wakes the controller up for spec.schedule
+		ForceDeleteTimeout:      forceDeleteTimeout,                                // Deletions that take longer stop waiting for AWS
+		EnforceEBSEncryption:    enforceEBSEncryption,                              // Root volumes are launched encrypted
+		EnforceIMDSv2:           enforceIMDSv2,                                     // Instance metadata requires session tokens
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Ec2Instance")
 		os.Exit(1)
 	}
+	// Set up the InventoryReconciler, which aggregates every Ec2Instance into the ClusterInventory singleton.
+	if err = (&controller.InventoryReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterInventory")
+		os.Exit(1)
+	}
+	// Set up the Ec2InstanceSetReconciler, which runs and rolls out groups of identical Ec2Instances.
+	if err = (&controller.Ec2InstanceSetReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("ec2instanceset-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Ec2InstanceSet")
+		os.Exit(1)
+	}
+	// Set up the RegionCredentialsReconciler, which maps AWS regions to the credentials used there.
+	if err = (&controller.RegionCredentialsReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RegionCredentials")
+		os.Exit(1)
+	}
+	// Set up the RegionMigrationReconciler, which moves Ec2Instances between AWS regions.
+	if err = (&controller.RegionMigrationReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RegionMigration")
+		os.Exit(1)
+	}
+	// Set up the CloudFormationMigrationReconciler, which hands instances in CloudFormation stacks over to Ec2Instances.
+	if err = (&controller.CloudFormationMigrationReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CloudFormationMigration")
+		os.Exit(1)
+	}
+	// Set up the RecoverOrphanReconciler, which recreates Ec2Instances for orphaned EC2 instances.
+	if err = (&controller.RecoverOrphanReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RecoverOrphan")
+		os.Exit(1)
+	}
+	// Set up the MaintenanceWindowReconciler, which manages SSM maintenance windows for patching.
+	if err = (&controller.MaintenanceWindowReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MaintenanceWindow")
+		os.Exit(1)
+	}
+	// Set up the NetworkLatencyProbeReconciler, which measures the latency between two Ec2Instances.
+	if err = (&controller.NetworkLatencyProbeReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("networklatencyprobe-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NetworkLatencyProbe")
+		os.Exit(1)
+	}
+	// Set up the NodeProvisionerReconciler, which launches Ec2Instances for pods waiting for a matching node.
+	if err = (&controller.NodeProvisionerReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("nodeprovisioner-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NodeProvisioner")
+		os.Exit(1)
+	}
+	// Set up the CapacityReservationReconciler, which manages EC2 On-Demand Capacity Reservations.
+	if err = (&controller.CapacityReservationReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CapacityReservation")
+		os.Exit(1)
+	}
+	// Set up the CapacityReservationAlertReconciler, which raises alerts and exports metrics for CapacityReservations.
+	if err = (&controller.CapacityReservationAlertReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("capacityreservation-alerts"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CapacityReservationAlerts")
+		os.Exit(1)
+	}
+	// Set up the AMIReconciler, which copies AMIs between regions.
+	if err = (&controller.AMIReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AMI")
+		os.Exit(1)
+	}
+	// Set up the TrafficMirrorReconciler, which mirrors instance traffic to inspection appliances.
+	if err = (&controller.TrafficMirrorReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TrafficMirrorSession")
+		os.Exit(1)
+	}
+	// Set up the VpcEndpointReconciler, which manages VPC endpoints for private access to AWS services.
+	if err = (&controller.VpcEndpointReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VpcEndpoint")
+		os.Exit(1)
+	}
+	// Set up the Ec2SecurityGroupReconciler, which manages VPC security groups and their rules.
+	if err = (&controller.Ec2SecurityGroupReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Ec2SecurityGroup")
+		os.Exit(1)
+	}
+	// Set up the Ec2KeyPairReconciler, which manages SSH key pairs and keeps their private keys in Secrets.
+	if err = (&controller.Ec2KeyPairReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Ec2KeyPair")
+		os.Exit(1)
+	}
+	// Set up the Ec2PlacementGroupReconciler, which manages placement groups Ec2Instances launch into.
+	if err = (&controller.Ec2PlacementGroupReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Ec2PlacementGroup")
+		os.Exit(1)
+	}
+	// Set up the Ec2LaunchTemplateReconciler, which manages versioned launch templates Ec2Instances launch from.
+	if err = (&controller.Ec2LaunchTemplateReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Ec2LaunchTemplate")
+		os.Exit(1)
+	}
+	// Set up the TransitGatewayRouteTableReconciler, which manages transit gateway route tables, their static routes and propagations.
+	if err = (&controller.TransitGatewayRouteTableReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TransitGatewayRouteTable")
+		os.Exit(1)
+	}
+	// Set up the NamespaceConfigReconciler, which reports the estimated monthly spend of each namespace.
+	if err = (&controller.NamespaceConfigReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NamespaceConfig")
+		os.Exit(1)
+	}
+	// Set up the CostAllocationReportReconciler, which writes a daily cost report per namespace.
+	if err = (&controller.CostAllocationReportReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CostAllocationReport")
+		os.Exit(1)
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		var owners []string
+		if allowedAMIOwners != "" {
+			owners = strings.Split(allowedAMIOwners, ",")
+		}
+		if err = webhookcomputev1.SetupEc2InstanceWebhookWithManager(mgr, &webhookcomputev1.Ec2InstanceCustomValidator{
+			DescribeImage:        controller.DescribeImage,
+			DescribeInstanceType: controller.DescribeInstanceType,
+			DescribeSubnet:       controller.DescribeSubnet,
+			RequiredTags: func(ctx context.Context) ([]computev1.RequiredTag, error) {
+				config, err := controller.GetOperatorConfig(ctx, mgr.GetClient())
+				if err != nil {
+					return nil, err
+				}
+				return config.Spec.RequiredTags, nil
+			},
+			AWSTimeout: func(ctx context.Context) (time.Duration, error) {
+				config, err := controller.GetOperatorConfig(ctx, mgr.GetClient())
+				if err != nil {
+					return 0, err
+				}
+				return time.Duration(config.Spec.WebhookAWSTimeoutSeconds) * time.Second, nil
+			},
+			NamespaceSpend: func(ctx context.Context, namespace string) (float64, float64, error) {
+				config, err := controller.GetNamespaceConfig(ctx, mgr.GetClient(), namespace)
+				if err != nil {
+					return 0, 0, err
+				}
+				current, err := controller.NamespaceMonthlySpendUSD(ctx, mgr.GetClient(), namespace)
+				return current, config.Spec.MaxMonthlySpendUSD, err
+			},
+			EstimateMonthlyCost:  controller.EstimateMonthlyCostUSD,
+			AllowedAMIOwners:     owners,
+			EnforceEBSEncryption: enforceEBSEncryption,
+			EnforceIMDSv2:        enforceIMDSv2,
+		}, &webhookcomputev1.Ec2InstanceCustomDefaulter{
+			// POD_NAMESPACE is set through the downward API; without it no defaults apply.
+			Defaults: func(ctx context.Context) (map[string]string, error) {
+				namespace := os.Getenv("POD_NAMESPACE")
+				if namespace == "" {
+					return nil, nil
+				}
+				defaults := &corev1.ConfigMap{}
+				err := mgr.GetClient().Get(ctx, types.NamespacedName{Namespace: namespace, Name: webhookcomputev1.DefaultsConfigMapName}, defaults)
+				if apierrors.IsNotFound(err) {
+					return nil, nil
+				}
+				return defaults.Data, err
+			},
+		}); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Ec2Instance")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	// Serve the aggregated inventory as JSON on /inventory.
+	if inventoryAddr != "0" {
+		if err := mgr.Add(&controller.InventoryServer{
+			Reader:      mgr.GetClient(),
+			BindAddress: inventoryAddr,
+		}); err != nil {
+			setupLog.Error(err, "unable to add inventory server to manager")
+			os.Exit(1)
+		}
+	}
+
+	// Upload the Ec2Instance inventory to S3 when Ec2OperatorConfig enables it.
+	if err := mgr.Add(&controller.InventoryExporter{Reader: mgr.GetClient()}); err != nil {
+		setupLog.Error(err, "unable to add inventory exporter to manager")
+		os.Exit(1)
+	}
+
 	// If a webhook certificate watcher is configured, add it to the manager.
 	// This ensures the manager can reload webhook certificates automatically.
 	if webhookCertWatcher != nil {
@@ -134,9 +504,22 @@ func main() {
 
 	// Start the manager, which will block until the process receives a termination signal.
 	// The manager handles all registered controllers, webhooks, and background tasks.
+	ctx := ctrl.SetupSignalHandler()
+	shutdownTracing, err := controller.SetupTracing(ctx)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+	// The signal context is done by now; give the exporter a moment of its own to flush.
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		setupLog.Error(err, "failed to flush traces")
+	}
 }
@@ -0,0 +1,27 @@
+package aws
+
+import "context"
+
+// CloudWatchClient is the part of the CloudWatch API used for the alarms of spec.cloudWatchAlarms.
+type CloudWatchClient interface {
+	// PutMetricAlarm creates the alarm, or replaces the alarm of the same name.
+	PutMetricAlarm(ctx context.Context, alarm MetricAlarm) error
+	// DeleteAlarms deletes the named alarms. Names without an alarm are ignored.
+	DeleteAlarms(ctx context.Context, names []string) error
+}
+
+// MetricAlarm is an alarm on a metric of a single EC2 instance.
+type MetricAlarm struct {
+	Name        string
+	Description string
+
+	Namespace  string
+	MetricName string
+	InstanceID string
+	Statistic  string
+
+	PeriodSeconds      int32
+	EvaluationPeriods  int32
+	Threshold          float64
+	ComparisonOperator string
+}
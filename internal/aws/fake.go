@@ -0,0 +1,173 @@
+package aws
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// FakeCall is a call made to a FakeEC2Client.
+type FakeCall struct {
+	// Method is the name of the EC2Client method, e.g. "RunInstances".
+	Method string
+	// Input is the *ec2.<Method>Input it was called with.
+	Input any
+}
+
+// FakeEC2Client is an EC2Client and LaunchTemplateClient for tests. It records every call and answers with the output and
+// error configured for the method; a method without either returns an empty output.
+type FakeEC2Client struct {
+	mu    sync.Mutex
+	calls []FakeCall
+
+	// Outputs holds the *ec2.<Method>Output to return, by method name.
+	Outputs map[string]any
+	// Errors holds the error to return, by method name.
+	Errors map[string]error
+}
+
+var (
+	_ EC2Client            = (*FakeEC2Client)(nil)
+	_ LaunchTemplateClient = (*FakeEC2Client)(nil)
+)
+
+// Calls returns the calls made so far, in order.
+func (f *FakeEC2Client) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeCall(nil), f.calls...)
+}
+
+// CallsTo returns the inputs of the calls made to method, in order.
+func (f *FakeEC2Client) CallsTo(method string) []any {
+	var inputs []any
+	for _, call := range f.Calls() {
+		if call.Method == method {
+			inputs = append(inputs, call.Input)
+		}
+	}
+	return inputs
+}
+
+// fakeCall records a call to method and returns its configured output, or empty, and error.
+func fakeCall[T any](f *FakeEC2Client, method string, input any) (*T, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, FakeCall{Method: method, Input: input})
+	if err := f.Errors[method]; err != nil {
+		return nil, err
+	}
+	if output, ok := f.Outputs[method].(*T); ok {
+		return output, nil
+	}
+	return new(T), nil
+}
+
+func (f *FakeEC2Client) RunInstances(_ context.Context, params *ec2.RunInstancesInput, _ ...func(*ec2.Options)) (*ec2.RunInstancesOutput, error) {
+	return fakeCall[ec2.RunInstancesOutput](f, "RunInstances", params)
+}
+
+func (f *FakeEC2Client) TerminateInstances(_ context.Context, params *ec2.TerminateInstancesInput, _ ...func(*ec2.Options)) (*ec2.TerminateInstancesOutput, error) {
+	return fakeCall[ec2.TerminateInstancesOutput](f, "TerminateInstances", params)
+}
+
+func (f *FakeEC2Client) DescribeInstances(_ context.Context, params *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	return fakeCall[ec2.DescribeInstancesOutput](f, "DescribeInstances", params)
+}
+
+func (f *FakeEC2Client) StopInstances(_ context.Context, params *ec2.StopInstancesInput, _ ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error) {
+	return fakeCall[ec2.StopInstancesOutput](f, "StopInstances", params)
+}
+
+func (f *FakeEC2Client) StartInstances(_ context.Context, params *ec2.StartInstancesInput, _ ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error) {
+	return fakeCall[ec2.StartInstancesOutput](f, "StartInstances", params)
+}
+
+func (f *FakeEC2Client) CreateTags(_ context.Context, params *ec2.CreateTagsInput, _ ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	return fakeCall[ec2.CreateTagsOutput](f, "CreateTags", params)
+}
+
+func (f *FakeEC2Client) DeleteTags(_ context.Context, params *ec2.DeleteTagsInput, _ ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error) {
+	return fakeCall[ec2.DeleteTagsOutput](f, "DeleteTags", params)
+}
+
+func (f *FakeEC2Client) DescribeSubnets(_ context.Context, params *ec2.DescribeSubnetsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	return fakeCall[ec2.DescribeSubnetsOutput](f, "DescribeSubnets", params)
+}
+
+func (f *FakeEC2Client) DescribeImages(_ context.Context, params *ec2.DescribeImagesInput, _ ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	return fakeCall[ec2.DescribeImagesOutput](f, "DescribeImages", params)
+}
+
+func (f *FakeEC2Client) DescribeLaunchTemplateVersions(_ context.Context, params *ec2.DescribeLaunchTemplateVersionsInput, _ ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+	return fakeCall[ec2.DescribeLaunchTemplateVersionsOutput](f, "DescribeLaunchTemplateVersions", params)
+}
+
+func (f *FakeEC2Client) DescribeLaunchTemplates(_ context.Context, params *ec2.DescribeLaunchTemplatesInput, _ ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplatesOutput, error) {
+	return fakeCall[ec2.DescribeLaunchTemplatesOutput](f, "DescribeLaunchTemplates", params)
+}
+
+func (f *FakeEC2Client) CreateLaunchTemplate(_ context.Context, params *ec2.CreateLaunchTemplateInput, _ ...func(*ec2.Options)) (*ec2.CreateLaunchTemplateOutput, error) {
+	return fakeCall[ec2.CreateLaunchTemplateOutput](f, "CreateLaunchTemplate", params)
+}
+
+func (f *FakeEC2Client) CreateLaunchTemplateVersion(_ context.Context, params *ec2.CreateLaunchTemplateVersionInput, _ ...func(*ec2.Options)) (*ec2.CreateLaunchTemplateVersionOutput, error) {
+	return fakeCall[ec2.CreateLaunchTemplateVersionOutput](f, "CreateLaunchTemplateVersion", params)
+}
+
+func (f *FakeEC2Client) ModifyLaunchTemplate(_ context.Context, params *ec2.ModifyLaunchTemplateInput, _ ...func(*ec2.Options)) (*ec2.ModifyLaunchTemplateOutput, error) {
+	return fakeCall[ec2.ModifyLaunchTemplateOutput](f, "ModifyLaunchTemplate", params)
+}
+
+func (f *FakeEC2Client) DeleteLaunchTemplate(_ context.Context, params *ec2.DeleteLaunchTemplateInput, _ ...func(*ec2.Options)) (*ec2.DeleteLaunchTemplateOutput, error) {
+	return fakeCall[ec2.DeleteLaunchTemplateOutput](f, "DeleteLaunchTemplate", params)
+}
+
+// FakeCloudWatchClient is a CloudWatchClient for tests. It keeps the alarms that were put and not
+// deleted, and answers with the error configured for the method, if any.
+type FakeCloudWatchClient struct {
+	mu     sync.Mutex
+	alarms map[string]MetricAlarm
+
+	// Errors holds the error to return, by method name.
+	Errors map[string]error
+}
+
+var _ CloudWatchClient = (*FakeCloudWatchClient)(nil)
+
+// Alarms returns the alarms that exist, by name.
+func (f *FakeCloudWatchClient) Alarms() map[string]MetricAlarm {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	alarms := map[string]MetricAlarm{}
+	for name, alarm := range f.alarms {
+		alarms[name] = alarm
+	}
+	return alarms
+}
+
+func (f *FakeCloudWatchClient) PutMetricAlarm(_ context.Context, alarm MetricAlarm) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["PutMetricAlarm"]; err != nil {
+		return err
+	}
+	if f.alarms == nil {
+		f.alarms = map[string]MetricAlarm{}
+	}
+	f.alarms[alarm.Name] = alarm
+	return nil
+}
+
+func (f *FakeCloudWatchClient) DeleteAlarms(_ context.Context, names []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["DeleteAlarms"]; err != nil {
+		return err
+	}
+	for _, name := range names {
+		delete(f.alarms, name)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws_test
+
+import (
+	"context"
+	"errors"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+var _ = Describe("FakeEC2Client", func() {
+	ctx := context.Background()
+
+	It("Should record calls in order and answer with the configured output", func() {
+		fake := &awsclient.FakeEC2Client{Outputs: map[string]any{
+			"RunInstances": &ec2.RunInstancesOutput{ReservationId: awssdk.String("r-1")},
+		}}
+		out, err := fake.RunInstances(ctx, &ec2.RunInstancesInput{ImageId: awssdk.String("ami-1")})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(awssdk.ToString(out.ReservationId)).To(Equal("r-1"))
+		_, err = fake.CreateTags(ctx, &ec2.CreateTagsInput{Resources: []string{"i-1"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fake.Calls()).To(HaveLen(2))
+		Expect(fake.Calls()[1].Method).To(Equal("CreateTags"))
+		Expect(fake.CallsTo("RunInstances")).To(ConsistOf(&ec2.RunInstancesInput{ImageId: awssdk.String("ami-1")}))
+	})
+
+	It("Should return an empty output or the configured error", func() {
+		fake := &awsclient.FakeEC2Client{Errors: map[string]error{"StopInstances": errors.New("UnauthorizedOperation")}}
+		out, err := fake.DescribeInstances(ctx, &ec2.DescribeInstancesInput{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.Reservations).To(BeEmpty())
+
+		_, err = fake.StopInstances(ctx, &ec2.StopInstancesInput{})
+		Expect(err).To(MatchError("UnauthorizedOperation"))
+		Expect(fake.CallsTo("StopInstances")).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("FakeCloudWatchClient", func() {
+	ctx := context.Background()
+
+	It("Should keep the alarms that were put and not deleted", func() {
+		fake := &awsclient.FakeCloudWatchClient{}
+		Expect(fake.PutMetricAlarm(ctx, awsclient.MetricAlarm{Name: "cpu-i-1", InstanceID: "i-1"})).To(Succeed())
+		Expect(fake.PutMetricAlarm(ctx, awsclient.MetricAlarm{Name: "disk-i-1", InstanceID: "i-1"})).To(Succeed())
+		Expect(fake.DeleteAlarms(ctx, []string{"disk-i-1", "unknown"})).To(Succeed())
+		Expect(fake.Alarms()).To(HaveKey("cpu-i-1"))
+		Expect(fake.Alarms()).To(HaveLen(1))
+
+		fake.Errors = map[string]error{"DeleteAlarms": errors.New("AccessDenied")}
+		Expect(fake.DeleteAlarms(ctx, []string{"cpu-i-1"})).To(MatchError("AccessDenied"))
+		Expect(fake.Alarms()).To(HaveLen(1))
+	})
+})
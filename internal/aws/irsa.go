@@ -0,0 +1,47 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Environment variables that the EKS pod identity webhook sets on pods whose service account is
+// annotated with an IAM role (IAM Roles for Service Accounts, IRSA).
+const (
+	RoleARNEnv              = "AWS_ROLE_ARN"
+	WebIdentityTokenFileEnv = "AWS_WEB_IDENTITY_TOKEN_FILE"
+)
+
+// IRSAConfigured reports whether the pod runs with an IRSA role.
+func IRSAConfigured() bool {
+	return os.Getenv(RoleARNEnv) != "" && os.Getenv(WebIdentityTokenFileEnv) != ""
+}
+
+// NewAWSConfigFromIRSA returns an AWS config whose credentials come from assuming roleARN with the
+// service account token in webIdentityTokenFile, $AWS_WEB_IDENTITY_TOKEN_FILE when empty. The
+// token is read again on every refresh, since the kubelet rotates it.
+func NewAWSConfigFromIRSA(ctx context.Context, roleARN, webIdentityTokenFile string) (awssdk.Config, error) {
+	if webIdentityTokenFile == "" {
+		webIdentityTokenFile = os.Getenv(WebIdentityTokenFileEnv)
+	}
+	if roleARN == "" || webIdentityTokenFile == "" {
+		return awssdk.Config{}, errors.New("IRSA needs a role ARN and a web identity token file")
+	}
+
+	// AssumeRoleWithWebIdentity is not signed, so the STS client needs no credentials of its own.
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithCredentialsProvider(awssdk.AnonymousCredentials{}))
+	if err != nil {
+		return awssdk.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	provider := stscreds.NewWebIdentityRoleProvider(sts.NewFromConfig(cfg), roleARN,
+		stscreds.IdentityTokenFile(webIdentityTokenFile))
+	cfg.Credentials = awssdk.NewCredentialsCache(provider)
+	return cfg, nil
+}
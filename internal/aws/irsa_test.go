@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+var _ = Describe("IRSA", func() {
+	setenv := func(key, value string) {
+		previous, had := os.LookupEnv(key)
+		Expect(os.Setenv(key, value)).To(Succeed())
+		DeferCleanup(func() {
+			if had {
+				_ = os.Setenv(key, previous)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		})
+	}
+
+	It("Should only be configured when both the role and the token file are set", func() {
+		setenv(awsclient.RoleARNEnv, "arn:aws:iam::123456789012:role/ec2-operator")
+		setenv(awsclient.WebIdentityTokenFileEnv, "")
+		Expect(awsclient.IRSAConfigured()).To(BeFalse())
+
+		setenv(awsclient.WebIdentityTokenFileEnv, "/var/run/secrets/eks.amazonaws.com/serviceaccount/token")
+		Expect(awsclient.IRSAConfigured()).To(BeTrue())
+	})
+
+	It("Should default the token file to the environment", func() {
+		setenv(awsclient.WebIdentityTokenFileEnv, "")
+		_, err := awsclient.NewAWSConfigFromIRSA(context.Background(), "arn:aws:iam::123456789012:role/ec2-operator", "")
+		Expect(err).To(HaveOccurred())
+
+		token := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(token, []byte("eyJhbGciOi"), 0o600)).To(Succeed())
+		setenv(awsclient.WebIdentityTokenFileEnv, token)
+		cfg, err := awsclient.NewAWSConfigFromIRSA(context.Background(), "arn:aws:iam::123456789012:role/ec2-operator", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Credentials).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// amiObjectTag marks an image copied by an AMI object with the UID of that object.
+const amiObjectTag = "ec2instance.compute.cloud.com/ami"
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch
+
+// backingSnapshotIDs returns the EBS snapshots the image is made of.
+func backingSnapshotIDs(image *ec2types.Image) []string {
+	var ids []string
+	for _, mapping := range image.BlockDeviceMappings {
+		if mapping.Ebs != nil && mapping.Ebs.SnapshotId != nil {
+			ids = append(ids, aws.ToString(mapping.Ebs.SnapshotId))
+		}
+	}
+	return ids
+}
+
+// amisToDeregister returns the images beyond the newest keep, oldest first. The current image and
+// images that are in use are never returned, but they do count toward keep.
+func amisToDeregister(images []ec2types.Image, keep int, current string, inUse map[string]bool) []ec2types.Image {
+	sorted := append([]ec2types.Image(nil), images...)
+	// CreationDate is an ISO 8601 timestamp, so it sorts as a string.
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return aws.ToString(sorted[i].CreationDate) > aws.ToString(sorted[j].CreationDate)
+	})
+
+	var expired []ec2types.Image
+	for i := len(sorted) - 1; i >= keep; i-- {
+		id := aws.ToString(sorted[i].ImageId)
+		if id == current || inUse[id] {
+			continue
+		}
+		expired = append(expired, sorted[i])
+	}
+	return expired
+}
+
+// amisInUse returns the AMI IDs any Ec2Instance in the cluster was asked to launch from or
+// launched from.
+func (r *AMIReconciler) amisInUse(ctx context.Context) (map[string]bool, error) {
+	instances := &computev1.Ec2InstanceList{}
+	if err := r.List(ctx, instances); err != nil {
+		return nil, fmt.Errorf("failed to list Ec2Instances: %w", err)
+	}
+	inUse := map[string]bool{}
+	for _, instance := range instances.Items {
+		inUse[instance.Spec.AMIId] = true
+		if instance.Status.SelectedAMIID != "" {
+			inUse[instance.Status.SelectedAMIID] = true
+		}
+	}
+	return inUse, nil
+}
+
+// pruneAMIs deregisters the copies made by the AMI object beyond spec.retentionCount, and deletes
+// their snapshots.
+func (r *AMIReconciler) pruneAMIs(ctx context.Context, ec2Client *ec2.Client, ami *computev1.AMI) error {
+	l := log.FromContext(ctx)
+
+	result, err := ec2Client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		Owners:  []string{"self"},
+		Filters: []ec2types.Filter{{Name: aws.String("tag:" + amiObjectTag), Values: []string{string(ami.UID)}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list copies of AMI %s: %w", ami.Name, err)
+	}
+	if len(result.Images) <= int(ami.Spec.RetentionCount) {
+		return nil
+	}
+
+	// Instances in any namespace may launch from a copy, so check all of them right before deleting.
+	inUse, err := r.amisInUse(ctx)
+	if err != nil {
+		return err
+	}
+	for _, image := range amisToDeregister(result.Images, int(ami.Spec.RetentionCount), ami.Status.ImageID, inUse) {
+		imageID := aws.ToString(image.ImageId)
+		if _, err := ec2Client.DeregisterImage(ctx, &ec2.DeregisterImageInput{ImageId: image.ImageId}); err != nil {
+			return fmt.Errorf("failed to deregister AMI %s: %w", imageID, err)
+		}
+		// Snapshots can only be deleted once no registered image uses them.
+		for _, snapshotID := range backingSnapshotIDs(&image) {
+			_, err := ec2Client.DeleteSnapshot(ctx, &ec2.DeleteSnapshotInput{SnapshotId: aws.String(snapshotID)})
+			if err != nil && !strings.Contains(err.Error(), "NotFound") {
+				return fmt.Errorf("failed to delete snapshot %s of AMI %s: %w", snapshotID, imageID, err)
+			}
+		}
+		l.Info("Deregistered AMI past retention", "imageID", imageID, "created", aws.ToString(image.CreationDate))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AMI retention", func() {
+	image := func(id, created string) ec2types.Image {
+		return ec2types.Image{ImageId: aws.String(id), CreationDate: aws.String(created)}
+	}
+	ids := func(images []ec2types.Image) []string {
+		var out []string
+		for _, image := range images {
+			out = append(out, aws.ToString(image.ImageId))
+		}
+		return out
+	}
+	images := []ec2types.Image{
+		image("ami-2", "2025-02-01T00:00:00.000Z"),
+		image("ami-4", "2025-04-01T00:00:00.000Z"),
+		image("ami-1", "2025-01-01T00:00:00.000Z"),
+		image("ami-3", "2025-03-01T00:00:00.000Z"),
+	}
+
+	It("should deregister the oldest copies beyond the retention count", func() {
+		Expect(ids(amisToDeregister(images, 2, "ami-4", nil))).To(Equal([]string{"ami-1", "ami-2"}))
+	})
+
+	It("should keep copies that instances use", func() {
+		Expect(ids(amisToDeregister(images, 2, "ami-4", map[string]bool{"ami-1": true}))).To(Equal([]string{"ami-2"}))
+	})
+
+	It("should never deregister the current copy", func() {
+		Expect(ids(amisToDeregister(images, 1, "ami-2", nil))).To(Equal([]string{"ami-1", "ami-3"}))
+	})
+
+	It("should read the backing snapshots of an image", func() {
+		img := &ec2types.Image{BlockDeviceMappings: []ec2types.BlockDeviceMapping{
+			{Ebs: &ec2types.EbsBlockDevice{SnapshotId: aws.String("snap-1")}},
+			{VirtualName: aws.String("ephemeral0")},
+		}}
+		Expect(backingSnapshotIDs(img)).To(Equal([]string{"snap-1"}))
+	})
+})
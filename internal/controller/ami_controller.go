@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// AMIReconciler copies AMIs into the region described by an AMI object.
+type AMIReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=amis,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=amis/status,verbs=get;update;patch
+
+// Reconcile starts the copy and follows it until the image is available.
+func (r *AMIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	ami := &computev1.AMI{}
+	if err := r.Get(ctx, req.NamespacedName, ami); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	ec2Client := awsClient(ami.Spec.Region)
+
+	// A new source means a new copy. The previous one stays until retention removes it.
+	if ami.Status.ImageID != "" && ami.Status.SourceAMIID != "" && ami.Status.SourceAMIID != ami.Spec.SourceAMIID {
+		l.Info("Source AMI changed, making a new copy", "previousImageID", ami.Status.ImageID, "sourceAMI", ami.Spec.SourceAMIID)
+		ami.Status = computev1.AMIStatus{}
+	}
+
+	if ami.Status.ImageID == "" {
+		result, err := ec2Client.CopyImage(ctx, &ec2.CopyImageInput{
+			// AMI names are unique per account and region, so include the object identity.
+			Name:          aws.String(fmt.Sprintf("%s-%s-%s", ami.Namespace, ami.Name, ami.Spec.SourceAMIID)),
+			Description:   aws.String(fmt.Sprintf("Copy of %s from %s, managed by ec2-operator", ami.Spec.SourceAMIID, ami.Spec.SourceRegion)),
+			SourceImageId: aws.String(ami.Spec.SourceAMIID),
+			SourceRegion:  aws.String(ami.Spec.SourceRegion),
+			// The UID makes the call idempotent if the status update below is lost.
+			ClientToken: aws.String(fmt.Sprintf("%s-%s", ami.UID, ami.Spec.SourceAMIID)),
+			TagSpecifications: []ec2types.TagSpecification{{
+				ResourceType: ec2types.ResourceTypeImage,
+				Tags:         []ec2types.Tag{{Key: aws.String(amiObjectTag), Value: aws.String(string(ami.UID))}},
+			}},
+		})
+		if err != nil {
+			l.Error(err, "Failed to copy AMI", "sourceAMI", ami.Spec.SourceAMIID, "sourceRegion", ami.Spec.SourceRegion)
+			return ctrl.Result{}, fmt.Errorf("failed to copy AMI: %w", err)
+		}
+		l.Info("Started AMI copy", "sourceAMI", ami.Spec.SourceAMIID, "imageID", aws.ToString(result.ImageId))
+
+		ami.Status.ImageID = aws.ToString(result.ImageId)
+		ami.Status.SourceAMIID = ami.Spec.SourceAMIID
+		ami.Status.State = string(ec2types.ImageStatePending)
+		if err := r.Status().Update(ctx, ami); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	image, err := DescribeImage(ctx, ami.Spec.Region, ami.Status.ImageID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	originalStatus := ami.Status.DeepCopy()
+	if ami.Status.SourceAMIID == "" {
+		// Copies made before the source was recorded.
+		ami.Status.SourceAMIID = ami.Spec.SourceAMIID
+	}
+	if image == nil {
+		ami.Status.State = "missing"
+		ami.Status.Message = fmt.Sprintf("image %s no longer exists in %s", ami.Status.ImageID, ami.Spec.Region)
+		ami.Status.BackingSnapshotIDs = nil
+	} else {
+		ami.Status.State = string(image.State)
+		ami.Status.Message = ""
+		if image.StateReason != nil {
+			ami.Status.Message = aws.ToString(image.StateReason.Message)
+		}
+		ami.Status.BackingSnapshotIDs = backingSnapshotIDs(image)
+	}
+	var pruneErr error
+	if ami.Status.State == string(ec2types.ImageStateAvailable) && ami.Spec.RetentionCount > 0 {
+		if pruneErr = r.pruneAMIs(ctx, ec2Client, ami); pruneErr != nil {
+			ami.Status.Message = pruneErr.Error()
+		}
+	}
+	if !equality.Semantic.DeepEqual(*originalStatus, ami.Status) {
+		if err := r.Status().Update(ctx, ami); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if pruneErr != nil {
+		return ctrl.Result{}, pruneErr
+	}
+
+	if ami.Status.State == string(ec2types.ImageStatePending) {
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// imageAPI is the part of the EC2 API describeImage uses.
+type imageAPI interface {
+	DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error)
+}
+
+// DescribeImage returns the AMI with the given ID in region, or nil if it does not exist there.
+func DescribeImage(ctx context.Context, region, amiID string) (*ec2types.Image, error) {
+	return describeImage(ctx, awsClient(region), region, amiID)
+}
+
+// describeImage is DescribeImage with a given client, for AMIs that only another account can see.
+func describeImage(ctx context.Context, ec2Client imageAPI, region, amiID string) (*ec2types.Image, error) {
+	result, err := ec2Client.DescribeImages(ctx, &ec2.DescribeImagesInput{ImageIds: []string{amiID}})
+	if err != nil {
+		if strings.Contains(err.Error(), "InvalidAMIID") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to describe AMI %s in %s: %w", amiID, region, err)
+	}
+	if len(result.Images) == 0 {
+		return nil, nil
+	}
+	return &result.Images[0], nil
+}
+
+// ensureRegionalAMI makes sure spec.amiId can be launched in spec.region when spec.autoCopyAMI is set.
+// It returns the AMI ID to launch from, or "" while a copy is still in progress.
+func (r *Ec2InstanceReconciler) ensureRegionalAMI(ctx context.Context, ec2Instance *computev1.Ec2Instance) (string, error) {
+	image, err := describeImage(ctx, instanceAWSClient(ec2Instance), ec2Instance.Spec.Region, ec2Instance.Spec.AMIId)
+	if err != nil {
+		return "", err
+	}
+	if image != nil {
+		return ec2Instance.Spec.AMIId, nil
+	}
+	if ec2Instance.Spec.AMISourceRegion == "" {
+		return "", fmt.Errorf("AMI %s does not exist in %s and spec.amiSourceRegion is not set", ec2Instance.Spec.AMIId, ec2Instance.Spec.Region)
+	}
+
+	// Copies are shared by every instance in the namespace that uses the same AMI in the same region.
+	name := strings.ToLower(fmt.Sprintf("%s-%s", ec2Instance.Spec.AMIId, ec2Instance.Spec.Region))
+	ami := &computev1.AMI{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: ec2Instance.Namespace, Name: name}, ami)
+	if errors.IsNotFound(err) {
+		ami = &computev1.AMI{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ec2Instance.Namespace,
+				Labels:    map[string]string{computev1.AMISourceAMILabel: ec2Instance.Spec.AMIId},
+			},
+			Spec: computev1.AMISpec{
+				Region:       ec2Instance.Spec.Region,
+				SourceAMIID:  ec2Instance.Spec.AMIId,
+				SourceRegion: ec2Instance.Spec.AMISourceRegion,
+			},
+		}
+		if err := r.Create(ctx, ami); err != nil {
+			return "", fmt.Errorf("failed to create AMI copy request: %w", err)
+		}
+		log.FromContext(ctx).Info("Requested AMI copy", "ami", name, "sourceRegion", ec2Instance.Spec.AMISourceRegion)
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	switch ami.Status.State {
+	case string(ec2types.ImageStateAvailable):
+		return ami.Status.ImageID, nil
+	case "", string(ec2types.ImageStatePending):
+		return "", nil
+	default:
+		return "", fmt.Errorf("copy of AMI %s into %s is %s: %s", ec2Instance.Spec.AMIId, ec2Instance.Spec.Region, ami.Status.State, ami.Status.Message)
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AMIReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.AMI{}).
+		Named("ami").
+		Complete(r)
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// autoRecoveryAPI is the part of the EC2 API reconcileAutoRecovery uses.
+type autoRecoveryAPI interface {
+	ModifyInstanceMaintenanceOptions(ctx context.Context, params *ec2.ModifyInstanceMaintenanceOptionsInput, optFns ...func(*ec2.Options)) (*ec2.ModifyInstanceMaintenanceOptionsOutput, error)
+}
+
+// reconcileAutoRecovery makes the instance's automatic recovery setting match spec.autoRecovery and
+// records the effective value in status.autoRecoveryEnabled.
+//
+// AWS exposes this through the instance maintenance options: "default" means EC2 recovers the
+// instance when the underlying host fails, "disabled" turns that off.
+func (r *Ec2InstanceReconciler) reconcileAutoRecovery(ctx context.Context, ec2Client autoRecoveryAPI, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	l := log.FromContext(ctx)
+
+	desired := ec2types.InstanceAutoRecoveryStateDisabled
+	if ec2Instance.Spec.AutoRecovery {
+		desired = ec2types.InstanceAutoRecoveryStateDefault
+
+		// Auto recovery only works on certain EBS-backed instance types. Tell the user instead of
+		// pretending it is enabled, once rather than on every sync.
+		info, err := DescribeInstanceType(ctx, ec2Instance.Spec.Region, string(awsInstance.InstanceType))
+		if err != nil {
+			return err
+		}
+		if !aws.ToBool(info.AutoRecoverySupported) {
+			ec2Instance.Status.AutoRecoveryEnabled = false
+			if apimeta.SetStatusCondition(&ec2Instance.Status.Conditions, metav1.Condition{
+				Type:               computev1.ConditionAutoRecoveryUnsupported,
+				Status:             metav1.ConditionTrue,
+				Reason:             "InstanceTypeUnsupported",
+				Message:            fmt.Sprintf("Instance type %s does not support automatic recovery", awsInstance.InstanceType),
+				ObservedGeneration: ec2Instance.Generation,
+			}) {
+				r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "AutoRecoveryUnsupported",
+					"Instance type %s does not support automatic recovery", awsInstance.InstanceType)
+			}
+			return nil
+		}
+	}
+	apimeta.RemoveStatusCondition(&ec2Instance.Status.Conditions, computev1.ConditionAutoRecoveryUnsupported)
+
+	var current ec2types.InstanceAutoRecoveryState
+	if awsInstance.MaintenanceOptions != nil {
+		current = awsInstance.MaintenanceOptions.AutoRecovery
+	}
+
+	if current != desired {
+		l.Info("Correcting auto recovery setting", "instanceID", ec2Instance.Status.InstanceID, "current", current, "desired", desired)
+
+		_, err := ec2Client.ModifyInstanceMaintenanceOptions(ctx, &ec2.ModifyInstanceMaintenanceOptionsInput{
+			InstanceId:   aws.String(ec2Instance.Status.InstanceID),
+			AutoRecovery: desired,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to modify auto recovery setting: %w", err)
+		}
+	}
+
+	ec2Instance.Status.AutoRecoveryEnabled = desired == ec2types.InstanceAutoRecoveryStateDefault
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// fakeMaintenanceAPI records the auto recovery settings asked for.
+type fakeMaintenanceAPI struct {
+	modified []ec2types.InstanceAutoRecoveryState
+}
+
+func (f *fakeMaintenanceAPI) ModifyInstanceMaintenanceOptions(_ context.Context, in *ec2.ModifyInstanceMaintenanceOptionsInput, _ ...func(*ec2.Options)) (*ec2.ModifyInstanceMaintenanceOptionsOutput, error) {
+	f.modified = append(f.modified, in.AutoRecovery)
+	return &ec2.ModifyInstanceMaintenanceOptionsOutput{}, nil
+}
+
+var _ = Describe("Auto recovery", func() {
+	const region = "autorecovery-test-1"
+
+	var (
+		fake     *fakeMaintenanceAPI
+		recorder *record.FakeRecorder
+		r        *Ec2InstanceReconciler
+		inst     *computev1.Ec2Instance
+	)
+
+	instance := func(instanceType ec2types.InstanceType, current ec2types.InstanceAutoRecoveryState) *ec2types.Instance {
+		return &ec2types.Instance{
+			InstanceType:       instanceType,
+			MaintenanceOptions: &ec2types.InstanceMaintenanceOptions{AutoRecovery: current},
+		}
+	}
+
+	BeforeEach(func() {
+		// Answered from the cache, so no DescribeInstanceTypes call goes to AWS.
+		instanceTypeCache.Store(region+"/t3.micro", &ec2types.InstanceTypeInfo{AutoRecoverySupported: aws.Bool(true)})
+		instanceTypeCache.Store(region+"/i3.metal", &ec2types.InstanceTypeInfo{AutoRecoverySupported: aws.Bool(false)})
+		fake = &fakeMaintenanceAPI{}
+		recorder = record.NewFakeRecorder(10)
+		r = &Ec2InstanceReconciler{Recorder: recorder}
+		inst = &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{Region: region, AutoRecovery: true}}
+		inst.Status.InstanceID = "i-1"
+	})
+
+	It("should turn recovery on for a supported instance type", func() {
+		Expect(r.reconcileAutoRecovery(context.Background(), fake, inst, instance("t3.micro", ec2types.InstanceAutoRecoveryStateDisabled))).To(Succeed())
+		Expect(fake.modified).To(Equal([]ec2types.InstanceAutoRecoveryState{ec2types.InstanceAutoRecoveryStateDefault}))
+		Expect(inst.Status.AutoRecoveryEnabled).To(BeTrue())
+	})
+
+	It("should leave an instance that already has the setting alone", func() {
+		Expect(r.reconcileAutoRecovery(context.Background(), fake, inst, instance("t3.micro", ec2types.InstanceAutoRecoveryStateDefault))).To(Succeed())
+		inst.Spec.AutoRecovery = false
+		Expect(r.reconcileAutoRecovery(context.Background(), fake, inst, instance("t3.micro", ec2types.InstanceAutoRecoveryStateDisabled))).To(Succeed())
+		Expect(fake.modified).To(BeEmpty())
+		Expect(inst.Status.AutoRecoveryEnabled).To(BeFalse())
+	})
+
+	It("should report an unsupported instance type once", func() {
+		for range 3 {
+			Expect(r.reconcileAutoRecovery(context.Background(), fake, inst, instance("i3.metal", ec2types.InstanceAutoRecoveryStateDefault))).To(Succeed())
+		}
+		Expect(fake.modified).To(BeEmpty())
+		Expect(inst.Status.AutoRecoveryEnabled).To(BeFalse())
+		Expect(apimeta.IsStatusConditionTrue(inst.Status.Conditions, computev1.ConditionAutoRecoveryUnsupported)).To(BeTrue())
+		Expect(recorder.Events).To(HaveLen(1))
+
+		// Resized to a supported type, the condition goes away.
+		Expect(r.reconcileAutoRecovery(context.Background(), fake, inst, instance("t3.micro", ec2types.InstanceAutoRecoveryStateDefault))).To(Succeed())
+		Expect(apimeta.FindStatusCondition(inst.Status.Conditions, computev1.ConditionAutoRecoveryUnsupported)).To(BeNil())
+		Expect(inst.Status.AutoRecoveryEnabled).To(BeTrue())
+	})
+})
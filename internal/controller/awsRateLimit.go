@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"golang.org/x/time/rate"
+)
+
+// DefaultAWSAPIQPS is the rate of EC2 API calls the operator makes when no other rate is configured.
+const DefaultAWSAPIQPS = 10
+
+// awsRateLimiter spreads the EC2 API calls of all reconciles, so many instances reconciling at once
+// stay below the account's request limit instead of running into RequestLimitExceeded.
+var awsRateLimiter = rate.NewLimiter(DefaultAWSAPIQPS, DefaultAWSAPIQPS)
+
+// SetAWSAPIRateLimit sets the rate of EC2 API calls in calls per second. It must be called before
+// the manager starts.
+func SetAWSAPIRateLimit(qps float64) {
+	awsRateLimiter = rate.NewLimiter(rate.Limit(qps), max(int(qps), 1))
+}
+
+// limitAWSCalls is an API option for AWS clients that waits for awsRateLimiter before each call.
+func limitAWSCalls(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("LimitAWSCalls",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			if err := awsRateLimiter.Wait(ctx); err != nil {
+				return middleware.InitializeOutput{}, middleware.Metadata{}, err
+			}
+			return next.HandleInitialize(ctx, in)
+		}), middleware.Before)
+}
+
+// isThrottlingError reports whether AWS turned a call down because too many were made, after the
+// SDK's own retries gave up.
+func isThrottlingError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	_, ok := retry.DefaultThrottleErrorCodes[apiErr.ErrorCode()]
+	return ok
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AWS rate limiting", func() {
+	It("should recognise throttling errors", func() {
+		for _, code := range []string{"RequestLimitExceeded", "ThrottlingException", "Throttling"} {
+			err := fmt.Errorf("failed to describe instance: %w", &smithy.GenericAPIError{Code: code})
+			Expect(isThrottlingError(err)).To(BeTrue(), code)
+		}
+	})
+
+	It("should not treat other errors as throttling", func() {
+		Expect(isThrottlingError(&smithy.GenericAPIError{Code: "InvalidInstanceID.NotFound"})).To(BeFalse())
+		Expect(isThrottlingError(errors.New("connection refused"))).To(BeFalse())
+		Expect(isThrottlingError(nil)).To(BeFalse())
+	})
+})
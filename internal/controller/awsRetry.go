@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/cenkalti/backoff/v4"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// transientAWSErrorCodes are the error codes of calls that may well succeed when made again a
+// little later. Anything else, such as InvalidAMIID.NotFound, fails the same way every time.
+var transientAWSErrorCodes = map[string]bool{
+	"RequestLimitExceeded": true,
+	"InternalError":        true,
+	"InternalFailure":      true,
+	"ServiceUnavailable":   true,
+	"Unavailable":          true,
+}
+
+// isTransientAWSError reports whether err is worth retrying.
+func isTransientAWSError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return transientAWSErrorCodes[apiErr.ErrorCode()] || isThrottlingError(err)
+}
+
+// newAWSRetryBackOff returns the backoff between attempts of a call: 500ms doubling up to 30s, for
+// at most 5 attempts.
+func newAWSRetryBackOff(ctx context.Context) backoff.BackOff {
+	b := backoff.NewExponentialBackOff(
+		backoff.WithInitialInterval(500*time.Millisecond),
+		backoff.WithMultiplier(2),
+		backoff.WithMaxInterval(30*time.Second),
+		// The attempt count is the limit, not the time spent.
+		backoff.WithMaxElapsedTime(0),
+	)
+	return backoff.WithContext(backoff.WithMaxRetries(b, 4), ctx)
+}
+
+// retryTransientAWSErrors calls fn until it succeeds, fails with an error that is not transient, or
+// b gives up. Every retry is counted in ec2instance_aws_retries_total under operation.
+func retryTransientAWSErrors(ctx context.Context, operation string, b backoff.BackOff, fn func() error) error {
+	return backoff.RetryNotify(func() error {
+		err := fn()
+		if err != nil && !isTransientAWSError(err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, b, func(err error, wait time.Duration) {
+		awsRetries.WithLabelValues(operation).Inc()
+		log.FromContext(ctx).Info("Retrying AWS call after transient error", "operation", operation, "wait", wait, "error", err.Error())
+	})
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/smithy-go"
+	"github.com/cenkalti/backoff/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retrying AWS calls", func() {
+	noWait := func() backoff.BackOff { return backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 4) }
+
+	It("should retry transient errors until the call succeeds", func() {
+		attempts := 0
+		err := retryTransientAWSErrors(context.Background(), "RunInstances", noWait(), func() error {
+			attempts++
+			if attempts < 3 {
+				return &smithy.GenericAPIError{Code: "InternalError"}
+			}
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts).To(Equal(3))
+	})
+
+	It("should give up after five attempts", func() {
+		attempts := 0
+		err := retryTransientAWSErrors(context.Background(), "RunInstances", noWait(), func() error {
+			attempts++
+			return &smithy.GenericAPIError{Code: "ServiceUnavailable"}
+		})
+		Expect(err).To(MatchError(ContainSubstring("ServiceUnavailable")))
+		Expect(attempts).To(Equal(5))
+	})
+
+	It("should return errors that are not transient right away", func() {
+		attempts := 0
+		err := retryTransientAWSErrors(context.Background(), "RunInstances", noWait(), func() error {
+			attempts++
+			return &smithy.GenericAPIError{Code: "InvalidAMIID.NotFound"}
+		})
+		Expect(err).To(MatchError(ContainSubstring("InvalidAMIID.NotFound")))
+		Expect(attempts).To(Equal(1))
+	})
+})
@@ -4,21 +4,141 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func awsClient(region string) *ec2.Client {
-	// read env variable for namespace
-	accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
-	secretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+// route53Region is where the global Route53 API is served from.
+const route53Region = "us-east-1"
+
+// awsConfigs caches the AWS config and EC2 client of each region, so credentials, assumed role
+// sessions and HTTP connections are reused across calls. RegionCredentialsReconciler empties it
+// whenever credentials change.
+var awsConfigs = struct {
+	sync.Mutex
+	byRegion   map[string]aws.Config
+	ec2Clients map[string]*ec2.Client
+	// crossAccount holds the clients of instances with spec.roleARN, keyed by namespace/name@region.
+	crossAccount map[string]crossAccountClient
+}{byRegion: map[string]aws.Config{}, ec2Clients: map[string]*ec2.Client{}, crossAccount: map[string]crossAccountClient{}}
+
+// regionCredentialsReader is used by awsConfig to look up RegionCredentials. It is set by
+// RegionCredentialsReconciler.SetupWithManager; without it every region uses the default credentials.
+var regionCredentialsReader client.Reader
+
+// awsConfig returns the AWS config for region, using the RegionCredentials of the region when there is one.
+func awsConfig(region string) aws.Config {
+	awsConfigs.Lock()
+	defer awsConfigs.Unlock()
+	cfg, _ := lockedAWSConfig(region)
+	return cfg
+}
+
+// lockedAWSConfig is awsConfig for callers that hold the awsConfigs lock. cached is false when the
+// config carries a credentials lookup error and must not be kept.
+func lockedAWSConfig(region string) (cfg aws.Config, cached bool) {
+	if cfg, ok := awsConfigs.byRegion[region]; ok {
+		return cfg, true
+	}
+
+	cfg = defaultAWSConfig(region)
+	if regionCredentialsReader != nil {
+		ctx := context.TODO()
+		regionCfg, err := regionAWSConfig(ctx, regionCredentialsReader, cfg)
+		if err != nil {
+			// Never fall back to the default credentials: they may belong to another account. Calls
+			// fail with the lookup error instead, and the next call retries the lookup.
+			cfg.Credentials = aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+				return aws.Credentials{}, err
+			})
+			return cfg, false
+		}
+		cfg = regionCfg
+	}
+	awsConfigs.byRegion[region] = cfg
+	return cfg, true
+}
+
+// resetAWSConfigs drops every cached config, so the next call in each region picks up new credentials.
+func resetAWSConfigs() {
+	awsConfigs.Lock()
+	defer awsConfigs.Unlock()
+	awsConfigs.byRegion = map[string]aws.Config{}
+	awsConfigs.ec2Clients = map[string]*ec2.Client{}
+	awsConfigs.crossAccount = map[string]crossAccountClient{}
+}
+
+// operatorCredentials, when set, are the operator's own credentials in every region instead of the
+// static keys in the environment.
+var operatorCredentials aws.CredentialsProvider
+
+// SetOperatorCredentials makes the operator use credentials, e.g. those of its IRSA role, wherever
+// no RegionCredentials apply. It must be called before the manager starts.
+func SetOperatorCredentials(credentials aws.CredentialsProvider) {
+	operatorCredentials = credentials
+}
 
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region), config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")))
+// defaultAWSConfig returns the config for region with the operator's own credentials.
+func defaultAWSConfig(region string) aws.Config {
+	provider := operatorCredentials
+	if provider == nil {
+		// read env variable for namespace
+		accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
+		secretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+		provider = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region), config.WithCredentialsProvider(provider))
 	if err != nil {
 		fmt.Println("Error loading AWS config:", err)
 		os.Exit(1)
 	}
-	return ec2.NewFromConfig(cfg)
+	return cfg
+}
+
+// awsClient returns the EC2 client for spec.region. Every region has its own client, created on
+// first use and shared by all instances in that region.
+func awsClient(region string) *ec2.Client {
+	awsConfigs.Lock()
+	defer awsConfigs.Unlock()
+	if client, ok := awsConfigs.ec2Clients[region]; ok {
+		return client
+	}
+	cfg, cached := lockedAWSConfig(region)
+	client := ec2.NewFromConfig(cfg, func(o *ec2.Options) {
+		o.APIOptions = append(o.APIOptions, limitAWSCalls, countAWSCalls)
+	})
+	if cached {
+		awsConfigs.ec2Clients[region] = client
+	}
+	return client
+}
+
+// route53Client returns a client for the (global) Route53 API.
+func route53Client() *route53.Client {
+	return route53.NewFromConfig(awsConfig(route53Region))
+}
+
+// ssmClient returns a Systems Manager client for region.
+func ssmClient(region string) *ssm.Client {
+	return ssm.NewFromConfig(awsConfig(region))
+}
+
+// s3Client returns an S3 client for region.
+func s3Client(region string) *s3.Client {
+	return s3.NewFromConfig(awsConfig(region))
+}
+
+// snsClient returns an SNS client for region.
+func snsClient(region string) *sns.Client {
+	return sns.NewFromConfig(awsConfig(region))
 }
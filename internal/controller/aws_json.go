@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/smithy-go"
+)
+
+// awsJSONService describes an AWS API that speaks the JSON 1.1 (or REST-JSON) protocol. It is used for the few
+// services the operator talks to without pulling in their full SDK module.
+type awsJSONService struct {
+	// Endpoint is the https URL of the service, e.g. https://ce.us-east-1.amazonaws.com.
+	Endpoint string
+	// SigningName and Region are used for the SigV4 signature.
+	SigningName string
+	Region      string
+	// TargetPrefix is prepended to the operation name in the X-Amz-Target header.
+	TargetPrefix string
+	// Credentials sign the calls. Without them the operator's credentials in Region are used.
+	Credentials aws.CredentialsProvider
+}
+
+// awsJSONError is the error body returned by JSON 1.1 services.
+type awsJSONError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+	// Some services capitalise the message field.
+	MessageUpper string `json:"Message"`
+}
+
+// call invokes operation with the JSON encoding of in and decodes the response into out (if non-nil).
+func (s awsJSONService) call(ctx context.Context, operation string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", operation, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", s.TargetPrefix+"."+operation)
+
+	return s.send(ctx, operation, req, body, out)
+}
+
+// get invokes a REST-JSON operation that is exposed as GET /<operation>?<query>, as Image Builder
+// does, and decodes the response into out.
+func (s awsJSONService) get(ctx context.Context, operation string, query url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.Endpoint+"/"+operation+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, operation, req, nil, out)
+}
+
+// send signs and performs req and decodes the response into out (if non-nil).
+func (s awsJSONService) send(ctx context.Context, operation string, req *http.Request, body []byte, out any) error {
+	respBody, err := signAndSend(ctx, s.Credentials, s.SigningName, s.Region, operation, req, body, parseJSONError)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", operation, err)
+	}
+	return nil
+}
+
+// parseJSONError returns the error code and message of a failed JSON response.
+func parseJSONError(resp *http.Response, body []byte) (code, message string) {
+	apiErr := awsJSONError{}
+	_ = json.Unmarshal(body, &apiErr)
+	code, message = apiErr.Type, apiErr.Message
+	if message == "" {
+		message = apiErr.MessageUpper
+	}
+	if code == "" {
+		// REST-JSON services report the error type in a header instead of the body.
+		code = resp.Header.Get("X-Amzn-ErrorType")
+	}
+	// The type may be qualified (aws.protocols#ThrottlingException) or carry a URL after a colon.
+	if i := strings.LastIndex(code, "#"); i >= 0 {
+		code = code[i+1:]
+	}
+	code, _, _ = strings.Cut(code, ":")
+	return code, message
+}
+
+// awsHTTPTimeout is how long a call of awsJSONService or awsQueryService may take, so an endpoint
+// that does not answer cannot hold up a reconcile worker.
+const awsHTTPTimeout = 30 * time.Second
+
+// awsHTTPClient performs the calls of awsJSONService and awsQueryService.
+var awsHTTPClient = &http.Client{Timeout: awsHTTPTimeout}
+
+// awsErrorParser returns the error code and message of a failed response.
+type awsErrorParser func(resp *http.Response, body []byte) (code, message string)
+
+// signAndSend signs req with SigV4 for the given service and region, performs it and returns the
+// response body. Without credentials the operator's credentials in region are used. Like the SDK clients it waits for awsRateLimiter, is counted in
+// ec2instance_aws_api_calls_total and retries transient errors. A failed response becomes a
+// smithy.APIError with the code and message parseError finds in it.
+func signAndSend(ctx context.Context, credentials aws.CredentialsProvider, signingName, region, operation string, req *http.Request, body []byte, parseError awsErrorParser) ([]byte, error) {
+	if credentials == nil {
+		credentials = awsConfig(region).Credentials
+	}
+	awsAPICalls.WithLabelValues(operation).Inc()
+	var respBody []byte
+	err := retryTransientAWSErrors(ctx, operation, newAWSRetryBackOff(ctx), func() error {
+		if err := awsRateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		creds, err := credentials.Retrieve(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+		}
+		// Every attempt is a fresh request with its own signature.
+		attempt := req.Clone(ctx)
+		if body != nil {
+			attempt.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		payloadHash := sha256.Sum256(body)
+		if err := v4.NewSigner().SignHTTP(ctx, creds, attempt, hex.EncodeToString(payloadHash[:]), signingName, region, time.Now()); err != nil {
+			return fmt.Errorf("failed to sign %s request: %w", operation, err)
+		}
+
+		resp, err := awsHTTPClient.Do(attempt)
+		if err != nil {
+			return fmt.Errorf("%s request failed: %w", operation, err)
+		}
+		defer resp.Body.Close() //nolint:errcheck
+
+		respBody, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read %s response: %w", operation, err)
+		}
+		if resp.StatusCode >= 300 {
+			code, message := parseError(resp, respBody)
+			if code == "" && resp.StatusCode >= 500 {
+				// What AWS calls these when it names them, so they are retried.
+				code = "InternalError"
+				if resp.StatusCode == http.StatusServiceUnavailable {
+					code = "ServiceUnavailable"
+				}
+			}
+			return fmt.Errorf("%s failed with status %d: %w", operation, resp.StatusCode, &smithy.GenericAPIError{Code: code, Message: message})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return respBody, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signed AWS calls", func() {
+	const region = "awsjson-test-1"
+
+	var (
+		requests atomic.Int32
+		bodies   chan string
+	)
+
+	// serve answers the requests in order with the given status codes and bodies; the last one
+	// answers every request after it.
+	serve := func(responses ...func(http.ResponseWriter)) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.Header.Get("Authorization")).To(HavePrefix("AWS4-HMAC-SHA256"))
+			body, _ := io.ReadAll(req.Body)
+			bodies <- string(body)
+			n := int(requests.Add(1))
+			responses[min(n, len(responses))-1](w)
+		}))
+		DeferCleanup(srv.Close)
+		return srv
+	}
+	respond := func(status int, header, body string) func(http.ResponseWriter) {
+		return func(w http.ResponseWriter) {
+			if header != "" {
+				w.Header().Set("X-Amzn-ErrorType", header)
+			}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}
+	}
+
+	BeforeEach(func() {
+		requests.Store(0)
+		bodies = make(chan string, 10)
+		previous := operatorCredentials
+		SetOperatorCredentials(credentials.NewStaticCredentialsProvider("AKIATEST", "secret", ""))
+		DeferCleanup(func() {
+			operatorCredentials = previous
+			resetAWSConfigs()
+		})
+	})
+
+	It("should retry throttled JSON calls with the same body", func() {
+		srv := serve(
+			respond(http.StatusBadRequest, "", `{"__type":"com.amazon.coral.availability#ThrottlingException","message":"Rate exceeded"}`),
+			respond(http.StatusOK, "", `{"Value":"ok"}`),
+		)
+		service := awsJSONService{Endpoint: srv.URL, SigningName: "ce", Region: region, TargetPrefix: "Test"}
+		var out struct{ Value string }
+		Expect(service.call(context.Background(), "GetValue", map[string]string{"Key": "k"}, &out)).To(Succeed())
+		Expect(out.Value).To(Equal("ok"))
+		Expect(requests.Load()).To(Equal(int32(2)))
+		Expect(<-bodies).To(Equal(`{"Key":"k"}`))
+		Expect(<-bodies).To(Equal(`{"Key":"k"}`))
+	})
+
+	It("should return other errors with their code right away", func() {
+		srv := serve(respond(http.StatusBadRequest, "ValidationException:http://internal.amazon.com/", `{"Message":"bad input"}`))
+		service := awsJSONService{Endpoint: srv.URL, SigningName: "imagebuilder", Region: region}
+		err := service.get(context.Background(), "GetComponent", url.Values{}, nil)
+		var apiErr smithy.APIError
+		Expect(errors.As(err, &apiErr)).To(BeTrue())
+		Expect(apiErr.ErrorCode()).To(Equal("ValidationException"))
+		Expect(apiErr.ErrorMessage()).To(Equal("bad input"))
+		Expect(requests.Load()).To(Equal(int32(1)))
+	})
+
+	It("should retry Query calls the service could not answer", func() {
+		srv := serve(
+			respond(http.StatusServiceUnavailable, "", ""),
+			respond(http.StatusOK, "", `<TestResponse><Value>ok</Value></TestResponse>`),
+		)
+		service := awsQueryService{Endpoint: srv.URL, SigningName: "monitoring", Region: region, Version: "2010-08-01"}
+		var out struct{ Value string }
+		Expect(service.call(context.Background(), "Test", url.Values{"Key": {"k"}}, &out)).To(Succeed())
+		Expect(out.Value).To(Equal("ok"))
+		Expect(requests.Load()).To(Equal(int32(2)))
+	})
+
+	It("should give up on an endpoint that does not answer", func() {
+		previous := awsHTTPClient
+		awsHTTPClient = &http.Client{Timeout: 50 * time.Millisecond}
+		DeferCleanup(func() { awsHTTPClient = previous })
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			select {
+			case <-req.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}))
+		DeferCleanup(srv.Close)
+		service := awsQueryService{Endpoint: srv.URL, SigningName: "monitoring", Region: region, Version: "2010-08-01"}
+		Expect(service.call(context.Background(), "Test", url.Values{}, nil)).To(MatchError(ContainSubstring("Timeout")))
+	})
+})
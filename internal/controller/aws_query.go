@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// awsQueryService describes an AWS API that speaks the Query protocol (form-encoded requests, XML
+// responses), such as CloudFormation. Like awsJSONService it avoids pulling in the full SDK module.
+type awsQueryService struct {
+	// Endpoint is the https URL of the service, e.g. https://cloudformation.us-east-1.amazonaws.com.
+	Endpoint string
+	// SigningName and Region are used for the SigV4 signature.
+	SigningName string
+	Region      string
+	// Version is the API version sent with every request, e.g. 2010-05-15.
+	Version string
+	// Credentials sign the calls. Without them the operator's credentials in Region are used.
+	Credentials aws.CredentialsProvider
+}
+
+// awsQueryError is the error body returned by Query services.
+type awsQueryError struct {
+	Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+// call invokes action with params and decodes the XML response into out (if non-nil). out should
+// describe the <ActionResponse> element.
+func (s awsQueryService) call(ctx context.Context, action string, params url.Values, out any) error {
+	form := url.Values{}
+	for k, v := range params {
+		form[k] = v
+	}
+	form.Set("Action", action)
+	form.Set("Version", s.Version)
+	body := []byte(form.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint+"/", strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	respBody, err := signAndSend(ctx, s.Credentials, s.SigningName, s.Region, action, req, body, parseQueryError)
+	if err != nil {
+		return err
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := xml.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", action, err)
+	}
+	return nil
+}
+
+// parseQueryError returns the error code and message of a failed Query response.
+func parseQueryError(_ *http.Response, body []byte) (code, message string) {
+	apiErr := awsQueryError{}
+	_ = xml.Unmarshal(body, &apiErr)
+	return apiErr.Error.Code, apiErr.Error.Message
+}
@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// underUtilizedPercent is the utilization below which a reservation is reported as under-used.
+	underUtilizedPercent = 50
+	// expiryWarning is how long before the end date a reservation is reported as expiring.
+	expiryWarning = 7 * 24 * time.Hour
+	// alertHistoryLimit is the number of alerts kept in status.
+	alertHistoryLimit = 20
+	// capacityReservationAlertInterval is how often alerts are re-evaluated without any change to the object.
+	capacityReservationAlertInterval = 5 * time.Minute
+)
+
+// CapacityReservationAlertReconciler watches CapacityReservation objects for reservations that
+// need attention and reports them as events, status alert history and Prometheus metrics. It only
+// reads what the CapacityReservationReconciler recorded in status and never calls AWS itself.
+type CapacityReservationAlertReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=capacityreservations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=capacityreservations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile updates the metrics of the reservation and raises or resolves its alerts.
+func (r *CapacityReservationAlertReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reservation := &computev1.CapacityReservation{}
+	if err := r.Get(ctx, req.NamespacedName, reservation); err != nil {
+		if errors.IsNotFound(err) {
+			labels := prometheus.Labels{"namespace": req.Namespace, "name": req.Name}
+			capacityReservationUtilization.DeletePartialMatch(labels)
+			capacityReservationExpiryDays.DeletePartialMatch(labels)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if reservation.Status.ReservationID == "" {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	recordCapacityReservationMetrics(reservation, now)
+
+	originalStatus := reservation.Status.DeepCopy()
+	raised, resolved := updateAlertHistory(&reservation.Status, evaluateCapacityReservationAlerts(reservation, now), metav1.NewTime(now))
+	for _, alert := range raised {
+		r.Recorder.Event(reservation, corev1.EventTypeWarning, string(alert.Type), alert.Message)
+	}
+	for _, alert := range resolved {
+		r.Recorder.Eventf(reservation, corev1.EventTypeNormal, string(alert.Type)+"Resolved", "Resolved: %s", alert.Message)
+	}
+
+	if !equality.Semantic.DeepEqual(*originalStatus, reservation.Status) {
+		if err := r.Status().Update(ctx, reservation); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.FromContext(ctx).Info("Updated capacity reservation alerts", "raised", len(raised), "resolved", len(resolved))
+	}
+	return ctrl.Result{RequeueAfter: capacityReservationAlertInterval}, nil
+}
+
+// capacityReservationUtilizationPercent returns the share of the reserved instances that are in use.
+func capacityReservationUtilizationPercent(status computev1.CapacityReservationStatus) float64 {
+	if status.TotalInstanceCount == 0 {
+		return 0
+	}
+	used := status.TotalInstanceCount - status.AvailableInstanceCount
+	return float64(used) / float64(status.TotalInstanceCount) * 100
+}
+
+// recordCapacityReservationMetrics exports utilization and, for limited reservations, days until expiry.
+func recordCapacityReservationMetrics(reservation *computev1.CapacityReservation, now time.Time) {
+	labels := prometheus.Labels{
+		"namespace":      reservation.Namespace,
+		"name":           reservation.Name,
+		"reservation_id": reservation.Status.ReservationID,
+	}
+	capacityReservationUtilization.With(labels).Set(capacityReservationUtilizationPercent(reservation.Status))
+	if reservation.Spec.EndDateType == "limited" && reservation.Spec.EndDate != nil {
+		capacityReservationExpiryDays.With(labels).Set(reservation.Spec.EndDate.Sub(now).Hours() / 24)
+	} else {
+		capacityReservationExpiryDays.Delete(labels)
+	}
+}
+
+// evaluateCapacityReservationAlerts returns the alerts that currently apply to an active reservation.
+func evaluateCapacityReservationAlerts(reservation *computev1.CapacityReservation, now time.Time) []computev1.CapacityReservationAlert {
+	status := reservation.Status
+	if status.State != "active" {
+		return nil
+	}
+
+	var alerts []computev1.CapacityReservationAlert
+	if utilization := capacityReservationUtilizationPercent(status); utilization < underUtilizedPercent {
+		alerts = append(alerts, computev1.CapacityReservationAlert{
+			Type: computev1.CapacityReservationUnderUtilized,
+			Message: fmt.Sprintf("Only %d of %d reserved instances are in use (%.0f%%)",
+				status.TotalInstanceCount-status.AvailableInstanceCount, status.TotalInstanceCount, utilization),
+		})
+	}
+	if status.TotalInstanceCount > 0 && status.AvailableInstanceCount == 0 {
+		alerts = append(alerts, computev1.CapacityReservationAlert{
+			Type:    computev1.CapacityReservationOverSubscribed,
+			Message: fmt.Sprintf("All %d reserved instances are in use, new instances will not get reserved capacity", status.TotalInstanceCount),
+		})
+	}
+	if reservation.Spec.EndDateType == "limited" && reservation.Spec.EndDate != nil {
+		if left := reservation.Spec.EndDate.Sub(now); left < expiryWarning {
+			alerts = append(alerts, computev1.CapacityReservationAlert{
+				Type:    computev1.CapacityReservationExpiringSoon,
+				Message: fmt.Sprintf("Reservation %s ends on %s", status.ReservationID, reservation.Spec.EndDate.UTC().Format(time.RFC3339)),
+			})
+		}
+	}
+	return alerts
+}
+
+// updateAlertHistory opens an entry for every firing alert that is not open yet and resolves open
+// entries that no longer fire. It returns the alerts that were raised and resolved.
+func updateAlertHistory(status *computev1.CapacityReservationStatus, firing []computev1.CapacityReservationAlert, now metav1.Time) (raised, resolved []computev1.CapacityReservationAlert) {
+	isFiring := map[computev1.CapacityReservationAlertType]bool{}
+	for _, alert := range firing {
+		isFiring[alert.Type] = true
+	}
+
+	open := map[computev1.CapacityReservationAlertType]bool{}
+	for i := range status.AlertHistory {
+		alert := &status.AlertHistory[i]
+		if alert.ResolvedAt != nil {
+			continue
+		}
+		if isFiring[alert.Type] {
+			open[alert.Type] = true
+			continue
+		}
+		alert.ResolvedAt = now.DeepCopy()
+		resolved = append(resolved, *alert)
+	}
+
+	for _, alert := range firing {
+		if open[alert.Type] {
+			continue
+		}
+		alert.RaisedAt = now
+		status.AlertHistory = append(status.AlertHistory, alert)
+		raised = append(raised, alert)
+	}
+
+	if len(status.AlertHistory) > alertHistoryLimit {
+		status.AlertHistory = status.AlertHistory[len(status.AlertHistory)-alertHistoryLimit:]
+	}
+	return raised, resolved
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CapacityReservationAlertReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.CapacityReservation{}).
+		Named("capacityreservation-alerts").
+		Complete(r)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("CapacityReservation alerts", func() {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	reservation := func(total, available int32, endDate *time.Time) *computev1.CapacityReservation {
+		cr := &computev1.CapacityReservation{Status: computev1.CapacityReservationStatus{
+			ReservationID:          "cr-123",
+			State:                  "active",
+			TotalInstanceCount:     total,
+			AvailableInstanceCount: available,
+		}}
+		if endDate != nil {
+			cr.Spec.EndDateType = "limited"
+			cr.Spec.EndDate = &metav1.Time{Time: *endDate}
+		}
+		return cr
+	}
+	alertTypes := func(alerts []computev1.CapacityReservationAlert) []computev1.CapacityReservationAlertType {
+		var types []computev1.CapacityReservationAlertType
+		for _, alert := range alerts {
+			types = append(types, alert.Type)
+		}
+		return types
+	}
+
+	It("should not alert on a healthy reservation", func() {
+		end := now.Add(30 * 24 * time.Hour)
+		Expect(evaluateCapacityReservationAlerts(reservation(4, 1, &end), now)).To(BeEmpty())
+	})
+
+	It("should alert on low utilization, exhaustion and expiry", func() {
+		Expect(alertTypes(evaluateCapacityReservationAlerts(reservation(4, 3, nil), now))).
+			To(ConsistOf(computev1.CapacityReservationUnderUtilized))
+		Expect(alertTypes(evaluateCapacityReservationAlerts(reservation(4, 0, nil), now))).
+			To(ConsistOf(computev1.CapacityReservationOverSubscribed))
+		end := now.Add(3 * 24 * time.Hour)
+		Expect(alertTypes(evaluateCapacityReservationAlerts(reservation(4, 2, &end), now))).
+			To(ConsistOf(computev1.CapacityReservationExpiringSoon))
+	})
+
+	It("should raise an alert once and resolve it when it stops firing", func() {
+		status := &computev1.CapacityReservationStatus{}
+		firing := []computev1.CapacityReservationAlert{{Type: computev1.CapacityReservationOverSubscribed, Message: "full"}}
+
+		raised, resolved := updateAlertHistory(status, firing, metav1.NewTime(now))
+		Expect(raised).To(HaveLen(1))
+		Expect(resolved).To(BeEmpty())
+
+		raised, _ = updateAlertHistory(status, firing, metav1.NewTime(now.Add(time.Minute)))
+		Expect(raised).To(BeEmpty())
+		Expect(status.AlertHistory).To(HaveLen(1))
+
+		_, resolved = updateAlertHistory(status, nil, metav1.NewTime(now.Add(time.Hour)))
+		Expect(resolved).To(HaveLen(1))
+		Expect(status.AlertHistory[0].ResolvedAt.Time).To(Equal(now.Add(time.Hour)))
+	})
+})
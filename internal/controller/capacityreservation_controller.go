@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// capacityReservationFinalizer makes sure the reservation is cancelled in AWS before the object is removed,
+// otherwise the reserved capacity keeps being billed.
+const capacityReservationFinalizer = "capacityreservation.compute.cloud.com"
+
+// CapacityReservationReconciler keeps an EC2 On-Demand Capacity Reservation in line with its CapacityReservation object.
+type CapacityReservationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=capacityreservations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=capacityreservations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=capacityreservations/finalizers,verbs=update
+
+// Reconcile creates, resizes and cancels the capacity reservation.
+func (r *CapacityReservationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	reservation := &computev1.CapacityReservation{}
+	if err := r.Get(ctx, req.NamespacedName, reservation); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	ec2Client := awsClient(reservation.Spec.Region)
+
+	if !reservation.DeletionTimestamp.IsZero() {
+		if reservation.Status.ReservationID != "" {
+			if err := cancelCapacityReservation(ctx, ec2Client, reservation.Status.ReservationID); err != nil {
+				l.Error(err, "Failed to cancel capacity reservation", "reservationID", reservation.Status.ReservationID)
+				return ctrl.Result{}, err
+			}
+			l.Info("Cancelled capacity reservation", "reservationID", reservation.Status.ReservationID)
+		}
+
+		controllerutil.RemoveFinalizer(reservation, capacityReservationFinalizer)
+		if err := r.Update(ctx, reservation); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if controllerutil.AddFinalizer(reservation, capacityReservationFinalizer) {
+		if err := r.Update(ctx, reservation); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if reservation.Status.ReservationID == "" {
+		input := &ec2.CreateCapacityReservationInput{
+			InstanceType:     aws.String(reservation.Spec.InstanceType),
+			InstanceCount:    aws.Int32(reservation.Spec.InstanceCount),
+			AvailabilityZone: aws.String(reservation.Spec.AvailabilityZone),
+			InstancePlatform: ec2types.CapacityReservationInstancePlatform(reservation.Spec.InstancePlatform),
+			Tenancy:          ec2types.CapacityReservationTenancy(reservation.Spec.Tenancy),
+			EndDateType:      ec2types.EndDateType(reservation.Spec.EndDateType),
+			// The UID makes the call idempotent if the status update below is lost.
+			ClientToken: aws.String(string(reservation.UID)),
+		}
+		if reservation.Spec.EndDate != nil {
+			input.EndDate = aws.Time(reservation.Spec.EndDate.Time)
+		}
+
+		result, err := ec2Client.CreateCapacityReservation(ctx, input)
+		if err != nil {
+			l.Error(err, "Failed to create capacity reservation")
+			return ctrl.Result{}, fmt.Errorf("failed to create capacity reservation: %w", err)
+		}
+		l.Info("Created capacity reservation", "reservationID", aws.ToString(result.CapacityReservation.CapacityReservationId))
+
+		reservation.Status.ReservationID = aws.ToString(result.CapacityReservation.CapacityReservationId)
+		setCapacityReservationStatus(&reservation.Status, result.CapacityReservation)
+		if err := r.Status().Update(ctx, reservation); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	describe, err := ec2Client.DescribeCapacityReservations(ctx, &ec2.DescribeCapacityReservationsInput{
+		CapacityReservationIds: []string{reservation.Status.ReservationID},
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to describe capacity reservation: %w", err)
+	}
+	if len(describe.CapacityReservations) == 0 {
+		return ctrl.Result{}, fmt.Errorf("capacity reservation %s not found", reservation.Status.ReservationID)
+	}
+	current := &describe.CapacityReservations[0]
+
+	originalStatus := reservation.Status.DeepCopy()
+	setCapacityReservationStatus(&reservation.Status, current)
+
+	// Count and end date are the only mutable attributes, and only while the reservation is active.
+	if current.State == ec2types.CapacityReservationStateActive && capacityReservationNeedsUpdate(reservation, current) {
+		l.Info("Modifying capacity reservation", "reservationID", reservation.Status.ReservationID,
+			"currentCount", aws.ToInt32(current.TotalInstanceCount), "desiredCount", reservation.Spec.InstanceCount)
+
+		input := &ec2.ModifyCapacityReservationInput{
+			CapacityReservationId: aws.String(reservation.Status.ReservationID),
+			InstanceCount:         aws.Int32(reservation.Spec.InstanceCount),
+			EndDateType:           ec2types.EndDateType(reservation.Spec.EndDateType),
+		}
+		if reservation.Spec.EndDateType == string(ec2types.EndDateTypeLimited) && reservation.Spec.EndDate != nil {
+			input.EndDate = aws.Time(reservation.Spec.EndDate.Time)
+		}
+		if _, err := ec2Client.ModifyCapacityReservation(ctx, input); err != nil {
+			l.Error(err, "Failed to modify capacity reservation")
+			return ctrl.Result{}, fmt.Errorf("failed to modify capacity reservation: %w", err)
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(*originalStatus, reservation.Status) {
+		if err := r.Status().Update(ctx, reservation); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Available capacity changes as instances launch into the reservation, so keep polling.
+	return ctrl.Result{RequeueAfter: time.Minute}, nil
+}
+
+// capacityReservationNeedsUpdate reports whether the instance count or end date in AWS differ from the spec.
+func capacityReservationNeedsUpdate(reservation *computev1.CapacityReservation, current *ec2types.CapacityReservation) bool {
+	if aws.ToInt32(current.TotalInstanceCount) != reservation.Spec.InstanceCount {
+		return true
+	}
+	if string(current.EndDateType) != reservation.Spec.EndDateType {
+		return true
+	}
+	if reservation.Spec.EndDateType == string(ec2types.EndDateTypeLimited) && reservation.Spec.EndDate != nil {
+		return current.EndDate == nil || !current.EndDate.Equal(reservation.Spec.EndDate.Time)
+	}
+	return false
+}
+
+// setCapacityReservationStatus copies the observed reservation state into status.
+func setCapacityReservationStatus(status *computev1.CapacityReservationStatus, current *ec2types.CapacityReservation) {
+	status.State = string(current.State)
+	status.TotalInstanceCount = aws.ToInt32(current.TotalInstanceCount)
+	status.AvailableInstanceCount = aws.ToInt32(current.AvailableInstanceCount)
+}
+
+// cancelCapacityReservation releases the reservation. Reservations that already ended are left alone.
+func cancelCapacityReservation(ctx context.Context, ec2Client *ec2.Client, reservationID string) error {
+	describe, err := ec2Client.DescribeCapacityReservations(ctx, &ec2.DescribeCapacityReservationsInput{
+		CapacityReservationIds: []string{reservationID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe capacity reservation: %w", err)
+	}
+	if len(describe.CapacityReservations) == 0 {
+		return nil
+	}
+	switch describe.CapacityReservations[0].State {
+	case ec2types.CapacityReservationStateCancelled, ec2types.CapacityReservationStateExpired, ec2types.CapacityReservationStateFailed:
+		return nil
+	}
+
+	_, err = ec2Client.CancelCapacityReservation(ctx, &ec2.CancelCapacityReservationInput{
+		CapacityReservationId: aws.String(reservationID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel capacity reservation: %w", err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CapacityReservationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.CapacityReservation{}).
+		Named("capacityreservation").
+		Complete(r)
+}
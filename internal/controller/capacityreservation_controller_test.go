@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("CapacityReservation Controller", func() {
+	Context("When comparing the spec with the reservation in AWS", func() {
+		endDate := time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+		reservation := &computev1.CapacityReservation{
+			Spec: computev1.CapacityReservationSpec{
+				InstanceCount: 2,
+				EndDateType:   "limited",
+				EndDate:       &metav1.Time{Time: endDate},
+			},
+		}
+
+		It("should not modify a reservation that matches", func() {
+			current := &ec2types.CapacityReservation{
+				TotalInstanceCount: aws.Int32(2),
+				EndDateType:        ec2types.EndDateTypeLimited,
+				EndDate:            aws.Time(endDate),
+			}
+			Expect(capacityReservationNeedsUpdate(reservation, current)).To(BeFalse())
+		})
+
+		It("should modify a reservation when the count changed", func() {
+			current := &ec2types.CapacityReservation{
+				TotalInstanceCount: aws.Int32(1),
+				EndDateType:        ec2types.EndDateTypeLimited,
+				EndDate:            aws.Time(endDate),
+			}
+			Expect(capacityReservationNeedsUpdate(reservation, current)).To(BeTrue())
+		})
+
+		It("should modify a reservation when the end date changed", func() {
+			current := &ec2types.CapacityReservation{
+				TotalInstanceCount: aws.Int32(2),
+				EndDateType:        ec2types.EndDateTypeUnlimited,
+			}
+			Expect(capacityReservationNeedsUpdate(reservation, current)).To(BeTrue())
+		})
+	})
+})
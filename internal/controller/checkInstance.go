@@ -5,25 +5,18 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
-	computev1 "github.com/bshaw7/operator-repo/api/v1"
+
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
 )
 
-func checkEC2InstanceExists(ctx context.Context, instanceID string, ec2Instance *computev1.Ec2Instance) (bool, *ec2types.Instance, error) {
-	// create the client for ec2 instance
+func checkEC2InstanceExists(ctx context.Context, ec2Client awsclient.EC2Client, instanceID string) (bool, *ec2types.Instance, error) {
 	fmt.Println("Checking instance ", instanceID)
-	ec2Client := awsClient(ec2Instance.Spec.Region)
 
+	// Stopped instances still exist; the caller decides what each state means.
 	input := &ec2.DescribeInstancesInput{
 		InstanceIds: []string{instanceID},
-		Filters: []ec2types.Filter{
-			{
-				Name:   aws.String("instance-state-name"),
-				Values: []string{"running"},
-			},
-		},
 	}
 
 	result, err := ec2Client.DescribeInstances(ctx, input)
@@ -39,7 +32,7 @@ func checkEC2InstanceExists(ctx context.Context, instanceID string, ec2Instance
 
 	// Check if we got any instances back
 	if len(result.Reservations) == 0 {
-		// No reservations means the instance is not found or not running
+		// No reservations means the instance is not found
 		return false, nil, nil
 	}
 	return true, &result.Reservations[0].Instances[0], nil
@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+var _ = Describe("Instance lifecycle calls", func() {
+	instanceIn := func(state ec2types.InstanceStateName) *ec2.DescribeInstancesOutput {
+		return &ec2.DescribeInstancesOutput{Reservations: []ec2types.Reservation{{Instances: []ec2types.Instance{{
+			InstanceId: aws.String("i-123"),
+			State:      &ec2types.InstanceState{Name: state},
+		}}}}}
+	}
+
+	DescribeTable("checkEC2InstanceExists",
+		func(output *ec2.DescribeInstancesOutput, describeErr error, exists bool, wantErr bool) {
+			fake := &awsclient.FakeEC2Client{
+				Outputs: map[string]any{"DescribeInstances": output},
+				Errors:  map[string]error{"DescribeInstances": describeErr},
+			}
+			found, awsInstance, err := checkEC2InstanceExists(context.Background(), fake, "i-123")
+			Expect(err != nil).To(Equal(wantErr))
+			Expect(found).To(Equal(exists))
+			Expect(awsInstance != nil).To(Equal(exists))
+			Expect(fake.CallsTo("DescribeInstances")).To(ConsistOf(&ec2.DescribeInstancesInput{InstanceIds: []string{"i-123"}}))
+		},
+		Entry("a running instance", instanceIn(ec2types.InstanceStateNameRunning), nil, true, false),
+		Entry("a stopped instance", instanceIn(ec2types.InstanceStateNameStopped), nil, true, false),
+		Entry("no reservations", &ec2.DescribeInstancesOutput{}, nil, false, false),
+		Entry("an unknown instance ID", nil, errors.New("api error InvalidInstanceID.NotFound"), false, false),
+		Entry("any other error", nil, errors.New("api error UnauthorizedOperation"), false, true),
+	)
+
+	It("Should terminate the instance and wait until it is gone", func() {
+		fake := &awsclient.FakeEC2Client{Outputs: map[string]any{
+			"TerminateInstances": &ec2.TerminateInstancesOutput{TerminatingInstances: []ec2types.InstanceStateChange{{
+				CurrentState: &ec2types.InstanceState{Name: ec2types.InstanceStateNameShuttingDown},
+			}}},
+			"DescribeInstances": instanceIn(ec2types.InstanceStateNameTerminated),
+		}}
+		inst := &computev1.Ec2Instance{Status: computev1.Ec2InstanceStatus{InstanceID: "i-123"}}
+
+		terminated, err := deleteEc2Instance(context.Background(), fake, inst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(terminated).To(BeTrue())
+		Expect(fake.CallsTo("TerminateInstances")).To(ConsistOf(&ec2.TerminateInstancesInput{InstanceIds: []string{"i-123"}}))
+	})
+})
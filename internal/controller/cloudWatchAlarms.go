@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+// cloudWatchQueryClient is the CloudWatch Query API of one region.
+type cloudWatchQueryClient struct {
+	api awsQueryService
+}
+
+var _ awsclient.CloudWatchClient = cloudWatchQueryClient{}
+
+// cloudWatch returns the CloudWatch API of region, called with credentials or, when nil, the
+// operator's own.
+func cloudWatch(region string, credentials aws.CredentialsProvider) cloudWatchQueryClient {
+	return cloudWatchQueryClient{api: awsQueryService{
+		Endpoint:    fmt.Sprintf("https://monitoring.%s.amazonaws.com", region),
+		SigningName: "monitoring",
+		Region:      region,
+		Version:     "2010-08-01",
+		Credentials: credentials,
+	}}
+}
+
+func (c cloudWatchQueryClient) PutMetricAlarm(ctx context.Context, alarm awsclient.MetricAlarm) error {
+	params := url.Values{
+		"AlarmName":                 {alarm.Name},
+		"AlarmDescription":          {alarm.Description},
+		"Namespace":                 {alarm.Namespace},
+		"MetricName":                {alarm.MetricName},
+		"Statistic":                 {alarm.Statistic},
+		"Period":                    {strconv.Itoa(int(alarm.PeriodSeconds))},
+		"EvaluationPeriods":         {strconv.Itoa(int(alarm.EvaluationPeriods))},
+		"Threshold":                 {strconv.FormatFloat(alarm.Threshold, 'f', -1, 64)},
+		"ComparisonOperator":        {alarm.ComparisonOperator},
+		"Dimensions.member.1.Name":  {"InstanceId"},
+		"Dimensions.member.1.Value": {alarm.InstanceID},
+	}
+	return c.api.call(ctx, "PutMetricAlarm", params, nil)
+}
+
+func (c cloudWatchQueryClient) DeleteAlarms(ctx context.Context, names []string) error {
+	params := url.Values{}
+	for i, name := range names {
+		params.Set(fmt.Sprintf("AlarmNames.member.%d", i+1), name)
+	}
+	return c.api.call(ctx, "DeleteAlarms", params, nil)
+}
+
+// instanceCloudWatchClient returns r.CloudWatchClient when it is set, and the CloudWatch API of the
+// instance's region and account otherwise.
+func (r *Ec2InstanceReconciler) instanceCloudWatchClient(ec2Instance *computev1.Ec2Instance) awsclient.CloudWatchClient {
+	if r.CloudWatchClient != nil {
+		return r.CloudWatchClient
+	}
+	return cloudWatch(ec2Instance.Spec.Region, instanceCredentials(ec2Instance))
+}
+
+// cloudWatchAlarmName is the name of the alarm created for spec on the instance.
+func cloudWatchAlarmName(spec computev1.CloudWatchAlarmSpec, instanceID string) string {
+	return fmt.Sprintf("%s-%s", spec.AlarmName, instanceID)
+}
+
+// desiredMetricAlarms returns the alarms spec.cloudWatchAlarms asks for on the current instance.
+func desiredMetricAlarms(ec2Instance *computev1.Ec2Instance) []awsclient.MetricAlarm {
+	alarms := make([]awsclient.MetricAlarm, 0, len(ec2Instance.Spec.CloudWatchAlarms))
+	for _, spec := range ec2Instance.Spec.CloudWatchAlarms {
+		alarm := awsclient.MetricAlarm{
+			Name:               cloudWatchAlarmName(spec, ec2Instance.Status.InstanceID),
+			Description:        fmt.Sprintf("%s of %s/%s, managed by ec2-operator", spec.MetricName, ec2Instance.Namespace, ec2Instance.Name),
+			Namespace:          spec.Namespace,
+			MetricName:         spec.MetricName,
+			InstanceID:         ec2Instance.Status.InstanceID,
+			Statistic:          spec.Statistic,
+			PeriodSeconds:      spec.PeriodSeconds,
+			EvaluationPeriods:  spec.EvaluationPeriods,
+			Threshold:          spec.Threshold,
+			ComparisonOperator: spec.ComparisonOperator,
+		}
+		// The defaults are only filled in by the API server.
+		if alarm.Namespace == "" {
+			alarm.Namespace = "AWS/EC2"
+		}
+		if alarm.Statistic == "" {
+			alarm.Statistic = "Average"
+		}
+		if alarm.PeriodSeconds == 0 {
+			alarm.PeriodSeconds = 300
+		}
+		if alarm.EvaluationPeriods == 0 {
+			alarm.EvaluationPeriods = 1
+		}
+		alarms = append(alarms, alarm)
+	}
+	return alarms
+}
+
+// reconcileCloudWatchAlarms puts the alarms of spec.cloudWatchAlarms once the instance is running,
+// deletes the ones no longer asked for and records the names in status.cloudWatchAlarmNames. The
+// alarms are put again whenever the spec changed, since PutMetricAlarm replaces an existing alarm.
+func (r *Ec2InstanceReconciler) reconcileCloudWatchAlarms(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	if awsInstance.State == nil || awsInstance.State.Name != ec2types.InstanceStateNameRunning {
+		return nil
+	}
+	desired := desiredMetricAlarms(ec2Instance)
+	var names []string
+	for _, alarm := range desired {
+		names = append(names, alarm.Name)
+	}
+	if slices.Equal(names, ec2Instance.Status.CloudWatchAlarmNames) &&
+		(len(names) == 0 || ec2Instance.Status.ObservedGeneration == ec2Instance.Generation) {
+		return nil
+	}
+
+	client := r.instanceCloudWatchClient(ec2Instance)
+	var kept, stale []string
+	for _, name := range ec2Instance.Status.CloudWatchAlarmNames {
+		if slices.Contains(names, name) {
+			kept = append(kept, name)
+		} else {
+			stale = append(stale, name)
+		}
+	}
+	if len(stale) > 0 {
+		if err := client.DeleteAlarms(ctx, stale); err != nil {
+			return fmt.Errorf("failed to delete CloudWatch alarms: %w", err)
+		}
+		log.FromContext(ctx).Info("Deleted CloudWatch alarms", "alarms", stale)
+	}
+
+	// Record every alarm put so far, so a failed put does not leak the ones before it.
+	ec2Instance.Status.CloudWatchAlarmNames = kept
+	for _, alarm := range desired {
+		if err := client.PutMetricAlarm(ctx, alarm); err != nil {
+			return fmt.Errorf("failed to put CloudWatch alarm %s: %w", alarm.Name, err)
+		}
+		if !slices.Contains(ec2Instance.Status.CloudWatchAlarmNames, alarm.Name) {
+			ec2Instance.Status.CloudWatchAlarmNames = append(ec2Instance.Status.CloudWatchAlarmNames, alarm.Name)
+		}
+	}
+	if len(names) > 0 {
+		log.FromContext(ctx).Info("Put CloudWatch alarms", "alarms", names)
+	}
+	ec2Instance.Status.CloudWatchAlarmNames = names
+	return nil
+}
+
+// deleteCloudWatchAlarms deletes the alarms recorded in status.cloudWatchAlarmNames.
+func (r *Ec2InstanceReconciler) deleteCloudWatchAlarms(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	if len(ec2Instance.Status.CloudWatchAlarmNames) == 0 {
+		return nil
+	}
+	if err := r.instanceCloudWatchClient(ec2Instance).DeleteAlarms(ctx, ec2Instance.Status.CloudWatchAlarmNames); err != nil {
+		return fmt.Errorf("failed to delete CloudWatch alarms: %w", err)
+	}
+	log.FromContext(ctx).Info("Deleted CloudWatch alarms", "alarms", ec2Instance.Status.CloudWatchAlarmNames)
+	ec2Instance.Status.CloudWatchAlarmNames = nil
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+var _ = Describe("CloudWatch alarms", func() {
+	ctx := context.Background()
+	running := &ec2types.Instance{State: &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning}}
+	var (
+		fake *awsclient.FakeCloudWatchClient
+		r    *Ec2InstanceReconciler
+		inst *computev1.Ec2Instance
+	)
+
+	BeforeEach(func() {
+		fake = &awsclient.FakeCloudWatchClient{}
+		r = &Ec2InstanceReconciler{CloudWatchClient: fake}
+		inst = &computev1.Ec2Instance{}
+		inst.Generation = 1
+		inst.Status.InstanceID = "i-1"
+		inst.Spec.CloudWatchAlarms = []computev1.CloudWatchAlarmSpec{
+			{AlarmName: "cpu-high", MetricName: "CPUUtilization", Threshold: 80, ComparisonOperator: "GreaterThanThreshold"},
+		}
+	})
+
+	It("should put the alarms on the instance once it is running", func() {
+		pending := &ec2types.Instance{State: &ec2types.InstanceState{Name: ec2types.InstanceStateNamePending}}
+		Expect(r.reconcileCloudWatchAlarms(ctx, inst, pending)).To(Succeed())
+		Expect(fake.Alarms()).To(BeEmpty())
+
+		Expect(r.reconcileCloudWatchAlarms(ctx, inst, running)).To(Succeed())
+		Expect(inst.Status.CloudWatchAlarmNames).To(Equal([]string{"cpu-high-i-1"}))
+		alarm := fake.Alarms()["cpu-high-i-1"]
+		Expect(alarm.InstanceID).To(Equal("i-1"))
+		Expect(alarm.Namespace).To(Equal("AWS/EC2"))
+		Expect(alarm.PeriodSeconds).To(Equal(int32(300)))
+		Expect(alarm.EvaluationPeriods).To(Equal(int32(1)))
+	})
+
+	It("should delete the alarms that are no longer asked for", func() {
+		Expect(r.reconcileCloudWatchAlarms(ctx, inst, running)).To(Succeed())
+		inst.Generation = 2
+		inst.Spec.CloudWatchAlarms[0].AlarmName = "cpu-very-high"
+		Expect(r.reconcileCloudWatchAlarms(ctx, inst, running)).To(Succeed())
+		Expect(fake.Alarms()).To(HaveLen(1))
+		Expect(fake.Alarms()).To(HaveKey("cpu-very-high-i-1"))
+		Expect(inst.Status.CloudWatchAlarmNames).To(Equal([]string{"cpu-very-high-i-1"}))
+
+		Expect(r.deleteCloudWatchAlarms(ctx, inst)).To(Succeed())
+		Expect(fake.Alarms()).To(BeEmpty())
+		Expect(inst.Status.CloudWatchAlarmNames).To(BeEmpty())
+	})
+
+	It("should keep track of the alarms put before a failure", func() {
+		inst.Spec.CloudWatchAlarms = append(inst.Spec.CloudWatchAlarms,
+			computev1.CloudWatchAlarmSpec{AlarmName: "status", MetricName: "StatusCheckFailed", Threshold: 1, ComparisonOperator: "GreaterThanOrEqualToThreshold"})
+		Expect(r.reconcileCloudWatchAlarms(ctx, inst, running)).To(Succeed())
+
+		inst.Generation = 2
+		fake.Errors = map[string]error{"PutMetricAlarm": errors.New("Throttling")}
+		Expect(r.reconcileCloudWatchAlarms(ctx, inst, running)).To(MatchError(ContainSubstring("Throttling")))
+		Expect(inst.Status.CloudWatchAlarmNames).To(ConsistOf("cpu-high-i-1", "status-i-1"))
+	})
+})
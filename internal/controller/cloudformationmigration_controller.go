@@ -0,0 +1,265 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// cloudFormation returns the CloudFormation Query API of region.
+func cloudFormation(region string) awsQueryService {
+	return awsQueryService{
+		Endpoint:    fmt.Sprintf("https://cloudformation.%s.amazonaws.com", region),
+		SigningName: "cloudformation",
+		Region:      region,
+		Version:     "2010-05-15",
+	}
+}
+
+// describeStackResourceResponse is the part of the DescribeStackResource response the migration needs.
+type describeStackResourceResponse struct {
+	Result struct {
+		Detail struct {
+			PhysicalResourceID string `xml:"PhysicalResourceId"`
+			ResourceType       string `xml:"ResourceType"`
+			ResourceStatus     string `xml:"ResourceStatus"`
+		} `xml:"StackResourceDetail"`
+	} `xml:"DescribeStackResourceResult"`
+}
+
+// describeStacksResponse is the part of the DescribeStacks response the migration needs.
+type describeStacksResponse struct {
+	Result struct {
+		Stacks []struct {
+			StackStatus string `xml:"StackStatus"`
+		} `xml:"Stacks>member"`
+	} `xml:"DescribeStacksResult"`
+}
+
+// CloudFormationMigrationReconciler hands EC2 instances managed by CloudFormation stacks over to the operator.
+type CloudFormationMigrationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=cloudformationmigrations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=cloudformationmigrations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch;create
+
+// Reconcile looks the instance up in the stack, creates an Ec2Instance that adopts it and, if asked
+// to, has CloudFormation skip the resource.
+func (r *CloudFormationMigrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	migration := &computev1.CloudFormationMigration{}
+	if err := r.Get(ctx, req.NamespacedName, migration); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	l.Info("Reconciling CloudFormationMigration", "phase", migration.Status.Phase, "stack", migration.Spec.StackName)
+
+	switch migration.Status.Phase {
+	case "", computev1.CloudFormationMigrationPhasePending:
+		return r.lookUpStackResource(ctx, migration)
+	case computev1.CloudFormationMigrationPhaseAdopting:
+		return r.createEc2Instance(ctx, migration)
+	default:
+		// Completed and Failed are terminal.
+		return ctrl.Result{}, nil
+	}
+}
+
+// setPhase records the new phase and message. The status write triggers the next reconcile.
+func (r *CloudFormationMigrationReconciler) setPhase(ctx context.Context, migration *computev1.CloudFormationMigration, phase computev1.CloudFormationMigrationPhase, message string) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("CloudFormationMigration phase transition", "from", migration.Status.Phase, "to", phase, "message", message)
+
+	migration.Status.Phase = phase
+	migration.Status.Message = message
+	if err := r.Status().Update(ctx, migration); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// lookUpStackResource resolves the logical ID to the instance ID.
+func (r *CloudFormationMigrationReconciler) lookUpStackResource(ctx context.Context, migration *computev1.CloudFormationMigration) (ctrl.Result, error) {
+	resp := describeStackResourceResponse{}
+	err := cloudFormation(migration.Spec.Region).call(ctx, "DescribeStackResource", url.Values{
+		"StackName":         {migration.Spec.StackName},
+		"LogicalResourceId": {migration.Spec.ResourceLogicalID},
+	}, &resp)
+	if err != nil {
+		if strings.Contains(err.Error(), "ValidationError") {
+			// The stack or the resource does not exist.
+			return r.setPhase(ctx, migration, computev1.CloudFormationMigrationPhaseFailed, err.Error())
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to describe stack resource: %w", err)
+	}
+
+	detail := resp.Result.Detail
+	if detail.ResourceType != "AWS::EC2::Instance" {
+		return r.setPhase(ctx, migration, computev1.CloudFormationMigrationPhaseFailed,
+			fmt.Sprintf("resource %s is a %s, not an AWS::EC2::Instance", migration.Spec.ResourceLogicalID, detail.ResourceType))
+	}
+	if detail.PhysicalResourceID == "" {
+		return r.setPhase(ctx, migration, computev1.CloudFormationMigrationPhaseFailed,
+			fmt.Sprintf("resource %s has not been created (status %s)", migration.Spec.ResourceLogicalID, detail.ResourceStatus))
+	}
+
+	migration.Status.InstanceID = detail.PhysicalResourceID
+	migration.Status.Ec2InstanceName = migrationInstanceName(migration)
+	return r.setPhase(ctx, migration, computev1.CloudFormationMigrationPhaseAdopting,
+		fmt.Sprintf("adopting instance %s as %s/%s", detail.PhysicalResourceID, migration.Spec.TargetNamespace, migration.Status.Ec2InstanceName))
+}
+
+// createEc2Instance creates the Ec2Instance that adopts the instance and then completes the migration.
+func (r *CloudFormationMigrationReconciler) createEc2Instance(ctx context.Context, migration *computev1.CloudFormationMigration) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	key := types.NamespacedName{Namespace: migration.Spec.TargetNamespace, Name: migration.Status.Ec2InstanceName}
+	existing := &computev1.Ec2Instance{}
+	err := r.Get(ctx, key, existing)
+	switch {
+	case err == nil:
+		if existing.Spec.AdoptInstanceID != migration.Status.InstanceID && existing.Status.InstanceID != migration.Status.InstanceID {
+			return r.setPhase(ctx, migration, computev1.CloudFormationMigrationPhaseFailed,
+				fmt.Sprintf("Ec2Instance %s already exists and manages a different instance", key))
+		}
+	case errors.IsNotFound(err):
+		exists, awsInstance, err := checkEC2InstanceExists(ctx, awsClient(migration.Spec.Region), migration.Status.InstanceID)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !exists {
+			return r.setPhase(ctx, migration, computev1.CloudFormationMigrationPhaseFailed,
+				fmt.Sprintf("instance %s no longer exists", migration.Status.InstanceID))
+		}
+
+		ec2Instance := &computev1.Ec2Instance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Annotations: map[string]string{
+					computev1.MigratedFromStackAnnotation: migration.Spec.StackName + "/" + migration.Spec.ResourceLogicalID,
+				},
+			},
+			Spec: specFromAWSInstance(awsInstance, migration.Spec.Region),
+		}
+		if err := r.Create(ctx, ec2Instance); err != nil {
+			l.Error(err, "Failed to create Ec2Instance")
+			return ctrl.Result{}, err
+		}
+		l.Info("Created Ec2Instance for stack resource", "ec2Instance", key, "instanceID", migration.Status.InstanceID)
+	default:
+		return ctrl.Result{}, err
+	}
+
+	message := fmt.Sprintf("instance %s is managed by Ec2Instance %s", migration.Status.InstanceID, key)
+	if migration.Spec.RemoveFromStack && !migration.Status.RemovedFromStack {
+		removed, note, err := removeFromStack(ctx, migration)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		migration.Status.RemovedFromStack = removed
+		message += "; " + note
+	}
+	return r.setPhase(ctx, migration, computev1.CloudFormationMigrationPhaseCompleted, message)
+}
+
+// removeFromStack has CloudFormation skip the resource, which is only possible while the stack is
+// stuck in UPDATE_ROLLBACK_FAILED. It returns whether the resource was skipped and a note for the status.
+func removeFromStack(ctx context.Context, migration *computev1.CloudFormationMigration) (bool, string, error) {
+	cfn := cloudFormation(migration.Spec.Region)
+
+	stacks := describeStacksResponse{}
+	if err := cfn.call(ctx, "DescribeStacks", url.Values{"StackName": {migration.Spec.StackName}}, &stacks); err != nil {
+		return false, "", fmt.Errorf("failed to describe stack: %w", err)
+	}
+	if len(stacks.Result.Stacks) == 0 {
+		return false, "", fmt.Errorf("stack %s not found", migration.Spec.StackName)
+	}
+	status := stacks.Result.Stacks[0].StackStatus
+	if status != "UPDATE_ROLLBACK_FAILED" {
+		return false, fmt.Sprintf("stack is %s so the resource cannot be skipped; set DeletionPolicy: Retain on %s and remove it from the template",
+			status, migration.Spec.ResourceLogicalID), nil
+	}
+
+	err := cfn.call(ctx, "ContinueUpdateRollback", url.Values{
+		"StackName":                {migration.Spec.StackName},
+		"ResourcesToSkip.member.1": {migration.Spec.ResourceLogicalID},
+	}, nil)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to continue update rollback: %w", err)
+	}
+	log.FromContext(ctx).Info("Skipped resource in stack rollback", "stack", migration.Spec.StackName, "resource", migration.Spec.ResourceLogicalID)
+	return true, "removed from the stack", nil
+}
+
+// invalidNameChars matches everything that may not appear in an object name.
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// migrationInstanceName is spec.instanceName, or the logical ID turned into a valid object name.
+func migrationInstanceName(migration *computev1.CloudFormationMigration) string {
+	if migration.Spec.InstanceName != "" {
+		return migration.Spec.InstanceName
+	}
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(migration.Spec.ResourceLogicalID), "-")
+	return strings.Trim(name, "-")
+}
+
+// specFromAWSInstance describes an existing instance as an Ec2InstanceSpec that adopts it, so the
+// operator does not try to change anything right after the takeover.
+func specFromAWSInstance(awsInstance *ec2types.Instance, region string) computev1.Ec2InstanceSpec {
+	spec := computev1.Ec2InstanceSpec{
+		InstanceType:    string(awsInstance.InstanceType),
+		AMIId:           aws.ToString(awsInstance.ImageId),
+		Region:          region,
+		KeyPair:         aws.ToString(awsInstance.KeyName),
+		Subnet:          aws.ToString(awsInstance.SubnetId),
+		AdoptInstanceID: aws.ToString(awsInstance.InstanceId),
+		AutoRecovery:    true,
+	}
+	if awsInstance.Placement != nil {
+		spec.AvailabilityZone = aws.ToString(awsInstance.Placement.AvailabilityZone)
+	}
+	for _, group := range awsInstance.SecurityGroups {
+		spec.SecurityGroups = append(spec.SecurityGroups, aws.ToString(group.GroupId))
+	}
+	for _, tag := range awsInstance.Tags {
+		key := aws.ToString(tag.Key)
+		// aws: tags, including the CloudFormation stack tags, are reserved and cannot be set.
+		if strings.HasPrefix(key, "aws:") {
+			continue
+		}
+		if spec.Tags == nil {
+			spec.Tags = map[string]string{}
+		}
+		spec.Tags[key] = aws.ToString(tag.Value)
+	}
+	return spec
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CloudFormationMigrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.CloudFormationMigration{}).
+		Named("cloudformationmigration").
+		Complete(r)
+}
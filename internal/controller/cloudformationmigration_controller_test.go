@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/xml"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("CloudFormationMigration Controller", func() {
+	Context("When naming the Ec2Instance", func() {
+		It("should use spec.instanceName when set", func() {
+			migration := &computev1.CloudFormationMigration{Spec: computev1.CloudFormationMigrationSpec{
+				ResourceLogicalID: "WebServer", InstanceName: "web",
+			}}
+			Expect(migrationInstanceName(migration)).To(Equal("web"))
+		})
+
+		It("should turn the logical ID into a valid object name", func() {
+			migration := &computev1.CloudFormationMigration{Spec: computev1.CloudFormationMigrationSpec{
+				ResourceLogicalID: "WebServer_01",
+			}}
+			Expect(migrationInstanceName(migration)).To(Equal("webserver-01"))
+		})
+	})
+
+	Context("When describing the stack instance as a spec", func() {
+		It("should adopt the instance and keep its settings", func() {
+			awsInstance := &ec2types.Instance{
+				InstanceId:     aws.String("i-0123"),
+				InstanceType:   ec2types.InstanceTypeT3Micro,
+				ImageId:        aws.String("ami-0abc"),
+				KeyName:        aws.String("ops"),
+				SubnetId:       aws.String("subnet-1"),
+				Placement:      &ec2types.Placement{AvailabilityZone: aws.String("us-east-1a")},
+				SecurityGroups: []ec2types.GroupIdentifier{{GroupId: aws.String("sg-1")}},
+				Tags: []ec2types.Tag{
+					{Key: aws.String("team"), Value: aws.String("web")},
+					{Key: aws.String("aws:cloudformation:stack-name"), Value: aws.String("legacy-web")},
+				},
+			}
+
+			spec := specFromAWSInstance(awsInstance, "us-east-1")
+			Expect(spec.AdoptInstanceID).To(Equal("i-0123"))
+			Expect(spec.InstanceType).To(Equal("t3.micro"))
+			Expect(spec.AMIId).To(Equal("ami-0abc"))
+			Expect(spec.Region).To(Equal("us-east-1"))
+			Expect(spec.AvailabilityZone).To(Equal("us-east-1a"))
+			Expect(spec.SecurityGroups).To(Equal([]string{"sg-1"}))
+			Expect(spec.Tags).To(Equal(map[string]string{"team": "web"}))
+		})
+	})
+
+	Context("When decoding CloudFormation responses", func() {
+		It("should read the physical ID of the stack resource", func() {
+			body := `<DescribeStackResourceResponse xmlns="http://cloudformation.amazonaws.com/doc/2010-05-15/">
+  <DescribeStackResourceResult>
+    <StackResourceDetail>
+      <LogicalResourceId>WebServer</LogicalResourceId>
+      <PhysicalResourceId>i-0123</PhysicalResourceId>
+      <ResourceType>AWS::EC2::Instance</ResourceType>
+      <ResourceStatus>CREATE_COMPLETE</ResourceStatus>
+    </StackResourceDetail>
+  </DescribeStackResourceResult>
+</DescribeStackResourceResponse>`
+			resp := describeStackResourceResponse{}
+			Expect(xml.Unmarshal([]byte(body), &resp)).To(Succeed())
+			Expect(resp.Result.Detail.PhysicalResourceID).To(Equal("i-0123"))
+			Expect(resp.Result.Detail.ResourceType).To(Equal("AWS::EC2::Instance"))
+		})
+
+		It("should read the stack status", func() {
+			body := `<DescribeStacksResponse><DescribeStacksResult><Stacks><member>
+  <StackName>legacy-web</StackName><StackStatus>UPDATE_ROLLBACK_FAILED</StackStatus>
+</member></Stacks></DescribeStacksResult></DescribeStacksResponse>`
+			resp := describeStacksResponse{}
+			Expect(xml.Unmarshal([]byte(body), &resp)).To(Succeed())
+			Expect(resp.Result.Stacks).To(HaveLen(1))
+			Expect(resp.Result.Stacks[0].StackStatus).To(Equal("UPDATE_ROLLBACK_FAILED"))
+		})
+	})
+})
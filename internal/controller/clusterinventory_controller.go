@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// InventoryReconciler keeps the singleton ClusterInventory object in sync with all Ec2Instance
+// objects in the cluster. Every Ec2Instance change enqueues the singleton, so the inventory is
+// recomputed from scratch each time; this keeps the logic simple and avoids drift.
+type InventoryReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=clusterinventories,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=clusterinventories/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch
+
+// Reconcile recomputes the ClusterInventory status from the current list of Ec2Instance objects.
+func (r *InventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	// Only the singleton is managed; anything else is rejected by the CRD validation anyway.
+	if req.Name != computev1.ClusterInventoryName {
+		return ctrl.Result{}, nil
+	}
+
+	inventory := &computev1.ClusterInventory{}
+	if err := r.Get(ctx, req.NamespacedName, inventory); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		// The inventory is created lazily by the operator the first time an Ec2Instance shows up.
+		inventory.Name = computev1.ClusterInventoryName
+		if err := r.Create(ctx, inventory); err != nil {
+			l.Error(err, "Failed to create ClusterInventory")
+			return ctrl.Result{}, err
+		}
+		l.Info("Created ClusterInventory", "name", inventory.Name)
+	}
+
+	instances := &computev1.Ec2InstanceList{}
+	if err := r.List(ctx, instances); err != nil {
+		l.Error(err, "Failed to list Ec2Instances")
+		return ctrl.Result{}, err
+	}
+
+	inventory.Status = buildInventoryStatus(instances.Items)
+	now := metav1.Now()
+	inventory.Status.LastUpdated = &now
+
+	if err := r.Status().Update(ctx, inventory); err != nil {
+		l.Error(err, "Failed to update ClusterInventory status")
+		return ctrl.Result{}, err
+	}
+
+	l.Info("ClusterInventory updated",
+		"total", inventory.Status.TotalInstances,
+		"running", inventory.Status.RunningInstances,
+		"monthlyCostUSD", inventory.Status.TotalMonthlyCostUSD)
+	return ctrl.Result{}, nil
+}
+
+// buildInventoryStatus aggregates the given instances into totals and per-region / per-namespace breakdowns.
+func buildInventoryStatus(instances []computev1.Ec2Instance) computev1.ClusterInventoryStatus {
+	status := computev1.ClusterInventoryStatus{
+		ByRegion:    map[string]computev1.InventoryBreakdown{},
+		ByNamespace: map[string]computev1.InventoryBreakdown{},
+	}
+
+	for i := range instances {
+		inst := &instances[i]
+
+		var total computev1.InventoryBreakdown
+		addToBreakdown(&total, inst)
+		status.TotalInstances += total.TotalInstances
+		status.RunningInstances += total.RunningInstances
+		status.StoppedInstances += total.StoppedInstances
+		status.TerminatedInstances += total.TerminatedInstances
+		status.FailedInstances += total.FailedInstances
+		status.TotalMonthlyCostUSD += total.MonthlyCostUSD
+
+		region := status.ByRegion[inst.Spec.Region]
+		addToBreakdown(&region, inst)
+		status.ByRegion[inst.Spec.Region] = region
+
+		namespace := status.ByNamespace[inst.Namespace]
+		addToBreakdown(&namespace, inst)
+		status.ByNamespace[inst.Namespace] = namespace
+	}
+	return status
+}
+
+// addToBreakdown counts a single instance into b. Only running instances contribute to the cost estimate.
+func addToBreakdown(b *computev1.InventoryBreakdown, inst *computev1.Ec2Instance) {
+	b.TotalInstances++
+	switch inventoryState(inst) {
+	case "running":
+		b.RunningInstances++
+		if cost, ok := EstimateMonthlyCostUSD(inst); ok {
+			b.MonthlyCostUSD += cost
+		}
+	case "stopped":
+		b.StoppedInstances++
+	case "terminated":
+		b.TerminatedInstances++
+	case "failed":
+		b.FailedInstances++
+	}
+}
+
+// inventoryState folds the raw AWS state reported in status into the buckets used by the inventory.
+// "Terminated" (capitalised) is what the Ec2Instance reconciler writes when the AWS instance vanished
+// underneath it, so it is reported as failed rather than as a normal termination.
+func inventoryState(inst *computev1.Ec2Instance) string {
+	switch inst.Status.State {
+	case "running":
+		return "running"
+	case "stopping", "stopped":
+		return "stopped"
+	case "shutting-down", "terminated":
+		return "terminated"
+	case "Terminated":
+		return "failed"
+	default:
+		return "pending"
+	}
+}
+
+// SetupWithManager registers the InventoryReconciler with the manager.
+// Every Ec2Instance event is mapped onto the singleton ClusterInventory so it is recomputed on each change.
+func (r *InventoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// Status writes bump the resource version but not the generation, so ignoring them here
+		// stops the controller from re-triggering itself on every LastUpdated change.
+		For(&computev1.ClusterInventory{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&computev1.Ec2Instance{}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, obj client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: computev1.ClusterInventoryName}}}
+			})).
+		Named("clusterinventory").
+		Complete(r)
+}
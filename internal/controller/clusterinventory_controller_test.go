@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("ClusterInventory Controller", func() {
+	Context("When reconciling the inventory", func() {
+		ctx := context.Background()
+
+		inventoryName := types.NamespacedName{Name: computev1.ClusterInventoryName}
+
+		AfterEach(func() {
+			By("Cleaning up the Ec2Instances and the inventory")
+			Expect(k8sClient.DeleteAllOf(ctx, &computev1.Ec2Instance{}, client.InNamespace("default"))).To(Succeed())
+			inventory := &computev1.ClusterInventory{}
+			if err := k8sClient.Get(ctx, inventoryName, inventory); err == nil {
+				Expect(k8sClient.Delete(ctx, inventory)).To(Succeed())
+			}
+		})
+
+		It("should create the singleton and aggregate instance states", func() {
+			By("creating a running and a stopped Ec2Instance")
+			for name, state := range map[string]string{"inventory-running": "running", "inventory-stopped": "stopped"} {
+				instance := &computev1.Ec2Instance{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec:       computev1.Ec2InstanceSpec{InstanceType: "t3.micro", AMIId: "ami-123", Region: "us-east-1"},
+				}
+				Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+				instance.Status.State = state
+				Expect(k8sClient.Status().Update(ctx, instance)).To(Succeed())
+			}
+
+			By("Reconciling the inventory")
+			controllerReconciler := &InventoryReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: inventoryName})
+			Expect(err).NotTo(HaveOccurred())
+
+			inventory := &computev1.ClusterInventory{}
+			Expect(k8sClient.Get(ctx, inventoryName, inventory)).To(Succeed())
+			Expect(inventory.Status.TotalInstances).To(Equal(2))
+			Expect(inventory.Status.RunningInstances).To(Equal(1))
+			Expect(inventory.Status.StoppedInstances).To(Equal(1))
+			Expect(inventory.Status.ByRegion["us-east-1"].TotalInstances).To(Equal(2))
+			Expect(inventory.Status.ByNamespace["default"].RunningInstances).To(Equal(1))
+			Expect(inventory.Status.TotalMonthlyCostUSD).To(BeNumerically(">", 0))
+			Expect(inventory.Status.LastUpdated).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// fetchConsoleOutputAnnotation set to "true" asks the operator to fetch the console output of the
+	// instance once. The operator removes the annotation when done.
+	fetchConsoleOutputAnnotation = "ec2instance.compute.cloud.com/fetch-console-output"
+	// consoleOutputKey is the Secret key that holds the console output.
+	consoleOutputKey = "console-output"
+	// consoleOutputFetchedAtAnnotation records on the Secret when the output was fetched.
+	consoleOutputFetchedAtAnnotation = "ec2instance.compute.cloud.com/fetched-at"
+)
+
+// fetchConsoleOutput stores the console output of the instance in the <name>-console-output Secret,
+// points status.consoleOutputSecretRef at it and removes the fetch-console-output annotation.
+// The Secret is owned by the Ec2Instance, so it goes away with it.
+func (r *Ec2InstanceReconciler) fetchConsoleOutput(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	l := log.FromContext(ctx)
+
+	result, err := instanceAWSClient(ec2Instance).GetConsoleOutput(ctx, &ec2.GetConsoleOutputInput{
+		InstanceId: aws.String(ec2Instance.Status.InstanceID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get console output: %w", err)
+	}
+	output, err := base64.StdEncoding.DecodeString(aws.ToString(result.Output))
+	if err != nil {
+		return fmt.Errorf("failed to decode console output: %w", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ec2Instance.Name + "-console-output",
+			Namespace: ec2Instance.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[consoleOutputFetchedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{consoleOutputKey: output}
+		return controllerutil.SetControllerReference(ec2Instance, secret, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to store console output: %w", err)
+	}
+	l.Info("Stored console output", "secret", secret.Name, "bytes", len(output))
+
+	if ec2Instance.Status.ConsoleOutputSecretRef == nil || ec2Instance.Status.ConsoleOutputSecretRef.Name != secret.Name {
+		ec2Instance.Status.ConsoleOutputSecretRef = &corev1.LocalObjectReference{Name: secret.Name}
+		if err := r.Status().Update(ctx, ec2Instance); err != nil {
+			return err
+		}
+	}
+
+	// The status update above bumped the resourceVersion, so this update does not conflict with it.
+	delete(ec2Instance.Annotations, fetchConsoleOutputAnnotation)
+	return r.Update(ctx, ec2Instance)
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// captureScreenshotAnnotation set to "true" asks the operator to capture a console screenshot of
+	// the instance once. The operator removes the annotation when done.
+	captureScreenshotAnnotation = "ec2instance.compute.cloud.com/capture-screenshot"
+	// screenshotKey is the Secret key that holds the PNG image.
+	screenshotKey = "screenshot.png"
+)
+
+// captureScreenshot stores a console screenshot of the instance in the <name>-screenshot Secret,
+// records it in status and removes the capture-screenshot annotation. Like the console output,
+// the Secret is owned by the Ec2Instance.
+func (r *Ec2InstanceReconciler) captureScreenshot(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	result, err := instanceAWSClient(ec2Instance).GetConsoleScreenshot(ctx, &ec2.GetConsoleScreenshotInput{
+		InstanceId: aws.String(ec2Instance.Status.InstanceID),
+		WakeUp:     aws.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get console screenshot: %w", err)
+	}
+	image, err := base64.StdEncoding.DecodeString(aws.ToString(result.ImageData))
+	if err != nil {
+		return fmt.Errorf("failed to decode console screenshot: %w", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ec2Instance.Name + "-screenshot",
+			Namespace: ec2Instance.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{screenshotKey: image}
+		return controllerutil.SetControllerReference(ec2Instance, secret, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to store console screenshot: %w", err)
+	}
+	log.FromContext(ctx).Info("Stored console screenshot", "secret", secret.Name, "bytes", len(image))
+
+	now := metav1.Now()
+	ec2Instance.Status.ScreenshotSecretRef = &corev1.LocalObjectReference{Name: secret.Name}
+	ec2Instance.Status.ScreenshotCapturedAt = &now
+	if err := r.Status().Update(ctx, ec2Instance); err != nil {
+		return err
+	}
+
+	// The status update above bumped the resourceVersion, so this update does not conflict with it.
+	delete(ec2Instance.Annotations, captureScreenshotAnnotation)
+	return r.Update(ctx, ec2Instance)
+}
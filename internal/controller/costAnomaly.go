@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// costAllocationTagKey is put on every instance with cost anomaly detection enabled. The anomaly
+// monitor filters on it, so it has to be activated as a cost allocation tag in the billing console.
+const costAllocationTagKey = "ec2instance.compute.cloud.com/instance-id"
+
+// costExplorer is the Cost Explorer API, which also hosts Cost Anomaly Detection. It is a global
+// service served from us-east-1.
+var costExplorer = awsJSONService{
+	Endpoint:     "https://ce.us-east-1.amazonaws.com",
+	SigningName:  "ce",
+	Region:       "us-east-1",
+	TargetPrefix: "AWSInsightsIndexService",
+}
+
+// instanceCostExplorer returns the Cost Explorer API of the account the instance runs in.
+func instanceCostExplorer(ec2Instance *computev1.Ec2Instance) awsJSONService {
+	ce := costExplorer
+	ce.Credentials = instanceCredentials(ec2Instance)
+	return ce
+}
+
+// reconcileCostAnomalyDetection creates or removes the anomaly monitor and subscription of the
+// instance so they match spec.costAnomalyDetection.
+func reconcileCostAnomalyDetection(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	if !ec2Instance.Spec.CostAnomalyDetection.Enabled {
+		return deleteCostAnomalyDetection(ctx, ec2Instance)
+	}
+	if ec2Instance.Status.AnomalyMonitorARN != "" && ec2Instance.Status.AnomalySubscriptionARN != "" {
+		return nil
+	}
+
+	l := log.FromContext(ctx)
+	instanceID := ec2Instance.Status.InstanceID
+	ce := instanceCostExplorer(ec2Instance)
+
+	if ec2Instance.Status.AnomalyMonitorARN == "" {
+		_, err := instanceAWSClient(ec2Instance).CreateTags(ctx, &ec2.CreateTagsInput{
+			Resources: []string{instanceID},
+			Tags:      []ec2types.Tag{{Key: aws.String(costAllocationTagKey), Value: aws.String(instanceID)}},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to tag instance for cost allocation: %w", err)
+		}
+
+		out := struct{ MonitorArn string }{}
+		err = ce.call(ctx, "CreateAnomalyMonitor", map[string]any{
+			"AnomalyMonitor": map[string]any{
+				"MonitorName": fmt.Sprintf("%s-%s-%s", ec2Instance.Namespace, ec2Instance.Name, instanceID),
+				"MonitorType": "CUSTOM",
+				"MonitorSpecification": map[string]any{
+					"Tags": map[string]any{"Key": costAllocationTagKey, "Values": []string{instanceID}},
+				},
+			},
+		}, &out)
+		if err != nil {
+			return fmt.Errorf("failed to create anomaly monitor: %w", err)
+		}
+		l.Info("Created cost anomaly monitor", "instanceID", instanceID, "monitorARN", out.MonitorArn)
+		ec2Instance.Status.AnomalyMonitorARN = out.MonitorArn
+	}
+
+	// SNS subscribers only support immediate notifications.
+	out := struct{ SubscriptionArn string }{}
+	err := ce.call(ctx, "CreateAnomalySubscription", map[string]any{
+		"AnomalySubscription": map[string]any{
+			"SubscriptionName": fmt.Sprintf("%s-%s-%s", ec2Instance.Namespace, ec2Instance.Name, instanceID),
+			"MonitorArnList":   []string{ec2Instance.Status.AnomalyMonitorARN},
+			"Subscribers": []map[string]string{
+				{"Type": "SNS", "Address": ec2Instance.Spec.CostAnomalyDetection.NotificationARN},
+			},
+			"Frequency": "IMMEDIATE",
+			"ThresholdExpression": map[string]any{
+				"Dimensions": map[string]any{
+					"Key":          "ANOMALY_TOTAL_IMPACT_ABSOLUTE",
+					"Values":       []string{strconv.FormatFloat(ec2Instance.Spec.CostAnomalyDetection.ThresholdUSD, 'f', -1, 64)},
+					"MatchOptions": []string{"GREATER_THAN_OR_EQUAL"},
+				},
+			},
+		},
+	}, &out)
+	if err != nil {
+		return fmt.Errorf("failed to create anomaly subscription: %w", err)
+	}
+	l.Info("Created cost anomaly subscription", "instanceID", instanceID, "subscriptionARN", out.SubscriptionArn)
+	ec2Instance.Status.AnomalySubscriptionARN = out.SubscriptionArn
+	return nil
+}
+
+// deleteCostAnomalyDetection removes the anomaly subscription and monitor recorded in the status.
+func deleteCostAnomalyDetection(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	ce := instanceCostExplorer(ec2Instance)
+	if arn := ec2Instance.Status.AnomalySubscriptionARN; arn != "" {
+		if err := ce.call(ctx, "DeleteAnomalySubscription", map[string]string{"SubscriptionArn": arn}, nil); err != nil {
+			return fmt.Errorf("failed to delete anomaly subscription: %w", err)
+		}
+		ec2Instance.Status.AnomalySubscriptionARN = ""
+	}
+	if arn := ec2Instance.Status.AnomalyMonitorARN; arn != "" {
+		if err := ce.call(ctx, "DeleteAnomalyMonitor", map[string]string{"MonitorArn": arn}, nil); err != nil {
+			return fmt.Errorf("failed to delete anomaly monitor: %w", err)
+		}
+		ec2Instance.Status.AnomalyMonitorARN = ""
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// costReportRetentionDays is how many daily reports are kept per namespace.
+	costReportRetentionDays = 90
+	// costReportDelay is how long after midnight UTC the report of the previous day is written.
+	// Cost Explorer needs a few hours to settle the numbers of a day.
+	costReportDelay = 6 * time.Hour
+	// costReportPrefix is the name prefix of the daily reports.
+	costReportPrefix = "cost-"
+	// ec2ComputeService is the Cost Explorer SERVICE dimension value of EC2 instance usage.
+	ec2ComputeService = "Amazon Elastic Compute Cloud - Compute"
+)
+
+// resourceCost is the usage and cost Cost Explorer reports for one resource.
+type resourceCost struct {
+	Hours   float64
+	CostUSD float64
+}
+
+// CostAllocationReportReconciler writes a CostAllocationReport per namespace per day.
+type CostAllocationReportReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=costallocationreports,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=costallocationreports/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch
+
+// Reconcile writes the report of the last complete day for the namespace, if it is not there yet,
+// and deletes reports that fell out of the retention window.
+func (r *CostAllocationReportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+	now := time.Now().UTC()
+	day := costReportDay(now)
+	next := time.Until(day.AddDate(0, 0, 2).Add(costReportDelay))
+
+	if err := r.pruneReports(ctx, req.Namespace, now); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	report := &computev1.CostAllocationReport{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: costReportName(day)}, report)
+	if err == nil && report.Status.ReportDate != "" {
+		return ctrl.Result{RequeueAfter: next}, nil
+	}
+	found := err == nil
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	instances := &computev1.Ec2InstanceList{}
+	if err := r.List(ctx, instances, client.InNamespace(req.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list Ec2Instances in %s: %w", req.Namespace, err)
+	}
+	var instanceIDs []string
+	for _, inst := range instances.Items {
+		if inst.Status.InstanceID != "" {
+			instanceIDs = append(instanceIDs, inst.Status.InstanceID)
+		}
+	}
+	if len(instanceIDs) == 0 {
+		return ctrl.Result{RequeueAfter: next}, nil
+	}
+
+	costs, err := instanceCosts(ctx, instanceIDs, day)
+	if err != nil {
+		l.Error(err, "Failed to fetch instance costs", "date", day.Format(time.DateOnly))
+		return ctrl.Result{}, err
+	}
+
+	if !found {
+		report = &computev1.CostAllocationReport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: req.Namespace, Name: costReportName(day)},
+		}
+		if err := r.Create(ctx, report); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	status := costReportStatus(instances.Items, costs, day)
+	if !equality.Semantic.DeepEqual(report.Status, status) {
+		report.Status = status
+		if err := r.Status().Update(ctx, report); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	l.Info("Wrote cost allocation report", "date", status.ReportDate, "totalCostUSD", status.TotalCostUSD)
+	return ctrl.Result{RequeueAfter: next}, nil
+}
+
+// costReportDay is the last day that is complete and settled in Cost Explorer at now.
+func costReportDay(now time.Time) time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if now.Sub(today) < costReportDelay {
+		return today.AddDate(0, 0, -2)
+	}
+	return today.AddDate(0, 0, -1)
+}
+
+// costReportName is the name of the report for day.
+func costReportName(day time.Time) string {
+	return costReportPrefix + day.Format(time.DateOnly)
+}
+
+// costReportStatus turns the Cost Explorer data of day into a report of the instances.
+func costReportStatus(instances []computev1.Ec2Instance, costs map[string]resourceCost, day time.Time) computev1.CostAllocationReportStatus {
+	status := computev1.CostAllocationReportStatus{ReportDate: day.Format(time.DateOnly)}
+	for i := range instances {
+		inst := &instances[i]
+		if inst.Status.InstanceID == "" {
+			continue
+		}
+		cost := costs[inst.Status.InstanceID]
+		entry := computev1.CostEntry{
+			InstanceName: inst.Name,
+			InstanceID:   inst.Status.InstanceID,
+			InstanceType: launchInstanceType(inst),
+			Hours:        cost.Hours,
+			CostUSD:      cost.CostUSD,
+			Tags:         inst.Spec.Tags,
+		}
+		if cost.Hours > 0 {
+			entry.CostPerHour = cost.CostUSD / cost.Hours
+		}
+		status.Entries = append(status.Entries, entry)
+		status.TotalCostUSD += cost.CostUSD
+	}
+	status.ProjectedMonthlyUSD = status.TotalCostUSD / 24 * hoursPerMonth
+	return status
+}
+
+// instanceCosts fetches the resource-level cost and usage of the instances on day. Resource-level
+// data has to be enabled in the Cost Explorer settings and only covers the last 14 days.
+func instanceCosts(ctx context.Context, instanceIDs []string, day time.Time) (map[string]resourceCost, error) {
+	type metric struct{ Amount string }
+	costs := map[string]resourceCost{}
+	var token string
+	for {
+		in := map[string]any{
+			"TimePeriod": map[string]string{
+				"Start": day.Format(time.DateOnly),
+				"End":   day.AddDate(0, 0, 1).Format(time.DateOnly),
+			},
+			"Granularity": "DAILY",
+			"Metrics":     []string{"UnblendedCost", "UsageQuantity"},
+			"Filter": map[string]any{"And": []map[string]any{
+				{"Dimensions": map[string]any{"Key": "SERVICE", "Values": []string{ec2ComputeService}}},
+				{"Dimensions": map[string]any{"Key": "RESOURCE_ID", "Values": instanceIDs}},
+			}},
+			"GroupBy": []map[string]string{{"Type": "DIMENSION", "Key": "RESOURCE_ID"}},
+		}
+		if token != "" {
+			in["NextPageToken"] = token
+		}
+		out := struct {
+			ResultsByTime []struct {
+				Groups []struct {
+					Keys    []string
+					Metrics map[string]metric
+				}
+			}
+			NextPageToken string
+		}{}
+		if err := costExplorer.call(ctx, "GetCostAndUsageWithResources", in, &out); err != nil {
+			return nil, fmt.Errorf("failed to get cost and usage: %w", err)
+		}
+
+		for _, result := range out.ResultsByTime {
+			for _, group := range result.Groups {
+				if len(group.Keys) == 0 {
+					continue
+				}
+				cost := costs[group.Keys[0]]
+				amount, _ := strconv.ParseFloat(group.Metrics["UnblendedCost"].Amount, 64)
+				hours, _ := strconv.ParseFloat(group.Metrics["UsageQuantity"].Amount, 64)
+				cost.CostUSD += amount
+				cost.Hours += hours
+				costs[group.Keys[0]] = cost
+			}
+		}
+		if out.NextPageToken == "" {
+			return costs, nil
+		}
+		token = out.NextPageToken
+	}
+}
+
+// pruneReports deletes the reports of the namespace that are older than the retention window.
+func (r *CostAllocationReportReconciler) pruneReports(ctx context.Context, namespace string, now time.Time) error {
+	reports := &computev1.CostAllocationReportList{}
+	if err := r.List(ctx, reports, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list CostAllocationReports in %s: %w", namespace, err)
+	}
+	for i := range reports.Items {
+		report := &reports.Items[i]
+		if !costReportExpired(report.Name, now) {
+			continue
+		}
+		if err := r.Delete(ctx, report); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete CostAllocationReport %s: %w", report.Name, err)
+		}
+		log.FromContext(ctx).Info("Deleted expired cost allocation report", "report", report.Name)
+	}
+	return nil
+}
+
+// costReportExpired reports whether the report called name is older than the retention window.
+// Objects that do not follow the cost-YYYY-MM-DD naming are left alone.
+func costReportExpired(name string, now time.Time) bool {
+	day, err := time.Parse(time.DateOnly, strings.TrimPrefix(name, costReportPrefix))
+	if err != nil || !strings.HasPrefix(name, costReportPrefix) {
+		return false
+	}
+	return now.Sub(day) > costReportRetentionDays*24*time.Hour
+}
+
+// SetupWithManager sets up the controller with the Manager. Every Ec2Instance event is mapped onto
+// the namespace, so a namespace gets its first report as soon as it has an instance.
+func (r *CostAllocationReportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.CostAllocationReport{}).
+		Watches(&computev1.Ec2Instance{}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, obj client.Object) []reconcile.Request {
+				name := costReportName(costReportDay(time.Now().UTC()))
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+			})).
+		Named("costallocationreport").
+		Complete(r)
+}
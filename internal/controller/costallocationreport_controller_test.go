@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("CostAllocationReport Controller", func() {
+	Context("When picking the report date", func() {
+		It("should report on yesterday once Cost Explorer has settled", func() {
+			now := time.Date(2025, 3, 10, 7, 0, 0, 0, time.UTC)
+			Expect(costReportName(costReportDay(now))).To(Equal("cost-2025-03-09"))
+		})
+
+		It("should report on the day before yesterday early in the morning", func() {
+			now := time.Date(2025, 3, 10, 2, 0, 0, 0, time.UTC)
+			Expect(costReportName(costReportDay(now))).To(Equal("cost-2025-03-08"))
+		})
+	})
+
+	Context("When building the report", func() {
+		It("should add an entry per launched instance and project the monthly cost", func() {
+			instances := []computev1.Ec2Instance{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web"},
+					Spec:       computev1.Ec2InstanceSpec{InstanceType: "t3.micro", Tags: map[string]string{"team": "web"}},
+					Status:     computev1.Ec2InstanceStatus{InstanceID: "i-1"},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "idle"},
+					Spec:       computev1.Ec2InstanceSpec{InstanceType: "t3.small"},
+					Status:     computev1.Ec2InstanceStatus{InstanceID: "i-2"},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pending"},
+					Spec:       computev1.Ec2InstanceSpec{InstanceType: "t3.small"},
+				},
+			}
+			costs := map[string]resourceCost{"i-1": {Hours: 24, CostUSD: 2.4}}
+
+			status := costReportStatus(instances, costs, time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC))
+			Expect(status.ReportDate).To(Equal("2025-03-09"))
+			Expect(status.Entries).To(HaveLen(2))
+			Expect(status.Entries[0].InstanceType).To(Equal("t3.micro"))
+			Expect(status.Entries[0].CostPerHour).To(BeNumerically("~", 0.1))
+			Expect(status.Entries[0].Tags).To(HaveKeyWithValue("team", "web"))
+			Expect(status.Entries[1].CostUSD).To(BeZero())
+			Expect(status.Entries[1].CostPerHour).To(BeZero())
+			Expect(status.TotalCostUSD).To(BeNumerically("~", 2.4))
+			Expect(status.ProjectedMonthlyUSD).To(BeNumerically("~", 73))
+		})
+	})
+
+	Context("When pruning old reports", func() {
+		now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		It("should expire reports older than 90 days", func() {
+			Expect(costReportExpired("cost-2025-03-01", now)).To(BeTrue())
+			Expect(costReportExpired("cost-2025-03-04", now)).To(BeFalse())
+		})
+
+		It("should leave reports with other names alone", func() {
+			Expect(costReportExpired("my-report", now)).To(BeFalse())
+			Expect(costReportExpired("2020-01-01", now)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// cpuOptionsRequest returns the CPU options to launch with, filling a value left unset from the
+// defaults of the instance type.
+func cpuOptionsRequest(cpu computev1.CPUOptions, info *ec2types.InstanceTypeInfo) *ec2types.CpuOptionsRequest {
+	request := &ec2types.CpuOptionsRequest{}
+	if info.VCpuInfo != nil {
+		request.CoreCount = info.VCpuInfo.DefaultCores
+		request.ThreadsPerCore = info.VCpuInfo.DefaultThreadsPerCore
+	}
+	if cpu.CoreCount > 0 {
+		request.CoreCount = aws.Int32(cpu.CoreCount)
+	}
+	if cpu.ThreadsPerCore > 0 {
+		request.ThreadsPerCore = aws.Int32(cpu.ThreadsPerCore)
+	}
+	return request
+}
+
+// observedCPUOptions returns the cores and threads per core the instance runs with.
+func observedCPUOptions(awsInstance *ec2types.Instance) *computev1.CPUOptions {
+	if awsInstance.CpuOptions == nil {
+		return nil
+	}
+	return &computev1.CPUOptions{
+		CoreCount:      aws.ToInt32(awsInstance.CpuOptions.CoreCount),
+		ThreadsPerCore: aws.ToInt32(awsInstance.CpuOptions.ThreadsPerCore),
+	}
+}
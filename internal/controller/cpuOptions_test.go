@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("CPU options", func() {
+	info := &ec2types.InstanceTypeInfo{VCpuInfo: &ec2types.VCpuInfo{
+		DefaultCores:          aws.Int32(4),
+		DefaultThreadsPerCore: aws.Int32(2),
+	}}
+
+	It("should take the values that are set from the spec", func() {
+		request := cpuOptionsRequest(computev1.CPUOptions{CoreCount: 2, ThreadsPerCore: 1}, info)
+		Expect(aws.ToInt32(request.CoreCount)).To(Equal(int32(2)))
+		Expect(aws.ToInt32(request.ThreadsPerCore)).To(Equal(int32(1)))
+	})
+
+	It("should fill unset values from the instance type defaults", func() {
+		request := cpuOptionsRequest(computev1.CPUOptions{ThreadsPerCore: 1}, info)
+		Expect(aws.ToInt32(request.CoreCount)).To(Equal(int32(4)))
+		Expect(aws.ToInt32(request.ThreadsPerCore)).To(Equal(int32(1)))
+	})
+
+	It("should report the options of the instance", func() {
+		Expect(observedCPUOptions(&ec2types.Instance{})).To(BeNil())
+		Expect(observedCPUOptions(&ec2types.Instance{CpuOptions: &ec2types.CpuOptions{
+			CoreCount: aws.Int32(2), ThreadsPerCore: aws.Int32(1),
+		}})).To(Equal(&computev1.CPUOptions{CoreCount: 2, ThreadsPerCore: 1}))
+	})
+})
@@ -9,34 +9,218 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-func createEc2Instance(ec2Instance *computev1.Ec2Instance) (createdInstanceInfo *computev1.CreatedInstanceInfo, err error) {
+// enclaveImageTag tells the bootstrap scripts of the instance which enclave image to run.
+const enclaveImageTag = "ec2instance.compute.cloud.com/enclave-image"
+
+// launchPolicy is what the operator enforces on every instance it launches.
+type launchPolicy struct {
+	// EnforceEBSEncryption encrypts the root volume unless spec.encryptRootVolume is false.
+	EnforceEBSEncryption bool
+	// EnforceIMDSv2 requires session tokens for the instance metadata service.
+	EnforceIMDSv2 bool
+}
+
+// launchPolicy returns the policy of the operator's flags.
+func (r *Ec2InstanceReconciler) launchPolicy() launchPolicy {
+	return launchPolicy{EnforceEBSEncryption: r.EnforceEBSEncryption, EnforceIMDSv2: r.EnforceIMDSv2}
+}
+
+// createEc2Instance launches the instance with the given tags, base64 encoded user data, key pair,
+// security groups and launch template, applying policy on top of the spec. User data rendered from
+// spec.imageBuilderComponents replaces userData, the two cannot be combined.
+func createEc2Instance(ec2Client awsclient.EC2Client, ec2Instance *computev1.Ec2Instance, tags map[string]string, userData, keyName string, securityGroupIDs []string, launchTemplate *computev1.LaunchTemplateRef, policy launchPolicy) (createdInstanceInfo *computev1.CreatedInstanceInfo, err error) {
 	l := log.Log.WithName("createEc2Instance")
 
 	l.Info("=== STARTING EC2 INSTANCE CREATION ===",
-		"ami", ec2Instance.Spec.AMIId,
-		"instanceType", ec2Instance.Spec.InstanceType,
+		"ami", launchImageID(ec2Instance),
+		"instanceType", launchInstanceType(ec2Instance),
 		"region", ec2Instance.Spec.Region)
 
-	// create the client for ec2 instance
-	ec2Client := awsClient(ec2Instance.Spec.Region)
-
 	// create the input for the run instances
 	runInput := &ec2.RunInstancesInput{
-		ImageId:      aws.String(ec2Instance.Spec.AMIId),
-		InstanceType: ec2types.InstanceType(ec2Instance.Spec.InstanceType),
-		KeyName:      aws.String(ec2Instance.Spec.KeyPair),
+		ImageId:      aws.String(launchImageID(ec2Instance)),
+		InstanceType: ec2types.InstanceType(launchInstanceType(ec2Instance)),
+		KeyName:      aws.String(keyName),
 		SubnetId:     aws.String(ec2Instance.Spec.Subnet),
 		MinCount:     aws.Int32(1),
 		MaxCount:     aws.Int32(1),
-		//SecurityGroupIds: []string{ec2Instance.Spec.SecurityGroups[0]},
+		// Without security groups AWS uses the default group of the VPC.
+		SecurityGroupIds: securityGroupIDs,
+	}
+	applyLaunchTemplate(runInput, ec2Instance.Spec, launchTemplate)
+
+	// The interfaces of spec.networkInterfaces carry their own subnets and security groups.
+	if len(ec2Instance.Spec.NetworkInterfaces) > 0 {
+		runInput.NetworkInterfaces = networkInterfaceSpecifications(ec2Instance.Spec.NetworkInterfaces)
+		runInput.SubnetId = nil
+		runInput.SecurityGroupIds = nil
+	}
+
+	runInput.MetadataOptions = metadataOptionsRequest(ec2Instance, policy.EnforceIMDSv2)
+
+	if profile := ec2Instance.Spec.IAMInstanceProfile; profile != "" {
+		runInput.IamInstanceProfile = iamInstanceProfileSpecification(profile)
+	}
+
+	if ec2Instance.Spec.AvailabilityZone != "" {
+		// RunInstances only answers a subnet in another zone with a generic error, so say which
+		// zone the subnet is in. The webhook checks this too, but it may be disabled.
+		if ec2Instance.Spec.Subnet != "" {
+			subnet, err := describeSubnet(context.TODO(), ec2Client, ec2Instance.Spec.Region, ec2Instance.Spec.Subnet)
+			if err != nil {
+				return nil, err
+			}
+			if subnet == nil {
+				return nil, fmt.Errorf("subnet %s does not exist in region %s", ec2Instance.Spec.Subnet, ec2Instance.Spec.Region)
+			}
+			if zone := aws.ToString(subnet.AvailabilityZone); zone != ec2Instance.Spec.AvailabilityZone {
+				return nil, fmt.Errorf("subnet %s is in availability zone %s, not %s", ec2Instance.Spec.Subnet, zone, ec2Instance.Spec.AvailabilityZone)
+			}
+		}
+		runInput.Placement = &ec2types.Placement{AvailabilityZone: aws.String(ec2Instance.Spec.AvailabilityZone)}
+	}
+
+	if group := ec2Instance.Spec.PlacementGroup; group != nil {
+		if runInput.Placement == nil {
+			runInput.Placement = &ec2types.Placement{}
+		}
+		runInput.Placement.GroupName = aws.String(group.Name)
+		if group.PartitionNumber > 0 {
+			runInput.Placement.PartitionNumber = aws.Int32(group.PartitionNumber)
+		}
+	}
+
+	if ec2Instance.Spec.SpotOptions.Enabled {
+		runInput.InstanceMarketOptions = spotMarketOptions(ec2Instance.Spec.SpotOptions)
+	}
+
+	// Nitro Enclaves can only be enabled at launch. Check the instance type here too, the webhook
+	// may be disabled and RunInstances does not say why it rejected the request.
+	if ec2Instance.Spec.NitroEnclave.Enabled {
+		info, err := DescribeInstanceType(context.TODO(), ec2Instance.Spec.Region, launchInstanceType(ec2Instance))
+		if err != nil {
+			return nil, err
+		}
+		if info.NitroEnclavesSupport != ec2types.NitroEnclavesSupportSupported {
+			return nil, fmt.Errorf("instance type %s does not support Nitro Enclaves", launchInstanceType(ec2Instance))
+		}
+		runInput.EnclaveOptions = &ec2types.EnclaveOptionsRequest{Enabled: aws.Bool(true)}
+		if ref := ec2Instance.Spec.NitroEnclave.EnclaveImageRef; ref != "" {
+			if tags == nil {
+				tags = map[string]string{}
+			}
+			tags[enclaveImageTag] = ref
+		}
+	}
+
+	// RunInstances needs both values, so one left unset is taken from the instance type.
+	if cpu := ec2Instance.Spec.CPUOptions; cpu.CoreCount > 0 || cpu.ThreadsPerCore > 0 {
+		info, err := DescribeInstanceType(context.TODO(), ec2Instance.Spec.Region, launchInstanceType(ec2Instance))
+		if err != nil {
+			return nil, err
+		}
+		runInput.CpuOptions = cpuOptionsRequest(cpu, info)
+	}
+
+	// ENA Express is set per network interface, so the primary interface has to be described
+	// explicitly; unless spec.networkInterfaces already does, the subnet and security groups move
+	// into it.
+	if ec2Instance.Spec.ENAExpressEnabled {
+		info, err := DescribeInstanceType(context.TODO(), ec2Instance.Spec.Region, launchInstanceType(ec2Instance))
+		if err != nil {
+			return nil, err
+		}
+		if enaExpressSupported(info) {
+			if len(runInput.NetworkInterfaces) == 0 {
+				runInput.NetworkInterfaces = []ec2types.InstanceNetworkInterfaceSpecification{{
+					DeviceIndex: aws.Int32(0),
+					SubnetId:    runInput.SubnetId,
+					Groups:      runInput.SecurityGroupIds,
+				}}
+				runInput.SubnetId = nil
+				runInput.SecurityGroupIds = nil
+			}
+			for i := range runInput.NetworkInterfaces {
+				if aws.ToInt32(runInput.NetworkInterfaces[i].DeviceIndex) != 0 {
+					continue
+				}
+				runInput.NetworkInterfaces[i].EnaSrdSpecification = &ec2types.EnaSrdSpecificationRequest{
+					EnaSrdEnabled: aws.Bool(true),
+					EnaSrdUdpSpecification: &ec2types.EnaSrdUdpSpecificationRequest{
+						EnaSrdUdpEnabled: aws.Bool(ec2Instance.Spec.ENAExpressUDPEnabled),
+					},
+				}
+			}
+		} else {
+			l.Info("Instance type does not support ENA Express, launching without it", "instanceType", launchInstanceType(ec2Instance))
+		}
+	}
+
+	if len(tags) > 0 {
+		instanceTags := make([]ec2types.Tag, 0, len(tags))
+		for k, v := range tags {
+			instanceTags = append(instanceTags, ec2types.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		runInput.TagSpecifications = []ec2types.TagSpecification{{ResourceType: ec2types.ResourceTypeInstance, Tags: instanceTags}}
+	}
+
+	if len(ec2Instance.Spec.ImageBuilderComponents) > 0 {
+		userData, err = imageBuilderUserData(context.TODO(), ec2Instance)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if userData != "" {
+		runInput.UserData = aws.String(userData)
+	}
+
+	// Only ask for EBS optimization where it is optional; "default" types always have it.
+	if ec2Instance.Spec.EBSOptimized {
+		info, err := DescribeInstanceType(context.TODO(), ec2Instance.Spec.Region, launchInstanceType(ec2Instance))
+		if err != nil {
+			return nil, err
+		}
+		if info.EbsInfo != nil && info.EbsInfo.EbsOptimizedSupport == ec2types.EbsOptimizedSupportSupported {
+			runInput.EbsOptimized = aws.Bool(true)
+		}
+	}
+
+	// Hibernation stores RAM on the root volume, so it is always launched encrypted and at the size
+	// the webhook checked rather than with the AMI defaults. A launch template keeps its own root
+	// volume unless the spec configures it.
+	if (launchTemplate == nil || rootVolumeConfigured(ec2Instance.Spec)) &&
+		(ec2Instance.Spec.HibernationEnabled || encryptRootVolume(ec2Instance, policy.EnforceEBSEncryption)) {
+		mapping, err := encryptedRootVolumeMapping(context.TODO(), ec2Client, ec2Instance, launchImageID(ec2Instance))
+		if err != nil {
+			return nil, err
+		}
+		runInput.BlockDeviceMappings = []ec2types.BlockDeviceMapping{mapping}
+	}
+	if ec2Instance.Spec.HibernationEnabled {
+		runInput.HibernationOptions = &ec2types.HibernationOptionsRequest{Configured: aws.Bool(true)}
 	}
 
 	l.Info("=== CALLING AWS RunInstances API ===")
-	// run the instances
-	result, err := ec2Client.RunInstances(context.TODO(), runInput)
+	// The client token makes a retried RunInstances return the instance an earlier attempt may have
+	// launched, instead of launching a second one.
+	runInput.ClientToken = aws.String(fmt.Sprintf("%s-%d", ec2Instance.UID, time.Now().UnixNano()))
+	var result *ec2.RunInstancesOutput
+	runInstances := func() (err error) {
+		result, err = ec2Client.RunInstances(context.TODO(), runInput)
+		return err
+	}
+	err = retryTransientAWSErrors(context.TODO(), "RunInstances", newAWSRetryBackOff(context.TODO()), runInstances)
+	if err != nil && runInput.InstanceMarketOptions != nil && ec2Instance.Spec.SpotOptions.FallbackOnDemand && spotCapacityUnavailable(err) {
+		l.Info("No Spot capacity, launching On-Demand instead", "reason", err.Error())
+		runInput.InstanceMarketOptions = nil
+		// A different request needs a token of its own.
+		runInput.ClientToken = aws.String(fmt.Sprintf("%s-%d", ec2Instance.UID, time.Now().UnixNano()))
+		err = retryTransientAWSErrors(context.TODO(), "RunInstances", newAWSRetryBackOff(context.TODO()), runInstances)
+	}
 	if err != nil {
 		l.Error(err, "Failed to create EC2 instance")
 		return nil, fmt.Errorf("failed to create EC2 instance: %w", err)
@@ -76,7 +260,11 @@ func createEc2Instance(ec2Instance *computev1.Ec2Instance) (createdInstanceInfo
 		InstanceIds: []string{*inst.InstanceId},
 	}
 
-	describeResult, err := ec2Client.DescribeInstances(context.TODO(), describeInput)
+	var describeResult *ec2.DescribeInstancesOutput
+	err = retryTransientAWSErrors(context.TODO(), "DescribeInstances", newAWSRetryBackOff(context.TODO()), func() (err error) {
+		describeResult, err = ec2Client.DescribeInstances(context.TODO(), describeInput)
+		return err
+	})
 	if err != nil {
 		l.Error(err, "Failed to describe EC2 instance")
 		return nil, fmt.Errorf("failed to describe EC2 instance: %w", err)
@@ -132,3 +320,24 @@ func derefString(s *string) string {
 	}
 	return "<nil>"
 }
+
+// launchImageID returns the AMI to launch from: the regional copy if one was selected, otherwise spec.amiId.
+func launchImageID(ec2Instance *computev1.Ec2Instance) string {
+	if ec2Instance.Status.SelectedAMIID != "" {
+		return ec2Instance.Status.SelectedAMIID
+	}
+	return ec2Instance.Spec.AMIId
+}
+
+// launchInstanceType returns the instance type to launch: the one picked by
+// spec.instanceTypeOptimization if any, otherwise spec.instanceType, or the one of the launch
+// template when spec.instanceType is not set.
+func launchInstanceType(ec2Instance *computev1.Ec2Instance) string {
+	if ec2Instance.Spec.InstanceType != "" && ec2Instance.Spec.InstanceTypeOptimization != computev1.InstanceTypeOptimizationCostAware {
+		return ec2Instance.Spec.InstanceType
+	}
+	if ec2Instance.Status.SelectedInstanceType != "" {
+		return ec2Instance.Status.SelectedInstanceType
+	}
+	return ec2Instance.Spec.InstanceType
+}
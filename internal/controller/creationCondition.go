@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// creationConditionEnv declares the variables a creation condition can use. "namespace" is a
+// reserved word in CEL, so the namespace is exposed as namespaceObject like in admission policies.
+var creationConditionEnv = func() *cel.Env {
+	env, err := cel.NewEnv(
+		cel.Variable("spec", cel.DynType),
+		cel.Variable("metadata", cel.DynType),
+		cel.Variable("namespaceObject", cel.DynType),
+		cel.Variable("now", cel.TimestampType),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return env
+}()
+
+// evaluateCreationCondition evaluates expr against the instance and the metadata of its namespace.
+func evaluateCreationCondition(expr string, ec2Instance *computev1.Ec2Instance, namespace *corev1.Namespace, now time.Time) (bool, error) {
+	ast, issues := creationConditionEnv.Compile(expr)
+	if issues.Err() != nil {
+		return false, fmt.Errorf("invalid creation condition: %w", issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return false, fmt.Errorf("creation condition must evaluate to a bool, not %s", ast.OutputType())
+	}
+	program, err := creationConditionEnv.Program(ast)
+	if err != nil {
+		return false, fmt.Errorf("invalid creation condition: %w", err)
+	}
+
+	// Go through unstructured so the expression sees the same field names as the YAML.
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ec2Instance)
+	if err != nil {
+		return false, err
+	}
+	ns, err := runtime.DefaultUnstructuredConverter.ToUnstructured(namespace)
+	if err != nil {
+		return false, err
+	}
+
+	out, _, err := program.Eval(map[string]any{
+		"spec":            obj["spec"],
+		"metadata":        obj["metadata"],
+		"namespaceObject": ns,
+		"now":             now,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate creation condition: %w", err)
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("creation condition must evaluate to a bool, not %T", out.Value())
+	}
+	return result, nil
+}
+
+// creationConditionMet reports whether spec.creationCondition allows the instance to be launched.
+// When it does not, the CreationConditionNotMet condition says why.
+func (r *Ec2InstanceReconciler) creationConditionMet(ctx context.Context, ec2Instance *computev1.Ec2Instance) (bool, error) {
+	if ec2Instance.Spec.CreationCondition == "" {
+		return true, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ec2Instance.Namespace}, namespace); err != nil {
+		return false, fmt.Errorf("failed to get namespace %s: %w", ec2Instance.Namespace, err)
+	}
+
+	met, err := evaluateCreationCondition(ec2Instance.Spec.CreationCondition, ec2Instance, namespace, time.Now())
+	if met {
+		return true, nil
+	}
+
+	condition := metav1.Condition{
+		Type:               computev1.ConditionCreationConditionNotMet,
+		Status:             metav1.ConditionTrue,
+		Reason:             "EvaluatedFalse",
+		Message:            fmt.Sprintf("Waiting for %q to become true", ec2Instance.Spec.CreationCondition),
+		ObservedGeneration: ec2Instance.Generation,
+	}
+	if err != nil {
+		condition.Reason = "EvaluationError"
+		condition.Message = err.Error()
+	}
+	if apimeta.SetStatusCondition(&ec2Instance.Status.Conditions, condition) {
+		if err := r.Status().Update(ctx, ec2Instance); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
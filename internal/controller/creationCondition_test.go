@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Creation condition", func() {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	instance := &computev1.Ec2Instance{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod", CreationTimestamp: metav1.NewTime(created)},
+		Spec:       computev1.Ec2InstanceSpec{InstanceType: "t3.micro", Region: "eu-west-1"},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"stage": "live"}}}
+
+	It("should evaluate spec, metadata, namespace and time", func() {
+		met, err := evaluateCreationCondition(`spec.instanceType == "t3.micro" && namespaceObject.metadata.labels.stage == "live" && `+
+			`timestamp(metadata.creationTimestamp) > timestamp("2025-01-01T00:00:00Z")`, instance, namespace, created)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(met).To(BeTrue())
+	})
+
+	It("should hold creation back until the time has come", func() {
+		expr := `now >= timestamp("2025-03-02T00:00:00Z")`
+		Expect(evaluateCreationCondition(expr, instance, namespace, created)).To(BeFalse())
+		Expect(evaluateCreationCondition(expr, instance, namespace, created.Add(24*time.Hour))).To(BeTrue())
+	})
+
+	It("should reject expressions that are not boolean", func() {
+		_, err := evaluateCreationCondition(`spec.region`, instance, namespace, created)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// cronWakeUpBuffer is how many wakeups can be queued for the controller. A wakeup that does not fit
+// is dropped; the periodic sync applies the scheduled change a little later instead.
+const cronWakeUpBuffer = 100
+
+// CronScheduler wakes the Ec2Instance controller up at the times in spec.schedule, so that scheduled
+// stops and starts do not wait for the next periodic sync. It implements manager.Runnable; the
+// reconciler keeps it up to date through Sync and Forget.
+type CronScheduler struct {
+	cron    *cron.Cron
+	wakeUps chan event.GenericEvent
+
+	mu      sync.Mutex
+	entries map[types.NamespacedName]cronEntries
+}
+
+// cronEntries are the cron jobs of one Ec2Instance and the schedule they were created for.
+type cronEntries struct {
+	schedule computev1.InstanceScheduleSpec
+	ids      []cron.EntryID
+}
+
+// NewCronScheduler returns a CronScheduler without any jobs.
+func NewCronScheduler() *CronScheduler {
+	return &CronScheduler{
+		cron:    cron.New(),
+		wakeUps: make(chan event.GenericEvent, cronWakeUpBuffer),
+		entries: map[types.NamespacedName]cronEntries{},
+	}
+}
+
+// Start runs the cron jobs until the manager's context is cancelled.
+func (s *CronScheduler) Start(ctx context.Context) error {
+	s.cron.Start()
+	<-ctx.Done()
+	<-s.cron.Stop().Done()
+	return nil
+}
+
+// NeedLeaderElection returns true, wakeups are only useful to the replica that reconciles.
+func (s *CronScheduler) NeedLeaderElection() bool {
+	return true
+}
+
+// Source turns the wakeups into reconcile requests for the Ec2Instance controller.
+func (s *CronScheduler) Source() source.Source {
+	return source.Channel(s.wakeUps, &handler.EnqueueRequestForObject{})
+}
+
+// Sync brings the cron jobs of ec2Instance in line with its spec.schedule.
+func (s *CronScheduler) Sync(ec2Instance *computev1.Ec2Instance) error {
+	key := client.ObjectKeyFromObject(ec2Instance)
+	spec := ec2Instance.Spec.Schedule
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.entries[key]; ok && spec != nil && current.schedule == *spec {
+		return nil
+	}
+	s.forgetLocked(key)
+	if spec == nil {
+		return nil
+	}
+
+	entries := cronEntries{schedule: *spec}
+	for _, expression := range []string{spec.StopCron, spec.StartCron} {
+		if expression == "" {
+			continue
+		}
+		schedule, err := parseScheduleCron(expression, spec.Timezone)
+		if err != nil {
+			for _, id := range entries.ids {
+				s.cron.Remove(id)
+			}
+			return err
+		}
+		entries.ids = append(entries.ids, s.cron.Schedule(schedule, s.wakeUp(key)))
+	}
+	s.entries[key] = entries
+	return nil
+}
+
+// Forget removes the cron jobs of a deleted Ec2Instance.
+func (s *CronScheduler) Forget(key types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forgetLocked(key)
+}
+
+func (s *CronScheduler) forgetLocked(key types.NamespacedName) {
+	for _, id := range s.entries[key].ids {
+		s.cron.Remove(id)
+	}
+	delete(s.entries, key)
+}
+
+// wakeUp returns the job that queues a reconcile of the Ec2Instance key.
+func (s *CronScheduler) wakeUp(key types.NamespacedName) cron.Job {
+	return cron.FuncJob(func() {
+		select {
+		case s.wakeUps <- event.GenericEvent{Object: &computev1.Ec2Instance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		}}:
+		default:
+		}
+	})
+}
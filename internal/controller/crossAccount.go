@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+// crossAccountExpiryWindow is how long before expiry assumed role credentials are refreshed, so
+// no call goes out with credentials that expire on the way.
+const crossAccountExpiryWindow = 5 * time.Minute
+
+// crossAccountRetryInterval is how soon an instance whose role cannot be assumed is retried.
+const crossAccountRetryInterval = time.Minute
+
+// crossAccountClient is the AWS config and EC2 client of an instance with spec.roleARN, along with
+// the role they were built for.
+type crossAccountClient struct {
+	roleARN string
+	cfg     aws.Config
+	client  *ec2.Client
+}
+
+// roleSessionName identifies the instance in the CloudTrail logs of the assumed role's account.
+// Session names cannot contain "/" and are at most 64 characters long.
+func roleSessionName(ec2Instance *computev1.Ec2Instance) string {
+	name := ec2Instance.Namespace + "." + ec2Instance.Name
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// instanceAWSClient returns the EC2 client for ec2Instance: the region's client, or one that
+// assumes spec.roleARN. Assumed role credentials are cached per instance and refreshed shortly
+// before they expire.
+func instanceAWSClient(ec2Instance *computev1.Ec2Instance) *ec2.Client {
+	return instanceRegionAWSClient(ec2Instance, ec2Instance.Spec.Region)
+}
+
+// instanceRegionAWSClient is instanceAWSClient for the calls about ec2Instance made in another
+// region, such as copying its snapshots there, so they are made in the account of the instance.
+func instanceRegionAWSClient(ec2Instance *computev1.Ec2Instance, region string) *ec2.Client {
+	if ec2Instance.Spec.RoleARN == "" {
+		return awsClient(region)
+	}
+	return crossAccountClientFor(ec2Instance, region).client
+}
+
+// instanceAWSConfig returns the AWS config for ec2Instance, for the clients of other services that
+// act on the instance: the region's config, or the one that assumes spec.roleARN. It shares its
+// credentials with instanceAWSClient.
+func instanceAWSConfig(ec2Instance *computev1.Ec2Instance) aws.Config {
+	if ec2Instance.Spec.RoleARN == "" {
+		return awsConfig(ec2Instance.Spec.Region)
+	}
+	return crossAccountClientFor(ec2Instance, ec2Instance.Spec.Region).cfg
+}
+
+// crossAccountKey is the key of the cached client of ec2Instance in region.
+func crossAccountKey(ec2Instance *computev1.Ec2Instance, region string) string {
+	return ec2Instance.Namespace + "/" + ec2Instance.Name + "@" + region
+}
+
+// crossAccountClientFor returns the cached config and client in region of an instance with
+// spec.roleARN, building them when the role changed.
+func crossAccountClientFor(ec2Instance *computev1.Ec2Instance, region string) crossAccountClient {
+	roleARN := ec2Instance.Spec.RoleARN
+	key := crossAccountKey(ec2Instance, region)
+	awsConfigs.Lock()
+	defer awsConfigs.Unlock()
+	if cached, ok := awsConfigs.crossAccount[key]; ok && cached.roleARN == roleARN {
+		return cached
+	}
+	base, cacheable := lockedAWSConfig(region)
+	cfg := base.Copy()
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(base), roleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = roleSessionName(ec2Instance)
+	})
+	cfg.Credentials = aws.NewCredentialsCache(provider, func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = crossAccountExpiryWindow
+	})
+	entry := crossAccountClient{
+		roleARN: roleARN,
+		cfg:     cfg,
+		client: ec2.NewFromConfig(cfg, func(o *ec2.Options) {
+			o.APIOptions = append(o.APIOptions, limitAWSCalls, countAWSCalls)
+		}),
+	}
+	if cacheable {
+		awsConfigs.crossAccount[key] = entry
+	}
+	return entry
+}
+
+// instanceCredentials returns the credentials of spec.roleARN for the signed calls made about
+// ec2Instance, or nil for the operator's own credentials.
+func instanceCredentials(ec2Instance *computev1.Ec2Instance) aws.CredentialsProvider {
+	if ec2Instance.Spec.RoleARN == "" {
+		return nil
+	}
+	return instanceAWSConfig(ec2Instance).Credentials
+}
+
+// instanceSSMClient returns the Systems Manager client for ec2Instance, like instanceAWSClient.
+func instanceSSMClient(ec2Instance *computev1.Ec2Instance) *ssm.Client {
+	return ssm.NewFromConfig(instanceAWSConfig(ec2Instance))
+}
+
+// instanceSNSClient returns the SNS client for ec2Instance, like instanceAWSClient.
+func instanceSNSClient(ec2Instance *computev1.Ec2Instance) *sns.Client {
+	return sns.NewFromConfig(instanceAWSConfig(ec2Instance))
+}
+
+// instanceEC2Client returns the client for the lifecycle calls of ec2Instance: r.EC2Client when it
+// is set, otherwise instanceAWSClient.
+func (r *Ec2InstanceReconciler) instanceEC2Client(ec2Instance *computev1.Ec2Instance) awsclient.EC2Client {
+	if r.EC2Client != nil {
+		return r.EC2Client
+	}
+	return instanceAWSClient(ec2Instance)
+}
+
+// forgetInstanceAWSClient drops the cached clients of a deleted instance, in every region.
+func forgetInstanceAWSClient(ec2Instance *computev1.Ec2Instance) {
+	awsConfigs.Lock()
+	defer awsConfigs.Unlock()
+	prefix := crossAccountKey(ec2Instance, "")
+	for key := range awsConfigs.crossAccount {
+		if strings.HasPrefix(key, prefix) {
+			delete(awsConfigs.crossAccount, key)
+		}
+	}
+}
+
+// setCrossAccountCondition records the outcome of assuming spec.roleARN and reports whether the
+// condition changed. Without a role the condition is removed.
+func setCrossAccountCondition(ec2Instance *computev1.Ec2Instance, assumeErr error) bool {
+	if ec2Instance.Spec.RoleARN == "" {
+		return apimeta.RemoveStatusCondition(&ec2Instance.Status.Conditions, computev1.ConditionCrossAccountReady)
+	}
+	condition := metav1.Condition{
+		Type:               computev1.ConditionCrossAccountReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RoleAssumed",
+		Message:            fmt.Sprintf("Assumed role %s", ec2Instance.Spec.RoleARN),
+		ObservedGeneration: ec2Instance.Generation,
+	}
+	if assumeErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "AssumeRoleFailed"
+		condition.Message = fmt.Sprintf("Cannot assume role %s: %v", ec2Instance.Spec.RoleARN, assumeErr)
+	}
+	return apimeta.SetStatusCondition(&ec2Instance.Status.Conditions, condition)
+}
+
+// reconcileCrossAccount makes sure spec.roleARN can be assumed before anything is done in AWS, and
+// reports whether it can. A role that cannot be assumed is a configuration problem, so it ends up
+// in the CrossAccountReady condition rather than as a reconcile error.
+func (r *Ec2InstanceReconciler) reconcileCrossAccount(ctx context.Context, ec2Instance *computev1.Ec2Instance) (bool, error) {
+	var assumeErr error
+	if ec2Instance.Spec.RoleARN != "" {
+		// Served from the cache while the credentials are valid; only a refresh calls STS.
+		_, assumeErr = instanceAWSClient(ec2Instance).Options().Credentials.Retrieve(ctx)
+	}
+	if setCrossAccountCondition(ec2Instance, assumeErr) {
+		if assumeErr != nil {
+			log.FromContext(ctx).Error(assumeErr, "Failed to assume role", "roleARN", ec2Instance.Spec.RoleARN)
+			r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "AssumeRoleFailed", "Cannot assume role %s: %v", ec2Instance.Spec.RoleARN, assumeErr)
+		}
+		if err := r.Status().Update(ctx, ec2Instance); err != nil {
+			return false, fmt.Errorf("failed to update cross-account condition: %w", err)
+		}
+	}
+	return assumeErr == nil, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Cross-account instances", func() {
+	var inst *computev1.Ec2Instance
+
+	BeforeEach(func() {
+		inst = &computev1.Ec2Instance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "web"},
+			Spec:       computev1.Ec2InstanceSpec{Region: "eu-west-1", RoleARN: "arn:aws:iam::111111111111:role/ec2operator"},
+		}
+	})
+
+	AfterEach(func() {
+		resetAWSConfigs()
+	})
+
+	It("should name the session after the instance within the STS limits", func() {
+		Expect(roleSessionName(inst)).To(Equal("team-a.web"))
+		inst.Name = strings.Repeat("a", 80)
+		Expect(roleSessionName(inst)).To(HaveLen(64))
+	})
+
+	It("should keep one client per instance until the role changes", func() {
+		client := instanceAWSClient(inst)
+		Expect(instanceAWSClient(inst)).To(BeIdenticalTo(client))
+		Expect(client).NotTo(BeIdenticalTo(awsClient("eu-west-1")))
+
+		inst.Spec.RoleARN = "arn:aws:iam::222222222222:role/ec2operator"
+		Expect(instanceAWSClient(inst)).NotTo(BeIdenticalTo(client))
+
+		inst.Spec.RoleARN = ""
+		Expect(instanceAWSClient(inst)).To(BeIdenticalTo(awsClient("eu-west-1")))
+	})
+
+	It("should make the calls of other services about the instance with the role", func() {
+		credentials := instanceAWSClient(inst).Options().Credentials
+		Expect(instanceAWSConfig(inst).Credentials).To(BeIdenticalTo(credentials))
+		Expect(instanceCredentials(inst)).To(BeIdenticalTo(credentials))
+		Expect(instanceSSMClient(inst).Options().Credentials).To(BeIdenticalTo(credentials))
+		Expect(instanceCostExplorer(inst).Credentials).To(BeIdenticalTo(credentials))
+		Expect(cloudWatch(inst.Spec.Region, instanceCredentials(inst)).api.Credentials).To(BeIdenticalTo(credentials))
+
+		inst.Spec.RoleARN = ""
+		Expect(instanceCredentials(inst)).To(BeNil())
+		Expect(instanceSSMClient(inst).Options().Credentials).To(BeIdenticalTo(awsConfig("eu-west-1").Credentials))
+	})
+
+	It("should make the calls about the instance in other regions with the role", func() {
+		client := instanceRegionAWSClient(inst, "us-west-2")
+		Expect(client.Options().Region).To(Equal("us-west-2"))
+		Expect(instanceRegionAWSClient(inst, "us-west-2")).To(BeIdenticalTo(client))
+		Expect(client).NotTo(BeIdenticalTo(awsClient("us-west-2")))
+		Expect(client.Options().Credentials).NotTo(BeIdenticalTo(awsConfig("us-west-2").Credentials))
+		Expect(instanceRegionAWSClient(inst, "eu-west-1")).To(BeIdenticalTo(instanceAWSClient(inst)))
+
+		By("dropping the clients of every region with the instance")
+		forgetInstanceAWSClient(inst)
+		Expect(instanceRegionAWSClient(inst, "us-west-2")).NotTo(BeIdenticalTo(client))
+
+		inst.Spec.RoleARN = ""
+		Expect(instanceRegionAWSClient(inst, "us-west-2")).To(BeIdenticalTo(awsClient("us-west-2")))
+	})
+
+	It("should report whether the role could be assumed in the CrossAccountReady condition", func() {
+		Expect(setCrossAccountCondition(inst, errors.New("AccessDenied"))).To(BeTrue())
+		condition := apimeta.FindStatusCondition(inst.Status.Conditions, computev1.ConditionCrossAccountReady)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("AccessDenied"))
+
+		Expect(setCrossAccountCondition(inst, nil)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionTrue(inst.Status.Conditions, computev1.ConditionCrossAccountReady)).To(BeTrue())
+
+		inst.Spec.RoleARN = ""
+		Expect(setCrossAccountCondition(inst, nil)).To(BeTrue())
+		Expect(inst.Status.Conditions).To(BeEmpty())
+	})
+})
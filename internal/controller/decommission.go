@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// defaultDecommissionStepTimeout applies when a step does not set timeoutSeconds.
+const defaultDecommissionStepTimeout = 600 * time.Second
+
+// shellQuote quotes s for use as a single word in a shell command.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// decommissionCommands returns the shell commands an S3Export or SSMCommand step runs on the instance.
+func decommissionCommands(step *computev1.DecommissionStep, instanceID string) []string {
+	if step.Type == computev1.DecommissionStepS3Export {
+		export := step.S3Export
+		source := export.SourcePath
+		if source == "" {
+			source = "/data"
+		}
+		destination := fmt.Sprintf("s3://%s/%s%s/", export.Bucket, export.Prefix, instanceID)
+		return []string{"aws s3 sync " + shellQuote(source) + " " + shellQuote(destination)}
+	}
+	return step.SSMCommand.Commands
+}
+
+// decommissionStepTimeout returns how long step may run.
+func decommissionStepTimeout(step *computev1.DecommissionStep) time.Duration {
+	if step.TimeoutSeconds > 0 {
+		return time.Duration(step.TimeoutSeconds) * time.Second
+	}
+	return defaultDecommissionStepTimeout
+}
+
+// runDecommissionWorkflow runs the steps of spec.decommissionWorkflow in order and reports whether
+// all of them are done. Progress is written to status after every change, so a restarted operator
+// picks up the step, and the SSM command it started, where it was.
+func (r *Ec2InstanceReconciler) runDecommissionWorkflow(ctx context.Context, ec2Instance *computev1.Ec2Instance) (bool, error) {
+	steps := ec2Instance.Spec.DecommissionWorkflow.Steps
+	if len(steps) == 0 || ec2Instance.Status.InstanceID == "" {
+		return true, nil
+	}
+	original := ec2Instance.Status.DeepCopy()
+	progress := ec2Instance.Status.DecommissionProgress
+	if progress == nil {
+		progress = &computev1.DecommissionProgress{}
+		ec2Instance.Status.DecommissionProgress = progress
+	}
+
+	done := true
+	var stepErr error
+	for len(progress.CompletedSteps) < len(steps) {
+		step := &steps[len(progress.CompletedSteps)]
+		var result *computev1.DecommissionStepResult
+		result, stepErr = r.runDecommissionStep(ctx, ec2Instance, step, progress)
+		if stepErr != nil || result == nil {
+			done = false
+			break
+		}
+
+		if result.Result != computev1.DecommissionStepSucceeded && !step.ContinueOnFailure {
+			progress.Attempts++
+			progress.StepStartedAt = nil
+			progress.CommandID = ""
+			r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "DecommissionStepFailed",
+				"Decommission step %d (%s) %s, retrying: %s", len(progress.CompletedSteps)+1, step.Type, strings.ToLower(result.Result), result.Message)
+			done = false
+			break
+		}
+		log.FromContext(ctx).Info("Decommission step finished", "step", len(progress.CompletedSteps)+1, "type", step.Type, "result", result.Result)
+		progress.CompletedSteps = append(progress.CompletedSteps, *result)
+		progress.StepStartedAt = nil
+		progress.CommandID = ""
+		progress.Attempts = 0
+	}
+	progress.RemainingSteps = int32(max(len(steps)-len(progress.CompletedSteps), 0))
+
+	if !equality.Semantic.DeepEqual(*original, ec2Instance.Status) {
+		if err := r.Status().Update(ctx, ec2Instance); err != nil {
+			return false, err
+		}
+	}
+	if done && (original.DecommissionProgress == nil || original.DecommissionProgress.RemainingSteps > 0) {
+		r.Recorder.Event(ec2Instance, corev1.EventTypeNormal, "Decommissioned", "Decommission workflow finished, terminating the instance")
+	}
+	return done, stepErr
+}
+
+// runDecommissionStep advances step and returns its result once it has finished, or nil while it runs.
+func (r *Ec2InstanceReconciler) runDecommissionStep(ctx context.Context, ec2Instance *computev1.Ec2Instance, step *computev1.DecommissionStep, progress *computev1.DecommissionProgress) (*computev1.DecommissionStepResult, error) {
+	finished := func(result, message string) *computev1.DecommissionStepResult {
+		return &computev1.DecommissionStepResult{Type: step.Type, Result: result, Message: message, CompletedAt: metav1.Now()}
+	}
+	if progress.StepStartedAt == nil {
+		now := metav1.Now()
+		progress.StepStartedAt = &now
+	}
+	client := instanceSSMClient(ec2Instance)
+
+	if time.Since(progress.StepStartedAt.Time) > decommissionStepTimeout(step) {
+		if progress.CommandID != "" {
+			// Best effort; the command may have finished in the meantime.
+			_, _ = client.CancelCommand(ctx, &ssm.CancelCommandInput{CommandId: aws.String(progress.CommandID)})
+		}
+		return finished(computev1.DecommissionStepTimedOut, fmt.Sprintf("did not finish within %s", decommissionStepTimeout(step))), nil
+	}
+
+	if step.Type == computev1.DecommissionStepSNSNotify {
+		message := step.SNSNotify.Message
+		if message == "" {
+			message = fmt.Sprintf("Ec2Instance %s/%s (%s) is being decommissioned and will be terminated.",
+				ec2Instance.Namespace, ec2Instance.Name, ec2Instance.Status.InstanceID)
+		}
+		_, err := instanceSNSClient(ec2Instance).Publish(ctx, &sns.PublishInput{
+			TopicArn: aws.String(step.SNSNotify.TopicARN),
+			Subject:  aws.String("Decommissioning " + ec2Instance.Status.InstanceID),
+			Message:  aws.String(message),
+		})
+		if err != nil {
+			return finished(computev1.DecommissionStepFailed, err.Error()), nil
+		}
+		return finished(computev1.DecommissionStepSucceeded, ""), nil
+	}
+
+	// Commands need the SSM agent, which only answers while the instance runs.
+	if ec2Instance.Status.State != "running" {
+		return finished(computev1.DecommissionStepFailed, fmt.Sprintf("instance is %s, commands need it running", ec2Instance.Status.State)), nil
+	}
+
+	if progress.CommandID == "" {
+		command, err := client.SendCommand(ctx, &ssm.SendCommandInput{
+			DocumentName: aws.String("AWS-RunShellScript"),
+			InstanceIds:  []string{ec2Instance.Status.InstanceID},
+			Comment:      aws.String(fmt.Sprintf("Decommission %s/%s", ec2Instance.Namespace, ec2Instance.Name)),
+			Parameters: map[string][]string{
+				"commands":         decommissionCommands(step, ec2Instance.Status.InstanceID),
+				"executionTimeout": {strconv.Itoa(int(decommissionStepTimeout(step).Seconds()))},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to send decommission command: %w", err)
+		}
+		progress.CommandID = aws.ToString(command.Command.CommandId)
+		return nil, nil
+	}
+
+	invocation, err := client.GetCommandInvocation(ctx, &ssm.GetCommandInvocationInput{
+		CommandId:  aws.String(progress.CommandID),
+		InstanceId: aws.String(ec2Instance.Status.InstanceID),
+	})
+	if err != nil {
+		// The invocation shows up a moment after the command was sent.
+		if strings.Contains(err.Error(), "InvocationDoesNotExist") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get decommission command result: %w", err)
+	}
+	switch invocation.Status {
+	case ssmtypes.CommandInvocationStatusSuccess:
+		return finished(computev1.DecommissionStepSucceeded, ""), nil
+	case ssmtypes.CommandInvocationStatusPending, ssmtypes.CommandInvocationStatusInProgress,
+		ssmtypes.CommandInvocationStatusDelayed, ssmtypes.CommandInvocationStatusCancelling:
+		return nil, nil
+	}
+	message := strings.TrimSpace(aws.ToString(invocation.StandardErrorContent))
+	if message == "" {
+		message = "command " + strings.ToLower(string(invocation.Status))
+	}
+	return finished(computev1.DecommissionStepFailed, message), nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Decommission workflow", func() {
+	It("should sync the source directory to a prefix per instance", func() {
+		step := &computev1.DecommissionStep{
+			Type:     computev1.DecommissionStepS3Export,
+			S3Export: &computev1.S3ExportStep{Bucket: "archive", Prefix: "team-a/"},
+		}
+		Expect(decommissionCommands(step, "i-0abc")).To(Equal([]string{"aws s3 sync '/data' 's3://archive/team-a/i-0abc/'"}))
+	})
+
+	It("should quote paths for the shell", func() {
+		step := &computev1.DecommissionStep{
+			Type:     computev1.DecommissionStepS3Export,
+			S3Export: &computev1.S3ExportStep{Bucket: "archive", SourcePath: "/srv/it's here"},
+		}
+		Expect(decommissionCommands(step, "i-0abc")[0]).To(HavePrefix(`aws s3 sync '/srv/it'\''s here' `))
+	})
+
+	It("should run the commands of an SSMCommand step as they are", func() {
+		step := &computev1.DecommissionStep{
+			Type:       computev1.DecommissionStepSSMCommand,
+			SSMCommand: &computev1.SSMCommandStep{Commands: []string{"systemctl stop app", "pg_dumpall > /data/db.sql"}},
+		}
+		Expect(decommissionCommands(step, "i-0abc")).To(Equal([]string{"systemctl stop app", "pg_dumpall > /data/db.sql"}))
+	})
+
+	It("should default the step timeout", func() {
+		Expect(decommissionStepTimeout(&computev1.DecommissionStep{})).To(Equal(10 * time.Minute))
+		Expect(decommissionStepTimeout(&computev1.DecommissionStep{TimeoutSeconds: 30})).To(Equal(30 * time.Second))
+	})
+})
@@ -2,21 +2,22 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-func deleteEc2Instance(ctx context.Context, ec2Instance *computev1.Ec2Instance) (bool, error) {
+func deleteEc2Instance(ctx context.Context, ec2Client awsclient.EC2Client, ec2Instance *computev1.Ec2Instance) (bool, error) {
 	l := log.FromContext(ctx)
 
 	l.Info("Deleting EC2 instance", "instanceID", ec2Instance.Status.InstanceID)
 
-	// create the client for ec2 instance
-	ec2Client := awsClient(ec2Instance.Spec.Region)
-
 	// Terminate the instance
 	terminateResult, err := ec2Client.TerminateInstances(ctx, &ec2.TerminateInstancesInput{
 		InstanceIds: []string{ec2Instance.Status.InstanceID},
@@ -57,3 +58,23 @@ func deleteEc2Instance(ctx context.Context, ec2Instance *computev1.Ec2Instance)
 	l.Info("EC2 instance successfully terminated", "instanceID", ec2Instance.Status.InstanceID)
 	return true, nil
 }
+
+// disableInstanceMetadata turns off the IMDS HTTP endpoint of the instance. It is called from the
+// finalizer right before termination when spec.disableIMDSOnTermination is set, as defense in depth
+// against credential theft while the instance is shutting down.
+func disableInstanceMetadata(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	l := log.FromContext(ctx)
+
+	ec2Client := instanceAWSClient(ec2Instance)
+
+	_, err := ec2Client.ModifyInstanceMetadataOptions(ctx, &ec2.ModifyInstanceMetadataOptionsInput{
+		InstanceId:   aws.String(ec2Instance.Status.InstanceID),
+		HttpEndpoint: ec2types.InstanceMetadataEndpointStateDisabled,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to disable instance metadata endpoint: %w", err)
+	}
+
+	l.Info("Instance metadata endpoint disabled before termination", "instanceID", ec2Instance.Status.InstanceID)
+	return nil
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"time"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// DefaultDeletionTimeout is how long the finalizer may take to clean up an Ec2Instance when neither
+// spec.deletionTimeout nor --force-delete-timeout say otherwise.
+const DefaultDeletionTimeout = 10 * time.Minute
+
+// deletionTimeout returns how long the finalizer may take to clean up ec2Instance.
+func (r *Ec2InstanceReconciler) deletionTimeout(ec2Instance *computev1.Ec2Instance) time.Duration {
+	if timeout := ec2Instance.Spec.DeletionTimeout; timeout != nil && timeout.Duration > 0 {
+		return timeout.Duration
+	}
+	if r.ForceDeleteTimeout > 0 {
+		return r.ForceDeleteTimeout
+	}
+	return DefaultDeletionTimeout
+}
+
+// deletionTimedOut reports whether ec2Instance has been waiting for its finalizer for longer than
+// timeout at now.
+func deletionTimedOut(ec2Instance *computev1.Ec2Instance, timeout time.Duration, now time.Time) bool {
+	deleted := ec2Instance.DeletionTimestamp
+	return deleted != nil && now.Sub(deleted.Time) > timeout
+}
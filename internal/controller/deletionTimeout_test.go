@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Deletion timeout", func() {
+	It("should prefer spec.deletionTimeout over the operator default", func() {
+		inst := &computev1.Ec2Instance{}
+		Expect((&Ec2InstanceReconciler{}).deletionTimeout(inst)).To(Equal(DefaultDeletionTimeout))
+		Expect((&Ec2InstanceReconciler{ForceDeleteTimeout: time.Hour}).deletionTimeout(inst)).To(Equal(time.Hour))
+
+		inst.Spec.DeletionTimeout = &metav1.Duration{Duration: 2 * time.Minute}
+		Expect((&Ec2InstanceReconciler{ForceDeleteTimeout: time.Hour}).deletionTimeout(inst)).To(Equal(2 * time.Minute))
+	})
+
+	It("should time out once the deletion is older than the timeout", func() {
+		deleted := metav1.NewTime(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+		inst := &computev1.Ec2Instance{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deleted}}
+		Expect(deletionTimedOut(inst, 10*time.Minute, deleted.Add(9*time.Minute))).To(BeFalse())
+		Expect(deletionTimedOut(inst, 10*time.Minute, deleted.Add(11*time.Minute))).To(BeTrue())
+		Expect(deletionTimedOut(&computev1.Ec2Instance{}, 0, deleted.Time)).To(BeFalse())
+	})
+})
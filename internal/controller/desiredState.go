@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	"github.com/bshaw7/operator-repo/internal/statemachine"
+)
+
+// instanceStateAPI is the part of the EC2 API that stops and starts instances.
+type instanceStateAPI interface {
+	StopInstances(ctx context.Context, params *ec2.StopInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error)
+	StartInstances(ctx context.Context, params *ec2.StartInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error)
+}
+
+// unsupportedHibernationErrorCode is what StopInstances fails with when the instance cannot
+// hibernate right now, e.g. because the hibernation agent of its OS is not ready.
+const unsupportedHibernationErrorCode = "UnsupportedHibernationConfiguration"
+
+// desiredInstanceState returns spec.desiredState, which is running unless the instance should be stopped.
+func desiredInstanceState(ec2Instance *computev1.Ec2Instance) ec2types.InstanceStateName {
+	if ec2Instance.Spec.DesiredState == string(ec2types.InstanceStateNameStopped) {
+		return ec2types.InstanceStateNameStopped
+	}
+	return ec2types.InstanceStateNameRunning
+}
+
+// reconcileDesiredState stops or starts the instance to match spec.desiredState and records the
+// state AWS moved it to. It returns true while the instance is on its way, so the caller requeues
+// shortly; an instance that is pending or stopping is waited for rather than asked again.
+func (r *Ec2InstanceReconciler) reconcileDesiredState(ctx context.Context, ec2Client instanceStateAPI, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) (bool, error) {
+	if awsInstance.State == nil {
+		return false, nil
+	}
+	desired := desiredInstanceState(ec2Instance)
+	instanceID := ec2Instance.Status.InstanceID
+
+	switch state := awsInstance.State.Name; {
+	case state == desired:
+		return false, nil
+
+	case state == ec2types.InstanceStateNamePending || state == ec2types.InstanceStateNameStopping:
+		return true, nil
+
+	case state == ec2types.InstanceStateNameRunning && desired == ec2types.InstanceStateNameStopped:
+		if !r.transitionAllowed(ctx, ec2Instance, "stop", statemachine.Stopped) {
+			return false, nil
+		}
+		hibernate := ec2Instance.Spec.HibernationEnabled
+		out, err := ec2Client.StopInstances(ctx, &ec2.StopInstancesInput{
+			InstanceIds: []string{instanceID},
+			Hibernate:   aws.Bool(hibernate),
+		})
+		if err != nil && hibernate && strings.Contains(err.Error(), unsupportedHibernationErrorCode) {
+			r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "HibernationUnsupported",
+				"Stopping instance %s without hibernation: %v", instanceID, err)
+			hibernate = false
+			out, err = ec2Client.StopInstances(ctx, &ec2.StopInstancesInput{InstanceIds: []string{instanceID}})
+		}
+		if err != nil {
+			return true, fmt.Errorf("failed to stop instance: %w", err)
+		}
+		log.FromContext(ctx).Info("Stopping instance", "instanceID", instanceID, "hibernate", hibernate)
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "Stopping", "Stopping instance %s as spec.desiredState is stopped", instanceID)
+		if hibernate {
+			now := metav1.Now()
+			ec2Instance.Status.LastHibernated = &now
+		}
+		if len(out.StoppingInstances) > 0 && out.StoppingInstances[0].CurrentState != nil {
+			ec2Instance.Status.State = string(out.StoppingInstances[0].CurrentState.Name)
+		}
+		return true, nil
+
+	case state == ec2types.InstanceStateNameStopped && desired == ec2types.InstanceStateNameRunning:
+		if !r.transitionAllowed(ctx, ec2Instance, "start", statemachine.Running) {
+			return false, nil
+		}
+		out, err := ec2Client.StartInstances(ctx, &ec2.StartInstancesInput{InstanceIds: []string{instanceID}})
+		if err != nil {
+			return true, fmt.Errorf("failed to start instance: %w", err)
+		}
+		log.FromContext(ctx).Info("Starting instance", "instanceID", instanceID)
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "Starting", "Starting instance %s as spec.desiredState is running", instanceID)
+		if len(out.StartingInstances) > 0 && out.StartingInstances[0].CurrentState != nil {
+			ec2Instance.Status.State = string(out.StartingInstances[0].CurrentState.Name)
+		}
+		return true, nil
+	}
+
+	// Shutting down or terminated: the self-healing path takes it from here.
+	return false, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// fakeStateAPI answers stop and start calls with the transitional state AWS reports.
+type fakeStateAPI struct {
+	calls     []string
+	hibernate bool
+	// hibernateErr fails stops that ask for hibernation.
+	hibernateErr error
+}
+
+func (f *fakeStateAPI) StopInstances(_ context.Context, in *ec2.StopInstancesInput, _ ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error) {
+	f.calls = append(f.calls, "StopInstances")
+	f.hibernate = aws.ToBool(in.Hibernate)
+	if f.hibernate && f.hibernateErr != nil {
+		return nil, f.hibernateErr
+	}
+	return &ec2.StopInstancesOutput{StoppingInstances: []ec2types.InstanceStateChange{
+		{CurrentState: &ec2types.InstanceState{Name: ec2types.InstanceStateNameStopping}},
+	}}, nil
+}
+
+func (f *fakeStateAPI) StartInstances(context.Context, *ec2.StartInstancesInput, ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error) {
+	f.calls = append(f.calls, "StartInstances")
+	return &ec2.StartInstancesOutput{StartingInstances: []ec2types.InstanceStateChange{
+		{CurrentState: &ec2types.InstanceState{Name: ec2types.InstanceStateNamePending}},
+	}}, nil
+}
+
+var _ = Describe("Desired state", func() {
+	var (
+		fake *fakeStateAPI
+		r    *Ec2InstanceReconciler
+		inst *computev1.Ec2Instance
+	)
+
+	awsInstanceIn := func(state ec2types.InstanceStateName) *ec2types.Instance {
+		return &ec2types.Instance{State: &ec2types.InstanceState{Name: state}}
+	}
+
+	BeforeEach(func() {
+		fake = &fakeStateAPI{}
+		r = &Ec2InstanceReconciler{Recorder: record.NewFakeRecorder(10)}
+		inst = &computev1.Ec2Instance{Status: computev1.Ec2InstanceStatus{InstanceID: "i-0123", State: "running"}}
+	})
+
+	It("should stop a running instance that is to be stopped and record the new state", func() {
+		inst.Spec.DesiredState = "stopped"
+		inst.Spec.HibernationEnabled = true
+		transitioning, err := r.reconcileDesiredState(context.Background(), fake, inst, awsInstanceIn(ec2types.InstanceStateNameRunning))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transitioning).To(BeTrue())
+		Expect(fake.calls).To(Equal([]string{"StopInstances"}))
+		Expect(fake.hibernate).To(BeTrue())
+		Expect(inst.Status.State).To(Equal("stopping"))
+		Expect(inst.Status.LastHibernated).NotTo(BeNil())
+	})
+
+	It("should stop without hibernation when the instance cannot hibernate", func() {
+		recorder := record.NewFakeRecorder(10)
+		r.Recorder = recorder
+		fake.hibernateErr = errors.New("api error UnsupportedHibernationConfiguration: the instance is not ready to hibernate yet")
+		inst.Spec.DesiredState = "stopped"
+		inst.Spec.HibernationEnabled = true
+		transitioning, err := r.reconcileDesiredState(context.Background(), fake, inst, awsInstanceIn(ec2types.InstanceStateNameRunning))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transitioning).To(BeTrue())
+		Expect(fake.calls).To(Equal([]string{"StopInstances", "StopInstances"}))
+		Expect(fake.hibernate).To(BeFalse())
+		Expect(inst.Status.LastHibernated).To(BeNil())
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning HibernationUnsupported")))
+	})
+
+	It("should start a stopped instance when no desired state is set", func() {
+		inst.Status.State = "stopped"
+		transitioning, err := r.reconcileDesiredState(context.Background(), fake, inst, awsInstanceIn(ec2types.InstanceStateNameStopped))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transitioning).To(BeTrue())
+		Expect(fake.calls).To(Equal([]string{"StartInstances"}))
+		Expect(inst.Status.State).To(Equal("pending"))
+	})
+
+	It("should wait for a transitional state instead of calling AWS again", func() {
+		inst.Spec.DesiredState = "stopped"
+		transitioning, err := r.reconcileDesiredState(context.Background(), fake, inst, awsInstanceIn(ec2types.InstanceStateNameStopping))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transitioning).To(BeTrue())
+		Expect(fake.calls).To(BeEmpty())
+	})
+
+	It("should leave an instance in the desired state alone", func() {
+		transitioning, err := r.reconcileDesiredState(context.Background(), fake, inst, awsInstanceIn(ec2types.InstanceStateNameRunning))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transitioning).To(BeFalse())
+		Expect(fake.calls).To(BeEmpty())
+	})
+})
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// defaultDNSRecordTTL is used when no TTL is given for a record managed by the operator.
+const defaultDNSRecordTTL int64 = 60
+
+// changeARecord applies a single change (UPSERT or DELETE) to an A record pointing at ip.
+func changeARecord(ctx context.Context, action route53types.ChangeAction, hostedZoneID, recordName, ip string, ttl int64) error {
+	if ttl <= 0 {
+		ttl = defaultDNSRecordTTL
+	}
+
+	_, err := route53Client().ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(hostedZoneID),
+		ChangeBatch: &route53types.ChangeBatch{
+			Comment: aws.String("managed by ec2-operator"),
+			Changes: []route53types.Change{{
+				Action: action,
+				ResourceRecordSet: &route53types.ResourceRecordSet{
+					Name:            aws.String(recordName),
+					Type:            route53types.RRTypeA,
+					TTL:             aws.Int64(ttl),
+					ResourceRecords: []route53types.ResourceRecord{{Value: aws.String(ip)}},
+				},
+			}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to %s A record %s: %w", action, recordName, err)
+	}
+	return nil
+}
+
+// upsertARecord creates or updates the A record recordName so it points at ip.
+func upsertARecord(ctx context.Context, hostedZoneID, recordName, ip string, ttl int64) error {
+	return changeARecord(ctx, route53types.ChangeActionUpsert, hostedZoneID, recordName, ip, ttl)
+}
+
+// deleteARecord deletes the A record recordName if it still points at ip. Route53 only deletes a
+// record given its exact current values, so they are looked up first. A record that is gone or was
+// pointed elsewhere by someone else is left alone.
+func deleteARecord(ctx context.Context, hostedZoneID, recordName, ip string) error {
+	client := route53Client()
+	result, err := client.ListResourceRecordSets(ctx, &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(hostedZoneID),
+		StartRecordName: aws.String(recordName),
+		StartRecordType: route53types.RRTypeA,
+		MaxItems:        aws.Int32(1),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to look up A record %s: %w", recordName, err)
+	}
+	if len(result.ResourceRecordSets) == 0 {
+		return nil
+	}
+	record := result.ResourceRecordSets[0]
+	if !sameDNSName(aws.ToString(record.Name), recordName) || record.Type != route53types.RRTypeA || !recordPointsAt(record, ip) {
+		return nil
+	}
+
+	_, err = client.ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(hostedZoneID),
+		ChangeBatch: &route53types.ChangeBatch{
+			Comment: aws.String("managed by ec2-operator"),
+			Changes: []route53types.Change{{Action: route53types.ChangeActionDelete, ResourceRecordSet: &record}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to DELETE A record %s: %w", recordName, err)
+	}
+	return nil
+}
+
+// sameDNSName reports whether two DNS names are equal. Route53 returns names fully qualified, with
+// a trailing dot.
+func sameDNSName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+// recordPointsAt reports whether ip is one of the values of record.
+func recordPointsAt(record route53types.ResourceRecordSet, ip string) bool {
+	for _, value := range record.ResourceRecords {
+		if aws.ToString(value.Value) == ip {
+			return true
+		}
+	}
+	return false
+}
+
+// dnsRecordOutdated reports whether the record of spec.dns has to be written: it is not registered
+// yet, moved, points at another address or its TTL may have changed with the spec.
+func dnsRecordOutdated(ec2Instance *computev1.Ec2Instance, ip string) bool {
+	status := &ec2Instance.Status
+	return status.DNSRecordName != ec2Instance.Spec.DNS.RecordName ||
+		status.DNSHostedZoneID != ec2Instance.Spec.DNS.HostedZoneID ||
+		status.DNSRecordIP != ip ||
+		status.ObservedGeneration != ec2Instance.Generation
+}
+
+// reconcileDNSRecord points the A record of spec.dns at the instance once it has an IP, and
+// records it in status. A record that was renamed, moved to another zone or dropped from the spec
+// is deleted.
+func reconcileDNSRecord(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	spec := ec2Instance.Spec.DNS
+	status := &ec2Instance.Status
+	if spec == nil {
+		return deleteDNSRecord(ctx, ec2Instance)
+	}
+	ip := instanceIP(status, spec.UsePrivateIP)
+	if ip == "" || !dnsRecordOutdated(ec2Instance, ip) {
+		return nil
+	}
+
+	if status.DNSRecordName != spec.RecordName || status.DNSHostedZoneID != spec.HostedZoneID {
+		if err := deleteDNSRecord(ctx, ec2Instance); err != nil {
+			return err
+		}
+	}
+	if err := upsertARecord(ctx, spec.HostedZoneID, spec.RecordName, ip, spec.TTL); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Registered DNS record", "recordName", spec.RecordName, "ip", ip)
+	status.DNSRecordName, status.DNSHostedZoneID, status.DNSRecordIP = spec.RecordName, spec.HostedZoneID, ip
+	return nil
+}
+
+// deleteDNSRecord deletes the A record recorded in status, if any.
+func deleteDNSRecord(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	status := &ec2Instance.Status
+	if status.DNSRecordName == "" {
+		return nil
+	}
+	if err := deleteARecord(ctx, status.DNSHostedZoneID, status.DNSRecordName, status.DNSRecordIP); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Deleted DNS record", "recordName", status.DNSRecordName)
+	status.DNSRecordName, status.DNSHostedZoneID, status.DNSRecordIP = "", "", ""
+	return nil
+}
+
+// instanceIP returns the address a DNS record for the instance should point at: the public IP when
+// there is one, otherwise the private IP. Missing addresses are stored as "<nil>" by derefString.
+func instanceIP(status *computev1.Ec2InstanceStatus, preferPrivate bool) string {
+	public, private := status.PublicIP, status.PrivateIP
+	if public == "<nil>" {
+		public = ""
+	}
+	if private == "<nil>" {
+		private = ""
+	}
+	if preferPrivate || public == "" {
+		return private
+	}
+	return public
+}
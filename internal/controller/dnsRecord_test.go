@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("DNS records", func() {
+	It("should compare DNS names the way Route53 returns them", func() {
+		Expect(sameDNSName("web.example.com.", "Web.Example.com")).To(BeTrue())
+		Expect(sameDNSName("web.example.com.", "api.example.com")).To(BeFalse())
+
+		record := route53types.ResourceRecordSet{ResourceRecords: []route53types.ResourceRecord{{Value: aws.String("10.0.0.1")}}}
+		Expect(recordPointsAt(record, "10.0.0.1")).To(BeTrue())
+		Expect(recordPointsAt(record, "10.0.0.2")).To(BeFalse())
+	})
+
+	It("should only write the record when it changed", func() {
+		inst := &computev1.Ec2Instance{}
+		inst.Generation = 1
+		inst.Spec.DNS = &computev1.DNSRecordSpec{HostedZoneID: "Z1", RecordName: "web.example.com", TTL: 60}
+		Expect(dnsRecordOutdated(inst, "10.0.0.1")).To(BeTrue())
+
+		inst.Status.ObservedGeneration = 1
+		inst.Status.DNSRecordName, inst.Status.DNSHostedZoneID, inst.Status.DNSRecordIP = "web.example.com", "Z1", "10.0.0.1"
+		Expect(dnsRecordOutdated(inst, "10.0.0.1")).To(BeFalse())
+		Expect(dnsRecordOutdated(inst, "10.0.0.2")).To(BeTrue())
+
+		inst.Generation = 2
+		Expect(dnsRecordOutdated(inst, "10.0.0.1")).To(BeTrue())
+	})
+
+	It("should wait for an IP before registering the record", func() {
+		inst := &computev1.Ec2Instance{}
+		inst.Spec.DNS = &computev1.DNSRecordSpec{HostedZoneID: "Z1", RecordName: "web.example.com", UsePrivateIP: true}
+		inst.Status.PublicIP = "203.0.113.10"
+		inst.Status.PrivateIP = "<nil>"
+		Expect(reconcileDNSRecord(context.Background(), inst)).To(Succeed())
+		Expect(inst.Status.DNSRecordName).To(BeEmpty())
+	})
+})
@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// ebsVolumeOwnerTag marks the volumes created for spec.ebsVolumes with the namespace/name of their Ec2Instance.
+const ebsVolumeOwnerTag = "ec2instance.compute.cloud.com/volume-of"
+
+// ebsVolumeAPI is the part of the EC2 API that manages the volumes of spec.ebsVolumes.
+type ebsVolumeAPI interface {
+	DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
+	CreateVolume(ctx context.Context, params *ec2.CreateVolumeInput, optFns ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error)
+	AttachVolume(ctx context.Context, params *ec2.AttachVolumeInput, optFns ...func(*ec2.Options)) (*ec2.AttachVolumeOutput, error)
+	DetachVolume(ctx context.Context, params *ec2.DetachVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DetachVolumeOutput, error)
+	DeleteVolume(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error)
+}
+
+// describeEBSVolumes returns the volumes of status.ebsVolumeIDs that still exist, by ID. A filter
+// is used rather than VolumeIds, which fails the whole call when one of them is gone.
+func describeEBSVolumes(ctx context.Context, ec2Client ebsVolumeAPI, volumeIDs map[string]string) (map[string]ec2types.Volume, error) {
+	volumes := map[string]ec2types.Volume{}
+	if len(volumeIDs) == 0 {
+		return volumes, nil
+	}
+	ids := make([]string, 0, len(volumeIDs))
+	for _, id := range volumeIDs {
+		ids = append(ids, id)
+	}
+	result, err := ec2Client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("volume-id"), Values: ids}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe EBS volumes: %w", err)
+	}
+	for _, volume := range result.Volumes {
+		volumes[aws.ToString(volume.VolumeId)] = volume
+	}
+	return volumes, nil
+}
+
+// ebsVolumeOwner returns the Ec2Instance the operator created volumeID for, from its
+// ebsVolumeOwnerTag, or "" for a volume the operator did not create.
+func ebsVolumeOwner(ctx context.Context, ec2Client ebsVolumeAPI, volumeID string) (string, error) {
+	volumes, err := describeEBSVolumes(ctx, ec2Client, map[string]string{"": volumeID})
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range volumes[volumeID].Tags {
+		if aws.ToString(tag.Key) == ebsVolumeOwnerTag {
+			return aws.ToString(tag.Value), nil
+		}
+	}
+	return "", nil
+}
+
+// reconcileEBSVolumes creates the volumes of spec.ebsVolumes in the zone of the running instance
+// and attaches them. Volume IDs go to status.ebsVolumeIDs as soon as they exist, so a volume is
+// created once and reattached to whatever instance replaces this one. A volume still being created
+// or detached from a previous instance is attached on a later sync. An operator-created volume
+// already attached at the device is recorded as is.
+func (r *Ec2InstanceReconciler) reconcileEBSVolumes(ctx context.Context, ec2Client ebsVolumeAPI, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	if awsInstance.State == nil || awsInstance.State.Name != ec2types.InstanceStateNameRunning {
+		return nil
+	}
+	if len(ec2Instance.Spec.EBSVolumes) == 0 && len(ec2Instance.Status.EBSVolumeIDs) == 0 {
+		return nil
+	}
+	l := log.FromContext(ctx)
+	instanceID := ec2Instance.Status.InstanceID
+
+	attached := map[string]string{}
+	for _, mapping := range awsInstance.BlockDeviceMappings {
+		if mapping.Ebs != nil {
+			attached[aws.ToString(mapping.DeviceName)] = aws.ToString(mapping.Ebs.VolumeId)
+		}
+	}
+	volumes, err := describeEBSVolumes(ctx, ec2Client, ec2Instance.Status.EBSVolumeIDs)
+	if err != nil {
+		return err
+	}
+	if ec2Instance.Status.EBSVolumeIDs == nil {
+		ec2Instance.Status.EBSVolumeIDs = map[string]string{}
+	}
+
+	wanted := map[string]bool{}
+	for _, spec := range ec2Instance.Spec.EBSVolumes {
+		device := spec.DeviceName
+		wanted[device] = true
+		volumeID := ec2Instance.Status.EBSVolumeIDs[device]
+		if volumeID != "" && attached[device] == volumeID {
+			continue
+		}
+
+		if volumeID != "" {
+			volume, ok := volumes[volumeID]
+			if !ok {
+				l.Info("EBS volume is gone, creating a new one", "device", device, "volumeID", volumeID)
+				r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "VolumeLost", "Volume %s for %s no longer exists, creating a new one", volumeID, device)
+				delete(ec2Instance.Status.EBSVolumeIDs, device)
+				volumeID = ""
+			} else if volume.State != ec2types.VolumeStateAvailable {
+				continue
+			}
+		}
+
+		if existing := attached[device]; existing != "" {
+			// A volume the operator created for the instance under another Ec2Instance, e.g. before
+			// it was adopted, is taken over instead of reported.
+			if volumeID == "" {
+				owner, err := ebsVolumeOwner(ctx, ec2Client, existing)
+				if err != nil {
+					return err
+				}
+				if owner != "" {
+					ec2Instance.Status.EBSVolumeIDs[device] = existing
+					l.Info("Took over attached EBS volume", "device", device, "volumeID", existing, "owner", owner)
+					r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "VolumeAdopted", "Took over volume %s at %s, created for %s", existing, device, owner)
+					continue
+				}
+			}
+			r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "DeviceInUse", "Cannot attach a volume at %s: volume %s is attached there", device, existing)
+			continue
+		}
+
+		if volumeID == "" {
+			input := &ec2.CreateVolumeInput{
+				AvailabilityZone: awsInstance.Placement.AvailabilityZone,
+				VolumeType:       ec2types.VolumeType(spec.VolumeType),
+				Encrypted:        aws.Bool(spec.Encrypted),
+				TagSpecifications: []ec2types.TagSpecification{{
+					ResourceType: ec2types.ResourceTypeVolume,
+					Tags: []ec2types.Tag{
+						{Key: aws.String(ebsVolumeOwnerTag), Value: aws.String(ec2Instance.Namespace + "/" + ec2Instance.Name)},
+						{Key: aws.String("Name"), Value: aws.String(ec2Instance.Name + ":" + device)},
+					},
+				}},
+			}
+			if spec.SizeGB > 0 {
+				input.Size = aws.Int32(spec.SizeGB)
+			}
+			if spec.SnapshotID != "" {
+				input.SnapshotId = aws.String(spec.SnapshotID)
+			}
+			created, err := ec2Client.CreateVolume(ctx, input)
+			if err != nil {
+				return fmt.Errorf("failed to create EBS volume for %s: %w", device, err)
+			}
+			volumeID = aws.ToString(created.VolumeId)
+			ec2Instance.Status.EBSVolumeIDs[device] = volumeID
+			l.Info("Created EBS volume", "device", device, "volumeID", volumeID)
+			r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "VolumeCreated", "Created volume %s for %s", volumeID, device)
+			if created.State != ec2types.VolumeStateAvailable {
+				continue
+			}
+		}
+
+		if _, err := ec2Client.AttachVolume(ctx, &ec2.AttachVolumeInput{
+			Device:     aws.String(device),
+			InstanceId: aws.String(instanceID),
+			VolumeId:   aws.String(volumeID),
+		}); err != nil {
+			return fmt.Errorf("failed to attach EBS volume %s at %s: %w", volumeID, device, err)
+		}
+		l.Info("Attached EBS volume", "device", device, "volumeID", volumeID)
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "VolumeAttached", "Attached volume %s at %s", volumeID, device)
+	}
+
+	// Volumes dropped from the spec may hold data nobody else has, so they are only detached.
+	for device, volumeID := range ec2Instance.Status.EBSVolumeIDs {
+		if wanted[device] {
+			continue
+		}
+		if attached[device] == volumeID {
+			if _, err := ec2Client.DetachVolume(ctx, &ec2.DetachVolumeInput{
+				InstanceId: aws.String(instanceID),
+				VolumeId:   aws.String(volumeID),
+			}); err != nil {
+				return fmt.Errorf("failed to detach EBS volume %s from %s: %w", volumeID, device, err)
+			}
+		}
+		delete(ec2Instance.Status.EBSVolumeIDs, device)
+		l.Info("Released EBS volume removed from spec", "device", device, "volumeID", volumeID)
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "VolumeReleased", "Detached volume %s from %s; it is no longer managed and was not deleted", volumeID, device)
+	}
+	if len(ec2Instance.Status.EBSVolumeIDs) == 0 {
+		ec2Instance.Status.EBSVolumeIDs = nil
+	}
+	return nil
+}
+
+// deleteEBSVolumes deletes the volumes of status.ebsVolumeIDs once the instance is gone and reports
+// whether all of them are. Volumes with retainOnDelete are only forgotten; terminating the instance
+// already detached them. A volume that is still detaching is deleted on a later call.
+func deleteEBSVolumes(ctx context.Context, ec2Client ebsVolumeAPI, ec2Instance *computev1.Ec2Instance) (bool, error) {
+	if len(ec2Instance.Status.EBSVolumeIDs) == 0 {
+		return true, nil
+	}
+	retain := map[string]bool{}
+	for _, spec := range ec2Instance.Spec.EBSVolumes {
+		retain[spec.DeviceName] = spec.RetainOnDelete
+	}
+	volumes, err := describeEBSVolumes(ctx, ec2Client, ec2Instance.Status.EBSVolumeIDs)
+	if err != nil {
+		return false, err
+	}
+
+	for device, volumeID := range ec2Instance.Status.EBSVolumeIDs {
+		volume, ok := volumes[volumeID]
+		switch {
+		case retain[device]:
+			log.FromContext(ctx).Info("Retaining EBS volume", "device", device, "volumeID", volumeID)
+		case !ok:
+		case volume.State == ec2types.VolumeStateAvailable:
+			if _, err := ec2Client.DeleteVolume(ctx, &ec2.DeleteVolumeInput{VolumeId: aws.String(volumeID)}); err != nil {
+				return false, fmt.Errorf("failed to delete EBS volume %s: %w", volumeID, err)
+			}
+			log.FromContext(ctx).Info("Deleted EBS volume", "device", device, "volumeID", volumeID)
+		case volume.State == ec2types.VolumeStateDeleting:
+		default:
+			continue
+		}
+		delete(ec2Instance.Status.EBSVolumeIDs, device)
+	}
+	return len(ec2Instance.Status.EBSVolumeIDs) == 0, nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// fakeVolumeAPI keeps volumes in memory; new volumes come back available right away.
+type fakeVolumeAPI struct {
+	volumes map[string]ec2types.VolumeState
+	// owners holds the ebsVolumeOwnerTag of the volumes the operator created.
+	owners map[string]string
+	calls  []string
+}
+
+func (f *fakeVolumeAPI) DescribeVolumes(_ context.Context, in *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	out := &ec2.DescribeVolumesOutput{}
+	for _, id := range in.Filters[0].Values {
+		if state, ok := f.volumes[id]; ok {
+			volume := ec2types.Volume{VolumeId: aws.String(id), State: state}
+			if owner, ok := f.owners[id]; ok {
+				volume.Tags = []ec2types.Tag{{Key: aws.String(ebsVolumeOwnerTag), Value: aws.String(owner)}}
+			}
+			out.Volumes = append(out.Volumes, volume)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeVolumeAPI) CreateVolume(context.Context, *ec2.CreateVolumeInput, ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+	f.calls = append(f.calls, "CreateVolume")
+	f.volumes["vol-new"] = ec2types.VolumeStateAvailable
+	return &ec2.CreateVolumeOutput{VolumeId: aws.String("vol-new"), State: ec2types.VolumeStateAvailable}, nil
+}
+
+func (f *fakeVolumeAPI) AttachVolume(_ context.Context, in *ec2.AttachVolumeInput, _ ...func(*ec2.Options)) (*ec2.AttachVolumeOutput, error) {
+	f.calls = append(f.calls, "AttachVolume "+aws.ToString(in.VolumeId))
+	return &ec2.AttachVolumeOutput{}, nil
+}
+
+func (f *fakeVolumeAPI) DetachVolume(_ context.Context, in *ec2.DetachVolumeInput, _ ...func(*ec2.Options)) (*ec2.DetachVolumeOutput, error) {
+	f.calls = append(f.calls, "DetachVolume "+aws.ToString(in.VolumeId))
+	return &ec2.DetachVolumeOutput{}, nil
+}
+
+func (f *fakeVolumeAPI) DeleteVolume(_ context.Context, in *ec2.DeleteVolumeInput, _ ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error) {
+	f.calls = append(f.calls, "DeleteVolume "+aws.ToString(in.VolumeId))
+	delete(f.volumes, aws.ToString(in.VolumeId))
+	return &ec2.DeleteVolumeOutput{}, nil
+}
+
+var _ = Describe("EBS volumes", func() {
+	var (
+		fake *fakeVolumeAPI
+		r    *Ec2InstanceReconciler
+		inst *computev1.Ec2Instance
+	)
+
+	runningWith := func(attached map[string]string) *ec2types.Instance {
+		awsInstance := &ec2types.Instance{
+			State:     &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning},
+			Placement: &ec2types.Placement{AvailabilityZone: aws.String("eu-west-1a")},
+		}
+		for device, volumeID := range attached {
+			awsInstance.BlockDeviceMappings = append(awsInstance.BlockDeviceMappings, ec2types.InstanceBlockDeviceMapping{
+				DeviceName: aws.String(device), Ebs: &ec2types.EbsInstanceBlockDevice{VolumeId: aws.String(volumeID)},
+			})
+		}
+		return awsInstance
+	}
+
+	BeforeEach(func() {
+		fake = &fakeVolumeAPI{volumes: map[string]ec2types.VolumeState{}}
+		r = &Ec2InstanceReconciler{Recorder: record.NewFakeRecorder(10)}
+		inst = &computev1.Ec2Instance{
+			Spec:   computev1.Ec2InstanceSpec{EBSVolumes: []computev1.EBSVolumeSpec{{DeviceName: "/dev/sdf", SizeGB: 20, VolumeType: "gp3"}}},
+			Status: computev1.Ec2InstanceStatus{InstanceID: "i-0123456789abcdef0"},
+		}
+	})
+
+	It("should create and attach a volume once and record its ID", func() {
+		Expect(r.reconcileEBSVolumes(context.Background(), fake, inst, runningWith(nil))).To(Succeed())
+		Expect(fake.calls).To(Equal([]string{"CreateVolume", "AttachVolume vol-new"}))
+		Expect(inst.Status.EBSVolumeIDs).To(Equal(map[string]string{"/dev/sdf": "vol-new"}))
+
+		fake.calls = nil
+		Expect(r.reconcileEBSVolumes(context.Background(), fake, inst, runningWith(map[string]string{"/dev/sdf": "vol-new"}))).To(Succeed())
+		Expect(fake.calls).To(BeEmpty())
+	})
+
+	It("should reattach the recorded volume to a replacement instance", func() {
+		fake.volumes["vol-old"] = ec2types.VolumeStateAvailable
+		inst.Status.EBSVolumeIDs = map[string]string{"/dev/sdf": "vol-old"}
+		Expect(r.reconcileEBSVolumes(context.Background(), fake, inst, runningWith(nil))).To(Succeed())
+		Expect(fake.calls).To(Equal([]string{"AttachVolume vol-old"}))
+	})
+
+	It("should wait for a volume that is still attached to the previous instance", func() {
+		fake.volumes["vol-old"] = ec2types.VolumeStateInUse
+		inst.Status.EBSVolumeIDs = map[string]string{"/dev/sdf": "vol-old"}
+		Expect(r.reconcileEBSVolumes(context.Background(), fake, inst, runningWith(nil))).To(Succeed())
+		Expect(fake.calls).To(BeEmpty())
+	})
+
+	It("should take over an operator-created volume attached to an adopted instance", func() {
+		fake.volumes["vol-old"] = ec2types.VolumeStateInUse
+		fake.owners = map[string]string{"vol-old": "team-a/web"}
+		Expect(r.reconcileEBSVolumes(context.Background(), fake, inst, runningWith(map[string]string{"/dev/sdf": "vol-old"}))).To(Succeed())
+		Expect(fake.calls).To(BeEmpty())
+		Expect(inst.Status.EBSVolumeIDs).To(Equal(map[string]string{"/dev/sdf": "vol-old"}))
+
+		By("deleting it with the instance")
+		fake.volumes["vol-old"] = ec2types.VolumeStateAvailable
+		Expect(deleteEBSVolumes(context.Background(), fake, inst)).To(BeTrue())
+		Expect(fake.calls).To(Equal([]string{"DeleteVolume vol-old"}))
+	})
+
+	It("should leave a volume the operator did not create at the device alone", func() {
+		fake.volumes["vol-foreign"] = ec2types.VolumeStateInUse
+		Expect(r.reconcileEBSVolumes(context.Background(), fake, inst, runningWith(map[string]string{"/dev/sdf": "vol-foreign"}))).To(Succeed())
+		Expect(fake.calls).To(BeEmpty())
+		Expect(inst.Status.EBSVolumeIDs).To(BeEmpty())
+	})
+
+	It("should hand the volumes over to the target of a namespace transfer", func() {
+		inst.Status.EBSVolumeIDs = map[string]string{"/dev/sdf": "vol-old"}
+		target := &computev1.Ec2Instance{}
+		Expect(handOverResources(inst, target)).To(BeTrue())
+		Expect(target.Status.EBSVolumeIDs).To(Equal(map[string]string{"/dev/sdf": "vol-old"}))
+		Expect(handOverResources(inst, target)).To(BeFalse())
+	})
+
+	It("should detach but keep a volume removed from the spec", func() {
+		fake.volumes["vol-old"] = ec2types.VolumeStateInUse
+		inst.Spec.EBSVolumes = nil
+		inst.Status.EBSVolumeIDs = map[string]string{"/dev/sdf": "vol-old"}
+		Expect(r.reconcileEBSVolumes(context.Background(), fake, inst, runningWith(map[string]string{"/dev/sdf": "vol-old"}))).To(Succeed())
+		Expect(fake.calls).To(Equal([]string{"DetachVolume vol-old"}))
+		Expect(inst.Status.EBSVolumeIDs).To(BeNil())
+	})
+
+	It("should delete volumes on deletion unless they are retained", func() {
+		inst.Spec.EBSVolumes = append(inst.Spec.EBSVolumes, computev1.EBSVolumeSpec{DeviceName: "/dev/sdg", SizeGB: 20, RetainOnDelete: true})
+		fake.volumes["vol-f"] = ec2types.VolumeStateInUse
+		fake.volumes["vol-g"] = ec2types.VolumeStateAvailable
+		inst.Status.EBSVolumeIDs = map[string]string{"/dev/sdf": "vol-f", "/dev/sdg": "vol-g"}
+
+		done, err := deleteEBSVolumes(context.Background(), fake, inst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeFalse())
+		Expect(inst.Status.EBSVolumeIDs).To(Equal(map[string]string{"/dev/sdf": "vol-f"}))
+
+		fake.volumes["vol-f"] = ec2types.VolumeStateAvailable
+		done, err = deleteEBSVolumes(context.Background(), fake, inst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeTrue())
+		Expect(fake.calls).To(Equal([]string{"DeleteVolume vol-f"}))
+		Expect(fake.volumes).To(HaveKey("vol-g"))
+	})
+})
@@ -2,26 +2,80 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+	"github.com/bshaw7/operator-repo/internal/naming"
+	"github.com/bshaw7/operator-repo/internal/statemachine"
 )
 
+// ec2InstanceFinalizer makes sure the EC2 instance is cleaned up in AWS before the object is removed.
+const ec2InstanceFinalizer = "ec2instance.compute.cloud.com"
+
+// reconcileInterval is how often a running instance is compared with AWS.
+const reconcileInterval = 30 * time.Second
+
 // Ec2InstanceReconciler is a struct that implements the logic for reconciling Ec2Instance custom resources.
 // It embeds the Kubernetes client.Client interface, which provides methods for interacting with the Kubernetes API server,
 // and holds a pointer to a runtime.Scheme, which is used for type conversions between Go structs and Kubernetes objects.
 // This struct is used to reconcile the Ec2Instance custom resource.
 
 type Ec2InstanceReconciler struct {
-	client.Client                 // Used to perform CRUD operations on Kubernetes resources.
-	Scheme        *runtime.Scheme // Used to map Go types to Kubernetes GroupVersionKinds and vice versa.
+	client.Client                      // Used to perform CRUD operations on Kubernetes resources.
+	Scheme        *runtime.Scheme      // Used to map Go types to Kubernetes GroupVersionKinds and vice versa.
+	Recorder      record.EventRecorder // Used to emit Kubernetes Events visible in kubectl describe.
+
+	// MaxConcurrentReconciles is how many Ec2Instances are reconciled in parallel; 0 means 1.
+	MaxConcurrentReconciles int
+
+	// EC2Client, when set, is used to launch, look up, stop, start, tag and terminate every instance
+	// instead of the client of its region and role. Tests set it to an awsclient.FakeEC2Client.
+	EC2Client awsclient.EC2Client
+
+	// LabelSelector limits the Ec2Instances reconciled to those it matches, so that several operators
+	// can share a cluster. Nil reconciles all of them.
+	LabelSelector labels.Selector
+
+	// Scheduler, when set, wakes the controller up for the stops and starts of spec.schedule. Without
+	// it they are applied at the next periodic sync.
+	Scheduler *CronScheduler
+
+	// CloudWatchClient, when set, is used for the alarms of spec.cloudWatchAlarms instead of the
+	// CloudWatch API of the instance's region. Tests set it to an awsclient.FakeCloudWatchClient.
+	CloudWatchClient awsclient.CloudWatchClient
+
+	// ForceDeleteTimeout is how long the finalizer may take to clean up Ec2Instances that do not set
+	// spec.deletionTimeout; 0 means DefaultDeletionTimeout.
+	ForceDeleteTimeout time.Duration
+
+	// EnforceEBSEncryption launches instances with an encrypted root volume unless their
+	// spec.encryptRootVolume is false.
+	EnforceEBSEncryption bool
+
+	// EnforceIMDSv2 requires session tokens for the instance metadata service of every instance,
+	// whatever spec.metadataOptions says.
+	EnforceIMDSv2 bool
 }
 
 /* Following are "Markers": These comments are special markers that the controller-gen tool (part of the Kubebuilder framework) understands.
@@ -31,6 +85,15 @@ This file defines all the permissions your controller needs to interact with Kub
 // +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=amis,verbs=get;create
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2operatorconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=maintenancewindows,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2securitygroups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2keypairs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2launchtemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -45,8 +108,14 @@ This file defines all the permissions your controller needs to interact with Kub
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.2/pkg/reconcile
-func (r *Ec2InstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *Ec2InstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	l := log.FromContext(ctx)
+	ctx, span := tracer.Start(ctx, "Reconcile", trace.WithAttributes(
+		attribute.String("k8s.namespace", req.Namespace), attribute.String("k8s.name", req.Name)))
+	defer func(start time.Time) {
+		observeReconcile(start, err)
+		endSpan(span, err)
+	}(time.Now())
 
 	// TODO(user): your logic here
 	l.Info("=== RECONCILE LOOP STARTED ===", "namespace", req.Namespace, "name", req.Name)
@@ -57,27 +126,176 @@ func (r *Ec2InstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if err := r.Get(ctx, req.NamespacedName, ec2Instance); err != nil {
 		if errors.IsNotFound(err) {
 			l.Info("Instance Deleted. No need to reconcile")
+			if r.Scheduler != nil {
+				r.Scheduler.Forget(req.NamespacedName)
+			}
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
+	defer func() {
+		// Throttling passes once fewer calls are made; retrying soon beats the error backoff.
+		if isThrottlingError(err) {
+			l.Info("AWS is throttling requests, retrying shortly", "error", err.Error())
+			result, err = ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+			return
+		}
+		r.recordAWSError(ec2Instance, err)
+	}()
+
+	// A paused instance is left exactly as it is, in AWS and in Kubernetes, until it is resumed.
+	paused, err := r.reconcilePause(ctx, ec2Instance)
+	if err != nil || paused {
+		return ctrl.Result{}, err
+	}
+
+	// Every AWS call below, termination included, goes through spec.roleARN when it is set.
+	assumed, err := r.reconcileCrossAccount(ctx, ec2Instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !assumed {
+		return ctrl.Result{RequeueAfter: crossAccountRetryInterval}, nil
+	}
 
 	//check if deletionTimestamp is not zero
 	if !ec2Instance.DeletionTimestamp.IsZero() {
 		l.Info("Has deletionTimestamp, Instance is being deleted")
-		_, err := deleteEc2Instance(ctx, ec2Instance)
-		if err != nil {
-			l.Error(err, "Failed to delete EC2 instance")
-			return ctrl.Result{Requeue: true}, err
+		// A hanging AWS call must not keep the object, and with it its namespace, around forever.
+		if timeout := r.deletionTimeout(ec2Instance); deletionTimedOut(ec2Instance, timeout, time.Now()) {
+			err := fmt.Errorf("deletion did not finish within %s", timeout)
+			l.Error(err, "Removing the finalizer without finishing the cleanup in AWS", "instanceID", ec2Instance.Status.InstanceID)
+			r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, eventForceDeleteTimeout,
+				"Deletion did not finish within %s, removing the finalizer; instance %q may be left behind in AWS", timeout, ec2Instance.Status.InstanceID)
+		} else {
+			r.Recorder.Event(ec2Instance, corev1.EventTypeNormal, eventDeletionStarted, "Cleaning up before removing the finalizer")
+
+			// The anomaly monitor is tied to this object, even when the instance itself is orphaned.
+			if err := deleteCostAnomalyDetection(ctx, ec2Instance); err != nil {
+				l.Error(err, "Failed to remove cost anomaly detection")
+				// Persist whatever was already deleted so the retry does not trip over it.
+				if updateErr := r.Status().Update(ctx, ec2Instance); updateErr != nil {
+					l.Error(updateErr, "Failed to update status")
+				}
+				return ctrl.Result{}, err
+			}
+			if err := deleteHealthCheck(ctx, ec2Instance); err != nil {
+				l.Error(err, "Failed to remove health check")
+				return ctrl.Result{}, err
+			}
+			// Best effort: a target left behind only fails its patch runs, it does not keep anything alive.
+			if err := deregisterPatching(ctx, ec2Instance); err != nil {
+				l.Error(err, "Failed to deregister instance from maintenance window")
+			}
+
+			if ec2Instance.Spec.DeletionPolicy == computev1.DeletionPolicyOrphan {
+				// The instance is handed over to someone else (e.g. a namespace transfer), so leave it running.
+				l.Info("DeletionPolicy is Orphan, leaving EC2 instance running", "instanceID", ec2Instance.Status.InstanceID)
+			} else {
+				// Decommission steps run on the instance, so they go before anything that takes it apart.
+				done, err := r.runDecommissionWorkflow(ctx, ec2Instance)
+				if err != nil {
+					l.Error(err, "Failed to run decommission workflow")
+					return ctrl.Result{}, err
+				}
+				if !done {
+					return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+				}
+
+				// Cut off the metadata endpoint first so nothing can grab the instance credentials while it shuts down.
+				// This is best effort: failing to disable IMDS must not block the termination itself.
+				if ec2Instance.Spec.DisableIMDSOnTermination && ec2Instance.Status.InstanceID != "" {
+					if err := disableInstanceMetadata(ctx, ec2Instance); err != nil {
+						l.Error(err, "Failed to disable instance metadata before termination", "instanceID", ec2Instance.Status.InstanceID)
+					}
+				}
+
+				if err := deleteXRayConfig(ctx, ec2Instance); err != nil {
+					l.Error(err, "Failed to remove X-Ray configuration")
+					return ctrl.Result{}, err
+				}
+				if err := r.deleteCloudWatchAlarms(ctx, ec2Instance); err != nil {
+					l.Error(err, "Failed to remove CloudWatch alarms")
+					return ctrl.Result{}, err
+				}
+				if err := deleteDNSRecord(ctx, ec2Instance); err != nil {
+					l.Error(err, "Failed to remove DNS record")
+					return ctrl.Result{}, err
+				}
+
+				// An instance that is already terminated cannot be terminated again; only the finalizer is left.
+				if r.transitionAllowed(ctx, ec2Instance, "terminate", statemachine.Terminated) {
+					spanCtx, span := startSpan(ctx, "deleteEc2Instance", ec2Instance)
+					_, err := deleteEc2Instance(spanCtx, r.instanceEC2Client(ec2Instance), ec2Instance)
+					endSpan(span, err)
+					if err != nil {
+						l.Error(err, "Failed to delete EC2 instance")
+						return ctrl.Result{Requeue: true}, err
+					}
+				}
+
+				// Terminating the instance disassociates its Elastic IP and detaches the volumes of
+				// spec.ebsVolumes, but keeps both.
+				allocationBefore, volumesBefore := ec2Instance.Status.ElasticIPAllocationID, len(ec2Instance.Status.EBSVolumeIDs)
+				err = releaseElasticIP(ctx, instanceAWSClient(ec2Instance), ec2Instance)
+				if err == nil {
+					done, err = deleteEBSVolumes(ctx, instanceAWSClient(ec2Instance), ec2Instance)
+				}
+				if ec2Instance.Status.ElasticIPAllocationID != allocationBefore || len(ec2Instance.Status.EBSVolumeIDs) != volumesBefore {
+					if updateErr := r.Status().Update(ctx, ec2Instance); updateErr != nil {
+						return ctrl.Result{}, updateErr
+					}
+				}
+				if err != nil {
+					l.Error(err, "Failed to release Elastic IP or delete EBS volumes")
+					return ctrl.Result{}, err
+				}
+				if !done {
+					return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+				}
+			}
 		}
 
 		// Remove the finalizer
-		controllerutil.RemoveFinalizer(ec2Instance, "ec2instance.compute.cloud.com")
+		controllerutil.RemoveFinalizer(ec2Instance, ec2InstanceFinalizer)
 		if err := r.Update(ctx, ec2Instance); err != nil {
 			l.Error(err, "Failed to remove finalizer")
 			// Kubernetes will retry with backoff
 			return ctrl.Result{Requeue: true}, err
 		}
+		forgetInstanceAWSClient(ec2Instance)
+		if r.Scheduler != nil {
+			r.Scheduler.Forget(req.NamespacedName)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// A scheduled stop or start is written to spec.desiredState first, and then acted on below.
+	if err := r.reconcileSchedule(ctx, ec2Instance); err != nil {
+		l.Error(err, "Failed to apply spec.schedule")
+		return ctrl.Result{}, err
+	}
+
+	// Hand the instance over to another namespace if a transfer was requested.
+	if targetNamespace := ec2Instance.Annotations[transferToAnnotation]; targetNamespace != "" && ec2Instance.Status.InstanceID != "" {
+		return r.transferInstance(ctx, ec2Instance, targetNamespace)
+	}
+
+	// Capture a console screenshot on request, the graphical counterpart of the console output below.
+	if ec2Instance.Annotations[captureScreenshotAnnotation] == "true" && ec2Instance.Status.InstanceID != "" {
+		if err := r.captureScreenshot(ctx, ec2Instance); err != nil {
+			l.Error(err, "Failed to capture console screenshot")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the console output on request. Removing the annotation triggers the next reconcile.
+	if ec2Instance.Annotations[fetchConsoleOutputAnnotation] == "true" && ec2Instance.Status.InstanceID != "" {
+		if err := r.fetchConsoleOutput(ctx, ec2Instance); err != nil {
+			l.Error(err, "Failed to fetch console output")
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -92,20 +310,61 @@ func (r *Ec2InstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// New logic to check in k8s and aws as well if instance already exist
 
+	// A running instance that was synced recently and whose spec has not changed since gains nothing
+	// from another DescribeInstances call; this keeps status writes from costing AWS calls.
+	if wait, ok := syncSkippable(ec2Instance, time.Now()); ok {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
 	if ec2Instance.Status.InstanceID != "" {
 		// 1. USE THE UNUSED FUNCTION: Check AWS Reality
-		exists, awsInstance, err := checkEC2InstanceExists(ctx, ec2Instance.Status.InstanceID, ec2Instance)
+		spanCtx, span := startSpan(ctx, "checkEC2InstanceExists", ec2Instance)
+		exists, awsInstance, err := checkEC2InstanceExists(spanCtx, r.instanceEC2Client(ec2Instance), ec2Instance.Status.InstanceID)
+		if awsInstance != nil && awsInstance.State != nil {
+			span.SetAttributes(attribute.String("ec2.state", string(awsInstance.State.Name)))
+		}
+		endSpan(span, err)
 		if err != nil {
 			l.Error(err, "Failed to check if instance exists in AWS")
 			return ctrl.Result{}, err
 		}
 
-		// 2. SELF-HEALING: If AWS says "Not Found" or "Terminated"
-		if !exists || string(awsInstance.State.Name) == "terminated" {
+		// 2. SELF-HEALING: If AWS says "Not Found" or "Terminated", or is terminating a Spot instance to rebid
+		if !exists || string(awsInstance.State.Name) == "terminated" || spotRebidDue(ec2Instance, awsInstance) {
+			// A set replaces interrupted Spot instances itself, possibly in another capacity pool.
+			if ec2Instance.Labels[computev1.Ec2InstanceSetLabel] != "" &&
+				(spotInterrupted(awsInstance) || apimeta.IsStatusConditionTrue(ec2Instance.Status.Conditions, computev1.ConditionSpotInterrupted)) {
+				return ctrl.Result{}, r.markSpotInterrupted(ctx, ec2Instance)
+			}
+
+			if spotInterrupted(awsInstance) {
+				r.setSpotInterrupted(ec2Instance, "SpotCapacityReclaimed",
+					fmt.Sprintf("Spot instance %s was interrupted by AWS", ec2Instance.Status.InstanceID))
+			}
 			l.Info("Instance found in Status but missing/terminated in AWS. Triggering recreation.", "ID", ec2Instance.Status.InstanceID)
+			r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, eventInstanceTerminated,
+				"Instance %s is gone from AWS, launching a replacement", ec2Instance.Status.InstanceID)
+
+			// The anomaly monitor filters on the old instance ID; the replacement gets its own.
+			if err := deleteCostAnomalyDetection(ctx, ec2Instance); err != nil {
+				l.Error(err, "Failed to remove cost anomaly detection of the lost instance")
+			}
+			if err := deleteXRayConfig(ctx, ec2Instance); err != nil {
+				l.Error(err, "Failed to remove X-Ray configuration of the lost instance")
+			}
+			ec2Instance.Status.XRayEnabled = false
+			// The alarms watch the lost instance by its ID.
+			if err := r.deleteCloudWatchAlarms(ctx, ec2Instance); err != nil {
+				l.Error(err, "Failed to remove CloudWatch alarms of the lost instance")
+			}
+			// The health check points at the IP of the lost instance.
+			if err := deleteHealthCheck(ctx, ec2Instance); err != nil {
+				l.Error(err, "Failed to remove health check of the lost instance")
+			}
 
 			// Reset the Status ID to empty.
 			// In the NEXT loop, the operator will see empty ID and create a new one.
+			setDegradedConditions(ec2Instance, awsInstance)
 			ec2Instance.Status.InstanceID = ""
 			ec2Instance.Status.State = "Terminated"
 			if err := r.Status().Update(ctx, ec2Instance); err != nil {
@@ -117,22 +376,311 @@ func (r *Ec2InstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 
 		// 3. SYNC STATE: If it exists, update the status to match AWS (e.g. "pending" -> "running")
+		originalStatus := ec2Instance.Status.DeepCopy()
 		if ec2Instance.Status.State != string(awsInstance.State.Name) {
 			l.Info("Updating Instance State", "Old", ec2Instance.Status.State, "New", awsInstance.State.Name)
 			ec2Instance.Status.State = string(awsInstance.State.Name)
+			if awsInstance.State.Name == ec2types.InstanceStateNameRunning {
+				r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, eventInstanceRunning, "Instance %s is running", ec2Instance.Status.InstanceID)
+			}
+		}
+		// Status times are stored to the second; a finer launch time would look changed on every sync.
+		if awsInstance.LaunchTime != nil {
+			launchTime := metav1.NewTime(awsInstance.LaunchTime.Truncate(time.Second))
+			ec2Instance.Status.LaunchTime = &launchTime
+		}
+		if awsInstance.Placement != nil {
+			ec2Instance.Status.AvailabilityZone = aws.ToString(awsInstance.Placement.AvailabilityZone)
+		}
+		setSyncedConditions(ec2Instance, awsInstance)
+		ec2Instance.Status.InstanceLifecycle = instanceLifecycle(awsInstance)
+		if err := r.reconcileSpotInterruption(ctx, ec2Instance, awsInstance); err != nil {
+			l.Error(err, "Failed to check for a Spot interruption notice")
+			return ctrl.Result{}, err
+		}
+		spotReclaiming := r.reconcileSpotShutdown(ctx, ec2Instance, awsInstance)
+
+		ebsOptimized, err := isEBSOptimized(ctx, ec2Instance.Spec.Region, awsInstance)
+		if err != nil {
+			l.Error(err, "Failed to determine EBS optimization")
+			return ctrl.Result{}, err
+		}
+		ec2Instance.Status.EBSOptimized = ebsOptimized
+		ec2Instance.Status.NitroEnclaveEnabled = awsInstance.EnclaveOptions != nil && aws.ToBool(awsInstance.EnclaveOptions.Enabled)
+		ec2Instance.Status.CPUOptions = observedCPUOptions(awsInstance)
+		ec2Instance.Status.VpcID = aws.ToString(awsInstance.VpcId)
+		if err := r.reconcileENAExpress(ctx, ec2Instance, awsInstance); err != nil {
+			l.Error(err, "Failed to determine ENA Express settings")
+			return ctrl.Result{}, err
+		}
+
+		// A forced replacement makes every other change moot.
+		replaced, err := r.reconcileReplacement(ctx, ec2Instance, awsInstance)
+		if err != nil {
+			l.Error(err, "Failed to replace instance")
+			return ctrl.Result{}, err
+		}
+		if replaced {
+			return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		}
+
+		// A stop-start resize runs over several reconciles; settle it before touching anything else.
+		resizing, err := r.reconcileResize(ctx, instanceAWSClient(ec2Instance), ec2Instance, awsInstance)
+		if err != nil {
+			l.Error(err, "Failed to resize instance", "phase", ec2Instance.Status.ReconcilePhase)
+			return ctrl.Result{}, err
+		}
+		if resizing {
+			if !equality.Semantic.DeepEqual(*originalStatus, ec2Instance.Status) {
+				if err := r.Status().Update(ctx, ec2Instance); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		}
+
+		// Stop or start the instance when spec.desiredState asks for the other state.
+		transitioning, err := r.reconcileDesiredState(ctx, r.instanceEC2Client(ec2Instance), ec2Instance, awsInstance)
+		if err != nil {
+			l.Error(err, "Failed to reach desired state", "desiredState", ec2Instance.Spec.DesiredState)
+			return ctrl.Result{}, err
+		}
+		if transitioning {
+			if !equality.Semantic.DeepEqual(*originalStatus, ec2Instance.Status) {
+				if err := r.Status().Update(ctx, ec2Instance); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+
+		// 4. CONVERGE SETTINGS: bring instance attributes that can change after launch in line with the spec.
+		if err := r.reconcileAutoRecovery(ctx, instanceAWSClient(ec2Instance), ec2Instance, awsInstance); err != nil {
+			l.Error(err, "Failed to reconcile auto recovery")
+			return ctrl.Result{}, err
+		}
+		if err := r.reconcileSecurityGroups(ctx, ec2Instance, awsInstance); err != nil {
+			l.Error(err, "Failed to reconcile security groups")
+			return ctrl.Result{}, err
+		}
+		if err := r.reconcileIAMInstanceProfile(ctx, ec2Instance, awsInstance); err != nil {
+			l.Error(err, "Failed to reconcile IAM instance profile")
+			return ctrl.Result{}, err
+		}
+		if err := reconcileSourceDestCheck(ctx, ec2Instance, awsInstance); err != nil {
+			l.Error(err, "Failed to reconcile source/destination check")
+			return ctrl.Result{}, err
+		}
+		if err := reconcileInterfaceSourceDestCheck(ctx, ec2Instance, awsInstance); err != nil {
+			l.Error(err, "Failed to reconcile source/destination check of network interfaces")
+			return ctrl.Result{}, err
+		}
+		if err := r.reconcileMetadataOptions(ctx, ec2Instance, awsInstance); err != nil {
+			l.Error(err, "Failed to reconcile instance metadata options")
+			return ctrl.Result{}, err
+		}
+		if err := reconcileXRay(ctx, ec2Instance); err != nil {
+			l.Error(err, "Failed to reconcile X-Ray configuration")
+			return ctrl.Result{}, err
+		}
+		if err := r.reconcileTagDrift(ctx, ec2Instance, awsInstance); err != nil {
+			l.Error(err, "Failed to reconcile tags")
+			return ctrl.Result{}, err
+		}
+		if err := r.reconcileRequiredTags(ctx, ec2Instance, awsInstance); err != nil {
+			l.Error(err, "Failed to reconcile required tags")
+			return ctrl.Result{}, err
+		}
+		r.reconcileTagPolicyCompliance(ctx, ec2Instance, awsInstance)
+		if err := r.reconcileNodeLabels(ctx, ec2Instance, awsInstance); err != nil {
+			l.Error(err, "Failed to reconcile node labels")
+			return ctrl.Result{}, err
+		}
+		// Record anomaly detection ARNs even on failure, otherwise a half-finished setup would be
+		// created again (and leaked) on the next attempt.
+		anomalyErr := reconcileCostAnomalyDetection(ctx, ec2Instance)
+		if anomalyErr != nil {
+			l.Error(anomalyErr, "Failed to reconcile cost anomaly detection")
+		}
+		// Same for snapshots: the retained ones are recorded even if deleting an old one failed.
+		snapshotErr := r.reconcileSnapshotSchedule(ctx, ec2Instance, awsInstance)
+		if snapshotErr != nil {
+			l.Error(snapshotErr, "Failed to reconcile snapshot schedule")
+		}
+		// And for the health check, which must not be created twice.
+		healthCheckErr := reconcileHealthCheck(ctx, ec2Instance, awsInstance)
+		if healthCheckErr != nil {
+			l.Error(healthCheckErr, "Failed to reconcile Route53 health check")
+		}
+		// And for the maintenance window registration.
+		patchErr := r.reconcilePatchManagement(ctx, ec2Instance, awsInstance)
+		if patchErr != nil {
+			l.Error(patchErr, "Failed to reconcile patch management")
+		}
+		// And for EBS volumes and the Elastic IP, which must not be created twice either.
+		volumesErr := r.reconcileEBSVolumes(ctx, instanceAWSClient(ec2Instance), ec2Instance, awsInstance)
+		if volumesErr != nil {
+			l.Error(volumesErr, "Failed to reconcile EBS volumes")
+		}
+		elasticIPErr := r.reconcileElasticIP(ctx, instanceAWSClient(ec2Instance), ec2Instance, awsInstance)
+		if elasticIPErr != nil {
+			l.Error(elasticIPErr, "Failed to reconcile Elastic IP")
+		}
+		// And for the CloudWatch alarms, which must be deleted with the instance.
+		alarmsErr := r.reconcileCloudWatchAlarms(ctx, ec2Instance, awsInstance)
+		if alarmsErr != nil {
+			l.Error(alarmsErr, "Failed to reconcile CloudWatch alarms")
+		}
+		// And for the DNS record, which points at the IP the Elastic IP may just have changed.
+		dnsErr := reconcileDNSRecord(ctx, ec2Instance)
+		if dnsErr != nil {
+			l.Error(dnsErr, "Failed to reconcile DNS record")
+		}
+
+		if anomalyErr == nil && snapshotErr == nil && healthCheckErr == nil && patchErr == nil && volumesErr == nil && elasticIPErr == nil && alarmsErr == nil && dnsErr == nil {
+			now := metav1.Now()
+			ec2Instance.Status.LastSyncTime = &now
+			ec2Instance.Status.ObservedGeneration = ec2Instance.Generation
+		}
+
+		// Only write the status when something actually changed, every write triggers another reconcile.
+		if !equality.Semantic.DeepEqual(*originalStatus, ec2Instance.Status) {
 			if err := r.Status().Update(ctx, ec2Instance); err != nil {
 				return ctrl.Result{}, err
 			}
 		}
+		if anomalyErr != nil {
+			return ctrl.Result{}, anomalyErr
+		}
+		if snapshotErr != nil {
+			return ctrl.Result{}, snapshotErr
+		}
+		if healthCheckErr != nil {
+			return ctrl.Result{}, healthCheckErr
+		}
+		if patchErr != nil {
+			return ctrl.Result{}, patchErr
+		}
+		if volumesErr != nil {
+			return ctrl.Result{}, volumesErr
+		}
+		if elasticIPErr != nil {
+			return ctrl.Result{}, elasticIPErr
+		}
+		if alarmsErr != nil {
+			return ctrl.Result{}, alarmsErr
+		}
+		if dnsErr != nil {
+			return ctrl.Result{}, dnsErr
+		}
+
+		// Look again soon so the replacement is launched as soon as AWS has terminated the instance.
+		if spotReclaiming {
+			return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		}
 
 		// It exists and is healthy. Stop.
+		return ctrl.Result{RequeueAfter: reconcileInterval}, nil
+	}
+
+	// Take over an existing instance instead of launching a new one.
+	if ec2Instance.Spec.AdoptInstanceID != "" {
+		return r.adoptInstance(ctx, ec2Instance)
+	}
+
+	// Hold the launch back until spec.creationCondition is satisfied.
+	met, err := r.creationConditionMet(ctx, ec2Instance)
+	if err != nil {
+		l.Error(err, "Failed to check creation condition")
+		return ctrl.Result{}, err
+	}
+	if !met {
+		l.Info("Creation condition not met, waiting", "condition", ec2Instance.Spec.CreationCondition)
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	// Referenced Ec2SecurityGroups and Ec2KeyPairs have to be created before they can be passed to RunInstances.
+	securityGroupIDs, ready, err := r.securityGroupIDs(ctx, ec2Instance)
+	if err != nil {
+		l.Error(err, "Failed to resolve security group references")
+		return ctrl.Result{}, err
+	}
+	if !ready {
+		l.Info("Waiting for referenced security groups", "securityGroupRefs", referenceNames(ec2Instance.Spec.SecurityGroupRefs))
+		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+	}
+
+	keyName, ready, err := r.keyPairName(ctx, ec2Instance)
+	if err != nil {
+		l.Error(err, "Failed to resolve key pair reference")
+		return ctrl.Result{}, err
+	}
+	if !ready {
+		l.Info("Waiting for referenced key pair", "keyPairRef", ec2Instance.Spec.KeyPairRef.Name)
+		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+	}
+
+	launchTemplate, ready, err := r.launchTemplate(ctx, ec2Instance)
+	if err != nil {
+		l.Error(err, "Failed to resolve launch template reference")
+		return ctrl.Result{}, err
+	}
+	if !ready {
+		l.Info("Waiting for referenced launch template", "ec2LaunchTemplate", ec2Instance.Spec.LaunchTemplateRef.Ec2LaunchTemplate)
+		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+	}
+
+	// Take the AMI and instance type the spec leaves out from the launch template, so the launch
+	// checks and the cost estimate know them. They are recorded in status after the finalizer update.
+	launchAMIID := ec2Instance.Spec.AMIId
+	var selectedType, selectionReason string
+	if launchTemplate != nil && (launchAMIID == "" || ec2Instance.Spec.InstanceType == "") {
+		data, err := launchTemplateData(ctx, r.instanceEC2Client(ec2Instance), launchTemplate)
+		if err != nil {
+			l.Error(err, "Failed to describe launch template")
+			return ctrl.Result{}, err
+		}
+		if launchAMIID == "" {
+			launchAMIID = aws.ToString(data.ImageId)
+		}
+		if ec2Instance.Spec.InstanceType == "" && data.InstanceType != "" {
+			selectedType = string(data.InstanceType)
+			selectionReason = "spec.instanceType is not set, using the instance type of the launch template"
+		}
+	}
+
+	// Make sure the AMI can be launched in the target region, copying it there first if requested.
+	if ec2Instance.Spec.AutoCopyAMI && ec2Instance.Spec.AMIId != "" {
+		amiID, err := r.ensureRegionalAMI(ctx, ec2Instance)
+		if err != nil {
+			l.Error(err, "Failed to make AMI available in region", "ami", ec2Instance.Spec.AMIId, "region", ec2Instance.Spec.Region)
+			return ctrl.Result{}, err
+		}
+		if amiID == "" {
+			l.Info("Waiting for AMI copy to finish", "ami", ec2Instance.Spec.AMIId, "region", ec2Instance.Spec.Region)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		launchAMIID = amiID
+	}
+
+	// Pick the instance type now; it is recorded in status after the finalizer update below.
+	if ec2Instance.Spec.InstanceTypeOptimization == computev1.InstanceTypeOptimizationCostAware {
+		optimizedType, reason, err := selectInstanceType(ctx, ec2Instance, launchAMIID)
+		if err != nil {
+			l.Error(err, "Failed to select instance type")
+			return ctrl.Result{}, err
+		}
+		// Without a pick and without spec.instanceType, the type of the launch template is kept.
+		if optimizedType != "" {
+			selectedType, selectionReason = optimizedType, reason
+		}
+		l.Info("Selected instance type", "instanceType", selectedType, "reason", selectionReason)
+	}
+
 	l.Info("Creating new instance")
 
 	l.Info("=== ABOUT TO ADD FINALIZER ===")
-	ec2Instance.Finalizers = append(ec2Instance.Finalizers, "ec2instance.compute.cloud.com")
+	controllerutil.AddFinalizer(ec2Instance, ec2InstanceFinalizer)
 	if err := r.Update(ctx, ec2Instance); err != nil {
 		l.Error(err, "Failed to add finalizer")
 		return ctrl.Result{
@@ -144,7 +692,44 @@ func (r *Ec2InstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// Create a new instance
 	l.Info("=== CONTINUING WITH EC2 INSTANCE CREATION IN CURRENT RECONCILE ===")
 
-	createdInstanceInfo, err := createEc2Instance(ec2Instance)
+	// Set after the finalizer update, which replaces the in-memory status with the stored one.
+	if launchAMIID != ec2Instance.Spec.AMIId {
+		ec2Instance.Status.SelectedAMIID = launchAMIID
+	}
+	ec2Instance.Status.SelectedInstanceType = selectedType
+	ec2Instance.Status.InstanceTypeSelectionReason = selectionReason
+	apimeta.RemoveStatusCondition(&ec2Instance.Status.Conditions, computev1.ConditionCreationConditionNotMet)
+	apimeta.RemoveStatusCondition(&ec2Instance.Status.Conditions, computev1.ConditionSpotInterrupted)
+	apimeta.RemoveStatusCondition(&ec2Instance.Status.Conditions, computev1.ConditionPendingReplacement)
+
+	config, err := GetOperatorConfig(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	tags := instanceTags(ec2Instance, config.Spec.RequiredTags)
+	instanceName, err := naming.InstanceName(ec2Instance)
+	if err != nil {
+		l.Error(err, "Failed to render spec.namePattern")
+		return ctrl.Result{}, err
+	}
+	if instanceName != "" {
+		tags["Name"] = instanceName
+	}
+	tags[managedByTagKey] = managedByTagValue
+	userData, err := r.specUserData(ctx, ec2Instance)
+	if err != nil {
+		l.Error(err, "Failed to resolve user data")
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, eventInstanceCreating, "Launching a %s instance from %s in %s",
+		launchInstanceType(ec2Instance), launchImageID(ec2Instance), ec2Instance.Spec.Region)
+	_, createSpan := startSpan(ctx, "createEc2Instance", ec2Instance)
+	createdInstanceInfo, err := createEc2Instance(r.instanceEC2Client(ec2Instance), ec2Instance, tags, userData, keyName, securityGroupIDs, launchTemplate, r.launchPolicy())
+	if createdInstanceInfo != nil {
+		createSpan.SetAttributes(attribute.String("ec2.instance_id", createdInstanceInfo.InstanceID),
+			attribute.String("ec2.state", createdInstanceInfo.State))
+	}
+	endSpan(createSpan, err)
 	if err != nil {
 		l.Error(err, "Failed to create EC2 instance")
 		return ctrl.Result{}, err
@@ -160,6 +745,9 @@ func (r *Ec2InstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	ec2Instance.Status.PrivateIP = createdInstanceInfo.PrivateIP
 	ec2Instance.Status.PublicDNS = createdInstanceInfo.PublicDNS
 	ec2Instance.Status.PrivateDNS = createdInstanceInfo.PrivateDNS
+	ec2Instance.Status.InstanceName = instanceName
+	ec2Instance.Status.LaunchedAMIID = ec2Instance.Spec.AMIId
+	setLaunchedConditions(ec2Instance)
 
 	err = r.Status().Update(ctx, ec2Instance)
 	if err != nil {
@@ -171,14 +759,45 @@ func (r *Ec2InstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 }
 
+// syncSkippable reports whether the instance can go without a sync with AWS, and if so how long
+// until the next one is due.
+func syncSkippable(ec2Instance *computev1.Ec2Instance, now time.Time) (time.Duration, bool) {
+	status := ec2Instance.Status
+	if status.InstanceID == "" || status.State != "running" || status.ReconcilePhase != "" ||
+		status.ObservedGeneration != ec2Instance.Generation || status.LastSyncTime == nil {
+		return 0, false
+	}
+	wait := status.LastSyncTime.Add(reconcileInterval).Sub(now)
+	return wait, wait > 0
+}
+
 // SetupWithManager sets up the controller with the Manager.
 // SetupWithManager registers the Ec2InstanceReconciler with the controller manager.
 // It configures the controller to watch for changes to Ec2Instance resources.
 // The controller will be named "ec2instance" for logging and metrics purposes.
 // The Complete(r) call finalizes the setup, associating the reconciler logic with this controller.
 func (r *Ec2InstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// The instance gauge counts the instances in the manager's cache whenever it is scraped.
+	instancesByState.setReader(mgr.GetClient())
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&computev1.Ec2Instance{}).
 		Named("ec2instance").
-		Complete(r)
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		WithEventFilter(labelSelectorPredicate(r.LabelSelector))
+	if r.Scheduler != nil {
+		if err := mgr.Add(r.Scheduler); err != nil {
+			return err
+		}
+		// Only instances the selector matches are ever registered with the scheduler.
+		b = b.WatchesRawSource(r.Scheduler.Source())
+	}
+	return b.Complete(r)
+}
+
+// labelSelectorPredicate lets through the events of objects selector matches, and every event when
+// selector is nil. Objects that do not match are left to whichever operator they belong to.
+func labelSelectorPredicate(selector labels.Selector) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return selector == nil || selector.Matches(labels.Set(obj.GetLabels()))
+	})
 }
@@ -18,11 +18,15 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -69,8 +73,9 @@ var _ = Describe("Ec2Instance Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &Ec2InstanceReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -81,4 +86,65 @@ var _ = Describe("Ec2Instance Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When deciding whether to sync with AWS", func() {
+		now := time.Now()
+		synced := func(ago time.Duration) *computev1.Ec2Instance {
+			lastSync := metav1.NewTime(now.Add(-ago))
+			return &computev1.Ec2Instance{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status: computev1.Ec2InstanceStatus{
+					InstanceID:         "i-0123",
+					State:              "running",
+					ObservedGeneration: 2,
+					LastSyncTime:       &lastSync,
+				},
+			}
+		}
+
+		It("should skip a running instance synced within the interval", func() {
+			wait, ok := syncSkippable(synced(10*time.Second), now)
+			Expect(ok).To(BeTrue())
+			Expect(wait).To(Equal(20 * time.Second))
+		})
+
+		It("should sync once the interval has passed", func() {
+			_, ok := syncSkippable(synced(reconcileInterval), now)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should sync after a spec change", func() {
+			inst := synced(time.Second)
+			inst.Generation = 3
+			_, ok := syncSkippable(inst, now)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should sync instances that are not running", func() {
+			inst := synced(time.Second)
+			inst.Status.State = "pending"
+			_, ok := syncSkippable(inst, now)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("When filtering by label selector", func() {
+		labeled := func(labels map[string]string) *computev1.Ec2Instance {
+			return &computev1.Ec2Instance{ObjectMeta: metav1.ObjectMeta{Labels: labels}}
+		}
+
+		It("should only let through instances the selector matches", func() {
+			selector, err := labels.Parse("team=platform")
+			Expect(err).NotTo(HaveOccurred())
+			filter := labelSelectorPredicate(selector)
+
+			Expect(filter.Generic(event.GenericEvent{Object: labeled(map[string]string{"team": "platform"})})).To(BeTrue())
+			Expect(filter.Generic(event.GenericEvent{Object: labeled(map[string]string{"team": "data"})})).To(BeFalse())
+			Expect(filter.Create(event.CreateEvent{Object: labeled(nil)})).To(BeFalse())
+		})
+
+		It("should let everything through without a selector", func() {
+			Expect(labelSelectorPredicate(nil).Create(event.CreateEvent{Object: labeled(nil)})).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,289 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+// lifecycleTest drives one Ec2Instance in the test environment through the reconciler, with a
+// FakeEC2Client standing in for EC2.
+type lifecycleTest struct {
+	key        types.NamespacedName
+	fake       *awsclient.FakeEC2Client
+	recorder   *record.FakeRecorder
+	reconciler *Ec2InstanceReconciler
+}
+
+// reconcileOnce runs a single reconcile, which must succeed.
+func (t *lifecycleTest) reconcileOnce() reconcile.Result {
+	result, err := t.reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: t.key})
+	Expect(err).NotTo(HaveOccurred())
+	return result
+}
+
+// instance returns the Ec2Instance as stored in the API server.
+func (t *lifecycleTest) instance() *computev1.Ec2Instance {
+	inst := &computev1.Ec2Instance{}
+	Expect(k8sClient.Get(context.Background(), t.key, inst)).To(Succeed())
+	return inst
+}
+
+// awsReports makes EC2 launch instanceID and describe it in state from now on.
+func (t *lifecycleTest) awsReports(instanceID string, state ec2types.InstanceStateName) {
+	t.fake.Outputs["RunInstances"] = &ec2.RunInstancesOutput{Instances: []ec2types.Instance{{InstanceId: aws.String(instanceID)}}}
+	t.fake.Outputs["DescribeInstances"] = &ec2.DescribeInstancesOutput{Reservations: []ec2types.Reservation{{Instances: []ec2types.Instance{{
+		InstanceId:       aws.String(instanceID),
+		InstanceType:     ec2types.InstanceTypeT3Micro,
+		State:            &ec2types.InstanceState{Name: state},
+		LaunchTime:       aws.Time(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)),
+		Placement:        &ec2types.Placement{AvailabilityZone: aws.String("us-east-1a")},
+		PrivateIpAddress: aws.String("10.0.0.10"),
+		PublicDnsName:    aws.String(""),
+		// Both are known without asking AWS about the instance type.
+		EbsOptimized:       aws.Bool(true),
+		MaintenanceOptions: &ec2types.InstanceMaintenanceOptions{AutoRecovery: ec2types.InstanceAutoRecoveryStateDisabled},
+	}}}}}
+}
+
+// events returns the events recorded since the last call.
+func (t *lifecycleTest) events() []string {
+	var events []string
+	for {
+		select {
+		case event := <-t.recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+// setAnnotation updates a single annotation of the stored Ec2Instance.
+func (t *lifecycleTest) setAnnotation(key, value string) {
+	inst := t.instance()
+	if inst.Annotations == nil {
+		inst.Annotations = map[string]string{}
+	}
+	inst.Annotations[key] = value
+	Expect(k8sClient.Update(context.Background(), inst)).To(Succeed())
+}
+
+var _ = Describe("Ec2Instance lifecycle", func() {
+	BeforeEach(func() {
+		// Tag policies come from AWS Organizations, which the fake does not cover; pretend there is none.
+		tagPolicyCache.Lock()
+		tagPolicyCache.byRole[""] = cachedTagPolicy{fetchedAt: time.Now()}
+		tagPolicyCache.Unlock()
+	})
+
+	DescribeTable("Reconciling an Ec2Instance",
+		func(run func(t *lifecycleTest)) {
+			ctx := context.Background()
+			inst := &computev1.Ec2Instance{
+				ObjectMeta: metav1.ObjectMeta{GenerateName: "lifecycle-", Namespace: "default"},
+				Spec: computev1.Ec2InstanceSpec{
+					Region:       "us-east-1",
+					AMIId:        "ami-0123456789abcdef0",
+					InstanceType: "t3.micro",
+					// Auto recovery checks the instance type with AWS directly.
+					AutoRecovery: false,
+				},
+			}
+			Expect(k8sClient.Create(ctx, inst)).To(Succeed())
+			DeferCleanup(func() {
+				// The finalizer would keep the object around once the test no longer reconciles it.
+				stored := &computev1.Ec2Instance{}
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(inst), stored); apierrors.IsNotFound(err) {
+					return
+				}
+				if controllerutil.RemoveFinalizer(stored, ec2InstanceFinalizer) {
+					Expect(k8sClient.Update(ctx, stored)).To(Succeed())
+				}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, stored))).To(Succeed())
+			})
+
+			fake := &awsclient.FakeEC2Client{Outputs: map[string]any{}}
+			recorder := record.NewFakeRecorder(100)
+			t := &lifecycleTest{
+				key:      client.ObjectKeyFromObject(inst),
+				fake:     fake,
+				recorder: recorder,
+				reconciler: &Ec2InstanceReconciler{
+					Client:    k8sClient,
+					Scheme:    k8sClient.Scheme(),
+					Recorder:  recorder,
+					EC2Client: fake,
+				},
+			}
+			t.awsReports("i-0000000000000001", ec2types.InstanceStateNameRunning)
+			run(t)
+		},
+
+		Entry("launches an instance for a new object", func(t *lifecycleTest) {
+			result := t.reconcileOnce()
+			Expect(result.RequeueAfter).To(Equal(time.Second))
+
+			inst := t.instance()
+			Expect(inst.Finalizers).To(ContainElement(ec2InstanceFinalizer))
+			Expect(inst.Status.InstanceID).To(Equal("i-0000000000000001"))
+			Expect(inst.Status.State).To(Equal("running"))
+			Expect(inst.Status.PrivateIP).To(Equal("10.0.0.10"))
+			Expect(inst.Status.LaunchedAMIID).To(Equal("ami-0123456789abcdef0"))
+
+			Expect(t.fake.CallsTo("RunInstances")).To(HaveLen(1))
+			runInput := t.fake.CallsTo("RunInstances")[0].(*ec2.RunInstancesInput)
+			Expect(aws.ToString(runInput.ImageId)).To(Equal("ami-0123456789abcdef0"))
+			Expect(runInput.InstanceType).To(Equal(ec2types.InstanceTypeT3Micro))
+			Expect(runInput.TagSpecifications).To(HaveLen(1))
+			Expect(runInput.TagSpecifications[0].Tags).To(ContainElement(ec2types.Tag{
+				Key: aws.String(managedByTagKey), Value: aws.String(managedByTagValue),
+			}))
+			// Once while waiting for the instance to run, once for its details.
+			Expect(t.fake.CallsTo("DescribeInstances")).To(HaveLen(2))
+			Expect(t.events()).To(ContainElement(ContainSubstring(eventInstanceCreating)))
+		}),
+
+		Entry("leaves the instance type, AMI and root volume to a launch template", func(t *lifecycleTest) {
+			inst := t.instance()
+			inst.Spec.InstanceType = ""
+			inst.Spec.AMIId = ""
+			inst.Spec.LaunchTemplateRef = &computev1.LaunchTemplateRef{ID: "lt-0123456789abcdef0"}
+			Expect(k8sClient.Update(context.Background(), inst)).To(Succeed())
+			t.reconciler.EnforceEBSEncryption = true
+			t.fake.Outputs["DescribeLaunchTemplateVersions"] = &ec2.DescribeLaunchTemplateVersionsOutput{LaunchTemplateVersions: []ec2types.LaunchTemplateVersion{{
+				LaunchTemplateData: &ec2types.ResponseLaunchTemplateData{InstanceType: ec2types.InstanceTypeM5Large, ImageId: aws.String("ami-0fedcba9876543210")},
+			}}}
+
+			t.reconcileOnce()
+			runInput := t.fake.CallsTo("RunInstances")[0].(*ec2.RunInstancesInput)
+			Expect(runInput.ImageId).To(BeNil())
+			Expect(runInput.InstanceType).To(BeEmpty())
+			Expect(runInput.BlockDeviceMappings).To(BeEmpty())
+			Expect(aws.ToString(runInput.LaunchTemplate.LaunchTemplateId)).To(Equal("lt-0123456789abcdef0"))
+
+			// The values of the template are known for the cost estimate.
+			inst = t.instance()
+			Expect(inst.Status.SelectedInstanceType).To(Equal("m5.large"))
+			Expect(inst.Status.SelectedAMIID).To(Equal("ami-0fedcba9876543210"))
+			cost, ok := EstimateMonthlyCostUSD(inst)
+			Expect(ok).To(BeTrue())
+			Expect(cost).To(BeNumerically(">", 0))
+		}),
+
+		Entry("syncs the status with AWS on the next reconcile", func(t *lifecycleTest) {
+			t.reconcileOnce()
+			result := t.reconcileOnce()
+			Expect(result.RequeueAfter).To(Equal(reconcileInterval))
+
+			inst := t.instance()
+			Expect(inst.Status.State).To(Equal("running"))
+			Expect(inst.Status.AvailabilityZone).To(Equal("us-east-1a"))
+			Expect(inst.Status.LaunchTime).NotTo(BeNil())
+			Expect(inst.Status.LaunchTime.Time).To(BeTemporally("==", time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)))
+			Expect(inst.Status.EBSOptimized).To(BeTrue())
+			Expect(inst.Status.LastSyncTime).NotTo(BeNil())
+			Expect(inst.Status.ObservedGeneration).To(Equal(inst.Generation))
+
+			Expect(t.fake.CallsTo("RunInstances")).To(HaveLen(1))
+			Expect(t.fake.CallsTo("DescribeInstances")).To(HaveLen(3))
+			Expect(t.fake.CallsTo("DescribeInstances")[2]).To(Equal(&ec2.DescribeInstancesInput{InstanceIds: []string{"i-0000000000000001"}}))
+
+			By("skipping AWS while the last sync is recent")
+			t.reconcileOnce()
+			Expect(t.fake.CallsTo("DescribeInstances")).To(HaveLen(3))
+		}),
+
+		Entry("replaces an instance that was terminated outside the operator", func(t *lifecycleTest) {
+			t.reconcileOnce()
+
+			t.awsReports("i-0000000000000001", ec2types.InstanceStateNameTerminated)
+			result := t.reconcileOnce()
+			Expect(result.Requeue).To(BeTrue())
+			inst := t.instance()
+			Expect(inst.Status.InstanceID).To(BeEmpty())
+			Expect(inst.Status.State).To(Equal("Terminated"))
+			Expect(t.events()).To(ContainElement(ContainSubstring(eventInstanceTerminated)))
+
+			t.awsReports("i-0000000000000002", ec2types.InstanceStateNameRunning)
+			t.reconcileOnce()
+			inst = t.instance()
+			Expect(inst.Status.InstanceID).To(Equal("i-0000000000000002"))
+			Expect(inst.Status.State).To(Equal("running"))
+			Expect(t.fake.CallsTo("RunInstances")).To(HaveLen(2))
+			Expect(t.fake.CallsTo("TerminateInstances")).To(BeEmpty())
+		}),
+
+		Entry("terminates the instance before removing the finalizer", func(t *lifecycleTest) {
+			t.reconcileOnce()
+			Expect(k8sClient.Delete(context.Background(), t.instance())).To(Succeed())
+
+			t.fake.Outputs["TerminateInstances"] = &ec2.TerminateInstancesOutput{TerminatingInstances: []ec2types.InstanceStateChange{{
+				CurrentState: &ec2types.InstanceState{Name: ec2types.InstanceStateNameShuttingDown},
+			}}}
+			t.awsReports("i-0000000000000001", ec2types.InstanceStateNameTerminated)
+			t.reconcileOnce()
+
+			err := k8sClient.Get(context.Background(), t.key, &computev1.Ec2Instance{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(t.fake.CallsTo("TerminateInstances")).To(ConsistOf(&ec2.TerminateInstancesInput{InstanceIds: []string{"i-0000000000000001"}}))
+			Expect(t.events()).To(ContainElement(ContainSubstring(eventDeletionStarted)))
+		}),
+
+		Entry("leaves a paused instance alone, deletion included", func(t *lifecycleTest) {
+			t.reconcileOnce()
+			t.setAnnotation(pausedAnnotation, "true")
+			Expect(k8sClient.Delete(context.Background(), t.instance())).To(Succeed())
+			callsBefore := len(t.fake.Calls())
+
+			t.reconcileOnce()
+			inst := t.instance()
+			Expect(inst.DeletionTimestamp).NotTo(BeNil())
+			Expect(inst.Finalizers).To(ContainElement(ec2InstanceFinalizer))
+			Expect(apimeta.IsStatusConditionTrue(inst.Status.Conditions, computev1.ConditionPaused)).To(BeTrue())
+			Expect(t.fake.Calls()).To(HaveLen(callsBefore))
+			Expect(t.fake.CallsTo("TerminateInstances")).To(BeEmpty())
+
+			By("terminating it once it is resumed")
+			t.fake.Outputs["TerminateInstances"] = &ec2.TerminateInstancesOutput{TerminatingInstances: []ec2types.InstanceStateChange{{
+				CurrentState: &ec2types.InstanceState{Name: ec2types.InstanceStateNameShuttingDown},
+			}}}
+			t.awsReports("i-0000000000000001", ec2types.InstanceStateNameTerminated)
+			t.setAnnotation(pausedAnnotation, "false")
+			t.reconcileOnce()
+			Expect(t.fake.CallsTo("TerminateInstances")).To(HaveLen(1))
+		}),
+	)
+})
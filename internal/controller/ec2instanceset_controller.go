@@ -0,0 +1,360 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// instanceSetPollInterval is how often a set is reconciled while it rolls out or watches new
+// instances for failed health checks.
+const instanceSetPollInterval = 30 * time.Second
+
+// Ec2InstanceSetReconciler keeps the Ec2Instances of an Ec2InstanceSet in line with its template.
+type Ec2InstanceSetReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instancesets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instancesets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile creates and deletes Ec2Instances until spec.replicas instances run the template, and
+// rolls back to the last stable template when new instances fail their health checks.
+func (r *Ec2InstanceSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	set := &computev1.Ec2InstanceSet{}
+	if err := r.Get(ctx, req.NamespacedName, set); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	original := set.Status.DeepCopy()
+
+	list := &computev1.Ec2InstanceList{}
+	if err := r.List(ctx, list, client.InNamespace(set.Namespace), client.MatchingLabels{computev1.Ec2InstanceSetLabel: set.Name}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list instances of set: %w", err)
+	}
+
+	specHash := templateHash(&set.Spec.Template)
+	if set.Status.FailedTemplateHash != "" && set.Status.FailedTemplateHash != specHash {
+		// The template changed after a rollback, so the new one gets a chance.
+		set.Status.FailedTemplateHash = ""
+		apimeta.RemoveStatusCondition(&set.Status.Conditions, computev1.ConditionRollbackTriggered)
+	}
+	template, targetHash := &set.Spec.Template, specHash
+	if set.Status.FailedTemplateHash == specHash && set.Status.StableTemplate != nil {
+		template, targetHash = set.Status.StableTemplate, set.Status.StableTemplateHash
+	}
+	set.Status.CurrentTemplateHash = targetHash
+
+	// Interrupted Spot instances are replaced here rather than by their own controller, so the
+	// replacement can go to a healthier pool.
+	interrupted := map[string]int32{}
+	var current, outdated []computev1.Ec2Instance
+	for _, inst := range list.Items {
+		if !inst.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if apimeta.IsStatusConditionTrue(inst.Status.Conditions, computev1.ConditionSpotInterrupted) {
+			if err := r.Delete(ctx, &inst); client.IgnoreNotFound(err) != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to delete interrupted instance %s: %w", inst.Name, err)
+			}
+			pool := inst.Labels[computev1.SpotPoolLabel]
+			interrupted[pool]++
+			l.Info("Replacing interrupted Spot instance", "name", inst.Name, "pool", pool)
+			r.Recorder.Eventf(set, corev1.EventTypeWarning, "SpotInterrupted", "Spot instance %s in pool %s was interrupted", inst.Name, pool)
+			continue
+		}
+		if inst.Labels[computev1.TemplateHashLabel] == targetHash {
+			current = append(current, inst)
+		} else {
+			outdated = append(outdated, inst)
+		}
+	}
+
+	// Only an update away from a stable template can be rolled back.
+	rollingOut := set.Status.StableTemplate != nil && targetHash != set.Status.StableTemplateHash
+	if set.Spec.AutoRollback.Enabled && rollingOut {
+		var failed *computev1.InstanceHealthFailures
+		set.Status.HealthCheckFailures, failed = trackHealthCheckFailures(set.Status.HealthCheckFailures, current, set.Spec.AutoRollback, time.Now())
+		if failed != nil {
+			return r.rollBack(ctx, set, current, failed, specHash)
+		}
+	} else {
+		set.Status.HealthCheckFailures = nil
+	}
+
+	create, remove := planInstanceSet(set.Spec.Replicas, current, outdated)
+	pools := set.Spec.SpotCapacityPools
+	set.Status.PoolBreakdown = nil
+	if len(pools) > 0 {
+		set.Status.PoolBreakdown = spotPoolBreakdown(pools, original.PoolBreakdown, append(current, outdated...), interrupted, time.Now())
+	}
+	indexes := freeSetIndexes(append(current, outdated...), create)
+	for i := 0; i < create; i++ {
+		inst, err := r.newInstance(set, template, targetHash, indexes[i])
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(pools) > 0 {
+			pool := chooseSpotPool(pools, set.Status.PoolBreakdown)
+			applySpotPool(inst, pool)
+			status := set.Status.PoolBreakdown[spotPoolKey(pool)]
+			status.Instances++
+			set.Status.PoolBreakdown[spotPoolKey(pool)] = status
+		}
+		if err := r.Create(ctx, inst); err != nil {
+			l.Error(err, "Failed to create Ec2Instance for set")
+			return ctrl.Result{}, err
+		}
+		l.Info("Created Ec2Instance for set", "name", inst.Name, "templateHash", targetHash)
+	}
+	for i := range remove {
+		if err := r.Delete(ctx, &remove[i]); client.IgnoreNotFound(err) != nil {
+			l.Error(err, "Failed to delete Ec2Instance of set", "name", remove[i].Name)
+			return ctrl.Result{}, err
+		}
+		l.Info("Deleted Ec2Instance of set", "name", remove[i].Name)
+		if status, ok := set.Status.PoolBreakdown[remove[i].Labels[computev1.SpotPoolLabel]]; ok {
+			status.Instances--
+			set.Status.PoolBreakdown[remove[i].Labels[computev1.SpotPoolLabel]] = status
+		}
+	}
+
+	set.Status.Replicas = int32(len(current) + len(outdated) + create - len(remove))
+	set.Status.UpdatedReplicas = int32(len(current) + create)
+	set.Status.ReadyReplicas = readyInstances(current)
+	set.Status.AvailableReplicas = readyInstances(current) + readyInstances(outdated) - readyInstances(remove)
+	settled := create == 0 && len(remove) == 0 && len(outdated) == 0 && set.Status.ReadyReplicas == set.Spec.Replicas
+	if settled && targetHash != set.Status.StableTemplateHash {
+		l.Info("Template rolled out", "templateHash", targetHash)
+		set.Status.StableTemplateHash = targetHash
+		set.Status.StableTemplate = template.DeepCopy()
+		set.Status.HealthCheckFailures = nil
+	}
+
+	if !equality.Semantic.DeepEqual(original, &set.Status) {
+		if err := r.Status().Update(ctx, set); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if settled {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: instanceSetPollInterval}, nil
+}
+
+// rollBack marks the template as failed, deletes the instances created from it and lets the next
+// reconcile bring the stable template back.
+func (r *Ec2InstanceSetReconciler) rollBack(ctx context.Context, set *computev1.Ec2InstanceSet, current []computev1.Ec2Instance, failed *computev1.InstanceHealthFailures, specHash string) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+	message := fmt.Sprintf("instance %s failed %d health checks; rolled back to template %s", failed.Name, failed.Failures, set.Status.StableTemplateHash)
+	l.Info("Rolling back Ec2InstanceSet", "reason", message)
+
+	for i := range current {
+		if err := r.Delete(ctx, &current[i]); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete instance %s: %w", current[i].Name, err)
+		}
+	}
+
+	set.Status.FailedTemplateHash = specHash
+	set.Status.CurrentTemplateHash = set.Status.StableTemplateHash
+	set.Status.HealthCheckFailures = nil
+	apimeta.SetStatusCondition(&set.Status.Conditions, metav1.Condition{
+		Type:               computev1.ConditionRollbackTriggered,
+		Status:             metav1.ConditionTrue,
+		Reason:             "HealthCheckFailed",
+		Message:            message,
+		ObservedGeneration: set.Generation,
+	})
+	if err := r.Status().Update(ctx, set); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Eventf(set, corev1.EventTypeWarning, "RollbackTriggered", message)
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// newInstance builds the Ec2Instance with ordinal index of the set from template.
+func (r *Ec2InstanceSetReconciler) newInstance(set *computev1.Ec2InstanceSet, template *computev1.Ec2InstanceTemplate, hash string, index int) (*computev1.Ec2Instance, error) {
+	labels := map[string]string{}
+	for k, v := range template.Labels {
+		labels[k] = v
+	}
+	labels[computev1.Ec2InstanceSetLabel] = set.Name
+	labels[computev1.TemplateHashLabel] = hash
+	labels[computev1.SetIndexLabel] = strconv.Itoa(index)
+
+	inst := &computev1.Ec2Instance{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-%s-", set.Name, hash),
+			Namespace:    set.Namespace,
+			Labels:       labels,
+			Annotations:  template.Annotations,
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+	if err := controllerutil.SetControllerReference(set, inst, r.Scheme); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// freeSetIndexes returns the n lowest ordinals not taken by instances.
+func freeSetIndexes(instances []computev1.Ec2Instance, n int) []int {
+	taken := map[int]bool{}
+	for _, inst := range instances {
+		if index, err := strconv.Atoi(inst.Labels[computev1.SetIndexLabel]); err == nil {
+			taken[index] = true
+		}
+	}
+	free := make([]int, 0, n)
+	for index := 0; len(free) < n; index++ {
+		if !taken[index] {
+			free = append(free, index)
+		}
+	}
+	return free
+}
+
+// templateHash returns a short hash of the template, used to tell instances of different templates apart.
+func templateHash(template *computev1.Ec2InstanceTemplate) string {
+	data, _ := json.Marshal(template)
+	h := fnv.New32a()
+	_, _ = h.Write(data)
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// instanceReady reports whether the instance is running and, if it has a health check, healthy.
+func instanceReady(inst *computev1.Ec2Instance) bool {
+	if inst.Status.State != "running" {
+		return false
+	}
+	return inst.Spec.Route53HealthCheck == nil || inst.Status.HealthCheckStatus == healthCheckHealthy
+}
+
+// readyInstances returns how many of instances are ready.
+func readyInstances(instances []computev1.Ec2Instance) int32 {
+	var ready int32
+	for i := range instances {
+		if instanceReady(&instances[i]) {
+			ready++
+		}
+	}
+	return ready
+}
+
+// planInstanceSet decides how many instances to create and which to delete. Outdated instances are
+// replaced one at a time: a new instance is only added while all other new instances are ready, and
+// an outdated one is only deleted once a ready new instance takes its place.
+func planInstanceSet(replicas int32, current, outdated []computev1.Ec2Instance) (int, []computev1.Ec2Instance) {
+	want := int(replicas)
+	ready := int(readyInstances(current))
+
+	if len(current) > want {
+		// Drop surplus new instances, those that are not ready first.
+		sorted := append([]computev1.Ec2Instance(nil), current...)
+		sort.SliceStable(sorted, func(i, j int) bool { return !instanceReady(&sorted[i]) && instanceReady(&sorted[j]) })
+		return 0, append(sorted[:len(current)-want], outdated...)
+	}
+
+	create := 0
+	switch {
+	case len(current)+len(outdated) < want:
+		// Scaling up: fill the gap right away.
+		create = want - len(current) - len(outdated)
+	case len(current) < want && ready == len(current):
+		// Rolling update: surge by one.
+		create = 1
+	}
+
+	// Outdated instances can go as far as ready new instances replace them.
+	excess := len(outdated) + ready - want
+	if excess > len(outdated) {
+		excess = len(outdated)
+	}
+	var remove []computev1.Ec2Instance
+	if excess > 0 {
+		sorted := append([]computev1.Ec2Instance(nil), outdated...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].CreationTimestamp.Before(&sorted[j].CreationTimestamp)
+		})
+		remove = sorted[:excess]
+	}
+	return create, remove
+}
+
+// trackHealthCheckFailures counts the failed health checks of the new instances once their grace
+// period is over. A healthy result resets the count. It returns the updated counts and the instance
+// that reached the threshold, if any.
+func trackHealthCheckFailures(tracked []computev1.InstanceHealthFailures, current []computev1.Ec2Instance, spec computev1.AutoRollbackSpec, now time.Time) ([]computev1.InstanceHealthFailures, *computev1.InstanceHealthFailures) {
+	threshold := spec.HealthCheckFailureThreshold
+	if threshold < 1 {
+		threshold = 3
+	}
+	grace := time.Duration(spec.HealthCheckGracePeriodSeconds) * time.Second
+
+	previous := map[string]computev1.InstanceHealthFailures{}
+	for _, t := range tracked {
+		previous[t.Name] = t
+	}
+
+	var updated []computev1.InstanceHealthFailures
+	var failed *computev1.InstanceHealthFailures
+	for i := range current {
+		inst := &current[i]
+		entry := previous[inst.Name]
+		entry.Name = inst.Name
+
+		checked := inst.Status.HealthCheckLastChecked
+		launched := inst.Status.LaunchTime
+		inGrace := launched == nil || now.Before(launched.Add(grace))
+		newResult := checked != nil && (entry.LastChecked == nil || checked.After(entry.LastChecked.Time))
+		if !inGrace && newResult {
+			entry.LastChecked = checked.DeepCopy()
+			switch inst.Status.HealthCheckStatus {
+			case healthCheckUnhealthy:
+				entry.Failures++
+			case healthCheckHealthy:
+				entry.Failures = 0
+			}
+		}
+		updated = append(updated, entry)
+		if entry.Failures >= threshold && failed == nil {
+			failed = &entry
+		}
+	}
+	return updated, failed
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Ec2InstanceSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.Ec2InstanceSet{}).
+		Owns(&computev1.Ec2Instance{}).
+		Named("ec2instanceset").
+		Complete(r)
+}
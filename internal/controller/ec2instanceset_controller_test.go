@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Ec2InstanceSet Controller", func() {
+	instance := func(name, state string) computev1.Ec2Instance {
+		return computev1.Ec2Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     computev1.Ec2InstanceStatus{State: state},
+		}
+	}
+	names := func(instances []computev1.Ec2Instance) []string {
+		var out []string
+		for _, inst := range instances {
+			out = append(out, inst.Name)
+		}
+		return out
+	}
+
+	Context("When hashing templates", func() {
+		It("should only change the hash when the template changes", func() {
+			a := &computev1.Ec2InstanceTemplate{Spec: computev1.Ec2InstanceSpec{InstanceType: "t3.micro"}}
+			b := a.DeepCopy()
+			Expect(templateHash(a)).To(Equal(templateHash(b)))
+			b.Spec.InstanceType = "t3.small"
+			Expect(templateHash(a)).NotTo(Equal(templateHash(b)))
+		})
+	})
+
+	Context("When numbering instances", func() {
+		It("should hand out the lowest free ordinals", func() {
+			indexed := func(index string) computev1.Ec2Instance {
+				return computev1.Ec2Instance{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{computev1.SetIndexLabel: index}}}
+			}
+			Expect(freeSetIndexes(nil, 2)).To(Equal([]int{0, 1}))
+			Expect(freeSetIndexes([]computev1.Ec2Instance{indexed("0"), indexed("2"), {}}, 3)).To(Equal([]int{1, 3, 4}))
+		})
+	})
+
+	Context("When planning the instances", func() {
+		It("should create all missing instances when scaling up", func() {
+			create, remove := planInstanceSet(3, nil, nil)
+			Expect(create).To(Equal(3))
+			Expect(remove).To(BeEmpty())
+		})
+
+		It("should surge by one while the new instances are ready", func() {
+			outdated := []computev1.Ec2Instance{instance("old-1", "running"), instance("old-2", "running")}
+			create, remove := planInstanceSet(2, nil, outdated)
+			Expect(create).To(Equal(1))
+			Expect(remove).To(BeEmpty())
+		})
+
+		It("should wait for a new instance before deleting an old one", func() {
+			outdated := []computev1.Ec2Instance{instance("old-1", "running"), instance("old-2", "running")}
+			create, remove := planInstanceSet(2, []computev1.Ec2Instance{instance("new-1", "pending")}, outdated)
+			Expect(create).To(BeZero())
+			Expect(remove).To(BeEmpty())
+
+			create, remove = planInstanceSet(2, []computev1.Ec2Instance{instance("new-1", "running")}, outdated)
+			Expect(create).To(Equal(1))
+			Expect(names(remove)).To(HaveLen(1))
+		})
+
+		It("should remove surplus instances that are not ready first", func() {
+			current := []computev1.Ec2Instance{instance("a", "running"), instance("b", "pending"), instance("c", "running")}
+			create, remove := planInstanceSet(2, current, nil)
+			Expect(create).To(BeZero())
+			Expect(names(remove)).To(Equal([]string{"b"}))
+		})
+	})
+
+	Context("When counting replicas", func() {
+		It("should count the ready instances", func() {
+			Expect(readyInstances(nil)).To(BeZero())
+			Expect(readyInstances([]computev1.Ec2Instance{
+				instance("a", "running"), instance("b", "pending"), instance("c", "stopped"), instance("d", "running"),
+			})).To(Equal(int32(2)))
+		})
+	})
+
+	Context("When tracking health checks", func() {
+		launched := metav1.NewTime(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+		spec := computev1.AutoRollbackSpec{Enabled: true, HealthCheckFailureThreshold: 2, HealthCheckGracePeriodSeconds: 300}
+		unhealthy := func(checked time.Time) computev1.Ec2Instance {
+			inst := instance("new-1", "running")
+			inst.Status.LaunchTime = &launched
+			inst.Status.HealthCheckStatus = healthCheckUnhealthy
+			t := metav1.NewTime(checked)
+			inst.Status.HealthCheckLastChecked = &t
+			return inst
+		}
+
+		It("should ignore failures within the grace period", func() {
+			checked := launched.Add(time.Minute)
+			tracked, failed := trackHealthCheckFailures(nil, []computev1.Ec2Instance{unhealthy(checked)}, spec, checked)
+			Expect(failed).To(BeNil())
+			Expect(tracked[0].Failures).To(BeZero())
+		})
+
+		It("should count every result once and report the instance that reaches the threshold", func() {
+			first := launched.Add(10 * time.Minute)
+			tracked, failed := trackHealthCheckFailures(nil, []computev1.Ec2Instance{unhealthy(first)}, spec, first)
+			Expect(failed).To(BeNil())
+
+			tracked, failed = trackHealthCheckFailures(tracked, []computev1.Ec2Instance{unhealthy(first)}, spec, first.Add(time.Minute))
+			Expect(failed).To(BeNil())
+			Expect(tracked[0].Failures).To(Equal(int32(1)))
+
+			second := first.Add(time.Minute)
+			_, failed = trackHealthCheckFailures(tracked, []computev1.Ec2Instance{unhealthy(second)}, spec, second)
+			Expect(failed).NotTo(BeNil())
+			Expect(failed.Name).To(Equal("new-1"))
+		})
+
+		It("should reset the count on a healthy result", func() {
+			first := launched.Add(10 * time.Minute)
+			tracked, _ := trackHealthCheckFailures(nil, []computev1.Ec2Instance{unhealthy(first)}, spec, first)
+
+			healthy := unhealthy(first.Add(time.Minute))
+			healthy.Status.HealthCheckStatus = healthCheckHealthy
+			tracked, _ = trackHealthCheckFailures(tracked, []computev1.Ec2Instance{healthy}, spec, first.Add(time.Minute))
+			Expect(tracked[0].Failures).To(BeZero())
+		})
+	})
+})
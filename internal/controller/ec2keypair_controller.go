@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// ec2KeyPairFinalizer makes sure the key pair is deleted in AWS before the object is removed.
+	ec2KeyPairFinalizer = "ec2keypair.compute.cloud.com"
+	// ec2KeyPairUIDTag tells key pairs created for the object apart from key pairs that merely have
+	// the same name.
+	ec2KeyPairUIDTag = "ec2keypair.compute.cloud.com/uid"
+	// ec2KeyPairRetryInterval is how often a key pair that cannot be managed is looked at again.
+	ec2KeyPairRetryInterval = 5 * time.Minute
+)
+
+// keyPairAction is what reconciling an Ec2KeyPair has to do about the key pair found in AWS.
+type keyPairAction int
+
+const (
+	// keyPairInSync means the key pair exists and its private key is in the Secret.
+	keyPairInSync keyPairAction = iota
+	// keyPairCreate means there is no key pair yet.
+	keyPairCreate
+	// keyPairRecreate means the key pair was created, but its private key never reached the Secret.
+	keyPairRecreate
+	// keyPairKeyLost means the private key of a key pair in use is no longer in the Secret.
+	keyPairKeyLost
+	// keyPairForeign means a key pair with the name exists that this object did not create.
+	keyPairForeign
+)
+
+// decideKeyPairAction compares the key pair found in AWS, if any, with the object and its Secret.
+// A key pair whose private key went missing before it was ever recorded in status is replaced, as
+// nothing can have used it yet. Once recorded, replacing it would lock out its instances.
+func decideKeyPairAction(keyPair *computev1.Ec2KeyPair, existing *ec2types.KeyPairInfo, havePrivateKey bool) keyPairAction {
+	if existing == nil {
+		return keyPairCreate
+	}
+	owned := false
+	for _, tag := range existing.Tags {
+		if aws.ToString(tag.Key) == ec2KeyPairUIDTag && aws.ToString(tag.Value) == string(keyPair.UID) {
+			owned = true
+		}
+	}
+	switch {
+	case !owned:
+		return keyPairForeign
+	case havePrivateKey:
+		return keyPairInSync
+	case keyPair.Status.KeyPairID == "":
+		return keyPairRecreate
+	}
+	return keyPairKeyLost
+}
+
+// Ec2KeyPairReconciler creates an SSH key pair in AWS and keeps its private key in a Secret.
+type Ec2KeyPairReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2keypairs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2keypairs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2keypairs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
+
+// Reconcile creates the key pair, stores its private key and deletes it.
+func (r *Ec2KeyPairReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	keyPair := &computev1.Ec2KeyPair{}
+	if err := r.Get(ctx, req.NamespacedName, keyPair); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	ec2Client := awsClient(keyPair.Spec.Region)
+
+	if !keyPair.DeletionTimestamp.IsZero() {
+		if keyPair.Status.KeyPairID != "" {
+			// DeleteKeyPair succeeds for key pairs that are already gone.
+			if _, err := ec2Client.DeleteKeyPair(ctx, &ec2.DeleteKeyPairInput{KeyPairId: aws.String(keyPair.Status.KeyPairID)}); err != nil {
+				l.Error(err, "Failed to delete key pair", "keyPairID", keyPair.Status.KeyPairID)
+				return ctrl.Result{}, fmt.Errorf("failed to delete key pair: %w", err)
+			}
+			l.Info("Deleted key pair", "keyName", keyPair.Spec.KeyName, "keyPairID", keyPair.Status.KeyPairID)
+		}
+
+		controllerutil.RemoveFinalizer(keyPair, ec2KeyPairFinalizer)
+		if err := r.Update(ctx, keyPair); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if controllerutil.AddFinalizer(keyPair, ec2KeyPairFinalizer) {
+		if err := r.Update(ctx, keyPair); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	existing, err := findKeyPair(ctx, ec2Client, keyPair.Spec.KeyName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	havePrivateKey, err := r.havePrivateKey(ctx, keyPair)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	message := ""
+	switch decideKeyPairAction(keyPair, existing, havePrivateKey) {
+	case keyPairForeign:
+		message = fmt.Sprintf("key pair %s already exists in %s and was not created by this Ec2KeyPair", keyPair.Spec.KeyName, keyPair.Spec.Region)
+	case keyPairKeyLost:
+		message = fmt.Sprintf("the private key is no longer in Secret %s and cannot be retrieved from AWS; delete and recreate the Ec2KeyPair for a new key",
+			keyPair.Spec.PrivateKeySecretRef.Name)
+	case keyPairRecreate:
+		l.Info("Private key of key pair was never stored, creating it again", "keyName", keyPair.Spec.KeyName)
+		if _, err := ec2Client.DeleteKeyPair(ctx, &ec2.DeleteKeyPairInput{KeyPairId: existing.KeyPairId}); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete key pair: %w", err)
+		}
+		fallthrough
+	case keyPairCreate:
+		if existing, err = r.createKeyPair(ctx, ec2Client, keyPair); err != nil {
+			l.Error(err, "Failed to create key pair", "keyName", keyPair.Spec.KeyName)
+			return ctrl.Result{}, err
+		}
+	}
+
+	status := computev1.Ec2KeyPairStatus{Message: message}
+	if message == "" {
+		status.KeyPairID = aws.ToString(existing.KeyPairId)
+		status.KeyFingerprint = aws.ToString(existing.KeyFingerprint)
+	} else {
+		status.KeyPairID = keyPair.Status.KeyPairID
+		status.KeyFingerprint = keyPair.Status.KeyFingerprint
+	}
+	if keyPair.Status != status {
+		keyPair.Status = status
+		if err := r.Status().Update(ctx, keyPair); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	// Neither problem goes away without someone acting, so check back now and then rather than retrying.
+	if message != "" {
+		l.Info("Cannot manage key pair", "keyName", keyPair.Spec.KeyName, "reason", message)
+		return ctrl.Result{RequeueAfter: ec2KeyPairRetryInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// findKeyPair returns the key pair named keyName, or nil. A filter is used rather than KeyNames,
+// which fails when the key pair does not exist.
+func findKeyPair(ctx context.Context, ec2Client *ec2.Client, keyName string) (*ec2types.KeyPairInfo, error) {
+	result, err := ec2Client.DescribeKeyPairs(ctx, &ec2.DescribeKeyPairsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("key-name"), Values: []string{keyName}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe key pair %s: %w", keyName, err)
+	}
+	if len(result.KeyPairs) == 0 {
+		return nil, nil
+	}
+	return &result.KeyPairs[0], nil
+}
+
+// havePrivateKey reports whether the Secret of privateKeySecretRef holds a private key.
+func (r *Ec2KeyPairReconciler) havePrivateKey(ctx context.Context, keyPair *computev1.Ec2KeyPair) (bool, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: keyPair.Namespace, Name: keyPair.Spec.PrivateKeySecretRef.Name}, secret)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get Secret %s: %w", keyPair.Spec.PrivateKeySecretRef.Name, err)
+	}
+	return len(secret.Data[computev1.KeyPairPrivateKeySecretKey]) > 0, nil
+}
+
+// createKeyPair creates the key pair and writes its private key to the Secret right away, since AWS
+// never returns it again. A Secret created here is owned by the Ec2KeyPair, so it goes away with it.
+func (r *Ec2KeyPairReconciler) createKeyPair(ctx context.Context, ec2Client *ec2.Client, keyPair *computev1.Ec2KeyPair) (*ec2types.KeyPairInfo, error) {
+	result, err := ec2Client.CreateKeyPair(ctx, &ec2.CreateKeyPairInput{
+		KeyName: aws.String(keyPair.Spec.KeyName),
+		TagSpecifications: []ec2types.TagSpecification{{
+			ResourceType: ec2types.ResourceTypeKeyPair,
+			Tags:         []ec2types.Tag{{Key: aws.String(ec2KeyPairUIDTag), Value: aws.String(string(keyPair.UID))}},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key pair %s: %w", keyPair.Spec.KeyName, err)
+	}
+	log.FromContext(ctx).Info("Created key pair", "keyName", keyPair.Spec.KeyName, "keyPairID", aws.ToString(result.KeyPairId))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      keyPair.Spec.PrivateKeySecretRef.Name,
+			Namespace: keyPair.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[computev1.KeyPairPrivateKeySecretKey] = []byte(strings.TrimSpace(aws.ToString(result.KeyMaterial)) + "\n")
+		if !secret.CreationTimestamp.IsZero() {
+			return nil
+		}
+		secret.Type = corev1.SecretTypeSSHAuth
+		return controllerutil.SetControllerReference(keyPair, secret, r.Scheme)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to store private key of key pair %s: %w", keyPair.Spec.KeyName, err)
+	}
+	return &ec2types.KeyPairInfo{KeyPairId: result.KeyPairId, KeyFingerprint: result.KeyFingerprint}, nil
+}
+
+// keyPairName returns the key pair to launch ec2Instance with: spec.keyPair, or the key name of
+// spec.keyPairRef once that Ec2KeyPair has created it. It reports whether the name is known yet.
+func (r *Ec2InstanceReconciler) keyPairName(ctx context.Context, ec2Instance *computev1.Ec2Instance) (string, bool, error) {
+	ref := ec2Instance.Spec.KeyPairRef
+	if ref == nil {
+		return ec2Instance.Spec.KeyPair, true, nil
+	}
+	// The key pair is created with the operator's credentials, so another account cannot use it.
+	if ec2Instance.Spec.RoleARN != "" {
+		return "", false, fmt.Errorf("Ec2KeyPair %s is in the operator's account and cannot be used with spec.roleARN", ref.Name)
+	}
+	keyPair := &computev1.Ec2KeyPair{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: ec2Instance.Namespace, Name: ref.Name}, keyPair); err != nil {
+		if errors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to get Ec2KeyPair %s: %w", ref.Name, err)
+	}
+	if keyPair.Status.KeyPairID == "" {
+		return "", false, nil
+	}
+	if keyPair.Spec.Region != ec2Instance.Spec.Region {
+		return "", false, fmt.Errorf("Ec2KeyPair %s is in region %s, not %s", ref.Name, keyPair.Spec.Region, ec2Instance.Spec.Region)
+	}
+	return keyPair.Spec.KeyName, true, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Ec2KeyPairReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.Ec2KeyPair{}).
+		Owns(&corev1.Secret{}).
+		Named("ec2keypair").
+		Complete(r)
+}
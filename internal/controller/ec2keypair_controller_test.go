@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Ec2KeyPair Controller", func() {
+	Context("When deciding what to do about the key pair in AWS", func() {
+		var keyPair *computev1.Ec2KeyPair
+		owned := &ec2types.KeyPairInfo{
+			KeyPairId: aws.String("key-1"),
+			Tags:      []ec2types.Tag{{Key: aws.String(ec2KeyPairUIDTag), Value: aws.String("uid-1")}},
+		}
+
+		BeforeEach(func() {
+			keyPair = &computev1.Ec2KeyPair{ObjectMeta: metav1.ObjectMeta{UID: "uid-1"}}
+		})
+
+		It("Should create a key pair that does not exist", func() {
+			Expect(decideKeyPairAction(keyPair, nil, false)).To(Equal(keyPairCreate))
+		})
+
+		It("Should leave a key pair with the same name created by someone else alone", func() {
+			Expect(decideKeyPairAction(keyPair, &ec2types.KeyPairInfo{KeyPairId: aws.String("key-2")}, true)).To(Equal(keyPairForeign))
+		})
+
+		It("Should replace its key pair when the private key was never stored", func() {
+			Expect(decideKeyPairAction(keyPair, owned, true)).To(Equal(keyPairInSync))
+			Expect(decideKeyPairAction(keyPair, owned, false)).To(Equal(keyPairRecreate))
+		})
+
+		It("Should not replace a key pair in use when its private key is lost", func() {
+			keyPair.Status.KeyPairID = "key-1"
+			Expect(decideKeyPairAction(keyPair, owned, false)).To(Equal(keyPairKeyLost))
+		})
+	})
+
+	Context("When an Ec2Instance refers to the Ec2KeyPair", func() {
+		It("Should reject an instance in another account", func() {
+			inst := &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{
+				Region:     "us-east-1",
+				RoleARN:    "arn:aws:iam::111111111111:role/ec2operator",
+				KeyPairRef: &corev1.LocalObjectReference{Name: "web"},
+			}}
+			_, _, err := (&Ec2InstanceReconciler{}).keyPairName(context.Background(), inst)
+			Expect(err).To(MatchError(ContainSubstring("cannot be used with spec.roleARN")))
+		})
+	})
+})
@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+const (
+	// ec2LaunchTemplateFinalizer makes sure the launch template is deleted in AWS before the object
+	// is removed.
+	ec2LaunchTemplateFinalizer = "ec2launchtemplate.compute.cloud.com"
+	// ec2LaunchTemplateUIDTag tells launch templates created for the object apart from launch
+	// templates that merely have the same name.
+	ec2LaunchTemplateUIDTag = "ec2launchtemplate.compute.cloud.com/uid"
+	// ec2LaunchTemplateRetryInterval is how often a launch template that cannot be managed is looked
+	// at again.
+	ec2LaunchTemplateRetryInterval = 5 * time.Minute
+)
+
+// Ec2LaunchTemplateReconciler creates a launch template in AWS, adds a version for every change of
+// the spec and deletes it with the object.
+type Ec2LaunchTemplateReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// EC2Client, when set, is used for every launch template instead of the client of its region.
+	// Tests set it to an awsclient.FakeEC2Client.
+	EC2Client awsclient.LaunchTemplateClient
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2launchtemplates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2launchtemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2launchtemplates/finalizers,verbs=update
+
+// Reconcile creates the launch template, versions it and deletes it.
+func (r *Ec2LaunchTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	template := &computev1.Ec2LaunchTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, template); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	var ec2Client awsclient.LaunchTemplateClient = awsClient(template.Spec.Region)
+	if r.EC2Client != nil {
+		ec2Client = r.EC2Client
+	}
+
+	if !template.DeletionTimestamp.IsZero() {
+		if template.Status.TemplateID != "" {
+			_, err := ec2Client.DeleteLaunchTemplate(ctx, &ec2.DeleteLaunchTemplateInput{LaunchTemplateId: aws.String(template.Status.TemplateID)})
+			if err != nil && !strings.Contains(err.Error(), "InvalidLaunchTemplateId.NotFound") {
+				l.Error(err, "Failed to delete launch template", "templateID", template.Status.TemplateID)
+				return ctrl.Result{}, fmt.Errorf("failed to delete launch template: %w", err)
+			}
+			l.Info("Deleted launch template", "templateName", template.Spec.TemplateName, "templateID", template.Status.TemplateID)
+		}
+
+		controllerutil.RemoveFinalizer(template, ec2LaunchTemplateFinalizer)
+		if err := r.Update(ctx, template); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if controllerutil.AddFinalizer(template, ec2LaunchTemplateFinalizer) {
+		if err := r.Update(ctx, template); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	status := template.Status
+	status.Message = ""
+	if status.TemplateID == "" {
+		existing, err := findLaunchTemplate(ctx, ec2Client, template.Spec.TemplateName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		switch {
+		case existing == nil:
+			result, err := ec2Client.CreateLaunchTemplate(ctx, &ec2.CreateLaunchTemplateInput{
+				LaunchTemplateName: aws.String(template.Spec.TemplateName),
+				LaunchTemplateData: requestLaunchTemplateData(template.Spec.LaunchTemplateData),
+				TagSpecifications: []ec2types.TagSpecification{{
+					ResourceType: ec2types.ResourceTypeLaunchTemplate,
+					Tags:         []ec2types.Tag{{Key: aws.String(ec2LaunchTemplateUIDTag), Value: aws.String(string(template.UID))}},
+				}},
+			})
+			if err != nil {
+				l.Error(err, "Failed to create launch template", "templateName", template.Spec.TemplateName)
+				return ctrl.Result{}, fmt.Errorf("failed to create launch template %s: %w", template.Spec.TemplateName, err)
+			}
+			status.TemplateID = aws.ToString(result.LaunchTemplate.LaunchTemplateId)
+			status.DefaultVersion = int32(aws.ToInt64(result.LaunchTemplate.DefaultVersionNumber))
+			status.ObservedGeneration = template.Generation
+			l.Info("Created launch template", "templateName", template.Spec.TemplateName, "templateID", status.TemplateID)
+		case launchTemplateOwned(template, existing):
+			// An earlier attempt created it but did not get to record it. The spec may have changed
+			// since, so a version is added for the current one.
+			status.TemplateID = aws.ToString(existing.LaunchTemplateId)
+			status.DefaultVersion = int32(aws.ToInt64(existing.DefaultVersionNumber))
+			status.ObservedGeneration = 0
+		default:
+			status.Message = fmt.Sprintf("launch template %s already exists in %s and was not created by this Ec2LaunchTemplate",
+				template.Spec.TemplateName, template.Spec.Region)
+		}
+	}
+	if status.Message == "" && status.ObservedGeneration != template.Generation {
+		version, err := r.createDefaultVersion(ctx, ec2Client, template, status.TemplateID)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		status.DefaultVersion = version
+		status.ObservedGeneration = template.Generation
+	}
+
+	if template.Status != status {
+		template.Status = status
+		if err := r.Status().Update(ctx, template); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if status.Message != "" {
+		l.Info("Cannot manage launch template", "templateName", template.Spec.TemplateName, "reason", status.Message)
+		return ctrl.Result{RequeueAfter: ec2LaunchTemplateRetryInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// createDefaultVersion adds a version with the launch template data of the spec and makes it the
+// default one, so instances launched without a version use it.
+func (r *Ec2LaunchTemplateReconciler) createDefaultVersion(ctx context.Context, ec2Client awsclient.LaunchTemplateClient, template *computev1.Ec2LaunchTemplate, templateID string) (int32, error) {
+	result, err := ec2Client.CreateLaunchTemplateVersion(ctx, &ec2.CreateLaunchTemplateVersionInput{
+		LaunchTemplateId:   aws.String(templateID),
+		LaunchTemplateData: requestLaunchTemplateData(template.Spec.LaunchTemplateData),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create version of launch template %s: %w", templateID, err)
+	}
+	version := aws.ToInt64(result.LaunchTemplateVersion.VersionNumber)
+	if _, err := ec2Client.ModifyLaunchTemplate(ctx, &ec2.ModifyLaunchTemplateInput{
+		LaunchTemplateId: aws.String(templateID),
+		DefaultVersion:   aws.String(strconv.FormatInt(version, 10)),
+	}); err != nil {
+		return 0, fmt.Errorf("failed to make version %d the default of launch template %s: %w", version, templateID, err)
+	}
+	log.FromContext(ctx).Info("Created default version of launch template", "templateID", templateID, "version", version)
+	return int32(version), nil
+}
+
+// requestLaunchTemplateData returns the launch template data of spec for AWS.
+func requestLaunchTemplateData(spec computev1.LaunchTemplateDataSpec) *ec2types.RequestLaunchTemplateData {
+	data := &ec2types.RequestLaunchTemplateData{
+		InstanceType:     ec2types.InstanceType(spec.InstanceType),
+		SecurityGroupIds: spec.SecurityGroupIDs,
+	}
+	if spec.AMIId != "" {
+		data.ImageId = aws.String(spec.AMIId)
+	}
+	if spec.KeyName != "" {
+		data.KeyName = aws.String(spec.KeyName)
+	}
+	if spec.IAMInstanceProfile != "" {
+		profile := iamInstanceProfileSpecification(spec.IAMInstanceProfile)
+		data.IamInstanceProfile = &ec2types.LaunchTemplateIamInstanceProfileSpecificationRequest{Arn: profile.Arn, Name: profile.Name}
+	}
+	if spec.UserData != "" {
+		data.UserData = aws.String(base64.StdEncoding.EncodeToString([]byte(spec.UserData)))
+	}
+	if spec.EBSOptimized {
+		data.EbsOptimized = aws.Bool(true)
+	}
+	if options := spec.MetadataOptions; options != nil {
+		data.MetadataOptions = &ec2types.LaunchTemplateInstanceMetadataOptionsRequest{
+			HttpTokens:           ec2types.LaunchTemplateHttpTokensState(options.HTTPTokens),
+			InstanceMetadataTags: ec2types.LaunchTemplateInstanceMetadataTagsState(options.InstanceMetadataTags),
+		}
+		if options.HTTPPutResponseHopLimit > 0 {
+			data.MetadataOptions.HttpPutResponseHopLimit = aws.Int32(options.HTTPPutResponseHopLimit)
+		}
+	}
+	if len(spec.Tags) > 0 {
+		tags := make([]ec2types.Tag, 0, len(spec.Tags))
+		for _, key := range slices.Sorted(maps.Keys(spec.Tags)) {
+			tags = append(tags, ec2types.Tag{Key: aws.String(key), Value: aws.String(spec.Tags[key])})
+		}
+		data.TagSpecifications = []ec2types.LaunchTemplateTagSpecificationRequest{{ResourceType: ec2types.ResourceTypeInstance, Tags: tags}}
+	}
+	return data
+}
+
+// launchTemplateOwned reports whether the launch template found in AWS was created for the object.
+func launchTemplateOwned(template *computev1.Ec2LaunchTemplate, existing *ec2types.LaunchTemplate) bool {
+	for _, tag := range existing.Tags {
+		if aws.ToString(tag.Key) == ec2LaunchTemplateUIDTag && aws.ToString(tag.Value) == string(template.UID) {
+			return true
+		}
+	}
+	return false
+}
+
+// findLaunchTemplate returns the launch template named templateName, or nil. A filter is used
+// rather than LaunchTemplateNames, which fails when the launch template does not exist.
+func findLaunchTemplate(ctx context.Context, ec2Client awsclient.LaunchTemplateClient, templateName string) (*ec2types.LaunchTemplate, error) {
+	result, err := ec2Client.DescribeLaunchTemplates(ctx, &ec2.DescribeLaunchTemplatesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("launch-template-name"), Values: []string{templateName}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe launch template %s: %w", templateName, err)
+	}
+	if len(result.LaunchTemplates) == 0 {
+		return nil, nil
+	}
+	return &result.LaunchTemplates[0], nil
+}
+
+// launchTemplate returns spec.launchTemplateRef with a referenced Ec2LaunchTemplate resolved to the
+// ID of its launch template, or nil without a launch template. It reports whether the launch
+// template is known yet.
+func (r *Ec2InstanceReconciler) launchTemplate(ctx context.Context, ec2Instance *computev1.Ec2Instance) (*computev1.LaunchTemplateRef, bool, error) {
+	ref := ec2Instance.Spec.LaunchTemplateRef
+	if ref == nil || ref.Ec2LaunchTemplate == "" {
+		return ref, true, nil
+	}
+	// The launch template is created with the operator's credentials, so another account cannot
+	// launch from it.
+	if ec2Instance.Spec.RoleARN != "" {
+		return nil, false, fmt.Errorf("Ec2LaunchTemplate %s is in the operator's account and cannot be used with spec.roleARN", ref.Ec2LaunchTemplate)
+	}
+	template := &computev1.Ec2LaunchTemplate{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: ec2Instance.Namespace, Name: ref.Ec2LaunchTemplate}, template); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get Ec2LaunchTemplate %s: %w", ref.Ec2LaunchTemplate, err)
+	}
+	if template.Status.TemplateID == "" {
+		return nil, false, nil
+	}
+	if template.Spec.Region != ec2Instance.Spec.Region {
+		return nil, false, fmt.Errorf("Ec2LaunchTemplate %s is in region %s, not %s", ref.Ec2LaunchTemplate, template.Spec.Region, ec2Instance.Spec.Region)
+	}
+	return &computev1.LaunchTemplateRef{ID: template.Status.TemplateID, Version: ref.Version}, true, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Ec2LaunchTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.Ec2LaunchTemplate{}).
+		Named("ec2launchtemplate").
+		Complete(r)
+}
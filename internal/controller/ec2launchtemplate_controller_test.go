@@ -0,0 +1,258 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+var _ = Describe("Ec2LaunchTemplate Controller", func() {
+	var template *computev1.Ec2LaunchTemplate
+
+	BeforeEach(func() {
+		template = &computev1.Ec2LaunchTemplate{
+			ObjectMeta: metav1.ObjectMeta{UID: "uid-1"},
+			Spec: computev1.Ec2LaunchTemplateSpec{
+				Region:       "us-east-1",
+				TemplateName: "web",
+				LaunchTemplateData: computev1.LaunchTemplateDataSpec{
+					InstanceType: "t3.micro",
+					AMIId:        "ami-123",
+				},
+			},
+		}
+	})
+
+	Context("When building the launch template data", func() {
+		It("Should only set the fields the spec sets", func() {
+			data := requestLaunchTemplateData(template.Spec.LaunchTemplateData)
+			Expect(data.InstanceType).To(Equal(ec2types.InstanceTypeT3Micro))
+			Expect(aws.ToString(data.ImageId)).To(Equal("ami-123"))
+			Expect(data.KeyName).To(BeNil())
+			Expect(data.IamInstanceProfile).To(BeNil())
+			Expect(data.UserData).To(BeNil())
+			Expect(data.EbsOptimized).To(BeNil())
+			Expect(data.MetadataOptions).To(BeNil())
+			Expect(data.TagSpecifications).To(BeEmpty())
+		})
+
+		It("Should encode the user data and tag the instances", func() {
+			template.Spec.LaunchTemplateData.UserData = "#!/bin/sh\necho hello"
+			template.Spec.LaunchTemplateData.IAMInstanceProfile = "arn:aws:iam::123456789012:instance-profile/web"
+			template.Spec.LaunchTemplateData.Tags = map[string]string{"team": "web", "env": "prod"}
+			template.Spec.LaunchTemplateData.MetadataOptions = &computev1.MetadataOptionsSpec{HTTPTokens: "required", HTTPPutResponseHopLimit: 2}
+			data := requestLaunchTemplateData(template.Spec.LaunchTemplateData)
+
+			Expect(aws.ToString(data.UserData)).To(Equal(base64.StdEncoding.EncodeToString([]byte("#!/bin/sh\necho hello"))))
+			Expect(aws.ToString(data.IamInstanceProfile.Arn)).To(Equal("arn:aws:iam::123456789012:instance-profile/web"))
+			Expect(data.IamInstanceProfile.Name).To(BeNil())
+			Expect(data.MetadataOptions.HttpTokens).To(Equal(ec2types.LaunchTemplateHttpTokensStateRequired))
+			Expect(aws.ToInt32(data.MetadataOptions.HttpPutResponseHopLimit)).To(Equal(int32(2)))
+			Expect(data.TagSpecifications).To(HaveLen(1))
+			Expect(data.TagSpecifications[0].ResourceType).To(Equal(ec2types.ResourceTypeInstance))
+			Expect(data.TagSpecifications[0].Tags).To(Equal([]ec2types.Tag{
+				{Key: aws.String("env"), Value: aws.String("prod")},
+				{Key: aws.String("team"), Value: aws.String("web")},
+			}))
+		})
+	})
+
+	Context("When looking at the launch template in AWS", func() {
+		It("Should leave a launch template with the same name created by someone else alone", func() {
+			owned := &ec2types.LaunchTemplate{Tags: []ec2types.Tag{{Key: aws.String(ec2LaunchTemplateUIDTag), Value: aws.String("uid-1")}}}
+			Expect(launchTemplateOwned(template, owned)).To(BeTrue())
+			Expect(launchTemplateOwned(template, &ec2types.LaunchTemplate{LaunchTemplateName: aws.String("web")})).To(BeFalse())
+		})
+	})
+
+	Context("When an Ec2Instance refers to the Ec2LaunchTemplate", func() {
+		It("Should reject an instance in another account", func() {
+			inst := &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{
+				Region:            "us-east-1",
+				RoleARN:           "arn:aws:iam::111111111111:role/ec2operator",
+				LaunchTemplateRef: &computev1.LaunchTemplateRef{Ec2LaunchTemplate: "web"},
+			}}
+			_, _, err := (&Ec2InstanceReconciler{}).launchTemplate(context.Background(), inst)
+			Expect(err).To(MatchError(ContainSubstring("cannot be used with spec.roleARN")))
+		})
+	})
+
+	Context("When reconciling an Ec2LaunchTemplate", func() {
+		var (
+			ctx        context.Context
+			key        types.NamespacedName
+			fake       *awsclient.FakeEC2Client
+			reconciler *Ec2LaunchTemplateReconciler
+		)
+
+		// stored returns the Ec2LaunchTemplate as stored in the API server.
+		stored := func() *computev1.Ec2LaunchTemplate {
+			obj := &computev1.Ec2LaunchTemplate{}
+			Expect(k8sClient.Get(ctx, key, obj)).To(Succeed())
+			return obj
+		}
+		reconcileOnce := func() reconcile.Result {
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			return result
+		}
+		// versionCreated makes AWS answer the next CreateLaunchTemplateVersion with version.
+		versionCreated := func(version int64) {
+			fake.Outputs["CreateLaunchTemplateVersion"] = &ec2.CreateLaunchTemplateVersionOutput{
+				LaunchTemplateVersion: &ec2types.LaunchTemplateVersion{VersionNumber: aws.Int64(version)},
+			}
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			obj := &computev1.Ec2LaunchTemplate{
+				ObjectMeta: metav1.ObjectMeta{GenerateName: "launchtemplate-", Namespace: "default"},
+				Spec:       template.Spec,
+			}
+			Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+			key = client.ObjectKeyFromObject(obj)
+			DeferCleanup(func() {
+				obj := &computev1.Ec2LaunchTemplate{}
+				if err := k8sClient.Get(ctx, key, obj); apierrors.IsNotFound(err) {
+					return
+				}
+				if controllerutil.RemoveFinalizer(obj, ec2LaunchTemplateFinalizer) {
+					Expect(k8sClient.Update(ctx, obj)).To(Succeed())
+				}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, obj))).To(Succeed())
+			})
+
+			fake = &awsclient.FakeEC2Client{Outputs: map[string]any{
+				"CreateLaunchTemplate": &ec2.CreateLaunchTemplateOutput{LaunchTemplate: &ec2types.LaunchTemplate{
+					LaunchTemplateId:     aws.String("lt-0123456789abcdef0"),
+					DefaultVersionNumber: aws.Int64(1),
+				}},
+			}}
+			reconciler = &Ec2LaunchTemplateReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), EC2Client: fake}
+		})
+
+		It("Should create the launch template and record it", func() {
+			reconcileOnce()
+
+			obj := stored()
+			Expect(obj.Finalizers).To(ContainElement(ec2LaunchTemplateFinalizer))
+			Expect(obj.Status.TemplateID).To(Equal("lt-0123456789abcdef0"))
+			Expect(obj.Status.DefaultVersion).To(Equal(int32(1)))
+			Expect(obj.Status.ObservedGeneration).To(Equal(obj.Generation))
+
+			Expect(fake.CallsTo("CreateLaunchTemplate")).To(HaveLen(1))
+			input := fake.CallsTo("CreateLaunchTemplate")[0].(*ec2.CreateLaunchTemplateInput)
+			Expect(aws.ToString(input.LaunchTemplateName)).To(Equal("web"))
+			Expect(input.LaunchTemplateData.InstanceType).To(Equal(ec2types.InstanceTypeT3Micro))
+			Expect(input.TagSpecifications[0].Tags).To(ConsistOf(ec2types.Tag{
+				Key: aws.String(ec2LaunchTemplateUIDTag), Value: aws.String(string(obj.UID)),
+			}))
+			// The first version is the default already.
+			Expect(fake.CallsTo("CreateLaunchTemplateVersion")).To(BeEmpty())
+
+			By("leaving AWS alone while the spec is unchanged")
+			reconcileOnce()
+			Expect(fake.Calls()).To(HaveLen(2))
+		})
+
+		It("Should add a version for a changed spec and make it the default", func() {
+			reconcileOnce()
+
+			obj := stored()
+			obj.Spec.LaunchTemplateData.InstanceType = "m5.large"
+			Expect(k8sClient.Update(ctx, obj)).To(Succeed())
+			versionCreated(2)
+			reconcileOnce()
+
+			Expect(fake.CallsTo("CreateLaunchTemplateVersion")).To(HaveLen(1))
+			input := fake.CallsTo("CreateLaunchTemplateVersion")[0].(*ec2.CreateLaunchTemplateVersionInput)
+			Expect(aws.ToString(input.LaunchTemplateId)).To(Equal("lt-0123456789abcdef0"))
+			Expect(input.LaunchTemplateData.InstanceType).To(Equal(ec2types.InstanceTypeM5Large))
+			Expect(fake.CallsTo("ModifyLaunchTemplate")).To(Equal([]any{&ec2.ModifyLaunchTemplateInput{
+				LaunchTemplateId: aws.String("lt-0123456789abcdef0"),
+				DefaultVersion:   aws.String("2"),
+			}}))
+
+			obj = stored()
+			Expect(obj.Status.DefaultVersion).To(Equal(int32(2)))
+			Expect(obj.Status.ObservedGeneration).To(Equal(obj.Generation))
+			Expect(fake.CallsTo("CreateLaunchTemplate")).To(HaveLen(1))
+		})
+
+		It("Should adopt the launch template it created before a crash", func() {
+			fake.Outputs["DescribeLaunchTemplates"] = &ec2.DescribeLaunchTemplatesOutput{LaunchTemplates: []ec2types.LaunchTemplate{{
+				LaunchTemplateId:     aws.String("lt-0aaaaaaaaaaaaaaaa"),
+				LaunchTemplateName:   aws.String("web"),
+				DefaultVersionNumber: aws.Int64(1),
+				Tags:                 []ec2types.Tag{{Key: aws.String(ec2LaunchTemplateUIDTag), Value: aws.String(string(stored().UID))}},
+			}}}
+			versionCreated(2)
+			reconcileOnce()
+
+			Expect(fake.CallsTo("CreateLaunchTemplate")).To(BeEmpty())
+			// The spec may have changed after the crash, so it is versioned again.
+			Expect(fake.CallsTo("CreateLaunchTemplateVersion")).To(HaveLen(1))
+			obj := stored()
+			Expect(obj.Status.TemplateID).To(Equal("lt-0aaaaaaaaaaaaaaaa"))
+			Expect(obj.Status.DefaultVersion).To(Equal(int32(2)))
+			Expect(obj.Status.ObservedGeneration).To(Equal(obj.Generation))
+			Expect(obj.Status.Message).To(BeEmpty())
+		})
+
+		It("Should leave a launch template with the same name created by someone else alone", func() {
+			fake.Outputs["DescribeLaunchTemplates"] = &ec2.DescribeLaunchTemplatesOutput{LaunchTemplates: []ec2types.LaunchTemplate{{
+				LaunchTemplateId:   aws.String("lt-0bbbbbbbbbbbbbbbb"),
+				LaunchTemplateName: aws.String("web"),
+			}}}
+			result := reconcileOnce()
+			Expect(result.RequeueAfter).To(Equal(ec2LaunchTemplateRetryInterval))
+
+			Expect(fake.CallsTo("CreateLaunchTemplate")).To(BeEmpty())
+			Expect(fake.CallsTo("CreateLaunchTemplateVersion")).To(BeEmpty())
+			obj := stored()
+			Expect(obj.Status.TemplateID).To(BeEmpty())
+			Expect(obj.Status.Message).To(ContainSubstring("was not created by this Ec2LaunchTemplate"))
+		})
+
+		It("Should delete the launch template before removing the finalizer", func() {
+			reconcileOnce()
+			Expect(k8sClient.Delete(ctx, stored())).To(Succeed())
+			reconcileOnce()
+
+			Expect(fake.CallsTo("DeleteLaunchTemplate")).To(Equal([]any{&ec2.DeleteLaunchTemplateInput{
+				LaunchTemplateId: aws.String("lt-0123456789abcdef0"),
+			}}))
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, key, &computev1.Ec2LaunchTemplate{}))).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// ec2PlacementGroupFinalizer makes sure the placement group is deleted in AWS before the object
+	// is removed.
+	ec2PlacementGroupFinalizer = "ec2placementgroup.compute.cloud.com"
+	// ec2PlacementGroupUIDTag tells placement groups created for the object apart from placement
+	// groups that merely have the same name.
+	ec2PlacementGroupUIDTag = "ec2placementgroup.compute.cloud.com/uid"
+	// ec2PlacementGroupRetryInterval is how often a placement group that cannot be managed, or is
+	// still being created, is looked at again.
+	ec2PlacementGroupRetryInterval = time.Minute
+)
+
+// Ec2PlacementGroupReconciler creates a placement group in AWS and deletes it with the object.
+type Ec2PlacementGroupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2placementgroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2placementgroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2placementgroups/finalizers,verbs=update
+
+// Reconcile creates the placement group and deletes it.
+func (r *Ec2PlacementGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	group := &computev1.Ec2PlacementGroup{}
+	if err := r.Get(ctx, req.NamespacedName, group); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	ec2Client := awsClient(group.Spec.Region)
+
+	if !group.DeletionTimestamp.IsZero() {
+		if group.Status.GroupID != "" {
+			// AWS refuses to delete a placement group that still has instances; the error is
+			// retried until they are gone.
+			_, err := ec2Client.DeletePlacementGroup(ctx, &ec2.DeletePlacementGroupInput{GroupName: aws.String(group.Spec.GroupName)})
+			if err != nil && !strings.Contains(err.Error(), "InvalidPlacementGroup.Unknown") {
+				l.Error(err, "Failed to delete placement group", "groupName", group.Spec.GroupName)
+				return ctrl.Result{}, fmt.Errorf("failed to delete placement group: %w", err)
+			}
+			l.Info("Deleted placement group", "groupName", group.Spec.GroupName, "groupID", group.Status.GroupID)
+		}
+
+		controllerutil.RemoveFinalizer(group, ec2PlacementGroupFinalizer)
+		if err := r.Update(ctx, group); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if controllerutil.AddFinalizer(group, ec2PlacementGroupFinalizer) {
+		if err := r.Update(ctx, group); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	existing, err := findPlacementGroup(ctx, ec2Client, group.Spec.GroupName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if existing == nil {
+		result, err := ec2Client.CreatePlacementGroup(ctx, createPlacementGroupInput(group))
+		if err != nil {
+			l.Error(err, "Failed to create placement group", "groupName", group.Spec.GroupName)
+			return ctrl.Result{}, fmt.Errorf("failed to create placement group %s: %w", group.Spec.GroupName, err)
+		}
+		existing = result.PlacementGroup
+		l.Info("Created placement group", "groupName", group.Spec.GroupName, "groupID", aws.ToString(existing.GroupId))
+	}
+
+	status := computev1.Ec2PlacementGroupStatus{GroupID: group.Status.GroupID, State: group.Status.State}
+	if placementGroupOwned(group, existing) {
+		status.GroupID = aws.ToString(existing.GroupId)
+		status.State = string(existing.State)
+	} else {
+		status.Message = fmt.Sprintf("placement group %s already exists in %s and was not created by this Ec2PlacementGroup",
+			group.Spec.GroupName, group.Spec.Region)
+	}
+	if group.Status != status {
+		group.Status = status
+		if err := r.Status().Update(ctx, group); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if status.Message != "" {
+		l.Info("Cannot manage placement group", "groupName", group.Spec.GroupName, "reason", status.Message)
+		return ctrl.Result{RequeueAfter: ec2PlacementGroupRetryInterval}, nil
+	}
+	if status.State != string(ec2types.PlacementGroupStateAvailable) {
+		return ctrl.Result{RequeueAfter: ec2PlacementGroupRetryInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// createPlacementGroupInput returns the request that creates the placement group of the object,
+// tagged so it can be recognised as created by it.
+func createPlacementGroupInput(group *computev1.Ec2PlacementGroup) *ec2.CreatePlacementGroupInput {
+	input := &ec2.CreatePlacementGroupInput{
+		GroupName: aws.String(group.Spec.GroupName),
+		Strategy:  ec2types.PlacementStrategy(group.Spec.Strategy),
+		TagSpecifications: []ec2types.TagSpecification{{
+			ResourceType: ec2types.ResourceTypePlacementGroup,
+			Tags:         []ec2types.Tag{{Key: aws.String(ec2PlacementGroupUIDTag), Value: aws.String(string(group.UID))}},
+		}},
+	}
+	if group.Spec.PartitionCount > 0 {
+		input.PartitionCount = aws.Int32(group.Spec.PartitionCount)
+	}
+	return input
+}
+
+// placementGroupOwned reports whether the placement group found in AWS was created for the object.
+func placementGroupOwned(group *computev1.Ec2PlacementGroup, existing *ec2types.PlacementGroup) bool {
+	for _, tag := range existing.Tags {
+		if aws.ToString(tag.Key) == ec2PlacementGroupUIDTag && aws.ToString(tag.Value) == string(group.UID) {
+			return true
+		}
+	}
+	return false
+}
+
+// findPlacementGroup returns the placement group named groupName, or nil. A filter is used rather
+// than GroupNames, which fails when the placement group does not exist.
+func findPlacementGroup(ctx context.Context, ec2Client *ec2.Client, groupName string) (*ec2types.PlacementGroup, error) {
+	result, err := ec2Client.DescribePlacementGroups(ctx, &ec2.DescribePlacementGroupsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("group-name"), Values: []string{groupName}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe placement group %s: %w", groupName, err)
+	}
+	for i := range result.PlacementGroups {
+		// A deleted placement group is listed for a while, and its name can be taken again.
+		if result.PlacementGroups[i].State != ec2types.PlacementGroupStateDeleted {
+			return &result.PlacementGroups[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Ec2PlacementGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.Ec2PlacementGroup{}).
+		Named("ec2placementgroup").
+		Complete(r)
+}
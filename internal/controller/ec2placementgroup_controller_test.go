@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Ec2PlacementGroup Controller", func() {
+	var group *computev1.Ec2PlacementGroup
+
+	BeforeEach(func() {
+		group = &computev1.Ec2PlacementGroup{
+			ObjectMeta: metav1.ObjectMeta{UID: "uid-1"},
+			Spec:       computev1.Ec2PlacementGroupSpec{Region: "us-east-1", GroupName: "hpc", Strategy: computev1.PlacementStrategyPartition},
+		}
+	})
+
+	Context("When creating the placement group", func() {
+		It("Should tag it with the object and only set the partition count when asked to", func() {
+			input := createPlacementGroupInput(group)
+			Expect(aws.ToString(input.GroupName)).To(Equal("hpc"))
+			Expect(input.Strategy).To(Equal(ec2types.PlacementStrategyPartition))
+			Expect(input.PartitionCount).To(BeNil())
+			Expect(input.TagSpecifications[0].Tags).To(ConsistOf(ec2types.Tag{Key: aws.String(ec2PlacementGroupUIDTag), Value: aws.String("uid-1")}))
+
+			group.Spec.PartitionCount = 3
+			Expect(aws.ToInt32(createPlacementGroupInput(group).PartitionCount)).To(Equal(int32(3)))
+		})
+	})
+
+	Context("When looking at the placement group in AWS", func() {
+		It("Should leave a placement group with the same name created by someone else alone", func() {
+			owned := &ec2types.PlacementGroup{Tags: []ec2types.Tag{{Key: aws.String(ec2PlacementGroupUIDTag), Value: aws.String("uid-1")}}}
+			Expect(placementGroupOwned(group, owned)).To(BeTrue())
+			Expect(placementGroupOwned(group, &ec2types.PlacementGroup{GroupName: aws.String("hpc")})).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,324 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// ec2SecurityGroupFinalizer makes sure the security group is deleted in AWS before the object is removed.
+	ec2SecurityGroupFinalizer = "ec2securitygroup.compute.cloud.com"
+	// ec2SecurityGroupUIDTag is put on the group so a create whose status update was lost finds its
+	// group again instead of failing on the duplicate name.
+	ec2SecurityGroupUIDTag = "ec2securitygroup.compute.cloud.com/uid"
+	// ec2SecurityGroupResync is how often the rules are compared with AWS to undo manual changes.
+	ec2SecurityGroupResync = 5 * time.Minute
+)
+
+// Ec2SecurityGroupReconciler keeps a VPC security group and its rules in line with the
+// Ec2SecurityGroup object.
+type Ec2SecurityGroupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2securitygroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2securitygroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2securitygroups/finalizers,verbs=update
+
+// Reconcile creates the security group, converges its rules and deletes it.
+func (r *Ec2SecurityGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	group := &computev1.Ec2SecurityGroup{}
+	if err := r.Get(ctx, req.NamespacedName, group); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	ec2Client := awsClient(group.Spec.Region)
+
+	if !group.DeletionTimestamp.IsZero() {
+		if group.Status.GroupID != "" {
+			inUse, err := deleteSecurityGroup(ctx, ec2Client, group.Status.GroupID)
+			if err != nil {
+				l.Error(err, "Failed to delete security group", "groupID", group.Status.GroupID)
+				return ctrl.Result{}, err
+			}
+			// Instances still using the group keep it alive; retry once they are gone.
+			if inUse {
+				l.Info("Security group is still in use, retrying", "groupID", group.Status.GroupID)
+				return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+			}
+			l.Info("Deleted security group", "groupID", group.Status.GroupID)
+		}
+
+		controllerutil.RemoveFinalizer(group, ec2SecurityGroupFinalizer)
+		if err := r.Update(ctx, group); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if controllerutil.AddFinalizer(group, ec2SecurityGroupFinalizer) {
+		if err := r.Update(ctx, group); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	groupID, err := findSecurityGroup(ctx, ec2Client, group)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if groupID == "" {
+		result, err := ec2Client.CreateSecurityGroup(ctx, &ec2.CreateSecurityGroupInput{
+			GroupName:   aws.String(group.Namespace + "-" + group.Name),
+			Description: aws.String(group.Spec.Description),
+			VpcId:       aws.String(group.Spec.VpcID),
+			TagSpecifications: []ec2types.TagSpecification{{
+				ResourceType: ec2types.ResourceTypeSecurityGroup,
+				Tags: []ec2types.Tag{
+					{Key: aws.String("Name"), Value: aws.String(group.Name)},
+					{Key: aws.String(ec2SecurityGroupUIDTag), Value: aws.String(string(group.UID))},
+				},
+			}},
+		})
+		if err != nil {
+			l.Error(err, "Failed to create security group")
+			return ctrl.Result{}, fmt.Errorf("failed to create security group: %w", err)
+		}
+		groupID = aws.ToString(result.GroupId)
+		l.Info("Created security group", "groupID", groupID)
+	}
+	if group.Status.GroupID != groupID {
+		group.Status.GroupID = groupID
+		if err := r.Status().Update(ctx, group); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	syncErr := syncSecurityGroupRules(ctx, ec2Client, group)
+	message := ""
+	if syncErr != nil {
+		message = syncErr.Error()
+	}
+	if group.Status.Message != message || (syncErr == nil && group.Status.ObservedGeneration != group.Generation) {
+		group.Status.Message = message
+		if syncErr == nil {
+			group.Status.ObservedGeneration = group.Generation
+		}
+		if err := r.Status().Update(ctx, group); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if syncErr != nil {
+		return ctrl.Result{}, syncErr
+	}
+	return ctrl.Result{RequeueAfter: ec2SecurityGroupResync}, nil
+}
+
+// findSecurityGroup returns the ID of the object's group, looked up by the ID in status or, before
+// that is recorded, by the UID tag. It returns "" when there is none.
+func findSecurityGroup(ctx context.Context, ec2Client *ec2.Client, group *computev1.Ec2SecurityGroup) (string, error) {
+	filter := ec2types.Filter{Name: aws.String("tag:" + ec2SecurityGroupUIDTag), Values: []string{string(group.UID)}}
+	if group.Status.GroupID != "" {
+		filter = ec2types.Filter{Name: aws.String("group-id"), Values: []string{group.Status.GroupID}}
+	}
+	result, err := ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{Filters: []ec2types.Filter{filter}})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe security group: %w", err)
+	}
+	if len(result.SecurityGroups) == 0 {
+		return "", nil
+	}
+	return aws.ToString(result.SecurityGroups[0].GroupId), nil
+}
+
+// securityGroupRuleKey identifies a single rule: one protocol, port range and peer.
+type securityGroupRuleKey struct {
+	egress   bool
+	protocol string
+	fromPort int32
+	toPort   int32
+	peer     string
+}
+
+// newSecurityGroupRuleKey normalizes a rule the way AWS stores it: "all" is protocol -1 without ports.
+func newSecurityGroupRuleKey(egress bool, protocol string, fromPort, toPort int32, peer string) securityGroupRuleKey {
+	if protocol == string(computev1.SecurityGroupProtocolAll) || protocol == "-1" {
+		return securityGroupRuleKey{egress: egress, protocol: "-1", fromPort: -1, toPort: -1, peer: peer}
+	}
+	return securityGroupRuleKey{egress: egress, protocol: strings.ToLower(protocol), fromPort: fromPort, toPort: toPort, peer: peer}
+}
+
+// permission returns the rule as the IpPermission AuthorizeSecurityGroupIngress/Egress take.
+func (k securityGroupRuleKey) permission(description string) ec2types.IpPermission {
+	permission := ec2types.IpPermission{IpProtocol: aws.String(k.protocol)}
+	if k.protocol != "-1" {
+		permission.FromPort = aws.Int32(k.fromPort)
+		permission.ToPort = aws.Int32(k.toPort)
+	}
+	var desc *string
+	if description != "" {
+		desc = aws.String(description)
+	}
+	switch {
+	case strings.HasPrefix(k.peer, "sg-"):
+		permission.UserIdGroupPairs = []ec2types.UserIdGroupPair{{GroupId: aws.String(k.peer), Description: desc}}
+	case strings.Contains(k.peer, ":"):
+		permission.Ipv6Ranges = []ec2types.Ipv6Range{{CidrIpv6: aws.String(k.peer), Description: desc}}
+	default:
+		permission.IpRanges = []ec2types.IpRange{{CidrIp: aws.String(k.peer), Description: desc}}
+	}
+	return permission
+}
+
+// desiredSecurityGroupRules expands the spec into single rules, with their descriptions. Without
+// egress rules the allow-all rule AWS creates is kept.
+func desiredSecurityGroupRules(spec computev1.Ec2SecurityGroupSpec) map[securityGroupRuleKey]string {
+	rules := map[securityGroupRuleKey]string{}
+	for _, rule := range spec.Ingress {
+		for _, peer := range append(append([]string{}, rule.CIDRBlocks...), rule.SourceSecurityGroupIDs...) {
+			rules[newSecurityGroupRuleKey(false, string(rule.Protocol), rule.FromPort, rule.ToPort, peer)] = rule.Description
+		}
+	}
+	for _, rule := range spec.Egress {
+		for _, peer := range append(append([]string{}, rule.CIDRBlocks...), rule.DestinationSecurityGroupIDs...) {
+			rules[newSecurityGroupRuleKey(true, string(rule.Protocol), rule.FromPort, rule.ToPort, peer)] = rule.Description
+		}
+	}
+	if len(spec.Egress) == 0 {
+		rules[newSecurityGroupRuleKey(true, "-1", 0, 0, "0.0.0.0/0")] = ""
+	}
+	return rules
+}
+
+// currentSecurityGroupRuleKey returns the key of a rule as AWS reports it.
+func currentSecurityGroupRuleKey(rule ec2types.SecurityGroupRule) securityGroupRuleKey {
+	peer := aws.ToString(rule.CidrIpv4)
+	if rule.CidrIpv6 != nil {
+		peer = aws.ToString(rule.CidrIpv6)
+	}
+	if rule.ReferencedGroupInfo != nil {
+		peer = aws.ToString(rule.ReferencedGroupInfo.GroupId)
+	}
+	return newSecurityGroupRuleKey(aws.ToBool(rule.IsEgress), aws.ToString(rule.IpProtocol),
+		aws.ToInt32(rule.FromPort), aws.ToInt32(rule.ToPort), peer)
+}
+
+// securityGroupRuleChanges compares the rules in AWS with the desired ones. It returns the rules to
+// add and the IDs of the ingress and egress rules to revoke. Descriptions are not compared.
+func securityGroupRuleChanges(desired map[securityGroupRuleKey]string, current []ec2types.SecurityGroupRule) (add []securityGroupRuleKey, revokeIngress, revokeEgress []string) {
+	existing := map[securityGroupRuleKey]bool{}
+	for _, rule := range current {
+		key := currentSecurityGroupRuleKey(rule)
+		if _, ok := desired[key]; ok && !existing[key] {
+			existing[key] = true
+			continue
+		}
+		if key.egress {
+			revokeEgress = append(revokeEgress, aws.ToString(rule.SecurityGroupRuleId))
+		} else {
+			revokeIngress = append(revokeIngress, aws.ToString(rule.SecurityGroupRuleId))
+		}
+	}
+	for key := range desired {
+		if !existing[key] {
+			add = append(add, key)
+		}
+	}
+	return add, revokeIngress, revokeEgress
+}
+
+// syncSecurityGroupRules authorizes and revokes rules so the group matches the spec.
+func syncSecurityGroupRules(ctx context.Context, ec2Client *ec2.Client, group *computev1.Ec2SecurityGroup) error {
+	l := log.FromContext(ctx)
+	groupID := aws.String(group.Status.GroupID)
+
+	var current []ec2types.SecurityGroupRule
+	paginator := ec2.NewDescribeSecurityGroupRulesPaginator(ec2Client, &ec2.DescribeSecurityGroupRulesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("group-id"), Values: []string{group.Status.GroupID}}},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe security group rules: %w", err)
+		}
+		current = append(current, page.SecurityGroupRules...)
+	}
+
+	desired := desiredSecurityGroupRules(group.Spec)
+	add, revokeIngress, revokeEgress := securityGroupRuleChanges(desired, current)
+
+	// Add first, so traffic the spec allows is never cut off in between.
+	var ingress, egress []ec2types.IpPermission
+	for _, key := range add {
+		if key.egress {
+			egress = append(egress, key.permission(desired[key]))
+		} else {
+			ingress = append(ingress, key.permission(desired[key]))
+		}
+	}
+	if len(ingress) > 0 {
+		if _, err := ec2Client.AuthorizeSecurityGroupIngress(ctx, &ec2.AuthorizeSecurityGroupIngressInput{GroupId: groupID, IpPermissions: ingress}); err != nil {
+			return fmt.Errorf("failed to authorize ingress rules: %w", err)
+		}
+	}
+	if len(egress) > 0 {
+		if _, err := ec2Client.AuthorizeSecurityGroupEgress(ctx, &ec2.AuthorizeSecurityGroupEgressInput{GroupId: groupID, IpPermissions: egress}); err != nil {
+			return fmt.Errorf("failed to authorize egress rules: %w", err)
+		}
+	}
+	if len(revokeIngress) > 0 {
+		if _, err := ec2Client.RevokeSecurityGroupIngress(ctx, &ec2.RevokeSecurityGroupIngressInput{GroupId: groupID, SecurityGroupRuleIds: revokeIngress}); err != nil {
+			return fmt.Errorf("failed to revoke ingress rules: %w", err)
+		}
+	}
+	if len(revokeEgress) > 0 {
+		if _, err := ec2Client.RevokeSecurityGroupEgress(ctx, &ec2.RevokeSecurityGroupEgressInput{GroupId: groupID, SecurityGroupRuleIds: revokeEgress}); err != nil {
+			return fmt.Errorf("failed to revoke egress rules: %w", err)
+		}
+	}
+	if len(add)+len(revokeIngress)+len(revokeEgress) > 0 {
+		l.Info("Updated security group rules", "groupID", group.Status.GroupID,
+			"added", len(add), "revoked", len(revokeIngress)+len(revokeEgress))
+	}
+	return nil
+}
+
+// deleteSecurityGroup deletes the group and reports whether it could not be deleted yet because
+// something, such as an instance, still uses it. Groups that are already gone are left alone.
+func deleteSecurityGroup(ctx context.Context, ec2Client *ec2.Client, groupID string) (bool, error) {
+	_, err := ec2Client.DeleteSecurityGroup(ctx, &ec2.DeleteSecurityGroupInput{GroupId: aws.String(groupID)})
+	switch {
+	case err == nil, strings.Contains(err.Error(), "InvalidGroup.NotFound"):
+		return false, nil
+	case strings.Contains(err.Error(), "DependencyViolation"):
+		return true, nil
+	}
+	return false, fmt.Errorf("failed to delete security group: %w", err)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Ec2SecurityGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.Ec2SecurityGroup{}).
+		Named("ec2securitygroup").
+		Complete(r)
+}
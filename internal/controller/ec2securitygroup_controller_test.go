@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Ec2SecurityGroup Controller", func() {
+	Context("When comparing the rules in the spec with AWS", func() {
+		cidrRule := func(id string, egress bool, protocol string, from, to int32, cidr string) ec2types.SecurityGroupRule {
+			return ec2types.SecurityGroupRule{
+				SecurityGroupRuleId: aws.String(id),
+				IsEgress:            aws.Bool(egress),
+				IpProtocol:          aws.String(protocol),
+				FromPort:            aws.Int32(from),
+				ToPort:              aws.Int32(to),
+				CidrIpv4:            aws.String(cidr),
+			}
+		}
+
+		It("Should keep the allow-all egress rule when the spec has no egress rules", func() {
+			spec := computev1.Ec2SecurityGroupSpec{Ingress: []computev1.IngressRule{
+				{Protocol: computev1.SecurityGroupProtocolTCP, FromPort: 443, ToPort: 443, CIDRBlocks: []string{"0.0.0.0/0"}},
+			}}
+			current := []ec2types.SecurityGroupRule{cidrRule("sgr-egress", true, "-1", -1, -1, "0.0.0.0/0")}
+
+			add, revokeIngress, revokeEgress := securityGroupRuleChanges(desiredSecurityGroupRules(spec), current)
+			Expect(add).To(ConsistOf(newSecurityGroupRuleKey(false, "tcp", 443, 443, "0.0.0.0/0")))
+			Expect(revokeIngress).To(BeEmpty())
+			Expect(revokeEgress).To(BeEmpty())
+		})
+
+		It("Should revoke rules added outside the spec and the default egress rule when egress is given", func() {
+			spec := computev1.Ec2SecurityGroupSpec{
+				Ingress: []computev1.IngressRule{
+					{Protocol: computev1.SecurityGroupProtocolTCP, FromPort: 22, ToPort: 22, SourceSecurityGroupIDs: []string{"sg-bastion"}},
+				},
+				Egress: []computev1.EgressRule{
+					{Protocol: computev1.SecurityGroupProtocolAll, CIDRBlocks: []string{"10.0.0.0/8"}},
+				},
+			}
+			bastion := ec2types.SecurityGroupRule{
+				SecurityGroupRuleId: aws.String("sgr-bastion"),
+				IsEgress:            aws.Bool(false),
+				IpProtocol:          aws.String("tcp"),
+				FromPort:            aws.Int32(22),
+				ToPort:              aws.Int32(22),
+				ReferencedGroupInfo: &ec2types.ReferencedSecurityGroup{GroupId: aws.String("sg-bastion")},
+			}
+			current := []ec2types.SecurityGroupRule{
+				bastion,
+				cidrRule("sgr-manual", false, "tcp", 3389, 3389, "0.0.0.0/0"),
+				cidrRule("sgr-egress", true, "-1", -1, -1, "0.0.0.0/0"),
+			}
+
+			add, revokeIngress, revokeEgress := securityGroupRuleChanges(desiredSecurityGroupRules(spec), current)
+			Expect(add).To(ConsistOf(newSecurityGroupRuleKey(true, "-1", -1, -1, "10.0.0.0/8")))
+			Expect(revokeIngress).To(ConsistOf("sgr-manual"))
+			Expect(revokeEgress).To(ConsistOf("sgr-egress"))
+		})
+
+		It("Should put IPv6 ranges and security groups into the matching part of the permission", func() {
+			permission := newSecurityGroupRuleKey(false, "tcp", 80, 80, "::/0").permission("HTTP")
+			Expect(permission.Ipv6Ranges).To(HaveLen(1))
+			Expect(aws.ToString(permission.Ipv6Ranges[0].Description)).To(Equal("HTTP"))
+			Expect(permission.IpRanges).To(BeEmpty())
+
+			permission = newSecurityGroupRuleKey(false, "all", 0, 0, "sg-1").permission("")
+			Expect(aws.ToString(permission.IpProtocol)).To(Equal("-1"))
+			Expect(permission.FromPort).To(BeNil())
+			Expect(aws.ToString(permission.UserIdGroupPairs[0].GroupId)).To(Equal("sg-1"))
+		})
+	})
+})
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// elasticIPOwnerTag marks the Elastic IPs allocated for spec.elasticIP with the namespace/name of
+// their Ec2Instance.
+const elasticIPOwnerTag = "ec2instance.compute.cloud.com/elastic-ip-of"
+
+// elasticIPAPI is the part of the EC2 API that manages the Elastic IP of spec.elasticIP.
+type elasticIPAPI interface {
+	AllocateAddress(ctx context.Context, params *ec2.AllocateAddressInput, optFns ...func(*ec2.Options)) (*ec2.AllocateAddressOutput, error)
+	DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)
+	AssociateAddress(ctx context.Context, params *ec2.AssociateAddressInput, optFns ...func(*ec2.Options)) (*ec2.AssociateAddressOutput, error)
+	DisassociateAddress(ctx context.Context, params *ec2.DisassociateAddressInput, optFns ...func(*ec2.Options)) (*ec2.DisassociateAddressOutput, error)
+	ReleaseAddress(ctx context.Context, params *ec2.ReleaseAddressInput, optFns ...func(*ec2.Options)) (*ec2.ReleaseAddressOutput, error)
+}
+
+// describeElasticIP returns the Elastic IP with allocationID, or nil if it does not exist. A filter
+// is used rather than AllocationIds, which fails when the address is gone.
+func describeElasticIP(ctx context.Context, ec2Client elasticIPAPI, allocationID string) (*ec2types.Address, error) {
+	result, err := ec2Client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("allocation-id"), Values: []string{allocationID}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe Elastic IP %s: %w", allocationID, err)
+	}
+	if len(result.Addresses) == 0 {
+		return nil, nil
+	}
+	return &result.Addresses[0], nil
+}
+
+// ownedElasticIP returns the Elastic IP the operator allocated that is associated with instanceID,
+// or nil if there is none.
+func ownedElasticIP(ctx context.Context, ec2Client elasticIPAPI, instanceID string) (*ec2types.Address, error) {
+	result, err := ec2Client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("instance-id"), Values: []string{instanceID}},
+			{Name: aws.String("tag-key"), Values: []string{elasticIPOwnerTag}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe Elastic IPs of %s: %w", instanceID, err)
+	}
+	if len(result.Addresses) == 0 {
+		return nil, nil
+	}
+	return &result.Addresses[0], nil
+}
+
+// reconcileElasticIP associates the Elastic IP of spec.elasticIP with the running instance,
+// allocating one first unless an existing allocation is given, and reports it as the public IP.
+// An address allocated by the operator is released once the spec no longer uses it. One that is
+// already associated with the instance, e.g. after it was adopted, is taken over rather than
+// replaced with a second one.
+func (r *Ec2InstanceReconciler) reconcileElasticIP(ctx context.Context, ec2Client elasticIPAPI, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	spec := ec2Instance.Spec.ElasticIP
+	released := false
+	if !spec.Enabled || spec.ExistingAllocationID != "" {
+		if ec2Instance.Status.ElasticIPAllocationID != "" {
+			if err := releaseElasticIP(ctx, ec2Client, ec2Instance); err != nil {
+				return err
+			}
+			released = true
+		}
+	}
+	if !spec.Enabled {
+		// awsInstance still shows a just released address; the public IP AWS assigns instead, if
+		// any, is picked up on the next sync.
+		if released {
+			ec2Instance.Status.PublicIP = ""
+		} else {
+			ec2Instance.Status.PublicIP = derefString(awsInstance.PublicIpAddress)
+		}
+		return nil
+	}
+	if awsInstance.State == nil || awsInstance.State.Name != ec2types.InstanceStateNameRunning {
+		return nil
+	}
+	l := log.FromContext(ctx)
+	instanceID := ec2Instance.Status.InstanceID
+
+	allocationID := spec.ExistingAllocationID
+	if allocationID == "" {
+		allocationID = ec2Instance.Status.ElasticIPAllocationID
+	}
+	var address *ec2types.Address
+	if allocationID != "" {
+		var err error
+		if address, err = describeElasticIP(ctx, ec2Client, allocationID); err != nil {
+			return err
+		}
+		if address == nil && spec.ExistingAllocationID != "" {
+			return fmt.Errorf("elastic IP %s from spec.elasticIP.existingAllocationID does not exist", allocationID)
+		}
+		if address == nil {
+			l.Info("Allocated Elastic IP is gone, allocating a new one", "allocationID", allocationID)
+			ec2Instance.Status.ElasticIPAllocationID = ""
+		}
+	}
+	if address == nil && spec.ExistingAllocationID == "" {
+		var err error
+		if address, err = ownedElasticIP(ctx, ec2Client, instanceID); err != nil {
+			return err
+		}
+		if address != nil {
+			allocationID = aws.ToString(address.AllocationId)
+			ec2Instance.Status.ElasticIPAllocationID = allocationID
+			l.Info("Took over associated Elastic IP", "allocationID", allocationID, "publicIP", aws.ToString(address.PublicIp))
+		}
+	}
+	if address == nil {
+		allocated, err := ec2Client.AllocateAddress(ctx, &ec2.AllocateAddressInput{
+			Domain: ec2types.DomainTypeVpc,
+			TagSpecifications: []ec2types.TagSpecification{{
+				ResourceType: ec2types.ResourceTypeElasticIp,
+				Tags: []ec2types.Tag{
+					{Key: aws.String(elasticIPOwnerTag), Value: aws.String(ec2Instance.Namespace + "/" + ec2Instance.Name)},
+					{Key: aws.String("Name"), Value: aws.String(ec2Instance.Name)},
+				},
+			}},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to allocate Elastic IP: %w", err)
+		}
+		allocationID = aws.ToString(allocated.AllocationId)
+		ec2Instance.Status.ElasticIPAllocationID = allocationID
+		address = &ec2types.Address{AllocationId: allocated.AllocationId, PublicIp: allocated.PublicIp}
+		l.Info("Allocated Elastic IP", "allocationID", allocationID, "publicIP", aws.ToString(allocated.PublicIp))
+	}
+
+	if aws.ToString(address.InstanceId) != instanceID {
+		// An address held by another instance is not taken away from it.
+		if _, err := ec2Client.AssociateAddress(ctx, &ec2.AssociateAddressInput{
+			AllocationId:       aws.String(allocationID),
+			InstanceId:         aws.String(instanceID),
+			AllowReassociation: aws.Bool(false),
+		}); err != nil {
+			return fmt.Errorf("failed to associate Elastic IP %s: %w", allocationID, err)
+		}
+		l.Info("Associated Elastic IP", "allocationID", allocationID, "publicIP", aws.ToString(address.PublicIp))
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "ElasticIPAssociated",
+			"Associated Elastic IP %s (%s)", aws.ToString(address.PublicIp), allocationID)
+	}
+	ec2Instance.Status.PublicIP = aws.ToString(address.PublicIp)
+	return nil
+}
+
+// releaseElasticIP disassociates the Elastic IP the operator allocated and releases it. Addresses
+// from spec.elasticIP.existingAllocationID are left alone; terminating the instance frees them.
+func releaseElasticIP(ctx context.Context, ec2Client elasticIPAPI, ec2Instance *computev1.Ec2Instance) error {
+	allocationID := ec2Instance.Status.ElasticIPAllocationID
+	if allocationID == "" {
+		return nil
+	}
+	address, err := describeElasticIP(ctx, ec2Client, allocationID)
+	if err != nil {
+		return err
+	}
+	if address != nil {
+		if address.AssociationId != nil {
+			if _, err := ec2Client.DisassociateAddress(ctx, &ec2.DisassociateAddressInput{AssociationId: address.AssociationId}); err != nil {
+				return fmt.Errorf("failed to disassociate Elastic IP %s: %w", allocationID, err)
+			}
+		}
+		if _, err := ec2Client.ReleaseAddress(ctx, &ec2.ReleaseAddressInput{AllocationId: aws.String(allocationID)}); err != nil {
+			return fmt.Errorf("failed to release Elastic IP %s: %w", allocationID, err)
+		}
+		log.FromContext(ctx).Info("Released Elastic IP", "allocationID", allocationID, "publicIP", aws.ToString(address.PublicIp))
+	}
+	ec2Instance.Status.ElasticIPAllocationID = ""
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// fakeAddressAPI keeps Elastic IPs in memory, keyed by allocation ID.
+type fakeAddressAPI struct {
+	addresses map[string]*ec2types.Address
+	calls     []string
+}
+
+func (f *fakeAddressAPI) AllocateAddress(_ context.Context, in *ec2.AllocateAddressInput, _ ...func(*ec2.Options)) (*ec2.AllocateAddressOutput, error) {
+	f.calls = append(f.calls, "AllocateAddress")
+	f.addresses["eipalloc-new"] = &ec2types.Address{AllocationId: aws.String("eipalloc-new"), PublicIp: aws.String("203.0.113.10"), Tags: in.TagSpecifications[0].Tags}
+	return &ec2.AllocateAddressOutput{AllocationId: aws.String("eipalloc-new"), PublicIp: aws.String("203.0.113.10")}, nil
+}
+
+func (f *fakeAddressAPI) DescribeAddresses(_ context.Context, in *ec2.DescribeAddressesInput, _ ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error) {
+	out := &ec2.DescribeAddressesOutput{}
+	for _, address := range f.addresses {
+		if matchesAddressFilters(address, in.Filters) {
+			out.Addresses = append(out.Addresses, *address)
+		}
+	}
+	return out, nil
+}
+
+// matchesAddressFilters reports whether address matches the DescribeAddresses filters the operator uses.
+func matchesAddressFilters(address *ec2types.Address, filters []ec2types.Filter) bool {
+	for _, filter := range filters {
+		var value string
+		switch aws.ToString(filter.Name) {
+		case "allocation-id":
+			value = aws.ToString(address.AllocationId)
+		case "instance-id":
+			value = aws.ToString(address.InstanceId)
+		case "tag-key":
+			for _, tag := range address.Tags {
+				if aws.ToString(tag.Key) == filter.Values[0] {
+					value = filter.Values[0]
+				}
+			}
+		}
+		if value != filter.Values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *fakeAddressAPI) AssociateAddress(_ context.Context, in *ec2.AssociateAddressInput, _ ...func(*ec2.Options)) (*ec2.AssociateAddressOutput, error) {
+	f.calls = append(f.calls, "AssociateAddress "+aws.ToString(in.AllocationId))
+	address := f.addresses[aws.ToString(in.AllocationId)]
+	address.InstanceId, address.AssociationId = in.InstanceId, aws.String("eipassoc-1")
+	return &ec2.AssociateAddressOutput{AssociationId: address.AssociationId}, nil
+}
+
+func (f *fakeAddressAPI) DisassociateAddress(_ context.Context, in *ec2.DisassociateAddressInput, _ ...func(*ec2.Options)) (*ec2.DisassociateAddressOutput, error) {
+	f.calls = append(f.calls, "DisassociateAddress "+aws.ToString(in.AssociationId))
+	return &ec2.DisassociateAddressOutput{}, nil
+}
+
+func (f *fakeAddressAPI) ReleaseAddress(_ context.Context, in *ec2.ReleaseAddressInput, _ ...func(*ec2.Options)) (*ec2.ReleaseAddressOutput, error) {
+	f.calls = append(f.calls, "ReleaseAddress "+aws.ToString(in.AllocationId))
+	delete(f.addresses, aws.ToString(in.AllocationId))
+	return &ec2.ReleaseAddressOutput{}, nil
+}
+
+var _ = Describe("Elastic IP", func() {
+	var (
+		fake    *fakeAddressAPI
+		r       *Ec2InstanceReconciler
+		inst    *computev1.Ec2Instance
+		running *ec2types.Instance
+	)
+
+	BeforeEach(func() {
+		fake = &fakeAddressAPI{addresses: map[string]*ec2types.Address{}}
+		r = &Ec2InstanceReconciler{Recorder: record.NewFakeRecorder(10)}
+		inst = &computev1.Ec2Instance{
+			Spec:   computev1.Ec2InstanceSpec{ElasticIP: computev1.ElasticIPSpec{Enabled: true}},
+			Status: computev1.Ec2InstanceStatus{InstanceID: "i-0123456789abcdef0", PublicIP: "198.51.100.1"},
+		}
+		running = &ec2types.Instance{State: &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning}}
+	})
+
+	It("should allocate an address once, associate it and report it as the public IP", func() {
+		Expect(r.reconcileElasticIP(context.Background(), fake, inst, running)).To(Succeed())
+		Expect(fake.calls).To(Equal([]string{"AllocateAddress", "AssociateAddress eipalloc-new"}))
+		Expect(inst.Status.ElasticIPAllocationID).To(Equal("eipalloc-new"))
+		Expect(inst.Status.PublicIP).To(Equal("203.0.113.10"))
+
+		fake.calls = nil
+		Expect(r.reconcileElasticIP(context.Background(), fake, inst, running)).To(Succeed())
+		Expect(fake.calls).To(BeEmpty())
+	})
+
+	It("should associate an existing allocation without allocating or recording it", func() {
+		fake.addresses["eipalloc-0abc"] = &ec2types.Address{AllocationId: aws.String("eipalloc-0abc"), PublicIp: aws.String("203.0.113.20")}
+		inst.Spec.ElasticIP.ExistingAllocationID = "eipalloc-0abc"
+		Expect(r.reconcileElasticIP(context.Background(), fake, inst, running)).To(Succeed())
+		Expect(fake.calls).To(Equal([]string{"AssociateAddress eipalloc-0abc"}))
+		Expect(inst.Status.ElasticIPAllocationID).To(BeEmpty())
+		Expect(inst.Status.PublicIP).To(Equal("203.0.113.20"))
+	})
+
+	It("should take over the address the operator associated with an adopted instance", func() {
+		fake.addresses["eipalloc-old"] = &ec2types.Address{
+			AllocationId:  aws.String("eipalloc-old"),
+			PublicIp:      aws.String("203.0.113.30"),
+			InstanceId:    aws.String("i-0123456789abcdef0"),
+			AssociationId: aws.String("eipassoc-old"),
+			Tags:          []ec2types.Tag{{Key: aws.String(elasticIPOwnerTag), Value: aws.String("team-a/web")}},
+		}
+		Expect(r.reconcileElasticIP(context.Background(), fake, inst, running)).To(Succeed())
+		Expect(fake.calls).To(BeEmpty())
+		Expect(inst.Status.ElasticIPAllocationID).To(Equal("eipalloc-old"))
+		Expect(inst.Status.PublicIP).To(Equal("203.0.113.30"))
+	})
+
+	It("should not take over an address the operator did not allocate", func() {
+		fake.addresses["eipalloc-foreign"] = &ec2types.Address{
+			AllocationId: aws.String("eipalloc-foreign"),
+			InstanceId:   aws.String("i-0123456789abcdef0"),
+		}
+		Expect(r.reconcileElasticIP(context.Background(), fake, inst, running)).To(Succeed())
+		Expect(fake.calls).To(Equal([]string{"AllocateAddress", "AssociateAddress eipalloc-new"}))
+	})
+
+	It("should hand the address over to the target of a namespace transfer", func() {
+		inst.Status.ElasticIPAllocationID = "eipalloc-old"
+		target := &computev1.Ec2Instance{}
+		Expect(handOverResources(inst, target)).To(BeTrue())
+		Expect(target.Status.ElasticIPAllocationID).To(Equal("eipalloc-old"))
+		Expect(handOverResources(inst, target)).To(BeFalse())
+	})
+
+	It("should release the allocated address when it is no longer wanted", func() {
+		Expect(r.reconcileElasticIP(context.Background(), fake, inst, running)).To(Succeed())
+		fake.calls = nil
+
+		inst.Spec.ElasticIP.Enabled = false
+		Expect(r.reconcileElasticIP(context.Background(), fake, inst, running)).To(Succeed())
+		Expect(fake.calls).To(Equal([]string{"DisassociateAddress eipassoc-1", "ReleaseAddress eipalloc-new"}))
+		Expect(inst.Status.ElasticIPAllocationID).To(BeEmpty())
+		Expect(inst.Status.PublicIP).To(BeEmpty())
+	})
+})
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// enaExpressSupported reports whether the instance type can use ENA Express.
+func enaExpressSupported(info *ec2types.InstanceTypeInfo) bool {
+	return info.NetworkInfo != nil && aws.ToBool(info.NetworkInfo.EnaSrdSupported)
+}
+
+// enaExpressStatus returns the ENA Express settings of the primary network interface of the instance.
+func enaExpressStatus(awsInstance *ec2types.Instance, supported bool) *computev1.ENAExpressStatus {
+	status := &computev1.ENAExpressStatus{SupportedByInstanceType: supported}
+	if ni := instancePrimaryInterface(awsInstance); ni != nil {
+		if spec := ni.Attachment.EnaSrdSpecification; spec != nil {
+			status.Enabled = aws.ToBool(spec.EnaSrdEnabled)
+			status.UDPEnabled = spec.EnaSrdUdpSpecification != nil && aws.ToBool(spec.EnaSrdUdpSpecification.EnaSrdUdpEnabled)
+		}
+	}
+	return status
+}
+
+// reconcileENAExpress records whether the ENA Express settings asked for are active, and warns once
+// when they are not.
+func (r *Ec2InstanceReconciler) reconcileENAExpress(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	if !ec2Instance.Spec.ENAExpressEnabled {
+		ec2Instance.Status.ENAExpress = nil
+		return nil
+	}
+
+	info, err := DescribeInstanceType(ctx, ec2Instance.Spec.Region, string(awsInstance.InstanceType))
+	if err != nil {
+		return err
+	}
+	previous := ec2Instance.Status.ENAExpress
+	current := enaExpressStatus(awsInstance, enaExpressSupported(info))
+	ec2Instance.Status.ENAExpress = current
+	if previous != nil && *previous == *current {
+		return nil
+	}
+
+	switch {
+	case !current.SupportedByInstanceType:
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "ENAExpressUnsupported",
+			"Instance type %s does not support ENA Express; the instance runs without it", awsInstance.InstanceType)
+	case !current.Enabled:
+		r.Recorder.Event(ec2Instance, corev1.EventTypeWarning, "ENAExpressInactive",
+			"ENA Express was requested but is not enabled on the primary network interface")
+	case ec2Instance.Spec.ENAExpressUDPEnabled && !current.UDPEnabled:
+		r.Recorder.Event(ec2Instance, corev1.EventTypeWarning, "ENAExpressInactive",
+			"ENA Express UDP was requested but is not enabled on the primary network interface")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("ENA Express", func() {
+	attached := func(deviceIndex int32, spec *ec2types.InstanceAttachmentEnaSrdSpecification) ec2types.InstanceNetworkInterface {
+		return ec2types.InstanceNetworkInterface{Attachment: &ec2types.InstanceNetworkInterfaceAttachment{
+			DeviceIndex:         aws.Int32(deviceIndex),
+			EnaSrdSpecification: spec,
+		}}
+	}
+
+	It("should read the settings of the primary network interface", func() {
+		instance := &ec2types.Instance{NetworkInterfaces: []ec2types.InstanceNetworkInterface{
+			attached(1, nil),
+			attached(0, &ec2types.InstanceAttachmentEnaSrdSpecification{
+				EnaSrdEnabled:          aws.Bool(true),
+				EnaSrdUdpSpecification: &ec2types.InstanceAttachmentEnaSrdUdpSpecification{EnaSrdUdpEnabled: aws.Bool(true)},
+			}),
+		}}
+		Expect(enaExpressStatus(instance, true)).To(Equal(&computev1.ENAExpressStatus{Enabled: true, UDPEnabled: true, SupportedByInstanceType: true}))
+	})
+
+	It("should report ENA Express as off when the interface has no settings", func() {
+		instance := &ec2types.Instance{NetworkInterfaces: []ec2types.InstanceNetworkInterface{attached(0, nil)}}
+		Expect(enaExpressStatus(instance, false)).To(Equal(&computev1.ENAExpressStatus{}))
+	})
+
+	It("should take support from the instance type network info", func() {
+		Expect(enaExpressSupported(&ec2types.InstanceTypeInfo{})).To(BeFalse())
+		Expect(enaExpressSupported(&ec2types.InstanceTypeInfo{NetworkInfo: &ec2types.NetworkInfo{EnaSrdSupported: aws.Bool(true)}})).To(BeTrue())
+	})
+})
@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// Values of status.healthCheckStatus.
+const (
+	healthCheckHealthy   = "Healthy"
+	healthCheckUnhealthy = "Unhealthy"
+)
+
+// healthyCheckerShare is the share of Route53 health checkers that must report success for an
+// endpoint to count as healthy. Route53 itself uses the same 18% rule.
+const healthyCheckerShare = 0.18
+
+// healthCheckConfig returns the Route53 health check configuration for the instance at ip.
+func healthCheckConfig(spec *computev1.Route53HealthCheckSpec, ip string) *route53types.HealthCheckConfig {
+	config := &route53types.HealthCheckConfig{
+		Type:             route53types.HealthCheckType(spec.Protocol),
+		IPAddress:        aws.String(ip),
+		Port:             aws.Int32(spec.Port),
+		FailureThreshold: aws.Int32(spec.FailureThreshold),
+		RequestInterval:  aws.Int32(spec.RequestInterval),
+	}
+	if spec.Protocol != string(route53types.HealthCheckTypeTcp) && spec.Path != "" {
+		config.ResourcePath = aws.String(spec.Path)
+	}
+	return config
+}
+
+// healthCheckReplaced reports whether the health check has to be recreated because a setting that
+// Route53 cannot update changed.
+func healthCheckReplaced(desired, current *route53types.HealthCheckConfig) bool {
+	return desired.Type != current.Type || aws.ToInt32(desired.RequestInterval) != aws.ToInt32(current.RequestInterval)
+}
+
+// healthCheckOutdated reports whether an update is needed to bring the health check in line.
+func healthCheckOutdated(desired, current *route53types.HealthCheckConfig) bool {
+	return aws.ToString(desired.IPAddress) != aws.ToString(current.IPAddress) ||
+		aws.ToInt32(desired.Port) != aws.ToInt32(current.Port) ||
+		aws.ToString(desired.ResourcePath) != aws.ToString(current.ResourcePath) ||
+		aws.ToInt32(desired.FailureThreshold) != aws.ToInt32(current.FailureThreshold)
+}
+
+// healthFromObservations returns the health reported by the Route53 checkers and when they last
+// checked. It returns "" when no checker has reported yet.
+func healthFromObservations(observations []route53types.HealthCheckObservation) (string, time.Time) {
+	var reported, healthy int
+	var lastChecked time.Time
+	for _, observation := range observations {
+		report := observation.StatusReport
+		if report == nil || report.Status == nil {
+			continue
+		}
+		reported++
+		if strings.HasPrefix(aws.ToString(report.Status), "Success") {
+			healthy++
+		}
+		if checked := aws.ToTime(report.CheckedTime); checked.After(lastChecked) {
+			lastChecked = checked
+		}
+	}
+	if reported == 0 {
+		return "", lastChecked
+	}
+	if float64(healthy)/float64(reported) > healthyCheckerShare {
+		return healthCheckHealthy, lastChecked
+	}
+	return healthCheckUnhealthy, lastChecked
+}
+
+// reconcileHealthCheck creates, updates or deletes the Route53 health check of the instance so it
+// matches spec.route53HealthCheck, and records what the checkers see. The health check ID is set in
+// status as soon as it exists, even when a later step fails.
+func reconcileHealthCheck(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	spec := ec2Instance.Spec.Route53HealthCheck
+	if spec == nil {
+		return deleteHealthCheck(ctx, ec2Instance)
+	}
+	// Without a public IP there is nothing to check; stopped instances lose theirs.
+	ip := aws.ToString(awsInstance.PublicIpAddress)
+	if ip == "" {
+		return nil
+	}
+
+	l := log.FromContext(ctx)
+	client := route53Client()
+	desired := healthCheckConfig(spec, ip)
+
+	if ec2Instance.Status.HealthCheckID != "" {
+		current, err := client.GetHealthCheck(ctx, &route53.GetHealthCheckInput{HealthCheckId: aws.String(ec2Instance.Status.HealthCheckID)})
+		var notFound *route53types.NoSuchHealthCheck
+		switch {
+		case errors.As(err, &notFound):
+			l.Info("Health check is gone, creating a new one", "healthCheckID", ec2Instance.Status.HealthCheckID)
+			clearHealthCheckStatus(&ec2Instance.Status)
+		case err != nil:
+			return fmt.Errorf("failed to get health check %s: %w", ec2Instance.Status.HealthCheckID, err)
+		case healthCheckReplaced(desired, current.HealthCheck.HealthCheckConfig):
+			if err := deleteHealthCheck(ctx, ec2Instance); err != nil {
+				return err
+			}
+		case healthCheckOutdated(desired, current.HealthCheck.HealthCheckConfig):
+			input := &route53.UpdateHealthCheckInput{
+				HealthCheckId:      current.HealthCheck.Id,
+				HealthCheckVersion: current.HealthCheck.HealthCheckVersion,
+				IPAddress:          desired.IPAddress,
+				Port:               desired.Port,
+				FailureThreshold:   desired.FailureThreshold,
+				ResourcePath:       desired.ResourcePath,
+			}
+			if desired.ResourcePath == nil && current.HealthCheck.HealthCheckConfig.ResourcePath != nil {
+				input.ResetElements = []route53types.ResettableElementName{route53types.ResettableElementNameResourcePath}
+			}
+			if _, err := client.UpdateHealthCheck(ctx, input); err != nil {
+				return fmt.Errorf("failed to update health check %s: %w", ec2Instance.Status.HealthCheckID, err)
+			}
+			l.Info("Updated health check", "healthCheckID", ec2Instance.Status.HealthCheckID, "ip", ip)
+		}
+	}
+
+	if ec2Instance.Status.HealthCheckID == "" {
+		created, err := client.CreateHealthCheck(ctx, &route53.CreateHealthCheckInput{
+			// Caller references can never be reused, not even after the health check is deleted.
+			CallerReference:   aws.String(fmt.Sprintf("%s-%d", ec2Instance.UID, time.Now().UnixNano())),
+			HealthCheckConfig: desired,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create health check: %w", err)
+		}
+		ec2Instance.Status.HealthCheckID = aws.ToString(created.HealthCheck.Id)
+		l.Info("Created health check", "healthCheckID", ec2Instance.Status.HealthCheckID, "ip", ip)
+
+		_, err = client.ChangeTagsForResource(ctx, &route53.ChangeTagsForResourceInput{
+			ResourceType: route53types.TagResourceTypeHealthcheck,
+			ResourceId:   created.HealthCheck.Id,
+			AddTags: []route53types.Tag{
+				{Key: aws.String("Name"), Value: aws.String(fmt.Sprintf("%s/%s", ec2Instance.Namespace, ec2Instance.Name))},
+				{Key: aws.String(costAllocationTagKey), Value: aws.String(ec2Instance.Status.InstanceID)},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to tag health check %s: %w", ec2Instance.Status.HealthCheckID, err)
+		}
+	}
+
+	status, err := client.GetHealthCheckStatus(ctx, &route53.GetHealthCheckStatusInput{HealthCheckId: aws.String(ec2Instance.Status.HealthCheckID)})
+	if err != nil {
+		return fmt.Errorf("failed to get status of health check %s: %w", ec2Instance.Status.HealthCheckID, err)
+	}
+	health, lastChecked := healthFromObservations(status.HealthCheckObservations)
+	ec2Instance.Status.HealthCheckStatus = health
+	if !lastChecked.IsZero() {
+		// Keep second precision so an unchanged report does not look like a status change.
+		checked := metav1.NewTime(lastChecked.Truncate(time.Second))
+		ec2Instance.Status.HealthCheckLastChecked = &checked
+	}
+	return nil
+}
+
+// deleteHealthCheck removes the Route53 health check of the instance, if there is one.
+func deleteHealthCheck(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	if ec2Instance.Status.HealthCheckID == "" {
+		return nil
+	}
+	_, err := route53Client().DeleteHealthCheck(ctx, &route53.DeleteHealthCheckInput{HealthCheckId: aws.String(ec2Instance.Status.HealthCheckID)})
+	var notFound *route53types.NoSuchHealthCheck
+	if err != nil && !errors.As(err, &notFound) {
+		return fmt.Errorf("failed to delete health check %s: %w", ec2Instance.Status.HealthCheckID, err)
+	}
+	log.FromContext(ctx).Info("Deleted health check", "healthCheckID", ec2Instance.Status.HealthCheckID)
+	clearHealthCheckStatus(&ec2Instance.Status)
+	return nil
+}
+
+// clearHealthCheckStatus forgets the health check and what it reported.
+func clearHealthCheckStatus(status *computev1.Ec2InstanceStatus) {
+	status.HealthCheckID = ""
+	status.HealthCheckStatus = ""
+	status.HealthCheckLastChecked = nil
+}
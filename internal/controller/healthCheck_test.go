@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Route53 health check", func() {
+	spec := &computev1.Route53HealthCheckSpec{Protocol: "HTTP", Port: 80, Path: "/healthz", FailureThreshold: 3, RequestInterval: 30}
+
+	It("should update a health check in place when the IP changes", func() {
+		current := healthCheckConfig(spec, "203.0.113.10")
+		desired := healthCheckConfig(spec, "203.0.113.20")
+		Expect(healthCheckReplaced(desired, current)).To(BeFalse())
+		Expect(healthCheckOutdated(desired, current)).To(BeTrue())
+		Expect(healthCheckOutdated(current, current)).To(BeFalse())
+	})
+
+	It("should replace a health check when the protocol changes", func() {
+		tcp := &computev1.Route53HealthCheckSpec{Protocol: "TCP", Port: 22, FailureThreshold: 3, RequestInterval: 30}
+		desired := healthCheckConfig(tcp, "203.0.113.10")
+		Expect(desired.ResourcePath).To(BeNil())
+		Expect(healthCheckReplaced(desired, healthCheckConfig(spec, "203.0.113.10"))).To(BeTrue())
+	})
+
+	It("should report healthy when more than 18% of the checkers succeed", func() {
+		checked := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		observation := func(status string, at time.Time) route53types.HealthCheckObservation {
+			return route53types.HealthCheckObservation{StatusReport: &route53types.StatusReport{Status: aws.String(status), CheckedTime: aws.Time(at)}}
+		}
+		observations := []route53types.HealthCheckObservation{
+			observation("Success: HTTP Status Code 200, OK", checked),
+			observation("Failure: Connection timed out.", checked.Add(time.Second)),
+			observation("Failure: Connection timed out.", checked),
+			observation("Failure: Connection timed out.", checked),
+		}
+		health, lastChecked := healthFromObservations(observations)
+		Expect(health).To(Equal(healthCheckHealthy))
+		Expect(lastChecked).To(Equal(checked.Add(time.Second)))
+
+		health, _ = healthFromObservations(append(observations,
+			observation("Failure: Connection timed out.", checked), observation("Failure: Connection timed out.", checked)))
+		Expect(health).To(Equal(healthCheckUnhealthy))
+
+		health, _ = healthFromObservations(nil)
+		Expect(health).To(BeEmpty())
+	})
+})
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// setHealthCondition sets one of the Ready, Provisioning and Degraded conditions.
+func setHealthCondition(ec2Instance *computev1.Ec2Instance, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&ec2Instance.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: ec2Instance.Generation,
+	})
+}
+
+// setLaunchedConditions marks an instance RunInstances just accepted as provisioning.
+func setLaunchedConditions(ec2Instance *computev1.Ec2Instance) {
+	message := fmt.Sprintf("Instance %s was launched and is starting", ec2Instance.Status.InstanceID)
+	setHealthCondition(ec2Instance, computev1.ConditionProvisioning, metav1.ConditionTrue, "InstanceLaunched", message)
+	setHealthCondition(ec2Instance, computev1.ConditionReady, metav1.ConditionFalse, "InstanceLaunched", message)
+}
+
+// setSyncedConditions sets the Ready, Provisioning and Degraded conditions from the instance AWS
+// reports. It is not used for terminated instances, see setDegradedConditions.
+func setSyncedConditions(ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) {
+	state := awsInstance.State.Name
+	hasIP := aws.ToString(awsInstance.PrivateIpAddress) != "" || aws.ToString(awsInstance.PublicIpAddress) != ""
+	setHealthCondition(ec2Instance, computev1.ConditionDegraded, metav1.ConditionFalse, "InstanceFound",
+		fmt.Sprintf("Instance %s is %s", ec2Instance.Status.InstanceID, state))
+
+	switch {
+	case state == ec2types.InstanceStateNameRunning && hasIP:
+		message := fmt.Sprintf("Instance %s is running", ec2Instance.Status.InstanceID)
+		setHealthCondition(ec2Instance, computev1.ConditionReady, metav1.ConditionTrue, "InstanceRunning", message)
+		setHealthCondition(ec2Instance, computev1.ConditionProvisioning, metav1.ConditionFalse, "InstanceRunning", message)
+	case state == ec2types.InstanceStateNameRunning || state == ec2types.InstanceStateNamePending:
+		message := fmt.Sprintf("Instance %s is %s and has no IP address yet", ec2Instance.Status.InstanceID, state)
+		setHealthCondition(ec2Instance, computev1.ConditionReady, metav1.ConditionFalse, "InstanceStarting", message)
+		setHealthCondition(ec2Instance, computev1.ConditionProvisioning, metav1.ConditionTrue, "InstanceStarting", message)
+	default:
+		message := fmt.Sprintf("Instance %s is %s", ec2Instance.Status.InstanceID, state)
+		setHealthCondition(ec2Instance, computev1.ConditionReady, metav1.ConditionFalse, "InstanceNotRunning", message)
+		setHealthCondition(ec2Instance, computev1.ConditionProvisioning, metav1.ConditionFalse, "InstanceNotRunning", message)
+	}
+}
+
+// setDegradedConditions records that AWS terminated the instance, or no longer knows it when
+// awsInstance is nil. The message carries the reason AWS gives for the termination.
+func setDegradedConditions(ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) {
+	reason := "InstanceNotFound"
+	message := fmt.Sprintf("Instance %s no longer exists in AWS", ec2Instance.Status.InstanceID)
+	if awsInstance != nil {
+		reason = "InstanceTerminated"
+		message = fmt.Sprintf("Instance %s was terminated", ec2Instance.Status.InstanceID)
+		if awsInstance.StateReason != nil && aws.ToString(awsInstance.StateReason.Message) != "" {
+			message += ": " + aws.ToString(awsInstance.StateReason.Message)
+		}
+	}
+	setHealthCondition(ec2Instance, computev1.ConditionDegraded, metav1.ConditionTrue, reason, message)
+	setHealthCondition(ec2Instance, computev1.ConditionReady, metav1.ConditionFalse, reason, message)
+	setHealthCondition(ec2Instance, computev1.ConditionProvisioning, metav1.ConditionFalse, reason, message)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Health conditions", func() {
+	var inst *computev1.Ec2Instance
+
+	BeforeEach(func() {
+		inst = &computev1.Ec2Instance{Status: computev1.Ec2InstanceStatus{InstanceID: "i-123"}}
+	})
+
+	condition := func(conditionType string) *metav1.Condition {
+		return apimeta.FindStatusCondition(inst.Status.Conditions, conditionType)
+	}
+	awsInstance := func(state ec2types.InstanceStateName, privateIP string) *ec2types.Instance {
+		instance := &ec2types.Instance{State: &ec2types.InstanceState{Name: state}}
+		if privateIP != "" {
+			instance.PrivateIpAddress = aws.String(privateIP)
+		}
+		return instance
+	}
+
+	It("should go from Provisioning to Ready once the instance runs with an IP", func() {
+		setLaunchedConditions(inst)
+		Expect(condition(computev1.ConditionProvisioning).Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition(computev1.ConditionReady).Status).To(Equal(metav1.ConditionFalse))
+
+		setSyncedConditions(inst, awsInstance(ec2types.InstanceStateNameRunning, ""))
+		Expect(condition(computev1.ConditionProvisioning).Status).To(Equal(metav1.ConditionTrue))
+
+		setSyncedConditions(inst, awsInstance(ec2types.InstanceStateNameRunning, "10.0.0.1"))
+		Expect(condition(computev1.ConditionReady).Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition(computev1.ConditionReady).Reason).To(Equal("InstanceRunning"))
+		Expect(condition(computev1.ConditionProvisioning).Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition(computev1.ConditionProvisioning).Reason).To(Equal("InstanceRunning"))
+		Expect(condition(computev1.ConditionDegraded).Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should not be Ready while the instance is stopped", func() {
+		setSyncedConditions(inst, awsInstance(ec2types.InstanceStateNameStopped, "10.0.0.1"))
+		Expect(condition(computev1.ConditionReady).Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition(computev1.ConditionProvisioning).Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should carry the AWS reason when the instance was terminated", func() {
+		terminated := awsInstance(ec2types.InstanceStateNameTerminated, "")
+		terminated.StateReason = &ec2types.StateReason{Message: aws.String("Client.UserInitiatedShutdown: User initiated shutdown")}
+		setDegradedConditions(inst, terminated)
+		Expect(condition(computev1.ConditionDegraded).Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition(computev1.ConditionDegraded).Message).To(ContainSubstring("User initiated shutdown"))
+		Expect(condition(computev1.ConditionReady).Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should report an instance AWS no longer knows", func() {
+		setDegradedConditions(inst, nil)
+		Expect(condition(computev1.ConditionDegraded).Reason).To(Equal("InstanceNotFound"))
+	})
+})
@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"sigs.k8s.io/yaml"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// imageBuilderRuntimePhases are the component phases that make sense on a running instance. The
+// "test" phase only applies to images built by an Image Builder pipeline.
+var imageBuilderRuntimePhases = []string{"build", "validate"}
+
+// imageBuilderParameterRef matches a {{ parameter }} reference in a component document.
+var imageBuilderParameterRef = regexp.MustCompile(`{{\s*([A-Za-z0-9_.-]+)\s*}}`)
+
+// imageBuilderComponent is the part of an Image Builder component that is needed to render it.
+type imageBuilderComponent struct {
+	Name     string
+	Version  string
+	Platform string
+	Data     string
+}
+
+// imageBuilderDocument is an AWSTOE component document.
+type imageBuilderDocument struct {
+	Parameters []map[string]struct {
+		Default *string `json:"default"`
+	} `json:"parameters"`
+	Phases []struct {
+		Name  string `json:"name"`
+		Steps []struct {
+			Name   string `json:"name"`
+			Action string `json:"action"`
+			Inputs any    `json:"inputs"`
+		} `json:"steps"`
+	} `json:"phases"`
+}
+
+// imageBuilderComponentCache holds component definitions keyed by build version ARN. A build version
+// ARN names one immutable version of a component, so entries never go stale.
+var imageBuilderComponentCache struct {
+	sync.Mutex
+	components map[string]*imageBuilderComponent
+}
+
+// getImageBuilderComponent returns the definition of the component build version arn, fetched with
+// credentials or, when nil, the operator's own.
+func getImageBuilderComponent(ctx context.Context, credentials aws.CredentialsProvider, arn string) (*imageBuilderComponent, error) {
+	imageBuilderComponentCache.Lock()
+	defer imageBuilderComponentCache.Unlock()
+	if component, ok := imageBuilderComponentCache.components[arn]; ok {
+		return component, nil
+	}
+
+	// arn:aws:imagebuilder:<region>:<account>:component/<name>/<version>/<build>
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 {
+		return nil, fmt.Errorf("invalid Image Builder component ARN %q", arn)
+	}
+	region := parts[3]
+	imagebuilder := awsJSONService{
+		Endpoint:    fmt.Sprintf("https://imagebuilder.%s.amazonaws.com", region),
+		SigningName: "imagebuilder",
+		Region:      region,
+		Credentials: credentials,
+	}
+
+	out := struct {
+		Component imageBuilderComponent `json:"component"`
+	}{}
+	if err := imagebuilder.get(ctx, "GetComponent", url.Values{"componentBuildVersionArn": {arn}}, &out); err != nil {
+		return nil, fmt.Errorf("failed to get Image Builder component %s: %w", arn, err)
+	}
+
+	if imageBuilderComponentCache.components == nil {
+		imageBuilderComponentCache.components = map[string]*imageBuilderComponent{}
+	}
+	imageBuilderComponentCache.components[arn] = &out.Component
+	return &out.Component, nil
+}
+
+// imageBuilderUserData returns the base64 encoded user data that runs spec.imageBuilderComponents.
+// The components are fetched and rendered into a single shell script.
+func imageBuilderUserData(ctx context.Context, ec2Instance *computev1.Ec2Instance) (string, error) {
+	var script strings.Builder
+	script.WriteString("#!/bin/bash\n# Rendered from Image Builder components by ec2-operator.\nset -euo pipefail\n")
+	for _, ref := range ec2Instance.Spec.ImageBuilderComponents {
+		component, err := getImageBuilderComponent(ctx, instanceCredentials(ec2Instance), ref.ComponentARN)
+		if err != nil {
+			return "", err
+		}
+		rendered, err := renderImageBuilderComponent(component, ref.Parameters)
+		if err != nil {
+			return "", fmt.Errorf("failed to render Image Builder component %s: %w", ref.ComponentARN, err)
+		}
+		fmt.Fprintf(&script, "\n# Component %s %s (%s)\n%s", component.Name, component.Version, ref.ComponentARN, rendered)
+	}
+	return base64.StdEncoding.EncodeToString([]byte(script.String())), nil
+}
+
+// renderImageBuilderComponent turns the runtime phases of a component into shell commands. Only the
+// ExecuteBash and CreateFile actions have a direct shell equivalent; any other action is an error so
+// that a component is never half applied.
+func renderImageBuilderComponent(component *imageBuilderComponent, parameters map[string]string) (string, error) {
+	if component.Platform != "" && component.Platform != "Linux" {
+		return "", fmt.Errorf("platform %s is not supported, only Linux components can be rendered", component.Platform)
+	}
+
+	doc := imageBuilderDocument{}
+	if err := yaml.Unmarshal([]byte(component.Data), &doc); err != nil {
+		return "", fmt.Errorf("failed to parse component document: %w", err)
+	}
+
+	values := map[string]string{}
+	for _, declared := range doc.Parameters {
+		for name, param := range declared {
+			if param.Default != nil {
+				values[name] = *param.Default
+			}
+		}
+	}
+	for name, value := range parameters {
+		if !imageBuilderParameterDeclared(doc, name) {
+			return "", fmt.Errorf("parameter %q is not declared by the component", name)
+		}
+		values[name] = value
+	}
+
+	var missing []string
+	substitute := func(s string) string {
+		return imageBuilderParameterRef.ReplaceAllStringFunc(s, func(ref string) string {
+			name := imageBuilderParameterRef.FindStringSubmatch(ref)[1]
+			if value, ok := values[name]; ok {
+				return value
+			}
+			missing = append(missing, name)
+			return ref
+		})
+	}
+
+	var out strings.Builder
+	for _, phaseName := range imageBuilderRuntimePhases {
+		for _, phase := range doc.Phases {
+			if phase.Name != phaseName {
+				continue
+			}
+			for _, step := range phase.Steps {
+				fmt.Fprintf(&out, "# %s: %s\n", phase.Name, step.Name)
+				switch step.Action {
+				case "ExecuteBash":
+					inputs, _ := step.Inputs.(map[string]any)
+					commands, _ := inputs["commands"].([]any)
+					for _, command := range commands {
+						fmt.Fprintf(&out, "%s\n", substitute(fmt.Sprint(command)))
+					}
+				case "CreateFile":
+					files, _ := step.Inputs.([]any)
+					for _, f := range files {
+						file, _ := f.(map[string]any)
+						path := substitute(fmt.Sprint(file["path"]))
+						content := substitute(fmt.Sprint(file["content"]))
+						fmt.Fprintf(&out, "mkdir -p \"$(dirname '%s')\"\ncat > '%s' <<'EC2_OPERATOR_EOF'\n%s\nEC2_OPERATOR_EOF\n", path, path, content)
+					}
+				default:
+					return "", fmt.Errorf("step %s/%s uses action %s, which cannot run as user data", phase.Name, step.Name, step.Action)
+				}
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("no value for parameters %s", strings.Join(missing, ", "))
+	}
+	return out.String(), nil
+}
+
+// imageBuilderParameterDeclared reports whether the document declares a parameter called name.
+func imageBuilderParameterDeclared(doc imageBuilderDocument, name string) bool {
+	for _, declared := range doc.Parameters {
+		if _, ok := declared[name]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Image Builder components", func() {
+	const document = `name: hello
+schemaVersion: 1.0
+parameters:
+  - Greeting:
+      type: string
+      default: hello
+  - Target:
+      type: string
+phases:
+  - name: build
+    steps:
+      - name: Greet
+        action: ExecuteBash
+        inputs:
+          commands:
+            - echo "{{ Greeting }} {{Target}}"
+      - name: Config
+        action: CreateFile
+        inputs:
+          - path: /etc/hello.conf
+            content: target={{ Target }}
+  - name: test
+    steps:
+      - name: Reboot
+        action: Reboot
+`
+
+	It("should render the build phase with parameters substituted", func() {
+		out, err := renderImageBuilderComponent(&imageBuilderComponent{Platform: "Linux", Data: document},
+			map[string]string{"Target": "world"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring(`echo "hello world"`))
+		Expect(out).To(ContainSubstring("cat > '/etc/hello.conf'"))
+		Expect(out).To(ContainSubstring("target=world"))
+		Expect(out).NotTo(ContainSubstring("Reboot"))
+	})
+
+	It("should fail when a parameter has no value", func() {
+		_, err := renderImageBuilderComponent(&imageBuilderComponent{Data: document}, nil)
+		Expect(err).To(MatchError(ContainSubstring("Target")))
+	})
+
+	It("should reject parameters the component does not declare", func() {
+		_, err := renderImageBuilderComponent(&imageBuilderComponent{Data: document}, map[string]string{"Other": "x"})
+		Expect(err).To(MatchError(ContainSubstring(`"Other"`)))
+	})
+})
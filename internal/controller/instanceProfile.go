@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// iamInstanceProfileSpecification refers to an instance profile by ARN or by name, whichever
+// spec.iamInstanceProfile holds.
+func iamInstanceProfileSpecification(profile string) *ec2types.IamInstanceProfileSpecification {
+	if strings.HasPrefix(profile, "arn:") {
+		return &ec2types.IamInstanceProfileSpecification{Arn: aws.String(profile)}
+	}
+	return &ec2types.IamInstanceProfileSpecification{Name: aws.String(profile)}
+}
+
+// instanceProfileMatches reports whether the instance profile with the given ARN is profile, which
+// is an ARN or a name. The name is the last part of an ARN such as
+// arn:aws:iam::123456789012:instance-profile/path/name.
+func instanceProfileMatches(profile, arn string) bool {
+	if strings.HasPrefix(profile, "arn:") {
+		return profile == arn
+	}
+	return arn != "" && arn[strings.LastIndex(arn, "/")+1:] == profile
+}
+
+// reconcileIAMInstanceProfile attaches spec.iamInstanceProfile to the instance, replacing the
+// profile it has without relaunching it. An empty spec leaves the profile of the instance alone.
+func (r *Ec2InstanceReconciler) reconcileIAMInstanceProfile(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	current := ""
+	if awsInstance.IamInstanceProfile != nil {
+		current = aws.ToString(awsInstance.IamInstanceProfile.Arn)
+	}
+	ec2Instance.Status.IAMInstanceProfileARN = current
+
+	desired := ec2Instance.Spec.IAMInstanceProfile
+	if desired == "" || instanceProfileMatches(desired, current) {
+		return nil
+	}
+
+	ec2Client := instanceAWSClient(ec2Instance)
+	associations, err := ec2Client.DescribeIamInstanceProfileAssociations(ctx, &ec2.DescribeIamInstanceProfileAssociationsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("instance-id"), Values: []string{ec2Instance.Status.InstanceID}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe instance profile associations: %w", err)
+	}
+	var association *ec2types.IamInstanceProfileAssociation
+	for i, a := range associations.IamInstanceProfileAssociations {
+		switch a.State {
+		case ec2types.IamInstanceProfileAssociationStateAssociated:
+			association = &associations.IamInstanceProfileAssociations[i]
+		case ec2types.IamInstanceProfileAssociationStateAssociating, ec2types.IamInstanceProfileAssociationStateDisassociating:
+			// An earlier change is still being applied; look again on the next sync.
+			return nil
+		}
+	}
+
+	var result *ec2types.IamInstanceProfileAssociation
+	if association == nil {
+		output, err := ec2Client.AssociateIamInstanceProfile(ctx, &ec2.AssociateIamInstanceProfileInput{
+			InstanceId:         aws.String(ec2Instance.Status.InstanceID),
+			IamInstanceProfile: iamInstanceProfileSpecification(desired),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to associate instance profile %s: %w", desired, err)
+		}
+		result = output.IamInstanceProfileAssociation
+	} else {
+		output, err := ec2Client.ReplaceIamInstanceProfileAssociation(ctx, &ec2.ReplaceIamInstanceProfileAssociationInput{
+			AssociationId:      association.AssociationId,
+			IamInstanceProfile: iamInstanceProfileSpecification(desired),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to replace instance profile with %s: %w", desired, err)
+		}
+		result = output.IamInstanceProfileAssociation
+	}
+	if result != nil && result.IamInstanceProfile != nil {
+		ec2Instance.Status.IAMInstanceProfileARN = aws.ToString(result.IamInstanceProfile.Arn)
+	}
+	log.FromContext(ctx).Info("Updated IAM instance profile", "instanceID", ec2Instance.Status.InstanceID, "from", current, "to", desired)
+	r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "InstanceProfileUpdated", "Instance profile changed from %q to %q", current, desired)
+	return nil
+}
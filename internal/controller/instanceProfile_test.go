@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IAM instance profile", func() {
+	const arn = "arn:aws:iam::123456789012:instance-profile/apps/web"
+
+	It("should refer to the profile by ARN or by name", func() {
+		Expect(aws.ToString(iamInstanceProfileSpecification(arn).Arn)).To(Equal(arn))
+		byName := iamInstanceProfileSpecification("web")
+		Expect(aws.ToString(byName.Name)).To(Equal("web"))
+		Expect(byName.Arn).To(BeNil())
+	})
+
+	It("should match the associated profile by ARN or by name", func() {
+		Expect(instanceProfileMatches(arn, arn)).To(BeTrue())
+		Expect(instanceProfileMatches("web", arn)).To(BeTrue())
+		Expect(instanceProfileMatches("apps", arn)).To(BeFalse())
+		Expect(instanceProfileMatches("web", "")).To(BeFalse())
+		Expect(instanceProfileMatches("arn:aws:iam::123456789012:instance-profile/web", arn)).To(BeFalse())
+	})
+})
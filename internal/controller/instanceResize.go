@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	"github.com/bshaw7/operator-repo/internal/statemachine"
+)
+
+// reconcilePhaseFieldManager owns status.reconcilePhase, which is written with server-side apply.
+const reconcilePhaseFieldManager = "ec2instance-reconcile-phase"
+
+// instanceResizeAPI is the part of the EC2 API a stop-start resize uses.
+type instanceResizeAPI interface {
+	StopInstances(ctx context.Context, params *ec2.StopInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error)
+	ModifyInstanceAttribute(ctx context.Context, params *ec2.ModifyInstanceAttributeInput, optFns ...func(*ec2.Options)) (*ec2.ModifyInstanceAttributeOutput, error)
+	StartInstances(ctx context.Context, params *ec2.StartInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error)
+}
+
+// setReconcilePhase records the phase in status on its own, together with status.pendingInstanceType,
+// so both are persisted before the step the phase names is taken and are not lost when a later
+// status update fails. The phase is always sent, also when empty, so clearing it does not depend on
+// who else has written the field. The pending type is left out when empty, which removes it.
+func (r *Ec2InstanceReconciler) setReconcilePhase(ctx context.Context, ec2Instance *computev1.Ec2Instance, phase string) error {
+	patch := &unstructured.Unstructured{}
+	patch.SetGroupVersionKind(computev1.GroupVersion.WithKind("Ec2Instance"))
+	patch.SetNamespace(ec2Instance.Namespace)
+	patch.SetName(ec2Instance.Name)
+	if err := unstructured.SetNestedField(patch.Object, phase, "status", "reconcilePhase"); err != nil {
+		return err
+	}
+	if pending := ec2Instance.Status.PendingInstanceType; pending != "" {
+		if err := unstructured.SetNestedField(patch.Object, pending, "status", "pendingInstanceType"); err != nil {
+			return err
+		}
+	}
+	if err := r.Status().Patch(ctx, patch, client.Apply, client.FieldOwner(reconcilePhaseFieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to record reconcile phase %q: %w", phase, err)
+	}
+	log.FromContext(ctx).Info("Reconcile phase changed", "from", ec2Instance.Status.ReconcilePhase, "to", phase)
+	ec2Instance.Status.ReconcilePhase = phase
+	// Keep later updates of the object from failing on a stale resource version.
+	ec2Instance.ResourceVersion = patch.GetResourceVersion()
+	return nil
+}
+
+// resizeNeeded reports whether a running instance has to be stopped and started to get the
+// instance type in spec.instanceType. Types picked by the operator itself are left alone, and so are
+// instances that are to be stopped: the resize would start them again.
+func resizeNeeded(ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) bool {
+	return ec2Instance.Spec.InstanceType != "" &&
+		desiredInstanceState(ec2Instance) == ec2types.InstanceStateNameRunning &&
+		ec2Instance.Spec.InstanceTypeOptimization != computev1.InstanceTypeOptimizationCostAware &&
+		string(awsInstance.InstanceType) != ec2Instance.Spec.InstanceType &&
+		awsInstance.State != nil && awsInstance.State.Name == ec2types.InstanceStateNameRunning
+}
+
+// reconcileResize moves the instance through a stop-start resize, one phase at a time. Each phase is
+// recorded before its AWS call and every step checks AWS first, so an operator restarted at any
+// point picks up at the recorded phase without stopping or modifying the instance twice. The target
+// type is checkpointed in status.pendingInstanceType. It returns true while the resize is in progress.
+func (r *Ec2InstanceReconciler) reconcileResize(ctx context.Context, ec2Client instanceResizeAPI, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) (bool, error) {
+	l := log.FromContext(ctx)
+	instanceID := aws.String(ec2Instance.Status.InstanceID)
+	state := ec2types.InstanceStateNameRunning
+	if awsInstance.State != nil {
+		state = awsInstance.State.Name
+	}
+
+	phase := ec2Instance.Status.ReconcilePhase
+	if phase == "" {
+		if !resizeNeeded(ec2Instance, awsInstance) || !r.transitionAllowed(ctx, ec2Instance, "stop", statemachine.Stopped) {
+			return false, nil
+		}
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "Resizing",
+			"Stopping instance to change its type from %s to %s", awsInstance.InstanceType, ec2Instance.Spec.InstanceType)
+		ec2Instance.Status.PendingInstanceType = ec2Instance.Spec.InstanceType
+		phase = computev1.ReconcilePhaseStopping
+		if err := r.setReconcilePhase(ctx, ec2Instance, phase); err != nil {
+			return true, err
+		}
+	}
+
+	// A resize recorded before the pending type was checkpointed goes to spec.instanceType.
+	instanceType := ec2Instance.Status.PendingInstanceType
+	if instanceType == "" {
+		instanceType = ec2Instance.Spec.InstanceType
+	}
+
+	switch phase {
+	case computev1.ReconcilePhaseStopping:
+		if state != ec2types.InstanceStateNameStopped {
+			if state == ec2types.InstanceStateNameRunning || state == ec2types.InstanceStateNamePending {
+				if _, err := ec2Client.StopInstances(ctx, &ec2.StopInstancesInput{InstanceIds: []string{*instanceID}}); err != nil {
+					return true, fmt.Errorf("failed to stop instance for resize: %w", err)
+				}
+				l.Info("Stopping instance for resize", "instanceID", *instanceID)
+			}
+			return true, nil
+		}
+		if err := r.setReconcilePhase(ctx, ec2Instance, computev1.ReconcilePhaseStopped); err != nil {
+			return true, err
+		}
+		fallthrough
+
+	case computev1.ReconcilePhaseStopped:
+		if err := r.setReconcilePhase(ctx, ec2Instance, computev1.ReconcilePhaseModifyingType); err != nil {
+			return true, err
+		}
+		fallthrough
+
+	case computev1.ReconcilePhaseModifyingType:
+		// The type may already have been changed by a run that did not get to record the next phase.
+		if string(awsInstance.InstanceType) != instanceType {
+			_, err := ec2Client.ModifyInstanceAttribute(ctx, &ec2.ModifyInstanceAttributeInput{
+				InstanceId:   instanceID,
+				InstanceType: &ec2types.AttributeValue{Value: aws.String(instanceType)},
+			})
+			if err != nil {
+				r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "ResizeFailed", "Failed to change instance type to %s: %v", instanceType, err)
+				return true, fmt.Errorf("failed to change instance type to %s: %w", instanceType, err)
+			}
+			l.Info("Changed instance type", "instanceID", *instanceID, "instanceType", instanceType)
+		}
+		if err := r.setReconcilePhase(ctx, ec2Instance, computev1.ReconcilePhaseStarting); err != nil {
+			return true, err
+		}
+		// awsInstance still shows the instance as stopped, so start it right away.
+		fallthrough
+
+	case computev1.ReconcilePhaseStarting:
+		if state != ec2types.InstanceStateNameRunning {
+			if state == ec2types.InstanceStateNameStopped {
+				if !r.transitionAllowed(ctx, ec2Instance, "start", statemachine.Running) {
+					return true, nil
+				}
+				if _, err := ec2Client.StartInstances(ctx, &ec2.StartInstancesInput{InstanceIds: []string{*instanceID}}); err != nil {
+					return true, fmt.Errorf("failed to start instance after resize: %w", err)
+				}
+				l.Info("Starting instance after resize", "instanceID", *instanceID)
+			}
+			return true, nil
+		}
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "Resized", "Instance type changed to %s", awsInstance.InstanceType)
+		// A spec.instanceType changed during the resize gets a resize of its own on the next sync.
+		ec2Instance.Status.PendingInstanceType = ""
+		return false, r.setReconcilePhase(ctx, ec2Instance, "")
+	}
+
+	// Not a resize phase, so there is nothing to resume.
+	return false, nil
+}
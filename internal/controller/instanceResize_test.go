@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// fakeResizeAPI plays an instance that stops and starts instantly and records the calls it gets.
+type fakeResizeAPI struct {
+	instance  ec2types.Instance
+	calls     []string
+	modifyErr error
+}
+
+func (f *fakeResizeAPI) StopInstances(context.Context, *ec2.StopInstancesInput, ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error) {
+	f.calls = append(f.calls, "StopInstances")
+	f.instance.State = &ec2types.InstanceState{Name: ec2types.InstanceStateNameStopped}
+	return &ec2.StopInstancesOutput{}, nil
+}
+
+func (f *fakeResizeAPI) ModifyInstanceAttribute(_ context.Context, in *ec2.ModifyInstanceAttributeInput, _ ...func(*ec2.Options)) (*ec2.ModifyInstanceAttributeOutput, error) {
+	f.calls = append(f.calls, "ModifyInstanceAttribute")
+	if err := f.modifyErr; err != nil {
+		f.modifyErr = nil
+		return nil, err
+	}
+	f.instance.InstanceType = ec2types.InstanceType(aws.ToString(in.InstanceType.Value))
+	return &ec2.ModifyInstanceAttributeOutput{}, nil
+}
+
+func (f *fakeResizeAPI) StartInstances(context.Context, *ec2.StartInstancesInput, ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error) {
+	f.calls = append(f.calls, "StartInstances")
+	f.instance.State = &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning}
+	return &ec2.StartInstancesOutput{}, nil
+}
+
+var _ = Describe("Instance resize", func() {
+	const resourceName = "resize-test"
+	key := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var fake *fakeResizeAPI
+
+	BeforeEach(func() {
+		fake = &fakeResizeAPI{instance: ec2types.Instance{
+			InstanceId:   aws.String("i-0123"),
+			InstanceType: ec2types.InstanceTypeT3Micro,
+			State:        &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning},
+		}}
+		ec2Instance := &computev1.Ec2Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec:       computev1.Ec2InstanceSpec{InstanceType: "t3.large", AMIId: "ami-123", Region: "us-east-1"},
+		}
+		Expect(k8sClient.Create(ctx, ec2Instance)).To(Succeed())
+		ec2Instance.Status = computev1.Ec2InstanceStatus{InstanceID: "i-0123", State: "running"}
+		Expect(k8sClient.Status().Update(ctx, ec2Instance)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, &computev1.Ec2Instance{ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"}})).To(Succeed())
+	})
+
+	// reconcileAfterRestart runs one resize step the way a freshly started operator would: with a new
+	// reconciler and nothing but what is stored in the API server.
+	reconcileAfterRestart := func() (string, bool, error) {
+		ec2Instance := &computev1.Ec2Instance{}
+		Expect(k8sClient.Get(ctx, key, ec2Instance)).To(Succeed())
+		r := &Ec2InstanceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: record.NewFakeRecorder(10)}
+		awsInstance := fake.instance
+		resizing, err := r.reconcileResize(ctx, fake, ec2Instance, &awsInstance)
+
+		stored := &computev1.Ec2Instance{}
+		Expect(k8sClient.Get(ctx, key, stored)).To(Succeed())
+		return stored.Status.ReconcilePhase, resizing, err
+	}
+
+	It("should persist each phase and finish after a restart between every step", func() {
+		phase, resizing, err := reconcileAfterRestart()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resizing).To(BeTrue())
+		Expect(phase).To(Equal(computev1.ReconcilePhaseStopping))
+
+		phase, resizing, err = reconcileAfterRestart()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resizing).To(BeTrue())
+		Expect(phase).To(Equal(computev1.ReconcilePhaseStarting))
+
+		phase, resizing, err = reconcileAfterRestart()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resizing).To(BeFalse())
+		Expect(phase).To(BeEmpty())
+
+		Expect(fake.calls).To(Equal([]string{"StopInstances", "ModifyInstanceAttribute", "StartInstances"}))
+		Expect(fake.instance.InstanceType).To(Equal(ec2types.InstanceTypeT3Large))
+	})
+
+	It("should resume at the recorded phase instead of stopping the instance again", func() {
+		fake.modifyErr = errors.New("InsufficientInstanceCapacity")
+
+		_, _, err := reconcileAfterRestart()
+		Expect(err).NotTo(HaveOccurred())
+		phase, resizing, err := reconcileAfterRestart()
+		Expect(err).To(HaveOccurred())
+		Expect(resizing).To(BeTrue())
+		Expect(phase).To(Equal(computev1.ReconcilePhaseModifyingType))
+
+		phase, _, err = reconcileAfterRestart()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(computev1.ReconcilePhaseStarting))
+		phase, _, err = reconcileAfterRestart()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(BeEmpty())
+
+		Expect(fake.calls).To(Equal([]string{"StopInstances", "ModifyInstanceAttribute", "ModifyInstanceAttribute", "StartInstances"}))
+	})
+
+	It("should finish with the type it started toward when spec.instanceType changes during the resize", func() {
+		_, _, err := reconcileAfterRestart()
+		Expect(err).NotTo(HaveOccurred())
+		stored := &computev1.Ec2Instance{}
+		Expect(k8sClient.Get(ctx, key, stored)).To(Succeed())
+		Expect(stored.Status.PendingInstanceType).To(Equal("t3.large"))
+
+		stored.Spec.InstanceType = "m5.large"
+		Expect(k8sClient.Update(ctx, stored)).To(Succeed())
+
+		phase, _, err := reconcileAfterRestart()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(computev1.ReconcilePhaseStarting))
+		Expect(fake.instance.InstanceType).To(Equal(ec2types.InstanceTypeT3Large))
+		phase, resizing, err := reconcileAfterRestart()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resizing).To(BeFalse())
+		Expect(phase).To(BeEmpty())
+		Expect(k8sClient.Get(ctx, key, stored)).To(Succeed())
+		Expect(stored.Status.PendingInstanceType).To(BeEmpty())
+
+		By("starting a resize of its own for the new type")
+		phase, resizing, err = reconcileAfterRestart()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resizing).To(BeTrue())
+		Expect(phase).To(Equal(computev1.ReconcilePhaseStopping))
+		Expect(k8sClient.Get(ctx, key, stored)).To(Succeed())
+		Expect(stored.Status.PendingInstanceType).To(Equal("m5.large"))
+	})
+
+	It("should not resize an instance that already has the requested type", func() {
+		fake.instance.InstanceType = ec2types.InstanceTypeT3Large
+		phase, resizing, err := reconcileAfterRestart()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resizing).To(BeFalse())
+		Expect(phase).To(BeEmpty())
+		Expect(fake.calls).To(BeEmpty())
+	})
+})
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// scheduleCatchUp is how far back a missed scheduled stop or start is still applied, e.g. after the
+// operator was down. It also bounds the search for the latest firing of frequent schedules.
+const scheduleCatchUp = 7 * 24 * time.Hour
+
+// parseScheduleCron parses a five field cron expression of spec.schedule in timezone, UTC when empty.
+func parseScheduleCron(expression, timezone string) (cron.Schedule, error) {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	return cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", timezone, expression))
+}
+
+// lastFiring returns the latest time schedule fired after since and no later than now.
+func lastFiring(schedule cron.Schedule, since, now time.Time) (time.Time, bool) {
+	if earliest := now.Add(-scheduleCatchUp); since.Before(earliest) {
+		since = earliest
+	}
+	var last time.Time
+	for next := schedule.Next(since); !next.IsZero() && !next.After(now); next = schedule.Next(next) {
+		last = next
+	}
+	return last, !last.IsZero()
+}
+
+// scheduledDesiredState returns the desired state spec.schedule asks for and the time it did, if a
+// stop or start fired since the last one that was applied, or since the object was created.
+func scheduledDesiredState(ec2Instance *computev1.Ec2Instance, now time.Time) (string, time.Time, bool, error) {
+	spec := ec2Instance.Spec.Schedule
+	if spec == nil {
+		return "", time.Time{}, false, nil
+	}
+	since := ec2Instance.CreationTimestamp.Time
+	if last := ec2Instance.Status.LastScheduledAction; last != nil {
+		since = last.Time
+	}
+
+	var state string
+	var at time.Time
+	for _, trigger := range []struct {
+		expression, state string
+	}{
+		// A start wins over a stop due at the same time.
+		{spec.StopCron, string(ec2types.InstanceStateNameStopped)},
+		{spec.StartCron, string(ec2types.InstanceStateNameRunning)},
+	} {
+		if trigger.expression == "" {
+			continue
+		}
+		schedule, err := parseScheduleCron(trigger.expression, spec.Timezone)
+		if err != nil {
+			return "", time.Time{}, false, err
+		}
+		if fired, ok := lastFiring(schedule, since, now); ok && !fired.Before(at) {
+			state, at = trigger.state, fired
+		}
+	}
+	return state, at, state != "", nil
+}
+
+// reconcileSchedule applies a due scheduled stop or start. It sets spec.desiredState and stores it
+// before anything acts on it, then records the trigger in status so it is not applied twice.
+func (r *Ec2InstanceReconciler) reconcileSchedule(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	if r.Scheduler != nil {
+		if err := r.Scheduler.Sync(ec2Instance); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to schedule wakeups for spec.schedule")
+		}
+	}
+
+	state, at, due, err := scheduledDesiredState(ec2Instance, time.Now())
+	if err != nil {
+		// The webhook rejects bad expressions, so this only happens when webhooks are disabled.
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "InvalidSchedule", "Cannot parse spec.schedule: %v", err)
+		return nil
+	}
+	if !due {
+		return nil
+	}
+
+	if ec2Instance.Spec.DesiredState != state {
+		log.FromContext(ctx).Info("Schedule changes the desired state", "desiredState", state, "scheduledAt", at)
+		ec2Instance.Spec.DesiredState = state
+		if err := r.Update(ctx, ec2Instance); err != nil {
+			return fmt.Errorf("failed to set scheduled desired state: %w", err)
+		}
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "Scheduled",
+			"Schedule set desiredState to %s as of %s", state, at.Format(time.RFC3339))
+	}
+	scheduledAt := metav1.NewTime(at)
+	ec2Instance.Status.LastScheduledAction = &scheduledAt
+	if err := r.Status().Update(ctx, ec2Instance); err != nil {
+		return fmt.Errorf("failed to record scheduled action: %w", err)
+	}
+	return nil
+}
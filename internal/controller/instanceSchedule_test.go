@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Instance schedule", func() {
+	// A Monday.
+	created := time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)
+	scheduled := func(schedule computev1.InstanceScheduleSpec, lastAction *time.Time) *computev1.Ec2Instance {
+		inst := &computev1.Ec2Instance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dev", CreationTimestamp: metav1.NewTime(created)},
+			Spec:       computev1.Ec2InstanceSpec{Schedule: &schedule},
+		}
+		if lastAction != nil {
+			at := metav1.NewTime(*lastAction)
+			inst.Status.LastScheduledAction = &at
+		}
+		return inst
+	}
+	workdays := computev1.InstanceScheduleSpec{StopCron: "0 19 * * 1-5", StartCron: "0 7 * * 1-5"}
+
+	It("should do nothing until the first trigger after creation", func() {
+		_, _, due, err := scheduledDesiredState(scheduled(workdays, nil), created.Add(6*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(due).To(BeFalse())
+	})
+
+	It("should apply the latest trigger since the last applied one", func() {
+		state, at, due, err := scheduledDesiredState(scheduled(workdays, nil), created.Add(8*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(due).To(BeTrue())
+		Expect(state).To(Equal("stopped"))
+		Expect(at).To(Equal(time.Date(2025, 3, 3, 19, 0, 0, 0, time.UTC)))
+
+		// The operator was down over night: the morning start supersedes the evening stop.
+		state, at, due, _ = scheduledDesiredState(scheduled(workdays, nil), time.Date(2025, 3, 4, 8, 0, 0, 0, time.UTC))
+		Expect(due).To(BeTrue())
+		Expect(state).To(Equal("running"))
+		Expect(at).To(Equal(time.Date(2025, 3, 4, 7, 0, 0, 0, time.UTC)))
+	})
+
+	It("should not apply a trigger twice", func() {
+		stop := time.Date(2025, 3, 3, 19, 0, 0, 0, time.UTC)
+		_, _, due, _ := scheduledDesiredState(scheduled(workdays, &stop), time.Date(2025, 3, 3, 23, 0, 0, 0, time.UTC))
+		Expect(due).To(BeFalse())
+	})
+
+	It("should evaluate the expressions in the time zone", func() {
+		berlin := workdays
+		berlin.Timezone = "Europe/Berlin"
+		state, at, due, err := scheduledDesiredState(scheduled(berlin, nil), time.Date(2025, 3, 3, 18, 30, 0, 0, time.UTC))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(due).To(BeTrue())
+		Expect(state).To(Equal("stopped"))
+		Expect(at.Equal(time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC))).To(BeTrue())
+	})
+
+	It("should report expressions it cannot parse", func() {
+		_, _, _, err := scheduledDesiredState(scheduled(computev1.InstanceScheduleSpec{StopCron: "at night"}, nil), created)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should keep one set of cron jobs per instance", func() {
+		scheduler := NewCronScheduler()
+		inst := scheduled(workdays, nil)
+		Expect(scheduler.Sync(inst)).To(Succeed())
+		Expect(scheduler.Sync(inst)).To(Succeed())
+		Expect(scheduler.cron.Entries()).To(HaveLen(2))
+
+		inst.Spec.Schedule = &computev1.InstanceScheduleSpec{StopCron: "0 19 * * *"}
+		Expect(scheduler.Sync(inst)).To(Succeed())
+		Expect(scheduler.cron.Entries()).To(HaveLen(1))
+
+		scheduler.Forget(types.NamespacedName{Namespace: "default", Name: "dev"})
+		Expect(scheduler.cron.Entries()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,344 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// instanceTypeCandidates is how many of the smallest matching instance types are priced.
+	instanceTypeCandidates = 5
+	// onDemandPriceCacheTTL is how long an on-demand price is reused. AWS changes prices rarely.
+	onDemandPriceCacheTTL = 24 * time.Hour
+	// reservedInstanceTerm is the one year term reserved prices are compared at, in seconds.
+	reservedInstanceTerm = 365 * 24 * 60 * 60
+)
+
+// pricing is the AWS Price List API. It is served from a few regions only and returns prices for all of them.
+var pricing = awsJSONService{
+	Endpoint:     "https://api.pricing.us-east-1.amazonaws.com",
+	SigningName:  "pricing",
+	Region:       "us-east-1",
+	TargetPrefix: "AWSPriceListService",
+}
+
+// onDemandPriceCache holds Linux on-demand hourly prices keyed by region/instance type.
+var onDemandPriceCache sync.Map
+
+type cachedPrice struct {
+	hourlyUSD float64
+	fetchedAt time.Time
+}
+
+// instanceTypeCandidate is an instance type considered by cost-aware selection.
+type instanceTypeCandidate struct {
+	InstanceType string
+	// OnDemandHourly is 0 when the price could not be determined.
+	OnDemandHourly float64
+	// ReservedHourly is the effective hourly price of the cheapest one year standard offering, 0 if none.
+	ReservedHourly float64
+	// UnusedReserved is the number of active Reserved Instances of this type not covered by running instances.
+	UnusedReserved int32
+}
+
+// selectInstanceType picks the instance type to launch for spec.instanceTypeOptimization "cost-aware"
+// and explains the choice. amiID is the image the instance will be launched from.
+func selectInstanceType(ctx context.Context, ec2Instance *computev1.Ec2Instance, amiID string) (string, string, error) {
+	l := log.FromContext(ctx)
+	region := ec2Instance.Spec.Region
+	// The AMI and Reserved Instances are those of the account the instance runs in.
+	ec2Client := instanceAWSClient(ec2Instance)
+
+	names, err := candidateInstanceTypes(ctx, ec2Client, region, amiID, ec2Instance.Spec.Resources)
+	if err != nil {
+		return "", "", err
+	}
+	if len(names) == 0 {
+		return ec2Instance.Spec.InstanceType, fmt.Sprintf("no instance type in %s has at least %d vCPUs and %d MiB, using spec.instanceType",
+			region, ec2Instance.Spec.Resources.VCPUs, ec2Instance.Spec.Resources.MemoryMiB), nil
+	}
+
+	unused, err := unusedReservedInstances(ctx, ec2Client, ec2Instance.Spec.AvailabilityZone, names)
+	if err != nil {
+		return "", "", err
+	}
+
+	candidates := make([]instanceTypeCandidate, 0, len(names))
+	for _, name := range names {
+		candidate := instanceTypeCandidate{InstanceType: name, UnusedReserved: unused[name]}
+		if candidate.OnDemandHourly, err = onDemandHourlyPrice(ctx, region, name); err != nil {
+			l.Error(err, "Failed to get on-demand price", "instanceType", name)
+		}
+		if candidate.ReservedHourly, err = reservedHourlyPrice(ctx, region, name); err != nil {
+			l.Error(err, "Failed to get Reserved Instance offerings", "instanceType", name)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if instanceType, reason, ok := chooseInstanceType(candidates); ok {
+		return instanceType, reason, nil
+	}
+	return ec2Instance.Spec.InstanceType, fmt.Sprintf("none of %s could be priced, using spec.instanceType", strings.Join(names, ", ")), nil
+}
+
+// chooseInstanceType prefers a candidate with unused Reserved Instances, which costs nothing extra,
+// then the cheapest on-demand candidate. It returns false when no candidate qualifies.
+func chooseInstanceType(candidates []instanceTypeCandidate) (string, string, bool) {
+	var reserved, onDemand *instanceTypeCandidate
+	names := make([]string, 0, len(candidates))
+	for i := range candidates {
+		c := &candidates[i]
+		names = append(names, c.InstanceType)
+		if c.UnusedReserved > 0 && (reserved == nil || c.OnDemandHourly < reserved.OnDemandHourly) {
+			reserved = c
+		}
+		if c.OnDemandHourly > 0 && (onDemand == nil || c.OnDemandHourly < onDemand.OnDemandHourly) {
+			onDemand = c
+		}
+	}
+
+	switch {
+	case reserved != nil:
+		return reserved.InstanceType, fmt.Sprintf("%s has %d unused Reserved Instances, so it costs nothing extra (compared %s)",
+			reserved.InstanceType, reserved.UnusedReserved, strings.Join(names, ", ")), true
+	case onDemand != nil:
+		reason := fmt.Sprintf("%s is the cheapest on-demand type at $%.4f/h", onDemand.InstanceType, onDemand.OnDemandHourly)
+		if onDemand.ReservedHourly > 0 {
+			reason += fmt.Sprintf(", $%.4f/h with a 1 year Reserved Instance", onDemand.ReservedHourly)
+		}
+		return onDemand.InstanceType, reason + fmt.Sprintf(" (compared %s)", strings.Join(names, ", ")), true
+	default:
+		return "", "", false
+	}
+}
+
+// candidateInstanceTypes returns the smallest current generation instance types in region that can
+// run the AMI and have at least the requested vCPUs and memory.
+func candidateInstanceTypes(ctx context.Context, ec2Client *ec2.Client, region, amiID string, resources *computev1.InstanceResources) ([]string, error) {
+	image, err := describeImage(ctx, ec2Client, region, amiID)
+	if err != nil {
+		return nil, err
+	}
+	if image == nil {
+		return nil, fmt.Errorf("AMI %s not found in %s", amiID, region)
+	}
+
+	var names []string
+	paginator := ec2.NewGetInstanceTypesFromInstanceRequirementsPaginator(ec2Client, &ec2.GetInstanceTypesFromInstanceRequirementsInput{
+		ArchitectureTypes:   []ec2types.ArchitectureType{ec2types.ArchitectureType(image.Architecture)},
+		VirtualizationTypes: []ec2types.VirtualizationType{ec2types.VirtualizationType(image.VirtualizationType)},
+		InstanceRequirements: &ec2types.InstanceRequirementsRequest{
+			VCpuCount:           &ec2types.VCpuCountRangeRequest{Min: aws.Int32(resources.VCPUs)},
+			MemoryMiB:           &ec2types.MemoryMiBRequest{Min: aws.Int32(resources.MemoryMiB)},
+			InstanceGenerations: []ec2types.InstanceGeneration{ec2types.InstanceGenerationCurrent},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instance types for %d vCPUs and %d MiB: %w", resources.VCPUs, resources.MemoryMiB, err)
+		}
+		for _, t := range page.InstanceTypes {
+			names = append(names, aws.ToString(t.InstanceType))
+		}
+	}
+
+	// Order by size so the cheapest types are the ones that get priced.
+	infos := make([]*ec2types.InstanceTypeInfo, 0, len(names))
+	for start := 0; start < len(names); start += 100 {
+		batch := names[start:min(start+100, len(names))]
+		instanceTypes := make([]ec2types.InstanceType, 0, len(batch))
+		for _, name := range batch {
+			instanceTypes = append(instanceTypes, ec2types.InstanceType(name))
+		}
+		result, err := ec2Client.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{InstanceTypes: instanceTypes})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instance types: %w", err)
+		}
+		for i := range result.InstanceTypes {
+			info := &result.InstanceTypes[i]
+			instanceTypeCache.Store(region+"/"+string(info.InstanceType), info)
+			infos = append(infos, info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		vi, vj := aws.ToInt32(infos[i].VCpuInfo.DefaultVCpus), aws.ToInt32(infos[j].VCpuInfo.DefaultVCpus)
+		if vi != vj {
+			return vi < vj
+		}
+		mi, mj := aws.ToInt64(infos[i].MemoryInfo.SizeInMiB), aws.ToInt64(infos[j].MemoryInfo.SizeInMiB)
+		if mi != mj {
+			return mi < mj
+		}
+		return infos[i].InstanceType < infos[j].InstanceType
+	})
+
+	names = names[:0]
+	for _, info := range infos[:min(instanceTypeCandidates, len(infos))] {
+		names = append(names, string(info.InstanceType))
+	}
+	return names, nil
+}
+
+// unusedReservedInstances counts active Linux Reserved Instances per instance type that are not
+// already covered by a pending or running instance. Zonal reservations only count when they are in
+// availabilityZone. Usage is counted per region, which is how regional reservations are applied.
+func unusedReservedInstances(ctx context.Context, ec2Client *ec2.Client, availabilityZone string, instanceTypes []string) (map[string]int32, error) {
+	reservations, err := ec2Client.DescribeReservedInstances(ctx, &ec2.DescribeReservedInstancesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("state"), Values: []string{string(ec2types.ReservedInstanceStateActive)}},
+			{Name: aws.String("instance-type"), Values: instanceTypes},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe Reserved Instances: %w", err)
+	}
+
+	unused := map[string]int32{}
+	for _, ri := range reservations.ReservedInstances {
+		if !strings.HasPrefix(string(ri.ProductDescription), string(ec2types.RIProductDescriptionLinuxUnix)) {
+			continue
+		}
+		if ri.Scope == ec2types.ScopeAvailabilityZone && aws.ToString(ri.AvailabilityZone) != availabilityZone {
+			continue
+		}
+		unused[string(ri.InstanceType)] += aws.ToInt32(ri.InstanceCount)
+	}
+	if len(unused) == 0 {
+		return unused, nil
+	}
+
+	paginator := ec2.NewDescribeInstancesPaginator(ec2Client, &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("instance-state-name"), Values: []string{"pending", "running"}},
+			{Name: aws.String("instance-type"), Values: instanceTypes},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe running instances: %w", err)
+		}
+		for _, reservation := range page.Reservations {
+			for _, inst := range reservation.Instances {
+				if unused[string(inst.InstanceType)] > 0 {
+					unused[string(inst.InstanceType)]--
+				}
+			}
+		}
+	}
+	return unused, nil
+}
+
+// onDemandHourlyPrice returns the Linux, shared tenancy on-demand price of instanceType in region.
+func onDemandHourlyPrice(ctx context.Context, region, instanceType string) (float64, error) {
+	key := region + "/" + instanceType
+	if cached, ok := onDemandPriceCache.Load(key); ok && time.Since(cached.(cachedPrice).fetchedAt) < onDemandPriceCacheTTL {
+		return cached.(cachedPrice).hourlyUSD, nil
+	}
+
+	filter := func(field, value string) map[string]string {
+		return map[string]string{"Type": "TERM_MATCH", "Field": field, "Value": value}
+	}
+	in := map[string]any{
+		"ServiceCode":   "AmazonEC2",
+		"FormatVersion": "aws_v1",
+		"MaxResults":    1,
+		"Filters": []map[string]string{
+			filter("regionCode", region),
+			filter("instanceType", instanceType),
+			filter("operatingSystem", "Linux"),
+			filter("tenancy", "Shared"),
+			filter("preInstalledSw", "NA"),
+			filter("capacitystatus", "Used"),
+		},
+	}
+	out := struct {
+		PriceList []string
+	}{}
+	if err := pricing.call(ctx, "GetProducts", in, &out); err != nil {
+		return 0, err
+	}
+	if len(out.PriceList) == 0 {
+		return 0, fmt.Errorf("no on-demand price for %s in %s", instanceType, region)
+	}
+
+	hourly, err := parseOnDemandPrice(out.PriceList[0])
+	if err != nil {
+		return 0, err
+	}
+	onDemandPriceCache.Store(key, cachedPrice{hourlyUSD: hourly, fetchedAt: time.Now()})
+	return hourly, nil
+}
+
+// parseOnDemandPrice reads the hourly USD price from a price list product document.
+func parseOnDemandPrice(product string) (float64, error) {
+	doc := struct {
+		Terms struct {
+			OnDemand map[string]struct {
+				PriceDimensions map[string]struct {
+					PricePerUnit map[string]string `json:"pricePerUnit"`
+				} `json:"priceDimensions"`
+			} `json:"OnDemand"`
+		} `json:"terms"`
+	}{}
+	if err := json.Unmarshal([]byte(product), &doc); err != nil {
+		return 0, fmt.Errorf("failed to parse price list: %w", err)
+	}
+	for _, term := range doc.Terms.OnDemand {
+		for _, dimension := range term.PriceDimensions {
+			if usd, ok := dimension.PricePerUnit["USD"]; ok {
+				return strconv.ParseFloat(usd, 64)
+			}
+		}
+	}
+	return 0, fmt.Errorf("price list has no on-demand USD price")
+}
+
+// reservedHourlyPrice returns the effective hourly price of the cheapest one year standard Linux
+// Reserved Instance offering for instanceType in region, or 0 when there is none.
+func reservedHourlyPrice(ctx context.Context, region, instanceType string) (float64, error) {
+	result, err := awsClient(region).DescribeReservedInstancesOfferings(ctx, &ec2.DescribeReservedInstancesOfferingsInput{
+		InstanceType:       ec2types.InstanceType(instanceType),
+		ProductDescription: ec2types.RIProductDescriptionLinuxUnix,
+		OfferingClass:      ec2types.OfferingClassTypeStandard,
+		InstanceTenancy:    ec2types.TenancyDefault,
+		IncludeMarketplace: aws.Bool(false),
+		MinDuration:        aws.Int64(reservedInstanceTerm),
+		MaxDuration:        aws.Int64(reservedInstanceTerm),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var cheapest float64
+	for _, offering := range result.ReservedInstancesOfferings {
+		hours := float64(aws.ToInt64(offering.Duration)) / 3600
+		if hours == 0 {
+			continue
+		}
+		hourly := float64(aws.ToFloat32(offering.FixedPrice))/hours + float64(aws.ToFloat32(offering.UsagePrice))
+		for _, charge := range offering.RecurringCharges {
+			if charge.Frequency == ec2types.RecurringChargeFrequencyHourly {
+				hourly += aws.ToFloat64(charge.Amount)
+			}
+		}
+		if cheapest == 0 || hourly < cheapest {
+			cheapest = hourly
+		}
+	}
+	return cheapest, nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cost-aware instance type selection", func() {
+	It("should prefer a type with unused Reserved Instances", func() {
+		instanceType, reason, ok := chooseInstanceType([]instanceTypeCandidate{
+			{InstanceType: "t3.large", OnDemandHourly: 0.0832},
+			{InstanceType: "m5.large", OnDemandHourly: 0.096, UnusedReserved: 2},
+		})
+		Expect(ok).To(BeTrue())
+		Expect(instanceType).To(Equal("m5.large"))
+		Expect(reason).To(ContainSubstring("2 unused Reserved Instances"))
+	})
+
+	It("should otherwise pick the cheapest on-demand type", func() {
+		instanceType, reason, ok := chooseInstanceType([]instanceTypeCandidate{
+			{InstanceType: "m5.large", OnDemandHourly: 0.096},
+			{InstanceType: "t3.large", OnDemandHourly: 0.0832, ReservedHourly: 0.052},
+			{InstanceType: "c5.large"},
+		})
+		Expect(ok).To(BeTrue())
+		Expect(instanceType).To(Equal("t3.large"))
+		Expect(reason).To(ContainSubstring("$0.0832/h"))
+		Expect(reason).To(ContainSubstring("$0.0520/h with a 1 year Reserved Instance"))
+	})
+
+	It("should give up when nothing could be priced", func() {
+		_, _, ok := chooseInstanceType([]instanceTypeCandidate{{InstanceType: "t3.large"}})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should read the on-demand price from a price list product", func() {
+		product := `{"product":{"sku":"ABC"},"terms":{"OnDemand":{"ABC.JRTCKXETXF":{"priceDimensions":` +
+			`{"ABC.JRTCKXETXF.6YS6EN2CT7":{"unit":"Hrs","pricePerUnit":{"USD":"0.0416000000"}}}}}}}`
+		Expect(parseOnDemandPrice(product)).To(Equal(0.0416))
+	})
+})
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// instanceTypeCache remembers DescribeInstanceTypes results. Instance type capabilities never change
+// for the lifetime of the operator, so there is no need to ask AWS more than once per region and type.
+var instanceTypeCache sync.Map
+
+// DescribeInstanceType returns the capabilities of instanceType in region, using the cache when possible.
+func DescribeInstanceType(ctx context.Context, region, instanceType string) (*ec2types.InstanceTypeInfo, error) {
+	key := region + "/" + instanceType
+	if info, ok := instanceTypeCache.Load(key); ok {
+		return info.(*ec2types.InstanceTypeInfo), nil
+	}
+
+	ec2Client := awsClient(region)
+	result, err := ec2Client.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []ec2types.InstanceType{ec2types.InstanceType(instanceType)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe instance type %s: %w", instanceType, err)
+	}
+	if len(result.InstanceTypes) == 0 {
+		return nil, fmt.Errorf("instance type %s is not offered in region %s", instanceType, region)
+	}
+
+	info := &result.InstanceTypes[0]
+	instanceTypeCache.Store(key, info)
+	return info, nil
+}
+
+// isEBSOptimized reports whether the instance has EBS optimization, including instance types where
+// it is always on and the instance attribute does not need to be set.
+func isEBSOptimized(ctx context.Context, region string, awsInstance *ec2types.Instance) (bool, error) {
+	if aws.ToBool(awsInstance.EbsOptimized) {
+		return true, nil
+	}
+	info, err := DescribeInstanceType(ctx, region, string(awsInstance.InstanceType))
+	if err != nil {
+		return false, err
+	}
+	return info.EbsInfo != nil && info.EbsInfo.EbsOptimizedSupport == ec2types.EbsOptimizedSupportDefault, nil
+}
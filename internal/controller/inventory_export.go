@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// inventoryExportPoll is how often the exporter re-reads its settings from Ec2OperatorConfig.
+const inventoryExportPoll = time.Minute
+
+// inventoryRecord is one Ec2Instance in an inventory export: its Kubernetes identity next to the
+// AWS side of the instance.
+type inventoryRecord struct {
+	Namespace string                      `json:"namespace"`
+	Name      string                      `json:"name"`
+	Labels    map[string]string           `json:"labels,omitempty"`
+	Spec      computev1.Ec2InstanceSpec   `json:"spec"`
+	Status    computev1.Ec2InstanceStatus `json:"status"`
+}
+
+// InventoryExporter uploads all Ec2Instances to S3 at the interval set in
+// Ec2OperatorConfig.spec.inventoryExport. It implements manager.Runnable.
+type InventoryExporter struct {
+	Reader client.Reader
+
+	lastExport time.Time
+}
+
+// Start checks every minute whether an export is due, until the manager's context is cancelled.
+func (e *InventoryExporter) Start(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("inventory-export")
+	for {
+		if err := e.exportIfDue(ctx, time.Now()); err != nil {
+			l.Error(err, "Failed to export inventory")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(inventoryExportPoll):
+		}
+	}
+}
+
+// NeedLeaderElection returns true so only one replica uploads.
+func (e *InventoryExporter) NeedLeaderElection() bool {
+	return true
+}
+
+// exportIfDue uploads the inventory when the export is enabled and the interval has passed.
+func (e *InventoryExporter) exportIfDue(ctx context.Context, now time.Time) error {
+	config, err := GetOperatorConfig(ctx, e.Reader)
+	if err != nil {
+		return err
+	}
+	spec := config.Spec.InventoryExport
+	if !spec.Enabled || spec.S3Bucket == "" {
+		return nil
+	}
+	interval := time.Duration(spec.ExportIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	if now.Sub(e.lastExport) < interval {
+		return nil
+	}
+
+	instances := &computev1.Ec2InstanceList{}
+	if err := e.Reader.List(ctx, instances); err != nil {
+		return fmt.Errorf("failed to list Ec2Instances: %w", err)
+	}
+	records := inventoryRecords(instances.Items)
+
+	body, contentType, err := encodeInventory(records, spec.Format)
+	if err != nil {
+		return err
+	}
+	input := &s3.PutObjectInput{
+		Bucket:               aws.String(spec.S3Bucket),
+		Key:                  aws.String(inventoryExportKey(spec, now)),
+		Body:                 bytes.NewReader(body),
+		ContentType:          aws.String(contentType),
+		ServerSideEncryption: s3types.ServerSideEncryptionAes256,
+	}
+	if spec.KMSKeyID != "" {
+		input.ServerSideEncryption = s3types.ServerSideEncryptionAwsKms
+		input.SSEKMSKeyId = aws.String(spec.KMSKeyID)
+	}
+	region := spec.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	if _, err := s3Client(region).PutObject(ctx, input); err != nil {
+		return fmt.Errorf("failed to upload inventory to s3://%s/%s: %w", spec.S3Bucket, *input.Key, err)
+	}
+
+	e.lastExport = now
+	log.FromContext(ctx).Info("Exported inventory", "bucket", spec.S3Bucket, "key", *input.Key, "instances", len(records))
+	return nil
+}
+
+// inventoryExportKey is the object key of the export taken at now.
+func inventoryExportKey(spec computev1.InventoryExportSpec, now time.Time) string {
+	ext := "json"
+	if spec.Format == computev1.InventoryExportFormatCSV {
+		ext = "csv"
+	}
+	return path.Join(spec.S3Prefix, fmt.Sprintf("inventory-%s.%s", now.UTC().Format(time.DateOnly), ext))
+}
+
+// inventoryRecords turns the instances into export records, sorted by namespace and name.
+func inventoryRecords(instances []computev1.Ec2Instance) []inventoryRecord {
+	records := make([]inventoryRecord, 0, len(instances))
+	for i := range instances {
+		inst := &instances[i]
+		records = append(records, inventoryRecord{
+			Namespace: inst.Namespace,
+			Name:      inst.Name,
+			Labels:    inst.Labels,
+			Spec:      inst.Spec,
+			Status:    inst.Status,
+		})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Namespace != records[j].Namespace {
+			return records[i].Namespace < records[j].Namespace
+		}
+		return records[i].Name < records[j].Name
+	})
+	return records
+}
+
+// inventoryCSVHeader are the columns of a CSV export. CSV only carries the fields most tools
+// need; the JSON export has the full spec and status.
+var inventoryCSVHeader = []string{
+	"namespace", "name", "labels", "instanceID", "instanceType", "amiID", "region",
+	"availabilityZone", "state", "privateIP", "publicIP", "launchTime", "tags",
+}
+
+// encodeInventory serializes the records in format and returns them with their content type.
+func encodeInventory(records []inventoryRecord, format computev1.InventoryExportFormat) ([]byte, string, error) {
+	if format != computev1.InventoryExportFormatCSV {
+		body, err := json.Marshal(records)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encode inventory: %w", err)
+		}
+		return body, "application/json", nil
+	}
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	_ = w.Write(inventoryCSVHeader)
+	for _, r := range records {
+		launchTime := ""
+		if r.Status.LaunchTime != nil {
+			launchTime = r.Status.LaunchTime.UTC().Format(time.RFC3339)
+		}
+		_ = w.Write([]string{
+			r.Namespace, r.Name, joinKeyValues(r.Labels), r.Status.InstanceID, r.Spec.InstanceType, r.Spec.AMIId,
+			r.Spec.Region, r.Spec.AvailabilityZone, r.Status.State, r.Status.PrivateIP, r.Status.PublicIP,
+			launchTime, joinKeyValues(r.Spec.Tags),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, "", fmt.Errorf("failed to encode inventory: %w", err)
+	}
+	return buf.Bytes(), "text/csv", nil
+}
+
+// joinKeyValues renders a map as sorted key=value pairs separated by semicolons.
+func joinKeyValues(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
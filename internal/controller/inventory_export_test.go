@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Inventory export", func() {
+	instances := []computev1.Ec2Instance{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "db", Labels: map[string]string{"app": "db"}},
+			Spec:       computev1.Ec2InstanceSpec{InstanceType: "r5.large", Region: "eu-west-1"},
+			Status:     computev1.Ec2InstanceStatus{InstanceID: "i-2", State: "running"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "web"},
+			Spec:       computev1.Ec2InstanceSpec{InstanceType: "t3.micro", Tags: map[string]string{"b": "2", "a": "1"}},
+			Status:     computev1.Ec2InstanceStatus{InstanceID: "i-1", State: "running"},
+		},
+	}
+
+	It("should name the export after the format and the day", func() {
+		now := time.Date(2025, 3, 9, 23, 0, 0, 0, time.UTC)
+		spec := computev1.InventoryExportSpec{S3Prefix: "ec2operator/"}
+		Expect(inventoryExportKey(spec, now)).To(Equal("ec2operator/inventory-2025-03-09.json"))
+		spec.Format = computev1.InventoryExportFormatCSV
+		Expect(inventoryExportKey(spec, now)).To(Equal("ec2operator/inventory-2025-03-09.csv"))
+	})
+
+	It("should export Kubernetes metadata next to spec and status as JSON", func() {
+		body, contentType, err := encodeInventory(inventoryRecords(instances), computev1.InventoryExportFormatJSON)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contentType).To(Equal("application/json"))
+
+		var records []inventoryRecord
+		Expect(json.Unmarshal(body, &records)).To(Succeed())
+		Expect(records).To(HaveLen(2))
+		Expect(records[0].Namespace).To(Equal("team-a"))
+		Expect(records[1].Labels).To(HaveKeyWithValue("app", "db"))
+		Expect(records[1].Status.InstanceID).To(Equal("i-2"))
+	})
+
+	It("should export one row per instance as CSV", func() {
+		body, contentType, err := encodeInventory(inventoryRecords(instances), computev1.InventoryExportFormatCSV)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contentType).To(Equal("text/csv"))
+
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[0]).To(HavePrefix("namespace,name,labels,instanceID"))
+		Expect(lines[1]).To(Equal("team-a,web,,i-1,t3.micro,,,,running,,,,a=1;b=2"))
+		Expect(lines[2]).To(HavePrefix("team-b,db,app=db,i-2,r5.large"))
+	})
+})
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// InventoryServer serves the ClusterInventory status as JSON on /inventory so that tools
+// outside of Kubernetes (dashboards, scripts) can read it without talking to the API server.
+// It implements manager.Runnable and is started by the manager alongside the controllers.
+type InventoryServer struct {
+	Reader      client.Reader
+	BindAddress string
+}
+
+// Start runs the HTTP server until the manager's context is cancelled.
+func (s *InventoryServer) Start(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("inventory-server")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/inventory", s.serveInventory)
+
+	srv := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			l.Error(err, "Failed to shut down inventory server")
+		}
+	}()
+
+	l.Info("Starting inventory server", "address", s.BindAddress)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection returns false so every replica can answer inventory requests.
+func (s *InventoryServer) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *InventoryServer) serveInventory(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	inventory := &computev1.ClusterInventory{}
+	err := s.Reader.Get(req.Context(), types.NamespacedName{Name: computev1.ClusterInventoryName}, inventory)
+	if apierrors.IsNotFound(err) {
+		http.Error(w, "inventory has not been computed yet", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(inventory.Status); err != nil {
+		log.FromContext(req.Context()).Error(err, "Failed to encode inventory")
+	}
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// launchTemplateSpecification returns the launch template of spec.launchTemplateRef for
+// RunInstances.
+func launchTemplateSpecification(ref *computev1.LaunchTemplateRef) *ec2types.LaunchTemplateSpecification {
+	specification := &ec2types.LaunchTemplateSpecification{}
+	if ref.ID != "" {
+		specification.LaunchTemplateId = aws.String(ref.ID)
+	} else {
+		specification.LaunchTemplateName = aws.String(ref.Name)
+	}
+	if ref.Version != "" {
+		specification.Version = aws.String(ref.Version)
+	}
+	return specification
+}
+
+// applyLaunchTemplate launches from the launch template of ref, if set. The fields of runInput the
+// spec leaves empty are cleared, so RunInstances takes them from the template rather than
+// overriding them with empty values.
+func applyLaunchTemplate(runInput *ec2.RunInstancesInput, spec computev1.Ec2InstanceSpec, ref *computev1.LaunchTemplateRef) {
+	if ref == nil {
+		return
+	}
+	runInput.LaunchTemplate = launchTemplateSpecification(ref)
+	// Without them in the spec, the AMI and instance type in status were looked up from the
+	// template, which may have a new default version by now.
+	if spec.AMIId == "" {
+		runInput.ImageId = nil
+	}
+	if spec.InstanceType == "" && spec.InstanceTypeOptimization == "" {
+		runInput.InstanceType = ""
+	}
+	if aws.ToString(runInput.KeyName) == "" {
+		runInput.KeyName = nil
+	}
+	if aws.ToString(runInput.SubnetId) == "" {
+		runInput.SubnetId = nil
+	}
+}
+
+type launchTemplateVersionAPI interface {
+	DescribeLaunchTemplateVersions(ctx context.Context, params *ec2.DescribeLaunchTemplateVersionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplateVersionsOutput, error)
+}
+
+// launchTemplateData returns the launch parameters of the template version ref launches from, so
+// the instance type and AMI the spec leaves to the template are known before the launch.
+func launchTemplateData(ctx context.Context, ec2Client launchTemplateVersionAPI, ref *computev1.LaunchTemplateRef) (*ec2types.ResponseLaunchTemplateData, error) {
+	specification := launchTemplateSpecification(ref)
+	version := aws.ToString(specification.Version)
+	if version == "" {
+		version = "$Default"
+	}
+	template := ref.ID
+	if template == "" {
+		template = ref.Name
+	}
+	result, err := ec2Client.DescribeLaunchTemplateVersions(ctx, &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId:   specification.LaunchTemplateId,
+		LaunchTemplateName: specification.LaunchTemplateName,
+		Versions:           []string{version},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe version %s of launch template %s: %w", version, template, err)
+	}
+	if len(result.LaunchTemplateVersions) == 0 || result.LaunchTemplateVersions[0].LaunchTemplateData == nil {
+		return nil, fmt.Errorf("launch template %s has no version %s", template, version)
+	}
+	return result.LaunchTemplateVersions[0].LaunchTemplateData, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+var _ = Describe("Launch template", func() {
+	It("should refer to the template by ID or by name", func() {
+		Expect(launchTemplateSpecification(&computev1.LaunchTemplateRef{ID: "lt-1", Version: "$Latest"})).To(Equal(&ec2types.LaunchTemplateSpecification{
+			LaunchTemplateId: aws.String("lt-1"),
+			Version:          aws.String("$Latest"),
+		}))
+		Expect(launchTemplateSpecification(&computev1.LaunchTemplateRef{Name: "web"})).To(Equal(&ec2types.LaunchTemplateSpecification{
+			LaunchTemplateName: aws.String("web"),
+		}))
+	})
+
+	It("should leave the fields the spec does not set to the template", func() {
+		ref := &computev1.LaunchTemplateRef{Name: "web"}
+		spec := computev1.Ec2InstanceSpec{InstanceType: "t3.micro", AMIId: "ami-123"}
+		runInput := &ec2.RunInstancesInput{InstanceType: "t3.micro", ImageId: aws.String("ami-123"), KeyName: aws.String(""), SubnetId: aws.String("")}
+		applyLaunchTemplate(runInput, spec, ref)
+		Expect(runInput.LaunchTemplate).NotTo(BeNil())
+		Expect(runInput.KeyName).To(BeNil())
+		Expect(runInput.SubnetId).To(BeNil())
+		Expect(runInput.InstanceType).To(Equal(ec2types.InstanceType("t3.micro")))
+		Expect(aws.ToString(runInput.ImageId)).To(Equal("ami-123"))
+
+		runInput = &ec2.RunInstancesInput{KeyName: aws.String("ops"), SubnetId: aws.String("subnet-1")}
+		applyLaunchTemplate(runInput, spec, ref)
+		Expect(aws.ToString(runInput.KeyName)).To(Equal("ops"))
+		Expect(aws.ToString(runInput.SubnetId)).To(Equal("subnet-1"))
+	})
+
+	It("should not touch the request without a launch template", func() {
+		runInput := &ec2.RunInstancesInput{KeyName: aws.String("")}
+		applyLaunchTemplate(runInput, computev1.Ec2InstanceSpec{}, nil)
+		Expect(runInput.LaunchTemplate).To(BeNil())
+		Expect(runInput.KeyName).NotTo(BeNil())
+	})
+
+	It("should leave the instance type and AMI the spec does not set to the template", func() {
+		// Both were looked up from the template and recorded in status.
+		runInput := &ec2.RunInstancesInput{InstanceType: "m5.large", ImageId: aws.String("ami-template")}
+		applyLaunchTemplate(runInput, computev1.Ec2InstanceSpec{}, &computev1.LaunchTemplateRef{Name: "web"})
+		Expect(runInput.ImageId).To(BeNil())
+		Expect(runInput.InstanceType).To(BeEmpty())
+
+		runInput = &ec2.RunInstancesInput{InstanceType: "m5.large"}
+		applyLaunchTemplate(runInput, computev1.Ec2InstanceSpec{InstanceTypeOptimization: computev1.InstanceTypeOptimizationCostAware},
+			&computev1.LaunchTemplateRef{Name: "web"})
+		Expect(runInput.InstanceType).To(Equal(ec2types.InstanceTypeM5Large))
+	})
+
+	It("should look up the template version the instance launches from", func() {
+		fake := &awsclient.FakeEC2Client{Outputs: map[string]any{
+			"DescribeLaunchTemplateVersions": &ec2.DescribeLaunchTemplateVersionsOutput{LaunchTemplateVersions: []ec2types.LaunchTemplateVersion{{
+				LaunchTemplateData: &ec2types.ResponseLaunchTemplateData{InstanceType: ec2types.InstanceTypeM5Large, ImageId: aws.String("ami-template")},
+			}}},
+		}}
+		data, err := launchTemplateData(context.Background(), fake, &computev1.LaunchTemplateRef{ID: "lt-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data.InstanceType).To(Equal(ec2types.InstanceTypeM5Large))
+		Expect(aws.ToString(data.ImageId)).To(Equal("ami-template"))
+
+		_, err = launchTemplateData(context.Background(), fake, &computev1.LaunchTemplateRef{Name: "web", Version: "3"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallsTo("DescribeLaunchTemplateVersions")).To(Equal([]any{
+			&ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: aws.String("lt-1"), Versions: []string{"$Default"}},
+			&ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateName: aws.String("web"), Versions: []string{"3"}},
+		}))
+
+		_, err = launchTemplateData(context.Background(), &awsclient.FakeEC2Client{}, &computev1.LaunchTemplateRef{Name: "web", Version: "9"})
+		Expect(err).To(MatchError("launch template web has no version 9"))
+	})
+})
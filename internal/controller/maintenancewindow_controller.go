@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// maintenanceWindowFinalizer makes sure the window is deleted in AWS before the object is removed.
+const maintenanceWindowFinalizer = "maintenancewindow.compute.cloud.com"
+
+// MaintenanceWindowReconciler keeps an SSM maintenance window in line with its MaintenanceWindow object.
+type MaintenanceWindowReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=maintenancewindows,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=maintenancewindows/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=maintenancewindows/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes the maintenance window.
+func (r *MaintenanceWindowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	window := &computev1.MaintenanceWindow{}
+	if err := r.Get(ctx, req.NamespacedName, window); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	client := ssmClient(window.Spec.Region)
+
+	if !window.DeletionTimestamp.IsZero() {
+		if window.Status.WindowID != "" {
+			// Deleting the window also removes the targets and tasks Ec2Instances registered in it.
+			_, err := client.DeleteMaintenanceWindow(ctx, &ssm.DeleteMaintenanceWindowInput{
+				WindowId: aws.String(window.Status.WindowID),
+			})
+			if err != nil && !strings.Contains(err.Error(), "DoesNotExist") {
+				l.Error(err, "Failed to delete maintenance window", "windowID", window.Status.WindowID)
+				return ctrl.Result{}, fmt.Errorf("failed to delete maintenance window: %w", err)
+			}
+			l.Info("Deleted maintenance window", "windowID", window.Status.WindowID)
+		}
+
+		controllerutil.RemoveFinalizer(window, maintenanceWindowFinalizer)
+		if err := r.Update(ctx, window); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if controllerutil.AddFinalizer(window, maintenanceWindowFinalizer) {
+		if err := r.Update(ctx, window); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if window.Status.WindowID == "" {
+		input := &ssm.CreateMaintenanceWindowInput{
+			Name:     aws.String(maintenanceWindowName(window)),
+			Schedule: aws.String(window.Spec.Schedule),
+			Duration: aws.Int32(window.Spec.DurationHours),
+			Cutoff:   window.Spec.CutoffHours,
+			// Only instances registered by the Ec2Instance controller should be patched.
+			AllowUnassociatedTargets: false,
+			Tags: []ssmtypes.Tag{
+				{Key: aws.String("Name"), Value: aws.String(window.Name)},
+				{Key: aws.String("ec2instance.compute.cloud.com/maintenance-window"), Value: aws.String(string(window.UID))},
+			},
+			// The UID makes the call idempotent if the status update below is lost.
+			ClientToken: aws.String(string(window.UID)),
+		}
+		if window.Spec.ScheduleTimezone != "" {
+			input.ScheduleTimezone = aws.String(window.Spec.ScheduleTimezone)
+		}
+
+		result, err := client.CreateMaintenanceWindow(ctx, input)
+		if err != nil {
+			l.Error(err, "Failed to create maintenance window")
+			return ctrl.Result{}, fmt.Errorf("failed to create maintenance window: %w", err)
+		}
+		l.Info("Created maintenance window", "windowID", aws.ToString(result.WindowId))
+
+		window.Status.WindowID = aws.ToString(result.WindowId)
+		window.Status.ObservedGeneration = window.Generation
+		return ctrl.Result{}, r.Status().Update(ctx, window)
+	}
+
+	if window.Status.ObservedGeneration == window.Generation {
+		return ctrl.Result{}, nil
+	}
+
+	timezone := window.Spec.ScheduleTimezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	_, err := client.UpdateMaintenanceWindow(ctx, &ssm.UpdateMaintenanceWindowInput{
+		WindowId:         aws.String(window.Status.WindowID),
+		Schedule:         aws.String(window.Spec.Schedule),
+		ScheduleTimezone: aws.String(timezone),
+		Duration:         aws.Int32(window.Spec.DurationHours),
+		Cutoff:           aws.Int32(window.Spec.CutoffHours),
+	})
+	if err != nil {
+		l.Error(err, "Failed to update maintenance window", "windowID", window.Status.WindowID)
+		return ctrl.Result{}, fmt.Errorf("failed to update maintenance window: %w", err)
+	}
+	l.Info("Updated maintenance window", "windowID", window.Status.WindowID)
+
+	window.Status.ObservedGeneration = window.Generation
+	return ctrl.Result{}, r.Status().Update(ctx, window)
+}
+
+// maintenanceWindowName returns the name of the window in AWS. Names are unique per account, so
+// the namespace is part of it; SSM allows letters, digits, dashes, underscores and dots.
+func maintenanceWindowName(window *computev1.MaintenanceWindow) string {
+	name := window.Namespace + "-" + window.Name
+	if len(name) > 128 {
+		name = name[:128]
+	}
+	return name
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MaintenanceWindowReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.MaintenanceWindow{}).
+		Named("maintenancewindow").
+		Complete(r)
+}
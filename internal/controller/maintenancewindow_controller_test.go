@@ -0,0 +1,41 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("MaintenanceWindow Controller", func() {
+	Context("When naming the window in AWS", func() {
+		It("should include the namespace", func() {
+			window := &computev1.MaintenanceWindow{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "weekly"}}
+			Expect(maintenanceWindowName(window)).To(Equal("team-a-weekly"))
+		})
+
+		It("should stay within the SSM length limit", func() {
+			window := &computev1.MaintenanceWindow{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: strings.Repeat("w", 200)}}
+			Expect(maintenanceWindowName(window)).To(HaveLen(128))
+		})
+	})
+})
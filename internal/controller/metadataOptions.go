@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// metadataOptionsRequest returns the instance metadata settings of spec.metadataOptions, or nil to
+// keep the AWS defaults. enforceIMDSv2 requires session tokens whatever the spec says.
+func metadataOptionsRequest(ec2Instance *computev1.Ec2Instance, enforceIMDSv2 bool) *ec2types.InstanceMetadataOptionsRequest {
+	spec := ec2Instance.Spec.MetadataOptions
+	if spec == nil && !enforceIMDSv2 {
+		return nil
+	}
+	request := &ec2types.InstanceMetadataOptionsRequest{}
+	if spec != nil {
+		request.HttpTokens = ec2types.HttpTokensState(spec.HTTPTokens)
+		request.InstanceMetadataTags = ec2types.InstanceMetadataTagsState(spec.InstanceMetadataTags)
+		if spec.HTTPPutResponseHopLimit > 0 {
+			request.HttpPutResponseHopLimit = aws.Int32(spec.HTTPPutResponseHopLimit)
+		}
+	}
+	if enforceIMDSv2 {
+		request.HttpTokens = ec2types.HttpTokensStateRequired
+	}
+	return request
+}
+
+// metadataOptionsChange returns the change that brings the metadata settings of the instance in line
+// with desired, or nil when they already are. Settings desired leaves unset are not changed.
+func metadataOptionsChange(instanceID string, desired *ec2types.InstanceMetadataOptionsRequest, current *ec2types.InstanceMetadataOptionsResponse) *ec2.ModifyInstanceMetadataOptionsInput {
+	if desired == nil || current == nil {
+		return nil
+	}
+	change := &ec2.ModifyInstanceMetadataOptionsInput{InstanceId: aws.String(instanceID)}
+	changed := false
+	if desired.HttpTokens != "" && desired.HttpTokens != current.HttpTokens {
+		change.HttpTokens = desired.HttpTokens
+		changed = true
+	}
+	if desired.HttpPutResponseHopLimit != nil && aws.ToInt32(desired.HttpPutResponseHopLimit) != aws.ToInt32(current.HttpPutResponseHopLimit) {
+		change.HttpPutResponseHopLimit = desired.HttpPutResponseHopLimit
+		changed = true
+	}
+	if desired.InstanceMetadataTags != "" && desired.InstanceMetadataTags != current.InstanceMetadataTags {
+		change.InstanceMetadataTags = desired.InstanceMetadataTags
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return change
+}
+
+// reconcileMetadataOptions applies spec.metadataOptions and --enforce-imdsv2 to an existing
+// instance, e.g. after the spec changed or the settings were changed outside the operator.
+func (r *Ec2InstanceReconciler) reconcileMetadataOptions(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	desired := metadataOptionsRequest(ec2Instance, r.EnforceIMDSv2)
+	change := metadataOptionsChange(ec2Instance.Status.InstanceID, desired, awsInstance.MetadataOptions)
+	if change == nil {
+		return nil
+	}
+	if _, err := instanceAWSClient(ec2Instance).ModifyInstanceMetadataOptions(ctx, change); err != nil {
+		return fmt.Errorf("failed to set instance metadata options: %w", err)
+	}
+	log.FromContext(ctx).Info("Corrected instance metadata options", "instanceID", ec2Instance.Status.InstanceID,
+		"httpTokens", desired.HttpTokens, "instanceMetadataTags", desired.InstanceMetadataTags)
+	return nil
+}
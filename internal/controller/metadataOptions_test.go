@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Instance metadata options", func() {
+	It("should keep the AWS defaults unless the spec or the operator asks for settings", func() {
+		inst := &computev1.Ec2Instance{}
+		Expect(metadataOptionsRequest(inst, false)).To(BeNil())
+		Expect(metadataOptionsRequest(inst, true)).To(Equal(&ec2types.InstanceMetadataOptionsRequest{HttpTokens: ec2types.HttpTokensStateRequired}))
+
+		inst.Spec.MetadataOptions = &computev1.MetadataOptionsSpec{HTTPTokens: "optional", HTTPPutResponseHopLimit: 2, InstanceMetadataTags: "enabled"}
+		Expect(metadataOptionsRequest(inst, false)).To(Equal(&ec2types.InstanceMetadataOptionsRequest{
+			HttpTokens:              ec2types.HttpTokensStateOptional,
+			HttpPutResponseHopLimit: aws.Int32(2),
+			InstanceMetadataTags:    ec2types.InstanceMetadataTagsStateEnabled,
+		}))
+		Expect(metadataOptionsRequest(inst, true).HttpTokens).To(Equal(ec2types.HttpTokensStateRequired))
+	})
+
+	It("should only change the settings that differ from the instance", func() {
+		current := &ec2types.InstanceMetadataOptionsResponse{
+			HttpTokens:              ec2types.HttpTokensStateOptional,
+			HttpPutResponseHopLimit: aws.Int32(1),
+			InstanceMetadataTags:    ec2types.InstanceMetadataTagsStateDisabled,
+		}
+		desired := &ec2types.InstanceMetadataOptionsRequest{HttpTokens: ec2types.HttpTokensStateRequired, HttpPutResponseHopLimit: aws.Int32(1)}
+		Expect(metadataOptionsChange("i-1", desired, current)).To(Equal(&ec2.ModifyInstanceMetadataOptionsInput{
+			InstanceId: aws.String("i-1"),
+			HttpTokens: ec2types.HttpTokensStateRequired,
+		}))
+
+		current.HttpTokens = ec2types.HttpTokensStateRequired
+		Expect(metadataOptionsChange("i-1", desired, current)).To(BeNil())
+		Expect(metadataOptionsChange("i-1", nil, current)).To(BeNil())
+	})
+})
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var (
+	// capacityReservationUtilization is the share of reserved instances in use, per CapacityReservation.
+	capacityReservationUtilization = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ec2instance_capacity_reservation_utilization_percent",
+		Help: "Percentage of the reserved instance capacity that is in use.",
+	}, []string{"namespace", "name", "reservation_id"})
+
+	// capacityReservationExpiryDays is the time left until a limited CapacityReservation ends.
+	capacityReservationExpiryDays = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ec2instance_capacity_reservation_expiry_days",
+		Help: "Days until the capacity reservation ends. Not reported for reservations without an end date.",
+	}, []string{"namespace", "name", "reservation_id"})
+
+	// reconcileTotal counts Ec2Instance reconciles by whether they returned an error.
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ec2instance_reconcile_total",
+		Help: "Number of Ec2Instance reconciles, by result (success or error).",
+	}, []string{"result"})
+
+	// reconcileDuration is the time an Ec2Instance reconcile takes, AWS calls included.
+	reconcileDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ec2instance_reconcile_duration_seconds",
+		Help:    "Time taken to reconcile an Ec2Instance, including the AWS calls made for it.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	})
+
+	// awsAPICalls counts the AWS API calls the operator makes, so throttling can be traced to a caller.
+	awsAPICalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ec2instance_aws_api_calls_total",
+		Help: "Number of AWS API calls made by the operator, by operation (e.g. RunInstances).",
+	}, []string{"operation"})
+
+	// awsRetries counts the AWS calls repeated after a transient error.
+	awsRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ec2instance_aws_retries_total",
+		Help: "Number of AWS calls retried after a transient error, by operation.",
+	}, []string{"operation"})
+
+	// instancesByState is the number of Ec2Instances in each instance state.
+	instancesByState = &instanceStateCollector{desc: prometheus.NewDesc(
+		"ec2instance_instances_total",
+		"Number of Ec2Instances by the state of their EC2 instance (e.g. running, stopped, terminated).",
+		[]string{"state"}, nil,
+	)}
+
+	// spotInterruptions counts the Spot instances AWS reclaimed, each counted once.
+	spotInterruptions = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ec2instance_spot_interruptions_total",
+		Help: "Number of Spot instances interrupted by AWS to reclaim capacity.",
+	})
+
+	// tagDriftCorrections counts the times tags changed outside the operator were put back.
+	tagDriftCorrections = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ec2instance_tag_drift_corrections_total",
+		Help: "Number of times instance tags changed outside the operator were corrected.",
+	})
+)
+
+func init() {
+	// The controller-runtime registry is served on the manager's metrics endpoint.
+	metrics.Registry.MustRegister(capacityReservationUtilization, capacityReservationExpiryDays,
+		reconcileTotal, reconcileDuration, awsAPICalls, awsRetries, instancesByState, spotInterruptions,
+		tagDriftCorrections)
+}
+
+// observeReconcile records the outcome and duration of a reconcile that started at start.
+func observeReconcile(start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	reconcileTotal.WithLabelValues(result).Inc()
+	reconcileDuration.Observe(time.Since(start).Seconds())
+}
+
+// countAWSCalls is an API option for AWS clients that counts every call in awsAPICalls. Retries of a
+// call are not counted separately.
+func countAWSCalls(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("CountAWSCalls",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			awsAPICalls.WithLabelValues(awsmiddleware.GetOperationName(ctx)).Inc()
+			return next.HandleInitialize(ctx, in)
+		}), middleware.Before)
+}
+
+// instanceStateCounts returns how many of instances are in each state. Instances that have not
+// been launched yet are left out.
+func instanceStateCounts(instances []computev1.Ec2Instance) map[string]int {
+	counts := map[string]int{}
+	for _, inst := range instances {
+		if inst.Status.State != "" {
+			// The self-healing path records "Terminated"; AWS reports "terminated".
+			counts[strings.ToLower(inst.Status.State)]++
+		}
+	}
+	return counts
+}
+
+// instanceStateCollectTimeout bounds the cache list of a scrape, e.g. while the cache is still syncing.
+const instanceStateCollectTimeout = 5 * time.Second
+
+// instanceStateCollector counts the Ec2Instances by state when the metrics are scraped, rather than
+// on every reconcile. The list is served from the cache.
+type instanceStateCollector struct {
+	desc *prometheus.Desc
+
+	mu     sync.RWMutex
+	reader client.Reader
+}
+
+// setReader makes the collector count the Ec2Instances reader lists. Until it is called nothing is reported.
+func (c *instanceStateCollector) setReader(reader client.Reader) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reader = reader
+}
+
+// Describe implements prometheus.Collector.
+func (c *instanceStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector. States no instance is in are not reported.
+func (c *instanceStateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	reader := c.reader
+	c.mu.RUnlock()
+	if reader == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), instanceStateCollectTimeout)
+	defer cancel()
+	instances := &computev1.Ec2InstanceList{}
+	if err := reader.List(ctx, instances); err != nil {
+		log.Log.Error(err, "Failed to list Ec2Instances for the instance gauge")
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for state, count := range instanceStateCounts(instances.Items) {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), state)
+	}
+}
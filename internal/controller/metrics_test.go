@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Instance gauge", func() {
+	It("should count launched instances by state", func() {
+		instances := []computev1.Ec2Instance{
+			{Status: computev1.Ec2InstanceStatus{State: "running"}},
+			{Status: computev1.Ec2InstanceStatus{State: "running"}},
+			{Status: computev1.Ec2InstanceStatus{State: "Terminated"}},
+			{Status: computev1.Ec2InstanceStatus{State: "terminated"}},
+			{},
+		}
+		Expect(instanceStateCounts(instances)).To(Equal(map[string]int{"running": 2, "terminated": 2}))
+	})
+
+	It("should count the instances when it is collected", func() {
+		scheme := runtime.NewScheme()
+		Expect(computev1.AddToScheme(scheme)).To(Succeed())
+		instance := func(name, state string) *computev1.Ec2Instance {
+			return &computev1.Ec2Instance{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+				Status:     computev1.Ec2InstanceStatus{State: state},
+			}
+		}
+		reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			instance("web-1", "running"), instance("web-2", "running"), instance("db", "stopped"),
+		).Build()
+
+		collector := &instanceStateCollector{desc: prometheus.NewDesc("ec2instance_instances_total", "Number of Ec2Instances.", []string{"state"}, nil)}
+		Expect(testutil.CollectAndCount(collector)).To(BeZero())
+
+		collector.setReader(reader)
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP ec2instance_instances_total Number of Ec2Instances.
+# TYPE ec2instance_instances_total gauge
+ec2instance_instances_total{state="running"} 2
+ec2instance_instances_total{state="stopped"} 1
+`))).To(Succeed())
+	})
+})
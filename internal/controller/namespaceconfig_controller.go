@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// NamespaceConfigReconciler keeps the spend reported by each NamespaceConfig in line with the
+// Ec2Instances of its namespace.
+type NamespaceConfigReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=namespaceconfigs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=namespaceconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch
+
+// Reconcile recomputes the monthly spend of the namespace.
+func (r *NamespaceConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	config := &computev1.NamespaceConfig{}
+	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	spend, err := NamespaceMonthlySpendUSD(ctx, r.Client, config.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if config.Status.CurrentMonthlySpendUSD != spend {
+		config.Status.CurrentMonthlySpendUSD = spend
+		if err := r.Status().Update(ctx, config); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.FromContext(ctx).Info("Updated namespace spend", "monthlySpendUSD", spend, "limitUSD", config.Spec.MaxMonthlySpendUSD)
+	}
+	return ctrl.Result{}, nil
+}
+
+// GetNamespaceConfig returns the NamespaceConfig of the namespace, or an empty one when it does not exist.
+func GetNamespaceConfig(ctx context.Context, c client.Reader, namespace string) (*computev1.NamespaceConfig, error) {
+	config := &computev1.NamespaceConfig{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: computev1.NamespaceConfigName}, config)
+	if errors.IsNotFound(err) {
+		return &computev1.NamespaceConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NamespaceConfig of %s: %w", namespace, err)
+	}
+	return config, nil
+}
+
+// NamespaceMonthlySpendUSD returns the estimated monthly cost of the Ec2Instances in the namespace.
+func NamespaceMonthlySpendUSD(ctx context.Context, c client.Reader, namespace string) (float64, error) {
+	instances := &computev1.Ec2InstanceList{}
+	if err := c.List(ctx, instances, client.InNamespace(namespace)); err != nil {
+		return 0, fmt.Errorf("failed to list Ec2Instances in %s: %w", namespace, err)
+	}
+	return monthlySpendUSD(instances.Items), nil
+}
+
+// monthlySpendUSD sums the estimated cost of the instances that are running or still being
+// launched. Stopped and terminated instances, and those being deleted, cost nothing going forward.
+func monthlySpendUSD(instances []computev1.Ec2Instance) float64 {
+	var total float64
+	for i := range instances {
+		inst := &instances[i]
+		if !inst.DeletionTimestamp.IsZero() {
+			continue
+		}
+		switch inventoryState(inst) {
+		case "running", "pending":
+			if cost, ok := EstimateMonthlyCostUSD(inst); ok {
+				total += cost
+			}
+		}
+	}
+	return total
+}
+
+// SetupWithManager sets up the controller with the Manager. Every Ec2Instance event is mapped onto
+// the NamespaceConfig of its namespace.
+func (r *NamespaceConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.NamespaceConfig{}).
+		Watches(&computev1.Ec2Instance{}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, obj client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: computev1.NamespaceConfigName}}}
+			})).
+		Named("namespaceconfig").
+		Complete(r)
+}
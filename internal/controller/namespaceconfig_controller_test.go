@@ -0,0 +1,48 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("NamespaceConfig Controller", func() {
+	instance := func(state, cost string) computev1.Ec2Instance {
+		return computev1.Ec2Instance{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{estimatedMonthlyCostAnnotation: cost}},
+			Status:     computev1.Ec2InstanceStatus{State: state},
+		}
+	}
+
+	It("should count running and launching instances only", func() {
+		deleting := instance("running", "1000")
+		now := metav1.Now()
+		deleting.DeletionTimestamp = &now
+		instances := []computev1.Ec2Instance{
+			instance("running", "100"),
+			instance("", "50"),
+			instance("stopped", "200"),
+			instance("terminated", "300"),
+			deleting,
+		}
+		Expect(monthlySpendUSD(instances)).To(Equal(150.0))
+	})
+})
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// networkInterfaceSpecifications returns the interfaces of spec.networkInterfaces for RunInstances.
+// The interfaces are created with the instance and deleted with it.
+func networkInterfaceSpecifications(interfaces []computev1.NetworkInterfaceSpec) []ec2types.InstanceNetworkInterfaceSpecification {
+	specifications := make([]ec2types.InstanceNetworkInterfaceSpecification, 0, len(interfaces))
+	for _, ni := range interfaces {
+		specification := ec2types.InstanceNetworkInterfaceSpecification{
+			DeviceIndex:         aws.Int32(ni.DeviceIndex),
+			SubnetId:            aws.String(ni.SubnetID),
+			Groups:              ni.SecurityGroups,
+			DeleteOnTermination: aws.Bool(true),
+		}
+		for i, address := range ni.PrivateIPAddresses {
+			specification.PrivateIpAddresses = append(specification.PrivateIpAddresses, ec2types.PrivateIpAddressSpecification{
+				PrivateIpAddress: aws.String(address),
+				Primary:          aws.Bool(i == 0),
+			})
+		}
+		if ni.SecondaryPrivateIPCount > 0 {
+			specification.SecondaryPrivateIpAddressCount = aws.Int32(ni.SecondaryPrivateIPCount)
+		}
+		if ni.AssociatePublicIP {
+			specification.AssociatePublicIpAddress = aws.Bool(true)
+		}
+		specifications = append(specifications, specification)
+	}
+	return specifications
+}
+
+// attachedInterface returns the network interface of the instance at deviceIndex, or nil.
+func attachedInterface(awsInstance *ec2types.Instance, deviceIndex int32) *ec2types.InstanceNetworkInterface {
+	for i := range awsInstance.NetworkInterfaces {
+		ni := &awsInstance.NetworkInterfaces[i]
+		if ni.Attachment != nil && aws.ToInt32(ni.Attachment.DeviceIndex) == deviceIndex {
+			return ni
+		}
+	}
+	return nil
+}
+
+// networkInterfacesDrifted reports whether an interface of spec.networkInterfaces is missing from
+// the instance or is in another subnet.
+func networkInterfacesDrifted(ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) bool {
+	for _, ni := range ec2Instance.Spec.NetworkInterfaces {
+		attached := attachedInterface(awsInstance, ni.DeviceIndex)
+		if attached == nil || aws.ToString(attached.SubnetId) != ni.SubnetID {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileInterfaceSourceDestCheck sets the source/destination check of each interface of
+// spec.networkInterfaces that asks for one, like reconcileSourceDestCheck does for the instance.
+func reconcileInterfaceSourceDestCheck(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	for _, ni := range ec2Instance.Spec.NetworkInterfaces {
+		attached := attachedInterface(awsInstance, ni.DeviceIndex)
+		if ni.SourceDestCheck == nil || attached == nil ||
+			(attached.SourceDestCheck != nil && *attached.SourceDestCheck == *ni.SourceDestCheck) {
+			continue
+		}
+		_, err := instanceAWSClient(ec2Instance).ModifyNetworkInterfaceAttribute(ctx, &ec2.ModifyNetworkInterfaceAttributeInput{
+			NetworkInterfaceId: attached.NetworkInterfaceId,
+			SourceDestCheck:    &ec2types.AttributeBooleanValue{Value: ni.SourceDestCheck},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set source/destination check of %s: %w", aws.ToString(attached.NetworkInterfaceId), err)
+		}
+		log.FromContext(ctx).Info("Corrected source/destination check", "networkInterfaceID", aws.ToString(attached.NetworkInterfaceId),
+			"deviceIndex", ni.DeviceIndex, "sourceDestCheck", *ni.SourceDestCheck)
+	}
+	return nil
+}
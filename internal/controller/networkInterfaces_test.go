@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Network interfaces", func() {
+	attached := func(deviceIndex int32, subnetID string) ec2types.InstanceNetworkInterface {
+		return ec2types.InstanceNetworkInterface{
+			SubnetId:   aws.String(subnetID),
+			Attachment: &ec2types.InstanceNetworkInterfaceAttachment{DeviceIndex: aws.Int32(deviceIndex)},
+		}
+	}
+
+	It("should launch the interfaces with the first private IP as the primary one", func() {
+		specifications := networkInterfaceSpecifications([]computev1.NetworkInterfaceSpec{
+			{DeviceIndex: 0, SubnetID: "subnet-a", SecurityGroups: []string{"sg-1"}, PrivateIPAddresses: []string{"10.0.0.10", "10.0.0.11"}},
+			{DeviceIndex: 1, SubnetID: "subnet-b", SecondaryPrivateIPCount: 2, AssociatePublicIP: true},
+		})
+		Expect(specifications).To(HaveLen(2))
+		Expect(specifications[0].Groups).To(Equal([]string{"sg-1"}))
+		Expect(specifications[0].PrivateIpAddresses).To(Equal([]ec2types.PrivateIpAddressSpecification{
+			{PrivateIpAddress: aws.String("10.0.0.10"), Primary: aws.Bool(true)},
+			{PrivateIpAddress: aws.String("10.0.0.11"), Primary: aws.Bool(false)},
+		}))
+		Expect(specifications[0].SecondaryPrivateIpAddressCount).To(BeNil())
+		Expect(specifications[0].AssociatePublicIpAddress).To(BeNil())
+		Expect(aws.ToInt32(specifications[1].DeviceIndex)).To(Equal(int32(1)))
+		Expect(aws.ToInt32(specifications[1].SecondaryPrivateIpAddressCount)).To(Equal(int32(2)))
+		Expect(aws.ToBool(specifications[1].AssociatePublicIpAddress)).To(BeTrue())
+		Expect(aws.ToBool(specifications[1].DeleteOnTermination)).To(BeTrue())
+	})
+
+	It("should report drift when an interface is missing or in another subnet", func() {
+		ec2Instance := &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{NetworkInterfaces: []computev1.NetworkInterfaceSpec{
+			{DeviceIndex: 0, SubnetID: "subnet-a"},
+			{DeviceIndex: 1, SubnetID: "subnet-b"},
+		}}}
+		awsInstance := &ec2types.Instance{NetworkInterfaces: []ec2types.InstanceNetworkInterface{attached(1, "subnet-b"), attached(0, "subnet-a")}}
+		Expect(networkInterfacesDrifted(ec2Instance, awsInstance)).To(BeFalse())
+
+		awsInstance.NetworkInterfaces = awsInstance.NetworkInterfaces[1:]
+		Expect(networkInterfacesDrifted(ec2Instance, awsInstance)).To(BeTrue())
+
+		awsInstance.NetworkInterfaces = []ec2types.InstanceNetworkInterface{attached(0, "subnet-a"), attached(1, "subnet-c")}
+		Expect(networkInterfacesDrifted(ec2Instance, awsInstance)).To(BeTrue())
+	})
+
+	It("should not report drift without spec.networkInterfaces", func() {
+		Expect(networkInterfacesDrifted(&computev1.Ec2Instance{}, &ec2types.Instance{})).To(BeFalse())
+	})
+})
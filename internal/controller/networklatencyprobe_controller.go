@@ -0,0 +1,381 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// networkLatencyProbeFinalizer makes sure the network insights path is deleted with the probe.
+const networkLatencyProbeFinalizer = "networklatencyprobe.compute.cloud.com"
+
+// latencySamples is the number of probes sent per measurement.
+const latencySamples = 20
+
+// latencySampleRE matches one round trip in the output of the probe script, as printed by ping.
+var latencySampleRE = regexp.MustCompile(`time[=<]([0-9.]+) ?ms`)
+
+// NetworkLatencyProbeReconciler measures the latency between two Ec2Instances. Reachability
+// Analyzer verifies that the network path exists, but it does not report latency, so the latency
+// itself is measured from the source instance through SSM Run Command.
+type NetworkLatencyProbeReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=networklatencyprobes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=networklatencyprobes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=networklatencyprobes/finalizers,verbs=update
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile runs a measurement every spec.probeIntervalSeconds: it starts a path analysis and the
+// probe command together, then polls both until they are done.
+func (r *NetworkLatencyProbeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	probe := &computev1.NetworkLatencyProbe{}
+	if err := r.Get(ctx, req.NamespacedName, probe); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	source, err := r.probeInstance(ctx, probe, probe.Spec.SourceEc2InstanceRef.Name)
+	if err != nil && probe.DeletionTimestamp.IsZero() {
+		return r.setProbeMessage(ctx, probe, err.Error())
+	}
+
+	if !probe.DeletionTimestamp.IsZero() {
+		if probe.Status.PathID != "" {
+			// The path lives in the region of the source; without it there is nothing left to find it by.
+			if source == nil {
+				l.Info("Source Ec2Instance is gone, leaving network insights path behind", "pathID", probe.Status.PathID)
+			} else if err := deleteNetworkInsightsPath(ctx, instanceAWSClient(source), &probe.Status); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		controllerutil.RemoveFinalizer(probe, networkLatencyProbeFinalizer)
+		if err := r.Update(ctx, probe); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	target, err := r.probeInstance(ctx, probe, probe.Spec.TargetEc2InstanceRef.Name)
+	if err != nil {
+		return r.setProbeMessage(ctx, probe, err.Error())
+	}
+	if source.Spec.Region != target.Spec.Region {
+		return r.setProbeMessage(ctx, probe, "source and target must be in the same region")
+	}
+
+	if controllerutil.AddFinalizer(probe, networkLatencyProbeFinalizer) {
+		if err := r.Update(ctx, probe); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	originalStatus := probe.Status.DeepCopy()
+	ec2Client := instanceAWSClient(source)
+
+	// A replaced instance needs a new path.
+	if probe.Status.PathID != "" && (probe.Status.SourceInstanceID != source.Status.InstanceID ||
+		probe.Status.TargetInstanceID != target.Status.InstanceID) {
+		if err := deleteNetworkInsightsPath(ctx, ec2Client, &probe.Status); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if probe.Status.PathID == "" {
+		input := &ec2.CreateNetworkInsightsPathInput{
+			Source:      aws.String(source.Status.InstanceID),
+			Destination: aws.String(target.Status.InstanceID),
+			// Reachability Analyzer only knows TCP and UDP; a TCP path without a port stands in for ICMP.
+			Protocol: ec2types.ProtocolTcp,
+			TagSpecifications: []ec2types.TagSpecification{{
+				ResourceType: ec2types.ResourceTypeNetworkInsightsPath,
+				Tags:         []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String(probe.Namespace + "/" + probe.Name)}},
+			}},
+		}
+		if probe.Spec.Protocol == computev1.LatencyProbeProtocolTCP {
+			input.DestinationPort = aws.Int32(probe.Spec.Port)
+		}
+		path, err := ec2Client.CreateNetworkInsightsPath(ctx, input)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create network insights path: %w", err)
+		}
+		probe.Status.PathID = aws.ToString(path.NetworkInsightsPath.NetworkInsightsPathId)
+		probe.Status.SourceInstanceID = source.Status.InstanceID
+		probe.Status.TargetInstanceID = target.Status.InstanceID
+		l.Info("Created network insights path", "pathID", probe.Status.PathID)
+	}
+
+	interval := time.Duration(probe.Spec.ProbeIntervalSeconds) * time.Second
+	if probe.Status.CommandID == "" {
+		if last := probe.Status.LastMeasuredAt; last != nil && time.Since(last.Time) < interval {
+			return r.updateProbeStatus(ctx, probe, originalStatus, time.Until(last.Add(interval)))
+		}
+		if err := r.startMeasurement(ctx, ec2Client, probe, source, target); err != nil {
+			probe.Status.Message = err.Error()
+			if _, updateErr := r.updateProbeStatus(ctx, probe, originalStatus, 0); updateErr != nil {
+				l.Error(updateErr, "Failed to update status")
+			}
+			return ctrl.Result{}, err
+		}
+		return r.updateProbeStatus(ctx, probe, originalStatus, 15*time.Second)
+	}
+
+	// The measurement ends when the command is collected, so the analysis has to be collected first.
+	analysisDone, err := r.collectAnalysis(ctx, ec2Client, probe)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !analysisDone {
+		return r.updateProbeStatus(ctx, probe, originalStatus, 15*time.Second)
+	}
+	commandDone, err := r.collectLatency(ctx, source, probe)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !commandDone {
+		return r.updateProbeStatus(ctx, probe, originalStatus, 15*time.Second)
+	}
+	return r.updateProbeStatus(ctx, probe, originalStatus, interval)
+}
+
+// probeInstance returns the named Ec2Instance once it has an instance to probe.
+func (r *NetworkLatencyProbeReconciler) probeInstance(ctx context.Context, probe *computev1.NetworkLatencyProbe, name string) (*computev1.Ec2Instance, error) {
+	instance := &computev1.Ec2Instance{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: probe.Namespace, Name: name}, instance); err != nil {
+		return nil, fmt.Errorf("failed to get Ec2Instance %s: %w", name, err)
+	}
+	if instance.Status.InstanceID == "" || instance.Status.PrivateIP == "" {
+		return instance, fmt.Errorf("the Ec2Instance %s has not been launched yet", name)
+	}
+	return instance, nil
+}
+
+// startMeasurement replaces the previous path analysis with a new one and sends the probes.
+func (r *NetworkLatencyProbeReconciler) startMeasurement(ctx context.Context, ec2Client *ec2.Client, probe *computev1.NetworkLatencyProbe, source, target *computev1.Ec2Instance) error {
+	// Only the latest analysis is kept; completed ones just pile up otherwise.
+	if probe.Status.PathAnalysisID != "" {
+		_, err := ec2Client.DeleteNetworkInsightsAnalysis(ctx, &ec2.DeleteNetworkInsightsAnalysisInput{
+			NetworkInsightsAnalysisId: aws.String(probe.Status.PathAnalysisID),
+		})
+		if err != nil && !strings.Contains(err.Error(), "NotFound") {
+			return fmt.Errorf("failed to delete network insights analysis: %w", err)
+		}
+		probe.Status.PathAnalysisID = ""
+	}
+	analysis, err := ec2Client.StartNetworkInsightsAnalysis(ctx, &ec2.StartNetworkInsightsAnalysisInput{
+		NetworkInsightsPathId: aws.String(probe.Status.PathID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start network insights analysis: %w", err)
+	}
+	probe.Status.PathAnalysisID = aws.ToString(analysis.NetworkInsightsAnalysis.NetworkInsightsAnalysisId)
+
+	command, err := instanceSSMClient(source).SendCommand(ctx, &ssm.SendCommandInput{
+		DocumentName: aws.String("AWS-RunShellScript"),
+		InstanceIds:  []string{source.Status.InstanceID},
+		Comment:      aws.String("Latency probe " + probe.Namespace + "/" + probe.Name),
+		Parameters: map[string][]string{
+			"commands": {latencyProbeScript(probe.Spec.Protocol, target.Status.PrivateIP, probe.Spec.Port, latencySamples)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send latency probe command: %w", err)
+	}
+	probe.Status.CommandID = aws.ToString(command.Command.CommandId)
+	log.FromContext(ctx).Info("Started latency measurement", "analysisID", probe.Status.PathAnalysisID, "commandID", probe.Status.CommandID)
+	return nil
+}
+
+// collectAnalysis records the outcome of the path analysis and reports whether it has finished.
+func (r *NetworkLatencyProbeReconciler) collectAnalysis(ctx context.Context, ec2Client *ec2.Client, probe *computev1.NetworkLatencyProbe) (bool, error) {
+	result, err := ec2Client.DescribeNetworkInsightsAnalyses(ctx, &ec2.DescribeNetworkInsightsAnalysesInput{
+		NetworkInsightsAnalysisIds: []string{probe.Status.PathAnalysisID},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to describe network insights analysis: %w", err)
+	}
+	if len(result.NetworkInsightsAnalyses) == 0 {
+		return true, nil
+	}
+	analysis := result.NetworkInsightsAnalyses[0]
+	switch analysis.Status {
+	case ec2types.AnalysisStatusRunning:
+		return false, nil
+	case ec2types.AnalysisStatusSucceeded:
+		reachable := aws.ToBool(analysis.NetworkPathFound)
+		if !reachable && (probe.Status.Reachable == nil || *probe.Status.Reachable) {
+			r.Recorder.Eventf(probe, corev1.EventTypeWarning, "PathUnreachable",
+				"No network path from %s to %s", probe.Status.SourceInstanceID, probe.Status.TargetInstanceID)
+		}
+		probe.Status.Reachable = &reachable
+	default:
+		probe.Status.Reachable = nil
+		log.FromContext(ctx).Info("Network insights analysis failed", "message", aws.ToString(analysis.StatusMessage))
+	}
+	return true, nil
+}
+
+// collectLatency records the latency measured by the probe command and reports whether it has finished.
+func (r *NetworkLatencyProbeReconciler) collectLatency(ctx context.Context, source *computev1.Ec2Instance, probe *computev1.NetworkLatencyProbe) (bool, error) {
+	invocation, err := instanceSSMClient(source).GetCommandInvocation(ctx, &ssm.GetCommandInvocationInput{
+		CommandId:  aws.String(probe.Status.CommandID),
+		InstanceId: aws.String(source.Status.InstanceID),
+	})
+	if err != nil {
+		// The invocation shows up a moment after the command was sent.
+		if strings.Contains(err.Error(), "InvocationDoesNotExist") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get latency probe result: %w", err)
+	}
+	switch invocation.Status {
+	case ssmtypes.CommandInvocationStatusPending, ssmtypes.CommandInvocationStatusInProgress,
+		ssmtypes.CommandInvocationStatusDelayed, ssmtypes.CommandInvocationStatusCancelling:
+		return false, nil
+	}
+
+	now := metav1.Now()
+	probe.Status.LastMeasuredAt = &now
+	probe.Status.CommandID = ""
+
+	samples := parseLatencySamples(aws.ToString(invocation.StandardOutputContent))
+	if invocation.Status != ssmtypes.CommandInvocationStatusSuccess || len(samples) == 0 {
+		probe.Status.Message = fmt.Sprintf("latency probe %s: %s", strings.ToLower(string(invocation.Status)),
+			strings.TrimSpace(aws.ToString(invocation.StandardErrorContent)))
+		return true, nil
+	}
+
+	probe.Status.LatencyP50Ms = latencyPercentile(samples, 50)
+	probe.Status.LatencyP99Ms = latencyPercentile(samples, 99)
+	probe.Status.Message = ""
+	log.FromContext(ctx).Info("Measured latency", "p50", probe.Status.LatencyP50Ms, "p99", probe.Status.LatencyP99Ms)
+
+	if threshold := probe.Spec.AlertThresholdMs; threshold > 0 && probe.Status.LatencyP99Ms > threshold {
+		r.Recorder.Eventf(probe, corev1.EventTypeWarning, "LatencyThresholdExceeded",
+			"p99 latency %.3fms is above the threshold of %.3fms", probe.Status.LatencyP99Ms, threshold)
+	}
+	return true, nil
+}
+
+// latencyProbeScript returns the shell script that probes ip count times and prints one
+// time=<ms> ms line per successful probe, the way ping does.
+func latencyProbeScript(protocol computev1.LatencyProbeProtocol, ip string, port int32, count int) string {
+	if protocol == computev1.LatencyProbeProtocolTCP {
+		return fmt.Sprintf(`for i in $(seq %d); do
+  start=$(date +%%s%%N)
+  if timeout 1 bash -c '</dev/tcp/%s/%d' 2>/dev/null; then
+    echo "$start $(date +%%s%%N)" | awk '{printf "time=%%.3f ms\n", ($2-$1)/1e6}'
+  fi
+  sleep 0.2
+done`, count, ip, port)
+	}
+	return fmt.Sprintf("ping -c %d -i 0.2 -W 1 %s", count, ip)
+}
+
+// parseLatencySamples returns the round trip times in milliseconds found in the probe output.
+func parseLatencySamples(output string) []float64 {
+	var samples []float64
+	for _, match := range latencySampleRE.FindAllStringSubmatch(output, -1) {
+		if ms, err := strconv.ParseFloat(match[1], 64); err == nil {
+			samples = append(samples, ms)
+		}
+	}
+	return samples
+}
+
+// latencyPercentile returns the p-th percentile of samples using the nearest-rank method.
+func latencyPercentile(samples []float64, p float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// deleteNetworkInsightsPath deletes the path together with its analysis, which has to go first.
+func deleteNetworkInsightsPath(ctx context.Context, ec2Client *ec2.Client, status *computev1.NetworkLatencyProbeStatus) error {
+	if status.PathAnalysisID != "" {
+		_, err := ec2Client.DeleteNetworkInsightsAnalysis(ctx, &ec2.DeleteNetworkInsightsAnalysisInput{
+			NetworkInsightsAnalysisId: aws.String(status.PathAnalysisID),
+		})
+		if err != nil && !strings.Contains(err.Error(), "NotFound") {
+			return fmt.Errorf("failed to delete network insights analysis: %w", err)
+		}
+		status.PathAnalysisID = ""
+	}
+	_, err := ec2Client.DeleteNetworkInsightsPath(ctx, &ec2.DeleteNetworkInsightsPathInput{
+		NetworkInsightsPathId: aws.String(status.PathID),
+	})
+	if err != nil && !strings.Contains(err.Error(), "NotFound") {
+		return fmt.Errorf("failed to delete network insights path: %w", err)
+	}
+	log.FromContext(ctx).Info("Deleted network insights path", "pathID", status.PathID)
+	status.PathID = ""
+	status.Reachable = nil
+	return nil
+}
+
+// setProbeMessage records why the probe cannot run and checks again later.
+func (r *NetworkLatencyProbeReconciler) setProbeMessage(ctx context.Context, probe *computev1.NetworkLatencyProbe, message string) (ctrl.Result, error) {
+	if probe.Status.Message != message {
+		probe.Status.Message = message
+		if err := r.Status().Update(ctx, probe); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: time.Minute}, nil
+}
+
+// updateProbeStatus writes the status when it changed and requeues after wait.
+func (r *NetworkLatencyProbeReconciler) updateProbeStatus(ctx context.Context, probe *computev1.NetworkLatencyProbe, originalStatus *computev1.NetworkLatencyProbeStatus, wait time.Duration) (ctrl.Result, error) {
+	if !equality.Semantic.DeepEqual(*originalStatus, probe.Status) {
+		if err := r.Status().Update(ctx, probe); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: wait}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NetworkLatencyProbeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.NetworkLatencyProbe{}).
+		Named("networklatencyprobe").
+		Complete(r)
+}
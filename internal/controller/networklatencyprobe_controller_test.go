@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("NetworkLatencyProbe Controller", func() {
+	Context("When reading the probe output", func() {
+		It("should parse the round trips printed by ping", func() {
+			output := `PING 10.0.1.12 (10.0.1.12) 56(84) bytes of data.
+64 bytes from 10.0.1.12: icmp_seq=1 ttl=64 time=0.412 ms
+64 bytes from 10.0.1.12: icmp_seq=2 ttl=64 time=0.198 ms
+64 bytes from 10.0.1.12: icmp_seq=3 ttl=64 time<1 ms
+
+--- 10.0.1.12 ping statistics ---
+3 packets transmitted, 3 received, 0% packet loss, time 402ms
+rtt min/avg/max/mdev = 0.198/0.305/0.412/0.107 ms`
+			Expect(parseLatencySamples(output)).To(Equal([]float64{0.412, 0.198, 1}))
+		})
+
+		It("should parse the output of the TCP probe", func() {
+			Expect(parseLatencySamples("time=1.250 ms\ntime=0.750 ms\n")).To(Equal([]float64{1.25, 0.75}))
+		})
+	})
+
+	Context("When computing percentiles", func() {
+		samples := []float64{5, 1, 4, 2, 3, 6, 7, 8, 9, 10}
+
+		It("should use the nearest rank", func() {
+			Expect(latencyPercentile(samples, 50)).To(Equal(5.0))
+			Expect(latencyPercentile(samples, 99)).To(Equal(10.0))
+			Expect(latencyPercentile(samples, 0)).To(Equal(1.0))
+		})
+
+		It("should return zero without samples", func() {
+			Expect(latencyPercentile(nil, 99)).To(BeZero())
+		})
+	})
+
+	Context("When building the probe script", func() {
+		It("should ping for ICMP probes", func() {
+			Expect(latencyProbeScript(computev1.LatencyProbeProtocolICMP, "10.0.1.12", 0, 20)).
+				To(Equal("ping -c 20 -i 0.2 -W 1 10.0.1.12"))
+		})
+
+		It("should open connections to the port for TCP probes", func() {
+			script := latencyProbeScript(computev1.LatencyProbeProtocolTCP, "10.0.1.12", 5432, 20)
+			Expect(script).To(ContainSubstring("seq 20"))
+			Expect(script).To(ContainSubstring("/dev/tcp/10.0.1.12/5432"))
+		})
+	})
+})
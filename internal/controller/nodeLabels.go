@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// Labels set on the Node of an instance that joined an EKS cluster, for use in nodeAffinity rules.
+const (
+	nodeLabelInstanceType     = "node.ec2.compute.cloud.com/instance-type"
+	nodeLabelInstanceID       = "node.ec2.compute.cloud.com/instance-id"
+	nodeLabelAvailabilityZone = "node.ec2.compute.cloud.com/availability-zone"
+	nodeLabelRegion           = "node.ec2.compute.cloud.com/region"
+)
+
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;patch
+
+// nodeLabels returns the labels that mirror the instance onto its Node. The instance type is taken
+// from AWS so the label follows a stop-start resize.
+func nodeLabels(ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) map[string]string {
+	labels := map[string]string{
+		nodeLabelInstanceType: string(awsInstance.InstanceType),
+		nodeLabelInstanceID:   ec2Instance.Status.InstanceID,
+		nodeLabelRegion:       ec2Instance.Spec.Region,
+	}
+	if awsInstance.Placement != nil {
+		labels[nodeLabelAvailabilityZone] = aws.ToString(awsInstance.Placement.AvailabilityZone)
+	}
+	return labels
+}
+
+// nodeForInstance returns the Node the kubelet registered for instanceID. The AWS cloud provider
+// records the instance ID in spec.providerID as aws:///<availability-zone>/<instance-id>.
+func nodeForInstance(nodes []corev1.Node, instanceID string) *corev1.Node {
+	for i := range nodes {
+		if strings.HasSuffix(nodes[i].Spec.ProviderID, "/"+instanceID) {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
+
+// reconcileNodeLabels mirrors the instance metadata onto its Node when the instance is an EKS
+// worker. Until the node has joined there is nothing to label and the next resync tries again.
+func (r *Ec2InstanceReconciler) reconcileNodeLabels(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	if ec2Instance.Spec.EKSClusterRef == nil {
+		return nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	node := nodeForInstance(nodes.Items, ec2Instance.Status.InstanceID)
+	if node == nil {
+		return nil
+	}
+
+	desired := nodeLabels(ec2Instance, awsInstance)
+	changed := false
+	for k, v := range desired {
+		if node.Labels[k] != v {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	for k, v := range desired {
+		node.Labels[k] = v
+	}
+	if err := r.Patch(ctx, node, patch); err != nil {
+		return fmt.Errorf("failed to label node %s: %w", node.Name, err)
+	}
+	log.FromContext(ctx).Info("Updated node labels", "node", node.Name, "instanceType", desired[nodeLabelInstanceType])
+	return nil
+}
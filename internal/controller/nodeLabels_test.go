@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Node labels", func() {
+	It("should find the node by the instance ID in its provider ID", func() {
+		nodes := []corev1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Spec: corev1.NodeSpec{ProviderID: "aws:///us-east-1a/i-0aaa"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "b"}, Spec: corev1.NodeSpec{ProviderID: "aws:///us-east-1b/i-0bbb"}},
+		}
+		Expect(nodeForInstance(nodes, "i-0bbb").Name).To(Equal("b"))
+		Expect(nodeForInstance(nodes, "i-0ccc")).To(BeNil())
+	})
+
+	It("should take the instance type from AWS", func() {
+		instance := &computev1.Ec2Instance{
+			Spec:   computev1.Ec2InstanceSpec{InstanceType: "t3.micro", Region: "us-east-1"},
+			Status: computev1.Ec2InstanceStatus{InstanceID: "i-0aaa"},
+		}
+		awsInstance := &ec2types.Instance{
+			InstanceType: ec2types.InstanceTypeT3Large,
+			Placement:    &ec2types.Placement{AvailabilityZone: aws.String("us-east-1a")},
+		}
+		Expect(nodeLabels(instance, awsInstance)).To(Equal(map[string]string{
+			nodeLabelInstanceType:     "t3.large",
+			nodeLabelInstanceID:       "i-0aaa",
+			nodeLabelAvailabilityZone: "us-east-1a",
+			nodeLabelRegion:           "us-east-1",
+		}))
+	})
+})
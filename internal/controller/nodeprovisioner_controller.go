@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// nodeProvisionerConfigMap is the ConfigMap that turns on node provisioning in its namespace
+	// and describes the instances to launch.
+	nodeProvisionerConfigMap = "ec2-node-provisioner"
+	// provisionedForPodLabel is set on Ec2Instances launched for a pending pod to the UID of the pod.
+	provisionedForPodLabel = "ec2instance.compute.cloud.com/provisioned-for-pod"
+	// defaultMaxProvisionedInstances caps the provisioned instances of a namespace when the
+	// ConfigMap does not set maxInstances.
+	defaultMaxProvisionedInstances = 10
+)
+
+// NodeProvisionerReconciler launches an Ec2Instance for every pending pod that cannot be scheduled
+// because no node matches its required instance type or availability zone. It only acts in
+// namespaces with an ec2-node-provisioner ConfigMap, which looks like:
+//
+//	region: us-east-1
+//	amiId: ami-0123456789abcdef0
+//	eksCluster: my-cluster
+//	defaultInstanceType: m5.large    # for pods that only ask for an availability zone
+//	subnet.us-east-1a: subnet-aaaa   # one subnet per availability zone
+//	securityGroups: sg-1,sg-2
+//	userData: <bootstrap script that joins the cluster>
+//	maxInstances: "10"
+//
+// The instances stay when the pod goes away; removing idle nodes is left to the cluster operator.
+type NodeProvisionerReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile creates the Ec2Instance a pending pod needs, once per pod.
+func (r *NodeProvisionerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, req.NamespacedName, pod); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !podUnschedulable(pod) {
+		return ctrl.Result{}, nil
+	}
+	instanceType, zone, ok := podInstanceRequirements(pod)
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+
+	config := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: nodeProvisionerConfigMap}, config); err != nil {
+		// No ConfigMap, no provisioning in this namespace.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	provisioned := &computev1.Ec2InstanceList{}
+	if err := r.List(ctx, provisioned, client.InNamespace(pod.Namespace), client.HasLabels{provisionedForPodLabel}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list provisioned instances: %w", err)
+	}
+	for _, inst := range provisioned.Items {
+		if inst.Labels[provisionedForPodLabel] == string(pod.UID) {
+			return ctrl.Result{}, nil
+		}
+	}
+	limit := defaultMaxProvisionedInstances
+	if value, err := strconv.Atoi(config.Data["maxInstances"]); err == nil {
+		limit = value
+	}
+	if len(provisioned.Items) >= limit {
+		r.Recorder.Eventf(pod, corev1.EventTypeWarning, "NodeProvisioningLimitReached",
+			"Not launching an instance: %d of %d provisioned instances already exist", len(provisioned.Items), limit)
+		return ctrl.Result{}, nil
+	}
+
+	inst, err := provisionedInstance(pod, config.Data, instanceType, zone)
+	if err != nil {
+		r.Recorder.Eventf(pod, corev1.EventTypeWarning, "NodeProvisioningFailed", "Cannot launch an instance: %v", err)
+		return ctrl.Result{}, nil
+	}
+	if err := r.Create(ctx, inst); err != nil {
+		// The name is derived from the pod, so a lost race ends here.
+		if errors.IsAlreadyExists(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to create Ec2Instance for pod: %w", err)
+	}
+	l.Info("Provisioned Ec2Instance for pending pod", "pod", pod.Name, "ec2Instance", inst.Name,
+		"instanceType", inst.Spec.InstanceType, "availabilityZone", inst.Spec.AvailabilityZone)
+	r.Recorder.Eventf(pod, corev1.EventTypeNormal, "NodeProvisioned", "Launching Ec2Instance %s (%s in %s)",
+		inst.Name, inst.Spec.InstanceType, inst.Spec.AvailabilityZone)
+	return ctrl.Result{}, nil
+}
+
+// podUnschedulable reports whether the scheduler gave up on placing the pod for now.
+func podUnschedulable(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled {
+			return condition.Status == corev1.ConditionFalse && condition.Reason == corev1.PodReasonUnschedulable
+		}
+	}
+	return false
+}
+
+// podInstanceRequirements returns the instance type and availability zone the pod requires through
+// its node selector or required node affinity on the labels the operator puts on its nodes. With
+// several allowed values the first one is used; ok is false when the pod requires neither.
+func podInstanceRequirements(pod *corev1.Pod) (instanceType, zone string, ok bool) {
+	instanceType = pod.Spec.NodeSelector[nodeLabelInstanceType]
+	zone = pod.Spec.NodeSelector[nodeLabelAvailabilityZone]
+
+	// Only the first term counts: terms are ORed, so satisfying one is enough.
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil &&
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		terms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		if len(terms) > 0 {
+			for _, expr := range terms[0].MatchExpressions {
+				if expr.Operator != corev1.NodeSelectorOpIn || len(expr.Values) == 0 {
+					continue
+				}
+				switch expr.Key {
+				case nodeLabelInstanceType:
+					instanceType = expr.Values[0]
+				case nodeLabelAvailabilityZone:
+					zone = expr.Values[0]
+				}
+			}
+		}
+	}
+	return instanceType, zone, instanceType != "" || zone != ""
+}
+
+// provisionedInstance builds the Ec2Instance for pod from the provisioner ConfigMap data.
+func provisionedInstance(pod *corev1.Pod, config map[string]string, instanceType, zone string) (*computev1.Ec2Instance, error) {
+	if instanceType == "" {
+		instanceType = config["defaultInstanceType"]
+	}
+	if config["region"] == "" || config["amiId"] == "" || config["eksCluster"] == "" || instanceType == "" {
+		return nil, fmt.Errorf("%s must set region, amiId, eksCluster and defaultInstanceType", nodeProvisionerConfigMap)
+	}
+	subnet := config["subnet"]
+	if zone != "" {
+		subnet = config["subnet."+zone]
+		if subnet == "" {
+			return nil, fmt.Errorf("%s has no subnet.%s", nodeProvisionerConfigMap, zone)
+		}
+	}
+	var userData *computev1.UserDataSpec
+	if config["userData"] != "" {
+		userData = &computev1.UserDataSpec{Inline: config["userData"]}
+	}
+	var securityGroups []string
+	for _, group := range strings.Split(config["securityGroups"], ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			securityGroups = append(securityGroups, group)
+		}
+	}
+
+	return &computev1.Ec2Instance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "node-" + string(pod.UID),
+			Namespace: pod.Namespace,
+			Labels:    map[string]string{provisionedForPodLabel: string(pod.UID)},
+		},
+		Spec: computev1.Ec2InstanceSpec{
+			InstanceType:     instanceType,
+			AMIId:            config["amiId"],
+			Region:           config["region"],
+			AvailabilityZone: zone,
+			Subnet:           subnet,
+			SecurityGroups:   securityGroups,
+			KeyPair:          config["keyPair"],
+			UserData:         userData,
+			EKSClusterRef:    &computev1.EKSClusterReference{Name: config["eksCluster"]},
+			AutoRecovery:     true,
+			Tags:             map[string]string{"Name": "node-" + pod.Namespace + "-" + pod.Name},
+		},
+	}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager. Only pods that wait for a node are
+// watched; the rest of the pod traffic is filtered out before it reaches the queue.
+func (r *NodeProvisionerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	pending := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		pod, ok := obj.(*corev1.Pod)
+		return ok && pod.Status.Phase == corev1.PodPending && pod.Spec.NodeName == ""
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Pod{}, builder.WithPredicates(pending)).
+		Named("nodeprovisioner").
+		Complete(r)
+}
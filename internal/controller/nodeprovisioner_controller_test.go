@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("NodeProvisioner Controller", func() {
+	unschedulable := corev1.PodStatus{
+		Phase: corev1.PodPending,
+		Conditions: []corev1.PodCondition{{
+			Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable,
+		}},
+	}
+	affinity := func(exprs ...corev1.NodeSelectorRequirement) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: exprs}},
+			},
+		}}
+	}
+	config := map[string]string{
+		"region":            "us-east-1",
+		"amiId":             "ami-0123456789abcdef0",
+		"eksCluster":        "prod",
+		"subnet.us-east-1b": "subnet-b",
+		"securityGroups":    "sg-1, sg-2",
+	}
+
+	Context("When looking at a pod", func() {
+		It("should only act on pods the scheduler could not place", func() {
+			Expect(podUnschedulable(&corev1.Pod{Status: unschedulable})).To(BeTrue())
+			Expect(podUnschedulable(&corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}})).To(BeFalse())
+			Expect(podUnschedulable(&corev1.Pod{Spec: corev1.PodSpec{NodeName: "node-1"}, Status: unschedulable})).To(BeFalse())
+		})
+
+		It("should read the instance type and zone from the required node affinity", func() {
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Affinity: affinity(
+				corev1.NodeSelectorRequirement{Key: nodeLabelInstanceType, Operator: corev1.NodeSelectorOpIn, Values: []string{"g5.xlarge", "g5.2xlarge"}},
+				corev1.NodeSelectorRequirement{Key: nodeLabelAvailabilityZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"us-east-1b"}},
+			)}}
+			instanceType, zone, ok := podInstanceRequirements(pod)
+			Expect(ok).To(BeTrue())
+			Expect(instanceType).To(Equal("g5.xlarge"))
+			Expect(zone).To(Equal("us-east-1b"))
+		})
+
+		It("should read the node selector", func() {
+			pod := &corev1.Pod{Spec: corev1.PodSpec{NodeSelector: map[string]string{nodeLabelInstanceType: "c5.large"}}}
+			instanceType, zone, ok := podInstanceRequirements(pod)
+			Expect(ok).To(BeTrue())
+			Expect(instanceType).To(Equal("c5.large"))
+			Expect(zone).To(BeEmpty())
+		})
+
+		It("should ignore pods without instance requirements", func() {
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Affinity: affinity(
+				corev1.NodeSelectorRequirement{Key: nodeLabelInstanceType, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"t3.micro"}},
+			)}}
+			_, _, ok := podInstanceRequirements(pod)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("When building the instance", func() {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ml", Name: "trainer", UID: "1234"}}
+
+		It("should use the subnet of the required zone", func() {
+			inst, err := provisionedInstance(pod, config, "g5.xlarge", "us-east-1b")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inst.Name).To(Equal("node-1234"))
+			Expect(inst.Namespace).To(Equal("ml"))
+			Expect(inst.Labels).To(HaveKeyWithValue(provisionedForPodLabel, "1234"))
+			Expect(inst.Spec.InstanceType).To(Equal("g5.xlarge"))
+			Expect(inst.Spec.Subnet).To(Equal("subnet-b"))
+			Expect(inst.Spec.SecurityGroups).To(Equal([]string{"sg-1", "sg-2"}))
+			Expect(inst.Spec.EKSClusterRef.Name).To(Equal("prod"))
+		})
+
+		It("should refuse a zone without a subnet", func() {
+			_, err := provisionedInstance(pod, config, "g5.xlarge", "us-east-1c")
+			Expect(err).To(MatchError(ContainSubstring("subnet.us-east-1c")))
+		})
+
+		It("should need a default instance type for zone-only pods", func() {
+			_, err := provisionedInstance(pod, config, "", "us-east-1b")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
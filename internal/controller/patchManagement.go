@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// Values of status.patchComplianceStatus.
+const (
+	patchCompliant    = "Compliant"
+	patchNonCompliant = "NonCompliant"
+)
+
+// patchGroupTag is the instance tag Patch Manager uses to pick the patch baseline.
+const patchGroupTag = "Patch Group"
+
+// patchDocument is the SSM document that scans for and installs patches.
+const patchDocument = "AWS-RunPatchBaseline"
+
+// patchComplianceStatus summarizes the patch state of an instance the way Patch Manager does:
+// anything missing, failed or waiting for a reboot makes it non-compliant.
+func patchComplianceStatus(state *ssmtypes.InstancePatchState) string {
+	if state.MissingCount > 0 || state.FailedCount > 0 ||
+		aws.ToInt32(state.InstalledPendingRebootCount) > 0 ||
+		aws.ToInt32(state.SecurityNonCompliantCount) > 0 {
+		return patchNonCompliant
+	}
+	return patchCompliant
+}
+
+// patchTaskParameters returns the AWS-RunPatchBaseline parameters of the patch task.
+func patchTaskParameters(rebootAfterPatch bool) map[string][]string {
+	reboot := "NoReboot"
+	if rebootAfterPatch {
+		reboot = "RebootIfNeeded"
+	}
+	return map[string][]string{
+		"Operation":    {"Install"},
+		"RebootOption": {reboot},
+	}
+}
+
+// reconcilePatchManagement registers the instance and a patch task in the maintenance window named
+// by spec.patchManagement, and records the patch state reported by Patch Manager. What has been
+// registered is recorded in status even on failure, so nothing is registered twice.
+func (r *Ec2InstanceReconciler) reconcilePatchManagement(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	l := log.FromContext(ctx)
+	spec := ec2Instance.Spec.PatchManagement
+	if !spec.Enabled || spec.MaintenanceWindowRef == nil {
+		if err := deregisterPatching(ctx, ec2Instance); err != nil {
+			return err
+		}
+		ec2Instance.Status.LastPatchedAt = nil
+		ec2Instance.Status.PatchComplianceStatus = ""
+		return nil
+	}
+
+	window := &computev1.MaintenanceWindow{}
+	key := types.NamespacedName{Namespace: ec2Instance.Namespace, Name: spec.MaintenanceWindowRef.Name}
+	if err := r.Get(ctx, key, window); err != nil {
+		return fmt.Errorf("failed to get maintenance window %s: %w", key.Name, err)
+	}
+	if window.Status.WindowID == "" {
+		return fmt.Errorf("maintenance window %s has not been created yet", key.Name)
+	}
+	if window.Spec.Region != ec2Instance.Spec.Region {
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "MaintenanceWindowRegionMismatch",
+			"Maintenance window %s is in %s, the instance in %s", key.Name, window.Spec.Region, ec2Instance.Spec.Region)
+		return nil
+	}
+
+	if spec.PatchBaselineID != "" {
+		if err := ensurePatchGroup(ctx, ec2Instance, awsInstance, spec.PatchBaselineID); err != nil {
+			return err
+		}
+	}
+
+	// A task cannot be moved to another window, and its parameters are simplest to replace whole.
+	if reg := ec2Instance.Status.PatchRegistration; reg != nil &&
+		(reg.WindowID != window.Status.WindowID || reg.RebootAfterPatch != spec.RebootAfterPatch) {
+		if err := deregisterPatching(ctx, ec2Instance); err != nil {
+			return err
+		}
+	}
+
+	client := ssmClient(ec2Instance.Spec.Region)
+	if ec2Instance.Status.PatchRegistration == nil {
+		ec2Instance.Status.PatchRegistration = &computev1.PatchRegistration{
+			WindowID:         window.Status.WindowID,
+			RebootAfterPatch: spec.RebootAfterPatch,
+		}
+	}
+	reg := ec2Instance.Status.PatchRegistration
+
+	if reg.WindowTargetID == "" {
+		target, err := client.RegisterTargetWithMaintenanceWindow(ctx, &ssm.RegisterTargetWithMaintenanceWindowInput{
+			WindowId:     aws.String(reg.WindowID),
+			ResourceType: ssmtypes.MaintenanceWindowResourceTypeInstance,
+			Targets:      []ssmtypes.Target{{Key: aws.String("InstanceIds"), Values: []string{ec2Instance.Status.InstanceID}}},
+			Name:         aws.String(ec2Instance.Status.InstanceID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register instance with maintenance window %s: %w", reg.WindowID, err)
+		}
+		reg.WindowTargetID = aws.ToString(target.WindowTargetId)
+		l.Info("Registered instance with maintenance window", "windowID", reg.WindowID, "windowTargetID", reg.WindowTargetID)
+	}
+
+	if reg.WindowTaskID == "" {
+		task, err := client.RegisterTaskWithMaintenanceWindow(ctx, &ssm.RegisterTaskWithMaintenanceWindowInput{
+			WindowId:       aws.String(reg.WindowID),
+			TaskArn:        aws.String(patchDocument),
+			TaskType:       ssmtypes.MaintenanceWindowTaskTypeRunCommand,
+			Targets:        []ssmtypes.Target{{Key: aws.String("WindowTargetIds"), Values: []string{reg.WindowTargetID}}},
+			Name:           aws.String("patch-" + ec2Instance.Status.InstanceID),
+			MaxConcurrency: aws.String("1"),
+			MaxErrors:      aws.String("1"),
+			TaskInvocationParameters: &ssmtypes.MaintenanceWindowTaskInvocationParameters{
+				RunCommand: &ssmtypes.MaintenanceWindowRunCommandParameters{
+					Parameters: patchTaskParameters(reg.RebootAfterPatch),
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register patch task with maintenance window %s: %w", reg.WindowID, err)
+		}
+		reg.WindowTaskID = aws.ToString(task.WindowTaskId)
+		l.Info("Registered patch task", "windowID", reg.WindowID, "windowTaskID", reg.WindowTaskID)
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "PatchingScheduled",
+			"Patches are installed in maintenance window %s", key.Name)
+	}
+
+	states, err := client.DescribeInstancePatchStates(ctx, &ssm.DescribeInstancePatchStatesInput{
+		InstanceIds: []string{ec2Instance.Status.InstanceID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe patch state: %w", err)
+	}
+	// Nothing is reported until the first scan or install has run.
+	if len(states.InstancePatchStates) == 0 {
+		return nil
+	}
+	state := &states.InstancePatchStates[0]
+	ec2Instance.Status.PatchComplianceStatus = patchComplianceStatus(state)
+	if state.Operation == ssmtypes.PatchOperationTypeInstall && state.OperationEndTime != nil {
+		patchedAt := metav1.NewTime(*state.OperationEndTime)
+		ec2Instance.Status.LastPatchedAt = &patchedAt
+	}
+	return nil
+}
+
+// ensurePatchGroup puts the instance in a patch group named after the baseline and registers the
+// baseline for that group, so Patch Manager patches the instance against it.
+func ensurePatchGroup(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance, baselineID string) error {
+	for _, tag := range awsInstance.Tags {
+		if aws.ToString(tag.Key) == patchGroupTag && aws.ToString(tag.Value) == baselineID {
+			return nil
+		}
+	}
+
+	// A patch group has one baseline; it is already registered when another instance got here first.
+	_, err := ssmClient(ec2Instance.Spec.Region).RegisterPatchBaselineForPatchGroup(ctx, &ssm.RegisterPatchBaselineForPatchGroupInput{
+		BaselineId: aws.String(baselineID),
+		PatchGroup: aws.String(baselineID),
+	})
+	if err != nil && !strings.Contains(err.Error(), "AlreadyExists") {
+		return fmt.Errorf("failed to register patch baseline %s: %w", baselineID, err)
+	}
+
+	_, err = instanceAWSClient(ec2Instance).CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{ec2Instance.Status.InstanceID},
+		Tags:      []ec2types.Tag{{Key: aws.String(patchGroupTag), Value: aws.String(baselineID)}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag instance with patch group: %w", err)
+	}
+	log.FromContext(ctx).Info("Added instance to patch group", "patchGroup", baselineID)
+	return nil
+}
+
+// deregisterPatching removes the patch task and the instance target from the maintenance window.
+// Both are already gone when the window itself was deleted.
+func deregisterPatching(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	reg := ec2Instance.Status.PatchRegistration
+	if reg == nil {
+		return nil
+	}
+	client := ssmClient(ec2Instance.Spec.Region)
+
+	if reg.WindowTaskID != "" {
+		_, err := client.DeregisterTaskFromMaintenanceWindow(ctx, &ssm.DeregisterTaskFromMaintenanceWindowInput{
+			WindowId:     aws.String(reg.WindowID),
+			WindowTaskId: aws.String(reg.WindowTaskID),
+		})
+		if err != nil && !strings.Contains(err.Error(), "DoesNotExist") {
+			return fmt.Errorf("failed to deregister patch task %s: %w", reg.WindowTaskID, err)
+		}
+		reg.WindowTaskID = ""
+	}
+	if reg.WindowTargetID != "" {
+		_, err := client.DeregisterTargetFromMaintenanceWindow(ctx, &ssm.DeregisterTargetFromMaintenanceWindowInput{
+			WindowId:       aws.String(reg.WindowID),
+			WindowTargetId: aws.String(reg.WindowTargetID),
+		})
+		if err != nil && !strings.Contains(err.Error(), "DoesNotExist") {
+			return fmt.Errorf("failed to deregister instance from maintenance window %s: %w", reg.WindowID, err)
+		}
+	}
+	log.FromContext(ctx).Info("Deregistered instance from maintenance window", "windowID", reg.WindowID)
+	ec2Instance.Status.PatchRegistration = nil
+	return nil
+}
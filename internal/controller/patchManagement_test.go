@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Patch management", func() {
+	Context("When summarizing the patch state", func() {
+		It("should be compliant when nothing is missing", func() {
+			state := &ssmtypes.InstancePatchState{InstalledCount: 42}
+			Expect(patchComplianceStatus(state)).To(Equal(patchCompliant))
+		})
+
+		It("should be non-compliant when patches are missing or failed", func() {
+			Expect(patchComplianceStatus(&ssmtypes.InstancePatchState{MissingCount: 1})).To(Equal(patchNonCompliant))
+			Expect(patchComplianceStatus(&ssmtypes.InstancePatchState{FailedCount: 1})).To(Equal(patchNonCompliant))
+		})
+
+		It("should be non-compliant while a reboot is pending", func() {
+			state := &ssmtypes.InstancePatchState{InstalledPendingRebootCount: aws.Int32(2)}
+			Expect(patchComplianceStatus(state)).To(Equal(patchNonCompliant))
+		})
+	})
+
+	Context("When building the patch task", func() {
+		It("should only reboot when asked to", func() {
+			Expect(patchTaskParameters(true)).To(HaveKeyWithValue("RebootOption", []string{"RebootIfNeeded"}))
+			Expect(patchTaskParameters(false)).To(HaveKeyWithValue("RebootOption", []string{"NoReboot"}))
+			Expect(patchTaskParameters(false)).To(HaveKeyWithValue("Operation", []string{"Install"}))
+		})
+	})
+})
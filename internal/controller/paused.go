@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// pausedAnnotation set to "true" freezes the reconciliation of an Ec2Instance, e.g. during an
+// incident. Nothing is changed in AWS until it is removed or set to "false"; that includes deletion.
+const pausedAnnotation = "ec2instance.compute.cloud.com/paused"
+
+// setPausedCondition brings the Paused condition in line with the annotation and reports whether
+// the instance is paused and whether the condition changed.
+func setPausedCondition(ec2Instance *computev1.Ec2Instance) (paused, changed bool) {
+	if ec2Instance.Annotations[pausedAnnotation] != "true" {
+		return false, apimeta.RemoveStatusCondition(&ec2Instance.Status.Conditions, computev1.ConditionPaused)
+	}
+	return true, apimeta.SetStatusCondition(&ec2Instance.Status.Conditions, metav1.Condition{
+		Type:               computev1.ConditionPaused,
+		Status:             metav1.ConditionTrue,
+		Reason:             "PausedByAnnotation",
+		Message:            fmt.Sprintf("Reconciliation is paused; remove the %s annotation or set it to \"false\" to resume", pausedAnnotation),
+		ObservedGeneration: ec2Instance.Generation,
+	})
+}
+
+// reconcilePause records whether ec2Instance is paused in its conditions and reports whether it is.
+func (r *Ec2InstanceReconciler) reconcilePause(ctx context.Context, ec2Instance *computev1.Ec2Instance) (bool, error) {
+	paused, changed := setPausedCondition(ec2Instance)
+	if changed {
+		if paused {
+			log.FromContext(ctx).Info("Reconciliation paused by annotation", "annotation", pausedAnnotation)
+		} else {
+			log.FromContext(ctx).Info("Reconciliation resumed")
+		}
+		if err := r.Status().Update(ctx, ec2Instance); err != nil {
+			return paused, fmt.Errorf("failed to update paused condition: %w", err)
+		}
+	}
+	return paused, nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Pausing reconciliation", func() {
+	It("should set the Paused condition while the annotation is true and clear it afterwards", func() {
+		inst := &computev1.Ec2Instance{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{pausedAnnotation: "true"}}}
+		paused, changed := setPausedCondition(inst)
+		Expect(paused).To(BeTrue())
+		Expect(changed).To(BeTrue())
+		Expect(apimeta.IsStatusConditionTrue(inst.Status.Conditions, computev1.ConditionPaused)).To(BeTrue())
+
+		_, changed = setPausedCondition(inst)
+		Expect(changed).To(BeFalse())
+
+		inst.Annotations[pausedAnnotation] = "false"
+		paused, changed = setPausedCondition(inst)
+		Expect(paused).To(BeFalse())
+		Expect(changed).To(BeTrue())
+		Expect(apimeta.FindStatusCondition(inst.Status.Conditions, computev1.ConditionPaused)).To(BeNil())
+	})
+})
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"strconv"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// estimatedMonthlyCostAnnotation lets users (or an admission webhook) record the expected monthly
+// cost of an instance. When present it takes precedence over the built-in price table.
+const estimatedMonthlyCostAnnotation = "ec2instance.compute.cloud.com/estimated-monthly-cost"
+
+// hoursPerMonth is the number of hours AWS uses when quoting monthly on-demand prices.
+const hoursPerMonth = 730
+
+// onDemandHourlyUSD is a small on-demand (Linux, us-east-1) price table for the instance types we
+// see most often. It is only used for estimates; the AWS bill is the source of truth.
+var onDemandHourlyUSD = map[string]float64{
+	"t2.micro":   0.0116,
+	"t2.small":   0.023,
+	"t2.medium":  0.0464,
+	"t2.large":   0.0928,
+	"t3.micro":   0.0104,
+	"t3.small":   0.0208,
+	"t3.medium":  0.0416,
+	"t3.large":   0.0832,
+	"t3.xlarge":  0.1664,
+	"t3.2xlarge": 0.3328,
+	"m5.large":   0.096,
+	"m5.xlarge":  0.192,
+	"m5.2xlarge": 0.384,
+	"m6i.large":  0.096,
+	"m6i.xlarge": 0.192,
+	"c5.large":   0.085,
+	"c5.xlarge":  0.17,
+	"c6i.large":  0.085,
+	"c6i.xlarge": 0.17,
+	"r5.large":   0.126,
+	"r5.xlarge":  0.252,
+}
+
+// EstimateMonthlyCostUSD returns the expected monthly cost of keeping the instance running.
+// The second return value is false when neither the annotation nor the price table knows the instance type.
+func EstimateMonthlyCostUSD(ec2Instance *computev1.Ec2Instance) (float64, bool) {
+	if v, ok := ec2Instance.Annotations[estimatedMonthlyCostAnnotation]; ok {
+		if cost, err := strconv.ParseFloat(v, 64); err == nil {
+			return cost, true
+		}
+	}
+	hourly, ok := onDemandHourlyUSD[launchInstanceType(ec2Instance)]
+	if !ok {
+		return 0, false
+	}
+	return hourly * hoursPerMonth, true
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/aws/smithy-go"
+	corev1 "k8s.io/api/core/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// Event reasons of the main reconcile transitions of an Ec2Instance.
+const (
+	eventInstanceCreating   = "InstanceCreating"
+	eventInstanceRunning    = "InstanceRunning"
+	eventInstanceTerminated = "InstanceTerminated"
+	eventDeletionStarted    = "DeletionStarted"
+	eventForceDeleteTimeout = "ForceDeleteTimeout"
+	eventErrorSyncing       = "ErrorSyncing"
+)
+
+// isAWSError reports whether err, or an error it wraps, came back from an AWS API.
+func isAWSError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr)
+}
+
+// recordAWSError emits an ErrorSyncing warning carrying the AWS error message when a reconcile of
+// ec2Instance failed on an AWS call. Other failures, such as update conflicts, stay in the log.
+func (r *Ec2InstanceReconciler) recordAWSError(ec2Instance *computev1.Ec2Instance, err error) {
+	if err == nil || !isAWSError(err) {
+		return
+	}
+	r.Recorder.Event(ec2Instance, corev1.EventTypeWarning, eventErrorSyncing, err.Error())
+}
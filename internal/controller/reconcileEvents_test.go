@@ -0,0 +1,37 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reconcile events", func() {
+	It("should recognise AWS errors behind wrapping", func() {
+		apiErr := &smithy.GenericAPIError{Code: "UnauthorizedOperation", Message: "not allowed"}
+		Expect(isAWSError(fmt.Errorf("failed to create instance: %w", apiErr))).To(BeTrue())
+	})
+
+	It("should leave other errors alone", func() {
+		Expect(isAWSError(errors.New("the object has been modified"))).To(BeFalse())
+	})
+})
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/transport"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// auditIDHeader is the header the API server takes the audit ID of a request from when the client
+// sets it, instead of generating a new one.
+const auditIDHeader = "Audit-ID"
+
+// reconcileIDTransport sends the ID of the reconcile a request is made from as its audit ID, so the
+// audit log groups every request of one reconcile under the same ID, which is also the reconcileID
+// in the operator logs. Reads served from the informer cache never reach the API server.
+type reconcileIDTransport struct {
+	next        http.RoundTripper
+	reconcileID func(ctx context.Context) types.UID
+}
+
+func (t *reconcileIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := t.reconcileID(req.Context())
+	if id == "" || req.Header.Get(auditIDHeader) != "" {
+		return t.next.RoundTrip(req)
+	}
+	// A RoundTripper must not change the request it was given.
+	req = req.Clone(req.Context())
+	req.Header.Set(auditIDHeader, string(id))
+	return t.next.RoundTrip(req)
+}
+
+// WithReconcileIDHeader wraps the transport of the manager's REST config so API requests made
+// during a reconcile carry its reconcile ID. Use it with rest.Config.Wrap.
+var WithReconcileIDHeader transport.WrapperFunc = func(rt http.RoundTripper) http.RoundTripper {
+	return &reconcileIDTransport{next: rt, reconcileID: controller.ReconcileIDFromContext}
+}
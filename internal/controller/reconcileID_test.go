@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type reconcileIDKey struct{}
+
+var _ = Describe("Reconcile ID header", func() {
+	var sent *http.Request
+	var rt http.RoundTripper
+
+	BeforeEach(func() {
+		sent = nil
+		rt = &reconcileIDTransport{
+			next: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				sent = req
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			}),
+			reconcileID: func(ctx context.Context) types.UID {
+				id, _ := ctx.Value(reconcileIDKey{}).(types.UID)
+				return id
+			},
+		}
+	})
+
+	It("should send the reconcile ID as the audit ID", func() {
+		ctx := context.WithValue(context.Background(), reconcileIDKey{}, types.UID("abc-123"))
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api/", nil)
+		_, err := rt.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sent.Header.Get("Audit-ID")).To(Equal("abc-123"))
+		Expect(req.Header.Get("Audit-ID")).To(BeEmpty())
+	})
+
+	It("should leave requests outside a reconcile alone", func() {
+		req, _ := http.NewRequest(http.MethodGet, "https://api/", nil)
+		_, err := rt.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sent).To(BeIdenticalTo(req))
+		Expect(sent.Header.Get("Audit-ID")).To(BeEmpty())
+	})
+})
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// RecoverOrphanReconciler recreates the Ec2Instance of an EC2 instance that lost it.
+type RecoverOrphanReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=recoverorphans,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=recoverorphans/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances/status,verbs=get;update;patch
+
+// Reconcile looks the instance up in AWS and creates an Ec2Instance that manages it.
+func (r *RecoverOrphanReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	recovery := &computev1.RecoverOrphan{}
+	if err := r.Get(ctx, req.NamespacedName, recovery); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if recovery.Status.Phase != "" {
+		// Completed and Failed are terminal.
+		return ctrl.Result{}, nil
+	}
+
+	// Refuse to create a second owner for an instance that is still managed.
+	all := &computev1.Ec2InstanceList{}
+	if err := r.List(ctx, all); err != nil {
+		return ctrl.Result{}, err
+	}
+	for _, inst := range all.Items {
+		if inst.Status.InstanceID == recovery.Spec.InstanceID || inst.Spec.AdoptInstanceID == recovery.Spec.InstanceID {
+			if inst.Namespace == recovery.Spec.TargetNamespace && inst.Name == recovery.Spec.TargetName {
+				// Created by an earlier attempt whose status update did not go through.
+				return r.setPhase(ctx, recovery, computev1.RecoverOrphanPhaseCompleted, "instance is managed by the target Ec2Instance")
+			}
+			return r.setPhase(ctx, recovery, computev1.RecoverOrphanPhaseFailed,
+				fmt.Sprintf("instance is not orphaned: it is managed by Ec2Instance %s/%s", inst.Namespace, inst.Name))
+		}
+	}
+
+	exists, awsInstance, err := checkEC2InstanceExists(ctx, awsClient(recovery.Spec.Region), recovery.Spec.InstanceID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !exists || awsInstance.State == nil || awsInstance.State.Name == ec2types.InstanceStateNameTerminated {
+		return r.setPhase(ctx, recovery, computev1.RecoverOrphanPhaseFailed,
+			fmt.Sprintf("instance %s does not exist in %s or is terminated", recovery.Spec.InstanceID, recovery.Spec.Region))
+	}
+
+	key := types.NamespacedName{Namespace: recovery.Spec.TargetNamespace, Name: recovery.Spec.TargetName}
+	if err := r.Get(ctx, key, &computev1.Ec2Instance{}); err == nil {
+		return r.setPhase(ctx, recovery, computev1.RecoverOrphanPhaseFailed, fmt.Sprintf("Ec2Instance %s already exists", key))
+	} else if !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	ec2Instance := &computev1.Ec2Instance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        key.Name,
+			Namespace:   key.Namespace,
+			Annotations: map[string]string{computev1.RecoveredFromOrphanAnnotation: recovery.Name},
+			Finalizers:  []string{ec2InstanceFinalizer},
+		},
+		Spec: recoveredSpec(awsInstance, recovery.Spec),
+	}
+	if err := r.Create(ctx, ec2Instance); err != nil {
+		l.Error(err, "Failed to create Ec2Instance for orphaned instance")
+		return ctrl.Result{}, err
+	}
+
+	// Fill in status right away so the instance is never treated as one that still has to be launched.
+	ec2Instance.Status.InstanceID = recovery.Spec.InstanceID
+	ec2Instance.Status.State = string(awsInstance.State.Name)
+	ec2Instance.Status.PublicIP = derefString(awsInstance.PublicIpAddress)
+	ec2Instance.Status.PrivateIP = derefString(awsInstance.PrivateIpAddress)
+	ec2Instance.Status.PublicDNS = derefString(awsInstance.PublicDnsName)
+	ec2Instance.Status.PrivateDNS = derefString(awsInstance.PrivateDnsName)
+	if awsInstance.LaunchTime != nil {
+		launchTime := metav1.NewTime(*awsInstance.LaunchTime)
+		ec2Instance.Status.LaunchTime = &launchTime
+	}
+	if err := r.Status().Update(ctx, ec2Instance); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	l.Info("Recovered orphaned instance", "instanceID", recovery.Spec.InstanceID, "ec2Instance", key)
+	return r.setPhase(ctx, recovery, computev1.RecoverOrphanPhaseCompleted, fmt.Sprintf("instance is managed by Ec2Instance %s", key))
+}
+
+// setPhase records the outcome of the recovery.
+func (r *RecoverOrphanReconciler) setPhase(ctx context.Context, recovery *computev1.RecoverOrphan, phase computev1.RecoverOrphanPhase, message string) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("RecoverOrphan finished", "phase", phase, "message", message)
+	recovery.Status.Phase = phase
+	recovery.Status.Message = message
+	return ctrl.Result{}, r.Status().Update(ctx, recovery)
+}
+
+// recoveredSpec is the spec of the Ec2Instance that takes the orphaned instance back. Without
+// spec.populateSpecFromAWS only the fields the API requires are set.
+func recoveredSpec(awsInstance *ec2types.Instance, spec computev1.RecoverOrphanSpec) computev1.Ec2InstanceSpec {
+	full := specFromAWSInstance(awsInstance, spec.Region)
+	if spec.PopulateSpecFromAWS {
+		return full
+	}
+	return computev1.Ec2InstanceSpec{
+		InstanceType:    full.InstanceType,
+		AMIId:           full.AMIId,
+		Region:          full.Region,
+		AdoptInstanceID: full.AdoptInstanceID,
+		AutoRecovery:    full.AutoRecovery,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RecoverOrphanReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.RecoverOrphan{}).
+		Named("recoverorphan").
+		Complete(r)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("RecoverOrphan Controller", func() {
+	awsInstance := &ec2types.Instance{
+		InstanceId:     aws.String("i-0123"),
+		InstanceType:   ec2types.InstanceTypeM5Large,
+		ImageId:        aws.String("ami-0abc"),
+		SubnetId:       aws.String("subnet-1"),
+		SecurityGroups: []ec2types.GroupIdentifier{{GroupId: aws.String("sg-1")}},
+		Tags:           []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String("web")}},
+	}
+
+	It("should copy the instance configuration when asked to", func() {
+		spec := recoveredSpec(awsInstance, computev1.RecoverOrphanSpec{Region: "us-east-1", PopulateSpecFromAWS: true})
+		Expect(spec.InstanceType).To(Equal("m5.large"))
+		Expect(spec.Subnet).To(Equal("subnet-1"))
+		Expect(spec.SecurityGroups).To(Equal([]string{"sg-1"}))
+		Expect(spec.Tags).To(HaveKeyWithValue("Name", "web"))
+		Expect(spec.AdoptInstanceID).To(Equal("i-0123"))
+	})
+
+	It("should only set the required fields otherwise", func() {
+		spec := recoveredSpec(awsInstance, computev1.RecoverOrphanSpec{Region: "us-east-1"})
+		Expect(spec.InstanceType).To(Equal("m5.large"))
+		Expect(spec.AMIId).To(Equal("ami-0abc"))
+		Expect(spec.Region).To(Equal("us-east-1"))
+		Expect(spec.Subnet).To(BeEmpty())
+		Expect(spec.SecurityGroups).To(BeEmpty())
+		Expect(spec.Tags).To(BeEmpty())
+	})
+})
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// regionCredentialsResync is how often credentials are verified again, which also picks up rotated secrets.
+const regionCredentialsResync = 10 * time.Minute
+
+// RegionCredentialsReconciler verifies RegionCredentials and makes awsConfig pick up changes to them.
+type RegionCredentialsReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// fingerprints remembers what each RegionCredentials resolved to on its last reconcile.
+	fingerprints map[string]string
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=regioncredentials,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=regioncredentials/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile drops the cached AWS configs when the credentials of a region changed and checks that
+// the credentials work with STS GetCallerIdentity.
+func (r *RegionCredentialsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	rc := &computev1.RegionCredentials{}
+	if err := r.Get(ctx, req.NamespacedName, rc); err != nil {
+		if errors.IsNotFound(err) {
+			delete(r.fingerprints, req.Name)
+			resetAWSConfigs()
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	status := computev1.RegionCredentialsStatus{}
+	fingerprint := fmt.Sprintf("%s|%s|%s", rc.Spec.Region, rc.Spec.RoleARN, rc.Spec.ExternalID)
+	if ref := rc.Spec.SecretRef; ref != nil {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+			if !errors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			status.Message = fmt.Sprintf("secret %s/%s not found", ref.Namespace, ref.Name)
+		}
+		fingerprint += "|" + secret.ResourceVersion
+	}
+	if r.fingerprints[rc.Name] != fingerprint {
+		l.Info("Region credentials changed", "region", rc.Spec.Region)
+		resetAWSConfigs()
+		if r.fingerprints == nil {
+			r.fingerprints = map[string]string{}
+		}
+		r.fingerprints[rc.Name] = fingerprint
+	}
+
+	all := &computev1.RegionCredentialsList{}
+	if err := r.List(ctx, all); err != nil {
+		return ctrl.Result{}, err
+	}
+	if owner := regionCredentialsFor(all.Items, rc.Spec.Region); owner != nil && owner.Name != rc.Name {
+		status.Message = fmt.Sprintf("region %s is already configured by %s", rc.Spec.Region, owner.Name)
+	}
+
+	if status.Message == "" {
+		identity, err := sts.NewFromConfig(awsConfig(rc.Spec.Region)).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			l.Error(err, "Failed to verify region credentials", "region", rc.Spec.Region)
+			status.Message = err.Error()
+		} else {
+			status.Ready = true
+			status.AccountID = aws.ToString(identity.Account)
+			status.ARN = aws.ToString(identity.Arn)
+		}
+	}
+
+	if rc.Status != status {
+		rc.Status = status
+		if err := r.Status().Update(ctx, rc); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: regionCredentialsResync}, nil
+}
+
+// regionCredentialsFor returns the RegionCredentials that applies to region. Should there be more
+// than one, the first by name wins.
+func regionCredentialsFor(items []computev1.RegionCredentials, region string) *computev1.RegionCredentials {
+	var matches []*computev1.RegionCredentials
+	for i := range items {
+		if items[i].Spec.Region == region && items[i].DeletionTimestamp.IsZero() {
+			matches = append(matches, &items[i])
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Name < matches[j].Name })
+	return matches[0]
+}
+
+// regionAWSConfig applies the RegionCredentials of base.Region, if any, to base.
+func regionAWSConfig(ctx context.Context, reader client.Reader, base aws.Config) (aws.Config, error) {
+	all := &computev1.RegionCredentialsList{}
+	if err := reader.List(ctx, all); err != nil {
+		return aws.Config{}, fmt.Errorf("failed to list RegionCredentials: %w", err)
+	}
+	rc := regionCredentialsFor(all.Items, base.Region)
+	if rc == nil {
+		return base, nil
+	}
+
+	cfg := base.Copy()
+	if ref := rc.Spec.SecretRef; ref != nil {
+		secret := &corev1.Secret{}
+		if err := reader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+			return aws.Config{}, fmt.Errorf("failed to get credentials secret of %s: %w", base.Region, err)
+		}
+		cfg.Credentials = credentials.NewStaticCredentialsProvider(
+			string(secret.Data["AWS_ACCESS_KEY_ID"]), string(secret.Data["AWS_SECRET_ACCESS_KEY"]), string(secret.Data["AWS_SESSION_TOKEN"]))
+	}
+	if rc.Spec.RoleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), rc.Spec.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "ec2operator-" + base.Region
+			if rc.Spec.ExternalID != "" {
+				o.ExternalID = aws.String(rc.Spec.ExternalID)
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return cfg, nil
+}
+
+// SetupWithManager sets up the controller with the Manager and lets awsConfig look up
+// RegionCredentials. The uncached API reader is used so lookups work before the cache has synced.
+func (r *RegionCredentialsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	regionCredentialsReader = mgr.GetAPIReader()
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.RegionCredentials{}).
+		Named("regioncredentials").
+		Complete(r)
+}
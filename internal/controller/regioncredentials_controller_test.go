@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("RegionCredentials Controller", func() {
+	regionCredentials := func(name, region string) computev1.RegionCredentials {
+		return computev1.RegionCredentials{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       computev1.RegionCredentialsSpec{Region: region},
+		}
+	}
+
+	Context("When picking the credentials of a region", func() {
+		items := []computev1.RegionCredentials{
+			regionCredentials("west-b", "eu-west-1"),
+			regionCredentials("east", "us-east-1"),
+			regionCredentials("west-a", "eu-west-1"),
+		}
+
+		It("should match on spec.region", func() {
+			Expect(regionCredentialsFor(items, "us-east-1").Name).To(Equal("east"))
+			Expect(regionCredentialsFor(items, "ap-south-1")).To(BeNil())
+		})
+
+		It("should let the first name win when a region is configured twice", func() {
+			Expect(regionCredentialsFor(items, "eu-west-1").Name).To(Equal("west-a"))
+		})
+	})
+
+	Context("When building the AWS config of two regions", func() {
+		ctx := context.Background()
+
+		BeforeEach(func() {
+			By("creating a secret for eu-west-1 and a role for us-east-1")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "eu-west-1-creds", Namespace: "default"},
+				Data: map[string][]byte{
+					"AWS_ACCESS_KEY_ID":     []byte("AKIAWEST"),
+					"AWS_SECRET_ACCESS_KEY": []byte("west-secret"),
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			west := regionCredentials("eu-west-1", "eu-west-1")
+			west.Spec.SecretRef = &computev1.SecretKeyReference{Name: "eu-west-1-creds", Namespace: "default"}
+			Expect(k8sClient.Create(ctx, &west)).To(Succeed())
+
+			east := regionCredentials("us-east-1", "us-east-1")
+			east.Spec.RoleARN = "arn:aws:iam::111111111111:role/ec2operator"
+			Expect(k8sClient.Create(ctx, &east)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			for _, name := range []string{"eu-west-1", "us-east-1"} {
+				Expect(k8sClient.Delete(ctx, &computev1.RegionCredentials{ObjectMeta: metav1.ObjectMeta{Name: name}})).To(Succeed())
+			}
+			Expect(k8sClient.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "eu-west-1-creds", Namespace: "default"}})).To(Succeed())
+			regionCredentialsReader = nil
+			resetAWSConfigs()
+		})
+
+		It("should use the secret in one region and the role in the other", func() {
+			west, err := regionAWSConfig(ctx, k8sClient, defaultAWSConfig("eu-west-1"))
+			Expect(err).NotTo(HaveOccurred())
+			creds, err := west.Credentials.Retrieve(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds.AccessKeyID).To(Equal("AKIAWEST"))
+
+			east, err := regionAWSConfig(ctx, k8sClient, defaultAWSConfig("us-east-1"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(east.Credentials).To(BeAssignableToTypeOf(&aws.CredentialsCache{}))
+		})
+
+		It("should cache the config of each region until credentials change", func() {
+			regionCredentialsReader = k8sClient
+			first := awsConfig("eu-west-1")
+			Expect(awsConfigs.byRegion).To(HaveKey("eu-west-1"))
+			Expect(awsConfig("eu-west-1").Credentials).To(BeIdenticalTo(first.Credentials))
+
+			resetAWSConfigs()
+			Expect(awsConfigs.byRegion).To(BeEmpty())
+		})
+
+		It("should share one EC2 client per region until credentials change", func() {
+			regionCredentialsReader = k8sClient
+			west := awsClient("eu-west-1")
+			Expect(awsClient("eu-west-1")).To(BeIdenticalTo(west))
+			Expect(awsClient("us-east-1")).NotTo(BeIdenticalTo(west))
+			Expect(west.Options().Region).To(Equal("eu-west-1"))
+
+			resetAWSConfigs()
+			Expect(awsConfigs.ec2Clients).To(BeEmpty())
+			Expect(awsClient("eu-west-1")).NotTo(BeIdenticalTo(west))
+		})
+	})
+})
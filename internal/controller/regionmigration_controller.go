@@ -0,0 +1,420 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// regionMigrationPollInterval is how often long running AWS operations (AMI copy, instance boot,
+// status checks) are polled while a migration waits on them.
+const regionMigrationPollInterval = 30 * time.Second
+
+// RegionMigrationReconciler moves an Ec2Instance to another AWS region.
+//
+// The migration is a linear state machine stored in status.phase. Each reconcile performs the work
+// of the current phase and either advances to the next one or requeues to poll AWS, so a restart of
+// the operator simply resumes from the last recorded phase.
+type RegionMigrationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=regionmigrations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=regionmigrations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+
+// Reconcile drives a RegionMigration one phase at a time.
+func (r *RegionMigrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	migration := &computev1.RegionMigration{}
+	if err := r.Get(ctx, req.NamespacedName, migration); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	l.Info("Reconciling RegionMigration", "phase", migration.Status.Phase, "targetRegion", migration.Spec.TargetRegion)
+
+	switch migration.Status.Phase {
+	case "", computev1.RegionMigrationPhasePending:
+		return r.startMigration(ctx, migration)
+	case computev1.RegionMigrationPhaseCopyingAMI:
+		return r.prepareTargetAMI(ctx, migration)
+	case computev1.RegionMigrationPhaseLaunchingInstance:
+		return r.launchTargetInstance(ctx, migration)
+	case computev1.RegionMigrationPhaseCuttingOver:
+		return r.cutOverTraffic(ctx, migration)
+	case computev1.RegionMigrationPhaseVerifyingHealth:
+		return r.verifyTargetHealth(ctx, migration)
+	case computev1.RegionMigrationPhaseTerminatingSource:
+		return r.terminateSource(ctx, migration)
+	default:
+		// Completed and Failed are terminal.
+		return ctrl.Result{}, nil
+	}
+}
+
+// setPhase records the new phase and message. The status write triggers the next reconcile.
+func (r *RegionMigrationReconciler) setPhase(ctx context.Context, migration *computev1.RegionMigration, phase computev1.RegionMigrationPhase, message string) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("RegionMigration phase transition", "from", migration.Status.Phase, "to", phase, "message", message)
+
+	migration.Status.Phase = phase
+	migration.Status.Message = message
+	if phase == computev1.RegionMigrationPhaseCompleted {
+		now := metav1.Now()
+		migration.Status.CompletionTime = &now
+	}
+	if err := r.Status().Update(ctx, migration); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// getSource returns the Ec2Instance being migrated.
+func (r *RegionMigrationReconciler) getSource(ctx context.Context, migration *computev1.RegionMigration) (*computev1.Ec2Instance, error) {
+	source := &computev1.Ec2Instance{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: migration.Namespace, Name: migration.Spec.SourceEc2InstanceRef.Name}, source)
+	return source, err
+}
+
+// startMigration validates the request before any AWS resources are touched.
+func (r *RegionMigrationReconciler) startMigration(ctx context.Context, migration *computev1.RegionMigration) (ctrl.Result, error) {
+	source, err := r.getSource(ctx, migration)
+	if errors.IsNotFound(err) {
+		return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseFailed,
+			fmt.Sprintf("source Ec2Instance %q not found", migration.Spec.SourceEc2InstanceRef.Name))
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if source.Spec.Region == migration.Spec.TargetRegion {
+		return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseFailed, "source instance already runs in the target region")
+	}
+	if source.Status.InstanceID == "" {
+		// Wait for the source to be launched before migrating it.
+		return ctrl.Result{RequeueAfter: regionMigrationPollInterval}, nil
+	}
+
+	return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseCopyingAMI,
+		fmt.Sprintf("preparing AMI %s in %s", launchImageID(source), migration.Spec.TargetRegion))
+}
+
+// prepareTargetAMI makes the source image available in the target region and waits until it can be launched.
+func (r *RegionMigrationReconciler) prepareTargetAMI(ctx context.Context, migration *computev1.RegionMigration) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	source, err := r.getSource(ctx, migration)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	targetClient := targetAWSClient(source, migration)
+
+	if migration.Status.TargetAMIID == "" {
+		var amiID string
+		switch migration.Spec.AMICopyStrategy {
+		case computev1.AMICopyStrategyLaunchFromLatestAMI:
+			amiID, err = findLatestMatchingAMI(ctx, source, targetClient, migration.Spec.TargetRegion)
+		default:
+			amiID, err = copyAMIToRegion(ctx, source, targetClient, migration)
+		}
+		if err != nil {
+			l.Error(err, "Failed to prepare AMI in target region")
+			return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseFailed, err.Error())
+		}
+
+		migration.Status.TargetAMIID = amiID
+		if err := r.Status().Update(ctx, migration); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: regionMigrationPollInterval}, nil
+	}
+
+	result, err := targetClient.DescribeImages(ctx, &ec2.DescribeImagesInput{ImageIds: []string{migration.Status.TargetAMIID}})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to describe target AMI: %w", err)
+	}
+	if len(result.Images) == 0 {
+		return ctrl.Result{RequeueAfter: regionMigrationPollInterval}, nil
+	}
+
+	switch result.Images[0].State {
+	case ec2types.ImageStateAvailable:
+		return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseLaunchingInstance,
+			fmt.Sprintf("AMI %s is available in %s", migration.Status.TargetAMIID, migration.Spec.TargetRegion))
+	case ec2types.ImageStatePending:
+		l.Info("Waiting for AMI copy to finish", "amiID", migration.Status.TargetAMIID)
+		return ctrl.Result{RequeueAfter: regionMigrationPollInterval}, nil
+	default:
+		return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseFailed,
+			fmt.Sprintf("AMI %s ended in state %s", migration.Status.TargetAMIID, result.Images[0].State))
+	}
+}
+
+// targetEc2InstanceName returns the name of the Ec2Instance launched in the target region.
+func targetEc2InstanceName(migration *computev1.RegionMigration) string {
+	if migration.Status.TargetEc2InstanceName != "" {
+		return migration.Status.TargetEc2InstanceName
+	}
+	return fmt.Sprintf("%s-%s", migration.Spec.SourceEc2InstanceRef.Name, migration.Spec.TargetRegion)
+}
+
+// targetAWSClient returns the EC2 client for the calls made in the target region. It is the client
+// the target Ec2Instance will use: for a source with spec.roleARN it assumes the same role, so the
+// AMI is copied into the account the instance is launched in.
+func targetAWSClient(source *computev1.Ec2Instance, migration *computev1.RegionMigration) *ec2.Client {
+	return instanceAWSClient(&computev1.Ec2Instance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: migration.Namespace, Name: targetEc2InstanceName(migration)},
+		Spec:       computev1.Ec2InstanceSpec{Region: migration.Spec.TargetRegion, RoleARN: source.Spec.RoleARN},
+	})
+}
+
+// copyAMIToRegion starts a CopyImage of the source AMI into the target region. The migration UID is
+// used as client token so a retried call never starts a second copy.
+func copyAMIToRegion(ctx context.Context, source *computev1.Ec2Instance, targetClient *ec2.Client, migration *computev1.RegionMigration) (string, error) {
+	result, err := targetClient.CopyImage(ctx, &ec2.CopyImageInput{
+		Name:          aws.String(fmt.Sprintf("%s-%s", source.Name, migration.Spec.TargetRegion)),
+		Description:   aws.String(fmt.Sprintf("Copied from %s in %s by ec2-operator", launchImageID(source), source.Spec.Region)),
+		SourceImageId: aws.String(launchImageID(source)),
+		SourceRegion:  aws.String(source.Spec.Region),
+		ClientToken:   aws.String(string(migration.UID)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to copy AMI %s to %s: %w", launchImageID(source), migration.Spec.TargetRegion, err)
+	}
+	return aws.ToString(result.ImageId), nil
+}
+
+// findLatestMatchingAMI looks for the newest AMI in the target region with the same name and owner
+// as the source AMI. This works for images published to every region, such as vendor AMIs.
+func findLatestMatchingAMI(ctx context.Context, source *computev1.Ec2Instance, targetClient imageAPI, targetRegion string) (string, error) {
+	sourceImages, err := instanceAWSClient(source).DescribeImages(ctx, &ec2.DescribeImagesInput{ImageIds: []string{launchImageID(source)}})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe source AMI: %w", err)
+	}
+	if len(sourceImages.Images) == 0 {
+		return "", fmt.Errorf("source AMI %s not found in %s", launchImageID(source), source.Spec.Region)
+	}
+	sourceImage := sourceImages.Images[0]
+
+	candidates, err := targetClient.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		Owners: []string{aws.ToString(sourceImage.OwnerId)},
+		Filters: []ec2types.Filter{
+			{Name: aws.String("name"), Values: []string{aws.ToString(sourceImage.Name)}},
+			{Name: aws.String("state"), Values: []string{"available"}},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search AMIs in %s: %w", targetRegion, err)
+	}
+	if len(candidates.Images) == 0 {
+		return "", fmt.Errorf("no AMI named %q owned by %s in %s", aws.ToString(sourceImage.Name), aws.ToString(sourceImage.OwnerId), targetRegion)
+	}
+
+	// CreationDate is ISO 8601, so a string sort gives chronological order.
+	sort.Slice(candidates.Images, func(i, j int) bool {
+		return aws.ToString(candidates.Images[i].CreationDate) > aws.ToString(candidates.Images[j].CreationDate)
+	})
+	return aws.ToString(candidates.Images[0].ImageId), nil
+}
+
+// targetInstanceSpec returns the spec of the Ec2Instance launched in the target region: the spec of
+// source without everything that only exists in the source region.
+func targetInstanceSpec(source *computev1.Ec2Instance, migration *computev1.RegionMigration) computev1.Ec2InstanceSpec {
+	spec := *source.Spec.DeepCopy()
+	// Everything that is scoped to a region has to be replaced or dropped.
+	spec.Region = migration.Spec.TargetRegion
+	spec.AMIId = migration.Status.TargetAMIID
+	spec.Subnet = migration.Spec.TargetSubnet
+	spec.AvailabilityZone = ""
+	spec.SecurityGroups = nil
+	spec.SecurityGroupRefs = nil
+	spec.KeyPairRef = nil
+	spec.AdoptInstanceID = ""
+	spec.PlacementGroup = nil
+	// Launch templates are regional as well. The settings the source took from its template are
+	// not carried over, apart from the instance type it was launched with.
+	spec.LaunchTemplateRef = nil
+	if spec.InstanceType == "" {
+		spec.InstanceType = launchInstanceType(source)
+	}
+	// KMS keys are regional; the root volume is encrypted with the default EBS key instead.
+	spec.KMSKeyID = ""
+	// The interfaces carry subnets and security groups of the source region. The target instance
+	// gets a single interface in spec.subnet instead.
+	spec.NetworkInterfaces = nil
+	// Snapshots are regional too, so the data volumes are created empty. A volume that took its size
+	// from the snapshot has no size left and is dropped.
+	spec.EBSVolumes = nil
+	for _, volume := range source.Spec.EBSVolumes {
+		if volume.SizeGB == 0 {
+			continue
+		}
+		volume.SnapshotID = ""
+		spec.EBSVolumes = append(spec.EBSVolumes, volume)
+	}
+	// The target instance allocates its own address instead.
+	spec.ElasticIP.ExistingAllocationID = ""
+	return spec
+}
+
+// launchTargetInstance creates the Ec2Instance in the target region and waits until it is running.
+func (r *RegionMigrationReconciler) launchTargetInstance(ctx context.Context, migration *computev1.RegionMigration) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	targetName := targetEc2InstanceName(migration)
+	if migration.Status.TargetEc2InstanceName == "" {
+		migration.Status.TargetEc2InstanceName = targetName
+		if err := r.Status().Update(ctx, migration); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	target := &computev1.Ec2Instance{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: migration.Namespace, Name: targetName}, target)
+	if errors.IsNotFound(err) {
+		source, err := r.getSource(ctx, migration)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		target = &computev1.Ec2Instance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      targetName,
+				Namespace: migration.Namespace,
+				Labels:    source.Labels,
+			},
+			Spec: targetInstanceSpec(source, migration),
+		}
+
+		if err := r.Create(ctx, target); err != nil {
+			l.Error(err, "Failed to create target Ec2Instance")
+			return ctrl.Result{}, err
+		}
+		l.Info("Created target Ec2Instance", "name", targetName, "region", migration.Spec.TargetRegion)
+		return ctrl.Result{RequeueAfter: regionMigrationPollInterval}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if target.Status.InstanceID == "" || target.Status.State != "running" {
+		l.Info("Waiting for target instance to be running", "name", targetName, "state", target.Status.State)
+		return ctrl.Result{RequeueAfter: regionMigrationPollInterval}, nil
+	}
+
+	migration.Status.TargetInstanceID = target.Status.InstanceID
+	return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseCuttingOver,
+		fmt.Sprintf("instance %s is running in %s", target.Status.InstanceID, migration.Spec.TargetRegion))
+}
+
+// cutOverTraffic points the configured Route53 record at the new instance.
+func (r *RegionMigrationReconciler) cutOverTraffic(ctx context.Context, migration *computev1.RegionMigration) (ctrl.Result, error) {
+	if migration.Spec.TrafficCutoverRef == nil {
+		return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseVerifyingHealth, "no traffic cutover configured")
+	}
+
+	cutover := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: migration.Namespace, Name: migration.Spec.TrafficCutoverRef.Name}, cutover); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to read traffic cutover ConfigMap: %w", err)
+	}
+	hostedZoneID, recordName := cutover.Data["hostedZoneID"], cutover.Data["recordName"]
+	if hostedZoneID == "" || recordName == "" {
+		return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseFailed,
+			"traffic cutover ConfigMap must set hostedZoneID and recordName")
+	}
+	ttl, _ := strconv.ParseInt(cutover.Data["ttl"], 10, 64)
+
+	target := &computev1.Ec2Instance{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: migration.Namespace, Name: migration.Status.TargetEc2InstanceName}, target); err != nil {
+		return ctrl.Result{}, err
+	}
+	ip := instanceIP(&target.Status, false)
+	if ip == "" {
+		return ctrl.Result{RequeueAfter: regionMigrationPollInterval}, nil
+	}
+
+	if err := upsertARecord(ctx, hostedZoneID, recordName, ip, ttl); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := metav1.Now()
+	migration.Status.CutoverTime = &now
+	return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseVerifyingHealth,
+		fmt.Sprintf("%s now points at %s", recordName, ip))
+}
+
+// verifyTargetHealth waits for both EC2 status checks of the new instance to pass. They are read
+// with the client of the target Ec2Instance, in the account it runs in.
+func (r *RegionMigrationReconciler) verifyTargetHealth(ctx context.Context, migration *computev1.RegionMigration) (ctrl.Result, error) {
+	target := &computev1.Ec2Instance{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: migration.Namespace, Name: migration.Status.TargetEc2InstanceName}, target); err != nil {
+		return ctrl.Result{}, err
+	}
+	result, err := instanceAWSClient(target).DescribeInstanceStatus(ctx, &ec2.DescribeInstanceStatusInput{
+		InstanceIds: []string{migration.Status.TargetInstanceID},
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to describe target instance status: %w", err)
+	}
+
+	if len(result.InstanceStatuses) == 0 {
+		return ctrl.Result{RequeueAfter: regionMigrationPollInterval}, nil
+	}
+	st := result.InstanceStatuses[0]
+	if st.InstanceStatus == nil || st.SystemStatus == nil ||
+		st.InstanceStatus.Status != ec2types.SummaryStatusOk || st.SystemStatus.Status != ec2types.SummaryStatusOk {
+		log.FromContext(ctx).Info("Waiting for target instance status checks", "instanceID", migration.Status.TargetInstanceID)
+		return ctrl.Result{RequeueAfter: regionMigrationPollInterval}, nil
+	}
+
+	return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseTerminatingSource, "target instance passed its status checks")
+}
+
+// terminateSource deletes the source Ec2Instance. Its finalizer terminates the old EC2 instance.
+func (r *RegionMigrationReconciler) terminateSource(ctx context.Context, migration *computev1.RegionMigration) (ctrl.Result, error) {
+	source, err := r.getSource(ctx, migration)
+	if errors.IsNotFound(err) {
+		return r.setPhase(ctx, migration, computev1.RegionMigrationPhaseCompleted,
+			fmt.Sprintf("workload migrated to %s", migration.Spec.TargetRegion))
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if source.DeletionTimestamp.IsZero() {
+		if err := r.Delete(ctx, source); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		log.FromContext(ctx).Info("Deleted source Ec2Instance", "name", source.Name)
+	}
+	return ctrl.Result{RequeueAfter: regionMigrationPollInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RegionMigrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.RegionMigration{}).
+		Named("regionmigration").
+		Complete(r)
+}
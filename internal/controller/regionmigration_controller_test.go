@@ -0,0 +1,194 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("RegionMigration Controller", func() {
+	Context("When the source Ec2Instance does not exist", func() {
+		const resourceName = "migration-missing-source"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating a RegionMigration pointing at a missing instance")
+			migration := &computev1.RegionMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: computev1.RegionMigrationSpec{
+					SourceEc2InstanceRef: corev1.LocalObjectReference{Name: "does-not-exist"},
+					TargetRegion:         "eu-west-1",
+				},
+			}
+			Expect(k8sClient.Create(ctx, migration)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			migration := &computev1.RegionMigration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, migration)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, migration)).To(Succeed())
+		})
+
+		It("should fail the migration without touching AWS", func() {
+			controllerReconciler := &RegionMigrationReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			migration := &computev1.RegionMigration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, migration)).To(Succeed())
+			Expect(migration.Status.Phase).To(Equal(computev1.RegionMigrationPhaseFailed))
+		})
+	})
+
+	Context("When launching the target instance", func() {
+		var (
+			source    *computev1.Ec2Instance
+			migration *computev1.RegionMigration
+		)
+
+		BeforeEach(func() {
+			source = &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{
+				Region:           "us-east-1",
+				AMIId:            "ami-source",
+				InstanceType:     "t3.micro",
+				AvailabilityZone: "us-east-1a",
+				SecurityGroups:   []string{"sg-source"},
+			}}
+			migration = &computev1.RegionMigration{Spec: computev1.RegionMigrationSpec{TargetRegion: "eu-west-1", TargetSubnet: "subnet-target"}}
+			migration.Status.TargetAMIID = "ami-target"
+		})
+
+		It("should keep the settings that are not scoped to a region", func() {
+			spec := targetInstanceSpec(source, migration)
+			Expect(spec.Region).To(Equal("eu-west-1"))
+			Expect(spec.AMIId).To(Equal("ami-target"))
+			Expect(spec.Subnet).To(Equal("subnet-target"))
+			Expect(spec.AvailabilityZone).To(BeEmpty())
+			Expect(spec.SecurityGroups).To(BeEmpty())
+			Expect(spec.InstanceType).To(Equal("t3.micro"))
+			Expect(source.Spec.Region).To(Equal("us-east-1"))
+		})
+
+		It("should launch an instance with several network interfaces into the target subnet", func() {
+			source.Spec.NetworkInterfaces = []computev1.NetworkInterfaceSpec{
+				{DeviceIndex: 0, SubnetID: "subnet-source-a", SecurityGroups: []string{"sg-source"}},
+				{DeviceIndex: 1, SubnetID: "subnet-source-b"},
+			}
+			spec := targetInstanceSpec(source, migration)
+			Expect(spec.NetworkInterfaces).To(BeEmpty())
+			Expect(spec.Subnet).To(Equal("subnet-target"))
+			Expect(source.Spec.NetworkInterfaces).To(HaveLen(2))
+		})
+
+		It("should create the data volumes without the source-region snapshots", func() {
+			source.Spec.EBSVolumes = []computev1.EBSVolumeSpec{
+				{DeviceName: "/dev/sdf", SizeGB: 100, SnapshotID: "snap-source"},
+				{DeviceName: "/dev/sdg", SnapshotID: "snap-sized-by-snapshot"},
+				{DeviceName: "/dev/sdh", SizeGB: 20},
+			}
+			spec := targetInstanceSpec(source, migration)
+			Expect(spec.EBSVolumes).To(Equal([]computev1.EBSVolumeSpec{
+				{DeviceName: "/dev/sdf", SizeGB: 100},
+				{DeviceName: "/dev/sdh", SizeGB: 20},
+			}))
+			Expect(source.Spec.EBSVolumes[0].SnapshotID).To(Equal("snap-source"))
+		})
+
+		It("should allocate a new Elastic IP in the target region", func() {
+			source.Spec.ElasticIP = computev1.ElasticIPSpec{Enabled: true, ExistingAllocationID: "eipalloc-source"}
+			spec := targetInstanceSpec(source, migration)
+			Expect(spec.ElasticIP).To(Equal(computev1.ElasticIPSpec{Enabled: true}))
+		})
+
+		It("should launch outside the placement group of the source region", func() {
+			source.Spec.PlacementGroup = &computev1.PlacementGroupSpec{Name: "source-cluster", PartitionNumber: 2}
+			spec := targetInstanceSpec(source, migration)
+			Expect(spec.PlacementGroup).To(BeNil())
+			Expect(source.Spec.PlacementGroup).NotTo(BeNil())
+		})
+
+		It("should encrypt the root volume with the default key of the target region", func() {
+			source.Spec.EncryptRootVolume = aws.Bool(true)
+			source.Spec.KMSKeyID = "arn:aws:kms:us-east-1:123456789012:key/source"
+			spec := targetInstanceSpec(source, migration)
+			Expect(spec.KMSKeyID).To(BeEmpty())
+			Expect(spec.EncryptRootVolume).To(Equal(aws.Bool(true)))
+		})
+
+		It("should launch without the launch template of the source region", func() {
+			source.Spec.LaunchTemplateRef = &computev1.LaunchTemplateRef{ID: "lt-0123456789abcdef0", Version: "$Default"}
+			spec := targetInstanceSpec(source, migration)
+			Expect(spec.LaunchTemplateRef).To(BeNil())
+			Expect(spec.InstanceType).To(Equal("t3.micro"))
+
+			By("keeping the instance type the source took from its template")
+			source.Spec.InstanceType = ""
+			source.Spec.AMIId = ""
+			source.Status.SelectedInstanceType = "m5.large"
+			source.Status.SelectedAMIID = "ami-template"
+			spec = targetInstanceSpec(source, migration)
+			Expect(spec.InstanceType).To(Equal("m5.large"))
+			Expect(spec.AMIId).To(Equal("ami-target"))
+		})
+
+		It("should copy the AMI and check the target in the account of a cross-account source", func() {
+			DeferCleanup(resetAWSConfigs)
+			source.Name = "web"
+			source.Spec.RoleARN = "arn:aws:iam::111111111111:role/ec2operator"
+			migration.Namespace = "team-a"
+			migration.Spec.SourceEc2InstanceRef.Name = "web"
+
+			spec := targetInstanceSpec(source, migration)
+			Expect(spec.RoleARN).To(Equal(source.Spec.RoleARN))
+			target := &computev1.Ec2Instance{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "web-eu-west-1"},
+				Spec:       spec,
+			}
+
+			client := targetAWSClient(source, migration)
+			Expect(client).To(BeIdenticalTo(instanceAWSClient(target)))
+			Expect(client.Options().Region).To(Equal("eu-west-1"))
+			Expect(client.Options().Credentials).NotTo(BeIdenticalTo(awsConfig("eu-west-1").Credentials))
+
+			By("using the operator's account for a source without a role")
+			source.Spec.RoleARN = ""
+			Expect(targetAWSClient(source, migration)).To(BeIdenticalTo(awsClient("eu-west-1")))
+		})
+	})
+})
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// replacementDrift returns the spec fields that differ from the instance and can only be applied
+// by launching a new one.
+func replacementDrift(ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) []string {
+	var fields []string
+	if ec2Instance.Status.LaunchedAMIID != "" && ec2Instance.Status.LaunchedAMIID != ec2Instance.Spec.AMIId {
+		fields = append(fields, "spec.amiId")
+	}
+	if ec2Instance.Spec.Subnet != "" && aws.ToString(awsInstance.SubnetId) != "" && aws.ToString(awsInstance.SubnetId) != ec2Instance.Spec.Subnet {
+		fields = append(fields, "spec.subnet")
+	}
+	if networkInterfacesDrifted(ec2Instance, awsInstance) {
+		fields = append(fields, "spec.networkInterfaces")
+	}
+	return fields
+}
+
+// reconcileReplacement terminates the instance when ec2instance.compute.cloud.com/force-replace is
+// set and the spec asks for something only a new instance can have. It reports whether it did; the
+// sync that finds the instance terminated then launches the replacement.
+func (r *Ec2InstanceReconciler) reconcileReplacement(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) (bool, error) {
+	// Instances launched before the AMI was recorded are taken to run the current one.
+	if ec2Instance.Status.LaunchedAMIID == "" {
+		ec2Instance.Status.LaunchedAMIID = ec2Instance.Spec.AMIId
+	}
+	drift := replacementDrift(ec2Instance, awsInstance)
+	if len(drift) == 0 || ec2Instance.Annotations[computev1.ForceReplaceAnnotation] != "true" {
+		return false, nil
+	}
+
+	message := fmt.Sprintf("Replacing instance %s because %s changed", ec2Instance.Status.InstanceID, strings.Join(drift, " and "))
+	log.FromContext(ctx).Info("Replacing instance", "instanceID", ec2Instance.Status.InstanceID, "fields", drift)
+	if _, err := r.instanceEC2Client(ec2Instance).TerminateInstances(ctx, &ec2.TerminateInstancesInput{
+		InstanceIds: []string{ec2Instance.Status.InstanceID},
+	}); err != nil {
+		return false, fmt.Errorf("failed to terminate instance for replacement: %w", err)
+	}
+	apimeta.SetStatusCondition(&ec2Instance.Status.Conditions, metav1.Condition{
+		Type:               computev1.ConditionPendingReplacement,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ForceReplace",
+		Message:            message,
+		ObservedGeneration: ec2Instance.Generation,
+	})
+	if err := r.Status().Update(ctx, ec2Instance); err != nil {
+		return false, err
+	}
+	r.Recorder.Event(ec2Instance, corev1.EventTypeNormal, computev1.ConditionPendingReplacement, message)
+
+	// The annotation is spent; leaving it would let later changes through without being asked for.
+	delete(ec2Instance.Annotations, computev1.ForceReplaceAnnotation)
+	return true, r.Update(ctx, ec2Instance)
+}
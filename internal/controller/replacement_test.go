@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Replacement drift", func() {
+	awsInstance := &ec2types.Instance{SubnetId: aws.String("subnet-1")}
+
+	It("should find nothing when the instance matches the spec", func() {
+		inst := &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{AMIId: "ami-1", Subnet: "subnet-1"}}
+		inst.Status.LaunchedAMIID = "ami-1"
+		Expect(replacementDrift(inst, awsInstance)).To(BeEmpty())
+	})
+
+	It("should report a changed AMI and subnet", func() {
+		inst := &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{AMIId: "ami-2", Subnet: "subnet-2"}}
+		inst.Status.LaunchedAMIID = "ami-1"
+		Expect(replacementDrift(inst, awsInstance)).To(Equal([]string{"spec.amiId", "spec.subnet"}))
+	})
+
+	It("should not report a subnet the spec leaves to AWS", func() {
+		inst := &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{AMIId: "ami-1"}}
+		Expect(replacementDrift(inst, awsInstance)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// GetOperatorConfig returns the cluster wide Ec2OperatorConfig, or an empty one when it does not exist.
+func GetOperatorConfig(ctx context.Context, c client.Reader) (*computev1.Ec2OperatorConfig, error) {
+	config := &computev1.Ec2OperatorConfig{}
+	err := c.Get(ctx, types.NamespacedName{Name: computev1.Ec2OperatorConfigName}, config)
+	if errors.IsNotFound(err) {
+		return &computev1.Ec2OperatorConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operator config: %w", err)
+	}
+	return config, nil
+}
+
+// instanceTags returns the tags to launch the instance with: spec.tags plus the default value of
+// every required tag that spec.tags does not set.
+func instanceTags(ec2Instance *computev1.Ec2Instance, required []computev1.RequiredTag) map[string]string {
+	tags := make(map[string]string, len(ec2Instance.Spec.Tags)+len(required))
+	for k, v := range ec2Instance.Spec.Tags {
+		tags[k] = v
+	}
+	for _, tag := range required {
+		if _, ok := tags[tag.Key]; !ok && tag.DefaultValue != "" {
+			tags[tag.Key] = tag.DefaultValue
+		}
+	}
+	return tags
+}
+
+// missingRequiredTags returns the required tags absent from the EC2 instance, with the value they
+// should get. Tags that have neither a value in spec.tags nor a default cannot be fixed and are skipped.
+func missingRequiredTags(ec2Instance *computev1.Ec2Instance, required []computev1.RequiredTag, actual []ec2types.Tag) []ec2types.Tag {
+	present := make(map[string]bool, len(actual))
+	for _, tag := range actual {
+		present[aws.ToString(tag.Key)] = true
+	}
+
+	desired := instanceTags(ec2Instance, required)
+	var missing []ec2types.Tag
+	for _, tag := range required {
+		if present[tag.Key] || desired[tag.Key] == "" {
+			continue
+		}
+		missing = append(missing, ec2types.Tag{Key: aws.String(tag.Key), Value: aws.String(desired[tag.Key])})
+	}
+	sort.Slice(missing, func(i, j int) bool { return aws.ToString(missing[i].Key) < aws.ToString(missing[j].Key) })
+	return missing
+}
+
+// reconcileRequiredTags adds required tags that are missing on the EC2 instance, e.g. because they
+// were removed in the console or the tag became required after the instance was launched.
+func (r *Ec2InstanceReconciler) reconcileRequiredTags(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	config, err := GetOperatorConfig(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+
+	missing := missingRequiredTags(ec2Instance, config.Spec.RequiredTags, awsInstance.Tags)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	_, err = instanceAWSClient(ec2Instance).CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{ec2Instance.Status.InstanceID},
+		Tags:      missing,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add required tags: %w", err)
+	}
+
+	keys := make([]string, 0, len(missing))
+	for _, tag := range missing {
+		keys = append(keys, aws.ToString(tag.Key))
+	}
+	log.FromContext(ctx).Info("Added missing required tags", "instanceID", ec2Instance.Status.InstanceID, "tags", keys)
+	r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "RequiredTagsAdded",
+		"Added missing required tags: %s", strings.Join(keys, ", "))
+	return nil
+}
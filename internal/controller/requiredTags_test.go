@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Required tags", func() {
+	required := []computev1.RequiredTag{
+		{Key: "Owner"},
+		{Key: "Environment", DefaultValue: "dev"},
+		{Key: "CostCenter"},
+	}
+	instance := &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{Tags: map[string]string{"Owner": "team-a", "Name": "web"}}}
+
+	It("should launch with spec tags and defaults", func() {
+		Expect(instanceTags(instance, required)).To(Equal(map[string]string{"Owner": "team-a", "Name": "web", "Environment": "dev"}))
+	})
+
+	It("should only add required tags that are missing and have a value", func() {
+		missing := missingRequiredTags(instance, required, []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String("web")}})
+		Expect(missing).To(Equal([]ec2types.Tag{
+			{Key: aws.String("Environment"), Value: aws.String("dev")},
+			{Key: aws.String("Owner"), Value: aws.String("team-a")},
+		}))
+	})
+})
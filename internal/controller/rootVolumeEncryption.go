@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// encryptRootVolume reports whether the instance is launched with an encrypted root volume:
+// spec.encryptRootVolume when it is set, and otherwise when the operator enforces EBS encryption or
+// storage.rootVolume.encrypted asks for it.
+func encryptRootVolume(ec2Instance *computev1.Ec2Instance, enforced bool) bool {
+	if ec2Instance.Spec.EncryptRootVolume != nil {
+		return *ec2Instance.Spec.EncryptRootVolume
+	}
+	return enforced || ec2Instance.Spec.Storage.RootVolume.Encrypted
+}
+
+// rootVolumeConfigured reports whether spec sets anything about the root volume.
+func rootVolumeConfigured(spec computev1.Ec2InstanceSpec) bool {
+	return spec.EncryptRootVolume != nil || spec.KMSKeyID != "" || spec.HibernationEnabled ||
+		spec.Storage.RootVolume != computev1.VolumeConfig{}
+}
+
+// encryptedRootVolumeMapping returns the block device mapping that launches the root volume of the
+// instance encrypted, with spec.kmsKeyID when set. The size and type of storage.rootVolume are
+// applied; left unset, the AMI defaults are kept. The root device of the AMI is looked up with
+// ec2Client, the client the instance is launched with.
+func encryptedRootVolumeMapping(ctx context.Context, ec2Client imageAPI, ec2Instance *computev1.Ec2Instance, imageID string) (ec2types.BlockDeviceMapping, error) {
+	rootVolume := ec2Instance.Spec.Storage.RootVolume
+	deviceName := rootVolume.DeviceName
+	if deviceName == "" {
+		image, err := describeImage(ctx, ec2Client, ec2Instance.Spec.Region, imageID)
+		if err != nil {
+			return ec2types.BlockDeviceMapping{}, err
+		}
+		if image == nil {
+			return ec2types.BlockDeviceMapping{}, fmt.Errorf("AMI %s not found in %s", imageID, ec2Instance.Spec.Region)
+		}
+		deviceName = aws.ToString(image.RootDeviceName)
+	}
+	ebs := &ec2types.EbsBlockDevice{
+		Encrypted:           aws.Bool(true),
+		DeleteOnTermination: aws.Bool(true),
+	}
+	if rootVolume.Size > 0 {
+		ebs.VolumeSize = aws.Int32(rootVolume.Size)
+	}
+	if rootVolume.Type != "" {
+		ebs.VolumeType = ec2types.VolumeType(rootVolume.Type)
+	}
+	if ec2Instance.Spec.KMSKeyID != "" {
+		ebs.KmsKeyId = aws.String(ec2Instance.Spec.KMSKeyID)
+	}
+	return ec2types.BlockDeviceMapping{DeviceName: aws.String(deviceName), Ebs: ebs}, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+var _ = Describe("Root volume encryption", func() {
+	It("should encrypt when enforced unless the instance opts out", func() {
+		inst := &computev1.Ec2Instance{}
+		Expect(encryptRootVolume(inst, true)).To(BeTrue())
+		Expect(encryptRootVolume(inst, false)).To(BeFalse())
+
+		inst.Spec.Storage.RootVolume.Encrypted = true
+		Expect(encryptRootVolume(inst, false)).To(BeTrue())
+
+		inst.Spec.EncryptRootVolume = aws.Bool(false)
+		Expect(encryptRootVolume(inst, true)).To(BeFalse())
+		inst.Spec.EncryptRootVolume = aws.Bool(true)
+		inst.Spec.Storage.RootVolume.Encrypted = false
+		Expect(encryptRootVolume(inst, false)).To(BeTrue())
+	})
+
+	It("should keep the AMI defaults the spec leaves unset and use the KMS key", func() {
+		inst := &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{
+			Storage:  computev1.StorageConfig{RootVolume: computev1.VolumeConfig{DeviceName: "/dev/xvda"}},
+			KMSKeyID: "alias/ebs",
+		}}
+		mapping, err := encryptedRootVolumeMapping(context.Background(), &awsclient.FakeEC2Client{}, inst, "ami-123")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mapping).To(Equal(ec2types.BlockDeviceMapping{DeviceName: aws.String("/dev/xvda"), Ebs: &ec2types.EbsBlockDevice{
+			Encrypted:           aws.Bool(true),
+			DeleteOnTermination: aws.Bool(true),
+			KmsKeyId:            aws.String("alias/ebs"),
+		}}))
+
+		inst.Spec.Storage.RootVolume.Size = 50
+		inst.Spec.Storage.RootVolume.Type = "gp3"
+		mapping, err = encryptedRootVolumeMapping(context.Background(), &awsclient.FakeEC2Client{}, inst, "ami-123")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(aws.ToInt32(mapping.Ebs.VolumeSize)).To(Equal(int32(50)))
+		Expect(mapping.Ebs.VolumeType).To(Equal(ec2types.VolumeTypeGp3))
+	})
+
+	It("should look up the root device with the client the instance is launched with", func() {
+		inst := &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{Region: "us-east-1"}}
+		fake := &awsclient.FakeEC2Client{Outputs: map[string]any{
+			"DescribeImages": &ec2.DescribeImagesOutput{Images: []ec2types.Image{{ImageId: aws.String("ami-shared"), RootDeviceName: aws.String("/dev/sda1")}}},
+		}}
+		mapping, err := encryptedRootVolumeMapping(context.Background(), fake, inst, "ami-shared")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(aws.ToString(mapping.DeviceName)).To(Equal("/dev/sda1"))
+		Expect(fake.CallsTo("DescribeImages")).To(Equal([]any{&ec2.DescribeImagesInput{ImageIds: []string{"ami-shared"}}}))
+
+		_, err = encryptedRootVolumeMapping(context.Background(), &awsclient.FakeEC2Client{}, inst, "ami-hidden")
+		Expect(err).To(MatchError(ContainSubstring("AMI ami-hidden not found in us-east-1")))
+	})
+})
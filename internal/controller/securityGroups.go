@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// instancePrimaryInterface returns the network interface at device index 0, or nil.
+func instancePrimaryInterface(awsInstance *ec2types.Instance) *ec2types.InstanceNetworkInterface {
+	for i := range awsInstance.NetworkInterfaces {
+		ni := &awsInstance.NetworkInterfaces[i]
+		if ni.Attachment != nil && aws.ToInt32(ni.Attachment.DeviceIndex) == 0 {
+			return ni
+		}
+	}
+	return nil
+}
+
+// sortedSet returns the distinct values, sorted, so that two sets can be compared with slices.Equal.
+func sortedSet(values []string) []string {
+	set := slices.Clone(values)
+	slices.Sort(set)
+	return slices.Compact(set)
+}
+
+// securityGroupIDs returns spec.securityGroups together with the groups of spec.securityGroupRefs,
+// and reports whether all referenced Ec2SecurityGroups exist and have a group ID yet.
+func (r *Ec2InstanceReconciler) securityGroupIDs(ctx context.Context, ec2Instance *computev1.Ec2Instance) ([]string, bool, error) {
+	ids := slices.Clone(ec2Instance.Spec.SecurityGroups)
+	for _, ref := range ec2Instance.Spec.SecurityGroupRefs {
+		// The group is created with the operator's credentials, so another account cannot use it.
+		if ec2Instance.Spec.RoleARN != "" {
+			return nil, false, fmt.Errorf("Ec2SecurityGroup %s is in the operator's account and cannot be used with spec.roleARN", ref.Name)
+		}
+		group := &computev1.Ec2SecurityGroup{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: ec2Instance.Namespace, Name: ref.Name}, group); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, false, nil
+			}
+			return nil, false, fmt.Errorf("failed to get Ec2SecurityGroup %s: %w", ref.Name, err)
+		}
+		if group.Status.GroupID == "" {
+			return nil, false, nil
+		}
+		if group.Spec.Region != ec2Instance.Spec.Region {
+			return nil, false, fmt.Errorf("Ec2SecurityGroup %s is in region %s, not %s", ref.Name, group.Spec.Region, ec2Instance.Spec.Region)
+		}
+		ids = append(ids, group.Status.GroupID)
+	}
+	return ids, true, nil
+}
+
+// desiredSecurityGroups returns the groups the primary interface should have. Exclusively managed,
+// that is spec.securityGroups. Otherwise groups attached by someone else stay, and only groups the
+// operator applied before and that are no longer in spec.securityGroups are removed.
+func desiredSecurityGroups(current, spec, managed []string, exclusive bool) []string {
+	if exclusive {
+		return sortedSet(spec)
+	}
+	desired := slices.Clone(spec)
+	for _, group := range current {
+		if !slices.Contains(managed, group) || slices.Contains(spec, group) {
+			desired = append(desired, group)
+		}
+	}
+	return sortedSet(desired)
+}
+
+// reconcileSecurityGroups brings the security groups of the primary network interface in line with
+// spec.securityGroups and spec.securityGroupRefs when they differ, so changing them does not need a
+// new instance. Without either, whatever AWS assigned is left alone.
+func (r *Ec2InstanceReconciler) reconcileSecurityGroups(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	ni := instancePrimaryInterface(awsInstance)
+	if ni == nil {
+		return nil
+	}
+	var current []string
+	for _, group := range ni.Groups {
+		current = append(current, aws.ToString(group.GroupId))
+	}
+	current = sortedSet(current)
+	ec2Instance.Status.AppliedSecurityGroupIDs = current
+
+	ids, ready, err := r.securityGroupIDs(ctx, ec2Instance)
+	if err != nil {
+		return err
+	}
+	// Until all referenced groups exist the current groups are kept, rather than dropping the missing ones.
+	if !ready {
+		return nil
+	}
+	spec := sortedSet(ids)
+	if len(spec) == 0 {
+		return nil
+	}
+	desired := desiredSecurityGroups(current, spec, ec2Instance.Status.ManagedSecurityGroupIDs, ec2Instance.Spec.ManageSGExclusive)
+	if slices.Equal(current, desired) {
+		ec2Instance.Status.ManagedSecurityGroupIDs = spec
+		return nil
+	}
+
+	_, err = instanceAWSClient(ec2Instance).ModifyNetworkInterfaceAttribute(ctx, &ec2.ModifyNetworkInterfaceAttributeInput{
+		NetworkInterfaceId: ni.NetworkInterfaceId,
+		Groups:             desired,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set security groups of %s: %w", aws.ToString(ni.NetworkInterfaceId), err)
+	}
+	log.FromContext(ctx).Info("Updated security groups", "networkInterfaceID", aws.ToString(ni.NetworkInterfaceId), "from", current, "to", desired)
+	r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "SecurityGroupsUpdated", "Security groups changed from %v to %v", current, desired)
+	ec2Instance.Status.AppliedSecurityGroupIDs = desired
+	ec2Instance.Status.ManagedSecurityGroupIDs = spec
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Security groups", func() {
+	It("should find the interface at device index 0", func() {
+		instance := &ec2types.Instance{NetworkInterfaces: []ec2types.InstanceNetworkInterface{
+			{NetworkInterfaceId: aws.String("eni-1"), Attachment: &ec2types.InstanceNetworkInterfaceAttachment{DeviceIndex: aws.Int32(1)}},
+			{NetworkInterfaceId: aws.String("eni-0"), Attachment: &ec2types.InstanceNetworkInterfaceAttachment{DeviceIndex: aws.Int32(0)}},
+		}}
+		Expect(aws.ToString(instancePrimaryInterface(instance).NetworkInterfaceId)).To(Equal("eni-0"))
+		Expect(instancePrimaryInterface(&ec2types.Instance{})).To(BeNil())
+	})
+
+	It("should compare security groups regardless of order and duplicates", func() {
+		Expect(sortedSet([]string{"sg-b", "sg-a", "sg-b"})).To(Equal([]string{"sg-a", "sg-b"}))
+		Expect(sortedSet(nil)).To(BeEmpty())
+	})
+
+	It("should replace all groups when managed exclusively", func() {
+		Expect(desiredSecurityGroups([]string{"sg-a", "sg-user"}, []string{"sg-a", "sg-b"}, []string{"sg-a"}, true)).
+			To(Equal([]string{"sg-a", "sg-b"}))
+	})
+
+	It("should keep groups attached by someone else", func() {
+		Expect(desiredSecurityGroups([]string{"sg-a", "sg-user"}, []string{"sg-a", "sg-b"}, []string{"sg-a"}, false)).
+			To(Equal([]string{"sg-a", "sg-b", "sg-user"}))
+	})
+
+	It("should remove groups dropped from the spec", func() {
+		Expect(desiredSecurityGroups([]string{"sg-a", "sg-old", "sg-user"}, []string{"sg-a"}, []string{"sg-a", "sg-old"}, false)).
+			To(Equal([]string{"sg-a", "sg-user"}))
+	})
+
+	Context("When resolving spec.securityGroupRefs", func() {
+		var inst *computev1.Ec2Instance
+
+		BeforeEach(func() {
+			inst = &computev1.Ec2Instance{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+				Spec: computev1.Ec2InstanceSpec{
+					Region:            "us-east-1",
+					SecurityGroups:    []string{"sg-0aaaaaaaaaaaaaaaa"},
+					SecurityGroupRefs: []corev1.LocalObjectReference{{Name: "web-sg"}},
+				},
+			}
+		})
+
+		It("should reject an instance in another account", func() {
+			inst.Spec.RoleARN = "arn:aws:iam::111111111111:role/ec2operator"
+			_, _, err := (&Ec2InstanceReconciler{}).securityGroupIDs(context.Background(), inst)
+			Expect(err).To(MatchError(ContainSubstring("cannot be used with spec.roleARN")))
+		})
+
+		It("should reject a group in another region", func() {
+			ctx := context.Background()
+			group := &computev1.Ec2SecurityGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-sg"},
+				Spec:       computev1.Ec2SecurityGroupSpec{Region: "eu-west-1", VpcID: "vpc-0123456789abcdef0"},
+			}
+			Expect(k8sClient.Create(ctx, group)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, group)
+			group.Status.GroupID = "sg-0bbbbbbbbbbbbbbbb"
+			Expect(k8sClient.Status().Update(ctx, group)).To(Succeed())
+
+			r := &Ec2InstanceReconciler{Client: k8sClient}
+			_, _, err := r.securityGroupIDs(ctx, inst)
+			Expect(err).To(MatchError("Ec2SecurityGroup web-sg is in region eu-west-1, not us-east-1"))
+
+			inst.Spec.Region = "eu-west-1"
+			ids, ready, err := r.securityGroupIDs(ctx, inst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ready).To(BeTrue())
+			Expect(ids).To(Equal([]string{"sg-0aaaaaaaaaaaaaaaa", "sg-0bbbbbbbbbbbbbbbb"}))
+		})
+	})
+})
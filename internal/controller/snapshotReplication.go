@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// snapshotReplicaOfTag marks a cross-region copy with the ID of the snapshot it was copied from.
+const snapshotReplicaOfTag = "ec2instance.compute.cloud.com/replica-of"
+
+// copyThrottles recognizes the errors CopySnapshot returns when a region has too many copies in
+// flight, on top of the generic throttling errors.
+var copyThrottles = retry.IsErrorThrottles(append([]retry.IsErrorThrottle{
+	retry.ThrottleErrorCode{Codes: map[string]struct{}{"ResourceLimitExceeded": {}}},
+}, retry.DefaultThrottles...))
+
+// copySnapshotBackoff spaces out CopySnapshot retries while the target region is throttling.
+var copySnapshotBackoff = wait.Backoff{Duration: 2 * time.Second, Factor: 2, Jitter: 0.1, Steps: 4}
+
+// replicatedSources returns the IDs of the snapshots a target region should hold a copy of: the
+// newest retentionCount source snapshots.
+func replicatedSources(history []computev1.SnapshotRef, retentionCount int) []string {
+	if retentionCount < 1 {
+		retentionCount = 1
+	}
+	var ids []string
+	for _, snapshot := range history {
+		if len(ids) == retentionCount {
+			break
+		}
+		ids = append(ids, snapshot.SnapshotID)
+	}
+	return ids
+}
+
+// replicationComplete reports whether the status shows a copy of every snapshot in every target
+// region and nothing more, in which case there is no need to look at AWS.
+func replicationComplete(ec2Instance *computev1.Ec2Instance) bool {
+	targets := ec2Instance.Spec.SnapshotSchedule.Replication
+	if len(targets) != len(ec2Instance.Status.ReplicatedSnapshots) {
+		return false
+	}
+	for _, target := range targets {
+		replicas, ok := ec2Instance.Status.ReplicatedSnapshots[target.TargetRegion]
+		if !ok {
+			return false
+		}
+		want := replicatedSources(ec2Instance.Status.SnapshotHistory, target.RetentionCount)
+		if len(replicas) != len(want) {
+			return false
+		}
+		have := make(map[string]bool, len(replicas))
+		for _, replica := range replicas {
+			have[replica.SourceSnapshotID] = true
+		}
+		for _, id := range want {
+			if !have[id] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// reconcileSnapshotReplication copies the retained snapshots to every target region and deletes
+// copies beyond the target's retention or whose source snapshot is gone. sources are the retained
+// source snapshots, newest first.
+func (r *Ec2InstanceReconciler) reconcileSnapshotReplication(ctx context.Context, ec2Instance *computev1.Ec2Instance, sources []ec2types.Snapshot) error {
+	targets := ec2Instance.Spec.SnapshotSchedule.Replication
+	if len(targets) == 0 {
+		ec2Instance.Status.ReplicatedSnapshots = nil
+		return nil
+	}
+
+	replicated := make(map[string][]computev1.SnapshotRef, len(targets))
+	var errs []error
+	for _, target := range targets {
+		replicas, err := r.replicateSnapshots(ctx, ec2Instance, target, sources)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		replicated[target.TargetRegion] = replicas
+	}
+	ec2Instance.Status.ReplicatedSnapshots = replicated
+	return errors.Join(errs...)
+}
+
+// replicateSnapshots brings the copies in one target region in line and returns them, newest first.
+// On error the copies known so far are returned so the status stays accurate.
+func (r *Ec2InstanceReconciler) replicateSnapshots(ctx context.Context, ec2Instance *computev1.Ec2Instance, target computev1.SnapshotReplicationTarget, sources []ec2types.Snapshot) ([]computev1.SnapshotRef, error) {
+	l := log.FromContext(ctx).WithValues("targetRegion", target.TargetRegion)
+	// The copies belong to the account of the instance, like its snapshots.
+	targetClient := instanceRegionAWSClient(ec2Instance, target.TargetRegion)
+
+	// Copies carry the instance tag of their source, so the same lookup finds them.
+	replicas, err := instanceSnapshots(ctx, targetClient, ec2Instance.Status.InstanceID)
+	if err != nil {
+		return ec2Instance.Status.ReplicatedSnapshots[target.TargetRegion], err
+	}
+
+	history := make([]computev1.SnapshotRef, 0, len(sources))
+	sourceByID := make(map[string]ec2types.Snapshot, len(sources))
+	for _, source := range sources {
+		history = append(history, computev1.SnapshotRef{SnapshotID: aws.ToString(source.SnapshotId)})
+		sourceByID[aws.ToString(source.SnapshotId)] = source
+	}
+	want := replicatedSources(history, target.RetentionCount)
+	wanted := make(map[string]bool, len(want))
+	for _, id := range want {
+		wanted[id] = true
+	}
+
+	var refs []computev1.SnapshotRef
+	var errs []error
+	copied := map[string]bool{}
+	for _, replica := range replicas {
+		source := snapshotTag(replica.Tags, snapshotReplicaOfTag)
+		if wanted[source] && !copied[source] {
+			copied[source] = true
+			refs = append(refs, computev1.SnapshotRef{
+				SnapshotID:       aws.ToString(replica.SnapshotId),
+				StartTime:        metav1.NewTime(aws.ToTime(replica.StartTime)),
+				SourceSnapshotID: source,
+			})
+			continue
+		}
+		if _, err := targetClient.DeleteSnapshot(ctx, &ec2.DeleteSnapshotInput{SnapshotId: replica.SnapshotId}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete snapshot copy %s in %s: %w", aws.ToString(replica.SnapshotId), target.TargetRegion, err))
+			continue
+		}
+		l.Info("Deleted snapshot copy", "snapshotID", aws.ToString(replica.SnapshotId), "sourceSnapshotID", source)
+	}
+
+	for _, id := range want {
+		// A snapshot can only be copied once it is complete; pending ones are picked up on a later pass.
+		if copied[id] || sourceByID[id].State != ec2types.SnapshotStateCompleted {
+			continue
+		}
+		replicaID, err := copySnapshot(ctx, targetClient, ec2Instance, target, id)
+		if err != nil {
+			errs = append(errs, err)
+			break
+		}
+		l.Info("Copied snapshot", "sourceSnapshotID", id, "snapshotID", replicaID)
+		refs = append(refs, computev1.SnapshotRef{
+			SnapshotID:       replicaID,
+			StartTime:        metav1.Now(),
+			SourceSnapshotID: id,
+		})
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool { return refs[i].StartTime.After(refs[j].StartTime.Time) })
+	return refs, errors.Join(errs...)
+}
+
+// copySnapshot copies a snapshot into the target region, backing off while the region throttles
+// snapshot copies.
+func copySnapshot(ctx context.Context, targetClient *ec2.Client, ec2Instance *computev1.Ec2Instance, target computev1.SnapshotReplicationTarget, sourceID string) (string, error) {
+	input := &ec2.CopySnapshotInput{
+		SourceRegion:     aws.String(ec2Instance.Spec.Region),
+		SourceSnapshotId: aws.String(sourceID),
+		Description:      aws.String(fmt.Sprintf("Copy of %s from %s", sourceID, ec2Instance.Spec.Region)),
+		TagSpecifications: []ec2types.TagSpecification{{
+			ResourceType: ec2types.ResourceTypeSnapshot,
+			Tags: []ec2types.Tag{
+				{Key: aws.String(snapshotInstanceTag), Value: aws.String(ec2Instance.Status.InstanceID)},
+				{Key: aws.String(snapshotReplicaOfTag), Value: aws.String(sourceID)},
+				{Key: aws.String("Name"), Value: aws.String(ec2Instance.Name)},
+			},
+		}},
+	}
+	if target.KMSKeyID != "" {
+		input.Encrypted = aws.Bool(true)
+		input.KmsKeyId = aws.String(target.KMSKeyID)
+	}
+
+	var output *ec2.CopySnapshotOutput
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, copySnapshotBackoff, func(ctx context.Context) (bool, error) {
+		output, lastErr = targetClient.CopySnapshot(ctx, input)
+		if lastErr == nil {
+			return true, nil
+		}
+		if copyThrottles.IsErrorThrottle(lastErr) == aws.TrueTernary {
+			return false, nil
+		}
+		return false, lastErr
+	})
+	if err != nil {
+		if wait.Interrupted(err) {
+			err = lastErr
+		}
+		return "", fmt.Errorf("failed to copy snapshot %s to %s: %w", sourceID, target.TargetRegion, err)
+	}
+	return aws.ToString(output.SnapshotId), nil
+}
+
+// snapshotTag returns the value of the tag with the given key, or "".
+func snapshotTag(tags []ec2types.Tag, key string) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == key {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
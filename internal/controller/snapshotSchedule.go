@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// snapshotInstanceTag marks snapshots taken by spec.snapshotSchedule with the ID of their instance.
+const snapshotInstanceTag = "ec2instance.compute.cloud.com/snapshot-of"
+
+// snapshotDue returns the time the next scheduled snapshot was due, and whether that time has passed.
+// The schedule counts from the last snapshot, or from the creation of the object when there is none.
+func snapshotDue(schedule cron.Schedule, ec2Instance *computev1.Ec2Instance, now time.Time) (time.Time, bool) {
+	last := ec2Instance.CreationTimestamp.Time
+	if len(ec2Instance.Status.SnapshotHistory) > 0 {
+		last = ec2Instance.Status.SnapshotHistory[0].StartTime.Time
+	}
+	next := schedule.Next(last)
+	return next, !next.After(now)
+}
+
+// reconcileSnapshotSchedule snapshots the root volume when spec.snapshotSchedule says one is due,
+// then deletes the oldest snapshots beyond the retention count and records the rest in status.
+func (r *Ec2InstanceReconciler) reconcileSnapshotSchedule(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	l := log.FromContext(ctx)
+	spec := ec2Instance.Spec.SnapshotSchedule
+	if spec == nil {
+		return nil
+	}
+
+	schedule, err := cron.ParseStandard(spec.CronExpression)
+	if err != nil {
+		// The webhook rejects bad expressions, so this only happens when webhooks are disabled.
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "InvalidSnapshotSchedule",
+			"Cannot parse snapshot schedule %q: %v", spec.CronExpression, err)
+		return nil
+	}
+	due, ok := snapshotDue(schedule, ec2Instance, time.Now())
+	if !ok && replicationComplete(ec2Instance) {
+		return nil
+	}
+
+	ec2Client := instanceAWSClient(ec2Instance)
+	snapshots, err := instanceSnapshots(ctx, ec2Client, ec2Instance.Status.InstanceID)
+	if err != nil {
+		return err
+	}
+
+	// A snapshot newer than the due time means it was taken but the status update got lost.
+	if ok && (len(snapshots) == 0 || aws.ToTime(snapshots[0].StartTime).Before(due)) {
+		volumeID := rootVolumeID(awsInstance)
+		if volumeID == "" {
+			return fmt.Errorf("instance %s has no EBS root volume to snapshot", ec2Instance.Status.InstanceID)
+		}
+		description := spec.Description
+		if description == "" {
+			description = fmt.Sprintf("Scheduled snapshot of %s/%s (%s)", ec2Instance.Namespace, ec2Instance.Name, ec2Instance.Status.InstanceID)
+		}
+		snapshot, err := ec2Client.CreateSnapshot(ctx, &ec2.CreateSnapshotInput{
+			VolumeId:    aws.String(volumeID),
+			Description: aws.String(description),
+			TagSpecifications: []ec2types.TagSpecification{{
+				ResourceType: ec2types.ResourceTypeSnapshot,
+				Tags: []ec2types.Tag{
+					{Key: aws.String(snapshotInstanceTag), Value: aws.String(ec2Instance.Status.InstanceID)},
+					{Key: aws.String("Name"), Value: aws.String(ec2Instance.Name)},
+				},
+			}},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to snapshot root volume %s: %w", volumeID, err)
+		}
+		l.Info("Created scheduled snapshot", "snapshotID", aws.ToString(snapshot.SnapshotId), "volumeID", volumeID)
+		r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "SnapshotCreated",
+			"Created snapshot %s of root volume %s", aws.ToString(snapshot.SnapshotId), volumeID)
+		snapshots = append([]ec2types.Snapshot{{SnapshotId: snapshot.SnapshotId, StartTime: snapshot.StartTime, State: snapshot.State}}, snapshots...)
+	}
+
+	retention := spec.RetentionCount
+	if retention < 1 {
+		retention = 1
+	}
+	var deleteErr error
+	for len(snapshots) > retention {
+		oldest := snapshots[len(snapshots)-1]
+		if _, err := ec2Client.DeleteSnapshot(ctx, &ec2.DeleteSnapshotInput{SnapshotId: oldest.SnapshotId}); err != nil {
+			deleteErr = fmt.Errorf("failed to delete snapshot %s: %w", aws.ToString(oldest.SnapshotId), err)
+			break
+		}
+		l.Info("Deleted snapshot beyond retention", "snapshotID", aws.ToString(oldest.SnapshotId))
+		snapshots = snapshots[:len(snapshots)-1]
+	}
+
+	history := make([]computev1.SnapshotRef, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		history = append(history, computev1.SnapshotRef{
+			SnapshotID: aws.ToString(snapshot.SnapshotId),
+			StartTime:  metav1.NewTime(aws.ToTime(snapshot.StartTime)),
+		})
+	}
+	ec2Instance.Status.SnapshotHistory = history
+	if deleteErr != nil {
+		return deleteErr
+	}
+	return r.reconcileSnapshotReplication(ctx, ec2Instance, snapshots)
+}
+
+// instanceSnapshots returns the scheduled snapshots of instanceID, newest first.
+func instanceSnapshots(ctx context.Context, ec2Client *ec2.Client, instanceID string) ([]ec2types.Snapshot, error) {
+	var snapshots []ec2types.Snapshot
+	paginator := ec2.NewDescribeSnapshotsPaginator(ec2Client, &ec2.DescribeSnapshotsInput{
+		OwnerIds: []string{"self"},
+		Filters:  []ec2types.Filter{{Name: aws.String("tag:" + snapshotInstanceTag), Values: []string{instanceID}}},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshots of %s: %w", instanceID, err)
+		}
+		snapshots = append(snapshots, page.Snapshots...)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return aws.ToTime(snapshots[i].StartTime).After(aws.ToTime(snapshots[j].StartTime))
+	})
+	return snapshots, nil
+}
+
+// rootVolumeID returns the ID of the EBS volume attached as the root device, or "" if there is none.
+func rootVolumeID(awsInstance *ec2types.Instance) string {
+	for _, mapping := range awsInstance.BlockDeviceMappings {
+		if aws.ToString(mapping.DeviceName) == aws.ToString(awsInstance.RootDeviceName) && mapping.Ebs != nil {
+			return aws.ToString(mapping.Ebs.VolumeId)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Snapshot schedule", func() {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	schedule, _ := cron.ParseStandard("0 3 * * *")
+
+	It("should count the first snapshot from the creation of the object", func() {
+		instance := &computev1.Ec2Instance{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+		due, ok := snapshotDue(schedule, instance, created.Add(time.Hour))
+		Expect(ok).To(BeFalse())
+		Expect(due).To(Equal(time.Date(2025, 3, 2, 3, 0, 0, 0, time.UTC)))
+	})
+
+	It("should count from the last snapshot", func() {
+		instance := &computev1.Ec2Instance{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+			Status: computev1.Ec2InstanceStatus{SnapshotHistory: []computev1.SnapshotRef{
+				{SnapshotID: "snap-2", StartTime: metav1.NewTime(time.Date(2025, 3, 5, 3, 0, 10, 0, time.UTC))},
+			}},
+		}
+		_, ok := snapshotDue(schedule, instance, time.Date(2025, 3, 6, 2, 59, 0, 0, time.UTC))
+		Expect(ok).To(BeFalse())
+		_, ok = snapshotDue(schedule, instance, time.Date(2025, 3, 6, 3, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+	})
+})
+
+var _ = Describe("Snapshot replication", func() {
+	history := []computev1.SnapshotRef{{SnapshotID: "snap-3"}, {SnapshotID: "snap-2"}, {SnapshotID: "snap-1"}}
+
+	It("should replicate the newest snapshots up to the target retention", func() {
+		Expect(replicatedSources(history, 2)).To(Equal([]string{"snap-3", "snap-2"}))
+		Expect(replicatedSources(history, 7)).To(Equal([]string{"snap-3", "snap-2", "snap-1"}))
+	})
+
+	It("should only skip AWS when every target holds exactly the wanted copies", func() {
+		instance := &computev1.Ec2Instance{
+			Spec: computev1.Ec2InstanceSpec{SnapshotSchedule: &computev1.SnapshotScheduleSpec{
+				Replication: []computev1.SnapshotReplicationTarget{{TargetRegion: "us-west-2", RetentionCount: 2}},
+			}},
+			Status: computev1.Ec2InstanceStatus{
+				SnapshotHistory: history,
+				ReplicatedSnapshots: map[string][]computev1.SnapshotRef{"us-west-2": {
+					{SnapshotID: "snap-b", SourceSnapshotID: "snap-3"},
+				}},
+			},
+		}
+		Expect(replicationComplete(instance)).To(BeFalse())
+
+		instance.Status.ReplicatedSnapshots["us-west-2"] = append(instance.Status.ReplicatedSnapshots["us-west-2"],
+			computev1.SnapshotRef{SnapshotID: "snap-a", SourceSnapshotID: "snap-2"})
+		Expect(replicationComplete(instance)).To(BeTrue())
+
+		instance.Spec.SnapshotSchedule.Replication = nil
+		Expect(replicationComplete(instance)).To(BeFalse())
+	})
+})
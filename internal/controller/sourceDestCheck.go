@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// reconcileSourceDestCheck sets the source/destination check of the instance to
+// spec.sourceDestCheck. New instances always start with the check on, so the first sync after
+// launch turns it off where the spec asks for that.
+func reconcileSourceDestCheck(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	desired := ec2Instance.Spec.SourceDestCheck
+	if desired == nil {
+		return nil
+	}
+	ec2Client := instanceAWSClient(ec2Instance)
+	instanceID := aws.String(ec2Instance.Status.InstanceID)
+
+	current := awsInstance.SourceDestCheck
+	if current == nil {
+		attribute, err := ec2Client.DescribeInstanceAttribute(ctx, &ec2.DescribeInstanceAttributeInput{
+			InstanceId: instanceID,
+			Attribute:  ec2types.InstanceAttributeNameSourceDestCheck,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read source/destination check: %w", err)
+		}
+		if attribute.SourceDestCheck != nil {
+			current = attribute.SourceDestCheck.Value
+		}
+	}
+	if current != nil && *current == *desired {
+		return nil
+	}
+
+	_, err := ec2Client.ModifyInstanceAttribute(ctx, &ec2.ModifyInstanceAttributeInput{
+		InstanceId:      instanceID,
+		SourceDestCheck: &ec2types.AttributeBooleanValue{Value: desired},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set source/destination check: %w", err)
+	}
+	log.FromContext(ctx).Info("Corrected source/destination check", "instanceID", *instanceID, "sourceDestCheck", *desired)
+	return nil
+}
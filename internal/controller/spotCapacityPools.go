@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// spotInterruptionWindow is how long a pool has to go without an interruption before its weight
+// is restored.
+const spotInterruptionWindow = time.Hour
+
+// spotPoolKey identifies a pool in status.poolBreakdown and in the SpotPoolLabel of its instances.
+func spotPoolKey(pool *computev1.SpotCapacityPool) string {
+	return pool.InstanceType + "-" + pool.AvailabilityZone
+}
+
+// spotPoolBreakdown returns the state of every pool: the instances in it, the interruptions carried
+// over from previous plus the ones just seen, and the weight lowered by them.
+func spotPoolBreakdown(pools []computev1.SpotCapacityPool, previous map[string]computev1.PoolStatus, instances []computev1.Ec2Instance, interrupted map[string]int32, now time.Time) map[string]computev1.PoolStatus {
+	counts := map[string]int32{}
+	for i := range instances {
+		counts[instances[i].Labels[computev1.SpotPoolLabel]]++
+	}
+
+	breakdown := make(map[string]computev1.PoolStatus, len(pools))
+	for i := range pools {
+		key := spotPoolKey(&pools[i])
+		status := previous[key]
+		if status.LastInterruptionAt != nil && now.Sub(status.LastInterruptionAt.Time) > spotInterruptionWindow {
+			status.Interruptions = 0
+		}
+		if n := interrupted[key]; n > 0 {
+			status.Interruptions += n
+			at := metav1.NewTime(now)
+			status.LastInterruptionAt = &at
+		}
+		status.Instances = counts[key]
+		status.EffectiveWeight = pools[i].Weight / float64(1+status.Interruptions)
+		breakdown[key] = status
+	}
+	return breakdown
+}
+
+// chooseSpotPool returns the pool that is furthest below its share of the instances by effective
+// weight, counting the instance about to be launched. Pools that all have no weight share equally.
+func chooseSpotPool(pools []computev1.SpotCapacityPool, breakdown map[string]computev1.PoolStatus) *computev1.SpotCapacityPool {
+	var totalWeight float64
+	var totalInstances int32 = 1
+	for i := range pools {
+		status := breakdown[spotPoolKey(&pools[i])]
+		totalWeight += status.EffectiveWeight
+		totalInstances += status.Instances
+	}
+
+	var chosen *computev1.SpotCapacityPool
+	var chosenDeficit float64
+	for i := range pools {
+		status := breakdown[spotPoolKey(&pools[i])]
+		share := 1 / float64(len(pools))
+		if totalWeight > 0 {
+			share = status.EffectiveWeight / totalWeight
+		}
+		deficit := share*float64(totalInstances) - float64(status.Instances)
+		if chosen == nil || deficit > chosenDeficit {
+			chosen, chosenDeficit = &pools[i], deficit
+		}
+	}
+	return chosen
+}
+
+// applySpotPool makes inst a Spot instance in pool.
+func applySpotPool(inst *computev1.Ec2Instance, pool *computev1.SpotCapacityPool) {
+	inst.Spec.InstanceType = pool.InstanceType
+	inst.Spec.AvailabilityZone = pool.AvailabilityZone
+	inst.Spec.SpotOptions = computev1.SpotOptionsSpec{Enabled: true, MaxPrice: pool.MaxPrice}
+	inst.Labels[computev1.SpotPoolLabel] = spotPoolKey(pool)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Spot capacity pools", func() {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	pools := []computev1.SpotCapacityPool{
+		{InstanceType: "m5.large", AvailabilityZone: "us-east-1a", Weight: 3},
+		{InstanceType: "m5a.large", AvailabilityZone: "us-east-1b", Weight: 1},
+	}
+	inPool := func(key string) computev1.Ec2Instance {
+		return computev1.Ec2Instance{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{computev1.SpotPoolLabel: key}}}
+	}
+
+	It("should spread instances proportionally to the weights", func() {
+		breakdown := spotPoolBreakdown(pools, nil, nil, nil, now)
+		for i := 0; i < 8; i++ {
+			pool := chooseSpotPool(pools, breakdown)
+			status := breakdown[spotPoolKey(pool)]
+			status.Instances++
+			breakdown[spotPoolKey(pool)] = status
+		}
+		Expect(breakdown["m5.large-us-east-1a"].Instances).To(Equal(int32(6)))
+		Expect(breakdown["m5a.large-us-east-1b"].Instances).To(Equal(int32(2)))
+	})
+
+	It("should count the instances in each pool", func() {
+		instances := []computev1.Ec2Instance{inPool("m5.large-us-east-1a"), inPool("m5.large-us-east-1a"), inPool("m5a.large-us-east-1b")}
+		breakdown := spotPoolBreakdown(pools, nil, instances, nil, now)
+		Expect(breakdown["m5.large-us-east-1a"].Instances).To(Equal(int32(2)))
+		Expect(breakdown["m5a.large-us-east-1b"].Instances).To(Equal(int32(1)))
+	})
+
+	It("should lower the weight of a pool with interruptions", func() {
+		breakdown := spotPoolBreakdown(pools, nil, nil, map[string]int32{"m5.large-us-east-1a": 3}, now)
+		status := breakdown["m5.large-us-east-1a"]
+		Expect(status.Interruptions).To(Equal(int32(3)))
+		Expect(status.EffectiveWeight).To(Equal(0.75))
+		Expect(status.LastInterruptionAt.Time).To(Equal(now))
+
+		// With an equal instance count the replacement goes to the healthier pool.
+		breakdown["m5.large-us-east-1a"] = computev1.PoolStatus{Instances: 1, Interruptions: 3, EffectiveWeight: 0.75}
+		breakdown["m5a.large-us-east-1b"] = computev1.PoolStatus{Instances: 1, EffectiveWeight: 1}
+		Expect(spotPoolKey(chooseSpotPool(pools, breakdown))).To(Equal("m5a.large-us-east-1b"))
+	})
+
+	It("should restore the weight after an hour without interruptions", func() {
+		last := metav1.NewTime(now.Add(-2 * time.Hour))
+		previous := map[string]computev1.PoolStatus{"m5.large-us-east-1a": {Interruptions: 4, LastInterruptionAt: &last}}
+		status := spotPoolBreakdown(pools, previous, nil, nil, now)["m5.large-us-east-1a"]
+		Expect(status.Interruptions).To(BeZero())
+		Expect(status.EffectiveWeight).To(Equal(3.0))
+	})
+
+	It("should make the instance a Spot instance in the pool", func() {
+		inst := &computev1.Ec2Instance{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{}}}
+		pool := &computev1.SpotCapacityPool{InstanceType: "c5.large", AvailabilityZone: "us-east-1c", MaxPrice: "0.04"}
+		applySpotPool(inst, pool)
+		Expect(inst.Spec.InstanceType).To(Equal("c5.large"))
+		Expect(inst.Spec.AvailabilityZone).To(Equal("us-east-1c"))
+		Expect(inst.Spec.SpotOptions).To(Equal(computev1.SpotOptionsSpec{Enabled: true, MaxPrice: "0.04"}))
+		Expect(inst.Labels).To(HaveKeyWithValue(computev1.SpotPoolLabel, "c5.large-us-east-1c"))
+	})
+})
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	"github.com/bshaw7/operator-repo/internal/statemachine"
+)
+
+// spotInterruptedState is recorded in status.state from the moment AWS announces the interruption
+// until the instance is actually stopped or terminated.
+const spotInterruptedState = "SpotInterrupted"
+
+// spotMarketOptions returns the Spot market options to launch with. Stopped and hibernated Spot
+// instances can only come back through a persistent request; a one-time request suffices when the
+// instance is terminated and replaced.
+func spotMarketOptions(spot computev1.SpotOptionsSpec) *ec2types.InstanceMarketOptionsRequest {
+	options := &ec2types.SpotMarketOptions{
+		SpotInstanceType:             ec2types.SpotInstanceTypeOneTime,
+		InstanceInterruptionBehavior: ec2types.InstanceInterruptionBehaviorTerminate,
+	}
+	switch spot.InterruptionBehavior {
+	case computev1.SpotInterruptionStop:
+		options.SpotInstanceType = ec2types.SpotInstanceTypePersistent
+		options.InstanceInterruptionBehavior = ec2types.InstanceInterruptionBehaviorStop
+	case computev1.SpotInterruptionHibernate:
+		options.SpotInstanceType = ec2types.SpotInstanceTypePersistent
+		options.InstanceInterruptionBehavior = ec2types.InstanceInterruptionBehaviorHibernate
+	}
+	if spot.MaxPrice != "" {
+		options.MaxPrice = aws.String(spot.MaxPrice)
+	}
+	return &ec2types.InstanceMarketOptionsRequest{
+		MarketType:  ec2types.MarketTypeSpot,
+		SpotOptions: options,
+	}
+}
+
+// spotCapacityUnavailable reports whether RunInstances failed because no Spot capacity could be had
+// at the maximum price.
+func spotCapacityUnavailable(err error) bool {
+	for _, code := range []string{"InsufficientInstanceCapacity", "SpotMaxPriceTooLow", "MaxSpotInstanceCountExceeded"} {
+		if strings.Contains(err.Error(), code) {
+			return true
+		}
+	}
+	return false
+}
+
+// instanceLifecycle returns how the instance is billed; AWS leaves the lifecycle of On-Demand
+// instances empty.
+func instanceLifecycle(awsInstance *ec2types.Instance) string {
+	if awsInstance.InstanceLifecycle == "" {
+		return "on-demand"
+	}
+	return string(awsInstance.InstanceLifecycle)
+}
+
+// spotInterruptionNotice reports whether the status of a Spot request announces an interruption,
+// which AWS does two minutes ahead.
+func spotInterruptionNotice(request *ec2types.SpotInstanceRequest) bool {
+	if request == nil || request.Status == nil {
+		return false
+	}
+	switch aws.ToString(request.Status.Code) {
+	case "marked-for-termination", "marked-for-stop", "marked-for-hibernation", "instance-terminated-by-price",
+		"instance-terminated-no-capacity", "instance-stopped-by-price", "instance-stopped-no-capacity":
+		return true
+	}
+	return false
+}
+
+// reconcileSpotInterruption checks the Spot request of a running Spot instance for an interruption
+// notice. While one is out, status.state is SpotInterrupted and the SpotInterrupted condition is
+// set, so the next syncs watch the instance closely and replace it once it is gone.
+func (r *Ec2InstanceReconciler) reconcileSpotInterruption(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	if awsInstance.InstanceLifecycle != ec2types.InstanceLifecycleTypeSpot || awsInstance.SpotInstanceRequestId == nil ||
+		awsInstance.State.Name != ec2types.InstanceStateNameRunning {
+		return nil
+	}
+	result, err := instanceAWSClient(ec2Instance).DescribeSpotInstanceRequests(ctx, &ec2.DescribeSpotInstanceRequestsInput{
+		SpotInstanceRequestIds: []string{aws.ToString(awsInstance.SpotInstanceRequestId)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe Spot request: %w", err)
+	}
+	var request *ec2types.SpotInstanceRequest
+	if len(result.SpotInstanceRequests) > 0 {
+		request = &result.SpotInstanceRequests[0]
+	}
+	if !spotInterruptionNotice(request) {
+		// A stopped or hibernated instance that AWS brought back is no longer interrupted.
+		apimeta.RemoveStatusCondition(&ec2Instance.Status.Conditions, computev1.ConditionSpotInterrupted)
+		return nil
+	}
+
+	ec2Instance.Status.State = spotInterruptedState
+	message := fmt.Sprintf("AWS is reclaiming Spot instance %s: %s", ec2Instance.Status.InstanceID, aws.ToString(request.Status.Message))
+	if r.setSpotInterrupted(ec2Instance, "InterruptionNotice", message) {
+		log.FromContext(ctx).Info("Spot interruption notice", "instanceID", ec2Instance.Status.InstanceID, "code", aws.ToString(request.Status.Code))
+	}
+	return nil
+}
+
+// setSpotInterrupted sets the SpotInterrupted condition and warns about it whenever it changes. The
+// interruption is counted once, when the condition first turns true.
+func (r *Ec2InstanceReconciler) setSpotInterrupted(ec2Instance *computev1.Ec2Instance, reason, message string) bool {
+	if !apimeta.IsStatusConditionTrue(ec2Instance.Status.Conditions, computev1.ConditionSpotInterrupted) {
+		spotInterruptions.Inc()
+	}
+	changed := apimeta.SetStatusCondition(&ec2Instance.Status.Conditions, metav1.Condition{
+		Type:               computev1.ConditionSpotInterrupted,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: ec2Instance.Generation,
+	})
+	if changed {
+		r.Recorder.Event(ec2Instance, corev1.EventTypeWarning, computev1.ConditionSpotInterrupted, message)
+	}
+	return changed
+}
+
+// spotShuttingDown reports whether AWS is shutting the instance down, or stopping it, to reclaim
+// Spot capacity.
+func spotShuttingDown(awsInstance *ec2types.Instance) bool {
+	if !spotInterrupted(awsInstance) || awsInstance.State == nil {
+		return false
+	}
+	switch awsInstance.State.Name {
+	case ec2types.InstanceStateNameShuttingDown, ec2types.InstanceStateNameStopping:
+		return true
+	}
+	return false
+}
+
+// reconcileSpotShutdown records a Spot instance that AWS is already shutting down, which is the
+// first sign of an interruption that came without a notice. It reports whether it is.
+func (r *Ec2InstanceReconciler) reconcileSpotShutdown(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) bool {
+	if !spotShuttingDown(awsInstance) {
+		return false
+	}
+	message := fmt.Sprintf("AWS is reclaiming Spot instance %s: %s", ec2Instance.Status.InstanceID, aws.ToString(awsInstance.StateReason.Message))
+	if r.setSpotInterrupted(ec2Instance, "SpotInstanceShutdown", message) {
+		log.FromContext(ctx).Info("Spot instance is being reclaimed", "instanceID", ec2Instance.Status.InstanceID, "state", awsInstance.State.Name)
+	}
+	return true
+}
+
+// spotRebidDue reports whether spec.spotOptions.rebidOnInterruption asks to replace the instance
+// now, while AWS is still terminating it.
+func spotRebidDue(ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) bool {
+	return ec2Instance.Spec.SpotOptions.RebidOnInterruption && spotShuttingDown(awsInstance) &&
+		awsInstance.State.Name == ec2types.InstanceStateNameShuttingDown
+}
+
+// spotInterrupted reports whether AWS stopped or terminated the instance to reclaim Spot capacity.
+func spotInterrupted(awsInstance *ec2types.Instance) bool {
+	if awsInstance == nil || awsInstance.StateReason == nil {
+		return false
+	}
+	switch aws.ToString(awsInstance.StateReason.Code) {
+	case "Server.SpotInstanceTermination", "Server.SpotInstanceShutdown":
+		return true
+	}
+	return false
+}
+
+// markSpotInterrupted records that the Spot instance was reclaimed and leaves the replacement to
+// the Ec2InstanceSet, which may launch it in another capacity pool. The instance is recorded as
+// terminated so deleting the object does not try to terminate it again.
+func (r *Ec2InstanceReconciler) markSpotInterrupted(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	if apimeta.IsStatusConditionTrue(ec2Instance.Status.Conditions, computev1.ConditionSpotInterrupted) {
+		return nil
+	}
+	message := fmt.Sprintf("Spot instance %s was interrupted by AWS", ec2Instance.Status.InstanceID)
+	log.FromContext(ctx).Info("Spot instance interrupted", "instanceID", ec2Instance.Status.InstanceID)
+	ec2Instance.Status.State = string(statemachine.Terminated)
+	apimeta.SetStatusCondition(&ec2Instance.Status.Conditions, metav1.Condition{
+		Type:               computev1.ConditionSpotInterrupted,
+		Status:             metav1.ConditionTrue,
+		Reason:             "SpotCapacityReclaimed",
+		Message:            message,
+		ObservedGeneration: ec2Instance.Generation,
+	})
+	if err := r.Status().Update(ctx, ec2Instance); err != nil {
+		return err
+	}
+	spotInterruptions.Inc()
+	r.Recorder.Event(ec2Instance, corev1.EventTypeWarning, computev1.ConditionSpotInterrupted, message)
+	return nil
+}
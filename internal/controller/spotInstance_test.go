@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("Spot instances", func() {
+	It("should launch from a one-time request when the instance is terminated on interruption", func() {
+		options := spotMarketOptions(computev1.SpotOptionsSpec{Enabled: true, MaxPrice: "0.05"})
+		Expect(options.MarketType).To(Equal(ec2types.MarketTypeSpot))
+		Expect(options.SpotOptions.SpotInstanceType).To(Equal(ec2types.SpotInstanceTypeOneTime))
+		Expect(options.SpotOptions.InstanceInterruptionBehavior).To(Equal(ec2types.InstanceInterruptionBehaviorTerminate))
+		Expect(aws.ToString(options.SpotOptions.MaxPrice)).To(Equal("0.05"))
+	})
+
+	It("should launch from a persistent request when the instance is kept on interruption", func() {
+		options := spotMarketOptions(computev1.SpotOptionsSpec{Enabled: true, InterruptionBehavior: computev1.SpotInterruptionHibernate})
+		Expect(options.SpotOptions.SpotInstanceType).To(Equal(ec2types.SpotInstanceTypePersistent))
+		Expect(options.SpotOptions.InstanceInterruptionBehavior).To(Equal(ec2types.InstanceInterruptionBehaviorHibernate))
+		Expect(options.SpotOptions.MaxPrice).To(BeNil())
+	})
+
+	It("should only fall back to On-Demand when Spot capacity is missing", func() {
+		Expect(spotCapacityUnavailable(errors.New("api error InsufficientInstanceCapacity: no capacity"))).To(BeTrue())
+		Expect(spotCapacityUnavailable(errors.New("api error SpotMaxPriceTooLow: price"))).To(BeTrue())
+		Expect(spotCapacityUnavailable(errors.New("api error InvalidAMIID.NotFound"))).To(BeFalse())
+	})
+
+	It("should recognize interruption notices", func() {
+		request := func(code string) *ec2types.SpotInstanceRequest {
+			return &ec2types.SpotInstanceRequest{Status: &ec2types.SpotInstanceStatus{Code: aws.String(code)}}
+		}
+		Expect(spotInterruptionNotice(request("marked-for-termination"))).To(BeTrue())
+		Expect(spotInterruptionNotice(request("marked-for-stop"))).To(BeTrue())
+		Expect(spotInterruptionNotice(request("fulfilled"))).To(BeFalse())
+		Expect(spotInterruptionNotice(nil)).To(BeFalse())
+	})
+
+	It("should detect Spot instances AWS is shutting down", func() {
+		shutdown := func(state ec2types.InstanceStateName, code string) *ec2types.Instance {
+			return &ec2types.Instance{
+				State:       &ec2types.InstanceState{Name: state},
+				StateReason: &ec2types.StateReason{Code: aws.String(code), Message: aws.String(code + ": reclaimed")},
+			}
+		}
+		Expect(spotShuttingDown(shutdown(ec2types.InstanceStateNameShuttingDown, "Server.SpotInstanceShutdown"))).To(BeTrue())
+		Expect(spotShuttingDown(shutdown(ec2types.InstanceStateNameStopping, "Server.SpotInstanceShutdown"))).To(BeTrue())
+		Expect(spotShuttingDown(shutdown(ec2types.InstanceStateNameShuttingDown, "Client.UserInitiatedShutdown"))).To(BeFalse())
+		Expect(spotShuttingDown(shutdown(ec2types.InstanceStateNameTerminated, "Server.SpotInstanceShutdown"))).To(BeFalse())
+
+		inst := &computev1.Ec2Instance{}
+		Expect(spotRebidDue(inst, shutdown(ec2types.InstanceStateNameShuttingDown, "Server.SpotInstanceShutdown"))).To(BeFalse())
+		inst.Spec.SpotOptions.RebidOnInterruption = true
+		Expect(spotRebidDue(inst, shutdown(ec2types.InstanceStateNameShuttingDown, "Server.SpotInstanceShutdown"))).To(BeTrue())
+		// A stopped Spot instance comes back by itself.
+		Expect(spotRebidDue(inst, shutdown(ec2types.InstanceStateNameStopping, "Server.SpotInstanceShutdown"))).To(BeFalse())
+	})
+
+	It("should warn and count each interruption once", func() {
+		recorder := record.NewFakeRecorder(10)
+		r := &Ec2InstanceReconciler{Recorder: recorder}
+		inst := &computev1.Ec2Instance{}
+		inst.Status.InstanceID = "i-0123456789abcdef0"
+		awsInstance := &ec2types.Instance{
+			State:       &ec2types.InstanceState{Name: ec2types.InstanceStateNameShuttingDown},
+			StateReason: &ec2types.StateReason{Code: aws.String("Server.SpotInstanceShutdown"), Message: aws.String("Server.SpotInstanceShutdown: reclaimed")},
+		}
+		before := testutil.ToFloat64(spotInterruptions)
+
+		Expect(r.reconcileSpotShutdown(context.Background(), inst, awsInstance)).To(BeTrue())
+		Expect(r.reconcileSpotShutdown(context.Background(), inst, awsInstance)).To(BeTrue())
+		Expect(testutil.ToFloat64(spotInterruptions)).To(Equal(before + 1))
+		Expect(apimeta.IsStatusConditionTrue(inst.Status.Conditions, computev1.ConditionSpotInterrupted)).To(BeTrue())
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(HavePrefix("Warning SpotInterrupted"))
+	})
+
+	It("should report On-Demand instances as on-demand", func() {
+		Expect(instanceLifecycle(&ec2types.Instance{})).To(Equal("on-demand"))
+		Expect(instanceLifecycle(&ec2types.Instance{InstanceLifecycle: ec2types.InstanceLifecycleTypeSpot})).To(Equal("spot"))
+	})
+})
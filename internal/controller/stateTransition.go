@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	"github.com/bshaw7/operator-repo/internal/statemachine"
+)
+
+// transitionAllowed reports whether the instance may be moved from its recorded state to the given
+// state. A refused transition is recorded in the InvalidStateTransition condition, which the caller
+// persists with the next status update, and the action must not be attempted.
+func (r *Ec2InstanceReconciler) transitionAllowed(ctx context.Context, ec2Instance *computev1.Ec2Instance, action string, to statemachine.State) bool {
+	from := statemachine.State(ec2Instance.Status.State)
+	if statemachine.IsValidTransition(from, to) {
+		apimeta.RemoveStatusCondition(&ec2Instance.Status.Conditions, computev1.ConditionInvalidStateTransition)
+		return true
+	}
+
+	message := fmt.Sprintf("Cannot %s instance %s: it is %s", action, ec2Instance.Status.InstanceID, from)
+	log.FromContext(ctx).Info("Refusing invalid state transition", "instanceID", ec2Instance.Status.InstanceID, "from", from, "to", to)
+	changed := apimeta.SetStatusCondition(&ec2Instance.Status.Conditions, metav1.Condition{
+		Type:               computev1.ConditionInvalidStateTransition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "InvalidTransition",
+		Message:            message,
+		ObservedGeneration: ec2Instance.Generation,
+	})
+	if changed {
+		r.Recorder.Event(ec2Instance, corev1.EventTypeWarning, computev1.ConditionInvalidStateTransition, message)
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// DescribeSubnet returns the subnet with the given ID in region, or nil if it does not exist there.
+func DescribeSubnet(ctx context.Context, region, subnetID string) (*ec2types.Subnet, error) {
+	return describeSubnet(ctx, awsClient(region), region, subnetID)
+}
+
+// subnetAPI is the part of the EC2 API that looks up subnets.
+type subnetAPI interface {
+	DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+}
+
+// describeSubnet is DescribeSubnet with a given client, for subnets in another account.
+func describeSubnet(ctx context.Context, ec2Client subnetAPI, region, subnetID string) (*ec2types.Subnet, error) {
+	result, err := ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{SubnetIds: []string{subnetID}})
+	if err != nil {
+		if strings.Contains(err.Error(), "InvalidSubnetID") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to describe subnet %s in %s: %w", subnetID, region, err)
+	}
+	if len(result.Subnets) == 0 {
+		return nil, nil
+	}
+	return &result.Subnets[0], nil
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// managedByTagKey is put on every instance the operator launches, whatever spec.tags says.
+	managedByTagKey   = "managedBy"
+	managedByTagValue = "ec2-operator"
+)
+
+// managedInstanceTags returns the tags the operator keeps in line with the spec: spec.tags, the
+// Name rendered from spec.namePattern and the managedBy tag.
+func managedInstanceTags(ec2Instance *computev1.Ec2Instance) map[string]string {
+	tags := make(map[string]string, len(ec2Instance.Spec.Tags)+2)
+	maps.Copy(tags, ec2Instance.Spec.Tags)
+	if ec2Instance.Status.InstanceName != "" {
+		tags["Name"] = ec2Instance.Status.InstanceName
+	}
+	tags[managedByTagKey] = managedByTagValue
+	return tags
+}
+
+// tagDrift returns the tags to set so the instance carries desired, and the keys to remove because
+// they were applied from the spec before (previous) and are gone from it now. Tags the operator
+// never managed, e.g. ones added in the console or by AWS, are left alone.
+func tagDrift(desired map[string]string, actual []ec2types.Tag, previous []string) (set []ec2types.Tag, remove []string) {
+	current := make(map[string]string, len(actual))
+	for _, tag := range actual {
+		current[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	for key, value := range desired {
+		if existing, ok := current[key]; !ok || existing != value {
+			set = append(set, ec2types.Tag{Key: aws.String(key), Value: aws.String(value)})
+		}
+	}
+	for _, key := range previous {
+		if _, ok := desired[key]; !ok && key != managedByTagKey {
+			if _, ok := current[key]; ok {
+				remove = append(remove, key)
+			}
+		}
+	}
+	sort.Slice(set, func(i, j int) bool { return aws.ToString(set[i].Key) < aws.ToString(set[j].Key) })
+	sort.Strings(remove)
+	return set, remove
+}
+
+// reconcileTagDrift converges the tags of the EC2 instance on spec.tags, undoing changes made
+// outside the operator and removing tags dropped from the spec. It runs on every sync and records
+// the time in status.lastTagSyncTime.
+func (r *Ec2InstanceReconciler) reconcileTagDrift(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) error {
+	desired := managedInstanceTags(ec2Instance)
+	set, remove := tagDrift(desired, awsInstance.Tags, ec2Instance.Status.ManagedTagKeys)
+	ec2Client := r.instanceEC2Client(ec2Instance)
+
+	if len(set) > 0 {
+		if _, err := ec2Client.CreateTags(ctx, &ec2.CreateTagsInput{Resources: []string{ec2Instance.Status.InstanceID}, Tags: set}); err != nil {
+			return fmt.Errorf("failed to set tags: %w", err)
+		}
+	}
+	if len(remove) > 0 {
+		tags := make([]ec2types.Tag, 0, len(remove))
+		for _, key := range remove {
+			tags = append(tags, ec2types.Tag{Key: aws.String(key)})
+		}
+		if _, err := ec2Client.DeleteTags(ctx, &ec2.DeleteTagsInput{Resources: []string{ec2Instance.Status.InstanceID}, Tags: tags}); err != nil {
+			return fmt.Errorf("failed to remove tags: %w", err)
+		}
+	}
+	ec2Instance.Status.ManagedTagKeys = slices.Sorted(maps.Keys(desired))
+	now := metav1.Now()
+	ec2Instance.Status.LastTagSyncTime = &now
+	if len(set) == 0 && len(remove) == 0 {
+		return nil
+	}
+	// Drift on a spec that was already synced was caused outside the operator; a changed spec is not.
+	if ec2Instance.Status.ObservedGeneration == ec2Instance.Generation {
+		tagDriftCorrections.Inc()
+	}
+
+	keys := make([]string, 0, len(set))
+	for _, tag := range set {
+		keys = append(keys, aws.ToString(tag.Key))
+	}
+	log.FromContext(ctx).Info("Corrected tag drift", "instanceID", ec2Instance.Status.InstanceID, "set", keys, "removed", remove)
+	r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "TagsUpdated", "Set tags %v and removed tags %v", keys, remove)
+	return nil
+}
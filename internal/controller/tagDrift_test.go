@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/client-go/tools/record"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	awsclient "github.com/bshaw7/operator-repo/internal/aws"
+)
+
+var _ = Describe("Tag drift", func() {
+	tag := func(key, value string) ec2types.Tag {
+		return ec2types.Tag{Key: aws.String(key), Value: aws.String(value)}
+	}
+
+	It("should always manage the managedBy tag and the rendered name", func() {
+		inst := &computev1.Ec2Instance{
+			Spec:   computev1.Ec2InstanceSpec{Tags: map[string]string{"Name": "web", "Team": "a"}},
+			Status: computev1.Ec2InstanceStatus{InstanceName: "shop-web-0"},
+		}
+		Expect(managedInstanceTags(inst)).To(Equal(map[string]string{"Name": "shop-web-0", "Team": "a", "managedBy": "ec2-operator"}))
+	})
+
+	It("should set missing and changed tags", func() {
+		set, remove := tagDrift(map[string]string{"Team": "a", "Env": "prod"}, []ec2types.Tag{tag("Team", "b")}, nil)
+		Expect(set).To(Equal([]ec2types.Tag{tag("Env", "prod"), tag("Team", "a")}))
+		Expect(remove).To(BeEmpty())
+	})
+
+	It("should only remove tags it applied before", func() {
+		actual := []ec2types.Tag{tag("Team", "a"), tag("Old", "x"), tag("Console", "y"), tag("managedBy", "ec2-operator")}
+		set, remove := tagDrift(map[string]string{"Team": "a", "managedBy": "ec2-operator"}, actual, []string{"Team", "Old", "Gone", "managedBy"})
+		Expect(set).To(BeEmpty())
+		Expect(remove).To(Equal([]string{"Old"}))
+	})
+
+	It("should record the sync time and only count drift on a synced spec as a correction", func() {
+		fake := &awsclient.FakeEC2Client{}
+		r := &Ec2InstanceReconciler{EC2Client: fake, Recorder: record.NewFakeRecorder(10)}
+		inst := &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{Tags: map[string]string{"Team": "a"}}}
+		inst.Generation = 2
+		inst.Status.InstanceID = "i-1"
+		awsInstance := &ec2types.Instance{Tags: []ec2types.Tag{tag("Team", "a"), tag("managedBy", "ec2-operator")}}
+		corrections := testutil.ToFloat64(tagDriftCorrections)
+
+		Expect(r.reconcileTagDrift(context.Background(), inst, awsInstance)).To(Succeed())
+		Expect(inst.Status.LastTagSyncTime).NotTo(BeNil())
+		Expect(fake.Calls()).To(BeEmpty())
+
+		// A spec change that was not synced yet is no drift made outside the operator.
+		inst.Spec.Tags["Team"] = "b"
+		Expect(r.reconcileTagDrift(context.Background(), inst, awsInstance)).To(Succeed())
+		Expect(fake.CallsTo("CreateTags")).To(HaveLen(1))
+		Expect(testutil.ToFloat64(tagDriftCorrections)).To(Equal(corrections))
+
+		inst.Status.ObservedGeneration = inst.Generation
+		Expect(r.reconcileTagDrift(context.Background(), inst, awsInstance)).To(Succeed())
+		Expect(fake.CallsTo("CreateTags")).To(HaveLen(2))
+		Expect(testutil.ToFloat64(tagDriftCorrections)).To(Equal(corrections + 1))
+	})
+})
@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// tagPolicyCacheTTL is how long an effective tag policy is reused. Policies change rarely and
+	// every instance in the account shares the same one.
+	tagPolicyCacheTTL = 10 * time.Minute
+	// tagPolicyEvaluationInterval is how often LastEvaluatedAt is refreshed when the result is unchanged.
+	tagPolicyEvaluationInterval = time.Hour
+)
+
+// organizations is the AWS Organizations API. It is a global service served from us-east-1.
+var organizations = awsJSONService{
+	Endpoint:     "https://organizations.us-east-1.amazonaws.com",
+	SigningName:  "organizations",
+	Region:       "us-east-1",
+	TargetPrefix: "AWSOrganizationsV20161128",
+}
+
+// tagPolicyRule is one tag of an effective tag policy: the expected key capitalization and the allowed values.
+type tagPolicyRule struct {
+	Key    string
+	Values []string
+}
+
+// cachedTagPolicy is the effective tag policy of one account and when it was fetched.
+type cachedTagPolicy struct {
+	rules     map[string]tagPolicyRule // keyed by lower-case tag key
+	fetchedAt time.Time
+}
+
+// tagPolicyCache holds the effective tag policy of each account, keyed by the spec.roleARN that
+// leads there; "" is the account the operator runs in.
+var tagPolicyCache = struct {
+	sync.Mutex
+	byRole map[string]cachedTagPolicy
+}{byRole: map[string]cachedTagPolicy{}}
+
+// effectiveTagPolicy returns the tag policy rules that apply to the account of ec2Instance, or nil
+// when there are none.
+func effectiveTagPolicy(ctx context.Context, ec2Instance *computev1.Ec2Instance) (map[string]tagPolicyRule, error) {
+	tagPolicyCache.Lock()
+	defer tagPolicyCache.Unlock()
+	roleARN := ec2Instance.Spec.RoleARN
+	if cached, ok := tagPolicyCache.byRole[roleARN]; ok && time.Since(cached.fetchedAt) < tagPolicyCacheTTL {
+		return cached.rules, nil
+	}
+
+	api := organizations
+	api.Credentials = instanceCredentials(ec2Instance)
+
+	out := struct {
+		EffectivePolicy struct {
+			PolicyContent string
+		}
+	}{}
+	err := api.call(ctx, "GetEffectivePolicy", map[string]string{"PolicyType": "TAG_POLICY"}, &out)
+	switch {
+	case err != nil && (strings.Contains(err.Error(), "AWSOrganizationsNotInUseException") ||
+		strings.Contains(err.Error(), "EffectivePolicyNotFoundException")):
+		// No organization or no tag policy attached: nothing to comply with.
+		out.EffectivePolicy.PolicyContent = ""
+	case err != nil:
+		return nil, err
+	}
+
+	rules, err := parseTagPolicy(out.EffectivePolicy.PolicyContent)
+	if err != nil {
+		return nil, err
+	}
+	tagPolicyCache.byRole[roleARN] = cachedTagPolicy{rules: rules, fetchedAt: time.Now()}
+	return rules, nil
+}
+
+// parseTagPolicy reads the "tags" section of an effective tag policy document. Effective policies
+// normally have their inheritance operators resolved, but "@@assign" wrappers are accepted as well.
+func parseTagPolicy(content string) (map[string]tagPolicyRule, error) {
+	if content == "" {
+		return nil, nil
+	}
+
+	doc := struct {
+		Tags map[string]struct {
+			TagKey   json.RawMessage `json:"tag_key"`
+			TagValue json.RawMessage `json:"tag_value"`
+		} `json:"tags"`
+	}{}
+	if err := json.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse tag policy: %w", err)
+	}
+	if len(doc.Tags) == 0 {
+		return nil, nil
+	}
+
+	rules := map[string]tagPolicyRule{}
+	for name, tag := range doc.Tags {
+		rule := tagPolicyRule{Key: name}
+		var key string
+		if unmarshalPolicyValue(tag.TagKey, &key) == nil && key != "" {
+			rule.Key = key
+		}
+		_ = unmarshalPolicyValue(tag.TagValue, &rule.Values)
+		rules[strings.ToLower(rule.Key)] = rule
+	}
+	return rules, nil
+}
+
+// unmarshalPolicyValue decodes a policy value that may be wrapped in {"@@assign": ...}.
+func unmarshalPolicyValue(raw json.RawMessage, into any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	wrapped := struct {
+		Assign json.RawMessage `json:"@@assign"`
+	}{}
+	if json.Unmarshal(raw, &wrapped) == nil && len(wrapped.Assign) > 0 {
+		raw = wrapped.Assign
+	}
+	return json.Unmarshal(raw, into)
+}
+
+// tagPolicyViolations returns the policy keys the tags violate. Like AWS, a tag only violates the
+// policy when it is present with the wrong key capitalization or a value that is not allowed; a
+// missing tag is compliant.
+func tagPolicyViolations(rules map[string]tagPolicyRule, tags []ec2types.Tag) []string {
+	var violated []string
+	for _, tag := range tags {
+		key, value := aws.ToString(tag.Key), aws.ToString(tag.Value)
+		rule, ok := rules[strings.ToLower(key)]
+		if !ok {
+			continue
+		}
+		if key != rule.Key || (len(rule.Values) > 0 && !tagValueAllowed(rule.Values, value)) {
+			violated = append(violated, rule.Key)
+		}
+	}
+	sort.Strings(violated)
+	return violated
+}
+
+// tagValueAllowed matches value against the allowed values, which may end in a "*" wildcard.
+func tagValueAllowed(allowed []string, value string) bool {
+	for _, a := range allowed {
+		if a == value || (strings.HasSuffix(a, "*") && strings.HasPrefix(value, strings.TrimSuffix(a, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileTagPolicyCompliance evaluates the instance tags against the effective tag policy. It only
+// reports: the organization owns the policy, so tags are never changed to comply.
+func (r *Ec2InstanceReconciler) reconcileTagPolicyCompliance(ctx context.Context, ec2Instance *computev1.Ec2Instance, awsInstance *ec2types.Instance) {
+	l := log.FromContext(ctx)
+
+	rules, err := effectiveTagPolicy(ctx, ec2Instance)
+	if err != nil {
+		// Missing organizations permissions must not stop the instance from being managed.
+		l.Error(err, "Failed to get effective tag policy")
+		return
+	}
+	if rules == nil {
+		ec2Instance.Status.TagPolicyCompliance = nil
+		apimeta.RemoveStatusCondition(&ec2Instance.Status.Conditions, computev1.ConditionTagPolicyCompliant)
+		return
+	}
+
+	violated := tagPolicyViolations(rules, awsInstance.Tags)
+	compliant := len(violated) == 0
+
+	previous := ec2Instance.Status.TagPolicyCompliance
+	unchanged := previous != nil && previous.Compliant == compliant && strings.Join(previous.ViolatedKeys, ",") == strings.Join(violated, ",")
+	if unchanged && previous.LastEvaluatedAt != nil && time.Since(previous.LastEvaluatedAt.Time) < tagPolicyEvaluationInterval {
+		return
+	}
+
+	now := metav1.Now()
+	ec2Instance.Status.TagPolicyCompliance = &computev1.TagComplianceStatus{
+		Compliant:       compliant,
+		ViolatedKeys:    violated,
+		LastEvaluatedAt: &now,
+	}
+
+	condition := metav1.Condition{
+		Type:               computev1.ConditionTagPolicyCompliant,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Compliant",
+		Message:            "Instance tags comply with the organization tag policy",
+		ObservedGeneration: ec2Instance.Generation,
+	}
+	if !compliant {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "TagPolicyViolation"
+		condition.Message = fmt.Sprintf("Tags violate the organization tag policy: %s", strings.Join(violated, ", "))
+		if !unchanged {
+			r.Recorder.Eventf(ec2Instance, corev1.EventTypeWarning, "TagPolicyViolation",
+				"Tags violate the organization tag policy: %s", strings.Join(violated, ", "))
+		}
+	}
+	apimeta.SetStatusCondition(&ec2Instance.Status.Conditions, condition)
+}
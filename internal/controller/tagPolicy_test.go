@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tag policy compliance", func() {
+	const policy = `{"tags":{"costcenter":{"tag_key":{"@@assign":"CostCenter"},"tag_value":{"@@assign":["100","200*"]}},` +
+		`"project":{"tag_key":"Project","tag_value":["Phoenix"]}}}`
+
+	tag := func(key, value string) ec2types.Tag {
+		return ec2types.Tag{Key: aws.String(key), Value: aws.String(value)}
+	}
+
+	It("should accept allowed values, wildcards and missing tags", func() {
+		rules, err := parseTagPolicy(policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tagPolicyViolations(rules, []ec2types.Tag{tag("CostCenter", "200-eu"), tag("Owner", "team-a")})).To(BeEmpty())
+	})
+
+	It("should report wrong capitalization and disallowed values", func() {
+		rules, err := parseTagPolicy(policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tagPolicyViolations(rules, []ec2types.Tag{tag("costcenter", "100"), tag("Project", "Pegasus")})).
+			To(Equal([]string{"CostCenter", "Project"}))
+	})
+
+	It("should treat an empty policy as no policy", func() {
+		Expect(parseTagPolicy("")).To(BeNil())
+	})
+})
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// tracerName is the instrumentation scope and service name of the operator's spans.
+const tracerName = "ec2-operator"
+
+// tracer follows the global tracer provider, so spans start being exported once SetupTracing ran.
+var tracer = otel.Tracer(tracerName)
+
+// SetupTracing exports spans over OTLP/HTTP to OTEL_EXPORTER_OTLP_ENDPOINT, e.g. a Jaeger or Tempo
+// collector. Without the variable tracing stays off. The returned function flushes the spans that
+// are still buffered and must be called on shutdown.
+func SetupTracing(ctx context.Context) (func(context.Context) error, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	// The exporter reads the endpoint, headers and TLS settings from the standard OTEL_* variables.
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	resource, err := sdkresource.Merge(sdkresource.Default(),
+		sdkresource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(tracerName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(resource))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// startSpan starts a span for an AWS operation on the instance of ec2Instance.
+func startSpan(ctx context.Context, name string, ec2Instance *computev1.Ec2Instance) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(
+		attribute.String("ec2.instance_id", ec2Instance.Status.InstanceID),
+		attribute.String("ec2.region", ec2Instance.Spec.Region),
+	))
+}
+
+// endSpan marks span as failed when err is set and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
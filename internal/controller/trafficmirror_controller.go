@@ -0,0 +1,315 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// trafficMirrorFinalizer makes sure the session, filter and target are removed from AWS before the object is deleted.
+const trafficMirrorFinalizer = "trafficmirrorsession.compute.cloud.com"
+
+// mirrorProtocolNumbers maps the protocol names of MirrorFilterRule to IANA protocol numbers.
+// "all" has no number; AWS matches every protocol when none is given.
+var mirrorProtocolNumbers = map[string]int32{"icmp": 1, "tcp": 6, "udp": 17}
+
+// TrafficMirrorReconciler mirrors the traffic of an Ec2Instance to an appliance ENI.
+//
+// The AWS resources are created in dependency order (target, filter, filter rules, session) and each
+// ID is written to the status as soon as it exists, so a failed reconcile never creates duplicates.
+type TrafficMirrorReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=trafficmirrorsessions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=trafficmirrorsessions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=trafficmirrorsessions/finalizers,verbs=update
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=ec2instances,verbs=get;list;watch
+
+// Reconcile sets up or tears down the traffic mirror session.
+func (r *TrafficMirrorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	session := &computev1.TrafficMirrorSession{}
+	if err := r.Get(ctx, req.NamespacedName, session); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	source := &computev1.Ec2Instance{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: session.Namespace, Name: session.Spec.SourceEc2InstanceRef.Name}, source)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	sourceFound := err == nil
+
+	if !session.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(session, trafficMirrorFinalizer) {
+			if !sourceFound {
+				// Without the source we do not know the region; the resources cannot be cleaned up.
+				l.Info("Source Ec2Instance is gone, leaving traffic mirror resources for manual cleanup",
+					"sessionID", session.Status.SessionID, "filterID", session.Status.FilterID, "targetID", session.Status.TargetID)
+			} else if err := r.deleteMirrorResources(ctx, instanceAWSClient(source), session); err != nil {
+				l.Error(err, "Failed to delete traffic mirror resources")
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(session, trafficMirrorFinalizer)
+			if err := r.Update(ctx, session); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !sourceFound || source.Status.InstanceID == "" {
+		l.Info("Waiting for source Ec2Instance to be launched", "ec2Instance", session.Spec.SourceEc2InstanceRef.Name)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	if controllerutil.AddFinalizer(session, trafficMirrorFinalizer) {
+		if err := r.Update(ctx, session); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	ec2Client := instanceAWSClient(source)
+
+	sourceENI, sourceVPC, err := primaryNetworkInterface(ctx, ec2Client, source.Status.InstanceID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	targetVPC, err := networkInterfaceVPC(ctx, ec2Client, session.Spec.TargetENIRef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if sourceVPC != targetVPC {
+		msg := fmt.Sprintf("source instance is in %s but target ENI %s is in %s; both must be in the same VPC",
+			sourceVPC, session.Spec.TargetENIRef, targetVPC)
+		if session.Status.Message != msg {
+			session.Status.Message = msg
+			if err := r.Status().Update(ctx, session); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		// Nothing will change until the spec does; the spec update triggers the next reconcile.
+		return ctrl.Result{}, nil
+	}
+
+	if session.Status.TargetID == "" {
+		result, err := ec2Client.CreateTrafficMirrorTarget(ctx, &ec2.CreateTrafficMirrorTargetInput{
+			NetworkInterfaceId: aws.String(session.Spec.TargetENIRef),
+			Description:        aws.String(fmt.Sprintf("%s/%s", session.Namespace, session.Name)),
+			ClientToken:        aws.String(string(session.UID) + "-target"),
+		})
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create traffic mirror target: %w", err)
+		}
+		session.Status.TargetID = aws.ToString(result.TrafficMirrorTarget.TrafficMirrorTargetId)
+		l.Info("Created traffic mirror target", "targetID", session.Status.TargetID)
+		if err := r.Status().Update(ctx, session); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if session.Status.FilterID == "" {
+		result, err := ec2Client.CreateTrafficMirrorFilter(ctx, &ec2.CreateTrafficMirrorFilterInput{
+			Description: aws.String(fmt.Sprintf("%s/%s", session.Namespace, session.Name)),
+			ClientToken: aws.String(string(session.UID) + "-filter"),
+		})
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create traffic mirror filter: %w", err)
+		}
+		session.Status.FilterID = aws.ToString(result.TrafficMirrorFilter.TrafficMirrorFilterId)
+		l.Info("Created traffic mirror filter", "filterID", session.Status.FilterID)
+		if err := r.Status().Update(ctx, session); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	specChanged := session.Status.ObservedGeneration != session.Generation
+
+	// Rules cannot be matched up with the spec one by one, so a spec change replaces all of them.
+	if specChanged && len(session.Status.FilterRuleIDs) > 0 {
+		if err := deleteMirrorFilterRules(ctx, ec2Client, session.Status.FilterRuleIDs); err != nil {
+			return ctrl.Result{}, err
+		}
+		session.Status.FilterRuleIDs = nil
+		if err := r.Status().Update(ctx, session); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if len(session.Status.FilterRuleIDs) == 0 {
+		for i, rule := range session.Spec.FilterRules {
+			ruleID, err := createMirrorFilterRule(ctx, ec2Client, session.Status.FilterID, int32(i+1), rule)
+			if err != nil {
+				// Keep the rules created so far so they are replaced, not leaked, on the next attempt.
+				if updateErr := r.Status().Update(ctx, session); updateErr != nil {
+					l.Error(updateErr, "Failed to update status")
+				}
+				return ctrl.Result{}, err
+			}
+			session.Status.FilterRuleIDs = append(session.Status.FilterRuleIDs, ruleID)
+		}
+	}
+
+	if session.Status.SessionID == "" {
+		input := &ec2.CreateTrafficMirrorSessionInput{
+			NetworkInterfaceId:    aws.String(sourceENI),
+			TrafficMirrorTargetId: aws.String(session.Status.TargetID),
+			TrafficMirrorFilterId: aws.String(session.Status.FilterID),
+			SessionNumber:         aws.Int32(session.Spec.SessionNumber),
+			Description:           aws.String(fmt.Sprintf("%s/%s", session.Namespace, session.Name)),
+			ClientToken:           aws.String(string(session.UID) + "-session"),
+		}
+		if session.Spec.PacketLength > 0 {
+			input.PacketLength = aws.Int32(session.Spec.PacketLength)
+		}
+		result, err := ec2Client.CreateTrafficMirrorSession(ctx, input)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create traffic mirror session: %w", err)
+		}
+		session.Status.SessionID = aws.ToString(result.TrafficMirrorSession.TrafficMirrorSessionId)
+		l.Info("Created traffic mirror session", "sessionID", session.Status.SessionID, "sourceENI", sourceENI)
+	} else if specChanged {
+		input := &ec2.ModifyTrafficMirrorSessionInput{
+			TrafficMirrorSessionId: aws.String(session.Status.SessionID),
+			SessionNumber:          aws.Int32(session.Spec.SessionNumber),
+		}
+		if session.Spec.PacketLength > 0 {
+			input.PacketLength = aws.Int32(session.Spec.PacketLength)
+		} else {
+			input.RemoveFields = []ec2types.TrafficMirrorSessionField{ec2types.TrafficMirrorSessionFieldPacketLength}
+		}
+		if _, err := ec2Client.ModifyTrafficMirrorSession(ctx, input); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to modify traffic mirror session: %w", err)
+		}
+		l.Info("Updated traffic mirror session", "sessionID", session.Status.SessionID)
+	}
+
+	session.Status.Message = ""
+	session.Status.ObservedGeneration = session.Generation
+	if err := r.Status().Update(ctx, session); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// deleteMirrorResources removes the session, filter rules, filter and target, in that order.
+func (r *TrafficMirrorReconciler) deleteMirrorResources(ctx context.Context, ec2Client *ec2.Client, session *computev1.TrafficMirrorSession) error {
+	if id := session.Status.SessionID; id != "" {
+		_, err := ec2Client.DeleteTrafficMirrorSession(ctx, &ec2.DeleteTrafficMirrorSessionInput{TrafficMirrorSessionId: aws.String(id)})
+		if err != nil && !strings.Contains(err.Error(), "NotFound") {
+			return fmt.Errorf("failed to delete traffic mirror session: %w", err)
+		}
+	}
+	if err := deleteMirrorFilterRules(ctx, ec2Client, session.Status.FilterRuleIDs); err != nil {
+		return err
+	}
+	if id := session.Status.FilterID; id != "" {
+		_, err := ec2Client.DeleteTrafficMirrorFilter(ctx, &ec2.DeleteTrafficMirrorFilterInput{TrafficMirrorFilterId: aws.String(id)})
+		if err != nil && !strings.Contains(err.Error(), "NotFound") {
+			return fmt.Errorf("failed to delete traffic mirror filter: %w", err)
+		}
+	}
+	if id := session.Status.TargetID; id != "" {
+		_, err := ec2Client.DeleteTrafficMirrorTarget(ctx, &ec2.DeleteTrafficMirrorTargetInput{TrafficMirrorTargetId: aws.String(id)})
+		if err != nil && !strings.Contains(err.Error(), "NotFound") {
+			return fmt.Errorf("failed to delete traffic mirror target: %w", err)
+		}
+	}
+	return nil
+}
+
+// createMirrorFilterRule adds one accept rule to the filter and returns its ID.
+func createMirrorFilterRule(ctx context.Context, ec2Client *ec2.Client, filterID string, ruleNumber int32, rule computev1.MirrorFilterRule) (string, error) {
+	cidr := rule.CIDR
+	if cidr == "" {
+		cidr = "0.0.0.0/0"
+	}
+	input := &ec2.CreateTrafficMirrorFilterRuleInput{
+		TrafficMirrorFilterId: aws.String(filterID),
+		TrafficDirection:      ec2types.TrafficDirection(rule.Direction),
+		RuleNumber:            aws.Int32(ruleNumber),
+		RuleAction:            ec2types.TrafficMirrorRuleActionAccept,
+		SourceCidrBlock:       aws.String("0.0.0.0/0"),
+		DestinationCidrBlock:  aws.String("0.0.0.0/0"),
+	}
+	if rule.Direction == string(ec2types.TrafficDirectionIngress) {
+		input.SourceCidrBlock = aws.String(cidr)
+	} else {
+		input.DestinationCidrBlock = aws.String(cidr)
+	}
+	if number, ok := mirrorProtocolNumbers[rule.Protocol]; ok {
+		input.Protocol = aws.Int32(number)
+	}
+
+	result, err := ec2Client.CreateTrafficMirrorFilterRule(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to create traffic mirror filter rule %d: %w", ruleNumber, err)
+	}
+	return aws.ToString(result.TrafficMirrorFilterRule.TrafficMirrorFilterRuleId), nil
+}
+
+// deleteMirrorFilterRules deletes the given filter rules, ignoring rules that are already gone.
+func deleteMirrorFilterRules(ctx context.Context, ec2Client *ec2.Client, ruleIDs []string) error {
+	for _, id := range ruleIDs {
+		_, err := ec2Client.DeleteTrafficMirrorFilterRule(ctx, &ec2.DeleteTrafficMirrorFilterRuleInput{TrafficMirrorFilterRuleId: aws.String(id)})
+		if err != nil && !strings.Contains(err.Error(), "NotFound") {
+			return fmt.Errorf("failed to delete traffic mirror filter rule %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// primaryNetworkInterface returns the ID and VPC of the primary network interface of the instance.
+func primaryNetworkInterface(ctx context.Context, ec2Client *ec2.Client, instanceID string) (string, string, error) {
+	result, err := ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{InstanceIds: []string{instanceID}})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to describe instance %s: %w", instanceID, err)
+	}
+	for _, reservation := range result.Reservations {
+		for i := range reservation.Instances {
+			if eni := instancePrimaryInterface(&reservation.Instances[i]); eni != nil {
+				return aws.ToString(eni.NetworkInterfaceId), aws.ToString(eni.VpcId), nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("instance %s has no primary network interface", instanceID)
+}
+
+// networkInterfaceVPC returns the VPC of a network interface.
+func networkInterfaceVPC(ctx context.Context, ec2Client *ec2.Client, eniID string) (string, error) {
+	result, err := ec2Client.DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{NetworkInterfaceIds: []string{eniID}})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe network interface %s: %w", eniID, err)
+	}
+	if len(result.NetworkInterfaces) == 0 {
+		return "", fmt.Errorf("network interface %s not found", eniID)
+	}
+	return aws.ToString(result.NetworkInterfaces[0].VpcId), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TrafficMirrorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.TrafficMirrorSession{}).
+		Named("trafficmirrorsession").
+		Complete(r)
+}
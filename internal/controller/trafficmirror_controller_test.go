@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("TrafficMirrorSession Controller", func() {
+	Context("When the source Ec2Instance has not been launched", func() {
+		const resourceName = "mirror-waiting-for-source"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			session := &computev1.TrafficMirrorSession{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: computev1.TrafficMirrorSessionSpec{
+					SourceEc2InstanceRef: corev1.LocalObjectReference{Name: "not-launched-yet"},
+					TargetENIRef:         "eni-0123456789abcdef0",
+					FilterRules:          []computev1.MirrorFilterRule{{Protocol: "tcp", Direction: "ingress", CIDR: "10.0.0.0/8"}},
+					SessionNumber:        1,
+				},
+			}
+			Expect(k8sClient.Create(ctx, session)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			session := &computev1.TrafficMirrorSession{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, session)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, session)).To(Succeed())
+		})
+
+		It("should wait without creating AWS resources", func() {
+			controllerReconciler := &TrafficMirrorReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+
+			session := &computev1.TrafficMirrorSession{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, session)).To(Succeed())
+			Expect(session.Finalizers).To(BeEmpty())
+			Expect(session.Status.TargetID).To(BeEmpty())
+		})
+	})
+})
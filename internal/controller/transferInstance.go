@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// transferToAnnotation asks the operator to move an Ec2Instance (and the EC2 instance behind it)
+// into another namespace without terminating the instance.
+const transferToAnnotation = "ec2instance.compute.cloud.com/transfer-to"
+
+// transferInstance moves ec2Instance into targetNamespace. It creates a copy of the object in the
+// target namespace that adopts the running EC2 instance, then deletes the source object with the
+// Orphan deletion policy so the finalizer does not terminate the instance.
+//
+// Every step is idempotent, so if the operator restarts half way the next reconcile picks up where it left off.
+func (r *Ec2InstanceReconciler) transferInstance(ctx context.Context, ec2Instance *computev1.Ec2Instance, targetNamespace string) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	if targetNamespace == ec2Instance.Namespace {
+		l.Info("Transfer target is the current namespace, ignoring", "namespace", targetNamespace)
+		return ctrl.Result{}, nil
+	}
+
+	l.Info("Transferring instance to another namespace",
+		"instanceID", ec2Instance.Status.InstanceID,
+		"from", ec2Instance.Namespace,
+		"to", targetNamespace)
+
+	// 1. Make sure the target object exists and adopts our instance.
+	target := &computev1.Ec2Instance{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: targetNamespace, Name: ec2Instance.Name}, target)
+	switch {
+	case errors.IsNotFound(err):
+		target = &computev1.Ec2Instance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ec2Instance.Name,
+				Namespace: targetNamespace,
+				Labels:    ec2Instance.Labels,
+			},
+			Spec: *ec2Instance.Spec.DeepCopy(),
+		}
+		target.Spec.AdoptInstanceID = ec2Instance.Status.InstanceID
+		target.Spec.DeletionPolicy = ""
+		if err := r.Create(ctx, target); err != nil {
+			l.Error(err, "Failed to create transfer target", "namespace", targetNamespace)
+			return ctrl.Result{}, err
+		}
+		l.Info("Created transfer target", "namespace", targetNamespace, "name", target.Name)
+	case err != nil:
+		return ctrl.Result{}, err
+	case target.Spec.AdoptInstanceID != ec2Instance.Status.InstanceID:
+		// Something else already lives under this name in the target namespace. Refuse to delete the
+		// source, otherwise the instance would end up without any owner.
+		err := fmt.Errorf("ec2instance %s/%s already exists and does not adopt instance %s",
+			targetNamespace, target.Name, ec2Instance.Status.InstanceID)
+		l.Error(err, "Cannot transfer instance")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	// 2. Hand the volumes of spec.ebsVolumes and the Elastic IP over to the target, which would
+	// otherwise never manage or delete the volumes and allocate a second address.
+	if handOverResources(ec2Instance, target) {
+		if err := r.Status().Update(ctx, target); err != nil {
+			l.Error(err, "Failed to hand resources over to transfer target")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// 3. Orphan the instance on the source side so the finalizer leaves it running, then delete the source.
+	if ec2Instance.Spec.DeletionPolicy != computev1.DeletionPolicyOrphan {
+		ec2Instance.Spec.DeletionPolicy = computev1.DeletionPolicyOrphan
+		if err := r.Update(ctx, ec2Instance); err != nil {
+			l.Error(err, "Failed to set Orphan deletion policy on transfer source")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.Delete(ctx, ec2Instance); err != nil && !errors.IsNotFound(err) {
+		l.Error(err, "Failed to delete transfer source")
+		return ctrl.Result{}, err
+	}
+
+	l.Info("Transfer complete", "instanceID", ec2Instance.Status.InstanceID, "namespace", targetNamespace)
+	return ctrl.Result{}, nil
+}
+
+// handOverResources records the resources the operator created for the instance of source in the
+// status of target, unless target already tracks them, and reports whether it changed.
+func handOverResources(source, target *computev1.Ec2Instance) bool {
+	changed := false
+	for device, volumeID := range source.Status.EBSVolumeIDs {
+		if _, ok := target.Status.EBSVolumeIDs[device]; ok {
+			continue
+		}
+		if target.Status.EBSVolumeIDs == nil {
+			target.Status.EBSVolumeIDs = map[string]string{}
+		}
+		target.Status.EBSVolumeIDs[device] = volumeID
+		changed = true
+	}
+	if target.Status.ElasticIPAllocationID == "" && source.Status.ElasticIPAllocationID != "" {
+		target.Status.ElasticIPAllocationID = source.Status.ElasticIPAllocationID
+		changed = true
+	}
+	return changed
+}
+
+// adoptInstance takes over the EC2 instance named in spec.adoptInstanceID instead of creating a new one.
+// The instance details are copied into status and the finalizer is added, after which the object is
+// reconciled like any instance the operator launched itself.
+func (r *Ec2InstanceReconciler) adoptInstance(ctx context.Context, ec2Instance *computev1.Ec2Instance) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	instanceID := ec2Instance.Spec.AdoptInstanceID
+	l.Info("Adopting existing EC2 instance", "instanceID", instanceID)
+
+	exists, awsInstance, err := checkEC2InstanceExists(ctx, r.instanceEC2Client(ec2Instance), instanceID)
+	if err != nil {
+		l.Error(err, "Failed to look up instance to adopt", "instanceID", instanceID)
+		return ctrl.Result{}, err
+	}
+	if !exists || awsInstance.State.Name == ec2types.InstanceStateNameTerminated || awsInstance.State.Name == ec2types.InstanceStateNameShuttingDown {
+		// Never fall back to creating a new instance here: the user asked for this specific one.
+		message := fmt.Sprintf("Instance %s to adopt does not exist in %s", instanceID, ec2Instance.Spec.Region)
+		if exists {
+			message = fmt.Sprintf("Instance %s to adopt is %s", instanceID, awsInstance.State.Name)
+		}
+		l.Info("Cannot adopt instance", "instanceID", instanceID, "reason", message)
+		if apimeta.SetStatusCondition(&ec2Instance.Status.Conditions, metav1.Condition{
+			Type:               computev1.ConditionFailed,
+			Status:             metav1.ConditionTrue,
+			Reason:             "AdoptionFailed",
+			Message:            message,
+			ObservedGeneration: ec2Instance.Generation,
+		}) {
+			r.Recorder.Event(ec2Instance, corev1.EventTypeWarning, "AdoptionFailed", message)
+			if err := r.Status().Update(ctx, ec2Instance); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		// Pointing spec.adoptInstanceID at another instance starts over.
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(ec2Instance, ec2InstanceFinalizer) {
+		controllerutil.AddFinalizer(ec2Instance, ec2InstanceFinalizer)
+		if err := r.Update(ctx, ec2Instance); err != nil {
+			l.Error(err, "Failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	ec2Instance.Status.InstanceID = instanceID
+	ec2Instance.Status.Adopted = true
+	ec2Instance.Status.State = string(awsInstance.State.Name)
+	ec2Instance.Status.PublicIP = derefString(awsInstance.PublicIpAddress)
+	ec2Instance.Status.PrivateIP = derefString(awsInstance.PrivateIpAddress)
+	ec2Instance.Status.PublicDNS = derefString(awsInstance.PublicDnsName)
+	ec2Instance.Status.PrivateDNS = derefString(awsInstance.PrivateDnsName)
+	ec2Instance.Status.VpcID = derefString(awsInstance.VpcId)
+	ec2Instance.Status.InstanceLifecycle = instanceLifecycle(awsInstance)
+	if awsInstance.Placement != nil {
+		ec2Instance.Status.AvailabilityZone = derefString(awsInstance.Placement.AvailabilityZone)
+	}
+	if awsInstance.LaunchTime != nil {
+		launchTime := metav1.NewTime(awsInstance.LaunchTime.Truncate(time.Second))
+		ec2Instance.Status.LaunchTime = &launchTime
+	}
+	apimeta.RemoveStatusCondition(&ec2Instance.Status.Conditions, computev1.ConditionFailed)
+	setSyncedConditions(ec2Instance, awsInstance)
+
+	if err := r.Status().Update(ctx, ec2Instance); err != nil {
+		l.Error(err, "Failed to update status after adoption")
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Eventf(ec2Instance, corev1.EventTypeNormal, "Adopted", "Adopted instance %s", instanceID)
+
+	l.Info("EC2 instance adopted", "instanceID", instanceID, "state", ec2Instance.Status.State)
+	return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+}
@@ -0,0 +1,344 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+const (
+	// transitGatewayRouteTableFinalizer makes sure the route table is deleted in AWS before the object is removed.
+	transitGatewayRouteTableFinalizer = "transitgatewayroutetable.compute.cloud.com"
+	// transitGatewayRouteTableUIDTag is put on the route table so a create whose status update was
+	// lost finds its table again instead of creating a second one.
+	transitGatewayRouteTableUIDTag = "transitgatewayroutetable.compute.cloud.com/uid"
+	// transitGatewayRouteTableResync is how often routes are compared with AWS to undo manual changes.
+	transitGatewayRouteTableResync = 5 * time.Minute
+)
+
+// TransitGatewayRouteTableReconciler keeps a transit gateway route table, its static routes and its
+// propagations in line with the TransitGatewayRouteTable object.
+type TransitGatewayRouteTableReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=transitgatewayroutetables,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=transitgatewayroutetables/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=transitgatewayroutetables/finalizers,verbs=update
+
+// Reconcile creates the route table, converges its routes and propagations, and deletes it.
+func (r *TransitGatewayRouteTableReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	table := &computev1.TransitGatewayRouteTable{}
+	if err := r.Get(ctx, req.NamespacedName, table); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	ec2Client := awsClient(table.Spec.Region)
+
+	if !table.DeletionTimestamp.IsZero() {
+		if table.Status.RouteTableID != "" {
+			if err := deleteTransitGatewayRouteTable(ctx, ec2Client, table.Status.RouteTableID); err != nil {
+				l.Error(err, "Failed to delete transit gateway route table", "routeTableID", table.Status.RouteTableID)
+				return ctrl.Result{}, err
+			}
+			l.Info("Deleted transit gateway route table", "routeTableID", table.Status.RouteTableID)
+		}
+
+		controllerutil.RemoveFinalizer(table, transitGatewayRouteTableFinalizer)
+		if err := r.Update(ctx, table); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if controllerutil.AddFinalizer(table, transitGatewayRouteTableFinalizer) {
+		if err := r.Update(ctx, table); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	current, err := findTransitGatewayRouteTable(ctx, ec2Client, table)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if current == nil {
+		result, err := ec2Client.CreateTransitGatewayRouteTable(ctx, &ec2.CreateTransitGatewayRouteTableInput{
+			TransitGatewayId: aws.String(table.Spec.TransitGatewayRef),
+			TagSpecifications: []ec2types.TagSpecification{{
+				ResourceType: ec2types.ResourceTypeTransitGatewayRouteTable,
+				Tags: []ec2types.Tag{
+					{Key: aws.String("Name"), Value: aws.String(table.Name)},
+					{Key: aws.String(transitGatewayRouteTableUIDTag), Value: aws.String(string(table.UID))},
+				},
+			}},
+		})
+		if err != nil {
+			l.Error(err, "Failed to create transit gateway route table")
+			return ctrl.Result{}, fmt.Errorf("failed to create transit gateway route table: %w", err)
+		}
+		current = result.TransitGatewayRouteTable
+		l.Info("Created transit gateway route table", "routeTableID", aws.ToString(current.TransitGatewayRouteTableId))
+	}
+
+	if table.Status.RouteTableID != aws.ToString(current.TransitGatewayRouteTableId) || table.Status.State != string(current.State) {
+		table.Status.RouteTableID = aws.ToString(current.TransitGatewayRouteTableId)
+		table.Status.State = string(current.State)
+		if err := r.Status().Update(ctx, table); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	// Routes and propagations can only be changed once the table is available.
+	if current.State != ec2types.TransitGatewayRouteTableStateAvailable {
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	syncErr := r.syncRoutes(ctx, ec2Client, table)
+	if syncErr == nil {
+		syncErr = r.syncPropagations(ctx, ec2Client, table)
+	}
+	message := ""
+	if syncErr != nil {
+		message = syncErr.Error()
+	}
+	if table.Status.Message != message {
+		table.Status.Message = message
+		if err := r.Status().Update(ctx, table); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if syncErr != nil {
+		return ctrl.Result{}, syncErr
+	}
+	return ctrl.Result{RequeueAfter: transitGatewayRouteTableResync}, nil
+}
+
+// findTransitGatewayRouteTable returns the route table of the object, looked up by the ID in status
+// or, before that is recorded, by the UID tag. It returns nil when there is none.
+func findTransitGatewayRouteTable(ctx context.Context, ec2Client *ec2.Client, table *computev1.TransitGatewayRouteTable) (*ec2types.TransitGatewayRouteTable, error) {
+	input := &ec2.DescribeTransitGatewayRouteTablesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("tag:" + transitGatewayRouteTableUIDTag), Values: []string{string(table.UID)}}},
+	}
+	if table.Status.RouteTableID != "" {
+		input = &ec2.DescribeTransitGatewayRouteTablesInput{TransitGatewayRouteTableIds: []string{table.Status.RouteTableID}}
+	}
+	result, err := ec2Client.DescribeTransitGatewayRouteTables(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe transit gateway route table: %w", err)
+	}
+	for i := range result.TransitGatewayRouteTables {
+		current := &result.TransitGatewayRouteTables[i]
+		switch current.State {
+		case ec2types.TransitGatewayRouteTableStateDeleting, ec2types.TransitGatewayRouteTableStateDeleted:
+			continue
+		}
+		return current, nil
+	}
+	return nil, nil
+}
+
+// transitGatewayRouteChanges compares the static routes in AWS with the spec. It returns the routes
+// to create, the routes whose target changed, and the destinations of routes to delete.
+func transitGatewayRouteChanges(desired []computev1.TGWRoute, current []ec2types.TransitGatewayRoute) (create, replace []computev1.TGWRoute, remove []string) {
+	existing := make(map[string]computev1.TGWRoute, len(current))
+	for _, route := range current {
+		if route.Type != ec2types.TransitGatewayRouteTypeStatic {
+			continue
+		}
+		observed := computev1.TGWRoute{DestinationCIDR: aws.ToString(route.DestinationCidrBlock)}
+		if len(route.TransitGatewayAttachments) > 0 {
+			observed.AttachmentRef = aws.ToString(route.TransitGatewayAttachments[0].TransitGatewayAttachmentId)
+		} else {
+			observed.Blackhole = route.State == ec2types.TransitGatewayRouteStateBlackhole
+		}
+		existing[observed.DestinationCIDR] = observed
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, route := range desired {
+		wanted[route.DestinationCIDR] = true
+		observed, ok := existing[route.DestinationCIDR]
+		switch {
+		case !ok:
+			create = append(create, route)
+		case observed != route:
+			replace = append(replace, route)
+		}
+	}
+	for _, route := range current {
+		cidr := aws.ToString(route.DestinationCidrBlock)
+		if route.Type == ec2types.TransitGatewayRouteTypeStatic && !wanted[cidr] {
+			remove = append(remove, cidr)
+		}
+	}
+	return create, replace, remove
+}
+
+// syncRoutes creates, replaces and deletes static routes so the table matches the spec.
+func (r *TransitGatewayRouteTableReconciler) syncRoutes(ctx context.Context, ec2Client *ec2.Client, table *computev1.TransitGatewayRouteTable) error {
+	l := log.FromContext(ctx)
+	routeTableID := aws.String(table.Status.RouteTableID)
+
+	result, err := ec2Client.SearchTransitGatewayRoutes(ctx, &ec2.SearchTransitGatewayRoutesInput{
+		TransitGatewayRouteTableId: routeTableID,
+		Filters:                    []ec2types.Filter{{Name: aws.String("type"), Values: []string{string(ec2types.TransitGatewayRouteTypeStatic)}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list routes of %s: %w", table.Status.RouteTableID, err)
+	}
+
+	create, replace, remove := transitGatewayRouteChanges(table.Spec.Routes, result.Routes)
+	for _, route := range create {
+		input := &ec2.CreateTransitGatewayRouteInput{
+			TransitGatewayRouteTableId: routeTableID,
+			DestinationCidrBlock:       aws.String(route.DestinationCIDR),
+		}
+		if route.Blackhole {
+			input.Blackhole = aws.Bool(true)
+		} else {
+			input.TransitGatewayAttachmentId = aws.String(route.AttachmentRef)
+		}
+		if _, err := ec2Client.CreateTransitGatewayRoute(ctx, input); err != nil {
+			return fmt.Errorf("failed to create route to %s: %w", route.DestinationCIDR, err)
+		}
+		l.Info("Created transit gateway route", "destination", route.DestinationCIDR, "attachment", route.AttachmentRef, "blackhole", route.Blackhole)
+	}
+	for _, route := range replace {
+		input := &ec2.ReplaceTransitGatewayRouteInput{
+			TransitGatewayRouteTableId: routeTableID,
+			DestinationCidrBlock:       aws.String(route.DestinationCIDR),
+		}
+		if route.Blackhole {
+			input.Blackhole = aws.Bool(true)
+		} else {
+			input.TransitGatewayAttachmentId = aws.String(route.AttachmentRef)
+		}
+		if _, err := ec2Client.ReplaceTransitGatewayRoute(ctx, input); err != nil {
+			return fmt.Errorf("failed to replace route to %s: %w", route.DestinationCIDR, err)
+		}
+		l.Info("Replaced transit gateway route", "destination", route.DestinationCIDR, "attachment", route.AttachmentRef, "blackhole", route.Blackhole)
+	}
+	for _, cidr := range remove {
+		_, err := ec2Client.DeleteTransitGatewayRoute(ctx, &ec2.DeleteTransitGatewayRouteInput{
+			TransitGatewayRouteTableId: routeTableID,
+			DestinationCidrBlock:       aws.String(cidr),
+		})
+		if err != nil && !strings.Contains(err.Error(), "NotFound") {
+			return fmt.Errorf("failed to delete route to %s: %w", cidr, err)
+		}
+		l.Info("Deleted transit gateway route that is not in the spec", "destination", cidr)
+	}
+	return nil
+}
+
+// enabledPropagations returns the attachments that propagate routes into the table.
+func enabledPropagations(ctx context.Context, ec2Client *ec2.Client, routeTableID string) ([]string, error) {
+	var attachments []string
+	paginator := ec2.NewGetTransitGatewayRouteTablePropagationsPaginator(ec2Client, &ec2.GetTransitGatewayRouteTablePropagationsInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list propagations of %s: %w", routeTableID, err)
+		}
+		for _, propagation := range page.TransitGatewayRouteTablePropagations {
+			if propagation.State == ec2types.TransitGatewayPropagationStateEnabled || propagation.State == ec2types.TransitGatewayPropagationStateEnabling {
+				attachments = append(attachments, aws.ToString(propagation.TransitGatewayAttachmentId))
+			}
+		}
+	}
+	return attachments, nil
+}
+
+// syncPropagations enables and disables route propagation so the table matches the spec.
+func (r *TransitGatewayRouteTableReconciler) syncPropagations(ctx context.Context, ec2Client *ec2.Client, table *computev1.TransitGatewayRouteTable) error {
+	l := log.FromContext(ctx)
+
+	current, err := enabledPropagations(ctx, ec2Client, table.Status.RouteTableID)
+	if err != nil {
+		return err
+	}
+	desired := make([]string, 0, len(table.Spec.Propagations))
+	for _, propagation := range table.Spec.Propagations {
+		desired = append(desired, propagation.AttachmentRef)
+	}
+
+	enable, disable := stringSetDiff(desired, current)
+	for _, attachment := range enable {
+		_, err := ec2Client.EnableTransitGatewayRouteTablePropagation(ctx, &ec2.EnableTransitGatewayRouteTablePropagationInput{
+			TransitGatewayRouteTableId: aws.String(table.Status.RouteTableID),
+			TransitGatewayAttachmentId: aws.String(attachment),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enable propagation from %s: %w", attachment, err)
+		}
+		l.Info("Enabled route propagation", "attachment", attachment)
+	}
+	for _, attachment := range disable {
+		_, err := ec2Client.DisableTransitGatewayRouteTablePropagation(ctx, &ec2.DisableTransitGatewayRouteTablePropagationInput{
+			TransitGatewayRouteTableId: aws.String(table.Status.RouteTableID),
+			TransitGatewayAttachmentId: aws.String(attachment),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to disable propagation from %s: %w", attachment, err)
+		}
+		l.Info("Disabled route propagation", "attachment", attachment)
+	}
+	return nil
+}
+
+// deleteTransitGatewayRouteTable disables all propagations, which AWS requires, then deletes the
+// table. Static routes go with it. Tables that are already gone are left alone.
+func deleteTransitGatewayRouteTable(ctx context.Context, ec2Client *ec2.Client, routeTableID string) error {
+	propagations, err := enabledPropagations(ctx, ec2Client, routeTableID)
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			return nil
+		}
+		return err
+	}
+	for _, attachment := range propagations {
+		_, err := ec2Client.DisableTransitGatewayRouteTablePropagation(ctx, &ec2.DisableTransitGatewayRouteTablePropagationInput{
+			TransitGatewayRouteTableId: aws.String(routeTableID),
+			TransitGatewayAttachmentId: aws.String(attachment),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to disable propagation from %s: %w", attachment, err)
+		}
+	}
+
+	_, err = ec2Client.DeleteTransitGatewayRouteTable(ctx, &ec2.DeleteTransitGatewayRouteTableInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+	})
+	if err != nil && !strings.Contains(err.Error(), "NotFound") {
+		return fmt.Errorf("failed to delete transit gateway route table: %w", err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TransitGatewayRouteTableReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.TransitGatewayRouteTable{}).
+		Named("transitgatewayroutetable").
+		Complete(r)
+}
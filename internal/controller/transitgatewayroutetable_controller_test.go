@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("TransitGatewayRouteTable Controller", func() {
+	Context("When comparing the routes in the spec with AWS", func() {
+		staticRoute := func(cidr, attachment string) ec2types.TransitGatewayRoute {
+			route := ec2types.TransitGatewayRoute{
+				DestinationCidrBlock: aws.String(cidr),
+				Type:                 ec2types.TransitGatewayRouteTypeStatic,
+				State:                ec2types.TransitGatewayRouteStateActive,
+			}
+			if attachment == "" {
+				route.State = ec2types.TransitGatewayRouteStateBlackhole
+			} else {
+				route.TransitGatewayAttachments = []ec2types.TransitGatewayRouteAttachment{{TransitGatewayAttachmentId: aws.String(attachment)}}
+			}
+			return route
+		}
+
+		It("should leave matching routes alone", func() {
+			desired := []computev1.TGWRoute{
+				{DestinationCIDR: "10.1.0.0/16", AttachmentRef: "tgw-attach-1"},
+				{DestinationCIDR: "10.99.0.0/16", Blackhole: true},
+			}
+			current := []ec2types.TransitGatewayRoute{staticRoute("10.1.0.0/16", "tgw-attach-1"), staticRoute("10.99.0.0/16", "")}
+			create, replace, remove := transitGatewayRouteChanges(desired, current)
+			Expect(create).To(BeEmpty())
+			Expect(replace).To(BeEmpty())
+			Expect(remove).To(BeEmpty())
+		})
+
+		It("should create missing routes and replace routes whose target changed", func() {
+			desired := []computev1.TGWRoute{
+				{DestinationCIDR: "10.1.0.0/16", AttachmentRef: "tgw-attach-2"},
+				{DestinationCIDR: "10.2.0.0/16", AttachmentRef: "tgw-attach-1"},
+				{DestinationCIDR: "10.3.0.0/16", Blackhole: true},
+			}
+			current := []ec2types.TransitGatewayRoute{staticRoute("10.1.0.0/16", "tgw-attach-1"), staticRoute("10.3.0.0/16", "tgw-attach-1")}
+			create, replace, remove := transitGatewayRouteChanges(desired, current)
+			Expect(create).To(Equal([]computev1.TGWRoute{desired[1]}))
+			Expect(replace).To(Equal([]computev1.TGWRoute{desired[0], desired[2]}))
+			Expect(remove).To(BeEmpty())
+		})
+
+		It("should delete static routes that are not in the spec", func() {
+			current := []ec2types.TransitGatewayRoute{staticRoute("10.1.0.0/16", "tgw-attach-1"), staticRoute("10.99.0.0/16", "")}
+			_, _, remove := transitGatewayRouteChanges(nil, current)
+			Expect(remove).To(ConsistOf("10.1.0.0/16", "10.99.0.0/16"))
+		})
+
+		It("should ignore propagated routes", func() {
+			propagated := staticRoute("10.5.0.0/16", "tgw-attach-3")
+			propagated.Type = ec2types.TransitGatewayRouteTypePropagated
+			create, replace, remove := transitGatewayRouteChanges(nil, []ec2types.TransitGatewayRoute{propagated})
+			Expect(create).To(BeEmpty())
+			Expect(replace).To(BeEmpty())
+			Expect(remove).To(BeEmpty())
+		})
+	})
+})
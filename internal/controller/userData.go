@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// encodeUserData returns inline user data base64 encoded, as RunInstances wants it. Data that
+// already decodes as base64 is taken as encoded; scripts never do, as they contain spaces or
+// newlines.
+func encodeUserData(data string) string {
+	if _, err := base64.StdEncoding.DecodeString(data); err == nil {
+		return data
+	}
+	return base64.StdEncoding.EncodeToString([]byte(data))
+}
+
+// specUserData returns the base64 encoded user data of spec.userData, reading it from its Secret
+// when it has a secretRef, or "" when there is none.
+func (r *Ec2InstanceReconciler) specUserData(ctx context.Context, ec2Instance *computev1.Ec2Instance) (string, error) {
+	userData := ec2Instance.Spec.UserData
+	if userData == nil {
+		return "", nil
+	}
+	if userData.SecretRef == nil {
+		if strings.TrimSpace(userData.Inline) == "" {
+			return "", nil
+		}
+		return encodeUserData(userData.Inline), nil
+	}
+
+	ref := userData.SecretRef
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: ec2Instance.Namespace, Name: ref.Name}, secret); err != nil {
+		return "", fmt.Errorf("failed to get user data Secret %s: %w", ref.Name, err)
+	}
+	data, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("user data Secret %s has no key %s", ref.Name, ref.Key)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/base64"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("User data", func() {
+	It("should encode plain text scripts", func() {
+		script := "#!/bin/bash\nyum install -y httpd\n"
+		Expect(encodeUserData(script)).To(Equal(base64.StdEncoding.EncodeToString([]byte(script))))
+	})
+
+	It("should keep user data that is already base64 encoded", func() {
+		encoded := base64.StdEncoding.EncodeToString([]byte("#!/bin/bash\necho hi\n"))
+		Expect(encodeUserData(encoded)).To(Equal(encoded))
+	})
+})
@@ -0,0 +1,247 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// vpcEndpointFinalizer makes sure the endpoint is deleted in AWS before the object is removed.
+const vpcEndpointFinalizer = "vpcendpoint.compute.cloud.com"
+
+// VpcEndpointReconciler keeps an AWS VPC endpoint in line with its VpcEndpoint object.
+type VpcEndpointReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=vpcendpoints,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=vpcendpoints/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.cloud.com,resources=vpcendpoints/finalizers,verbs=update
+
+// Reconcile creates, modifies and deletes the VPC endpoint.
+func (r *VpcEndpointReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	endpoint := &computev1.VpcEndpoint{}
+	if err := r.Get(ctx, req.NamespacedName, endpoint); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	ec2Client := awsClient(endpoint.Spec.Region)
+
+	if !endpoint.DeletionTimestamp.IsZero() {
+		if endpoint.Status.EndpointID != "" {
+			if err := deleteVpcEndpoint(ctx, ec2Client, endpoint.Status.EndpointID); err != nil {
+				l.Error(err, "Failed to delete VPC endpoint", "endpointID", endpoint.Status.EndpointID)
+				return ctrl.Result{}, err
+			}
+			l.Info("Deleted VPC endpoint", "endpointID", endpoint.Status.EndpointID)
+		}
+
+		controllerutil.RemoveFinalizer(endpoint, vpcEndpointFinalizer)
+		if err := r.Update(ctx, endpoint); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if controllerutil.AddFinalizer(endpoint, vpcEndpointFinalizer) {
+		if err := r.Update(ctx, endpoint); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if endpoint.Status.EndpointID == "" {
+		input := &ec2.CreateVpcEndpointInput{
+			ServiceName:     aws.String(endpoint.Spec.ServiceName),
+			VpcId:           aws.String(endpoint.Spec.VpcRef),
+			VpcEndpointType: ec2types.VpcEndpointType(endpoint.Spec.Type),
+			TagSpecifications: []ec2types.TagSpecification{{
+				ResourceType: ec2types.ResourceTypeVpcEndpoint,
+				Tags:         []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String(endpoint.Name)}},
+			}},
+			// The UID makes the call idempotent if the status update below is lost.
+			ClientToken: aws.String(string(endpoint.UID)),
+		}
+		if endpoint.Spec.Type == computev1.VpcEndpointTypeInterface {
+			input.SubnetIds = referenceNames(endpoint.Spec.SubnetRefs)
+			input.SecurityGroupIds = referenceNames(endpoint.Spec.SecurityGroupRefs)
+			input.PrivateDnsEnabled = aws.Bool(endpoint.Spec.PrivateDNSEnabled)
+		}
+
+		result, err := ec2Client.CreateVpcEndpoint(ctx, input)
+		if err != nil {
+			l.Error(err, "Failed to create VPC endpoint")
+			return ctrl.Result{}, fmt.Errorf("failed to create VPC endpoint: %w", err)
+		}
+		l.Info("Created VPC endpoint", "endpointID", aws.ToString(result.VpcEndpoint.VpcEndpointId))
+
+		endpoint.Status.EndpointID = aws.ToString(result.VpcEndpoint.VpcEndpointId)
+		endpoint.Status.ObservedGeneration = endpoint.Generation
+		setVpcEndpointStatus(&endpoint.Status, result.VpcEndpoint)
+		if err := r.Status().Update(ctx, endpoint); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	describe, err := ec2Client.DescribeVpcEndpoints(ctx, &ec2.DescribeVpcEndpointsInput{
+		VpcEndpointIds: []string{endpoint.Status.EndpointID},
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to describe VPC endpoint: %w", err)
+	}
+	if len(describe.VpcEndpoints) == 0 {
+		return ctrl.Result{}, fmt.Errorf("VPC endpoint %s not found", endpoint.Status.EndpointID)
+	}
+	current := &describe.VpcEndpoints[0]
+
+	originalStatus := endpoint.Status.DeepCopy()
+	setVpcEndpointStatus(&endpoint.Status, current)
+
+	if endpoint.Status.ObservedGeneration != endpoint.Generation {
+		if input := vpcEndpointModification(endpoint, current); input != nil {
+			l.Info("Modifying VPC endpoint", "endpointID", endpoint.Status.EndpointID)
+			if _, err := ec2Client.ModifyVpcEndpoint(ctx, input); err != nil {
+				l.Error(err, "Failed to modify VPC endpoint")
+				return ctrl.Result{}, fmt.Errorf("failed to modify VPC endpoint: %w", err)
+			}
+		}
+		endpoint.Status.ObservedGeneration = endpoint.Generation
+	}
+
+	if !equality.Semantic.DeepEqual(*originalStatus, endpoint.Status) {
+		if err := r.Status().Update(ctx, endpoint); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// DNS entries are only complete once the endpoint is available, so keep polling until then.
+	switch endpoint.Status.State {
+	case "pending", "pendingacceptance":
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// vpcEndpointModification returns the changes that bring the endpoint in line with the spec, or nil
+// when there are none. Only Interface endpoints have subnets, security groups and private DNS.
+func vpcEndpointModification(endpoint *computev1.VpcEndpoint, current *ec2types.VpcEndpoint) *ec2.ModifyVpcEndpointInput {
+	if endpoint.Spec.Type != computev1.VpcEndpointTypeInterface {
+		return nil
+	}
+
+	currentGroups := make([]string, 0, len(current.Groups))
+	for _, group := range current.Groups {
+		currentGroups = append(currentGroups, aws.ToString(group.GroupId))
+	}
+	addSubnets, removeSubnets := stringSetDiff(referenceNames(endpoint.Spec.SubnetRefs), current.SubnetIds)
+	addGroups, removeGroups := stringSetDiff(referenceNames(endpoint.Spec.SecurityGroupRefs), currentGroups)
+	dnsChanged := aws.ToBool(current.PrivateDnsEnabled) != endpoint.Spec.PrivateDNSEnabled
+	if len(addSubnets)+len(removeSubnets)+len(addGroups)+len(removeGroups) == 0 && !dnsChanged {
+		return nil
+	}
+
+	input := &ec2.ModifyVpcEndpointInput{
+		VpcEndpointId:          current.VpcEndpointId,
+		AddSubnetIds:           addSubnets,
+		RemoveSubnetIds:        removeSubnets,
+		AddSecurityGroupIds:    addGroups,
+		RemoveSecurityGroupIds: removeGroups,
+	}
+	if dnsChanged {
+		input.PrivateDnsEnabled = aws.Bool(endpoint.Spec.PrivateDNSEnabled)
+	}
+	return input
+}
+
+// setVpcEndpointStatus copies the observed endpoint state into status.
+func setVpcEndpointStatus(status *computev1.VpcEndpointStatus, current *ec2types.VpcEndpoint) {
+	// The API documents capitalized states but returns them in lower case; normalize either way.
+	status.State = strings.ToLower(string(current.State))
+	status.DNSEntries = nil
+	for _, entry := range current.DnsEntries {
+		status.DNSEntries = append(status.DNSEntries, computev1.VpcEndpointDNSEntry{
+			DNSName:      aws.ToString(entry.DnsName),
+			HostedZoneID: aws.ToString(entry.HostedZoneId),
+		})
+	}
+}
+
+// deleteVpcEndpoint deletes the endpoint. Endpoints that are already gone are left alone.
+func deleteVpcEndpoint(ctx context.Context, ec2Client *ec2.Client, endpointID string) error {
+	result, err := ec2Client.DeleteVpcEndpoints(ctx, &ec2.DeleteVpcEndpointsInput{
+		VpcEndpointIds: []string{endpointID},
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			return nil
+		}
+		return fmt.Errorf("failed to delete VPC endpoint: %w", err)
+	}
+	// DeleteVpcEndpoints reports per-endpoint failures in the response instead of as an error.
+	for _, item := range result.Unsuccessful {
+		if item.Error == nil || strings.Contains(aws.ToString(item.Error.Code), "NotFound") {
+			continue
+		}
+		return fmt.Errorf("failed to delete VPC endpoint %s: %s", aws.ToString(item.ResourceId), aws.ToString(item.Error.Message))
+	}
+	return nil
+}
+
+// referenceNames returns the names of the references, which hold AWS resource IDs.
+func referenceNames(refs []corev1.LocalObjectReference) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+// stringSetDiff returns the elements of desired missing from current, and those of current missing from desired.
+func stringSetDiff(desired, current []string) (add, remove []string) {
+	have := make(map[string]bool, len(current))
+	for _, s := range current {
+		have[s] = true
+	}
+	want := make(map[string]bool, len(desired))
+	for _, s := range desired {
+		want[s] = true
+		if !have[s] {
+			add = append(add, s)
+		}
+	}
+	for _, s := range current {
+		if !want[s] {
+			remove = append(remove, s)
+		}
+	}
+	return add, remove
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *VpcEndpointReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&computev1.VpcEndpoint{}).
+		Named("vpcendpoint").
+		Complete(r)
+}
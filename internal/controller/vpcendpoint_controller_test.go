@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+var _ = Describe("VpcEndpoint Controller", func() {
+	Context("When comparing the spec with the endpoint in AWS", func() {
+		endpoint := &computev1.VpcEndpoint{
+			Spec: computev1.VpcEndpointSpec{
+				Type:              computev1.VpcEndpointTypeInterface,
+				SubnetRefs:        []corev1.LocalObjectReference{{Name: "subnet-a"}, {Name: "subnet-b"}},
+				SecurityGroupRefs: []corev1.LocalObjectReference{{Name: "sg-1"}},
+				PrivateDNSEnabled: true,
+			},
+		}
+
+		It("should not modify an endpoint that matches", func() {
+			current := &ec2types.VpcEndpoint{
+				SubnetIds:         []string{"subnet-b", "subnet-a"},
+				Groups:            []ec2types.SecurityGroupIdentifier{{GroupId: aws.String("sg-1")}},
+				PrivateDnsEnabled: aws.Bool(true),
+			}
+			Expect(vpcEndpointModification(endpoint, current)).To(BeNil())
+		})
+
+		It("should add and remove subnets and security groups", func() {
+			current := &ec2types.VpcEndpoint{
+				SubnetIds:         []string{"subnet-a", "subnet-c"},
+				Groups:            []ec2types.SecurityGroupIdentifier{{GroupId: aws.String("sg-2")}},
+				PrivateDnsEnabled: aws.Bool(true),
+			}
+			input := vpcEndpointModification(endpoint, current)
+			Expect(input).NotTo(BeNil())
+			Expect(input.AddSubnetIds).To(Equal([]string{"subnet-b"}))
+			Expect(input.RemoveSubnetIds).To(Equal([]string{"subnet-c"}))
+			Expect(input.AddSecurityGroupIds).To(Equal([]string{"sg-1"}))
+			Expect(input.RemoveSecurityGroupIds).To(Equal([]string{"sg-2"}))
+			Expect(input.PrivateDnsEnabled).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// xraySamplingRateParameter is the SSM parameter the X-Ray daemon on the instance reads its sampling rate from.
+func xraySamplingRateParameter(instanceID string) string {
+	return fmt.Sprintf("/xray/%s/sampling-rate", instanceID)
+}
+
+// reconcileXRay publishes or removes the X-Ray sampling configuration of the instance and records in
+// status.xrayEnabled whether it is in place.
+func reconcileXRay(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	if !ec2Instance.Spec.XRayEnabled {
+		if ec2Instance.Status.XRayEnabled {
+			if err := deleteXRayConfig(ctx, ec2Instance); err != nil {
+				return err
+			}
+		}
+		ec2Instance.Status.XRayEnabled = false
+		return nil
+	}
+
+	name := xraySamplingRateParameter(ec2Instance.Status.InstanceID)
+	desired := strconv.FormatFloat(ec2Instance.Spec.XRaySamplingRate, 'f', -1, 64)
+	client := instanceSSMClient(ec2Instance)
+
+	current, err := getSSMParameter(ctx, client, name)
+	if err != nil {
+		return err
+	}
+	if current == desired {
+		ec2Instance.Status.XRayEnabled = true
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Publishing X-Ray sampling rate", "parameter", name, "samplingRate", desired)
+	_, err = client.PutParameter(ctx, &ssm.PutParameterInput{
+		Name:        aws.String(name),
+		Value:       aws.String(desired),
+		Type:        ssmtypes.ParameterTypeString,
+		Overwrite:   aws.Bool(true),
+		Description: aws.String(fmt.Sprintf("X-Ray sampling rate for %s/%s, managed by ec2-operator", ec2Instance.Namespace, ec2Instance.Name)),
+	})
+	if err != nil {
+		ec2Instance.Status.XRayEnabled = false
+		return fmt.Errorf("failed to put X-Ray sampling rate parameter: %w", err)
+	}
+
+	// Read it back so the status only claims what is actually stored.
+	current, err = getSSMParameter(ctx, client, name)
+	if err != nil {
+		return err
+	}
+	ec2Instance.Status.XRayEnabled = current == desired
+	return nil
+}
+
+// deleteXRayConfig removes the sampling rate parameter of the instance. A missing parameter is not an error.
+func deleteXRayConfig(ctx context.Context, ec2Instance *computev1.Ec2Instance) error {
+	// Nothing was published for an instance that never had X-Ray enabled.
+	if ec2Instance.Status.InstanceID == "" || (!ec2Instance.Spec.XRayEnabled && !ec2Instance.Status.XRayEnabled) {
+		return nil
+	}
+
+	_, err := instanceSSMClient(ec2Instance).DeleteParameter(ctx, &ssm.DeleteParameterInput{
+		Name: aws.String(xraySamplingRateParameter(ec2Instance.Status.InstanceID)),
+	})
+	var notFound *ssmtypes.ParameterNotFound
+	if err != nil && !errors.As(err, &notFound) {
+		return fmt.Errorf("failed to delete X-Ray sampling rate parameter: %w", err)
+	}
+	return nil
+}
+
+// getSSMParameter returns the value of the parameter, or "" if it does not exist.
+func getSSMParameter(ctx context.Context, client *ssm.Client, name string) (string, error) {
+	result, err := client.GetParameter(ctx, &ssm.GetParameterInput{Name: aws.String(name)})
+	var notFound *ssmtypes.ParameterNotFound
+	if errors.As(err, &notFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get parameter %s: %w", name, err)
+	}
+	return aws.ToString(result.Parameter.Value), nil
+}
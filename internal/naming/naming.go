@@ -0,0 +1,62 @@
+// Package naming renders the spec.namePattern of an Ec2Instance into the Name tag of its EC2
+// instance. The webhook and the reconciler share it, so a pattern that passes admission renders
+// the same name at launch.
+package naming
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+)
+
+// MaxNameLength is the longest value AWS accepts for a tag.
+const MaxNameLength = 256
+
+// Vars are the values a name pattern can refer to.
+type Vars struct {
+	Name         string
+	Namespace    string
+	InstanceType string
+	AZ           string
+	// Index is the ordinal of the instance within its Ec2InstanceSet, or 0 outside a set.
+	Index int
+}
+
+// VarsFor returns the pattern values of ec2Instance.
+func VarsFor(ec2Instance *computev1.Ec2Instance) Vars {
+	index, _ := strconv.Atoi(ec2Instance.Labels[computev1.SetIndexLabel])
+	return Vars{
+		Name:         ec2Instance.Name,
+		Namespace:    ec2Instance.Namespace,
+		InstanceType: ec2Instance.Spec.InstanceType,
+		AZ:           ec2Instance.Spec.AvailabilityZone,
+		Index:        index,
+	}
+}
+
+// Render executes pattern with vars and checks that the result can be used as a tag value.
+func Render(pattern string, vars Vars) (string, error) {
+	tmpl, err := template.New("namePattern").Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid name pattern: %w", err)
+	}
+	var name strings.Builder
+	if err := tmpl.Execute(&name, vars); err != nil {
+		return "", fmt.Errorf("failed to render name pattern: %w", err)
+	}
+	if name.Len() > MaxNameLength {
+		return "", fmt.Errorf("rendered name is %d characters, more than the %d allowed in a tag", name.Len(), MaxNameLength)
+	}
+	return name.String(), nil
+}
+
+// InstanceName renders the spec.namePattern of ec2Instance. It returns "" when no pattern is set.
+func InstanceName(ec2Instance *computev1.Ec2Instance) (string, error) {
+	if ec2Instance.Spec.NamePattern == "" {
+		return "", nil
+	}
+	return Render(ec2Instance.Spec.NamePattern, VarsFor(ec2Instance))
+}
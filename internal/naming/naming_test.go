@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	"github.com/bshaw7/operator-repo/internal/naming"
+)
+
+var _ = Describe("InstanceName", func() {
+	var inst *computev1.Ec2Instance
+
+	BeforeEach(func() {
+		inst = &computev1.Ec2Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec:       computev1.Ec2InstanceSpec{InstanceType: "m5.large", AvailabilityZone: "eu-west-1a"},
+		}
+	})
+
+	It("should render every variable", func() {
+		inst.Labels = map[string]string{computev1.SetIndexLabel: "3"}
+		inst.Spec.NamePattern = "{{.Namespace}}-{{.Name}}-{{.InstanceType}}-{{.AZ}}-{{.Index}}"
+		Expect(naming.InstanceName(inst)).To(Equal("shop-web-m5.large-eu-west-1a-3"))
+	})
+
+	It("should use index 0 outside a set", func() {
+		inst.Spec.NamePattern = "{{.Name}}-{{.Index}}"
+		Expect(naming.InstanceName(inst)).To(Equal("web-0"))
+	})
+
+	It("should render nothing without a pattern", func() {
+		Expect(naming.InstanceName(inst)).To(BeEmpty())
+	})
+
+	It("should reject unknown variables and bad templates", func() {
+		inst.Spec.NamePattern = "{{.Owner}}"
+		_, err := naming.InstanceName(inst)
+		Expect(err).To(MatchError(ContainSubstring("failed to render")))
+
+		inst.Spec.NamePattern = "{{.Name"
+		_, err = naming.InstanceName(inst)
+		Expect(err).To(MatchError(ContainSubstring("invalid name pattern")))
+	})
+
+	It("should reject names longer than a tag value", func() {
+		inst.Spec.NamePattern = strings.Repeat("x", 252) + "-{{.Namespace}}"
+		_, err := naming.InstanceName(inst)
+		Expect(err).To(MatchError(ContainSubstring("more than the 256 allowed")))
+	})
+})
@@ -0,0 +1,42 @@
+// Package statemachine describes the lifecycle of an EC2 instance and which state changes the
+// operator may ask for. AWS rejects requests such as starting a terminated instance; checking them
+// up front keeps the reconciler from retrying a call that can never succeed.
+package statemachine
+
+// State is an EC2 instance state as reported by AWS in State.Name.
+type State string
+
+const (
+	Pending      State = "pending"
+	Running      State = "running"
+	Stopping     State = "stopping"
+	Stopped      State = "stopped"
+	ShuttingDown State = "shutting-down"
+	Terminated   State = "terminated"
+)
+
+// transitions lists the states that can follow each state. A state the operator requests, such as
+// stopped, may be reached directly or through the matching transitional state.
+var transitions = map[State][]State{
+	Pending:      {Running, ShuttingDown, Terminated},
+	Running:      {Running, Stopping, Stopped, ShuttingDown, Terminated}, // running -> running is a reboot
+	Stopping:     {Stopped, ShuttingDown, Terminated},
+	Stopped:      {Pending, Running, ShuttingDown, Terminated},
+	ShuttingDown: {Terminated},
+	Terminated:   nil,
+}
+
+// IsValidTransition reports whether an instance in state from can be moved to state to. An unknown
+// from state, e.g. before the first status sync, does not block anything.
+func IsValidTransition(from, to State) bool {
+	next, known := transitions[from]
+	if !known {
+		return true
+	}
+	for _, s := range next {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statemachine_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	sm "github.com/bshaw7/operator-repo/internal/statemachine"
+)
+
+var _ = Describe("IsValidTransition", func() {
+	DescribeTable("transitions",
+		func(from, to sm.State, valid bool) {
+			Expect(sm.IsValidTransition(from, to)).To(Equal(valid))
+		},
+		Entry("start a stopped instance", sm.Stopped, sm.Running, true),
+		Entry("stop a running instance", sm.Running, sm.Stopped, true),
+		Entry("reboot a running instance", sm.Running, sm.Running, true),
+		Entry("terminate a stopping instance", sm.Stopping, sm.Terminated, true),
+		Entry("start a terminated instance", sm.Terminated, sm.Running, false),
+		Entry("stop a pending instance", sm.Pending, sm.Stopped, false),
+		Entry("reboot a stopped instance", sm.Stopped, sm.Stopping, false),
+		Entry("terminate a terminated instance", sm.Terminated, sm.Terminated, false),
+		Entry("anything from an unknown state", sm.State(""), sm.Stopped, true),
+	)
+})
@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultAWSTimeout bounds an AWS call when no timeout is configured.
+	defaultAWSTimeout = 5 * time.Second
+	// breakerThreshold is the number of consecutive timed out AWS calls that opens the circuit breaker.
+	breakerThreshold = 3
+	// breakerCooldown is how long the webhook stops calling AWS once the circuit breaker is open.
+	breakerCooldown = 60 * time.Second
+)
+
+// errBreakerOpen is returned instead of calling AWS while the circuit breaker is open.
+var errBreakerOpen = errors.New("AWS checks are paused after repeated timeouts")
+
+// AWSTimeoutGetter returns how long the webhook waits for a single AWS call.
+type AWSTimeoutGetter func(ctx context.Context) (time.Duration, error)
+
+// circuitBreaker stops the webhook from calling AWS for a while after several calls in a row timed
+// out, so an AWS outage does not make every apply wait for the full timeout. The zero value is closed.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	timeouts  int
+	openUntil time.Time
+	// now is overridden in tests.
+	now func() time.Time
+}
+
+func (b *circuitBreaker) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
+
+// allow reports whether AWS may be called. Once the cooldown has passed the next call goes through
+// and decides whether the breaker closes or opens again.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.clock().Before(b.openUntil)
+}
+
+// record counts the outcome of an AWS call and opens the breaker after breakerThreshold timeouts in a row.
+func (b *circuitBreaker) record(timedOut bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !timedOut {
+		b.timeouts = 0
+		return
+	}
+	b.timeouts++
+	if b.timeouts >= breakerThreshold {
+		b.openUntil = b.clock().Add(breakerCooldown)
+		b.timeouts = 0
+		ec2instancelog.Info("Pausing AWS checks in the webhook after repeated timeouts", "cooldown", breakerCooldown)
+	}
+}
+
+// awsTimeout returns the configured timeout for AWS calls, falling back to defaultAWSTimeout.
+func (v *Ec2InstanceCustomValidator) awsTimeout(ctx context.Context) time.Duration {
+	if v.AWSTimeout == nil {
+		return defaultAWSTimeout
+	}
+	timeout, err := v.AWSTimeout(ctx)
+	if err != nil || timeout <= 0 {
+		return defaultAWSTimeout
+	}
+	return timeout
+}
+
+// callAWS runs call with the configured timeout. Callers treat any error as a reason to admit the
+// object with a warning, so a slow AWS only ever costs one timeout per check.
+func callAWS[T any](ctx context.Context, v *Ec2InstanceCustomValidator, call func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if !v.breaker.allow() {
+		return zero, errBreakerOpen
+	}
+
+	timeout := v.awsTimeout(ctx)
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	result, err := call(callCtx)
+	timedOut := err != nil && errors.Is(callCtx.Err(), context.DeadlineExceeded)
+	v.breaker.record(timedOut)
+	if timedOut {
+		ec2instancelog.Info("AWS call from the webhook timed out, admitting without the check", "timeout", timeout)
+		return zero, fmt.Errorf("AWS did not answer within %s", timeout)
+	}
+	return result, err
+}
@@ -0,0 +1,1004 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	"github.com/bshaw7/operator-repo/internal/naming"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var ec2instancelog = logf.Log.WithName("ec2instance-resource")
+
+// ImageDescriber looks up an AMI in a region. It returns nil without an error when the AMI does not exist there.
+type ImageDescriber func(ctx context.Context, region, amiID string) (*ec2types.Image, error)
+
+// InstanceTypeDescriber looks up the capabilities of an instance type in a region.
+type InstanceTypeDescriber func(ctx context.Context, region, instanceType string) (*ec2types.InstanceTypeInfo, error)
+
+// SubnetDescriber looks up a subnet in a region. It returns nil without an error when the subnet does not exist there.
+type SubnetDescriber func(ctx context.Context, region, subnetID string) (*ec2types.Subnet, error)
+
+// RequiredTagsGetter returns the tags every Ec2Instance must carry.
+type RequiredTagsGetter func(ctx context.Context) ([]computev1.RequiredTag, error)
+
+// NamespaceSpendGetter returns the estimated monthly spend of the instances in a namespace and the
+// limit set for it, where a limit of 0 means none.
+type NamespaceSpendGetter func(ctx context.Context, namespace string) (currentUSD, limitUSD float64, err error)
+
+// CostEstimator returns the estimated monthly cost of an instance, or false when it is unknown.
+type CostEstimator func(ec2instance *computev1.Ec2Instance) (float64, bool)
+
+// DefaultsGetter returns the data of the ec2instance-defaults ConfigMap, or nil when there is none.
+type DefaultsGetter func(ctx context.Context) (map[string]string, error)
+
+// SetupEc2InstanceWebhookWithManager registers the webhooks for Ec2Instance in the manager. The
+// conversion webhook at /convert is registered along with them because v1 is the conversion hub;
+// it needs v1alpha1 in the manager's scheme.
+func SetupEc2InstanceWebhookWithManager(mgr ctrl.Manager, validator *Ec2InstanceCustomValidator, defaulter *Ec2InstanceCustomDefaulter) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&computev1.Ec2Instance{}).
+		WithValidator(validator).
+		WithDefaulter(defaulter).
+		Complete()
+}
+
+// DefaultsConfigMapName is the ConfigMap in the operator's namespace that Ec2InstanceCustomDefaulter
+// reads its defaults from.
+const DefaultsConfigMapName = "ec2instance-defaults"
+
+// +kubebuilder:webhook:path=/mutate-compute-cloud-com-v1-ec2instance,mutating=true,failurePolicy=fail,sideEffects=None,groups=compute.cloud.com,resources=ec2instances,verbs=create,versions=v1,name=mec2instance-v1.kb.io,admissionReviewVersions=v1
+
+// Ec2InstanceCustomDefaulter fills in the spec fields a new Ec2Instance leaves unset from the
+// ec2instance-defaults ConfigMap.
+type Ec2InstanceCustomDefaulter struct {
+	Defaults DefaultsGetter
+}
+
+var _ webhook.CustomDefaulter = &Ec2InstanceCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type Ec2Instance.
+func (d *Ec2InstanceCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	ec2instance, ok := obj.(*computev1.Ec2Instance)
+	if !ok {
+		return fmt.Errorf("expected an Ec2Instance object but got %T", obj)
+	}
+	if d.Defaults == nil {
+		return nil
+	}
+	defaults, err := d.Defaults(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", DefaultsConfigMapName, err)
+	}
+	if applied := applyDefaults(&ec2instance.Spec, defaults); len(applied) > 0 {
+		ec2instancelog.Info("Applied defaults to Ec2Instance", "namespace", ec2instance.GetNamespace(),
+			"name", ec2instance.GetName(), "configMap", DefaultsConfigMapName, "fields", applied)
+	}
+	return nil
+}
+
+// applyDefaults sets every field of spec that is empty and has a value in defaults, and returns the
+// paths of the fields it set. The keys of defaults are described on Ec2Instance.Spec.
+func applyDefaults(spec *computev1.Ec2InstanceSpec, defaults map[string]string) []string {
+	var applied []string
+	fields := map[string]*string{
+		"instanceType":       &spec.InstanceType,
+		"amiId":              &spec.AMIId,
+		"region":             &spec.Region,
+		"availabilityZone":   &spec.AvailabilityZone,
+		"subnet":             &spec.Subnet,
+		"keyPair":            &spec.KeyPair,
+		"iamInstanceProfile": &spec.IAMInstanceProfile,
+	}
+	// A referenced Ec2KeyPair takes the place of keyPair.
+	if spec.KeyPairRef != nil {
+		delete(fields, "keyPair")
+	}
+	// A launch template brings its own; defaults would override it.
+	if spec.LaunchTemplateRef != nil {
+		for _, key := range []string{"instanceType", "amiId", "availabilityZone", "subnet", "keyPair", "iamInstanceProfile"} {
+			delete(fields, key)
+		}
+	}
+	for key, target := range fields {
+		if *target == "" && defaults[key] != "" {
+			*target = defaults[key]
+			applied = append(applied, "spec."+key)
+		}
+	}
+	if len(spec.SecurityGroups) == 0 && len(spec.SecurityGroupRefs) == 0 && spec.LaunchTemplateRef == nil {
+		for _, group := range strings.Split(defaults["securityGroups"], ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				spec.SecurityGroups = append(spec.SecurityGroups, group)
+			}
+		}
+		if len(spec.SecurityGroups) > 0 {
+			applied = append(applied, "spec.securityGroups")
+		}
+	}
+	for key, value := range defaults {
+		tag, ok := strings.CutPrefix(key, "tags.")
+		if !ok || tag == "" {
+			continue
+		}
+		if _, set := spec.Tags[tag]; set {
+			continue
+		}
+		if spec.Tags == nil {
+			spec.Tags = map[string]string{}
+		}
+		spec.Tags[tag] = value
+		applied = append(applied, "spec.tags."+tag)
+	}
+	slices.Sort(applied)
+	return applied
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// +kubebuilder:webhook:path=/validate-compute-cloud-com-v1-ec2instance,mutating=false,failurePolicy=fail,sideEffects=None,groups=compute.cloud.com,resources=ec2instances,verbs=create;update,versions=v1,name=vec2instance-v1.kb.io,admissionReviewVersions=v1
+
+// Ec2InstanceCustomValidator validates Ec2Instance objects when they are created or updated.
+type Ec2InstanceCustomValidator struct {
+	DescribeImage        ImageDescriber
+	DescribeInstanceType InstanceTypeDescriber
+	DescribeSubnet       SubnetDescriber
+	RequiredTags         RequiredTagsGetter
+	// AWSTimeout bounds each AWS call; defaultAWSTimeout applies when it is nil.
+	AWSTimeout AWSTimeoutGetter
+	// NamespaceSpend and EstimateMonthlyCost enforce the spend limit of the namespace on creation.
+	NamespaceSpend      NamespaceSpendGetter
+	EstimateMonthlyCost CostEstimator
+
+	// AllowedAMIOwners restricts which accounts (IDs or aliases such as "amazon") AMIs may come from.
+	// When empty any owner is accepted.
+	AllowedAMIOwners []string
+
+	// EnforceEBSEncryption rejects Ec2Instances that set spec.encryptRootVolume to false.
+	EnforceEBSEncryption bool
+	// EnforceIMDSv2 rejects Ec2Instances that set spec.metadataOptions.httpTokens to optional.
+	EnforceIMDSv2 bool
+
+	breaker circuitBreaker
+}
+
+var _ webhook.CustomValidator = &Ec2InstanceCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Ec2Instance.
+func (v *Ec2InstanceCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	ec2instance, ok := obj.(*computev1.Ec2Instance)
+	if !ok {
+		return nil, fmt.Errorf("expected an Ec2Instance object but got %T", obj)
+	}
+	ec2instancelog.Info("Validation for Ec2Instance upon creation", "name", ec2instance.GetName())
+	defer observeWebhookDuration("create", time.Now())
+
+	spendWarnings, err := v.validateSpendLimit(ctx, ec2instance)
+	if err != nil {
+		return spendWarnings, err
+	}
+
+	// The checks that call AWS are independent, so run them side by side to keep admission fast.
+	var (
+		wg                                                              sync.WaitGroup
+		amiWarnings, typeWarnings, hibernationWarnings, enclaveWarnings admission.Warnings
+		sourceDestWarnings, cpuWarnings, subnetWarnings                 admission.Warnings
+		amiErr, typeErr                                                 error
+		hibernationErrs, enclaveErrs, cpuErrs, subnetErrs               field.ErrorList
+	)
+	wg.Add(7)
+	go func() {
+		defer wg.Done()
+		amiWarnings, amiErr = v.validateAMI(ctx, ec2instance)
+	}()
+	go func() {
+		defer wg.Done()
+		typeWarnings, typeErr = v.validateInstanceType(ctx, ec2instance)
+	}()
+	go func() {
+		defer wg.Done()
+		hibernationWarnings, hibernationErrs = v.validateHibernationRequirements(ctx, ec2instance.Spec)
+	}()
+	go func() {
+		defer wg.Done()
+		enclaveWarnings, enclaveErrs = v.validateNitroEnclave(ctx, ec2instance.Spec)
+	}()
+	go func() {
+		defer wg.Done()
+		sourceDestWarnings = v.validateSourceDestCheck(ctx, ec2instance.Spec)
+	}()
+	go func() {
+		defer wg.Done()
+		cpuWarnings, cpuErrs = v.validateCPUOptions(ctx, ec2instance.Spec)
+	}()
+	go func() {
+		defer wg.Done()
+		subnetWarnings, subnetErrs = v.validateSubnet(ctx, ec2instance.Spec)
+	}()
+	wg.Wait()
+
+	warnings := append(spendWarnings, amiWarnings...)
+	if amiErr != nil {
+		return warnings, amiErr
+	}
+	warnings = append(warnings, typeWarnings...)
+	if typeErr != nil {
+		return warnings, typeErr
+	}
+	warnings = append(warnings, hibernationWarnings...)
+	errs := hibernationErrs
+	warnings = append(warnings, enclaveWarnings...)
+	errs = append(errs, enclaveErrs...)
+	warnings = append(warnings, sourceDestWarnings...)
+	warnings = append(warnings, cpuWarnings...)
+	errs = append(errs, cpuErrs...)
+	warnings = append(warnings, subnetWarnings...)
+	errs = append(errs, subnetErrs...)
+	warnings = append(warnings, launchTemplateWarnings(ec2instance.Spec)...)
+	tagWarnings, tagErrs := v.validateRequiredTags(ctx, ec2instance.Spec)
+	warnings = append(warnings, tagWarnings...)
+	errs = append(errs, tagErrs...)
+	errs = append(errs, validateSnapshotSchedule(ec2instance.Spec)...)
+	errs = append(errs, validateSchedule(ec2instance.Spec)...)
+	errs = append(errs, validateNetworkInterfaces(ec2instance.Spec)...)
+	errs = append(errs, v.validateRootVolumeEncryption(ec2instance.Spec)...)
+	errs = append(errs, v.validateMetadataOptions(ec2instance.Spec)...)
+	errs = append(errs, validateCrossAccount(ec2instance.Spec)...)
+	errs = append(errs, validateNamePattern(ec2instance)...)
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+	}
+	return warnings, nil
+}
+
+// validateReplacementFields rejects changes to the AMI and subnet of a launched instance, which AWS
+// can only apply by launching a new one, unless the force-replace annotation asks for that.
+func validateReplacementFields(ec2instance, oldEc2instance *computev1.Ec2Instance) field.ErrorList {
+	if oldEc2instance.Status.InstanceID == "" || ec2instance.Annotations[computev1.ForceReplaceAnnotation] == "true" {
+		return nil
+	}
+	hint := fmt.Sprintf("delete and recreate the Ec2Instance, or set the %s: \"true\" annotation to have the operator replace the instance", computev1.ForceReplaceAnnotation)
+	var errs field.ErrorList
+	if ec2instance.Spec.AMIId != oldEc2instance.Spec.AMIId {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "amiId"), "the AMI of a launched instance cannot be changed; "+hint))
+	}
+	if ec2instance.Spec.Subnet != oldEc2instance.Spec.Subnet {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "subnet"), "the subnet of a launched instance cannot be changed; "+hint))
+	}
+	if !equality.Semantic.DeepEqual(launchedInterfaces(ec2instance.Spec.NetworkInterfaces), launchedInterfaces(oldEc2instance.Spec.NetworkInterfaces)) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "networkInterfaces"),
+			"only sourceDestCheck of the network interfaces of a launched instance can be changed; "+hint))
+	}
+	return errs
+}
+
+// launchedInterfaces returns the network interfaces without the settings that can be changed on a
+// running instance.
+func launchedInterfaces(interfaces []computev1.NetworkInterfaceSpec) []computev1.NetworkInterfaceSpec {
+	launched := make([]computev1.NetworkInterfaceSpec, 0, len(interfaces))
+	for _, ni := range interfaces {
+		ni.SourceDestCheck = nil
+		launched = append(launched, ni)
+	}
+	return launched
+}
+
+// validateAdoptInstanceID rejects pointing spec.adoptInstanceID elsewhere once an instance has been
+// adopted or launched: the object would silently keep managing the old one.
+func validateAdoptInstanceID(ec2instance, oldEc2instance *computev1.Ec2Instance) field.ErrorList {
+	if oldEc2instance.Status.InstanceID == "" || ec2instance.Spec.AdoptInstanceID == oldEc2instance.Spec.AdoptInstanceID {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(field.NewPath("spec", "adoptInstanceID"),
+		fmt.Sprintf("cannot be changed once instance %s is managed; delete the Ec2Instance with deletionPolicy Orphan and create a new one to adopt another instance", oldEc2instance.Status.InstanceID))}
+}
+
+// validateRegion rejects moving a launched instance to another region or account. Not even a forced
+// replacement allows it: the old instance would be terminated where it does not exist.
+func validateRegion(ec2instance, oldEc2instance *computev1.Ec2Instance) field.ErrorList {
+	if oldEc2instance.Status.InstanceID == "" {
+		return nil
+	}
+	var errs field.ErrorList
+	if ec2instance.Spec.Region != oldEc2instance.Spec.Region {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "region"),
+			fmt.Sprintf("cannot be changed once instance %s is launched in %s; use a RegionMigration or delete and recreate the Ec2Instance", oldEc2instance.Status.InstanceID, oldEc2instance.Spec.Region)))
+	}
+	if ec2instance.Spec.RoleARN != oldEc2instance.Spec.RoleARN {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "roleARN"),
+			fmt.Sprintf("cannot be changed once instance %s is launched; delete and recreate the Ec2Instance to use another account", oldEc2instance.Status.InstanceID)))
+	}
+	return errs
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Ec2Instance.
+func (v *Ec2InstanceCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	ec2instance, ok := newObj.(*computev1.Ec2Instance)
+	if !ok {
+		return nil, fmt.Errorf("expected an Ec2Instance object for the newObj but got %T", newObj)
+	}
+	oldEc2instance, ok := oldObj.(*computev1.Ec2Instance)
+	if !ok {
+		return nil, fmt.Errorf("expected an Ec2Instance object for the oldObj but got %T", oldObj)
+	}
+	ec2instancelog.Info("Validation for Ec2Instance upon update", "name", ec2instance.GetName())
+	defer observeWebhookDuration("update", time.Now())
+
+	errs := validateReplacementFields(ec2instance, oldEc2instance)
+	errs = append(errs, validateAdoptInstanceID(ec2instance, oldEc2instance)...)
+	errs = append(errs, validateRegion(ec2instance, oldEc2instance)...)
+	if len(errs) > 0 {
+		return nil, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+	}
+
+	// Only call AWS for fields that changed. Status and finalizer updates by the controller must not
+	// depend on AWS, and an instance launched from a copied AMI never has spec.amiId in its region.
+	var warnings admission.Warnings
+	regionChanged := ec2instance.Spec.Region != oldEc2instance.Spec.Region
+	if regionChanged || ec2instance.Spec.AMIId != oldEc2instance.Spec.AMIId {
+		amiWarnings, err := v.validateAMI(ctx, ec2instance)
+		if err != nil {
+			return amiWarnings, err
+		}
+		warnings = append(warnings, amiWarnings...)
+	}
+	if regionChanged || ec2instance.Spec.InstanceType != oldEc2instance.Spec.InstanceType ||
+		ec2instance.Spec.EBSOptimized != oldEc2instance.Spec.EBSOptimized {
+		typeWarnings, err := v.validateInstanceType(ctx, ec2instance)
+		if err != nil {
+			return append(warnings, typeWarnings...), err
+		}
+		warnings = append(warnings, typeWarnings...)
+	}
+	if regionChanged || ec2instance.Spec.InstanceType != oldEc2instance.Spec.InstanceType ||
+		ec2instance.Spec.HibernationEnabled != oldEc2instance.Spec.HibernationEnabled ||
+		ec2instance.Spec.Storage.RootVolume != oldEc2instance.Spec.Storage.RootVolume {
+		hibernationWarnings, errs := v.validateHibernationRequirements(ctx, ec2instance.Spec)
+		warnings = append(warnings, hibernationWarnings...)
+		if len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	// Nitro Enclaves are fixed at launch, so the setting cannot change afterwards.
+	if ec2instance.Spec.NitroEnclave != oldEc2instance.Spec.NitroEnclave {
+		return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, field.ErrorList{
+			field.Forbidden(field.NewPath("spec", "nitroEnclave"), "Nitro Enclaves are set at launch and cannot be changed"),
+		})
+	}
+	if ec2instance.Spec.NitroEnclave.Enabled && (regionChanged || ec2instance.Spec.InstanceType != oldEc2instance.Spec.InstanceType) {
+		enclaveWarnings, errs := v.validateNitroEnclave(ctx, ec2instance.Spec)
+		warnings = append(warnings, enclaveWarnings...)
+		if len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	// AWS cannot change the user data of an existing instance.
+	if oldEc2instance.Status.State != "" && !equality.Semantic.DeepEqual(ec2instance.Spec.UserData, oldEc2instance.Spec.UserData) {
+		return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, field.ErrorList{
+			field.Forbidden(field.NewPath("spec", "userData"), "user data is set at launch and cannot be changed once the instance exists"),
+		})
+	}
+	// CPU options are fixed at launch too; a resize must still fit them on the new instance type.
+	if ec2instance.Spec.CPUOptions != oldEc2instance.Spec.CPUOptions {
+		return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, field.ErrorList{
+			field.Forbidden(field.NewPath("spec", "cpuOptions"), "CPU options are set at launch and cannot be changed"),
+		})
+	}
+	// So is the placement group, once there is an instance.
+	if oldEc2instance.Status.InstanceID != "" && !equality.Semantic.DeepEqual(ec2instance.Spec.PlacementGroup, oldEc2instance.Spec.PlacementGroup) {
+		return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, field.ErrorList{
+			field.Forbidden(field.NewPath("spec", "placementGroup"), "the placement group is set at launch and cannot be changed once the instance exists"),
+		})
+	}
+	if regionChanged || ec2instance.Spec.InstanceType != oldEc2instance.Spec.InstanceType {
+		cpuWarnings, errs := v.validateCPUOptions(ctx, ec2instance.Spec)
+		warnings = append(warnings, cpuWarnings...)
+		if len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	if regionChanged || ec2instance.Spec.Subnet != oldEc2instance.Spec.Subnet ||
+		ec2instance.Spec.AvailabilityZone != oldEc2instance.Spec.AvailabilityZone {
+		subnetWarnings, errs := v.validateSubnet(ctx, ec2instance.Spec)
+		warnings = append(warnings, subnetWarnings...)
+		if len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	if regionChanged || ec2instance.Spec.AMIId != oldEc2instance.Spec.AMIId ||
+		!equality.Semantic.DeepEqual(ec2instance.Spec.SourceDestCheck, oldEc2instance.Spec.SourceDestCheck) {
+		warnings = append(warnings, v.validateSourceDestCheck(ctx, ec2instance.Spec)...)
+	}
+	if regionChanged || !equality.Semantic.DeepEqual(ec2instance.Spec.SnapshotSchedule, oldEc2instance.Spec.SnapshotSchedule) {
+		if errs := validateSnapshotSchedule(ec2instance.Spec); len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	if !equality.Semantic.DeepEqual(ec2instance.Spec.Schedule, oldEc2instance.Spec.Schedule) ||
+		!equality.Semantic.DeepEqual(ec2instance.Spec.SpotOptions, oldEc2instance.Spec.SpotOptions) {
+		if errs := validateSchedule(ec2instance.Spec); len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	if !equality.Semantic.DeepEqual(ec2instance.Spec.NetworkInterfaces, oldEc2instance.Spec.NetworkInterfaces) ||
+		ec2instance.Spec.Subnet != oldEc2instance.Spec.Subnet ||
+		!equality.Semantic.DeepEqual(ec2instance.Spec.SecurityGroups, oldEc2instance.Spec.SecurityGroups) ||
+		!equality.Semantic.DeepEqual(ec2instance.Spec.SecurityGroupRefs, oldEc2instance.Spec.SecurityGroupRefs) ||
+		!equality.Semantic.DeepEqual(ec2instance.Spec.SourceDestCheck, oldEc2instance.Spec.SourceDestCheck) {
+		if errs := validateNetworkInterfaces(ec2instance.Spec); len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	if !equality.Semantic.DeepEqual(ec2instance.Spec.EncryptRootVolume, oldEc2instance.Spec.EncryptRootVolume) ||
+		ec2instance.Spec.KMSKeyID != oldEc2instance.Spec.KMSKeyID {
+		if errs := v.validateRootVolumeEncryption(ec2instance.Spec); len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	if !equality.Semantic.DeepEqual(ec2instance.Spec.MetadataOptions, oldEc2instance.Spec.MetadataOptions) {
+		if errs := v.validateMetadataOptions(ec2instance.Spec); len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	if !equality.Semantic.DeepEqual(ec2instance.Spec.LaunchTemplateRef, oldEc2instance.Spec.LaunchTemplateRef) {
+		warnings = append(warnings, launchTemplateWarnings(ec2instance.Spec)...)
+	}
+	if ec2instance.Spec.RoleARN != oldEc2instance.Spec.RoleARN ||
+		!equality.Semantic.DeepEqual(ec2instance.Spec.PatchManagement, oldEc2instance.Spec.PatchManagement) ||
+		!equality.Semantic.DeepEqual(ec2instance.Spec.LaunchTemplateRef, oldEc2instance.Spec.LaunchTemplateRef) ||
+		!equality.Semantic.DeepEqual(ec2instance.Spec.SecurityGroupRefs, oldEc2instance.Spec.SecurityGroupRefs) ||
+		!equality.Semantic.DeepEqual(ec2instance.Spec.KeyPairRef, oldEc2instance.Spec.KeyPairRef) {
+		if errs := validateCrossAccount(ec2instance.Spec); len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	if ec2instance.Spec.NamePattern != oldEc2instance.Spec.NamePattern {
+		if errs := validateNamePattern(ec2instance); len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	// Objects created before a tag became required can still be updated as long as the tags are left alone.
+	if !equality.Semantic.DeepEqual(ec2instance.Spec.Tags, oldEc2instance.Spec.Tags) {
+		tagWarnings, errs := v.validateRequiredTags(ctx, ec2instance.Spec)
+		warnings = append(warnings, tagWarnings...)
+		if len(errs) > 0 {
+			return warnings, apierrors.NewInvalid(computev1.GroupVersion.WithKind("Ec2Instance").GroupKind(), ec2instance.Name, errs)
+		}
+	}
+	return warnings, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Ec2Instance.
+func (v *Ec2InstanceCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateSpendLimit rejects an instance that would take the estimated monthly spend of its
+// namespace over the limit in the NamespaceConfig.
+func (v *Ec2InstanceCustomValidator) validateSpendLimit(ctx context.Context, ec2instance *computev1.Ec2Instance) (admission.Warnings, error) {
+	if v.NamespaceSpend == nil || v.EstimateMonthlyCost == nil {
+		return nil, nil
+	}
+	current, limit, err := v.NamespaceSpend(ctx, ec2instance.Namespace)
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("failed to check the spend limit of namespace %s: %w", ec2instance.Namespace, err))
+	}
+	if limit <= 0 {
+		return nil, nil
+	}
+	cost, ok := v.EstimateMonthlyCost(ec2instance)
+	if !ok && ec2instance.Spec.InstanceType == "" {
+		return admission.Warnings{fmt.Sprintf("the instance type is left to the launch template, so the instance is not counted against the spend limit of $%.2f "+
+			"until it is launched; set the ec2instance.compute.cloud.com/estimated-monthly-cost annotation to count it now", limit)}, nil
+	}
+	if !ok {
+		return admission.Warnings{fmt.Sprintf("the monthly cost of instance type %s is unknown, so it is not counted against the spend limit of $%.2f; "+
+			"set the ec2instance.compute.cloud.com/estimated-monthly-cost annotation to count it", ec2instance.Spec.InstanceType, limit)}, nil
+	}
+	if current+cost > limit {
+		return nil, apierrors.NewForbidden(computev1.GroupVersion.WithResource("ec2instances").GroupResource(), ec2instance.Name,
+			fmt.Errorf("namespace %s would exceed its monthly spend limit: current spend is $%.2f, this instance adds an estimated $%.2f "+
+				"for a total of $%.2f, and the limit is $%.2f", ec2instance.Namespace, current, cost, current+cost, limit))
+	}
+	return nil, nil
+}
+
+// validateAMI checks that spec.amiId can be launched in spec.region, or can be copied there when autoCopyAMI is set.
+func (v *Ec2InstanceCustomValidator) validateAMI(ctx context.Context, ec2instance *computev1.Ec2Instance) (admission.Warnings, error) {
+	spec := ec2instance.Spec
+	// Private AMIs of another account cannot be seen with the operator's own credentials.
+	if spec.AMIId == "" || spec.Region == "" || spec.RoleARN != "" || v.DescribeImage == nil {
+		return nil, nil
+	}
+
+	image, err := v.describeImage(ctx, spec.Region, spec.AMIId)
+	if err != nil {
+		// Do not turn an AWS outage into an API outage; the controller reports launch failures anyway.
+		return admission.Warnings{fmt.Sprintf("could not verify AMI %s in %s: %v", spec.AMIId, spec.Region, err)}, nil
+	}
+	if image != nil {
+		return nil, v.validateImage(image, spec.Region)
+	}
+
+	// The AMI is not in the target region. Check the source region so the message can say what to do.
+	if spec.AMISourceRegion == "" || spec.AMISourceRegion == spec.Region {
+		return nil, fmt.Errorf("AMI %s does not exist in region %s; if it was published in another region, "+
+			"set spec.amiSourceRegion and either copy it with CopyImage or set spec.autoCopyAMI: true", spec.AMIId, spec.Region)
+	}
+
+	sourceImage, err := v.describeImage(ctx, spec.AMISourceRegion, spec.AMIId)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("could not verify AMI %s in %s: %v", spec.AMIId, spec.AMISourceRegion, err)}, nil
+	}
+	if sourceImage == nil {
+		return nil, fmt.Errorf("AMI %s exists in neither %s nor %s", spec.AMIId, spec.Region, spec.AMISourceRegion)
+	}
+	if err := v.validateImage(sourceImage, spec.AMISourceRegion); err != nil {
+		return nil, err
+	}
+
+	if !spec.AutoCopyAMI {
+		return nil, fmt.Errorf("AMI %s exists in %s but not in %s; copy it first with "+
+			"'aws ec2 copy-image --source-region %s --source-image-id %s --region %s --name <name>' "+
+			"and use the new AMI ID, or set spec.autoCopyAMI: true",
+			spec.AMIId, spec.AMISourceRegion, spec.Region, spec.AMISourceRegion, spec.AMIId, spec.Region)
+	}
+	return admission.Warnings{fmt.Sprintf("AMI %s will be copied from %s to %s before the instance is launched",
+		spec.AMIId, spec.AMISourceRegion, spec.Region)}, nil
+}
+
+// describeImage calls DescribeImage within the AWS timeout.
+func (v *Ec2InstanceCustomValidator) describeImage(ctx context.Context, region, amiID string) (*ec2types.Image, error) {
+	return callAWS(ctx, v, func(ctx context.Context) (*ec2types.Image, error) {
+		return v.DescribeImage(ctx, region, amiID)
+	})
+}
+
+// describeInstanceType calls DescribeInstanceType within the AWS timeout.
+func (v *Ec2InstanceCustomValidator) describeInstanceType(ctx context.Context, region, instanceType string) (*ec2types.InstanceTypeInfo, error) {
+	return callAWS(ctx, v, func(ctx context.Context) (*ec2types.InstanceTypeInfo, error) {
+		return v.DescribeInstanceType(ctx, region, instanceType)
+	})
+}
+
+// validateImage checks that the image can be used and comes from an allowed owner.
+func (v *Ec2InstanceCustomValidator) validateImage(image *ec2types.Image, region string) error {
+	if image.State != ec2types.ImageStateAvailable {
+		return fmt.Errorf("AMI %s in %s is %s, not available", aws.ToString(image.ImageId), region, image.State)
+	}
+	if len(v.AllowedAMIOwners) > 0 &&
+		!slices.Contains(v.AllowedAMIOwners, aws.ToString(image.OwnerId)) &&
+		!slices.Contains(v.AllowedAMIOwners, aws.ToString(image.ImageOwnerAlias)) {
+		return fmt.Errorf("AMI %s is owned by %s, which is not one of the allowed AMI owners %v",
+			aws.ToString(image.ImageId), aws.ToString(image.OwnerId), v.AllowedAMIOwners)
+	}
+	return nil
+}
+
+// validateInstanceType checks the requested features against the capabilities of the instance type.
+func (v *Ec2InstanceCustomValidator) validateInstanceType(ctx context.Context, ec2instance *computev1.Ec2Instance) (admission.Warnings, error) {
+	spec := ec2instance.Spec
+	// An instance type left to the launch template is checked by the controller at launch.
+	if !spec.EBSOptimized || spec.InstanceType == "" || v.DescribeInstanceType == nil {
+		return nil, nil
+	}
+
+	info, err := v.describeInstanceType(ctx, spec.Region, spec.InstanceType)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("could not verify instance type %s in %s: %v", spec.InstanceType, spec.Region, err)}, nil
+	}
+	if info.EbsInfo != nil && info.EbsInfo.EbsOptimizedSupport == ec2types.EbsOptimizedSupportUnsupported {
+		return nil, fmt.Errorf("instance type %s does not support EBS optimization; set spec.ebsOptimized: false or pick another instance type",
+			spec.InstanceType)
+	}
+	return nil, nil
+}
+
+// validateHibernationRequirements checks the prerequisites AWS has for launching an instance with
+// hibernation: an instance type that supports it, and an encrypted root volume large enough to hold
+// the contents of RAM plus some room for the operating system.
+func (v *Ec2InstanceCustomValidator) validateHibernationRequirements(ctx context.Context, spec computev1.Ec2InstanceSpec) (admission.Warnings, field.ErrorList) {
+	if !spec.HibernationEnabled {
+		return nil, nil
+	}
+
+	var errs field.ErrorList
+	rootPath := field.NewPath("spec", "storage", "rootVolume")
+
+	if !spec.Storage.RootVolume.Encrypted {
+		errs = append(errs, field.Invalid(rootPath.Child("encrypted"), spec.Storage.RootVolume.Encrypted,
+			"the root volume must be encrypted for hibernation"))
+	}
+
+	if spec.InstanceType == "" || v.DescribeInstanceType == nil {
+		return nil, errs
+	}
+	info, err := v.describeInstanceType(ctx, spec.Region, spec.InstanceType)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("could not verify hibernation support of %s in %s: %v", spec.InstanceType, spec.Region, err)}, errs
+	}
+
+	if !aws.ToBool(info.HibernationSupported) {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "instanceType"), spec.InstanceType,
+			"instance type does not support hibernation"))
+	}
+	if info.MemoryInfo != nil {
+		// Round the memory up to whole GiB, then add 2 GiB for the operating system.
+		memoryGiB := (aws.ToInt64(info.MemoryInfo.SizeInMiB) + 1023) / 1024
+		if required := memoryGiB + 2; int64(spec.Storage.RootVolume.Size) < required {
+			errs = append(errs, field.Invalid(rootPath.Child("size"), spec.Storage.RootVolume.Size,
+				fmt.Sprintf("the root volume must be at least %d GiB (%d GiB of memory + 2 GiB) for hibernation", required, memoryGiB)))
+		}
+	}
+	return nil, errs
+}
+
+// minNitroEnclaveVCPUs is the smallest instance size AWS allows Nitro Enclaves on.
+const minNitroEnclaveVCPUs = 4
+
+// validateNitroEnclave checks that the instance type supports Nitro Enclaves and is large enough
+// for them.
+func (v *Ec2InstanceCustomValidator) validateNitroEnclave(ctx context.Context, spec computev1.Ec2InstanceSpec) (admission.Warnings, field.ErrorList) {
+	if !spec.NitroEnclave.Enabled || spec.InstanceType == "" || v.DescribeInstanceType == nil {
+		return nil, nil
+	}
+	info, err := v.describeInstanceType(ctx, spec.Region, spec.InstanceType)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("could not verify Nitro Enclaves support of %s in %s: %v", spec.InstanceType, spec.Region, err)}, nil
+	}
+
+	var errs field.ErrorList
+	typePath := field.NewPath("spec", "instanceType")
+	if info.NitroEnclavesSupport != ec2types.NitroEnclavesSupportSupported {
+		errs = append(errs, field.Invalid(typePath, spec.InstanceType, "instance type does not support Nitro Enclaves"))
+	}
+	if info.VCpuInfo != nil && aws.ToInt32(info.VCpuInfo.DefaultVCpus) < minNitroEnclaveVCPUs {
+		errs = append(errs, field.Invalid(typePath, spec.InstanceType,
+			fmt.Sprintf("Nitro Enclaves need at least %d vCPUs, the instance type has %d", minNitroEnclaveVCPUs, aws.ToInt32(info.VCpuInfo.DefaultVCpus))))
+	}
+	return nil, errs
+}
+
+// validateCPUOptions checks spec.cpuOptions against the core counts and threads per core the
+// instance type allows.
+func (v *Ec2InstanceCustomValidator) validateCPUOptions(ctx context.Context, spec computev1.Ec2InstanceSpec) (admission.Warnings, field.ErrorList) {
+	cpu := spec.CPUOptions
+	if cpu.CoreCount == 0 && cpu.ThreadsPerCore == 0 {
+		return nil, nil
+	}
+	path := field.NewPath("spec", "cpuOptions")
+	var errs field.ErrorList
+	if cpu.ThreadsPerCore != 0 && cpu.ThreadsPerCore != 1 && cpu.ThreadsPerCore != 2 {
+		errs = append(errs, field.NotSupported(path.Child("threadsPerCore"), cpu.ThreadsPerCore, []string{"1", "2"}))
+	}
+	if cpu.CoreCount < 0 {
+		errs = append(errs, field.Invalid(path.Child("coreCount"), cpu.CoreCount, "must be at least 1"))
+	}
+	if len(errs) > 0 || spec.InstanceType == "" || v.DescribeInstanceType == nil {
+		return nil, errs
+	}
+	info, err := v.describeInstanceType(ctx, spec.Region, spec.InstanceType)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("could not verify CPU options of %s in %s: %v", spec.InstanceType, spec.Region, err)}, nil
+	}
+
+	// Types without valid core counts, such as T2 and T3, do not allow CPU options at all.
+	if info.VCpuInfo == nil || len(info.VCpuInfo.ValidCores) == 0 {
+		return nil, field.ErrorList{field.Invalid(field.NewPath("spec", "instanceType"), spec.InstanceType,
+			"instance type does not support specifying CPU options")}
+	}
+	if cpu.CoreCount > 0 {
+		maxCores := slices.Max(info.VCpuInfo.ValidCores)
+		if cpu.CoreCount > maxCores {
+			errs = append(errs, field.Invalid(path.Child("coreCount"), cpu.CoreCount,
+				fmt.Sprintf("instance type %s has at most %d cores", spec.InstanceType, maxCores)))
+		} else if !slices.Contains(info.VCpuInfo.ValidCores, cpu.CoreCount) {
+			errs = append(errs, field.Invalid(path.Child("coreCount"), cpu.CoreCount,
+				fmt.Sprintf("instance type %s allows core counts %v", spec.InstanceType, info.VCpuInfo.ValidCores)))
+		}
+	}
+	if cpu.ThreadsPerCore > 0 && len(info.VCpuInfo.ValidThreadsPerCore) > 0 &&
+		!slices.Contains(info.VCpuInfo.ValidThreadsPerCore, cpu.ThreadsPerCore) {
+		errs = append(errs, field.Invalid(path.Child("threadsPerCore"), cpu.ThreadsPerCore,
+			fmt.Sprintf("instance type %s allows threads per core %v", spec.InstanceType, info.VCpuInfo.ValidThreadsPerCore)))
+	}
+	return nil, errs
+}
+
+// validateSubnet checks that spec.subnet exists in spec.region and, when spec.availabilityZone is
+// set, that the subnet is in that zone.
+func (v *Ec2InstanceCustomValidator) validateSubnet(ctx context.Context, spec computev1.Ec2InstanceSpec) (admission.Warnings, field.ErrorList) {
+	// Subnets of another account cannot be seen with the operator's own credentials.
+	if spec.Subnet == "" || spec.Region == "" || spec.RoleARN != "" || v.DescribeSubnet == nil {
+		return nil, nil
+	}
+	subnet, err := callAWS(ctx, v, func(ctx context.Context) (*ec2types.Subnet, error) {
+		return v.DescribeSubnet(ctx, spec.Region, spec.Subnet)
+	})
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("could not verify subnet %s in %s: %v", spec.Subnet, spec.Region, err)}, nil
+	}
+	if subnet == nil {
+		return nil, field.ErrorList{field.NotFound(field.NewPath("spec", "subnet"), spec.Subnet)}
+	}
+	if zone := aws.ToString(subnet.AvailabilityZone); spec.AvailabilityZone != "" && zone != spec.AvailabilityZone {
+		return nil, field.ErrorList{field.Invalid(field.NewPath("spec", "availabilityZone"), spec.AvailabilityZone,
+			fmt.Sprintf("subnet %s is in availability zone %s", spec.Subnet, zone))}
+	}
+	return nil, nil
+}
+
+// routerAMIName matches the names of AMIs built to forward traffic: NAT instances, firewalls,
+// routers and VPN gateways.
+var routerAMIName = regexp.MustCompile(`(?i)nat|router|firewall|vpn|gateway|pfsense|opnsense|vyos|fortigate|palo|sophos|checkpoint|vsrx|csr1000v`)
+
+// validateSourceDestCheck warns when the source/destination check is turned off on an instance
+// whose AMI does not look like a network appliance, which is usually a mistake.
+func (v *Ec2InstanceCustomValidator) validateSourceDestCheck(ctx context.Context, spec computev1.Ec2InstanceSpec) admission.Warnings {
+	if spec.SourceDestCheck == nil || *spec.SourceDestCheck || spec.AMIId == "" || v.DescribeImage == nil {
+		return nil
+	}
+	image, err := v.describeImage(ctx, spec.Region, spec.AMIId)
+	if err != nil || image == nil {
+		// validateAMI reports AMIs that cannot be found.
+		return nil
+	}
+	if routerAMIName.MatchString(aws.ToString(image.Name)) {
+		return nil
+	}
+	return admission.Warnings{fmt.Sprintf("spec.sourceDestCheck is false but AMI %s (%s) does not look like a NAT, router or firewall image; "+
+		"the instance will accept traffic not addressed to it", spec.AMIId, aws.ToString(image.Name))}
+}
+
+// validateRequiredTags checks spec.tags against the required tags of the operator config. A missing
+// tag is only accepted when the config has a default value for it, which the operator then applies.
+func (v *Ec2InstanceCustomValidator) validateRequiredTags(ctx context.Context, spec computev1.Ec2InstanceSpec) (admission.Warnings, field.ErrorList) {
+	if v.RequiredTags == nil {
+		return nil, nil
+	}
+
+	tagsPath := field.NewPath("spec", "tags")
+	required, err := v.RequiredTags(ctx)
+	if err != nil {
+		return nil, field.ErrorList{field.InternalError(tagsPath, err)}
+	}
+
+	var warnings admission.Warnings
+	var errs field.ErrorList
+	for _, tag := range required {
+		value, ok := spec.Tags[tag.Key]
+		switch {
+		case !ok && tag.DefaultValue != "":
+			warnings = append(warnings, fmt.Sprintf("required tag %s is not set and defaults to %q", tag.Key, tag.DefaultValue))
+		case !ok:
+			errs = append(errs, field.Required(tagsPath.Key(tag.Key), fmt.Sprintf("tag %s is required", tag.Key)))
+		case value == "":
+			errs = append(errs, field.Invalid(tagsPath.Key(tag.Key), value, fmt.Sprintf("required tag %s must not be empty", tag.Key)))
+		case len(tag.AllowedValues) > 0 && !slices.Contains(tag.AllowedValues, value):
+			errs = append(errs, field.NotSupported(tagsPath.Key(tag.Key), value, tag.AllowedValues))
+		}
+	}
+	return warnings, errs
+}
+
+// validateSnapshotSchedule checks that the snapshot schedule is a cron expression the controller can
+// parse and that snapshots are not replicated into the region they are taken in.
+func validateSnapshotSchedule(spec computev1.Ec2InstanceSpec) field.ErrorList {
+	if spec.SnapshotSchedule == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec", "snapshotSchedule")
+	if _, err := cron.ParseStandard(spec.SnapshotSchedule.CronExpression); err != nil {
+		errs = append(errs, field.Invalid(path.Child("cronExpression"), spec.SnapshotSchedule.CronExpression, err.Error()))
+	}
+	for i, target := range spec.SnapshotSchedule.Replication {
+		if target.TargetRegion == spec.Region {
+			errs = append(errs, field.Invalid(path.Child("replication").Index(i).Child("targetRegion"),
+				target.TargetRegion, "must differ from spec.region"))
+		}
+	}
+	return errs
+}
+
+// validateCrossAccount rejects the features that cannot reach an instance in another account.
+// MaintenanceWindows and Ec2LaunchTemplates are created with the operator's credentials, and an
+// instance can only be patched inside a window, or launched from a template, of its own account.
+func validateCrossAccount(spec computev1.Ec2InstanceSpec) field.ErrorList {
+	if spec.RoleARN == "" {
+		return nil
+	}
+	var errs field.ErrorList
+	if spec.PatchManagement.Enabled {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "patchManagement", "enabled"),
+			"patch management is not supported with spec.roleARN: the MaintenanceWindow is in the operator's account, not the instance's"))
+	}
+	if spec.LaunchTemplateRef != nil && spec.LaunchTemplateRef.Ec2LaunchTemplate != "" {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "launchTemplateRef", "ec2LaunchTemplate"),
+			"an Ec2LaunchTemplate is not supported with spec.roleARN: its launch template is in the operator's account, not the instance's; "+
+				"refer to a launch template of the instance's account by id or name instead"))
+	}
+	if len(spec.SecurityGroupRefs) > 0 {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "securityGroupRefs"),
+			"Ec2SecurityGroups are not supported with spec.roleARN: their groups are in the operator's account, not the instance's; "+
+				"list the groups of the instance's account in spec.securityGroups instead"))
+	}
+	if spec.KeyPairRef != nil {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "keyPairRef"),
+			"an Ec2KeyPair is not supported with spec.roleARN: its key pair is in the operator's account, not the instance's; "+
+				"name a key pair of the instance's account in spec.keyPair instead"))
+	}
+	return errs
+}
+
+// validateSchedule checks that spec.schedule is made of cron expressions and a time zone the
+// controller can parse, and that a scheduled stop can actually stop the instance.
+func validateSchedule(spec computev1.Ec2InstanceSpec) field.ErrorList {
+	if spec.Schedule == nil {
+		return nil
+	}
+	path := field.NewPath("spec", "schedule")
+	timezone := spec.Schedule.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return field.ErrorList{field.Invalid(path.Child("timezone"), spec.Schedule.Timezone, err.Error())}
+	}
+
+	var errs field.ErrorList
+	for _, expression := range []struct{ name, value string }{
+		{"stopCron", spec.Schedule.StopCron},
+		{"startCron", spec.Schedule.StartCron},
+	} {
+		switch {
+		case expression.value == "":
+		case strings.HasPrefix(expression.value, "CRON_TZ=") || strings.HasPrefix(expression.value, "TZ="):
+			errs = append(errs, field.Invalid(path.Child(expression.name), expression.value, "set the time zone in spec.schedule.timezone"))
+		default:
+			if _, err := cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", timezone, expression.value)); err != nil {
+				errs = append(errs, field.Invalid(path.Child(expression.name), expression.value, err.Error()))
+			}
+		}
+	}
+	spot := spec.SpotOptions
+	if spec.Schedule.StopCron != "" && spot.Enabled &&
+		(spot.InterruptionBehavior == "" || spot.InterruptionBehavior == computev1.SpotInterruptionTerminate) {
+		errs = append(errs, field.Invalid(path.Child("stopCron"), spec.Schedule.StopCron,
+			"Spot instances that terminate on interruption cannot be stopped"))
+	}
+	return errs
+}
+
+// validateMetadataOptions rejects allowing IMDSv1 while the operator enforces IMDSv2.
+func (v *Ec2InstanceCustomValidator) validateMetadataOptions(spec computev1.Ec2InstanceSpec) field.ErrorList {
+	if !v.EnforceIMDSv2 || spec.MetadataOptions == nil || spec.MetadataOptions.HTTPTokens != "optional" {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(field.NewPath("spec", "metadataOptions", "httpTokens"),
+		"the operator enforces IMDSv2, session tokens cannot be optional")}
+}
+
+// launchTemplateWarnings warns about the fields of spec that override the launch template of
+// spec.launchTemplateRef, which can be surprising. instanceType and amiId are the documented way to
+// launch a template with another type or image, so they are left out.
+func launchTemplateWarnings(spec computev1.Ec2InstanceSpec) admission.Warnings {
+	if spec.LaunchTemplateRef == nil {
+		return nil
+	}
+	set := map[string]bool{
+		"spec.availabilityZone":   spec.AvailabilityZone != "",
+		"spec.subnet":             spec.Subnet != "",
+		"spec.keyPair":            spec.KeyPair != "" || spec.KeyPairRef != nil,
+		"spec.securityGroups":     len(spec.SecurityGroups) > 0 || len(spec.SecurityGroupRefs) > 0,
+		"spec.iamInstanceProfile": spec.IAMInstanceProfile != "",
+		"spec.userData":           spec.UserData != nil,
+		"spec.networkInterfaces":  len(spec.NetworkInterfaces) > 0,
+		"spec.placementGroup":     spec.PlacementGroup != nil,
+		"spec.cpuOptions":         spec.CPUOptions != computev1.CPUOptions{},
+	}
+	var overrides []string
+	for path, isSet := range set {
+		if isSet {
+			overrides = append(overrides, path)
+		}
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+	slices.Sort(overrides)
+	return admission.Warnings{fmt.Sprintf("%s override the values of the launch template in spec.launchTemplateRef",
+		strings.Join(overrides, ", "))}
+}
+
+// validateRootVolumeEncryption rejects an unencrypted root volume while the operator enforces EBS
+// encryption, and a KMS key for a root volume that is not encrypted.
+func (v *Ec2InstanceCustomValidator) validateRootVolumeEncryption(spec computev1.Ec2InstanceSpec) field.ErrorList {
+	if spec.EncryptRootVolume == nil || *spec.EncryptRootVolume {
+		return nil
+	}
+	var errs field.ErrorList
+	if v.EnforceEBSEncryption {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "encryptRootVolume"),
+			"the operator enforces EBS encryption, the root volume cannot be unencrypted"))
+	}
+	if spec.KMSKeyID != "" {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "kmsKeyID"), spec.KMSKeyID,
+			"a KMS key cannot be used with encryptRootVolume: false"))
+	}
+	return errs
+}
+
+// validateNetworkInterfaces checks that spec.networkInterfaces has a primary interface and is not
+// combined with the fields that describe the single interface of an instance launched without it.
+func validateNetworkInterfaces(spec computev1.Ec2InstanceSpec) field.ErrorList {
+	if len(spec.NetworkInterfaces) == 0 {
+		return nil
+	}
+	path := field.NewPath("spec", "networkInterfaces")
+	var errs field.ErrorList
+	if !slices.ContainsFunc(spec.NetworkInterfaces, func(ni computev1.NetworkInterfaceSpec) bool { return ni.DeviceIndex == 0 }) {
+		errs = append(errs, field.Required(path, "the primary interface, device index 0, is required"))
+	}
+	for i, ni := range spec.NetworkInterfaces {
+		if ni.AssociatePublicIP && len(spec.NetworkInterfaces) > 1 {
+			errs = append(errs, field.Invalid(path.Index(i).Child("associatePublicIP"), true,
+				"AWS only assigns a public IP at launch to instances with a single network interface"))
+		}
+	}
+	if spec.Subnet != "" {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "subnet"), "set subnetID of each network interface instead"))
+	}
+	if len(spec.SecurityGroups) > 0 {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "securityGroups"), "set securityGroups of each network interface instead"))
+	}
+	if len(spec.SecurityGroupRefs) > 0 {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "securityGroupRefs"), "cannot be combined with networkInterfaces"))
+	}
+	if spec.SourceDestCheck != nil {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "sourceDestCheck"), "set sourceDestCheck of each network interface instead"))
+	}
+	return errs
+}
+
+// validateNamePattern checks that spec.namePattern renders to a valid Name tag for this instance.
+func validateNamePattern(ec2instance *computev1.Ec2Instance) field.ErrorList {
+	if _, err := naming.InstanceName(ec2instance); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "namePattern"), ec2instance.Spec.NamePattern, err.Error())}
+	}
+	return nil
+}
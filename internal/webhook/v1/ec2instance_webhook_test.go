@@ -0,0 +1,736 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	computev1 "github.com/bshaw7/operator-repo/api/v1"
+	computev1alpha1 "github.com/bshaw7/operator-repo/api/v1alpha1"
+)
+
+// fakeImages serves DescribeImage from a map keyed by region and AMI ID.
+func fakeImages(images map[string]*ec2types.Image) ImageDescriber {
+	return func(_ context.Context, region, amiID string) (*ec2types.Image, error) {
+		return images[region+"/"+amiID], nil
+	}
+}
+
+func availableImage(amiID, owner string) *ec2types.Image {
+	return &ec2types.Image{ImageId: aws.String(amiID), OwnerId: aws.String(owner), State: ec2types.ImageStateAvailable}
+}
+
+var _ = Describe("Ec2Instance Webhook", func() {
+	var (
+		obj       *computev1.Ec2Instance
+		validator Ec2InstanceCustomValidator
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &computev1.Ec2Instance{
+			Spec: computev1.Ec2InstanceSpec{InstanceType: "t3.micro", AMIId: "ami-123", Region: "eu-west-1"},
+		}
+		validator = Ec2InstanceCustomValidator{
+			DescribeImage: fakeImages(map[string]*ec2types.Image{
+				"eu-west-1/ami-123": availableImage("ami-123", "111111111111"),
+				"us-east-1/ami-456": availableImage("ami-456", "111111111111"),
+			}),
+		}
+	})
+
+	Context("When creating an Ec2Instance", func() {
+		It("Should admit an AMI that exists in the target region", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should reject an AMI owned by an account that is not allowed", func() {
+			validator.AllowedAMIOwners = []string{"222222222222"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("not one of the allowed AMI owners")))
+		})
+
+		It("Should reject an AMI from another region and suggest copying it", func() {
+			obj.Spec.AMIId = "ami-456"
+			obj.Spec.AMISourceRegion = "us-east-1"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("aws ec2 copy-image --source-region us-east-1")))
+		})
+
+		It("Should admit an AMI from another region with a warning when autoCopyAMI is set", func() {
+			obj.Spec.AMIId = "ami-456"
+			obj.Spec.AMISourceRegion = "us-east-1"
+			obj.Spec.AutoCopyAMI = true
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("will be copied")))
+		})
+
+		It("Should reject an AMI that exists nowhere", func() {
+			obj.Spec.AMIId = "ami-789"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("does not exist in region eu-west-1")))
+		})
+
+		It("Should reject EBS optimization on an instance type that does not support it", func() {
+			validator.DescribeInstanceType = func(context.Context, string, string) (*ec2types.InstanceTypeInfo, error) {
+				return &ec2types.InstanceTypeInfo{
+					EbsInfo: &ec2types.EbsInfo{EbsOptimizedSupport: ec2types.EbsOptimizedSupportUnsupported},
+				}, nil
+			}
+			obj.Spec.InstanceType = "t2.micro"
+			obj.Spec.EBSOptimized = true
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("does not support EBS optimization")))
+		})
+
+		It("Should report every unmet hibernation requirement", func() {
+			validator.DescribeInstanceType = func(context.Context, string, string) (*ec2types.InstanceTypeInfo, error) {
+				return &ec2types.InstanceTypeInfo{
+					HibernationSupported: aws.Bool(false),
+					MemoryInfo:           &ec2types.MemoryInfo{SizeInMiB: aws.Int64(8192)},
+				}, nil
+			}
+			obj.Spec.HibernationEnabled = true
+			obj.Spec.Storage.RootVolume.Size = 8
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.instanceType")))
+			Expect(err).To(MatchError(ContainSubstring("spec.storage.rootVolume.encrypted")))
+			Expect(err).To(MatchError(ContainSubstring("at least 10 GiB")))
+		})
+
+		It("Should admit hibernation when the requirements are met", func() {
+			validator.DescribeInstanceType = func(context.Context, string, string) (*ec2types.InstanceTypeInfo, error) {
+				return &ec2types.InstanceTypeInfo{
+					HibernationSupported: aws.Bool(true),
+					MemoryInfo:           &ec2types.MemoryInfo{SizeInMiB: aws.Int64(1024)},
+				}, nil
+			}
+			obj.Spec.HibernationEnabled = true
+			obj.Spec.Storage.RootVolume = computev1.VolumeConfig{Size: 3, Encrypted: true}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should reject Nitro Enclaves on an instance type that is too small", func() {
+			validator.DescribeInstanceType = func(context.Context, string, string) (*ec2types.InstanceTypeInfo, error) {
+				return &ec2types.InstanceTypeInfo{
+					NitroEnclavesSupport: ec2types.NitroEnclavesSupportSupported,
+					VCpuInfo:             &ec2types.VCpuInfo{DefaultVCpus: aws.Int32(2)},
+				}, nil
+			}
+			obj.Spec.InstanceType = "m5.large"
+			obj.Spec.NitroEnclave.Enabled = true
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("at least 4 vCPUs")))
+		})
+
+		It("Should leave the checks of an instance type from the launch template to the controller", func() {
+			validator.DescribeInstanceType = func(context.Context, string, string) (*ec2types.InstanceTypeInfo, error) {
+				Fail("an instance type left to the launch template cannot be looked up")
+				return nil, nil
+			}
+			obj.Spec.InstanceType = ""
+			obj.Spec.AMIId = ""
+			obj.Spec.LaunchTemplateRef = &computev1.LaunchTemplateRef{ID: "lt-0123456789abcdef0"}
+			obj.Spec.NitroEnclave.Enabled = true
+			obj.Spec.EBSOptimized = true
+			obj.Spec.CPUOptions = computev1.CPUOptions{CoreCount: 2}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject CPU options the instance type does not allow", func() {
+			validator.DescribeInstanceType = func(context.Context, string, string) (*ec2types.InstanceTypeInfo, error) {
+				return &ec2types.InstanceTypeInfo{VCpuInfo: &ec2types.VCpuInfo{
+					ValidCores:          []int32{1, 2},
+					ValidThreadsPerCore: []int32{1, 2},
+				}}, nil
+			}
+			obj.Spec.InstanceType = "m5.large"
+			obj.Spec.CPUOptions = computev1.CPUOptions{CoreCount: 4, ThreadsPerCore: 1}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("at most 2 cores")))
+
+			obj.Spec.CPUOptions.CoreCount = 2
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should reject CPU options on an instance type without them", func() {
+			validator.DescribeInstanceType = func(context.Context, string, string) (*ec2types.InstanceTypeInfo, error) {
+				return &ec2types.InstanceTypeInfo{VCpuInfo: &ec2types.VCpuInfo{DefaultVCpus: aws.Int32(2)}}, nil
+			}
+			obj.Spec.InstanceType = "t3.micro"
+			obj.Spec.CPUOptions.ThreadsPerCore = 1
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("does not support specifying CPU options")))
+		})
+
+		It("Should reject a subnet outside the availability zone", func() {
+			validator.DescribeSubnet = func(context.Context, string, string) (*ec2types.Subnet, error) {
+				return &ec2types.Subnet{AvailabilityZone: aws.String("us-east-1b")}, nil
+			}
+			obj.Spec.Subnet = "subnet-1234"
+			obj.Spec.AvailabilityZone = "us-east-1a"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("subnet subnet-1234 is in availability zone us-east-1b")))
+
+			obj.Spec.AvailabilityZone = "us-east-1b"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should reject a subnet that does not exist", func() {
+			validator.DescribeSubnet = func(context.Context, string, string) (*ec2types.Subnet, error) {
+				return nil, nil
+			}
+			obj.Spec.Subnet = "subnet-1234"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.subnet: Not found")))
+		})
+
+		It("Should not look up the subnet of an instance in another account", func() {
+			validator.DescribeSubnet = func(context.Context, string, string) (*ec2types.Subnet, error) {
+				return nil, nil
+			}
+			obj.Spec.Subnet = "subnet-1234"
+			obj.Spec.RoleARN = "arn:aws:iam::111111111111:role/ec2operator"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should reject patch management for an instance in another account", func() {
+			obj.Spec.RoleARN = "arn:aws:iam::111111111111:role/ec2operator"
+			obj.Spec.PatchManagement.Enabled = true
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.patchManagement.enabled: Forbidden")))
+
+			oldObj := obj.DeepCopy()
+			oldObj.Spec.PatchManagement.Enabled = false
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.patchManagement.enabled: Forbidden")))
+		})
+
+		It("Should reject an Ec2LaunchTemplate for an instance in another account", func() {
+			obj.Spec.RoleARN = "arn:aws:iam::111111111111:role/ec2operator"
+			obj.Spec.LaunchTemplateRef = &computev1.LaunchTemplateRef{Ec2LaunchTemplate: "web"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.launchTemplateRef.ec2LaunchTemplate: Forbidden")))
+
+			oldObj := obj.DeepCopy()
+			oldObj.Spec.LaunchTemplateRef = nil
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.launchTemplateRef.ec2LaunchTemplate: Forbidden")))
+
+			obj.Spec.LaunchTemplateRef = &computev1.LaunchTemplateRef{Name: "web"}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject Ec2SecurityGroups for an instance in another account", func() {
+			obj.Spec.RoleARN = "arn:aws:iam::111111111111:role/ec2operator"
+			obj.Spec.SecurityGroupRefs = []corev1.LocalObjectReference{{Name: "web"}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.securityGroupRefs: Forbidden")))
+
+			oldObj := obj.DeepCopy()
+			oldObj.Spec.SecurityGroupRefs = nil
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.securityGroupRefs: Forbidden")))
+
+			obj.Spec.SecurityGroupRefs = nil
+			obj.Spec.SecurityGroups = []string{"sg-0123456789abcdef0"}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject an Ec2KeyPair for an instance in another account", func() {
+			obj.Spec.RoleARN = "arn:aws:iam::111111111111:role/ec2operator"
+			obj.Spec.KeyPairRef = &corev1.LocalObjectReference{Name: "web"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.keyPairRef: Forbidden")))
+
+			oldObj := obj.DeepCopy()
+			oldObj.Spec.KeyPairRef = nil
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.keyPairRef: Forbidden")))
+
+			obj.Spec.KeyPairRef = nil
+			obj.Spec.KeyPair = "web"
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a name pattern that renders more than 256 characters", func() {
+			obj.Spec.NamePattern = strings.Repeat("{{.InstanceType}}", 40)
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.namePattern")))
+
+			obj.Spec.NamePattern = "{{.Namespace}}-{{.Name}}-{{.Index}}"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should name every missing or invalid required tag", func() {
+			validator.RequiredTags = func(context.Context) ([]computev1.RequiredTag, error) {
+				return []computev1.RequiredTag{
+					{Key: "Owner"},
+					{Key: "CostCenter", AllowedValues: []string{"100", "200"}},
+					{Key: "Environment", DefaultValue: "dev"},
+				}, nil
+			}
+			obj.Spec.Tags = map[string]string{"CostCenter": "300"}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.tags[Owner]: Required value")))
+			Expect(err).To(MatchError(ContainSubstring(`spec.tags[CostCenter]: Unsupported value: "300"`)))
+			Expect(warnings).To(ContainElement(ContainSubstring("defaults to \"dev\"")))
+		})
+
+		It("Should reject a snapshot schedule that is not a cron expression", func() {
+			obj.Spec.SnapshotSchedule = &computev1.SnapshotScheduleSpec{CronExpression: "every night"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.snapshotSchedule.cronExpression")))
+		})
+
+		It("Should reject replicating snapshots into the region of the instance", func() {
+			obj.Spec.SnapshotSchedule = &computev1.SnapshotScheduleSpec{
+				CronExpression: "0 3 * * *",
+				Replication: []computev1.SnapshotReplicationTarget{
+					{TargetRegion: "us-west-2"},
+					{TargetRegion: obj.Spec.Region},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.snapshotSchedule.replication[1].targetRegion")))
+		})
+
+		It("Should accept a stop and start schedule in a time zone", func() {
+			obj.Spec.Schedule = &computev1.InstanceScheduleSpec{StopCron: "0 19 * * 1-5", StartCron: "0 7 * * 1-5", Timezone: "Europe/Berlin"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject an invalid stop/start schedule", func() {
+			obj.Spec.Schedule = &computev1.InstanceScheduleSpec{StopCron: "at night", StartCron: "CRON_TZ=UTC 0 7 * * *"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.schedule.stopCron")))
+			Expect(err).To(MatchError(ContainSubstring("spec.schedule.startCron")))
+
+			obj.Spec.Schedule = &computev1.InstanceScheduleSpec{StopCron: "0 19 * * *", Timezone: "Mars/Olympus"}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.schedule.timezone")))
+		})
+
+		It("Should require the primary interface when network interfaces are listed", func() {
+			obj.Spec.NetworkInterfaces = []computev1.NetworkInterfaceSpec{
+				{DeviceIndex: 0, SubnetID: "subnet-a"},
+				{DeviceIndex: 1, SubnetID: "subnet-b"},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+
+			obj.Spec.NetworkInterfaces = obj.Spec.NetworkInterfaces[1:]
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.networkInterfaces: Required value")))
+		})
+
+		It("Should reject network interfaces combined with the instance-level network fields", func() {
+			obj.Spec.NetworkInterfaces = []computev1.NetworkInterfaceSpec{
+				{DeviceIndex: 0, SubnetID: "subnet-a", AssociatePublicIP: true},
+				{DeviceIndex: 1, SubnetID: "subnet-b"},
+			}
+			obj.Spec.Subnet = "subnet-a"
+			obj.Spec.SecurityGroups = []string{"sg-123"}
+			obj.Spec.SourceDestCheck = aws.Bool(false)
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.subnet: Forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("spec.securityGroups: Forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("spec.sourceDestCheck: Forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("spec.networkInterfaces[0].associatePublicIP")))
+		})
+
+		It("Should reject an unencrypted root volume while EBS encryption is enforced", func() {
+			obj.Spec.EncryptRootVolume = aws.Bool(false)
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+
+			validator.EnforceEBSEncryption = true
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.encryptRootVolume: Forbidden")))
+
+			obj.Spec.EncryptRootVolume = nil
+			obj.Spec.KMSKeyID = "alias/ebs"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should reject a KMS key for an unencrypted root volume", func() {
+			obj.Spec.EncryptRootVolume = aws.Bool(false)
+			obj.Spec.KMSKeyID = "alias/ebs"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.kmsKeyID")))
+		})
+
+		It("Should warn about fields that override the launch template", func() {
+			obj.Spec.LaunchTemplateRef = &computev1.LaunchTemplateRef{ID: "lt-0123456789abcdef0"}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+
+			obj.Spec.Subnet = "subnet-1"
+			obj.Spec.KeyPair = "ops"
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf("spec.keyPair, spec.subnet override the values of the launch template in spec.launchTemplateRef"))
+		})
+
+		It("Should reject optional session tokens while IMDSv2 is enforced", func() {
+			obj.Spec.MetadataOptions = &computev1.MetadataOptionsSpec{HTTPTokens: "optional"}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+
+			validator.EnforceIMDSv2 = true
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.metadataOptions.httpTokens: Forbidden")))
+
+			obj.Spec.MetadataOptions.HTTPTokens = "required"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should reject scheduled stops of Spot instances that terminate on interruption", func() {
+			obj.Spec.Schedule = &computev1.InstanceScheduleSpec{StopCron: "0 19 * * *"}
+			obj.Spec.SpotOptions = computev1.SpotOptionsSpec{Enabled: true, InterruptionBehavior: computev1.SpotInterruptionTerminate}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("cannot be stopped")))
+		})
+
+		It("Should only warn when AWS cannot be reached", func() {
+			validator.DescribeImage = func(context.Context, string, string) (*ec2types.Image, error) {
+				return nil, errors.New("connection refused")
+			}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+		})
+
+		It("Should warn when the source/destination check is off for an AMI that is no router", func() {
+			obj.Spec.SourceDestCheck = aws.Bool(false)
+			validator.DescribeImage = func(context.Context, string, string) (*ec2types.Image, error) {
+				image := availableImage("ami-123", "111111111111")
+				image.Name = aws.String("ubuntu-jammy-22.04")
+				return image, nil
+			}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("does not look like a NAT, router or firewall image")))
+
+			validator.DescribeImage = func(context.Context, string, string) (*ec2types.Image, error) {
+				image := availableImage("ami-123", "111111111111")
+				image.Name = aws.String("amzn-ami-vpc-nat-2018.03")
+				return image, nil
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should reject an instance that takes the namespace over its spend limit", func() {
+			validator.NamespaceSpend = func(context.Context, string) (float64, float64, error) { return 450, 500, nil }
+			validator.EstimateMonthlyCost = func(*computev1.Ec2Instance) (float64, bool) { return 60.74, true }
+			obj.Namespace = "team-a"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(And(
+				ContainSubstring("current spend is $450.00"),
+				ContainSubstring("adds an estimated $60.74"),
+				ContainSubstring("the limit is $500.00"),
+			)))
+		})
+
+		It("Should admit an instance within the spend limit", func() {
+			validator.NamespaceSpend = func(context.Context, string) (float64, float64, error) { return 400, 500, nil }
+			validator.EstimateMonthlyCost = func(*computev1.Ec2Instance) (float64, bool) { return 60.74, true }
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should admit with a warning when AWS does not answer in time", func() {
+			validator.AWSTimeout = func(context.Context) (time.Duration, error) { return 10 * time.Millisecond, nil }
+			validator.DescribeImage = func(ctx context.Context, _, _ string) (*ec2types.Image, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("AWS did not answer within 10ms")))
+		})
+
+		It("Should stop calling AWS after repeated timeouts and resume after the cooldown", func() {
+			now := time.Now()
+			validator.breaker.now = func() time.Time { return now }
+			validator.AWSTimeout = func(context.Context) (time.Duration, error) { return 10 * time.Millisecond, nil }
+			calls := 0
+			validator.DescribeImage = func(ctx context.Context, _, _ string) (*ec2types.Image, error) {
+				calls++
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			for range breakerThreshold + 1 {
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(calls).To(Equal(breakerThreshold))
+
+			now = now.Add(breakerCooldown)
+			validator.DescribeImage = fakeImages(map[string]*ec2types.Image{"eu-west-1/ami-123": availableImage("ami-123", "111111111111")})
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+	})
+
+	Context("When updating an Ec2Instance", func() {
+		It("Should not look up an unchanged AMI", func() {
+			validator.DescribeImage = func(context.Context, string, string) (*ec2types.Image, error) {
+				Fail("DescribeImage must not be called")
+				return nil, nil
+			}
+			oldObj := obj.DeepCopy()
+			obj.Status.State = "running"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeEmpty())
+		})
+
+		It("Should reject changing Nitro Enclaves", func() {
+			oldObj := obj.DeepCopy()
+			obj.Spec.NitroEnclave.Enabled = true
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.nitroEnclave: Forbidden")))
+		})
+
+		It("Should reject changing user data once the instance exists", func() {
+			oldObj := obj.DeepCopy()
+			obj.Spec.UserData = &computev1.UserDataSpec{Inline: "#!/bin/bash\necho hi\n"}
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeEmpty())
+
+			oldObj.Status.State = "pending"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.userData: Forbidden")))
+		})
+
+		It("Should reject changing the AMI or subnet of a launched instance unless replacement is forced", func() {
+			validator.DescribeImage = fakeImages(map[string]*ec2types.Image{"eu-west-1/ami-456": availableImage("ami-456", "111111111111")})
+			oldObj := obj.DeepCopy()
+			oldObj.Status.InstanceID = "i-0123456789abcdef0"
+			obj.Spec.AMIId = "ami-456"
+			obj.Spec.Subnet = "subnet-456"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.amiId: Forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("spec.subnet: Forbidden")))
+
+			obj.Spec.Subnet = oldObj.Spec.Subnet
+			obj.Annotations = map[string]string{computev1.ForceReplaceAnnotation: "true"}
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeEmpty())
+		})
+
+		It("Should only allow changing the source/destination check of the interfaces of a launched instance", func() {
+			obj.Spec.NetworkInterfaces = []computev1.NetworkInterfaceSpec{{DeviceIndex: 0, SubnetID: "subnet-a"}}
+			obj.Status.InstanceID = "i-0123456789abcdef0"
+			oldObj := obj.DeepCopy()
+			obj.Spec.NetworkInterfaces[0].SourceDestCheck = aws.Bool(false)
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeEmpty())
+
+			obj.Spec.NetworkInterfaces[0].SubnetID = "subnet-b"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.networkInterfaces: Forbidden")))
+		})
+
+		It("Should reject changing the placement group once the instance exists", func() {
+			oldObj := obj.DeepCopy()
+			obj.Spec.PlacementGroup = &computev1.PlacementGroupSpec{Name: "hpc"}
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeEmpty())
+
+			oldObj = obj.DeepCopy()
+			oldObj.Status.InstanceID = "i-0123456789abcdef0"
+			obj.Spec.PlacementGroup.PartitionNumber = 2
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.placementGroup: Forbidden")))
+		})
+
+		It("Should reject changing adoptInstanceID after adoption", func() {
+			obj.Spec.AdoptInstanceID = "i-0123456789abcdef0"
+			oldObj := obj.DeepCopy()
+			obj.Spec.AdoptInstanceID = "i-0fedcba9876543210"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeEmpty())
+
+			oldObj.Status.InstanceID = "i-0123456789abcdef0"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.adoptInstanceID: Forbidden")))
+		})
+
+		It("Should reject moving a launched instance to another region", func() {
+			validator.DescribeImage = fakeImages(map[string]*ec2types.Image{"us-west-2/ami-123": availableImage("ami-123", "111111111111")})
+			oldObj := obj.DeepCopy()
+			obj.Spec.Region = "us-west-2"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeEmpty())
+
+			oldObj.Status.InstanceID = "i-0123456789abcdef0"
+			obj.Annotations = map[string]string{computev1.ForceReplaceAnnotation: "true"}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.region: Forbidden")))
+		})
+
+		It("Should reject switching a launched instance to another account", func() {
+			oldObj := obj.DeepCopy()
+			oldObj.Status.InstanceID = "i-0123456789abcdef0"
+			obj.Spec.RoleARN = "arn:aws:iam::111111111111:role/ec2operator"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.roleARN: Forbidden")))
+		})
+
+		It("Should reject changing CPU options", func() {
+			oldObj := obj.DeepCopy()
+			obj.Spec.CPUOptions.ThreadsPerCore = 1
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.cpuOptions: Forbidden")))
+		})
+
+		It("Should not enforce required tags when the tags are unchanged", func() {
+			validator.RequiredTags = func(context.Context) ([]computev1.RequiredTag, error) {
+				return []computev1.RequiredTag{{Key: "Owner"}}, nil
+			}
+			oldObj := obj.DeepCopy()
+			obj.Spec.XRayEnabled = true
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeEmpty())
+
+			obj.Spec.Tags = map[string]string{"Team": "a"}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("tag Owner is required")))
+		})
+	})
+
+	Context("When defaulting a new Ec2Instance", func() {
+		It("Should fill in unset fields from the defaults ConfigMap and keep the ones that are set", func() {
+			defaulter := Ec2InstanceCustomDefaulter{
+				Defaults: func(context.Context) (map[string]string, error) {
+					return map[string]string{
+						"region":          "us-east-1",
+						"subnet":          "subnet-123",
+						"securityGroups":  "sg-1, sg-2",
+						"tags.CostCenter": "platform",
+						"tags.Owner":      "nobody",
+					}, nil
+				},
+			}
+			obj.Spec.Tags = map[string]string{"Owner": "team-a"}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Region).To(Equal("eu-west-1"))
+			Expect(obj.Spec.Subnet).To(Equal("subnet-123"))
+			Expect(obj.Spec.SecurityGroups).To(Equal([]string{"sg-1", "sg-2"}))
+			Expect(obj.Spec.Tags).To(Equal(map[string]string{"Owner": "team-a", "CostCenter": "platform"}))
+		})
+
+		It("Should report the fields it set", func() {
+			spec := computev1.Ec2InstanceSpec{InstanceType: "t3.micro"}
+			applied := applyDefaults(&spec, map[string]string{"instanceType": "m5.large", "keyPair": "ops", "tags.Team": "a"})
+			Expect(applied).To(Equal([]string{"spec.keyPair", "spec.tags.Team"}))
+		})
+
+		It("Should not add default security groups when Ec2SecurityGroups are referenced", func() {
+			spec := computev1.Ec2InstanceSpec{SecurityGroupRefs: []corev1.LocalObjectReference{{Name: "web"}}}
+			Expect(applyDefaults(&spec, map[string]string{"securityGroups": "sg-1"})).To(BeEmpty())
+			Expect(spec.SecurityGroups).To(BeEmpty())
+		})
+
+		It("Should not add a default key pair when an Ec2KeyPair is referenced", func() {
+			spec := computev1.Ec2InstanceSpec{KeyPairRef: &corev1.LocalObjectReference{Name: "ops"}}
+			Expect(applyDefaults(&spec, map[string]string{"keyPair": "default"})).To(BeEmpty())
+			Expect(spec.KeyPair).To(BeEmpty())
+		})
+
+		It("Should only add the defaults a launch template cannot provide", func() {
+			spec := computev1.Ec2InstanceSpec{LaunchTemplateRef: &computev1.LaunchTemplateRef{Name: "web"}}
+			defaults := map[string]string{"instanceType": "t3.small", "subnet": "subnet-1", "keyPair": "default", "securityGroups": "sg-1"}
+			Expect(applyDefaults(&spec, defaults)).To(BeEmpty())
+			Expect(spec.InstanceType).To(BeEmpty())
+			Expect(spec.Subnet).To(BeEmpty())
+			Expect(spec.SecurityGroups).To(BeEmpty())
+		})
+	})
+
+	Context("When converting between v1 and v1alpha1", func() {
+		It("Should not lose v1 fields on a round trip through v1alpha1", func() {
+			hub := &computev1.Ec2Instance{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a", Annotations: map[string]string{"owner": "team-a"}},
+				Spec: computev1.Ec2InstanceSpec{
+					InstanceType:   "t3.micro",
+					AMIId:          "ami-123",
+					Region:         "eu-west-1",
+					SecurityGroups: []string{"sg-1"},
+					Tags:           map[string]string{"Team": "a"},
+					AutoRecovery:   true,
+				},
+				Status: computev1.Ec2InstanceStatus{
+					InstanceID: "i-123",
+					State:      "running",
+					PublicIP:   "203.0.113.10",
+					Conditions: []metav1.Condition{{
+						Type:               computev1.ConditionReady,
+						Status:             metav1.ConditionTrue,
+						Reason:             "Running",
+						LastTransitionTime: metav1.NewTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)),
+					}},
+				},
+			}
+
+			spoke := &computev1alpha1.Ec2Instance{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			Expect(spoke.Spec).To(Equal(computev1alpha1.Ec2InstanceSpec{InstanceType: "t3.micro", AMIID: "ami-123"}))
+			Expect(spoke.Status).To(Equal(computev1alpha1.Ec2InstanceStatus{InstanceID: "i-123", State: "running"}))
+			Expect(spoke.Annotations).To(HaveKey(computev1alpha1.ConversionDataAnnotation))
+
+			restored := &computev1.Ec2Instance{}
+			Expect(spoke.ConvertTo(restored)).To(Succeed())
+			Expect(restored).To(Equal(hub))
+		})
+
+		It("Should not lose v1alpha1 fields on a round trip through v1", func() {
+			spoke := &computev1alpha1.Ec2Instance{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a"},
+				Spec:       computev1alpha1.Ec2InstanceSpec{InstanceType: "t3.micro", AMIID: "ami-123"},
+				Status:     computev1alpha1.Ec2InstanceStatus{InstanceID: "i-123", State: "pending"},
+			}
+
+			hub := &computev1.Ec2Instance{}
+			Expect(spoke.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.AMIId).To(Equal("ami-123"))
+			Expect(hub.Status.InstanceID).To(Equal("i-123"))
+
+			restored := &computev1alpha1.Ec2Instance{}
+			Expect(restored.ConvertFrom(hub)).To(Succeed())
+			Expect(restored.Spec).To(Equal(spoke.Spec))
+			Expect(restored.Status).To(Equal(spoke.Status))
+		})
+
+		It("Should apply changes made through v1alpha1 on top of the preserved v1 fields", func() {
+			hub := &computev1.Ec2Instance{Spec: computev1.Ec2InstanceSpec{InstanceType: "t3.micro", AMIId: "ami-123", Region: "eu-west-1"}}
+			spoke := &computev1alpha1.Ec2Instance{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+			spoke.Spec.AMIID = "ami-456"
+			restored := &computev1.Ec2Instance{}
+			Expect(spoke.ConvertTo(restored)).To(Succeed())
+			Expect(restored.Spec.AMIId).To(Equal("ami-456"))
+			Expect(restored.Spec.Region).To(Equal("eu-west-1"))
+			Expect(restored.Annotations).NotTo(HaveKey(computev1alpha1.ConversionDataAnnotation))
+		})
+	})
+})
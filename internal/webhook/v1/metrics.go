@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// webhookDuration is the time the validating webhook takes to answer, AWS calls included.
+var webhookDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "ec2instance_webhook_duration_seconds",
+	Help:    "Time taken to validate an Ec2Instance, including the AWS calls made for it.",
+	Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+}, []string{"operation"})
+
+// webhookCertificateExpiry is when the serving certificate of the webhook expires, so a failed
+// cert-manager renewal can be alerted on before admission breaks.
+var webhookCertificateExpiry = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "ec2instance_webhook_certificate_expiry_timestamp_seconds",
+	Help: "Unix time at which the serving certificate of the Ec2Instance webhook expires.",
+})
+
+func init() {
+	// The controller-runtime registry is served on the manager's metrics endpoint.
+	metrics.Registry.MustRegister(webhookDuration, webhookCertificateExpiry)
+}
+
+// RecordCertificateExpiry publishes the expiry of cert. It is registered as the callback of the
+// webhook certificate watcher, which calls it for the initial certificate and on every rotation.
+func RecordCertificateExpiry(cert tls.Certificate) {
+	leaf := cert.Leaf
+	if leaf == nil && len(cert.Certificate) > 0 {
+		parsed, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return
+		}
+		leaf = parsed
+	}
+	if leaf != nil {
+		webhookCertificateExpiry.Set(float64(leaf.NotAfter.Unix()))
+	}
+}
+
+// observeWebhookDuration records the time since start for a create or update validation.
+func observeWebhookDuration(operation string, start time.Time) {
+	webhookDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}